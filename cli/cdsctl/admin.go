@@ -23,6 +23,7 @@ func adminCommands() []*cobra.Command {
 		adminPlugins(),
 		adminBroadcasts(),
 		adminErrors(),
+		adminFeatures(),
 		adminCurl(),
 	}
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminFeaturesCmd = cli.Command{
+	Name:  "features",
+	Short: "Manage CDS feature flipping",
+}
+
+func adminFeatures() *cobra.Command {
+	return cli.NewCommand(adminFeaturesCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminFeatureListCmd, adminFeatureListRun, nil),
+		cli.NewGetCommand(adminFeatureShowCmd, adminFeatureShowRun, nil),
+		cli.NewCommand(adminFeatureSetCmd, adminFeatureSetRun, nil),
+		cli.NewCommand(adminFeatureDeleteCmd, adminFeatureDeleteRun, nil),
+	})
+}
+
+var adminFeatureListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS features",
+}
+
+func adminFeatureListRun(v cli.Values) (cli.ListResult, error) {
+	fs, err := client.AdminFeatureList()
+	if err != nil {
+		return nil, err
+	}
+	for i := range fs {
+		fs[i].RulesSummary = fs[i].Rules.String()
+	}
+	return cli.AsListResult(fs), nil
+}
+
+var adminFeatureShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show a CDS feature",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func adminFeatureShowRun(v cli.Values) (interface{}, error) {
+	f, err := client.AdminFeatureGet(v.GetString("name"))
+	if err != nil {
+		return nil, err
+	}
+	f.RulesSummary = f.Rules.String()
+	return f, nil
+}
+
+var adminFeatureSetCmd = cli.Command{
+	Name:  "set",
+	Short: "Create or update a CDS feature",
+	Long: `Create or update a CDS feature, given rules replace existing ones.
+
+A disabled feature is off for everyone, an enabled feature without rules is on for everyone.
+With rules the feature is on for given projects, for projects of given groups and for a percentage of all projects.`,
+	Example: `enable a feature for everyone:
+
+	cdsctl admin features set cds:tracing
+
+enable a feature for some projects and 10% of others:
+
+	cdsctl admin features set cds:tracing --project MYPROJ --group my-group --percentage 10
+
+disable a feature:
+
+	cdsctl admin features set cds:tracing --disable
+	`,
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "disable",
+			Type:  cli.FlagBool,
+			Usage: "Disable the feature for everyone",
+		},
+		{
+			Name:  "project",
+			Type:  cli.FlagSlice,
+			Usage: "Enable the feature for given project keys",
+		},
+		{
+			Name:  "group",
+			Type:  cli.FlagSlice,
+			Usage: "Enable the feature for projects of given groups",
+		},
+		{
+			Name:  "percentage",
+			Usage: "Enable the feature for a percentage of projects (rollout)",
+			IsValid: func(s string) bool {
+				if s == "" {
+					return true
+				}
+				i, err := strconv.Atoi(s)
+				return err == nil && i >= 0 && i <= 100
+			},
+		},
+	},
+}
+
+func adminFeatureSetRun(v cli.Values) error {
+	f := sdk.Feature{
+		Name:    v.GetString("name"),
+		Enabled: !v.GetBool("disable"),
+		Rules: sdk.FeatureRules{
+			ProjectKeys: v.GetStringSlice("project"),
+			Groups:      v.GetStringSlice("group"),
+		},
+	}
+	if p := v.GetString("percentage"); p != "" {
+		f.Rules.Percentage, _ = strconv.Atoi(p)
+	}
+	if err := client.AdminFeatureSet(f); err != nil {
+		return err
+	}
+	fmt.Printf("Feature %s updated\n", f.Name)
+	return nil
+}
+
+var adminFeatureDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS feature, it will be enabled for everyone",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Aliases: []string{"remove", "rm"},
+}
+
+func adminFeatureDeleteRun(v cli.Values) error {
+	return client.AdminFeatureDelete(v.GetString("name"))
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var applicationDeploymentCmd = cli.Command{
+	Name:  "deployment",
+	Short: "Manage CDS application deployments",
+}
+
+func applicationDeployment() *cobra.Command {
+	return cli.NewCommand(applicationDeploymentCmd, nil, []*cobra.Command{
+		cli.NewListCommand(applicationDeploymentHistoryCmd, applicationDeploymentHistoryRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(applicationDeploymentRollbackCmd, applicationDeploymentRollbackRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var applicationDeploymentHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "List the last deployments of a CDS application",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "environment",
+			Usage: "Only list the deployments on this environment",
+		},
+	},
+}
+
+func applicationDeploymentHistoryRun(v cli.Values) (cli.ListResult, error) {
+	history, err := client.ApplicationDeploymentHistory(v.GetString(_ProjectKey), v.GetString(_ApplicationName), v.GetString("environment"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(history), nil
+}
+
+var applicationDeploymentRollbackCmd = cli.Command{
+	Name:  "rollback",
+	Short: "Rollback a CDS application to a previous version",
+	Long: `Relaunch the workflow node run of the current deployment of the application, its DeployApplication step restores the previous version
+with the integration plugin. Use --version to restore another version of the deployment history.
+
+	cdsctl application deployment rollback MY-PROJECT my-app --environment production
+	cdsctl application deployment rollback MY-PROJECT my-app --environment production --version 1.2.0
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "environment",
+			Usage: "Environment to rollback, required if the application is deployed on several environments",
+		},
+		{
+			Name:  "version",
+			Usage: "Version to restore, the previous version by default",
+		},
+	},
+}
+
+func applicationDeploymentRollbackRun(v cli.Values) error {
+	run, err := client.ApplicationDeploymentRollback(v.GetString(_ProjectKey), v.GetString(_ApplicationName), sdk.ApplicationDeploymentRollback{
+		Environment: v.GetString("environment"),
+		Version:     v.GetString("version"),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rollback started with workflow %s run #%d\n", run.Workflow.Name, run.Number)
+	return nil
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -84,6 +87,25 @@ var authConsumerNewCmd = cli.Command{
 			Type:  cli.FlagSlice,
 			Usage: "Define the list of scopes for the consumer",
 		},
+		{
+			Name:  "project",
+			Type:  cli.FlagSlice,
+			Usage: "Restrict the consumer to given projects",
+		},
+		{
+			Name:  "workflow",
+			Type:  cli.FlagSlice,
+			Usage: "Restrict the consumer to given workflows, as PROJECT_KEY/WORKFLOW_NAME or as WORKFLOW_NAME of the single given project",
+		},
+		{
+			Name:  "read-only",
+			Type:  cli.FlagBool,
+			Usage: "Restrict the consumer to read requests",
+		},
+		{
+			Name:  "expire-in",
+			Usage: "Expiration delay of the consumer, in days (30d) or as a duration (12h)",
+		},
 	},
 }
 
@@ -151,11 +173,28 @@ func authConsumerNewRun(v cli.Values) error {
 		}
 	}
 
+	restrictions, err := authConsumerRestrictionsFromFlags(v)
+	if err != nil {
+		return err
+	}
+
+	var expireAt *time.Time
+	if expireIn := v.GetString("expire-in"); expireIn != "" {
+		d, err := parseDurationInDays(expireIn)
+		if err != nil {
+			return err
+		}
+		t := time.Now().Add(d)
+		expireAt = &t
+	}
+
 	res, err := client.AuthConsumerCreateForUser(username, sdk.AuthConsumer{
-		Name:        name,
-		Description: description,
-		GroupIDs:    groupIDs,
-		Scopes:      scopes,
+		Name:         name,
+		Description:  description,
+		GroupIDs:     groupIDs,
+		Scopes:       scopes,
+		Restrictions: restrictions,
+		ExpireAt:     expireAt,
 	})
 	if err != nil {
 		return err
@@ -167,6 +206,49 @@ func authConsumerNewRun(v cli.Values) error {
 	return nil
 }
 
+func authConsumerRestrictionsFromFlags(v cli.Values) (sdk.AuthConsumerRestrictions, error) {
+	var r sdk.AuthConsumerRestrictions
+
+	projectKeys := v.GetStringSlice("project")
+	workflows := v.GetStringSlice("workflow")
+	for _, w := range workflows {
+		if s := strings.SplitN(w, "/", 2); len(s) == 2 {
+			r.Workflows = append(r.Workflows, sdk.AuthConsumerRestrictionWorkflow{ProjectKey: s[0], WorkflowName: s[1]})
+			continue
+		}
+		if len(projectKeys) != 1 {
+			return r, errors.Errorf("invalid given workflow '%s', use PROJECT_KEY/WORKFLOW_NAME or give a single project", w)
+		}
+		r.Workflows = append(r.Workflows, sdk.AuthConsumerRestrictionWorkflow{ProjectKey: projectKeys[0], WorkflowName: w})
+	}
+	// Projects given with workflows are only used as the project of the workflows
+	if len(workflows) == 0 {
+		r.ProjectKeys = projectKeys
+	}
+
+	if v.GetBool("read-only") {
+		r.Methods = sdk.AuthConsumerReadOnlyMethods
+	}
+
+	return r, r.IsValid()
+}
+
+// parseDurationInDays parses a duration that can be expressed in days like 30d.
+func parseDurationInDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days <= 0 {
+			return 0, errors.Errorf("invalid given duration: '%s'", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, errors.Errorf("invalid given duration: '%s'", s)
+	}
+	return d, nil
+}
+
 var authConsumerDeleteCmd = cli.Command{
 	Name:  "delete",
 	Short: "Delete an auth consumer",
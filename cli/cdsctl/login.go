@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/ovh/cds/cli/cdsctl/internal"
 
@@ -30,7 +31,7 @@ var loginCmd = cli.Command{
 		},
 		{
 			Name:      "driver",
-			Usage:     "An enabled auth driver to login with. This should be local, GitHub, GitLab, Ldap, builtin or corporate-sso",
+			Usage:     "An enabled auth driver to login with. This should be local, GitHub, GitLab, Ldap, oidc, builtin or corporate-sso",
 			ShortHand: "d",
 		},
 		{
@@ -56,6 +57,11 @@ var loginCmd = cli.Command{
 			Name:  "token",
 			Usage: "A CDS token that can be used to login with a builtin auth driver.",
 		},
+		{
+			Name:  "device-code",
+			Usage: "Login with a code to enter on another device instead of opening a browser, if the auth driver supports it. This is the default in no interactive mode",
+			Type:  cli.FlagBool,
+		},
 	},
 }
 
@@ -113,6 +119,13 @@ func loginRun(v cli.Values) error {
 	case sdk.ConsumerBuiltin:
 		req, err = loginRunBuiltin(v)
 	default:
+		if manifest, _ := drivers.Drivers.FindByConsumerType(driverType); manifest.DeviceCode && (noInteractive || v.GetBool("device-code")) {
+			res, err := loginRunDeviceCode(client, driverType, drivers.IsFirstConnection, v.GetString("init-token"))
+			if err != nil {
+				return err
+			}
+			return doAfterLogin(client, v, apiURL, driverType, res)
+		}
 		if noInteractive {
 			return fmt.Errorf("Cannot signin with %s driver in no interactive mode", driverType)
 		}
@@ -228,6 +241,45 @@ func loginRunExternal(v cli.Values, consumerType sdk.AuthConsumerType, apiURL st
 	return req, nil
 }
 
+// loginRunDeviceCode asks the user to validate a code on another device then polls the API until the signin is done.
+func loginRunDeviceCode(client cdsclient.Interface, consumerType sdk.AuthConsumerType, isFirstConnection bool, initToken string) (sdk.AuthConsumerSigninResponse, error) {
+	var res sdk.AuthConsumerSigninResponse
+
+	code, err := client.AuthConsumerDeviceCode(consumerType)
+	if err != nil {
+		return res, fmt.Errorf("cannot start device code signin: %v", err)
+	}
+
+	fmt.Fprintln(os.Stderr, "cdsctl: To login, visit the following url and enter the code", cli.Green("%s", code.UserCode))
+	fmt.Fprintln(os.Stderr, " >\t"+cli.Green("%s", code.VerificationURI))
+	if code.VerificationURIComplete != "" {
+		fmt.Fprintln(os.Stderr, " >\tor visit "+cli.Green("%s", code.VerificationURIComplete))
+	}
+
+	req := sdk.AuthConsumerSigninRequest{"device_code": code.DeviceCode}
+	if isFirstConnection && initToken != "" {
+		req["init_token"] = initToken
+	}
+
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	expireAt := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for code.ExpiresIn == 0 || time.Now().Before(expireAt) {
+		time.Sleep(interval)
+		res, err = client.AuthConsumerSignin(consumerType, req)
+		if err == nil {
+			return res, nil
+		}
+		if !sdk.ErrorIs(err, sdk.ErrSigninPending) {
+			return res, fmt.Errorf("cannot signin: %v", err)
+		}
+	}
+
+	return res, fmt.Errorf("cannot signin: the code has expired")
+}
+
 func doAfterLogin(client cdsclient.Interface, v cli.Values, apiURL string, driverType sdk.AuthConsumerType, res sdk.AuthConsumerSigninResponse) error {
 	noInteractive := v.GetBool("no-interactive")
 	insecureSkipVerifyTLS := v.GetBool("insecure")
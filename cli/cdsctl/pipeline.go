@@ -24,6 +24,7 @@ func pipeline() *cobra.Command {
 		cli.NewDeleteCommand(pipelineDeleteCmd, pipelineDeleteRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(pipelineExportCmd, pipelineExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(pipelineImportCmd, pipelineImportRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(pipelineRunLocalCmd, pipelineRunLocalRun, nil),
 	})
 }
 
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/engine/worker/pkg/localrun"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+var runLocalFlags = []cli.Flag{
+	{
+		Name:  "job",
+		Usage: "Run only the given jobs",
+		Type:  cli.FlagSlice,
+	},
+	{
+		Name:      "parameter",
+		ShortHand: "p",
+		Usage:     "Run the pipeline with parameter, ie. git.branch=master",
+		IsValid: func(s string) bool {
+			if s == "" {
+				return true
+			}
+			for _, p := range strings.Split(s, "||") {
+				if strings.Count(p, "=") < 1 {
+					return false
+				}
+			}
+			return true
+		},
+		Type: cli.FlagSlice,
+	},
+	{
+		Name:    "workspace",
+		Usage:   "Directory where the working directories of the jobs are created",
+		Default: ".cds-local/workspace",
+	},
+	{
+		Name:    "artifacts",
+		Usage:   "Directory where the artifacts are uploaded",
+		Default: ".cds-local/artifacts",
+	},
+	{
+		Name:  "docker",
+		Usage: "Run the script steps in a docker container, the image of the job worker model is used if no image is given",
+		Type:  cli.FlagBool,
+	},
+	{
+		Name:  "image",
+		Usage: "Docker image used to run the script steps",
+	},
+}
+
+var pipelineRunLocalCmd = cli.Command{
+	Name:  "run-local",
+	Short: "Run a pipeline on your machine",
+	Long: `
+Run the jobs of an exported pipeline on your machine, with the builtin actions of the worker.
+Script steps can be run in a docker container with the --docker flag.
+The artifacts are stored in a local directory.
+
+	cdsctl pipeline run-local build.pip.yml -p git.branch=master --job Compile
+
+Only the builtin actions ` + strings.Join(localrun.SupportedActions(), ", ") + ` are supported.
+`,
+	Args: []cli.Arg{
+		{Name: "filename"},
+	},
+	Flags: runLocalFlags,
+}
+
+func pipelineRunLocalRun(v cli.Values) error {
+	pip, err := readPipelineFile(v.GetString("filename"))
+	if err != nil {
+		return err
+	}
+	return runLocal(v, *pip, nil)
+}
+
+func readPipelineFile(filename string) (*sdk.Pipeline, error) {
+	btes, format, err := exportentities.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %s: %v", filename, err)
+	}
+	f, err := exportentities.GetFormatStr(format)
+	if err != nil {
+		return nil, err
+	}
+	p, err := exportentities.ParsePipeline(f, btes)
+	if err != nil {
+		return nil, err
+	}
+	return p.Pipeline()
+}
+
+// runLocal runs the pipeline with the flags of the command, given parameters are overridden by the parameter flag
+func runLocal(v cli.Values, pip sdk.Pipeline, params []sdk.Parameter) error {
+	for _, s := range v.GetStringSlice("parameter") {
+		if s == "" {
+			continue
+		}
+		t := strings.SplitN(s, "=", 2)
+		sdk.ParameterAddOrSetValue(&params, t[0], sdk.StringParameter, t[1])
+	}
+	// The default values of the pipeline parameters are used if not given
+	for _, p := range pip.Parameter {
+		if sdk.ParameterFind(params, "cds.pip."+p.Name) == nil {
+			sdk.AddParameter(&params, "cds.pip."+p.Name, p.Type, p.Value)
+		}
+	}
+
+	opts := localrun.Options{
+		Workspace:   v.GetString("workspace"),
+		ArtifactDir: v.GetString("artifacts"),
+		Parameters:  params,
+		Jobs:        v.GetStringSlice("job"),
+	}
+	if v.GetBool("docker") || v.GetString("image") != "" {
+		image := v.GetString("image")
+		opts.DockerImage = func(j sdk.Job) (string, error) {
+			if image != "" {
+				return image, nil
+			}
+			return jobModelImage(j)
+		}
+	}
+
+	// The builtin actions log technical details, only the logs of the jobs are displayed
+	if !v.GetBool("verbose") {
+		logrus.SetOutput(ioutil.Discard)
+	}
+
+	results, err := localrun.Run(context.Background(), pip, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	var failed bool
+	for _, r := range results {
+		status := cli.Green(r.Status)
+		if r.Status == sdk.StatusFail {
+			failed = true
+			status = cli.Red(r.Status)
+		}
+		name := r.Job
+		if r.Stage != "" {
+			name = r.Stage + "/" + r.Job
+		}
+		fmt.Printf("%s: %s %s\n", name, status, r.Reason)
+	}
+	if failed {
+		return fmt.Errorf("pipeline %s failed", pip.Name)
+	}
+	return nil
+}
+
+// jobModelImage returns the docker image of the worker model required by the job
+func jobModelImage(j sdk.Job) (string, error) {
+	var model string
+	for _, r := range j.Action.Requirements {
+		if r.Type == sdk.ModelRequirement {
+			model = strings.Split(r.Value, " ")[0]
+		}
+	}
+	if model == "" {
+		return "", fmt.Errorf("job %s has no model requirement, use the --image flag", j.Action.Name)
+	}
+
+	groupName, modelName := sdk.SharedInfraGroupName, model
+	if t := strings.SplitN(model, "/", 2); len(t) == 2 {
+		groupName, modelName = t[0], t[1]
+	}
+	m, err := client.WorkerModel(groupName, modelName)
+	if err != nil {
+		return "", fmt.Errorf("unable to get worker model %s of job %s: %v", model, j.Action.Name, err)
+	}
+	if m.Type != sdk.Docker {
+		return "", fmt.Errorf("worker model %s of job %s is not a docker model, use the --image flag", model, j.Action.Name)
+	}
+	return m.ModelDocker.Image, nil
+}
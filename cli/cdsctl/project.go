@@ -25,6 +25,7 @@ func projectCommands() []*cobra.Command {
 		cli.NewCommand(projectCreateCmd, projectCreateRun, nil),
 		cli.NewDeleteCommand(projectDeleteCmd, projectDeleteRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(projectFavoriteCmd, projectFavoriteRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(projectLocksCmd, projectLocksRun, nil, withAllCommandModifiers()...),
 		projectKey(),
 		projectGroup(),
 		projectVariable(),
@@ -56,6 +57,22 @@ func projectListRun(v cli.Values) (cli.ListResult, error) {
 	return cli.AsListResult(projs), nil
 }
 
+var projectLocksCmd = cli.Command{
+	Name:  "locks",
+	Short: "List the pipelines holding or waiting for the concurrency groups of a project",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectLocksRun(v cli.Values) (cli.ListResult, error) {
+	locks, err := client.ProjectConcurrencyLocks(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(locks), nil
+}
+
 var projectShowCmd = cli.Command{
 	Name:  "show",
 	Short: "Show a CDS project",
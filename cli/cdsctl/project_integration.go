@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 )
 
@@ -22,6 +24,9 @@ func projectIntegration() *cobra.Command {
 		cli.NewDeleteCommand(projectIntegrationDeleteCmd, projectIntegrationDeleteFunc, nil, withAllCommandModifiers()...),
 		cli.NewCommand(projectIntegrationImportCmd, projectIntegrationImportFunc, nil, withAllCommandModifiers()...),
 		cli.NewCommand(projectIntegrationExportCmd, projectIntegrationExportFunc, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(projectIntegrationStatusCmd, projectIntegrationStatusFunc, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(projectIntegrationDeadLettersCmd, projectIntegrationDeadLettersFunc, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectIntegrationReplayCmd, projectIntegrationReplayFunc, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -104,3 +109,108 @@ func projectIntegrationExportFunc(v cli.Values) error {
 	fmt.Println(string(btes))
 	return nil
 }
+
+var projectIntegrationStatusCmd = cli.Command{
+	Name:    "status",
+	Short:   "Show the delivery status of an event integration",
+	Example: "cdsctl project integration status MY-PROJECT MY-INTEGRATION-NAME",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func projectIntegrationStatusFunc(v cli.Values) (interface{}, error) {
+	return client.ProjectIntegrationEventsStatus(v.GetString(_ProjectKey), v.GetString("name"))
+}
+
+var projectIntegrationDeadLettersCmd = cli.Command{
+	Name:    "dead-letters",
+	Short:   "List the last events that could not be delivered to an event integration",
+	Example: "cdsctl project integration dead-letters MY-PROJECT MY-INTEGRATION-NAME",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+type projectIntegrationDeadLetter struct {
+	Date      string `cli:"date"`
+	EventType string `cli:"type"`
+	Workflow  string `cli:"workflow"`
+	Error     string `cli:"error"`
+}
+
+func projectIntegrationDeadLettersFunc(v cli.Values) (cli.ListResult, error) {
+	status, err := client.ProjectIntegrationEventsStatus(v.GetString(_ProjectKey), v.GetString("name"))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]projectIntegrationDeadLetter, len(status.DeadLetters))
+	for i, d := range status.DeadLetters {
+		res[i] = projectIntegrationDeadLetter{
+			Date:      d.Date.Format(time.RFC3339),
+			EventType: d.Event.EventType,
+			Workflow:  d.Event.WorkflowName,
+			Error:     d.Error,
+		}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var projectIntegrationReplayCmd = cli.Command{
+	Name:  "replay",
+	Short: "Send again the persisted events of a project to an event integration",
+	Long: `Events persisted by the CDS elasticsearch service are sent again to the event integration, in order.
+Events are selected with the same filters than live events.`,
+	Example: `cdsctl project integration replay MY-PROJECT MY-INTEGRATION-NAME --from 2019-10-01T10:00:00Z
+cdsctl project integration replay MY-PROJECT MY-INTEGRATION-NAME --from 2h`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Flags: []cli.Flag{
+		{Name: "from", Usage: "Replay events since this date (RFC3339) or duration (ie. 2h)"},
+		{Name: "to", Usage: "Replay events until this date (RFC3339) or duration (ie. 1h), default is now"},
+	},
+}
+
+func parseReplayDate(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, fmt.Errorf("invalid date %q, expected RFC3339 date or duration", s)
+	}
+	return t, nil
+}
+
+func projectIntegrationReplayFunc(v cli.Values) error {
+	if v.GetString("from") == "" {
+		return fmt.Errorf("missing --from flag")
+	}
+	var req sdk.EventReplayRequest
+	var err error
+	req.From, err = parseReplayDate(v.GetString("from"))
+	if err != nil {
+		return err
+	}
+	if v.GetString("to") != "" {
+		req.To, err = parseReplayDate(v.GetString("to"))
+		if err != nil {
+			return err
+		}
+	}
+	if err := client.ProjectIntegrationEventsReplay(v.GetString(_ProjectKey), v.GetString("name"), req); err != nil {
+		return err
+	}
+	fmt.Printf("Replay of events since %s started\n", req.From.Format(time.RFC3339))
+	return nil
+}
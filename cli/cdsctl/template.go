@@ -30,6 +30,9 @@ func template() *cobra.Command {
 		cli.NewCommand(templateDeleteCmd, templateDeleteRun, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(templateInstancesCmd, templateInstancesRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(templateDetachCmd, templateDetachRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(templatePublishCmd, templatePublishRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(templateVersionsCmd, templateVersionsRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(templateUpgradesCmd, templateUpgradesRun, nil, withAllCommandModifiers()...),
 	})
 }
 
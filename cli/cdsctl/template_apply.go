@@ -36,6 +36,10 @@ func templateApplyCmd(name string) cli.Command {
 				Usage:     "Specify params for template like --params paramKey=paramValue",
 				Default:   "",
 			},
+			{
+				Name:  "version",
+				Usage: "Range of published versions of the template to apply like ~1.2, by default the range of the existing instance is kept",
+			},
 			{
 				Type:    cli.FlagBool,
 				Name:    "detach",
@@ -281,12 +285,19 @@ func templateApplyRun(v cli.Values) error {
 		return fmt.Errorf("Unable to create directory %s: %v", v.GetString("output-dir"), err)
 	}
 
+	// keep the version range of the previous template instance if not given
+	version := v.GetString("version")
+	if version == "" && wti != nil {
+		version = wti.Request.Version
+	}
+
 	// check request before submit
 	req := sdk.WorkflowTemplateRequest{
 		ProjectKey:   projectKey,
 		WorkflowName: workflowName,
 		Parameters:   params,
 		Detached:     v.GetBool("detach"),
+		Version:      version,
 	}
 	if err := wt.CheckParams(req); err != nil {
 		return err
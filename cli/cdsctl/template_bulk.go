@@ -49,6 +49,10 @@ var templateBulkCmd = cli.Command{
 			Name:  "instances-file",
 			Usage: "Specify path|url of a json|yaml file that contains instances with params",
 		},
+		{
+			Name:  "version",
+			Usage: "Range of published versions of the template to apply on all instances like ~1.2",
+		},
 		{
 			Type:  cli.FlagBool,
 			Name:  "track",
@@ -262,22 +266,29 @@ func templateInitOperationFromParams(mwtis map[string]sdk.WorkflowTemplateInstan
 	return moperations
 }
 
-func templateAskForInstances(wt *sdk.WorkflowTemplate, mwtis map[string]sdk.WorkflowTemplateInstance, minstances map[string]templateBulkInstancePath,
+func templateAskForInstances(mupgrades map[int64]sdk.WorkflowTemplateInstanceUpgrade, mwtis map[string]sdk.WorkflowTemplateInstance, minstances map[string]templateBulkInstancePath,
 	moperations map[string]sdk.WorkflowTemplateBulkOperation) error {
 	opts := make([]cli.CustomMultiSelectOption, len(mwtis))
 	values := make(map[string]sdk.WorkflowTemplateInstance, len(mwtis))
 	i := 0
 	for key, instance := range mwtis {
-		notUpToDate := instance.WorkflowTemplateVersion < wt.Version
+		upgrade := mupgrades[instance.ID]
+		notUpToDate := upgrade.Status == sdk.WorkflowTemplateInstanceUpgradable
 
 		var info string
-		if instance.Workflow == nil {
+		switch upgrade.Status {
+		case sdk.WorkflowTemplateInstanceNotImported:
 			info = cli.Yellow("not imported")
-		} else if notUpToDate {
-			info = cli.Red("not up to date")
-		} else {
+		case sdk.WorkflowTemplateInstanceUpgradable:
+			info = cli.Red(fmt.Sprintf("not up to date (%s -> %s)", upgrade.Current, upgrade.Target))
+		case sdk.WorkflowTemplateInstanceOutOfRange:
+			info = cli.Yellow(fmt.Sprintf("latest version %s out of range %s", upgrade.Latest, upgrade.Range))
+		default:
 			info = cli.Green("up to date")
 		}
+		if upgrade.AsCode {
+			info += " (as code)"
+		}
 
 		_, instanceGivenAsParam := moperations[instance.Key()]
 		// selected by default if given as param or if no instances given as param an not up to date
@@ -378,8 +389,17 @@ func templateBulkRun(v cli.Values) error {
 
 	// ask interactively for params if prompt not disabled
 	if !v.GetBool("no-interactive") {
+		upgrades, err := client.TemplateGetUpgrades(wt.Group.Name, wt.Slug)
+		if err != nil {
+			return err
+		}
+		mupgrades := make(map[int64]sdk.WorkflowTemplateInstanceUpgrade, len(upgrades))
+		for _, u := range upgrades {
+			mupgrades[u.InstanceID] = u
+		}
+
 		sort.Slice(wtis, func(i, j int) bool { return wtis[i].Key() < wtis[j].Key() })
-		if err := templateAskForInstances(wt, mwtis, minstances, moperations); err != nil {
+		if err := templateAskForInstances(mupgrades, mwtis, minstances, moperations); err != nil {
 			return err
 		}
 
@@ -481,10 +501,14 @@ func templateBulkRun(v cli.Values) error {
 		}
 	}
 
-	// send bulk request
+	// send bulk request, the given version range overrides the range of existing instances
+	version := v.GetString("version")
 	b := sdk.WorkflowTemplateBulk{Operations: make([]sdk.WorkflowTemplateBulkOperation, len(moperations))}
 	i := 0
 	for _, o := range moperations {
+		if version != "" {
+			o.Request.Version = version
+		}
 		b.Operations[i] = o
 		i++
 	}
@@ -520,7 +544,11 @@ func templateBulkRun(v cli.Values) error {
 				case sdk.OperationStatusError:
 					status = cli.Red("error")
 				}
-				out += fmt.Sprintf("%s/%s -> %s %s\n", o.Request.ProjectKey, o.Request.WorkflowName, status, o.Error)
+				out += fmt.Sprintf("%s/%s -> %s %s", o.Request.ProjectKey, o.Request.WorkflowName, status, o.Error)
+				if o.PullRequestURL != "" {
+					out += fmt.Sprintf("pull request: %s", o.PullRequestURL)
+				}
+				out += "\n"
 			}
 
 			currentDisplay.Printf(out)
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/cli"
+)
+
+var templatePublishCmd = cli.Command{
+	Name:    "publish",
+	Short:   "Publish a version of a CDS workflow template from a tag of its repository",
+	Example: "cdsctl template publish group-name/template-slug v1.2.0",
+	Args: []cli.Arg{
+		{Name: "template-path"},
+		{Name: "tag"},
+	},
+}
+
+func templatePublishRun(v cli.Values) error {
+	groupName, templateSlug, err := cli.ParsePath(v.GetString("template-path"))
+	if err != nil {
+		return err
+	}
+
+	wtv, err := client.TemplatePublish(groupName, templateSlug, v.GetString("tag"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Version %s of template %s/%s successfully published from tag %s\n", wtv.Version, groupName, templateSlug, wtv.Tag)
+
+	return nil
+}
+
+var templateVersionsCmd = cli.Command{
+	Name:    "versions",
+	Short:   "Get published versions of a CDS workflow template",
+	Example: "cdsctl template versions group-name/template-slug",
+	OptionalArgs: []cli.Arg{
+		{Name: "template-path"},
+	},
+}
+
+func templateVersionsRun(v cli.Values) (cli.ListResult, error) {
+	wt, err := getTemplateFromCLI(v)
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		wt, err = suggestTemplate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wtvs, err := client.TemplateGetVersions(wt.Group.Name, wt.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.AsListResult(wtvs), nil
+}
+
+var templateUpgradesCmd = cli.Command{
+	Name:    "upgrades",
+	Short:   "Get the upgrade report of the instances of a CDS workflow template",
+	Example: "cdsctl template upgrades group-name/template-slug",
+	OptionalArgs: []cli.Arg{
+		{Name: "template-path"},
+	},
+}
+
+func templateUpgradesRun(v cli.Values) (cli.ListResult, error) {
+	wt, err := getTemplateFromCLI(v)
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		wt, err = suggestTemplate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	upgrades, err := client.TemplateGetUpgrades(wt.Group.Name, wt.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.AsListResult(upgrades), nil
+}
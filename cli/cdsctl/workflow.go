@@ -24,7 +24,9 @@ func workflow() *cobra.Command {
 		cli.NewGetCommand(workflowShowCmd, workflowShowRun, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowStatusCmd, workflowStatusRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowRunManualCmd, workflowRunManualRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowRunLocalCmd, workflowRunLocalRun, nil),
 		cli.NewCommand(workflowStopCmd, workflowStopRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowPurgeCmd, workflowPurgeRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowExportCmd, workflowExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowImportCmd, workflowImportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowPullCmd, workflowPullRun, nil, withAllCommandModifiers()...),
@@ -32,6 +34,7 @@ func workflow() *cobra.Command {
 		cli.NewCommand(workflowFavoriteCmd, workflowFavoriteRun, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowTransformAsCodeCmd, workflowTransformAsCodeRun, nil, withAllCommandModifiers()...),
 		workflowArtifact(),
+		workflowHooks(),
 		workflowLog(),
 		workflowAdvanced(),
 	})
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowHooksCmd = cli.Command{
+	Name:  "hooks",
+	Short: "Manage Workflow Hooks",
+}
+
+func workflowHooks() *cobra.Command {
+	return cli.NewCommand(workflowHooksCmd, nil, []*cobra.Command{
+		cli.NewListCommand(workflowHooksListCmd, workflowHooksListRun, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(workflowHooksRotateSecretCmd, workflowHooksRotateSecretRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+type workflowHookDisplay struct {
+	UUID   string `cli:"uuid,key"`
+	Ref    string `cli:"ref"`
+	Model  string `cli:"model"`
+	URL    string `cli:"url"`
+	Signed bool   `cli:"signed"`
+}
+
+var workflowHooksListCmd = cli.Command{
+	Name:  "list",
+	Short: "List hooks of a Workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+}
+
+func workflowHooksListRun(v cli.Values) (cli.ListResult, error) {
+	wf, err := client.WorkflowGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName))
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]workflowHookDisplay, 0, len(wf.WorkflowData.Node.Hooks))
+	for _, h := range wf.WorkflowData.Node.Hooks {
+		hooks = append(hooks, workflowHookDisplay{
+			UUID:   h.UUID,
+			Ref:    h.Ref,
+			Model:  h.HookModelName,
+			URL:    h.Config["webHookURL"].Value,
+			Signed: h.Config[sdk.HookConfigWebHookSecret].Value != "",
+		})
+	}
+	return cli.AsListResult(hooks), nil
+}
+
+var workflowHooksRotateSecretCmd = cli.Command{
+	Name:  "rotate-secret",
+	Short: "Generate a new secret for a webhook",
+	Long: `Generate a new secret used to sign the calls of a webhook.
+
+For a repository webhook, the new secret is set on the repository. For a webhook, calls have to be signed with the new secret in the header X-Cds-Signature-256:
+
+	$ cdsctl workflow hooks rotate-secret MYPROJECT my-workflow 6a1d8e52-... --format json
+	`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "uuid"},
+	},
+}
+
+func workflowHooksRotateSecretRun(v cli.Values) (interface{}, error) {
+	h, err := client.WorkflowHookSecretRotate(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("uuid"))
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		UUID   string `cli:"uuid,key"`
+		URL    string `cli:"url"`
+		Secret string `cli:"secret"`
+	}{
+		UUID:   h.UUID,
+		URL:    h.Config["webHookURL"].Value,
+		Secret: h.Config[sdk.HookConfigWebHookSecret].Value,
+	}, nil
+}
@@ -146,6 +146,10 @@ func workflowLogProcess(wr *sdk.WorkflowRun) []workflowLogDetail {
 		for _, node := range noderuns {
 			for _, stage := range node.Stages {
 				for _, job := range stage.RunJobs {
+					jobName := job.Job.Job.Action.Name
+					if job.Matrix != nil {
+						jobName = fmt.Sprintf("%s.%d", jobName, job.Matrix.Index+1)
+					}
 
 					for _, step := range job.Job.StepStatus {
 						logs = append(logs,
@@ -153,7 +157,7 @@ func workflowLogProcess(wr *sdk.WorkflowRun) []workflowLogDetail {
 								workflowName: wr.Workflow.Name,
 								pipelineName: node.WorkflowNodeName,
 								stageName:    stage.Name,
-								jobName:      job.Job.Job.Action.Name,
+								jobName:      jobName,
 								jobID:        job.ID,
 								status:       job.Status,
 								stepOrder:    step.StepOrder,
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/ovh/cds/cli"
+)
+
+var workflowPurgeCmd = cli.Command{
+	Name:  "purge",
+	Short: "Purge workflow runs with the retention policy of the workflow",
+	Long: `Purge workflow runs with the retention policy of the workflow, runs that are not kept are marked to delete.
+Their artifacts and logs will be deleted with them.
+
+The retention policy is set in the workflow as code:
+
+	retention_policy:
+	  keep_runs_per_branch: 10
+	  keep_days: 30
+	  keep_tagged: true
+	  keep_deployed: true
+	  keep_last_success: true
+`,
+	Example: `cdsctl workflow purge MYPROJECT myworkflow --dry-run # Show which runs would be deleted
+cdsctl workflow purge MYPROJECT myworkflow`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "dry-run",
+			Type:  cli.FlagBool,
+			Usage: "Only show for each run if it will be kept or deleted",
+		},
+	},
+}
+
+func workflowPurgeRun(v cli.Values) (cli.ListResult, error) {
+	res, err := client.WorkflowPurge(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetBool("dry-run"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(res), nil
+}
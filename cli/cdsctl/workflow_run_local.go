@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+var workflowRunLocalCmd = cli.Command{
+	Name:  "run-local",
+	Short: "Run a pipeline of a workflow as code on your machine",
+	Long: `
+Run the pipeline of a workflow node on your machine, with the files of the workflow as code directory.
+The node parameters and payload are used as parameters of the run.
+
+	cdsctl workflow run-local build --dir .cds -p git.branch=master
+
+See cdsctl pipeline run-local --help for more details.
+`,
+	Args: []cli.Arg{
+		{Name: "node-name"},
+	},
+	Flags: append([]cli.Flag{
+		{
+			Name:    "dir",
+			Usage:   "Directory of the workflow as code files",
+			Default: ".cds",
+		},
+	}, runLocalFlags...),
+}
+
+func workflowRunLocalRun(v cli.Values) error {
+	dir := v.GetString("dir")
+	files, err := filepath.Glob(filepath.Join(dir, "*.y*ml"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	nodeName := v.GetString("node-name")
+	var node *exportentities.NodeEntry
+	var workflowName string
+	pipelineFiles := map[string]string{}
+	for _, f := range files {
+		base := filepath.Base(f)
+		switch {
+		case strings.Contains(base, ".pip."):
+			pip, err := readPipelineFile(f)
+			if err != nil {
+				return err
+			}
+			pipelineFiles[pip.Name] = f
+		case strings.Contains(base, ".app."), strings.Contains(base, ".env."):
+		default:
+			btes, format, err := exportentities.ReadFile(f)
+			if err != nil {
+				return fmt.Errorf("unable to read file %s: %v", f, err)
+			}
+			var w exportentities.Workflow
+			if err := exportentities.Unmarshal(btes, format, &w); err != nil {
+				return fmt.Errorf("unable to read workflow file %s: %v", f, err)
+			}
+			if e, has := w.Entries()[nodeName]; has {
+				node = &e
+				workflowName = w.Name
+			}
+		}
+	}
+	if node == nil {
+		return fmt.Errorf("node %s not found in the workflow files of %s", nodeName, dir)
+	}
+	if node.PipelineName == "" {
+		return fmt.Errorf("node %s is not a pipeline node", nodeName)
+	}
+	pipelineFile, has := pipelineFiles[node.PipelineName]
+	if !has {
+		return fmt.Errorf("pipeline %s of node %s not found in %s", node.PipelineName, nodeName, dir)
+	}
+	pip, err := readPipelineFile(pipelineFile)
+	if err != nil {
+		return err
+	}
+
+	var params []sdk.Parameter
+	sdk.AddParameter(&params, "cds.workflow", sdk.StringParameter, workflowName)
+	sdk.AddParameter(&params, "cds.node", sdk.StringParameter, nodeName)
+	if node.ApplicationName != "" {
+		sdk.AddParameter(&params, "cds.application", sdk.StringParameter, node.ApplicationName)
+	}
+	if node.EnvironmentName != "" {
+		sdk.AddParameter(&params, "cds.environment", sdk.StringParameter, node.EnvironmentName)
+	}
+	for k, v := range node.Payload {
+		sdk.AddParameter(&params, k, sdk.StringParameter, fmt.Sprintf("%v", v))
+	}
+	for k, v := range node.Parameters {
+		sdk.AddParameter(&params, "cds.pip."+k, sdk.StringParameter, v)
+	}
+	return runLocal(v, *pip, params)
+}
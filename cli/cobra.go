@@ -468,7 +468,11 @@ func listItem(i interface{}, filters map[string]string, quiet bool, fields []str
 		f := s.Field(i)
 		structField := t.Field(i)
 		if f.Kind() == reflect.Ptr {
-			f = f.Elem()
+			if f.IsNil() { // display nil pointers as empty values
+				f = reflect.ValueOf("")
+			} else {
+				f = f.Elem()
+			}
 		}
 		switch f.Kind() {
 		case reflect.Array, reflect.Slice, reflect.Map:
@@ -166,14 +166,52 @@ func (e *helloDeploymentPlugin) Deploy(ctx context.Context, q *integrationplugin
 		return fail("deployment failed")
 	}
 
+	// With a canary or blue-green strategy, the new release only receives a part of the traffic,
+	// CDS will call Promote for each step then Abort if the deployment status is not successful.
+	if q.GetStrategy() != "" {
+		fmt.Printf("Fake %s deployment on Hello integration: %d%% of traffic sent to the new release\n", q.GetStrategy(), q.GetWeight())
+	}
+
+	// The ID is given back by CDS to DeployStatus, Promote and Abort, the version
+	// is recorded in the deployment history of the application
 	return &integrationplugin.DeployResult{
-		Status: sdk.StatusSuccess,
+		Status:  sdk.StatusSuccess,
+		ID:      application + "-" + q.GetOptions()["cds.version"],
+		Version: q.GetOptions()["cds.version"],
 	}, nil
 }
 
 func (e *helloDeploymentPlugin) DeployStatus(ctx context.Context, q *integrationplugin.DeployStatusQuery) (*integrationplugin.DeployResult, error) {
+	// here, you can request your "deployment" system to check the health of the deployment q.GetID()
+	return &integrationplugin.DeployResult{
+		Status: sdk.StatusSuccess,
+		ID:     q.GetID(),
+	}, nil
+}
+
+func (e *helloDeploymentPlugin) Promote(ctx context.Context, q *integrationplugin.PromoteQuery) (*integrationplugin.DeployResult, error) {
+	fmt.Printf("Fake promotion of %s on Hello integration: %d%% of traffic sent to the new release\n", q.GetID(), q.GetWeight())
+	return &integrationplugin.DeployResult{
+		Status: sdk.StatusSuccess,
+		ID:     q.GetID(),
+	}, nil
+}
+
+func (e *helloDeploymentPlugin) Abort(ctx context.Context, q *integrationplugin.AbortQuery) (*integrationplugin.DeployResult, error) {
+	fmt.Printf("Fake abort of %s on Hello integration: all the traffic sent back to the previous release\n", q.GetID())
 	return &integrationplugin.DeployResult{
 		Status: sdk.StatusSuccess,
+		ID:     q.GetID(),
+	}, nil
+}
+
+func (e *helloDeploymentPlugin) Rollback(ctx context.Context, q *integrationplugin.RollbackQuery) (*integrationplugin.DeployResult, error) {
+	var application = q.GetOptions()["cds.application"]
+	fmt.Printf("Fake rollback of %s to version %s on Hello integration\n", application, q.GetVersion())
+	return &integrationplugin.DeployResult{
+		Status:  sdk.StatusSuccess,
+		ID:      application + "-" + q.GetVersion(),
+		Version: q.GetVersion(),
 	}, nil
 }
 
@@ -24,6 +24,8 @@ func insertActionChild(db gorp.SqlExecutor, child sdk.Action, actionID int64, ex
 		Optional:       child.Optional,
 		AlwaysExecuted: child.AlwaysExecuted,
 		Enabled:        child.Enabled,
+		Timeout:        child.Timeout,
+		Retry:          child.Retry,
 	}
 	if err := insertEdge(db, &ae); err != nil {
 		return err
@@ -153,6 +153,12 @@ func loadChildren(ctx context.Context, db gorp.SqlExecutor, as ...*sdk.Action) e
 			child.Optional = edges[i].Optional
 			child.AlwaysExecuted = edges[i].AlwaysExecuted
 			child.Enabled = edges[i].Enabled
+			if edges[i].Timeout != "" {
+				child.Timeout = edges[i].Timeout
+			}
+			if edges[i].Retry != nil {
+				child.Retry = edges[i].Retry
+			}
 
 			// replace action parameter with value configured by user when he created the child action
 			params := make([]sdk.Parameter, len(child.Parameters))
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"go.opencensus.io/stats"
@@ -27,6 +28,7 @@ import (
 	"github.com/ovh/cds/engine/api/authentication/gitlab"
 	"github.com/ovh/cds/engine/api/authentication/ldap"
 	"github.com/ovh/cds/engine/api/authentication/local"
+	"github.com/ovh/cds/engine/api/authentication/oidc"
 	"github.com/ovh/cds/engine/api/bootstrap"
 	"github.com/ovh/cds/engine/api/broadcast"
 	"github.com/ovh/cds/engine/api/cache"
@@ -41,6 +43,7 @@ import (
 	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/purge"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/secret"
@@ -70,11 +73,23 @@ type Configuration struct {
 		Port int    `toml:"port" default:"8081" json:"port"`
 	} `toml:"http" json:"http"`
 	Secrets struct {
-		Key string `toml:"key" json:"-"`
+		Key       string               `toml:"key" json:"-"`
+		Keyring   []database.KeyConfig `toml:"keyring" comment:"Versioned cipher keys (32 bytes each), the newest one (highest timestamp) is used to encrypt secrets.\n Values encrypted with the legacy key or an older key are still decrypted, run 'engine database rotate-secrets' to re-encrypt them." json:"-" mapstructure:"keyring"`
+		Providers struct {
+			File struct {
+				BaseDirectory string `toml:"baseDirectory" comment:"Directory containing secret files, variables reference them with file:<project key>/<path>[#key]" json:"baseDirectory"`
+			} `toml:"file" json:"file"`
+			Vault struct {
+				Addr       string `toml:"addr" comment:"Vault address, variables reference secrets with vault:<pathPrefix>/<project key>/<path>[#key] (example: vault:secret/data/cds/MYPROJ/my-app#password)" json:"addr"`
+				Token      string `toml:"token" json:"-"`
+				PathPrefix string `toml:"pathPrefix" default:"secret/data/cds" comment:"Root of the project namespaces, a project can only read the secrets stored under <pathPrefix>/<project key>/" json:"pathPrefix"`
+			} `toml:"vault" json:"vault"`
+		} `toml:"providers" comment:"###########################\n External secret providers used to resolve variables of type external_secret \n##########################" json:"providers"`
 	} `toml:"secrets" json:"secrets"`
 	Database database.DBConfiguration `toml:"database" comment:"################################\n Postgresql Database settings \n###############################" json:"database"`
 	Cache    struct {
-		TTL   int `toml:"ttl" default:"60" json:"ttl"`
+		TTL   int    `toml:"ttl" default:"60" json:"ttl"`
+		Mode  string `toml:"mode" default:"redis" comment:"redis or local. With local, the cache is kept in memory and redis settings are ignored, use it only for a single instance installation" json:"mode"`
 		Redis struct {
 			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379" json:"host"`
 			Password string `toml:"password" json:"-"`
@@ -134,6 +149,17 @@ type Configuration struct {
 			ApplicationID  string `toml:"applicationID" json:"-" comment:"#######\n Gitlab OAuth Application ID"`
 			Secret         string `toml:"secret" json:"-"  comment:"Gitlab OAuth Application Secret"`
 		} `toml:"gitlab" json:"gitlab"`
+		OIDC struct {
+			Enabled        bool              `toml:"enabled" default:"false" json:"enabled"`
+			SignupDisabled bool              `toml:"signupDisabled" default:"false" json:"signupDisabled"`
+			URL            string            `toml:"url" json:"url" comment:"#######\n OpenID Connect issuer URL, the provider configuration is discovered from <url>/.well-known/openid-configuration"`
+			ClientID       string            `toml:"clientId" json:"-" comment:"#######\n OpenID Connect Client ID"`
+			ClientSecret   string            `toml:"clientSecret" json:"-" comment:"OpenID Connect Client Secret"`
+			Scopes         []string          `toml:"scopes" json:"scopes" comment:"Scopes of the authorization request, default to openid, profile and email" commented:"true"`
+			UsernameClaim  string            `toml:"usernameClaim" default:"preferred_username" json:"usernameClaim" comment:"Claim of the id token used as CDS username"`
+			GroupsClaim    string            `toml:"groupsClaim" default:"groups" json:"groupsClaim" comment:"Claim of the id token that contains the groups of the user"`
+			GroupsMapping  map[string]string `toml:"groupsMapping" json:"groupsMapping" comment:"Map a value of the groups claim to a CDS group. Memberships of the mapped CDS groups are synchronized at each signin\n Example: groupsMapping = { \"/cds/developers\" = \"developers\" }" commented:"true"`
+		} `toml:"oidc" json:"oidc"`
 	} `toml:"auth" comment:"##############################\n CDS Authentication Settings#\n#############################" json:"auth"`
 	SMTP struct {
 		Disable  bool   `toml:"disable" default:"true" json:"disable" comment:"Set to false to enable the internal SMTP client"`
@@ -180,7 +206,7 @@ type Configuration struct {
 			ClientID     string `toml:"clientid" json:"-"`
 			ClientSecret string `toml:"clientsecret" json:"-"`
 			Token        string `toml:"token" comment:"Token shared between Izanami and CDS to be able to send webhooks from izanami" json:"-"`
-		} `toml:"izanami" comment:"Feature flipping provider: https://maif.github.io/izanami" json:"izanami"`
+		} `toml:"izanami" comment:"Optional feature flipping provider: https://maif.github.io/izanami, if not set features are stored in CDS database and managed with cdsctl admin features" json:"izanami"`
 	} `toml:"features" comment:"###########################\n CDS Features flipping Settings \n##########################" json:"features"`
 	Services    []ServiceConfiguration `toml:"services" comment:"###########################\n CDS Services Settings \n##########################" json:"services"`
 	DefaultOS   string                 `toml:"defaultOS" default:"linux" comment:"if no model and os/arch is specified in your job's requirements then spawn worker on this operating system (example: freebsd, linux, windows)" json:"defaultOS"`
@@ -191,8 +217,9 @@ type Configuration struct {
 		URL         string `toml:"url" comment:"Example: http://localhost:9000" json:"url"`
 	} `toml:"graylog" json:"graylog" comment:"###########################\n Graylog Search. \n When CDS API generates errors, you can fetch them with cdsctl. \n Examples: \n $ cdsctl admin errors get <error-id> \n $ cdsctl admin errors get 55f6e977-d39b-11e8-8513-0242ac110007 \n##########################"`
 	Log struct {
-		StepMaxSize    int64 `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
-		ServiceMaxSize int64 `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		StepMaxSize    int64  `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
+		ServiceMaxSize int64  `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		Storage        string `toml:"storage" default:"database" comment:"Step logs storage: database or objectstore. With objectstore, step logs are stored as compressed chunks in the artifact storage and are not limited by stepMaxSize" json:"storage"`
 	} `toml:"log" json:"log" comment:"###########################\n Log settings.\n##########################"`
 }
 
@@ -276,6 +303,15 @@ type API struct {
 	AuthenticationDrivers map[sdk.AuthConsumerType]sdk.AuthDriver
 }
 
+// SecretKeyring returns the keyring used by the secret package.
+func (c Configuration) SecretKeyring() []secret.Key {
+	keys := make([]secret.Key, 0, len(c.Secrets.Keyring))
+	for _, k := range c.Secrets.Keyring {
+		keys = append(keys, secret.Key{Timestamp: k.Timestamp, Value: []byte(k.Key)})
+	}
+	return keys
+}
+
 // ApplyConfiguration apply an object of type api.Configuration after checking it
 func (a *API) ApplyConfiguration(config interface{}) error {
 	if err := a.CheckConfiguration(config); err != nil {
@@ -347,9 +383,14 @@ func (a *API) CheckConfiguration(config interface{}) error {
 		}
 	}
 
-	if len(aConfig.Secrets.Key) != 32 {
+	if len(aConfig.Secrets.Key) != 32 && !(aConfig.Secrets.Key == "" && len(aConfig.Secrets.Keyring) > 0) {
 		return fmt.Errorf("Invalid secret key. It should be 32 bits (%d)", len(aConfig.Secrets.Key))
 	}
+	for _, k := range aConfig.Secrets.Keyring {
+		if len(k.Key) != 32 {
+			return fmt.Errorf("Invalid secret keyring key %d. It should be 32 bits (%d)", k.Timestamp, len(k.Key))
+		}
+	}
 
 	if aConfig.DefaultArch == "" {
 		log.Warning(context.Background(), `You should add a default architecture in your configuration (example: defaultArch: "amd64"). It means if there is no model and os/arch requirement on your job then spawn on a worker based on this architecture`)
@@ -414,7 +455,19 @@ func (a *API) Serve(ctx context.Context) error {
 	}
 
 	// Initialize secret driver
-	secret.Init(a.Config.Secrets.Key)
+	if err := secret.Init(a.Config.Secrets.Key, a.Config.SecretKeyring()...); err != nil {
+		return sdk.WrapError(err, "unable to initialize the secret layer")
+	}
+	if a.Config.Secrets.Providers.File.BaseDirectory != "" {
+		secret.RegisterProvider("file", secret.FileProvider{BaseDirectory: a.Config.Secrets.Providers.File.BaseDirectory}, "")
+	}
+	if a.Config.Secrets.Providers.Vault.Addr != "" {
+		vaultProvider, err := secret.NewVaultProvider(a.Config.Secrets.Providers.Vault.Addr, a.Config.Secrets.Providers.Vault.Token)
+		if err != nil {
+			return sdk.WrapError(err, "unable to initialize the vault secret provider")
+		}
+		secret.RegisterProvider("vault", vaultProvider, a.Config.Secrets.Providers.Vault.PathPrefix)
+	}
 
 	// Initialize the jwt layer
 	if err := authentication.Init(a.ServiceName, []byte(a.Config.Auth.RSAPrivateKey)); err != nil {
@@ -432,11 +485,13 @@ func (a *API) Serve(ctx context.Context) error {
 		a.Config.SMTP.Disable)
 
 	// Initialize feature packages
-	log.Info(ctx, "Initializing feature flipping with izanami %s", a.Config.Features.Izanami.APIURL)
 	if a.Config.Features.Izanami.APIURL != "" {
-		if err := feature.Init(a.Config.Features.Izanami.APIURL, a.Config.Features.Izanami.ClientID, a.Config.Features.Izanami.ClientSecret); err != nil {
-			return errors.Wrap(err, "feature flipping not enabled with izanami: %v")
-		}
+		log.Info(ctx, "Initializing feature flipping with izanami %s", a.Config.Features.Izanami.APIURL)
+	} else {
+		log.Info(ctx, "Initializing feature flipping with features stored in database")
+	}
+	if err := feature.Init(a.mustDB, a.Config.Features.Izanami.APIURL, a.Config.Features.Izanami.ClientID, a.Config.Features.Izanami.ClientSecret); err != nil {
+		return errors.Wrap(err, "feature flipping not enabled with izanami")
 	}
 
 	//Initialize artifacts storage
@@ -495,6 +550,14 @@ func (a *API) Serve(ctx context.Context) error {
 	if errStorage != nil {
 		return fmt.Errorf("cannot initialize storage: %v", errStorage)
 	}
+	switch a.Config.Log.Storage {
+	case "", "database":
+	case "objectstore":
+		log.Info(ctx, "Step logs will be stored in the objectstore")
+		workflow.SetLogStorage(a.SharedStorage)
+	default:
+		return fmt.Errorf("invalid log storage %q", a.Config.Log.Storage)
+	}
 
 	log.Info(ctx, "Initializing database connection...")
 	//Intialize database
@@ -549,10 +612,16 @@ func (a *API) Serve(ctx context.Context) error {
 
 	log.Info(ctx, "API Public Key: \n%s", string(pubKey))
 
-	log.Info(ctx, "Initializing redis cache on %s...", a.Config.Cache.Redis.Host)
+	if a.Config.Cache.Mode == cache.ModeLocal {
+		log.Info(ctx, "Initializing local cache...")
+	} else {
+		log.Info(ctx, "Initializing redis cache on %s...", a.Config.Cache.Redis.Host)
+	}
 	// Init the cache
 	var errCache error
 	a.Cache, errCache = cache.New(
+		ctx,
+		a.Config.Cache.Mode,
 		a.Config.Cache.Redis.Host,
 		a.Config.Cache.Redis.Password,
 		a.Config.Cache.TTL)
@@ -631,6 +700,26 @@ func (a *API) Serve(ctx context.Context) error {
 		)
 	}
 
+	if a.Config.Auth.OIDC.Enabled {
+		a.AuthenticationDrivers[sdk.ConsumerOIDC], err = oidc.NewDriver(
+			ctx,
+			a.Config.Auth.OIDC.SignupDisabled,
+			a.Config.URL.UI,
+			oidc.Config{
+				URL:           a.Config.Auth.OIDC.URL,
+				ClientID:      a.Config.Auth.OIDC.ClientID,
+				ClientSecret:  a.Config.Auth.OIDC.ClientSecret,
+				Scopes:        a.Config.Auth.OIDC.Scopes,
+				UsernameClaim: a.Config.Auth.OIDC.UsernameClaim,
+				GroupsClaim:   a.Config.Auth.OIDC.GroupsClaim,
+				GroupsMapping: a.Config.Auth.OIDC.GroupsMapping,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	if a.Config.Auth.CorporateSSO.Enabled {
 		driverConfig := corpsso.Config{
 			MailDomain: a.Config.Auth.CorporateSSO.MailDomain,
@@ -707,6 +796,9 @@ func (a *API) Serve(ctx context.Context) error {
 	sdk.GoRoutine(ctx, "authentication.SessionCleaner", func(ctx context.Context) {
 		authentication.SessionCleaner(ctx, a.mustDB)
 	}, a.PanicDump())
+	sdk.GoRoutine(ctx, "authentication.ConsumerExpirationReminder", func(ctx context.Context) {
+		authentication.ConsumerExpirationReminder(ctx, a.mustDB)
+	}, a.PanicDump())
 
 	migrate.Add(ctx, sdk.Migration{Name: "AddDefaultVCSNotifications", Release: "0.41.0", Automatic: true, ExecFunc: func(ctx context.Context) error {
 		return migrate.AddDefaultVCSNotifications(ctx, a.Cache, a.DBConnectionFactory.GetDBMap)
@@ -717,6 +809,14 @@ func (a *API) Serve(ctx context.Context) error {
 	migrate.Add(ctx, sdk.Migration{Name: "RefactorAuthenticationAuth", Release: "0.41.0", Automatic: false, ExecFunc: func(ctx context.Context) error {
 		return migrate.RefactorAuthenticationAuth(ctx, a.DBConnectionFactory.GetDBMap(), a.Cache, a.Config.URL.API, a.Config.URL.UI)
 	}})
+	migrate.Add(ctx, sdk.Migration{Name: "EncryptWebHookSecrets", Release: "0.41.0", Automatic: true, ExecFunc: func(ctx context.Context) error {
+		return migrate.EncryptWebHookSecrets(ctx, a.DBConnectionFactory.GetDBMap())
+	}})
+	if workflow.LogStorageEnabled() {
+		migrate.Add(ctx, sdk.Migration{Name: "MoveStepLogsToObjectStore", Release: "0.41.0", Automatic: false, ExecFunc: func(ctx context.Context) error {
+			return migrate.MoveStepLogsToObjectStore(ctx, a.DBConnectionFactory.GetDBMap())
+		}})
+	}
 
 	isFreshInstall, errF := version.IsFreshInstall(a.mustDB())
 	if errF != nil {
@@ -778,7 +878,10 @@ func (a *API) Serve(ctx context.Context) error {
 		}, a.PanicDump())
 	sdk.GoRoutine(ctx, "workflow.Initialize",
 		func(ctx context.Context) {
-			workflow.Initialize(ctx, a.DBConnectionFactory.GetDBMap, a.Cache, a.Config.URL.UI, a.Config.DefaultOS, a.Config.DefaultArch)
+			workflow.Initialize(ctx, a.DBConnectionFactory.GetDBMap, a.Cache, a.Config.URL.UI, a.Config.DefaultOS, a.Config.DefaultArch,
+				func(ctx context.Context, db gorp.SqlExecutor, store cache.Store, nodeJobRunID int64) (*sdk.Project, error) {
+					return project.LoadProjectByNodeJobRunID(ctx, db, store, nodeJobRunID, project.LoadOptions.WithVariables)
+				})
 		}, a.PanicDump())
 	sdk.GoRoutine(ctx, "PushInElasticSearch",
 		func(ctx context.Context) {
@@ -210,6 +210,12 @@ func InsertVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid variable name. It should match %s", sdk.NamePattern))
 	}
 
+	if variable.Type == sdk.ExternalSecretVariable {
+		if _, err := secret.CheckReference(app.ProjectKey, variable.Value); err != nil {
+			return err
+		}
+	}
+
 	if sdk.NeedPlaceholder(variable.Type) && variable.Value == sdk.PasswordPlaceholder {
 		return fmt.Errorf("You try to insert a placeholder for new variable %s", variable.Name)
 	}
@@ -251,6 +257,12 @@ func UpdateVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid variable name. It should match %s", sdk.NamePattern))
 	}
 
+	if variable.Type == sdk.ExternalSecretVariable {
+		if _, err := secret.CheckReference(app.ProjectKey, variable.Value); err != nil {
+			return err
+		}
+	}
+
 	if sdk.NeedPlaceholder(variable.Type) && variable.Value == sdk.PasswordPlaceholder {
 		variable.Value = variableBefore.Value
 	}
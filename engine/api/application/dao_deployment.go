@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertDeployment records a deployment in the history of an application
+func InsertDeployment(db gorp.SqlExecutor, d *sdk.ApplicationDeployment) error {
+	d.Created = time.Now()
+	dbDeployment := dbApplicationDeployment(*d)
+	if err := gorpmapping.Insert(db, &dbDeployment); err != nil {
+		return sdk.WrapError(err, "cannot insert deployment for application %d", d.ApplicationID)
+	}
+	*d = sdk.ApplicationDeployment(dbDeployment)
+	return nil
+}
+
+// LoadDeployments returns the last deployments of an application sorted from the newest to the oldest,
+// an empty environment name returns the deployments of all the environments
+func LoadDeployments(ctx context.Context, db gorp.SqlExecutor, appID int64, environmentName string, limit int) ([]sdk.ApplicationDeployment, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM application_deployment
+		WHERE application_id = $1 AND ($2 = '' OR environment_name = $2)
+		ORDER BY id DESC
+		LIMIT $3`).Args(appID, environmentName, limit)
+	var dbDeployments []dbApplicationDeployment
+	if err := gorpmapping.GetAll(ctx, db, query, &dbDeployments); err != nil {
+		return nil, sdk.WrapError(err, "cannot load deployments for application %d", appID)
+	}
+	deployments := make([]sdk.ApplicationDeployment, len(dbDeployments))
+	for i := range dbDeployments {
+		deployments[i] = sdk.ApplicationDeployment(dbDeployments[i])
+	}
+	return deployments, nil
+}
@@ -8,10 +8,12 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 func (api *API) getApplicationDeploymentStrategiesConfigHandler() service.Handler {
@@ -199,3 +201,199 @@ func (api *API) getApplicationDeploymentStrategyConfigHandler() service.Handler
 		return service.WriteJSON(w, cfg, http.StatusOK)
 	}
 }
+
+// maxDeploymentHistory is the number of deployments returned by the history and used to find a rollback target
+const maxDeploymentHistory = 100
+
+func (api *API) getApplicationDeploymentHistoryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+		if limit <= 0 || limit > maxDeploymentHistory {
+			limit = maxDeploymentHistory
+		}
+
+		app, err := application.LoadByName(api.mustDB(), api.Cache, key, appName)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load application")
+		}
+
+		history, err := application.LoadDeployments(ctx, api.mustDB(), app.ID, FormString(r, "environment"), limit)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, history, http.StatusOK)
+	}
+}
+
+// postApplicationDeploymentRollbackHandler relaunches the node run of the current deployment of an application. The
+// DeployApplication step of the new node run asks the integration plugin to restore the previous version, the other
+// steps are skipped and the children of the node are not triggered.
+func (api *API) postApplicationDeploymentRollbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+
+		var req sdk.ApplicationDeploymentRollback
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key,
+			project.LoadOptions.WithVariables,
+			project.LoadOptions.WithFeatures,
+			project.LoadOptions.WithIntegrations,
+			project.LoadOptions.WithApplicationVariables,
+			project.LoadOptions.WithApplicationWithDeploymentStrategies,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithPipelines,
+		)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		app, err := application.LoadByName(api.mustDB(), api.Cache, key, appName)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load application")
+		}
+
+		history, err := application.LoadDeployments(ctx, api.mustDB(), app.ID, req.Environment, maxDeploymentHistory)
+		if err != nil {
+			return err
+		}
+		for i := range history {
+			if history[i].EnvironmentName != history[0].EnvironmentName {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "application %s is deployed on several environments, an environment is required", app.Name)
+			}
+		}
+
+		current, target, err := sdk.ApplicationDeploymentRollbackTarget(history, req.Version)
+		if err != nil {
+			return err
+		}
+
+		wr, err := workflow.LoadRunByID(api.mustDB(), current.WorkflowRunID, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run %d", current.WorkflowRunID)
+		}
+		nr, err := workflow.LoadNodeRunByID(api.mustDB(), current.WorkflowNodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run %d", current.WorkflowNodeRunID)
+		}
+		node := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+		if node == nil {
+			return sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "unable to find node %d", nr.WorkflowNodeID)
+		}
+
+		c := getAPIConsumer(ctx)
+		if !permission.AccessToWorkflowNode(ctx, api.mustDB(), &wr.Workflow, node, c, sdk.PermissionReadExecute) {
+			return sdk.WrapError(sdk.ErrNoPermExecution, "not enough right on node %s", node.Name)
+		}
+
+		log.Info(ctx, "rollback application %s/%s on environment %q from version %s to %s with workflow run %s #%d", key, app.Name,
+			current.EnvironmentName, current.Version, target.Version, wr.Workflow.Name, wr.Number)
+
+		opts := &sdk.WorkflowRunPostHandlerOption{
+			Number:      &wr.Number,
+			FromNodeIDs: []int64{node.ID},
+			Manual: &sdk.WorkflowNodeRunManual{
+				Payload:            nr.Payload,
+				PipelineParameters: nr.PipelineParameters,
+				RollbackVersion:    target.Version,
+			},
+		}
+
+		wr.Status = sdk.StatusWaiting
+		sdk.GoRoutine(context.Background(), fmt.Sprintf("api.initWorkflowRun-%d", wr.ID), func(ctx context.Context) {
+			api.initWorkflowRun(ctx, api.mustDB(), api.Cache, p, &wr.Workflow, wr, opts, c)
+		}, api.PanicDump())
+
+		return service.WriteJSON(w, wr, http.StatusAccepted)
+	}
+}
+
+// postWorkflowJobDeploymentHandler records the deployment done by a DeployApplication step in the history of the application
+func (api *API) postWorkflowJobDeploymentHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if _, isWorker := api.isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		id, err := requestVarInt(r, "permJobID")
+		if err != nil {
+			return sdk.WrapError(err, "invalid id")
+		}
+
+		var d sdk.ApplicationDeployment
+		if err := service.UnmarshalBody(r, &d); err != nil {
+			return err
+		}
+
+		nr, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run for job %d", id)
+		}
+		if nr.ApplicationID == 0 {
+			return sdk.WrapError(sdk.ErrApplicationNotFound, "there is no application linked")
+		}
+		wr, err := workflow.LoadRunByID(api.mustDB(), nr.WorkflowRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run %d", nr.WorkflowRunID)
+		}
+
+		d.ProjectID = wr.ProjectID
+		d.ApplicationID = nr.ApplicationID
+		d.WorkflowName = wr.Workflow.Name
+		d.WorkflowRunID = wr.ID
+		d.WorkflowRunNumber = wr.Number
+		d.WorkflowNodeRunID = nr.ID
+		d.EnvironmentName = ""
+		d.IntegrationName = ""
+		if node := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID); node != nil && node.Context != nil {
+			if env, has := wr.Workflow.Environments[node.Context.EnvironmentID]; has {
+				d.EnvironmentName = env.Name
+			}
+			if integ, has := wr.Workflow.ProjectIntegrations[node.Context.ProjectIntegrationID]; has {
+				d.IntegrationName = integ.Name
+			}
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "unable to start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		// Keep the version that was replaced by a rollback, it will not be restored by the next rollback
+		d.RollbackFrom = ""
+		if d.Strategy == sdk.DeploymentStrategyRollback {
+			history, err := application.LoadDeployments(ctx, tx, d.ApplicationID, d.EnvironmentName, maxDeploymentHistory)
+			if err != nil {
+				return err
+			}
+			for _, h := range history {
+				if h.EnvironmentName == d.EnvironmentName && h.Status == sdk.StatusSuccess {
+					d.RollbackFrom = h.Version
+					break
+				}
+			}
+		}
+
+		if err := application.InsertDeployment(tx, &d); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, d, http.StatusOK)
+	}
+}
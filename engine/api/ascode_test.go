@@ -130,7 +130,7 @@ func Test_getImportAsCodeHandler(t *testing.T) {
 
 	UUID := sdk.UUID()
 
-	feature.SetClient(nil)
+	feature.SetDriver(nil)
 
 	//This is a mock for the repositories service
 	services.HTTPClient = mock(
@@ -87,6 +87,35 @@ func (api *API) getAuthAskSigninHandler() service.Handler {
 	}
 }
 
+func (api *API) postAuthDeviceCodeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		// Extract consumer type from request, is invalid or not in api drivers list return an error
+		consumerType := sdk.AuthConsumerType(vars["consumerType"])
+		if !consumerType.IsValid() {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		driver, ok := api.AuthenticationDrivers[consumerType]
+		if !ok {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+
+		driverDeviceCode, ok := driver.(sdk.AuthDriverWithDeviceCode)
+		if !ok || !driver.GetManifest().DeviceCode {
+			return sdk.NewErrorFrom(sdk.ErrNotImplemented, "device code signin is not available for %s", consumerType)
+		}
+
+		// Start a device authorization, the signin will be done by polling the signin route with the device code
+		code, err := driverDeviceCode.GetDeviceCode(ctx)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, code, http.StatusOK)
+	}
+}
+
 func (api *API) postAuthSigninHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -260,6 +289,17 @@ func (api *API) postAuthSigninHandler() service.Handler {
 			}
 		}
 
+		// Synchronize the memberships of the groups managed by the driver
+		if x, ok := driver.(sdk.AuthDriverWithGroupsSync); ok && userInfo.Groups != nil {
+			u, err := user.LoadByID(ctx, tx, consumer.AuthentifiedUserID, user.LoadOptions.WithDeprecatedUser)
+			if err != nil {
+				return err
+			}
+			if err := authentication.SyncUserGroups(ctx, tx, u, x.GetManagedGroups(), userInfo.Groups); err != nil {
+				return err
+			}
+		}
+
 		// Generate a new session for consumer
 		session, err := authentication.NewSession(ctx, tx, consumer, driver.GetSessionDuration(), userInfo.MFA)
 		if err != nil {
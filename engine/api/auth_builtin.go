@@ -51,6 +51,9 @@ func (api *API) postAuthBuiltinSigninHandler() service.Handler {
 		if _, err := builtin.CheckSigninConsumerTokenIssuedAt(req["token"], consumer.IssuedAt); err != nil {
 			return err
 		}
+		if consumer.IsExpired() {
+			return sdk.NewErrorFrom(sdk.ErrUnauthorized, "consumer %s has expired", consumer.Name)
+		}
 
 		// Generate a new session for consumer
 		session, err := authentication.NewSession(ctx, tx, consumer, driver.GetSessionDuration(), false)
@@ -73,8 +73,8 @@ func (api *API) postConsumerByUserHandler() service.Handler {
 		}
 
 		// Create the new built in consumer from request data
-		newConsumer, token, err := builtin.NewConsumer(ctx, api.mustDB(), reqData.Name, reqData.Description,
-			consumer, reqData.GroupIDs, reqData.Scopes)
+		newConsumer, token, err := builtin.NewConsumerWithRestrictions(ctx, api.mustDB(), reqData.Name, reqData.Description,
+			consumer, reqData.GroupIDs, reqData.Scopes, reqData.Restrictions, reqData.ExpireAt)
 		if err != nil {
 			return err
 		}
@@ -67,6 +67,14 @@ func (d AuthDriver) CheckSigninRequest(req sdk.AuthConsumerSigninRequest) error
 // The parent consumer should be given with all data loaded including the authentified user.
 func NewConsumer(ctx context.Context, db gorp.SqlExecutor, name, description string, parentConsumer *sdk.AuthConsumer,
 	groupIDs []int64, scopes []sdk.AuthConsumerScope) (*sdk.AuthConsumer, string, error) {
+	return NewConsumerWithRestrictions(ctx, db, name, description, parentConsumer, groupIDs, scopes, sdk.AuthConsumerRestrictions{}, nil)
+}
+
+// NewConsumerWithRestrictions returns a new builtin consumer restricted to some projects, workflows or http methods,
+// with an optional expiration date. The consumer inherits the restrictions and the expiration date of its parent
+// if not given, and can't have less restrictions than its parent.
+func NewConsumerWithRestrictions(ctx context.Context, db gorp.SqlExecutor, name, description string, parentConsumer *sdk.AuthConsumer,
+	groupIDs []int64, scopes []sdk.AuthConsumerScope, restrictions sdk.AuthConsumerRestrictions, expireAt *time.Time) (*sdk.AuthConsumer, string, error) {
 	if name == "" {
 		return nil, "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "name should be given to create a built in consumer")
 	}
@@ -106,6 +114,32 @@ func NewConsumer(ctx context.Context, db gorp.SqlExecutor, name, description str
 		}
 	}
 
+	if !restrictions.HasProjectRestriction() {
+		restrictions.ProjectKeys = parentConsumer.Restrictions.ProjectKeys
+		restrictions.Workflows = parentConsumer.Restrictions.Workflows
+	}
+	if len(restrictions.Methods) == 0 {
+		restrictions.Methods = parentConsumer.Restrictions.Methods
+	}
+	if err := restrictions.IsValid(); err != nil {
+		return nil, "", err
+	}
+	if !restrictions.IsSubsetOf(parentConsumer.Restrictions) {
+		return nil, "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "built in consumer can't have less restrictions than its parent")
+	}
+
+	if expireAt == nil {
+		expireAt = parentConsumer.ExpireAt
+	}
+	if expireAt != nil {
+		if expireAt.Before(time.Now()) {
+			return nil, "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given expiration date in the past")
+		}
+		if parentConsumer.ExpireAt != nil && expireAt.After(*parentConsumer.ExpireAt) {
+			return nil, "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "built in consumer can't expire after its parent")
+		}
+	}
+
 	c := sdk.AuthConsumer{
 		Name:               name,
 		Description:        description,
@@ -116,6 +150,8 @@ func NewConsumer(ctx context.Context, db gorp.SqlExecutor, name, description str
 		GroupIDs:           groupIDs,
 		Scopes:             scopes,
 		IssuedAt:           time.Now(),
+		Restrictions:       restrictions,
+		ExpireAt:           expireAt,
 	}
 
 	if err := authentication.InsertConsumer(ctx, db, &c); err != nil {
@@ -179,7 +179,7 @@ func ConsumerRestoreInvalidatedGroupForUser(ctx context.Context, db gorp.SqlExec
 		filteredWarnings := make(sdk.AuthConsumerWarnings, 0, len(cs[i].Warnings))
 		for _, w := range cs[i].Warnings {
 			if (w.Type == sdk.WarningGroupInvalid && w.GroupID != groupID) ||
-				w.Type == sdk.WarningGroupRemoved {
+				w.Type == sdk.WarningGroupRemoved || w.Type == sdk.WarningExpireSoon {
 				filteredWarnings = append(filteredWarnings, w)
 			}
 		}
@@ -253,7 +253,7 @@ func ConsumerRestoreInvalidatedGroupsForUser(ctx context.Context, db gorp.SqlExe
 		// Clean warnings, removes warning for invalid groups and last group removed warning if exists
 		filteredWarnings := make(sdk.AuthConsumerWarnings, 0, len(cs[i].Warnings))
 		for _, w := range cs[i].Warnings {
-			if w.Type == sdk.WarningGroupRemoved {
+			if w.Type == sdk.WarningGroupRemoved || w.Type == sdk.WarningExpireSoon {
 				filteredWarnings = append(filteredWarnings, w)
 			}
 		}
@@ -0,0 +1,74 @@
+package authentication
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/mail"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// ConsumerExpirationRemindDelay is the delay before expiration when the owner of a consumer is reminded.
+const ConsumerExpirationRemindDelay = 7 * 24 * time.Hour
+
+// ConsumerExpirationReminder checks periodically consumers that will expire soon and remind their owners.
+func ConsumerExpirationReminder(ctx context.Context, dbFunc func() *gorp.DbMap) {
+	log.Info(ctx, "Initializing consumer expiration reminder...")
+	tick := time.NewTicker(time.Hour)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "ConsumerExpirationReminder> Exiting consumer expiration reminder: %v", ctx.Err())
+				return
+			}
+		case <-tick.C:
+			if err := RemindConsumersExpiration(ctx, dbFunc()); err != nil {
+				log.Error(ctx, "ConsumerExpirationReminder> %v", err)
+			}
+		}
+	}
+}
+
+// RemindConsumersExpiration adds a warning on consumers that will expire soon and sends a mail to their owners.
+// A consumer is reminded only once, the warning is used to know if it was already done.
+func RemindConsumersExpiration(ctx context.Context, db gorp.SqlExecutor) error {
+	cs, err := LoadConsumersExpireBefore(ctx, db, time.Now().Add(ConsumerExpirationRemindDelay))
+	if err != nil {
+		return err
+	}
+
+	for i := range cs {
+		var alreadyReminded bool
+		for _, w := range cs[i].Warnings {
+			if w.Type == sdk.WarningExpireSoon {
+				alreadyReminded = true
+				break
+			}
+		}
+		if alreadyReminded || cs[i].IsExpired() {
+			continue
+		}
+
+		cs[i].Warnings = append(cs[i].Warnings, sdk.NewConsumerWarningExpireSoon(*cs[i].ExpireAt))
+		if err := UpdateConsumer(ctx, db, &cs[i]); err != nil {
+			return err
+		}
+
+		u, err := user.LoadByID(ctx, db, cs[i].AuthentifiedUserID, user.LoadOptions.WithContacts)
+		if err != nil {
+			return err
+		}
+		if err := mail.SendMailConsumerExpireSoon(ctx, u.GetEmail(), u.Username, cs[i].Name, *cs[i].ExpireAt); err != nil {
+			log.Warning(ctx, "RemindConsumersExpiration> cannot send mail for consumer %s: %v", cs[i].ID, err)
+		}
+	}
+
+	return nil
+}
@@ -109,6 +109,17 @@ func LoadConsumerByTypeAndUserExternalID(ctx context.Context, db gorp.SqlExecuto
 	return getConsumer(ctx, db, query, opts...)
 }
 
+// LoadConsumersExpireBefore returns enabled auth consumers from database that will expire before given date.
+func LoadConsumersExpireBefore(ctx context.Context, db gorp.SqlExecutor, t time.Time, opts ...LoadConsumerOptionFunc) (sdk.AuthConsumers, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM auth_consumer
+    WHERE expire_at IS NOT NULL AND expire_at < $1 AND disabled = false
+    ORDER BY expire_at ASC
+  `).Args(t)
+	return getConsumers(ctx, db, query, opts...)
+}
+
 // InsertConsumer in database.
 func InsertConsumer(ctx context.Context, db gorp.SqlExecutor, ac *sdk.AuthConsumer) error {
 	if ac.ID == "" {
@@ -133,6 +144,12 @@ func UpdateConsumer(ctx context.Context, db gorp.SqlExecutor, ac *sdk.AuthConsum
 	return nil
 }
 
+// UpdateConsumerLastUsed sets the last usage date of a consumer, this field is not signed so the consumer is not updated entirely.
+func UpdateConsumerLastUsed(db gorp.SqlExecutor, id string, t time.Time) error {
+	_, err := db.Exec("UPDATE auth_consumer SET last_used = $2 WHERE id = $1", id, t)
+	return sdk.WrapError(err, "unable to update last usage of auth consumer with id %s", id)
+}
+
 // DeleteConsumerByID removes a auth consumer in database for given id.
 func DeleteConsumerByID(db gorp.SqlExecutor, id string) error {
 	_, err := db.Exec("DELETE FROM auth_consumer WHERE id = $1", id)
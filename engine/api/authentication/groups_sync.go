@@ -0,0 +1,86 @@
+package authentication
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// SyncUserGroups sets the memberships of the user for the groups managed by an auth driver.
+// The user is added to the given groups and removed from the other managed groups, consumers are updated like
+// when a membership is changed by hand. Unknown groups and the removal of the last admin of a group are skipped.
+// Given user should be loaded with its deprecated user struct.
+func SyncUserGroups(ctx context.Context, db gorp.SqlExecutor, u *sdk.AuthentifiedUser, managedGroups, userGroups []string) error {
+	if u.OldUserStruct == nil {
+		return sdk.WithStack(sdk.ErrUserNotFound)
+	}
+
+	links, err := group.LoadLinksGroupUserForUserIDs(ctx, db, []int64{u.OldUserStruct.ID})
+	if err != nil {
+		return err
+	}
+	currentGroupIDs := sdk.Int64Slice(links.ToGroupIDs())
+
+	for _, name := range managedGroups {
+		g, err := group.LoadByName(ctx, db, name)
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				log.Warning(ctx, "authentication.SyncUserGroups> managed group %s doesn't exist", name)
+				continue
+			}
+			return err
+		}
+
+		isMember := currentGroupIDs.Contains(g.ID)
+		shouldBeMember := sdk.IsInArray(name, userGroups)
+
+		switch {
+		case shouldBeMember && !isMember:
+			if err := group.InsertLinkGroupUser(db, &group.LinkGroupUser{
+				GroupID: g.ID,
+				UserID:  u.OldUserStruct.ID,
+			}); err != nil {
+				return err
+			}
+			if err := ConsumerRestoreInvalidatedGroupForUser(ctx, db, g.ID, u.ID); err != nil {
+				return err
+			}
+		case !shouldBeMember && isMember:
+			var link *group.LinkGroupUser
+			for i := range links {
+				if links[i].GroupID == g.ID {
+					link = &links[i]
+				}
+			}
+			if link.Admin {
+				groupLinks, err := group.LoadLinksGroupUserForGroupIDs(ctx, db, []int64{g.ID})
+				if err != nil {
+					return err
+				}
+				var adminFound bool
+				for i := range groupLinks {
+					if groupLinks[i].UserID != u.OldUserStruct.ID && groupLinks[i].Admin {
+						adminFound = true
+						break
+					}
+				}
+				if !adminFound {
+					log.Warning(ctx, "authentication.SyncUserGroups> user %s is the last admin of group %s, it will not be removed", u.Username, g.Name)
+					continue
+				}
+			}
+			if err := group.DeleteLinkGroupUser(db, link); err != nil {
+				return err
+			}
+			if err := ConsumerInvalidateGroupForUser(ctx, db, g, u); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package authentication_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSyncUserGroups(t *testing.T) {
+	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	g1 := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	g2 := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	g3 := &sdk.Group{Name: sdk.RandomString(10)}
+	u, _ := assets.InsertLambdaUser(t, db, g1, g3) // user is the admin of g3 because it creates it
+
+	managed := []string{g1.Name, g2.Name, g3.Name, "unknown-group"}
+	require.NoError(t, authentication.SyncUserGroups(context.TODO(), db, u, managed, []string{g2.Name}))
+
+	links, err := group.LoadLinksGroupUserForUserIDs(context.TODO(), db, []int64{u.OldUserStruct.ID})
+	require.NoError(t, err)
+	groupIDs := sdk.Int64Slice(links.ToGroupIDs())
+	assert.False(t, groupIDs.Contains(g1.ID), "user should be removed from g1")
+	assert.True(t, groupIDs.Contains(g2.ID), "user should be added to g2")
+	assert.True(t, groupIDs.Contains(g3.ID), "the last admin of g3 should not be removed")
+
+	// Running the sync again should not change anything
+	require.NoError(t, authentication.SyncUserGroups(context.TODO(), db, u, managed, []string{g2.Name}))
+	links2, err := group.LoadLinksGroupUserForUserIDs(context.TODO(), db, []int64{u.OldUserStruct.ID})
+	require.NoError(t, err)
+	assert.Len(t, links2, len(links))
+}
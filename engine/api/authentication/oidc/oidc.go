@@ -0,0 +1,441 @@
+package oidc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	_ sdk.AuthDriverWithRedirect         = new(authDriver)
+	_ sdk.AuthDriverWithSigninStateToken = new(authDriver)
+	_ sdk.AuthDriverWithGroupsSync       = new(authDriver)
+	_ sdk.AuthDriverWithDeviceCode       = new(authDriver)
+)
+
+// Config for an OpenID Connect provider.
+type Config struct {
+	URL           string
+	ClientID      string
+	ClientSecret  string
+	Scopes        []string
+	UsernameClaim string
+	GroupsClaim   string
+	// GroupsMapping gives the CDS group for each value of the groups claim
+	GroupsMapping map[string]string
+}
+
+// providerMetadata is the subset of the OpenID provider discovery document used by the driver.
+type providerMetadata struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	JWKSURI                     string `json:"jwks_uri"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+type authDriver struct {
+	signupDisabled bool
+	cdsURL         string
+	config         Config
+	provider       providerMetadata
+	httpClient     *http.Client
+
+	keysMutex sync.Mutex
+	keys      jose.JSONWebKeySet
+	keysDate  time.Time
+}
+
+// NewDriver returns a new OpenID Connect auth driver, the provider configuration is discovered from its issuer URL.
+func NewDriver(ctx context.Context, signupDisabled bool, cdsURL string, cfg Config) (sdk.AuthDriver, error) {
+	if cfg.URL == "" || cfg.ClientID == "" {
+		return nil, sdk.WithStack(fmt.Errorf("invalid oidc configuration: url and client id are mandatory"))
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+
+	d := &authDriver{
+		signupDisabled: signupDisabled,
+		cdsURL:         cdsURL,
+		config:         cfg,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+	}
+
+	issuer := strings.TrimSuffix(cfg.URL, "/")
+	if err := d.getJSON(ctx, issuer+"/.well-known/openid-configuration", &d.provider); err != nil {
+		return nil, sdk.WrapError(err, "unable to discover oidc provider %s", issuer)
+	}
+	if strings.TrimSuffix(d.provider.Issuer, "/") != issuer {
+		return nil, sdk.WithStack(fmt.Errorf("oidc provider issuer %s doesn't match %s", d.provider.Issuer, issuer))
+	}
+	if d.provider.AuthorizationEndpoint == "" || d.provider.TokenEndpoint == "" || d.provider.JWKSURI == "" {
+		return nil, sdk.WithStack(fmt.Errorf("invalid oidc provider %s metadata", issuer))
+	}
+
+	return d, nil
+}
+
+func (d *authDriver) GetManifest() sdk.AuthDriverManifest {
+	return sdk.AuthDriverManifest{
+		Type:           sdk.ConsumerOIDC,
+		SignupDisabled: d.signupDisabled,
+		DeviceCode:     d.provider.DeviceAuthorizationEndpoint != "",
+	}
+}
+
+func (d *authDriver) GetSessionDuration() time.Duration {
+	return time.Hour * 24 * 30 // 1 month session
+}
+
+// GetManagedGroups returns the CDS groups given by the groups mapping.
+func (d *authDriver) GetManagedGroups() []string {
+	var groups []string
+	for _, g := range d.config.GroupsMapping {
+		if !sdk.IsInArray(g, groups) {
+			groups = append(groups, g)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+func (d *authDriver) redirectURI() string {
+	return d.cdsURL + "/auth/callback/" + string(sdk.ConsumerOIDC)
+}
+
+// GetSigninURI returns the authorization code request, with a PKCE code challenge and a nonce derived from the state.
+func (d *authDriver) GetSigninURI(signinState sdk.AuthSigninConsumerToken) (sdk.AuthDriverSigningRedirect, error) {
+	state, err := authentication.NewDefaultSigninStateToken(signinState.Origin,
+		signinState.RedirectURI, signinState.IsFirstConnection)
+	if err != nil {
+		return sdk.AuthDriverSigningRedirect{}, err
+	}
+
+	challenge := sha256.Sum256([]byte(d.deriveFromState("code_verifier", state)))
+
+	u, err := url.Parse(d.provider.AuthorizationEndpoint)
+	if err != nil {
+		return sdk.AuthDriverSigningRedirect{}, sdk.WithStack(err)
+	}
+	q := u.Query()
+	q.Set("client_id", d.config.ClientID)
+	q.Set("response_type", "code")
+	q.Set("scope", strings.Join(d.config.Scopes, " "))
+	q.Set("redirect_uri", d.redirectURI())
+	q.Set("state", state)
+	q.Set("nonce", d.deriveFromState("nonce", state))
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return sdk.AuthDriverSigningRedirect{
+		Method: http.MethodGet,
+		URL:    u.String(),
+	}, nil
+}
+
+// deriveFromState returns a value bound to the signin state and only known by the API,
+// it is used as PKCE code verifier and as nonce without storing them between the redirect and the signin.
+func (d *authDriver) deriveFromState(usage, state string) string {
+	mac := hmac.New(sha256.New, x509.MarshalPKCS1PrivateKey(authentication.GetSigningKey()))
+	mac.Write([]byte(usage + ":" + state)) // nolint
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (d *authDriver) CheckSigninRequest(req sdk.AuthConsumerSigninRequest) error {
+	if req["device_code"] != "" {
+		return nil
+	}
+	if code, ok := req["code"]; !ok || code == "" {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing or invalid oidc code")
+	}
+	return nil
+}
+
+func (d *authDriver) CheckSigninStateToken(req sdk.AuthConsumerSigninRequest) error {
+	// There is no state for the device authorization flow
+	if req["device_code"] != "" {
+		return nil
+	}
+	state, okState := req["state"]
+	if !okState {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing state value")
+	}
+	return authentication.CheckDefaultSigninStateToken(state)
+}
+
+// GetDeviceCode starts a device authorization flow on the provider.
+func (d *authDriver) GetDeviceCode(ctx context.Context) (sdk.AuthDriverDeviceCode, error) {
+	var res sdk.AuthDriverDeviceCode
+	if d.provider.DeviceAuthorizationEndpoint == "" {
+		return res, sdk.NewErrorFrom(sdk.ErrNotImplemented, "oidc provider doesn't support device authorization")
+	}
+
+	if err := d.postForm(ctx, d.provider.DeviceAuthorizationEndpoint, url.Values{
+		"scope": {strings.Join(d.config.Scopes, " ")},
+	}, &res); err != nil {
+		return res, err
+	}
+	if res.Interval == 0 {
+		res.Interval = 5
+	}
+	return res, nil
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (d *authDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninRequest) (sdk.AuthDriverUserInfo, error) {
+	var info sdk.AuthDriverUserInfo
+
+	var nonce string
+	var t tokenResponse
+	if deviceCode := req["device_code"]; deviceCode != "" {
+		if err := d.postForm(ctx, d.provider.TokenEndpoint, url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {deviceCode},
+		}, &t); err != nil {
+			return info, err
+		}
+	} else {
+		nonce = d.deriveFromState("nonce", req["state"])
+		if err := d.postForm(ctx, d.provider.TokenEndpoint, url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {req["code"]},
+			"redirect_uri":  {d.redirectURI()},
+			"code_verifier": {d.deriveFromState("code_verifier", req["state"])},
+		}, &t); err != nil {
+			return info, err
+		}
+	}
+	if t.IDToken == "" {
+		return info, sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing id token in oidc provider response")
+	}
+
+	claims, err := d.verifyIDToken(ctx, t.IDToken, nonce)
+	if err != nil {
+		return info, err
+	}
+	return d.userInfoFromClaims(claims)
+}
+
+type idTokenClaims struct {
+	jwt.Claims
+	Nonce string `json:"nonce"`
+}
+
+// verifyIDToken checks the signature of the id token with the provider keys, and its claims. It returns all the claims of the token.
+func (d *authDriver) verifyIDToken(ctx context.Context, rawToken, nonce string) (map[string]interface{}, error) {
+	token, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return nil, sdk.NewError(sdk.ErrUnauthorized, fmt.Errorf("invalid id token: %v", err))
+	}
+	if len(token.Headers) == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid id token: missing header")
+	}
+	if token.Headers[0].Algorithm == "none" || strings.HasPrefix(token.Headers[0].Algorithm, "HS") {
+		return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid id token: unsupported signing algorithm %s", token.Headers[0].Algorithm)
+	}
+
+	key, err := d.getKey(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var std idTokenClaims
+	var claims map[string]interface{}
+	if err := token.Claims(key, &std, &claims); err != nil {
+		return nil, sdk.NewError(sdk.ErrUnauthorized, fmt.Errorf("invalid id token signature: %v", err))
+	}
+	if err := std.Claims.Validate(jwt.Expected{
+		Issuer:   d.provider.Issuer,
+		Audience: jwt.Audience{d.config.ClientID},
+		Time:     time.Now(),
+	}); err != nil {
+		return nil, sdk.NewError(sdk.ErrUnauthorized, fmt.Errorf("invalid id token: %v", err))
+	}
+	if std.Expiry == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid id token: missing expiration")
+	}
+	if nonce != "" && std.Nonce != nonce {
+		return nil, sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid id token: nonce doesn't match")
+	}
+
+	return claims, nil
+}
+
+// getKey returns the provider key for given key id, the keys are fetched again if the key is unknown to handle keys rotation.
+func (d *authDriver) getKey(ctx context.Context, kid string) (jose.JSONWebKey, error) {
+	d.keysMutex.Lock()
+	defer d.keysMutex.Unlock()
+
+	find := func() (jose.JSONWebKey, bool) {
+		if kid == "" && len(d.keys.Keys) == 1 {
+			return d.keys.Keys[0], true
+		}
+		if keys := d.keys.Key(kid); len(keys) > 0 {
+			return keys[0], true
+		}
+		return jose.JSONWebKey{}, false
+	}
+
+	if k, ok := find(); ok {
+		return k, nil
+	}
+	// Don't flood the provider with unknown key ids
+	if time.Since(d.keysDate) < time.Minute {
+		return jose.JSONWebKey{}, sdk.NewErrorFrom(sdk.ErrUnauthorized, "unknown id token key %q", kid)
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := d.getJSON(ctx, d.provider.JWKSURI, &keys); err != nil {
+		return jose.JSONWebKey{}, sdk.WrapError(err, "unable to get oidc provider keys")
+	}
+	d.keys = keys
+	d.keysDate = time.Now()
+
+	if k, ok := find(); ok {
+		return k, nil
+	}
+	return jose.JSONWebKey{}, sdk.NewErrorFrom(sdk.ErrUnauthorized, "unknown id token key %q", kid)
+}
+
+func (d *authDriver) userInfoFromClaims(claims map[string]interface{}) (sdk.AuthDriverUserInfo, error) {
+	var info sdk.AuthDriverUserInfo
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return info, sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing sub claim in id token")
+	}
+	info.ExternalID = sub
+	info.Username, _ = claims[d.config.UsernameClaim].(string)
+	info.Fullname, _ = claims["name"].(string)
+	info.Email, _ = claims["email"].(string)
+	if info.Username == "" {
+		return info, sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing %s claim in id token", d.config.UsernameClaim)
+	}
+	if info.Email == "" {
+		return info, sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing email claim in id token")
+	}
+	// The email is used to find existing users, it must have been verified by the provider
+	if verified, _ := claims["email_verified"].(bool); !verified {
+		return info, sdk.NewErrorFrom(sdk.ErrUnauthorized, "email %s is not verified", info.Email)
+	}
+	if amr, ok := claims["amr"].([]interface{}); ok {
+		for _, m := range amr {
+			if m == "mfa" || m == "otp" {
+				info.MFA = true
+			}
+		}
+	}
+
+	// Only the mapped groups are given, with an empty slice if the user is member of none of them
+	if len(d.config.GroupsMapping) > 0 {
+		info.Groups = []string{}
+		var values []string
+		switch v := claims[d.config.GroupsClaim].(type) {
+		case string:
+			values = []string{v}
+		case []interface{}:
+			for i := range v {
+				if s, ok := v[i].(string); ok {
+					values = append(values, s)
+				}
+			}
+		}
+		for _, v := range values {
+			if g, ok := d.config.GroupsMapping[v]; ok && !sdk.IsInArray(g, info.Groups) {
+				info.Groups = append(info.Groups, g)
+			}
+		}
+		sort.Strings(info.Groups)
+	}
+
+	return info, nil
+}
+
+func (d *authDriver) getJSON(ctx context.Context, u string, i interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := d.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer res.Body.Close() // nolint
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return sdk.WithStack(fmt.Errorf("%s returned %d: %s", u, res.StatusCode, string(body)))
+	}
+	return sdk.WithStack(json.Unmarshal(body, i))
+}
+
+// postForm sends a request to the provider, authenticated with the client credentials.
+// OAuth2 errors are converted, the pending state of a device authorization returns ErrSigninPending.
+func (d *authDriver) postForm(ctx context.Context, u string, values url.Values, i interface{}) error {
+	values.Set("client_id", d.config.ClientID)
+	if d.config.ClientSecret != "" {
+		values.Set("client_secret", d.config.ClientSecret)
+	}
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(values.Encode()))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	res, err := d.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer res.Body.Close() // nolint
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var oauthErr tokenResponse
+		_ = json.Unmarshal(body, &oauthErr)
+		switch oauthErr.Error {
+		case "authorization_pending", "slow_down":
+			return sdk.WithStack(sdk.ErrSigninPending)
+		case "access_denied", "expired_token", "invalid_grant":
+			return sdk.NewErrorFrom(sdk.ErrUnauthorized, "oidc authorization failed: %s %s", oauthErr.Error, oauthErr.ErrorDescription)
+		}
+		return sdk.WithStack(fmt.Errorf("oidc provider returned %d: %s", res.StatusCode, string(body)))
+	}
+	return sdk.WithStack(json.Unmarshal(body, i))
+}
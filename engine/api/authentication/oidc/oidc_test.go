@@ -0,0 +1,249 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/jws"
+)
+
+type testProvider struct {
+	*httptest.Server
+	key           *rsa.PrivateKey
+	codeChallenge string
+	nonce         string
+	devicePending bool
+	claims        map[string]interface{}
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &testProvider{key: key, devicePending: true}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(providerMetadata{
+			Issuer:                      p.URL,
+			AuthorizationEndpoint:       p.URL + "/authorize",
+			TokenEndpoint:               p.URL + "/token",
+			JWKSURI:                     p.URL + "/keys",
+			DeviceAuthorizationEndpoint: p.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &p.key.PublicKey, KeyID: "key-1", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(sdk.AuthDriverDeviceCode{
+			DeviceCode:      "my-device-code",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: p.URL + "/activate",
+			ExpiresIn:       600,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "cds", r.Form.Get("client_id"))
+		assert.Equal(t, "secret", r.Form.Get("client_secret"))
+
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			verifier := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+			if r.Form.Get("code") != "my-code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != p.codeChallenge {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			if p.devicePending {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(tokenResponse{AccessToken: "access", IDToken: p.idToken(t)})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *testProvider) idToken(t *testing.T) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "key-1"))
+	require.NoError(t, err)
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   p.URL,
+		Subject:  "1234",
+		Audience: jwt.Audience{"cds"},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+	}).Claims(map[string]interface{}{"nonce": p.nonce}).Claims(p.claims).CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func initSigningKey(t *testing.T) {
+	privKey, err := jws.NewRandomRSAKey()
+	require.NoError(t, err)
+	privKeyPEM, err := jws.ExportPrivateKey(privKey)
+	require.NoError(t, err)
+	require.NoError(t, authentication.Init("cds-test", privKeyPEM))
+}
+
+func newTestDriver(t *testing.T, p *testProvider) *authDriver {
+	d, err := NewDriver(context.TODO(), false, "http://cds.local", Config{
+		URL:          p.URL,
+		ClientID:     "cds",
+		ClientSecret: "secret",
+		GroupsMapping: map[string]string{
+			"/dev":   "developers",
+			"/admin": "administrators",
+		},
+	})
+	require.NoError(t, err)
+	return d.(*authDriver)
+}
+
+func TestDriverSignin(t *testing.T) {
+	initSigningKey(t)
+	p := newTestProvider(t)
+	defer p.Close()
+	p.claims = map[string]interface{}{
+		"preferred_username": "john",
+		"name":               "John Doe",
+		"email":              "john@example.com",
+		"email_verified":     true,
+		"groups":             []string{"/dev", "/other"},
+	}
+
+	d := newTestDriver(t, p)
+	assert.Equal(t, []string{"administrators", "developers"}, d.GetManagedGroups())
+	assert.True(t, d.GetManifest().DeviceCode)
+
+	redirect, err := d.GetSigninURI(sdk.AuthSigninConsumerToken{})
+	require.NoError(t, err)
+	u, err := url.Parse(redirect.URL)
+	require.NoError(t, err)
+	assert.Equal(t, p.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "http://cds.local/auth/callback/oidc", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	p.codeChallenge = u.Query().Get("code_challenge")
+	p.nonce = u.Query().Get("nonce")
+
+	req := sdk.AuthConsumerSigninRequest{"code": "my-code", "state": u.Query().Get("state")}
+	require.NoError(t, d.CheckSigninRequest(req))
+	require.NoError(t, d.CheckSigninStateToken(req))
+
+	info, err := d.GetUserInfo(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "1234", info.ExternalID)
+	assert.Equal(t, "john", info.Username)
+	assert.Equal(t, "John Doe", info.Fullname)
+	assert.Equal(t, "john@example.com", info.Email)
+	assert.Equal(t, []string{"developers"}, info.Groups)
+
+	// A wrong code verifier is refused by the provider
+	_, err = d.GetUserInfo(context.TODO(), sdk.AuthConsumerSigninRequest{"code": "my-code", "state": "another-state"})
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+
+	// The nonce of the id token must match the state
+	p.nonce = "wrong"
+	_, err = d.GetUserInfo(context.TODO(), req)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+}
+
+func TestUserInfoFromClaimsEmailVerified(t *testing.T) {
+	d := &authDriver{config: Config{UsernameClaim: "preferred_username"}}
+	claims := map[string]interface{}{
+		"sub":                "1234",
+		"preferred_username": "john",
+		"email":              "john@example.com",
+	}
+
+	// The email must be verified by the provider
+	_, err := d.userInfoFromClaims(claims)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+
+	claims["email_verified"] = false
+	_, err = d.userInfoFromClaims(claims)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+
+	claims["email_verified"] = "true"
+	_, err = d.userInfoFromClaims(claims)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+
+	claims["email_verified"] = true
+	info, err := d.userInfoFromClaims(claims)
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", info.Email)
+}
+
+func TestDriverDeviceCode(t *testing.T) {
+	initSigningKey(t)
+	p := newTestProvider(t)
+	defer p.Close()
+	p.claims = map[string]interface{}{
+		"preferred_username": "john",
+		"email":              "john@example.com",
+		"email_verified":     true,
+	}
+
+	d := newTestDriver(t, p)
+	code, err := d.GetDeviceCode(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD-EFGH", code.UserCode)
+	assert.Equal(t, 5, code.Interval)
+
+	req := sdk.AuthConsumerSigninRequest{"device_code": code.DeviceCode}
+	require.NoError(t, d.CheckSigninRequest(req))
+	require.NoError(t, d.CheckSigninStateToken(req))
+
+	_, err = d.GetUserInfo(context.TODO(), req)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrSigninPending))
+
+	p.devicePending = false
+	info, err := d.GetUserInfo(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "john", info.Username)
+	assert.Equal(t, []string{}, info.Groups)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	p := newTestProvider(t)
+	defer p.Close()
+	d := newTestDriver(t, p)
+
+	_, err := d.verifyIDToken(context.TODO(), p.idToken(t), "")
+	require.NoError(t, err)
+
+	// Token signed by an unknown key
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p.key = other
+	_, err = d.verifyIDToken(context.TODO(), p.idToken(t), "")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+
+	// Token for another client
+	d.config.ClientID = "another"
+	_, err = d.verifyIDToken(context.TODO(), p.idToken(t), "")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+}
@@ -17,6 +17,10 @@ func NewSession(ctx context.Context, db gorp.SqlExecutor, c *sdk.AuthConsumer, d
 		ConsumerID: c.ID,
 		ExpireAt:   time.Now().Add(duration),
 	}
+	// A session can't live longer than its consumer
+	if c.ExpireAt != nil && c.ExpireAt.Before(s.ExpireAt) {
+		s.ExpireAt = *c.ExpireAt
+	}
 
 	if err := InsertSession(ctx, db, &s); err != nil {
 		return nil, err
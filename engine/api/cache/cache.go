@@ -3,10 +3,13 @@ package cache
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
 	"time"
 
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -32,6 +35,9 @@ type Store interface {
 	Enqueue(queueName string, value interface{}) error
 	DequeueWithContext(c context.Context, queueName string, value interface{}) error
 	QueueLen(queueName string) (int, error)
+	IncrBy(key string, value int64) (int64, error)
+	ListPush(key string, value interface{}, maxLen int) error
+	ListRange(key string, values interface{}) error
 	RemoveFromQueue(queueName string, memberKey string) error
 	Publish(ctx context.Context, queueName string, value interface{}) error
 	Subscribe(queueName string) (PubSub, error)
@@ -45,9 +51,30 @@ type Store interface {
 	Unlock(key string) error
 }
 
-//New init a cache
-func New(redisHost, redisPassword string, TTL int) (Store, error) {
-	return NewRedisStore(redisHost, redisPassword, TTL)
+// Cache modes
+const (
+	ModeRedis = "redis"
+	ModeLocal = "local"
+)
+
+//New init a cache, the local mode keeps everything in memory and ignores redis settings
+//until the given context is done
+func New(ctx context.Context, mode, redisHost, redisPassword string, TTL int) (Store, error) {
+	switch mode {
+	case "", ModeRedis:
+		return NewRedisStore(redisHost, redisPassword, TTL)
+	case ModeLocal:
+		return NewLocalStore(ctx, TTL), nil
+	}
+	return nil, fmt.Errorf("invalid cache mode %q", mode)
+}
+
+// unmarshalList unmarshals the JSON values of a list in the slice pointed by values
+func unmarshalList(key string, list []string, values interface{}) error {
+	if err := json.Unmarshal([]byte("["+strings.Join(list, ",")+"]"), values); err != nil {
+		return sdk.WrapError(err, "cannot unmarshal list %s", key)
+	}
+	return nil
 }
 
 //NewWriteCloser returns a write closer
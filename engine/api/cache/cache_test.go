@@ -0,0 +1,228 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+type skipLogger struct {
+	t *testing.T
+}
+
+func (l skipLogger) Logf(format string, args ...interface{})   { l.t.Logf(format, args...) }
+func (l skipLogger) Errorf(format string, args ...interface{}) { l.t.Logf(format, args...) }
+func (l skipLogger) Fatalf(format string, args ...interface{}) { l.t.Skipf(format, args...) }
+
+func TestLocalStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	testStore(t, cache.NewLocalStore(ctx, 60))
+}
+
+func TestRedisStore(t *testing.T) {
+	cfg := test.LoadTestingConf(skipLogger{t})
+	store, err := cache.NewRedisStore(cfg["redisHost"], cfg["redisPassword"], 60)
+	if err != nil {
+		t.Skipf("Unable to connect to redis: %v", err)
+	}
+	defer store.Client.Close()
+	testStore(t, store)
+}
+
+type testItem struct {
+	Name  string
+	Value int
+}
+
+func testStore(t *testing.T, s cache.Store) {
+	prefix := cache.Key("test", sdk.RandomString(10))
+
+	t.Run("Get/Set/Delete", func(t *testing.T) {
+		k := cache.Key(prefix, "item")
+		var res testItem
+		found, err := s.Get(k, &res)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		require.NoError(t, s.Set(k, testItem{Name: "foo", Value: 1}))
+		found, err = s.Get(k, &res)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, testItem{Name: "foo", Value: 1}, res)
+
+		require.NoError(t, s.Delete(k))
+		found, err = s.Get(k, &res)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		k := cache.Key(prefix, "ttl")
+		require.NoError(t, s.SetWithDuration(k, "foo", 100*time.Millisecond))
+		var res string
+		found, _ := s.Get(k, &res)
+		assert.True(t, found)
+		time.Sleep(200 * time.Millisecond)
+		found, _ = s.Get(k, &res)
+		assert.False(t, found)
+
+		require.NoError(t, s.SetWithTTL(k, "foo", 0))
+		require.NoError(t, s.UpdateTTL(k, 1))
+		found, _ = s.Get(k, &res)
+		assert.True(t, found)
+		time.Sleep(1100 * time.Millisecond)
+		found, _ = s.Get(k, &res)
+		assert.False(t, found)
+	})
+
+	t.Run("DeleteAll", func(t *testing.T) {
+		require.NoError(t, s.Set(cache.Key(prefix, "all", "a"), "a"))
+		require.NoError(t, s.Set(cache.Key(prefix, "all", "b"), "b"))
+		require.NoError(t, s.Set(cache.Key(prefix, "other"), "c"))
+		require.NoError(t, s.DeleteAll(cache.Key(prefix, "all", "*")))
+
+		var res string
+		found, _ := s.Get(cache.Key(prefix, "all", "a"), &res)
+		assert.False(t, found)
+		found, _ = s.Get(cache.Key(prefix, "all", "b"), &res)
+		assert.False(t, found)
+		found, _ = s.Get(cache.Key(prefix, "other"), &res)
+		assert.True(t, found)
+	})
+
+	t.Run("Queue", func(t *testing.T) {
+		q := cache.Key(prefix, "queue")
+		require.NoError(t, s.Enqueue(q, testItem{Name: "first"}))
+		require.NoError(t, s.Enqueue(q, testItem{Name: "second"}))
+		require.NoError(t, s.Enqueue(q, testItem{Name: "third"}))
+		l, err := s.QueueLen(q)
+		require.NoError(t, err)
+		assert.Equal(t, 3, l)
+
+		btes, _ := json.Marshal(testItem{Name: "second"})
+		require.NoError(t, s.RemoveFromQueue(q, string(btes)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var res testItem
+		require.NoError(t, s.DequeueWithContext(ctx, q, &res))
+		assert.Equal(t, "first", res.Name)
+		require.NoError(t, s.DequeueWithContext(ctx, q, &res))
+		assert.Equal(t, "third", res.Name)
+		l, _ = s.QueueLen(q)
+		assert.Equal(t, 0, l)
+
+		// Dequeue blocks until an item is enqueued
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			s.Enqueue(q, testItem{Name: "late"}) // nolint
+		}()
+		res = testItem{}
+		require.NoError(t, s.DequeueWithContext(ctx, q, &res))
+		assert.Equal(t, "late", res.Name)
+
+		// Dequeue returns when the context is cancelled
+		ctxTimeout, cancelTimeout := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancelTimeout()
+		res = testItem{}
+		require.NoError(t, s.DequeueWithContext(ctxTimeout, q, &res))
+		assert.Equal(t, "", res.Name)
+	})
+
+	t.Run("IncrBy", func(t *testing.T) {
+		k := cache.Key(prefix, "counter")
+		n, err := s.IncrBy(k, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		n, err = s.IncrBy(k, 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), n)
+
+		var res int64
+		found, err := s.Get(k, &res)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, int64(6), res)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		k := cache.Key(prefix, "list")
+		var res []testItem
+		require.NoError(t, s.ListRange(k, &res))
+		assert.Empty(t, res)
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, s.ListPush(k, testItem{Value: i}, 3))
+		}
+		require.NoError(t, s.ListRange(k, &res))
+		assert.Equal(t, []testItem{{Value: 2}, {Value: 3}, {Value: 4}}, res)
+	})
+
+	t.Run("PubSub", func(t *testing.T) {
+		c := cache.Key(prefix, "channel")
+		ps, err := s.Subscribe(c)
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond) // wait for the subscription to be active
+
+		require.NoError(t, s.Publish(context.Background(), c, "hello"))
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		msg, err := s.GetMessageFromSubscription(ctx, ps)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", msg)
+
+		require.NoError(t, ps.Unsubscribe(c))
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		k := cache.Key(prefix, "set")
+		require.NoError(t, s.SetAdd(k, "a", testItem{Name: "a"}))
+		require.NoError(t, s.SetAdd(k, "b", testItem{Name: "b"}))
+		require.NoError(t, s.SetAdd(k, "c", testItem{Name: "c"}))
+		n, err := s.SetCard(k)
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+
+		require.NoError(t, s.SetRemove(k, "b", nil))
+		n, _ = s.SetCard(k)
+		assert.Equal(t, 2, n)
+
+		items := make([]*testItem, 2)
+		for i := range items {
+			items[i] = new(testItem)
+		}
+		require.NoError(t, s.SetScan(context.Background(), k, sdk.InterfaceSlice(items)...))
+		assert.Equal(t, "a", items[0].Name)
+		assert.Equal(t, "c", items[1].Name)
+
+		keys, err := s.ZScan(k, "a*")
+		require.NoError(t, err)
+		assert.Contains(t, keys, "a")
+		assert.NotContains(t, keys, "c")
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		k := cache.Key(prefix, "lock")
+		locked, err := s.Lock(k, time.Minute, 10, 1)
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		locked, _ = s.Lock(k, time.Minute, 10, 2)
+		assert.False(t, locked)
+
+		require.NoError(t, s.Unlock(k))
+		locked, err = s.Lock(k, time.Minute, 10, 1)
+		require.NoError(t, err)
+		assert.True(t, locked)
+		require.NoError(t, s.Unlock(k))
+	})
+}
@@ -0,0 +1,562 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+type localItem struct {
+	value  string
+	expire time.Time
+}
+
+func (i localItem) expired(now time.Time) bool {
+	return !i.expire.IsZero() && !now.Before(i.expire)
+}
+
+//LocalStore is an in memory implementation of Store, it behaves like RedisStore but can only be shared by goroutines
+//of a single process. It should be used for single instance installations and tests.
+//Expired items are evicted when read and periodically by a janitor goroutine that lives until the context of the store is done.
+type LocalStore struct {
+	ttl     int
+	mu      sync.Mutex
+	items   map[string]localItem
+	queues  map[string][]string
+	sets    map[string]map[string]float64
+	subs    map[string]map[*localPubSub]struct{}
+	changed chan struct{}
+}
+
+// localStoreJanitorInterval is the delay between two evictions of the expired items of a local store
+var localStoreJanitorInterval = time.Minute
+
+//NewLocalStore initiate a new in memory store, its janitor is stopped when the given context is done
+func NewLocalStore(ctx context.Context, ttl int) *LocalStore {
+	s := &LocalStore{
+		ttl:     ttl,
+		items:   map[string]localItem{},
+		queues:  map[string][]string{},
+		sets:    map[string]map[string]float64{},
+		subs:    map[string]map[*localPubSub]struct{}{},
+		changed: make(chan struct{}),
+	}
+	sdk.GoRoutine(ctx, "cache.LocalStore.janitor", s.janitor)
+	return s
+}
+
+// janitor evicts the expired items until the context is done
+func (s *LocalStore) janitor(ctx context.Context) {
+	tick := time.NewTicker(localStoreJanitorInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			s.evictExpired()
+		}
+	}
+}
+
+// evictExpired removes all the expired items
+func (s *LocalStore) evictExpired() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, i := range s.items {
+		if i.expired(now) {
+			delete(s.items, k)
+		}
+	}
+}
+
+// notify wakes up all goroutines waiting on a queue, lock must be held
+func (s *LocalStore) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// getItem returns a non expired item, lock must be held
+func (s *LocalStore) getItem(key string) (localItem, bool) {
+	i, has := s.items[key]
+	if !has {
+		return i, false
+	}
+	if i.expired(time.Now()) {
+		delete(s.items, key)
+		return i, false
+	}
+	return i, true
+}
+
+// setItem stores a value with given expiration (0 or less for eternity), lock must be held
+func (s *LocalStore) setItem(key, value string, duration time.Duration) {
+	i := localItem{value: value}
+	if duration > 0 {
+		i.expire = time.Now().Add(duration)
+	}
+	s.items[key] = i
+}
+
+// Get a key from local store
+func (s *LocalStore) Get(key string, value interface{}) (bool, error) {
+	s.mu.Lock()
+	i, has := s.getItem(key)
+	s.mu.Unlock()
+
+	if !has || i.value == "" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(i.value), value); err != nil {
+		return false, sdk.WrapError(err, "local> cannot get unmarshal %s", key)
+	}
+	return true, nil
+}
+
+// SetWithTTL a value in local store (0 for eternity)
+func (s *LocalStore) SetWithTTL(key string, value interface{}, ttl int) error {
+	return s.SetWithDuration(key, value, time.Duration(ttl)*time.Second)
+}
+
+// SetWithDuration a value in local store (0 for eternity)
+func (s *LocalStore) SetWithDuration(key string, value interface{}, duration time.Duration) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return sdk.WrapError(err, "local> error caching %s", key)
+	}
+
+	s.mu.Lock()
+	s.setItem(key, string(b), duration)
+	s.mu.Unlock()
+	return nil
+}
+
+// UpdateTTL update the ttl linked to the key, like redis the key is deleted if ttl is not positive
+func (s *LocalStore) UpdateTTL(key string, ttl int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, has := s.getItem(key)
+	if !has {
+		return nil
+	}
+	if ttl <= 0 {
+		delete(s.items, key)
+		return nil
+	}
+	i.expire = time.Now().Add(time.Duration(ttl) * time.Second)
+	s.items[key] = i
+	return nil
+}
+
+// Set a value in local store
+func (s *LocalStore) Set(key string, value interface{}) error {
+	return s.SetWithTTL(key, value, s.ttl)
+}
+
+// Delete a key in local store
+func (s *LocalStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.items, key)
+	delete(s.queues, key)
+	delete(s.sets, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// DeleteAll delete all mathing keys in local store, pattern uses the redis glob-style syntax
+func (s *LocalStore) DeleteAll(pattern string) error {
+	r, err := compileGlob(pattern)
+	if err != nil {
+		return sdk.WrapError(err, "local> Error deleting %s", pattern)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k := range s.items {
+		if r.MatchString(k) {
+			delete(s.items, k)
+		}
+	}
+	for k := range s.queues {
+		if r.MatchString(k) {
+			delete(s.queues, k)
+		}
+	}
+	for k := range s.sets {
+		if r.MatchString(k) {
+			delete(s.sets, k)
+		}
+	}
+	return nil
+}
+
+// Enqueue pushes to queue
+func (s *LocalStore) Enqueue(queueName string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return sdk.WrapError(err, "error queueing %s:%s", queueName, err)
+	}
+
+	s.mu.Lock()
+	s.queues[queueName] = append(s.queues[queueName], string(b))
+	s.notify()
+	s.mu.Unlock()
+	return nil
+}
+
+// IncrBy increments atomically the integer value of a key, a missing key is set to 0 before
+func (s *LocalStore) IncrBy(key string, value int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, has := s.getItem(key)
+	if !has {
+		i = localItem{}
+	}
+	var current int64
+	if i.value != "" {
+		var err error
+		current, err = strconv.ParseInt(i.value, 10, 64)
+		if err != nil {
+			return 0, sdk.WrapError(err, "local> value of %s is not an integer", key)
+		}
+	}
+	current += value
+	i.value = strconv.FormatInt(current, 10)
+	s.items[key] = i
+	return current, nil
+}
+
+// ListPush appends a value to a list and keeps only its last maxLen values
+func (s *LocalStore) ListPush(key string, value interface{}, maxLen int) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return sdk.WrapError(err, "local> cannot marshal value for %s", key)
+	}
+
+	s.mu.Lock()
+	l := append(s.queues[key], string(b))
+	if len(l) > maxLen {
+		l = append([]string(nil), l[len(l)-maxLen:]...)
+	}
+	s.queues[key] = l
+	s.mu.Unlock()
+	return nil
+}
+
+// ListRange unmarshals all the values of a list, from the oldest to the newest, in the slice pointed by values
+func (s *LocalStore) ListRange(key string, values interface{}) error {
+	s.mu.Lock()
+	l := append([]string(nil), s.queues[key]...)
+	s.mu.Unlock()
+	return unmarshalList(key, l, values)
+}
+
+// QueueLen returns the length of a queue
+func (s *LocalStore) QueueLen(queueName string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queues[queueName]), nil
+}
+
+// DequeueWithContext gets from queue This is blocking while there is nothing in the queue, it can be cancelled with a context.Context
+func (s *LocalStore) DequeueWithContext(c context.Context, queueName string, value interface{}) error {
+	var elem string
+	for {
+		s.mu.Lock()
+		q := s.queues[queueName]
+		if len(q) > 0 {
+			elem = q[0]
+			if len(q) == 1 {
+				delete(s.queues, queueName)
+			} else {
+				s.queues[queueName] = q[1:]
+			}
+			s.mu.Unlock()
+			break
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-c.Done():
+			return nil
+		}
+	}
+
+	if err := json.Unmarshal([]byte(elem), value); err != nil {
+		return sdk.WrapError(err, "local.DequeueWithContext> error on unmarshal value on queue:%s", queueName)
+	}
+	return nil
+}
+
+// RemoveFromQueue removes a member from a list
+func (s *LocalStore) RemoveFromQueue(rootKey string, memberKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.queues[rootKey]
+	res := q[:0]
+	for _, e := range q {
+		if e != memberKey {
+			res = append(res, e)
+		}
+	}
+	if len(res) == 0 {
+		delete(s.queues, rootKey)
+	} else {
+		s.queues[rootKey] = res
+	}
+	return nil
+}
+
+type localPubSub struct {
+	store    *LocalStore
+	channels []string
+	msgs     chan string
+}
+
+// Unsubscribe implements PubSub, without channels it unsubscribes from all the channels
+func (p *localPubSub) Unsubscribe(channels ...string) error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	if len(channels) == 0 {
+		channels = p.channels
+	}
+	for _, c := range channels {
+		delete(p.store.subs[c], p)
+		if len(p.store.subs[c]) == 0 {
+			delete(p.store.subs, c)
+		}
+	}
+	return nil
+}
+
+// Publish a msg in a channel
+func (s *LocalStore) Publish(ctx context.Context, channel string, value interface{}) error {
+	msg, err := json.Marshal(value)
+	if err != nil {
+		return sdk.WrapError(err, "local.Publish> Marshall error, cannot push in channel %s", channel)
+	}
+
+	iUnquoted, err := strconv.Unquote(string(msg))
+	if err != nil {
+		return sdk.WrapError(err, "local.Publish> Unquote error, cannot push in channel %s", channel)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for p := range s.subs[channel] {
+		select {
+		case p.msgs <- iUnquoted:
+		default:
+			log.Warning(ctx, "local.Publish> subscriber buffer is full, message dropped in channel %s", channel)
+		}
+	}
+	return nil
+}
+
+// Subscribe to a channel
+func (s *LocalStore) Subscribe(channel string) (PubSub, error) {
+	p := &localPubSub{
+		store:    s,
+		channels: []string{channel},
+		msgs:     make(chan string, 1000),
+	}
+
+	s.mu.Lock()
+	if _, has := s.subs[channel]; !has {
+		s.subs[channel] = map[*localPubSub]struct{}{}
+	}
+	s.subs[channel][p] = struct{}{}
+	s.mu.Unlock()
+	return p, nil
+}
+
+// GetMessageFromSubscription from a local PubSub
+func (s *LocalStore) GetMessageFromSubscription(c context.Context, pb PubSub) (string, error) {
+	p, ok := pb.(*localPubSub)
+	if !ok {
+		return "", fmt.Errorf("local.GetMessage> PubSub is not a local PubSub. Got %T", pb)
+	}
+
+	select {
+	case msg := <-p.msgs:
+		return msg, nil
+	case <-c.Done():
+		return "", nil
+	}
+}
+
+// SetAdd add a member (identified by a key) in the cached set
+func (s *LocalStore) SetAdd(rootKey string, memberKey string, member interface{}) error {
+	s.mu.Lock()
+	if _, has := s.sets[rootKey]; !has {
+		s.sets[rootKey] = map[string]float64{}
+	}
+	s.sets[rootKey][memberKey] = float64(time.Now().UnixNano())
+	s.mu.Unlock()
+	return s.SetWithTTL(Key(rootKey, memberKey), member, -1)
+}
+
+// SetRemove removes a member from a set
+func (s *LocalStore) SetRemove(rootKey string, memberKey string, member interface{}) error {
+	s.mu.Lock()
+	s.removeSetMember(rootKey, memberKey)
+	s.mu.Unlock()
+	return s.Delete(Key(rootKey, memberKey))
+}
+
+// removeSetMember removes a member from a set, lock must be held
+func (s *LocalStore) removeSetMember(rootKey string, memberKey string) {
+	delete(s.sets[rootKey], memberKey)
+	if len(s.sets[rootKey]) == 0 {
+		delete(s.sets, rootKey)
+	}
+}
+
+// SetCard returns the cardinality of a set
+func (s *LocalStore) SetCard(key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sets[key]), nil
+}
+
+// sortedSetMembers returns the members of a set sorted by score, lock must be held
+func (s *LocalStore) sortedSetMembers(key string) []string {
+	set := s.sets[key]
+	members := make([]string, 0, len(set))
+	for m := range set {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if set[members[i]] == set[members[j]] {
+			return members[i] < members[j]
+		}
+		return set[members[i]] < set[members[j]]
+	})
+	return members
+}
+
+// SetScan scans a set
+func (s *LocalStore) SetScan(ctx context.Context, key string, members ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := s.sortedSetMembers(key)
+	for i := range members {
+		if i >= len(values) {
+			break
+		}
+
+		k := Key(key, values[i])
+		item, has := s.getItem(k)
+		if !has {
+			//If the member is not found, return an error because the members are inconsistents
+			// but try to delete the member from the set
+			log.Error(ctx, "local>SetScan member %s not found", k)
+			s.removeSetMember(key, values[i])
+			log.Info(ctx, "local> member %s deleted", k)
+			return sdk.WithStack(fmt.Errorf("SetScan member %s not found", k))
+		}
+
+		if err := json.Unmarshal([]byte(item.value), members[i]); err != nil {
+			return sdk.WrapError(err, "local> cannot unmarshal %s", k)
+		}
+	}
+	return nil
+}
+
+// ZScan returns the members of a set matching the pattern, like redis each member is followed by its score
+func (s *LocalStore) ZScan(key, pattern string) ([]string, error) {
+	r, err := compileGlob(pattern)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []string
+	for _, m := range s.sortedSetMembers(key) {
+		if r.MatchString(m) {
+			res = append(res, m, strconv.FormatFloat(s.sets[key][m], 'g', -1, 64))
+		}
+	}
+	return res, nil
+}
+
+// Lock sets the key if it doesn't exist, retrying retryCount times
+func (s *LocalStore) Lock(key string, expiration time.Duration, retrywdMillisecond int, retryCount int) (bool, error) {
+	if retrywdMillisecond == -1 {
+		retrywdMillisecond = 30
+	}
+	if retryCount == -1 {
+		retryCount = 3
+	}
+	for i := 0; i < retryCount; i++ {
+		s.mu.Lock()
+		if _, has := s.getItem(key); !has {
+			s.setItem(key, "true", expiration)
+			s.mu.Unlock()
+			return true, nil
+		}
+		s.mu.Unlock()
+		time.Sleep(time.Duration(retrywdMillisecond) * time.Millisecond)
+	}
+	return false, nil
+}
+
+// Unlock deletes a key from cache
+func (s *LocalStore) Unlock(key string) error {
+	return s.Delete(key)
+}
+
+// compileGlob converts a redis glob-style pattern to a regexp
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + regexp.QuoteMeta(class[1:])
+			} else {
+				class = regexp.QuoteMeta(class)
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStoreJanitor(t *testing.T) {
+	interval := localStoreJanitorInterval
+	localStoreJanitorInterval = 10 * time.Millisecond
+	defer func() { localStoreJanitorInterval = interval }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewLocalStore(ctx, 60)
+
+	require.NoError(t, s.SetWithDuration("expired", "value", time.Millisecond))
+	require.NoError(t, s.SetWithTTL("eternal", "value", 0))
+	require.NoError(t, s.SetWithTTL("valid", "value", 60))
+
+	// Expired items are evicted without being read
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, has := s.items["expired"]
+		return !has
+	}, time.Second, 10*time.Millisecond)
+	s.mu.Lock()
+	assert.Len(t, s.items, 2)
+	s.mu.Unlock()
+
+	// Stopped janitor does not evict anymore
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, s.SetWithDuration("expired", "value", time.Millisecond))
+	time.Sleep(50 * time.Millisecond)
+	s.mu.Lock()
+	assert.Len(t, s.items, 3)
+	s.mu.Unlock()
+}
@@ -188,6 +188,48 @@ func (s *RedisStore) Enqueue(queueName string, value interface{}) error {
 	return nil
 }
 
+// IncrBy increments atomically the integer value of a key, a missing key is set to 0 before
+func (s *RedisStore) IncrBy(key string, value int64) (int64, error) {
+	if s.Client == nil {
+		return 0, sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+	res, err := s.Client.IncrBy(key, value).Result()
+	if err != nil {
+		return 0, sdk.WrapError(err, "redis> cannot increment %s", key)
+	}
+	return res, nil
+}
+
+// ListPush appends a value to a list and keeps only its last maxLen values
+func (s *RedisStore) ListPush(key string, value interface{}, maxLen int) error {
+	if s.Client == nil {
+		return sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return sdk.WrapError(err, "redis> cannot marshal value for %s", key)
+	}
+	pipe := s.Client.TxPipeline()
+	pipe.RPush(key, string(b))
+	pipe.LTrim(key, int64(-maxLen), -1)
+	if _, err := pipe.Exec(); err != nil {
+		return sdk.WrapError(err, "redis> cannot push to %s", key)
+	}
+	return nil
+}
+
+// ListRange unmarshals all the values of a list, from the oldest to the newest, in the slice pointed by values
+func (s *RedisStore) ListRange(key string, values interface{}) error {
+	if s.Client == nil {
+		return sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+	res, err := s.Client.LRange(key, 0, -1).Result()
+	if err != nil {
+		return sdk.WrapError(err, "redis> cannot read %s", key)
+	}
+	return unmarshalList(key, res, values)
+}
+
 // QueueLen returns the length of a queue
 func (s *RedisStore) QueueLen(queueName string) (int, error) {
 	if s.Client == nil {
@@ -204,6 +204,20 @@ func GetAllVariableByID(db gorp.SqlExecutor, environmentID int64, args ...GetAll
 	return variables, err
 }
 
+// checkExternalSecret checks that an external secret reference is in the namespace of the project of the environment
+func checkExternalSecret(db gorp.SqlExecutor, envID int64, reference string) error {
+	projectKey, err := db.SelectStr(`
+		SELECT project.projectkey
+		FROM environment
+		JOIN project ON project.id = environment.project_id
+		WHERE environment.id = $1`, envID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project of environment %d", envID)
+	}
+	_, err = secret.CheckReference(projectKey, reference)
+	return err
+}
+
 // InsertVariable Insert a new variable in the given environment
 func InsertVariable(db gorp.SqlExecutor, environmentID int64, variable *sdk.Variable, u sdk.Identifiable) error {
 	query := `INSERT INTO environment_variable(environment_id, name, value, cipher_value, type)
@@ -214,6 +228,12 @@ func InsertVariable(db gorp.SqlExecutor, environmentID int64, variable *sdk.Vari
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid variable name. It should match %s", sdk.NamePattern))
 	}
 
+	if variable.Type == sdk.ExternalSecretVariable {
+		if err := checkExternalSecret(db, environmentID, variable.Value); err != nil {
+			return err
+		}
+	}
+
 	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot encrypt secret %s", variable.Name)
@@ -250,6 +270,12 @@ func UpdateVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, va
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid variable name. It should match %s", sdk.NamePattern))
 	}
 
+	if variable.Type == sdk.ExternalSecretVariable {
+		if err := checkExternalSecret(db, envID, variable.Value); err != nil {
+			return err
+		}
+	}
+
 	// If we are updating a batch of variables, some of them might be secrets, we don't want to crush the value
 	if sdk.NeedPlaceholder(variable.Type) && variable.Value == sdk.PasswordPlaceholder {
 		varValue = varBefore.Value
@@ -0,0 +1,117 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// AMQPClient publishes events on a RabbitMQ exchange
+type AMQPClient struct {
+	options AMQPConfig
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+// AMQPConfig handles all config to connect to RabbitMQ
+type AMQPConfig struct {
+	URI        string
+	User       string
+	Password   string
+	Exchange   string
+	RoutingKey string
+}
+
+// initialize returns broker, isInit and err if
+func (c *AMQPClient) initialize(ctx context.Context, options interface{}) (Broker, error) {
+	conf, ok := options.(AMQPConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid AMQP Initialization")
+	}
+	if conf.URI == "" || (conf.Exchange == "" && conf.RoutingKey == "") {
+		return nil, fmt.Errorf("initAMQP> Invalid AMQP Configuration")
+	}
+	c.options = conf
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// connect opens the connection and the channel, lock must be held
+func (c *AMQPClient) connect() error {
+	uri := fmt.Sprintf("amqp://%s:%s@%s", c.options.User, c.options.Password, c.options.URI)
+	conn, err := amqp.Dial(uri)
+	if err != nil {
+		return fmt.Errorf("initAMQP> Error with dial on %s user:%s: %v", c.options.URI, c.options.User, err)
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close() // nolint
+		return fmt.Errorf("initAMQP> Error with channel on %s user:%s: %v", c.options.URI, c.options.User, err)
+	}
+
+	log.Debug("initAMQP> RabbitMQ used at %s on exchange:%s", c.options.URI, c.options.Exchange)
+	c.conn = conn
+	c.channel = channel
+	return nil
+}
+
+// close closes the connection
+func (c *AMQPClient) close(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		if err := c.conn.Close(); err != nil {
+			log.Warning(ctx, "closeAMQP> Error while closing connection: %v", err)
+		}
+		c.conn = nil
+	}
+}
+
+// sendEvent publishes the event, the connection is opened again once if it was closed
+func (c *AMQPClient) sendEvent(event *sdk.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Type:         event.EventType,
+		Body:         data,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+	if err := c.channel.Publish(c.options.Exchange, c.options.RoutingKey, false, false, msg); err != nil {
+		if err == amqp.ErrClosed {
+			if err := c.connect(); err != nil {
+				return err
+			}
+			return c.channel.Publish(c.options.Exchange, c.options.RoutingKey, false, false, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// status: here, if c is initialized, RabbitMQ is ok
+func (c *AMQPClient) status() string {
+	return "RabbitMQ OK"
+}
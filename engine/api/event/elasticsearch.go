@@ -68,3 +68,38 @@ func GetEvents(ctx context.Context, db gorp.SqlExecutor, store cache.Store, filt
 	}
 	return events, nil
 }
+
+// GetEventsHistory retrieves the persisted events of a project from elasticsearch, sorted by date. It also returns
+// the value of SearchAfter to get the next page, or nil if there is no more events.
+func GetEventsHistory(ctx context.Context, db gorp.SqlExecutor, filter sdk.EventHistoryFilter) ([]sdk.Event, []interface{}, error) {
+	srvs, err := services.LoadAllByType(ctx, db, services.TypeElasticsearch)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "Unable to get elasticsearch service")
+	}
+	if len(srvs) == 0 {
+		return nil, nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no elasticsearch service found, events are not persisted")
+	}
+
+	var esEvents []elastic.SearchHit
+	if _, _, err := services.DoJSONRequest(ctx, db, srvs, "GET", "/events/history", filter, &esEvents); err != nil {
+		return nil, nil, sdk.WrapError(err, "Unable to get events")
+	}
+
+	events := make([]sdk.Event, 0, len(esEvents))
+	for _, h := range esEvents {
+		if h.Source == nil {
+			continue
+		}
+		var e sdk.Event
+		if err := json.Unmarshal(*h.Source, &e); err != nil {
+			return nil, nil, sdk.WrapError(err, "Unable to read event %s", h.Id)
+		}
+		events = append(events, e)
+	}
+
+	var next []interface{}
+	if filter.Size > 0 && len(esEvents) == filter.Size {
+		next = esEvents[len(esEvents)-1].Sort
+	}
+	return events, next, nil
+}
@@ -28,6 +28,11 @@ var subscribers []chan<- sdk.Event
 
 func init() {
 	subscribers = make([]chan<- sdk.Event, 0)
+	brokersConnectionCache.OnEvicted(func(_ string, b interface{}) {
+		if broker, ok := b.(Broker); ok {
+			broker.close(context.Background())
+		}
+	})
 }
 
 // Broker event typed
@@ -43,6 +48,12 @@ func getBroker(ctx context.Context, t string, option interface{}) (Broker, error
 	case "kafka":
 		k := &KafkaClient{}
 		return k.initialize(ctx, option)
+	case "webhook":
+		w := &WebhookClient{}
+		return w.initialize(ctx, option)
+	case "amqp":
+		a := &AMQPClient{}
+		return a.initialize(ctx, option)
 	}
 	return nil, fmt.Errorf("Invalid Broker Type %s", t)
 }
@@ -56,21 +67,13 @@ func ResetPublicIntegrations(ctx context.Context, db *gorp.DbMap) error {
 
 	for _, integration := range integrations {
 		for _, cfg := range integration.PublicConfigurations {
-			kafkaCfg := KafkaConfig{
-				Enabled:         true,
-				BrokerAddresses: cfg["broker url"].Value,
-				User:            cfg["username"].Value,
-				Password:        cfg["password"].Value,
-				Topic:           cfg["topic"].Value,
-				MaxMessageByte:  10000000,
-			}
-
-			kafkaBroker, errk := getBroker(ctx, "kafka", kafkaCfg)
-			if errk != nil {
-				return sdk.WrapError(errk, "cannot get broker for %s and user %s", cfg["broker url"].Value, cfg["username"].Value)
+			t, options := brokerOptions(integration.Name, cfg)
+			broker, err := getBroker(ctx, t, options)
+			if err != nil {
+				return sdk.WrapError(err, "cannot get %s broker for public integration %s", t, integration.Name)
 			}
 
-			publicBrokersConnectionCache = append(publicBrokersConnectionCache, kafkaBroker)
+			publicBrokersConnectionCache = append(publicBrokersConnectionCache, &filteredBroker{Broker: broker, filter: sdk.NewEventIntegrationFilter(cfg)})
 		}
 	}
 
@@ -81,6 +84,11 @@ func ResetPublicIntegrations(ctx context.Context, db *gorp.DbMap) error {
 func DeleteEventIntegration(eventIntegrationID int64) {
 	brokerConnectionKey := strconv.FormatInt(eventIntegrationID, 10)
 	brokersConnectionCache.Delete(brokerConnectionKey)
+	if store != nil {
+		if err := store.DeleteAll(integrationStatusKey(eventIntegrationID, "*")); err != nil {
+			log.Error(context.Background(), "DeleteEventIntegration> cannot delete status of integration %d: %v", eventIntegrationID, err)
+		}
+	}
 }
 
 // ResetEventIntegration reset event integration in order to kill existing connection and add/check the new one
@@ -92,20 +100,13 @@ func ResetEventIntegration(ctx context.Context, db gorp.SqlExecutor, eventIntegr
 		return fmt.Errorf("cannot load project integration id %d and type event: %v", eventIntegrationID, err)
 	}
 
-	kafkaCfg := KafkaConfig{
-		Enabled:         true,
-		BrokerAddresses: projInt.Config["broker url"].Value,
-		User:            projInt.Config["username"].Value,
-		Password:        projInt.Config["password"].Value,
-		Topic:           projInt.Config["topic"].Value,
-		MaxMessageByte:  10000000,
-	}
-	kafkaBroker, errk := getBroker(ctx, "kafka", kafkaCfg)
-	if errk != nil {
-		return sdk.WrapError(sdk.ErrBadBrokerConfiguration, "cannot get broker for %s and user %s : %v", projInt.Config["broker url"].Value, projInt.Config["username"].Value, errk)
+	broker, err := newIntegrationBroker(ctx, projInt.ID, projInt.Model.Name, projInt.Config)
+	if err != nil {
+		return sdk.WrapError(sdk.ErrBadBrokerConfiguration, "cannot get broker for integration %s: %v", projInt.Name, err)
 	}
-	if err := brokersConnectionCache.Add(brokerConnectionKey, kafkaBroker, gocache.DefaultExpiration); err != nil {
-		return sdk.WrapError(sdk.ErrBadBrokerConfiguration, "cannot add broker in cache for %s and user %s : %v", projInt.Config["broker url"].Value, projInt.Config["username"].Value, err)
+	if err := brokersConnectionCache.Add(brokerConnectionKey, broker, gocache.DefaultExpiration); err != nil {
+		broker.close(ctx)
+		return sdk.WrapError(sdk.ErrBadBrokerConfiguration, "cannot add broker in cache for integration %s: %v", projInt.Name, err)
 	}
 	return nil
 }
@@ -174,24 +175,17 @@ func DequeueEvent(ctx context.Context, db *gorp.DbMap) {
 					continue
 				}
 
-				kafkaCfg := KafkaConfig{
-					Enabled:         true,
-					BrokerAddresses: projInt.Config["broker url"].Value,
-					User:            projInt.Config["username"].Value,
-					Password:        projInt.Config["password"].Value,
-					Topic:           projInt.Config["topic"].Value,
-					MaxMessageByte:  10000000,
-				}
-				kafkaBroker, errk := getBroker(ctx, "kafka", kafkaCfg)
-				if errk != nil {
-					log.Error(ctx, "Event.DequeueEvent> cannot get broker for %s and user %s : %v", projInt.Config["broker url"].Value, projInt.Config["username"].Value, errk)
+				broker, err := newIntegrationBroker(ctx, projInt.ID, projInt.Model.Name, projInt.Config)
+				if err != nil {
+					log.Error(ctx, "Event.DequeueEvent> cannot get broker for integration %s: %v", projInt.Name, err)
 					continue
 				}
-				if err := brokersConnectionCache.Add(brokerConnectionKey, kafkaBroker, gocache.DefaultExpiration); err != nil {
-					log.Error(ctx, "Event.DequeueEvent> cannot add broker in cache for %s and user %s : %v", projInt.Config["broker url"].Value, projInt.Config["username"].Value, err)
+				if err := brokersConnectionCache.Add(brokerConnectionKey, broker, gocache.DefaultExpiration); err != nil {
+					log.Error(ctx, "Event.DequeueEvent> cannot add broker in cache for integration %s: %v", projInt.Name, err)
+					broker.close(ctx)
 					continue
 				}
-				brokerConnection = kafkaBroker
+				brokerConnection = broker
 			}
 
 			broker, ok := brokerConnection.(Broker)
@@ -0,0 +1,275 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	integrationBrokerQueueSize = 1000
+	maxDeadLetters             = 100
+)
+
+var errDeliveryQueueFull = fmt.Errorf("delivery queue is full")
+
+// brokerOptions returns the broker type and options for an event integration configuration.
+// Event integrations that are not builtin webhook or RabbitMQ integrations are Kafka integrations.
+func brokerOptions(modelName string, cfg sdk.IntegrationConfig) (string, interface{}) {
+	switch modelName {
+	case sdk.WebhookIntegrationModel:
+		maxRetries, err := strconv.Atoi(cfg["max retries"].Value)
+		if err != nil || maxRetries < 0 {
+			maxRetries = 3
+		}
+		return "webhook", WebhookConfig{
+			URL:        cfg["url"].Value,
+			Secret:     cfg["secret"].Value,
+			MaxRetries: maxRetries,
+		}
+	case sdk.RabbitMQIntegrationModel:
+		return "amqp", AMQPConfig{
+			URI:        cfg["uri"].Value,
+			User:       cfg["username"].Value,
+			Password:   cfg["password"].Value,
+			Exchange:   cfg["exchange"].Value,
+			RoutingKey: cfg["routing key"].Value,
+		}
+	}
+	return "kafka", KafkaConfig{
+		Enabled:         true,
+		BrokerAddresses: cfg["broker url"].Value,
+		User:            cfg["username"].Value,
+		Password:        cfg["password"].Value,
+		Topic:           cfg["topic"].Value,
+		MaxMessageByte:  10000000,
+	}
+}
+
+// filteredBroker sends only the events selected by the filter
+type filteredBroker struct {
+	Broker
+	filter sdk.EventIntegrationFilter
+}
+
+func (b *filteredBroker) sendEvent(e *sdk.Event) error {
+	if !b.filter.Match(*e) {
+		return nil
+	}
+	return b.Broker.sendEvent(e)
+}
+
+// integrationBroker sends the events selected by the filter of a project integration.
+// Events are sent asynchronously in order, the delivery status is stored in cache.
+type integrationBroker struct {
+	Broker
+	integrationID int64
+	filter        sdk.EventIntegrationFilter
+	events        chan sdk.Event
+	// rejected are the events dropped because the delivery queue is full, they are recorded as dead letters by the
+	// delivery goroutine. When there are too many of them, they are only counted in lost.
+	rejected chan sdk.Event
+	lost     int64
+	cancel   func()
+}
+
+func newIntegrationBroker(ctx context.Context, integrationID int64, modelName string, cfg sdk.IntegrationConfig) (Broker, error) {
+	t, options := brokerOptions(modelName, cfg)
+	b, err := getBroker(ctx, t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	ib := &integrationBroker{
+		Broker:        b,
+		integrationID: integrationID,
+		filter:        sdk.NewEventIntegrationFilter(cfg),
+		events:        make(chan sdk.Event, integrationBrokerQueueSize),
+		rejected:      make(chan sdk.Event, maxDeadLetters),
+	}
+	var deliveryCtx context.Context
+	deliveryCtx, ib.cancel = context.WithCancel(context.Background())
+	go ib.deliver(deliveryCtx)
+	return ib, nil
+}
+
+// sendEvent never blocks, the events are dropped when the delivery queue is full
+func (b *integrationBroker) sendEvent(e *sdk.Event) error {
+	if !b.filter.Match(*e) {
+		return nil
+	}
+	select {
+	case b.events <- *e:
+		return nil
+	default:
+	}
+	select {
+	case b.rejected <- *e:
+	default:
+		atomic.AddInt64(&b.lost, 1)
+	}
+	return errDeliveryQueueFull
+}
+
+func (b *integrationBroker) deliver(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-b.rejected:
+			recordDelivery(ctx, b.integrationID, &e, errDeliveryQueueFull)
+		case e := <-b.events:
+			err := b.Broker.sendEvent(&e)
+			if err != nil {
+				log.Warning(ctx, "Error while sending message [%s: %s/%s/%s/%s/%s] to integration %d: %s", e.EventType, e.ProjectKey, e.WorkflowName, e.ApplicationName, e.PipelineName, e.EnvironmentName, b.integrationID, err)
+			}
+			recordDelivery(ctx, b.integrationID, &e, err)
+		}
+		if n := atomic.SwapInt64(&b.lost, 0); n > 0 {
+			recordLostEvents(ctx, b.integrationID, n)
+		}
+	}
+}
+
+func (b *integrationBroker) close(ctx context.Context) {
+	b.cancel()
+	b.Broker.close(ctx)
+}
+
+func integrationStatusKey(integrationID int64, field string) string {
+	return cache.Key("events", "integrations", strconv.FormatInt(integrationID, 10), "status", field)
+}
+
+// integrationError is the last delivery error of an event integration
+type integrationError struct {
+	Error string    `json:"error"`
+	Date  time.Time `json:"date"`
+}
+
+// recordDelivery updates the delivery status of an event integration, failed events are kept as dead letters.
+// Deliveries are recorded concurrently by several API instances, so counters are incremented atomically and
+// dead letters are pushed to a capped list.
+func recordDelivery(ctx context.Context, integrationID int64, e *sdk.Event, deliveryErr error) {
+	if store == nil {
+		return
+	}
+
+	now := time.Now()
+	if deliveryErr == nil {
+		if _, err := store.IncrBy(integrationStatusKey(integrationID, "sent"), 1); err != nil {
+			log.Error(ctx, "recordDelivery> cannot update status of integration %d: %v", integrationID, err)
+		}
+		if err := store.SetWithTTL(integrationStatusKey(integrationID, "last_delivery"), now, 0); err != nil {
+			log.Error(ctx, "recordDelivery> cannot update status of integration %d: %v", integrationID, err)
+		}
+		return
+	}
+
+	if _, err := store.IncrBy(integrationStatusKey(integrationID, "failed"), 1); err != nil {
+		log.Error(ctx, "recordDelivery> cannot update status of integration %d: %v", integrationID, err)
+	}
+	if err := store.SetWithTTL(integrationStatusKey(integrationID, "last_error"), integrationError{Error: deliveryErr.Error(), Date: now}, 0); err != nil {
+		log.Error(ctx, "recordDelivery> cannot update status of integration %d: %v", integrationID, err)
+	}
+	deadLetter := sdk.EventDeadLetter{Event: *e, Error: deliveryErr.Error(), Date: now}
+	if err := store.ListPush(integrationStatusKey(integrationID, "dead_letters"), deadLetter, maxDeadLetters); err != nil {
+		log.Error(ctx, "recordDelivery> cannot add dead letter of integration %d: %v", integrationID, err)
+	}
+}
+
+// recordLostEvents counts as failed the events dropped without being kept as dead letters
+func recordLostEvents(ctx context.Context, integrationID int64, n int64) {
+	if store == nil {
+		return
+	}
+	log.Warning(ctx, "recordLostEvents> %d events dropped for integration %d, the delivery queue is full", n, integrationID)
+	if _, err := store.IncrBy(integrationStatusKey(integrationID, "failed"), n); err != nil {
+		log.Error(ctx, "recordLostEvents> cannot update status of integration %d: %v", integrationID, err)
+	}
+}
+
+// GetEventIntegrationStatus returns the delivery status of an event integration
+func GetEventIntegrationStatus(integrationID int64) (sdk.EventIntegrationStatus, error) {
+	status := sdk.EventIntegrationStatus{IntegrationID: integrationID}
+	if store == nil {
+		return status, nil
+	}
+	if _, err := store.Get(integrationStatusKey(integrationID, "sent"), &status.Sent); err != nil {
+		return status, sdk.WrapError(err, "cannot get status of integration %d", integrationID)
+	}
+	if _, err := store.Get(integrationStatusKey(integrationID, "failed"), &status.Failed); err != nil {
+		return status, sdk.WrapError(err, "cannot get status of integration %d", integrationID)
+	}
+	var lastDelivery time.Time
+	if found, err := store.Get(integrationStatusKey(integrationID, "last_delivery"), &lastDelivery); err != nil {
+		return status, sdk.WrapError(err, "cannot get status of integration %d", integrationID)
+	} else if found {
+		status.LastDelivery = &lastDelivery
+	}
+	var lastError integrationError
+	if found, err := store.Get(integrationStatusKey(integrationID, "last_error"), &lastError); err != nil {
+		return status, sdk.WrapError(err, "cannot get status of integration %d", integrationID)
+	} else if found {
+		status.LastError = lastError.Error
+		status.LastErrorDate = &lastError.Date
+	}
+	if err := store.ListRange(integrationStatusKey(integrationID, "dead_letters"), &status.DeadLetters); err != nil {
+		return status, sdk.WrapError(err, "cannot get dead letters of integration %d", integrationID)
+	}
+	return status, nil
+}
+
+// ReplayEvents sends again to an event integration the events of its project persisted in elasticsearch since the given date.
+// Events are sent in order and synchronously, it returns the number of sent events.
+func ReplayEvents(ctx context.Context, db gorp.SqlExecutor, projInt sdk.ProjectIntegration, projectKey string, req sdk.EventReplayRequest) (int, error) {
+	t, options := brokerOptions(projInt.Model.Name, projInt.Config)
+	b, err := getBroker(ctx, t, options)
+	if err != nil {
+		return 0, sdk.WrapError(sdk.ErrBadBrokerConfiguration, "cannot get broker for integration %s: %v", projInt.Name, err)
+	}
+	defer b.close(ctx)
+
+	filter := sdk.NewEventIntegrationFilter(projInt.Config)
+	history := sdk.EventHistoryFilter{
+		ProjectKey: projectKey,
+		From:       req.From,
+		To:         req.To,
+		Size:       100,
+	}
+
+	var sent int
+	for {
+		if err := ctx.Err(); err != nil {
+			return sent, sdk.WithStack(err)
+		}
+		events, next, err := GetEventsHistory(ctx, db, history)
+		if err != nil {
+			return sent, err
+		}
+		for i := range events {
+			e := &events[i]
+			// like live events, only the events of workflows using the integration are sent
+			if !filter.Match(*e) || !sdk.IsInInt64Array(projInt.ID, e.EventIntegrationsID) {
+				continue
+			}
+			err := b.sendEvent(e)
+			recordDelivery(ctx, projInt.ID, e, err)
+			if err != nil {
+				return sent, sdk.WrapError(err, "cannot replay event %s of %s", e.EventType, e.Timestamp)
+			}
+			sent++
+		}
+		if len(next) == 0 {
+			return sent, nil
+		}
+		history.SearchAfter = next
+	}
+}
@@ -0,0 +1,191 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWebhookIntegrationBroker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store = cache.NewLocalStore(ctx, 60)
+	defer func() { store = nil }()
+
+	var calls int
+	received := make(chan sdk.Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, WebhookSignature("my-secret", body), r.Header.Get(WebhookHeaderSignature))
+		assert.Equal(t, "sdk.EventRunWorkflow", r.Header.Get(WebhookHeaderEventType))
+		var e sdk.Event
+		assert.NoError(t, json.Unmarshal(body, &e))
+		received <- e
+	}))
+	defer srv.Close()
+
+	b, err := newIntegrationBroker(context.TODO(), 42, sdk.WebhookIntegrationModel, sdk.IntegrationConfig{
+		"url":                           sdk.IntegrationConfigValue{Value: srv.URL},
+		"secret":                        sdk.IntegrationConfigValue{Value: "my-secret"},
+		"max retries":                   sdk.IntegrationConfigValue{Value: "1"},
+		sdk.IntegrationConfigEventTypes: sdk.IntegrationConfigValue{Value: "EventRunWorkflow"},
+	})
+	require.NoError(t, err)
+	defer b.close(context.TODO())
+
+	require.NoError(t, b.sendEvent(&sdk.Event{EventType: "sdk.EventRunWorkflowJob"}))
+	require.NoError(t, b.sendEvent(&sdk.Event{EventType: "sdk.EventRunWorkflow", WorkflowName: "my-workflow"}))
+
+	select {
+	case e := <-received:
+		assert.Equal(t, "my-workflow", e.WorkflowName)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+	assert.Equal(t, 2, calls)
+
+	// delivery status is recorded after the request
+	var status sdk.EventIntegrationStatus
+	for i := 0; i < 50; i++ {
+		status, err = GetEventIntegrationStatus(42)
+		require.NoError(t, err)
+		if status.Sent > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(1), status.Sent)
+	assert.Equal(t, int64(0), status.Failed)
+}
+
+func TestWebhookIntegrationBrokerDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store = cache.NewLocalStore(ctx, 60)
+	defer func() { store = nil }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	b, err := newIntegrationBroker(context.TODO(), 43, sdk.WebhookIntegrationModel, sdk.IntegrationConfig{
+		"url":         sdk.IntegrationConfigValue{Value: srv.URL},
+		"max retries": sdk.IntegrationConfigValue{Value: "0"},
+	})
+	require.NoError(t, err)
+	defer b.close(context.TODO())
+
+	require.NoError(t, b.sendEvent(&sdk.Event{EventType: "sdk.EventRunWorkflow", WorkflowName: "my-workflow"}))
+
+	var status sdk.EventIntegrationStatus
+	for i := 0; i < 100; i++ {
+		status, err = GetEventIntegrationStatus(43)
+		require.NoError(t, err)
+		if status.Failed > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(1), status.Failed)
+	require.Len(t, status.DeadLetters, 1)
+	assert.Equal(t, "my-workflow", status.DeadLetters[0].Event.WorkflowName)
+	assert.Contains(t, status.DeadLetters[0].Error, "HTTP 500")
+}
+
+func TestRecordDeliveryConcurrently(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store = cache.NewLocalStore(ctx, 60)
+	defer func() { store = nil }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			recordDelivery(ctx, 44, &sdk.Event{}, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			recordDelivery(ctx, 44, &sdk.Event{}, fmt.Errorf("delivery queue is full"))
+		}()
+	}
+	wg.Wait()
+
+	status, err := GetEventIntegrationStatus(44)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), status.Sent)
+	assert.Equal(t, int64(50), status.Failed)
+	assert.Len(t, status.DeadLetters, 50)
+}
+
+type fakeBroker struct{ sent chan sdk.Event }
+
+func (f *fakeBroker) initialize(ctx context.Context, options interface{}) (Broker, error) {
+	return f, nil
+}
+func (f *fakeBroker) sendEvent(e *sdk.Event) error { f.sent <- *e; return nil }
+func (f *fakeBroker) status() string               { return "fake" }
+func (f *fakeBroker) close(ctx context.Context)    {}
+
+func TestIntegrationBrokerQueueFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store = cache.NewLocalStore(ctx, 60)
+	defer func() { store = nil }()
+
+	fake := &fakeBroker{sent: make(chan sdk.Event, 10)}
+	b := &integrationBroker{
+		Broker:        fake,
+		integrationID: 45,
+		events:        make(chan sdk.Event, 1),
+		rejected:      make(chan sdk.Event, 1),
+	}
+
+	// Without delivery goroutine, the events that don't fit in the queue are dropped without blocking
+	require.NoError(t, b.sendEvent(&sdk.Event{WorkflowName: "queued"}))
+	assert.Equal(t, errDeliveryQueueFull, b.sendEvent(&sdk.Event{WorkflowName: "rejected"}))
+	assert.Equal(t, errDeliveryQueueFull, b.sendEvent(&sdk.Event{WorkflowName: "lost"}))
+
+	go b.deliver(ctx)
+	select {
+	case e := <-fake.sent:
+		assert.Equal(t, "queued", e.WorkflowName)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+
+	var status sdk.EventIntegrationStatus
+	for i := 0; i < 100; i++ {
+		var err error
+		status, err = GetEventIntegrationStatus(45)
+		require.NoError(t, err)
+		if status.Sent == 1 && status.Failed == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(1), status.Sent)
+	assert.Equal(t, int64(2), status.Failed)
+	require.Len(t, status.DeadLetters, 1)
+	assert.Equal(t, "rejected", status.DeadLetters[0].Event.WorkflowName)
+	assert.Equal(t, errDeliveryQueueFull.Error(), status.LastError)
+}
@@ -34,10 +34,26 @@ func publishWorkflowEvent(ctx context.Context, payload interface{}, key, workflo
 	publishEvent(ctx, event)
 }
 
+// hideHookSecrets returns a copy of the workflow without the webhook secrets
+func hideHookSecrets(w sdk.Workflow) sdk.Workflow {
+	if w.WorkflowData == nil {
+		return w
+	}
+	data := *w.WorkflowData
+	data.Node.Hooks = make([]sdk.NodeHook, len(w.WorkflowData.Node.Hooks))
+	for i, h := range w.WorkflowData.Node.Hooks {
+		h.Config = h.Config.Clone()
+		delete(h.Config, sdk.HookConfigWebHookSecret)
+		data.Node.Hooks[i] = h
+	}
+	w.WorkflowData = &data
+	return w
+}
+
 // PublishWorkflowAdd publishes an event for the creation of the given Workflow
 func PublishWorkflowAdd(ctx context.Context, projKey string, w sdk.Workflow, u sdk.Identifiable) {
 	e := sdk.EventWorkflowAdd{
-		Workflow: w,
+		Workflow: hideHookSecrets(w),
 	}
 	publishWorkflowEvent(ctx, e, projKey, w.Name, w.EventIntegrations, u)
 }
@@ -45,8 +61,8 @@ func PublishWorkflowAdd(ctx context.Context, projKey string, w sdk.Workflow, u s
 // PublishWorkflowUpdate publishes an event for the update of the given Workflow
 func PublishWorkflowUpdate(ctx context.Context, projKey string, w sdk.Workflow, oldw sdk.Workflow, u sdk.Identifiable) {
 	e := sdk.EventWorkflowUpdate{
-		NewWorkflow: w,
-		OldWorkflow: oldw,
+		NewWorkflow: hideHookSecrets(w),
+		OldWorkflow: hideHookSecrets(oldw),
 	}
 	publishWorkflowEvent(ctx, e, projKey, w.Name, w.EventIntegrations, u)
 }
@@ -54,7 +70,7 @@ func PublishWorkflowUpdate(ctx context.Context, projKey string, w sdk.Workflow,
 // PublishWorkflowDelete publishes an event for the deletion of the given Workflow
 func PublishWorkflowDelete(ctx context.Context, projKey string, w sdk.Workflow, u sdk.Identifiable) {
 	e := sdk.EventWorkflowDelete{
-		Workflow: w,
+		Workflow: hideHookSecrets(w),
 	}
 	publishWorkflowEvent(ctx, e, projKey, w.Name, w.EventIntegrations, u)
 }
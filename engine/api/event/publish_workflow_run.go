@@ -173,3 +173,21 @@ func PublishWorkflowNodeJobRun(ctx context.Context, db gorp.SqlExecutor, pkey st
 	}
 	publishRunWorkflow(ctx, e, pkey, wr.Workflow.Name, "", "", "", 0, 0, jr.Status, nil, wr.Workflow.EventIntegrations)
 }
+
+// PublishWorkflowRunSecretResolved publish an audit event when an external secret is resolved for a workflow run
+func PublishWorkflowRunSecretResolved(ctx context.Context, wr sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, name string, ref sdk.ExternalSecretReference) {
+	e := sdk.EventRunWorkflowSecretResolved{
+		Name:     name,
+		Provider: ref.Provider,
+		Path:     ref.Path,
+		Key:      ref.Key,
+	}
+	var subNumber int64
+	var status = wr.Status
+	if nr != nil {
+		e.NodeRunID = nr.ID
+		subNumber = nr.SubNumber
+		status = nr.Status
+	}
+	publishRunWorkflow(ctx, e, wr.Workflow.ProjectKey, wr.Workflow.Name, "", "", "", wr.Number, subNumber, status, nil, wr.Workflow.EventIntegrations)
+}
@@ -0,0 +1,131 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// WebhookClient sends events to an HTTP endpoint
+type WebhookClient struct {
+	options    WebhookConfig
+	httpClient *http.Client
+	ctx        context.Context
+	cancel     func()
+}
+
+// WebhookConfig handles all config to send events to an HTTP endpoint
+type WebhookConfig struct {
+	URL        string
+	Secret     string
+	MaxRetries int
+	Timeout    time.Duration
+}
+
+// Webhook request headers
+const (
+	WebhookHeaderEventType = "X-Cds-Event-Type"
+	WebhookHeaderDelivery  = "X-Cds-Delivery"
+	WebhookHeaderSignature = "X-Cds-Signature"
+)
+
+// WebhookSignature returns the signature of a webhook body for given secret
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// initialize returns broker, isInit and err if
+func (c *WebhookClient) initialize(ctx context.Context, options interface{}) (Broker, error) {
+	conf, ok := options.(WebhookConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid Webhook Initialization")
+	}
+	if conf.URL == "" {
+		return nil, fmt.Errorf("initWebhook> Invalid Webhook Configuration")
+	}
+	if conf.Timeout == 0 {
+		conf.Timeout = 10 * time.Second
+	}
+	c.options = conf
+	c.httpClient = &http.Client{Timeout: conf.Timeout}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c, nil
+}
+
+// close stops pending retries
+func (c *WebhookClient) close(ctx context.Context) {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// sendEvent posts the event, failed requests are retried with an exponential backoff
+func (c *WebhookClient) sendEvent(event *sdk.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	delivery := sdk.UUID()
+
+	backoff := time.Second
+	for i := 0; ; i++ {
+		err = c.post(delivery, event.EventType, body)
+		if err == nil || i >= c.options.MaxRetries {
+			return err
+		}
+		log.Debug("webhook> retrying delivery %s to %s in %s: %v", delivery, c.options.URL, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-c.ctx.Done():
+			return err
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func (c *WebhookClient) post(delivery, eventType string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(c.ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CDS/"+sdk.VERSION)
+	req.Header.Set(WebhookHeaderEventType, eventType)
+	req.Header.Set(WebhookHeaderDelivery, delivery)
+	if c.options.Secret != "" {
+		req.Header.Set(WebhookHeaderSignature, WebhookSignature(c.options.Secret, body))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer resp.Body.Close()            // nolint
+	io.Copy(ioutil.Discard, resp.Body) // nolint
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned HTTP %d", c.options.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// status: here, if c is initialized, webhook is ok
+func (c *WebhookClient) status() string {
+	return "Webhook OK"
+}
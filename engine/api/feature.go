@@ -4,8 +4,11 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	"github.com/ovh/cds/engine/api/feature"
 	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
 )
 
 func (api *API) cleanFeatureHandler() service.Handler {
@@ -13,3 +16,112 @@ func (api *API) cleanFeatureHandler() service.Handler {
 		return feature.Clean(api.Cache)
 	}
 }
+
+func checkNativeFeatures() error {
+	if !feature.IsNative() {
+		return sdk.NewErrorFrom(sdk.ErrNotImplemented, "features are not stored in CDS database, they are managed by the feature flipping provider")
+	}
+	return nil
+}
+
+func (api *API) getAdminFeaturesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if err := checkNativeFeatures(); err != nil {
+			return err
+		}
+
+		fs, err := feature.LoadAll(api.mustDB())
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, fs, http.StatusOK)
+	}
+}
+
+func (api *API) getAdminFeatureHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if err := checkNativeFeatures(); err != nil {
+			return err
+		}
+
+		f, err := feature.LoadByName(api.mustDB(), mux.Vars(r)["name"])
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+// putAdminFeatureHandler creates or updates the feature for given name.
+func (api *API) putAdminFeatureHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if err := checkNativeFeatures(); err != nil {
+			return err
+		}
+
+		var f sdk.Feature
+		if err := service.UnmarshalBody(r, &f); err != nil {
+			return err
+		}
+		f.Name = mux.Vars(r)["name"]
+		if err := f.IsValid(); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		old, err := feature.LoadByName(tx, f.Name)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if old != nil {
+			f.ID = old.ID
+			f.Created = old.Created
+			err = feature.Update(tx, &f)
+		} else {
+			err = feature.Insert(tx, &f)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+
+		if err := feature.Clean(api.Cache); err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+func (api *API) deleteAdminFeatureHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if err := checkNativeFeatures(); err != nil {
+			return err
+		}
+
+		f, err := feature.LoadByName(api.mustDB(), mux.Vars(r)["name"])
+		if err != nil {
+			return err
+		}
+
+		if err := feature.Delete(api.mustDB(), f.ID); err != nil {
+			return err
+		}
+
+		if err := feature.Clean(api.Cache); err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
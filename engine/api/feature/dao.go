@@ -0,0 +1,76 @@
+package feature
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadAll returns all features stored in database.
+func LoadAll(db gorp.SqlExecutor) ([]sdk.Feature, error) {
+	var fs []sdk.Feature
+	if _, err := db.Select(&fs, `SELECT id, name, enabled, rules, created, modified FROM "feature" ORDER BY name`); err != nil {
+		return nil, sdk.WrapError(err, "cannot load features")
+	}
+	return fs, nil
+}
+
+// LoadByName returns a feature from database for given name.
+func LoadByName(db gorp.SqlExecutor, name string) (*sdk.Feature, error) {
+	var f sdk.Feature
+	if err := db.SelectOne(&f, `SELECT id, name, enabled, rules, created, modified FROM "feature" WHERE name = $1`, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WrapError(err, "cannot load feature %s", name)
+	}
+	return &f, nil
+}
+
+// Insert a feature in database.
+func Insert(db gorp.SqlExecutor, f *sdk.Feature) error {
+	f.Created = time.Now()
+	f.Modified = f.Created
+	id, err := db.SelectInt(`INSERT INTO "feature" (name, enabled, rules, created, modified) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		f.Name, f.Enabled, f.Rules, f.Created, f.Modified)
+	if err != nil {
+		return sdk.WrapError(err, "cannot insert feature %s", f.Name)
+	}
+	f.ID = id
+	return nil
+}
+
+// Update a feature in database.
+func Update(db gorp.SqlExecutor, f *sdk.Feature) error {
+	f.Modified = time.Now()
+	if _, err := db.Exec(`UPDATE "feature" SET name = $2, enabled = $3, rules = $4, modified = $5 WHERE id = $1`,
+		f.ID, f.Name, f.Enabled, f.Rules, f.Modified); err != nil {
+		return sdk.WrapError(err, "cannot update feature %s", f.Name)
+	}
+	return nil
+}
+
+// Delete a feature in database.
+func Delete(db gorp.SqlExecutor, id int64) error {
+	if _, err := db.Exec(`DELETE FROM "feature" WHERE id = $1`, id); err != nil {
+		return sdk.WrapError(err, "cannot delete feature %d", id)
+	}
+	return nil
+}
+
+// loadProjectGroupNames returns the names of the groups that have a permission on given project.
+func loadProjectGroupNames(db gorp.SqlExecutor, projectKey string) ([]string, error) {
+	var names []string
+	if _, err := db.Select(&names, `
+		SELECT "group".name
+		FROM project_group
+		JOIN "group" ON "group".id = project_group.group_id
+		JOIN project ON project.id = project_group.project_id
+		WHERE project.projectkey = $1`, projectKey); err != nil {
+		return nil, sdk.WrapError(err, "cannot load groups for project %s", projectKey)
+	}
+	return names, nil
+}
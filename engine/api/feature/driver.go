@@ -0,0 +1,91 @@
+package feature
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/izanami"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Driver is a feature flipping backend.
+type Driver interface {
+	// IsEnabled returns true if the feature is enabled for given project, unknown features are enabled.
+	IsEnabled(ctx context.Context, featureID string, projectKey string) bool
+	// IsNative returns true if the features are stored in CDS database.
+	IsNative() bool
+}
+
+// CheckContext represents the context send to Izanami to check if the feature is enabled
+type CheckContext struct {
+	Key string `json:"key"`
+}
+
+type izanamiDriver struct {
+	client *izanami.Client
+}
+
+// NewIzanamiDriver returns a driver that checks features on an Izanami server.
+func NewIzanamiDriver(apiURL, clientID, clientSecret string) (Driver, error) {
+	c, err := izanami.New(apiURL, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &izanamiDriver{client: c}, nil
+}
+
+func (d *izanamiDriver) IsNative() bool { return false }
+
+func (d *izanamiDriver) IsEnabled(ctx context.Context, featureID string, projectKey string) bool {
+	if d.client == nil || d.client.Feature() == nil {
+		return true
+	}
+
+	resp, errCheck := d.client.Feature().CheckWithContext(featureID, CheckContext{projectKey})
+	if errCheck != nil {
+		if !strings.Contains(errCheck.Error(), "404") {
+			log.Warning(ctx, "Feature.IsEnabled > Cannot check feature %s: %s", featureID, errCheck)
+			return false
+		}
+		resp.Active = true
+	}
+
+	return resp.Active
+}
+
+type nativeDriver struct {
+	dbFunc func() *gorp.DbMap
+}
+
+// NewNativeDriver returns a driver that checks features stored in CDS database.
+func NewNativeDriver(dbFunc func() *gorp.DbMap) Driver {
+	return &nativeDriver{dbFunc: dbFunc}
+}
+
+func (d *nativeDriver) IsNative() bool { return true }
+
+func (d *nativeDriver) IsEnabled(ctx context.Context, featureID string, projectKey string) bool {
+	db := d.dbFunc()
+	f, err := LoadByName(db, featureID)
+	if err != nil {
+		if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			log.Warning(ctx, "Feature.IsEnabled > Cannot check feature %s: %v", featureID, err)
+			return false
+		}
+		return true
+	}
+
+	c := sdk.FeatureContext{ProjectKey: projectKey}
+	if projectKey != "" && len(f.Rules.Groups) > 0 {
+		c.Groups, err = loadProjectGroupNames(db, projectKey)
+		if err != nil {
+			log.Warning(ctx, "Feature.IsEnabled > Cannot check feature %s: %v", featureID, err)
+			return false
+		}
+	}
+
+	return f.IsEnabled(c)
+}
@@ -2,10 +2,10 @@ package feature
 
 import (
 	"context"
-	"strings"
+
+	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
-	"github.com/ovh/cds/sdk/izanami"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -16,12 +16,7 @@ const (
 	cacheFeatureKey = "feature:"
 )
 
-var izanamiClient *izanami.Client
-
-// CheckContext represents the context send to Izanami to check if the feature is enabled
-type CheckContext struct {
-	Key string `json:"key"`
-}
+var driver Driver
 
 // ProjectFeatures represents a project and the feature states
 type ProjectFeatures struct {
@@ -34,19 +29,31 @@ func List() []string {
 	return []string{FeatEnableTracing}
 }
 
-// Init initialize Izanami client
-func Init(apiURL, clientID, clientSecret string) error {
-	izc, err := izanami.New(apiURL, clientID, clientSecret)
-	SetClient(izc)
-	return err
+// Init initialize the feature flipping driver, Izanami is used if an url is given else features are stored in database.
+func Init(dbFunc func() *gorp.DbMap, izanamiAPIURL, izanamiClientID, izanamiClientSecret string) error {
+	if izanamiAPIURL == "" {
+		SetDriver(NewNativeDriver(dbFunc))
+		return nil
+	}
+	d, err := NewIzanamiDriver(izanamiAPIURL, izanamiClientID, izanamiClientSecret)
+	if err != nil {
+		return err
+	}
+	SetDriver(d)
+	return nil
+}
+
+// SetDriver set the feature flipping driver, without driver all features are enabled.
+func SetDriver(d Driver) {
+	driver = d
 }
 
-// SetClient set a client driver for Izanami
-func SetClient(c *izanami.Client) {
-	izanamiClient = c
+// IsNative returns true if the features are stored in CDS database.
+func IsNative() bool {
+	return driver != nil && driver.IsNative()
 }
 
-// GetFeatures tree for the given project from cache, if not found in cache init from Izanami.
+// GetFeatures tree for the given project from cache, if not found in cache init from the driver.
 func GetFeatures(ctx context.Context, store cache.Store, projectKey string) map[string]bool {
 	projFeats := ProjectFeatures{}
 
@@ -56,7 +63,7 @@ func GetFeatures(ctx context.Context, store cache.Store, projectKey string) map[
 		log.Error(ctx, "cannot get from cache %s: %v", k, err)
 	}
 	if find {
-		// if missing features, invalidate cache and rebuild data from Izanami
+		// if missing features, invalidate cache and rebuild data from the driver
 		var missingFeature bool
 		for _, f := range List() {
 			if _, ok := projFeats.Features[f]; !ok {
@@ -69,13 +76,13 @@ func GetFeatures(ctx context.Context, store cache.Store, projectKey string) map[
 		}
 	}
 
-	// get all features from Izanami and store in cache
+	// get all features from the driver and store in cache
 	projFeats = ProjectFeatures{Key: projectKey, Features: make(map[string]bool)}
 	for _, f := range List() {
-		projFeats.Features[f] = getStatusFromIzanami(ctx, f, projectKey)
+		projFeats.Features[f] = getStatus(ctx, f, projectKey)
 	}
 
-	// no expiration delay is set, the cache is cleared by Izanami calls on /feature/clean
+	// no expiration delay is set, the cache is cleared by Izanami calls on /feature/clean or when a feature is updated
 	if err := store.Set(cacheFeatureKey+projectKey, projFeats); err != nil {
 		log.Error(ctx, "unable to cache set %v: %v", cacheFeatureKey+projectKey, err)
 	}
@@ -92,27 +99,16 @@ func IsEnabled(ctx context.Context, store cache.Store, featureID string, project
 	}
 
 	// if features not in cache, it means that it's not a key from listed in List() func
-	// try to get a value from Izanami
-	return getStatusFromIzanami(ctx, featureID, projectKey)
+	// try to get a value from the driver
+	return getStatus(ctx, featureID, projectKey)
 }
 
-func getStatusFromIzanami(ctx context.Context, featureID string, projectKey string) bool {
+func getStatus(ctx context.Context, featureID string, projectKey string) bool {
 	// no feature flipping always return active.
-	if izanamiClient == nil || izanamiClient.Feature() == nil {
+	if driver == nil {
 		return true
 	}
-
-	// get from Izanami
-	resp, errCheck := izanamiClient.Feature().CheckWithContext(featureID, CheckContext{projectKey})
-	if errCheck != nil {
-		if !strings.Contains(errCheck.Error(), "404") {
-			log.Warning(ctx, "Feature.IsEnabled > Cannot check feature %s: %s", featureID, errCheck)
-			return false
-		}
-		resp.Active = true
-	}
-
-	return resp.Active
+	return driver.IsEnabled(ctx, featureID, projectKey)
 }
 
 // Clean the feature cache
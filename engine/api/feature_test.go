@@ -2,18 +2,23 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/feature"
 	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
 )
 
 func newTestAPIWithIzanamiToken(t *testing.T, token string, bootstrapFunc ...test.Bootstrapf) (*API, *gorp.DbMap, *Router, context.CancelFunc) {
@@ -57,3 +62,57 @@ func TestFeatureClean(t *testing.T) {
 	router.Mux.ServeHTTP(w, req)
 	assert.Equal(t, 204, w.Code)
 }
+
+func Test_putAdminFeatureHandler(t *testing.T) {
+	api, db, _, end := newTestAPI(t)
+	defer end()
+	feature.SetDriver(feature.NewNativeDriver(api.mustDB))
+	defer feature.SetDriver(nil)
+
+	_, jwt := assets.InsertAdminUser(t, db)
+	proj := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+	name := "feat-" + sdk.RandomString(10)
+
+	// Unknown feature is enabled by default
+	assert.True(t, feature.IsEnabled(context.TODO(), api.Cache, name, proj.Key))
+
+	f := sdk.Feature{Enabled: true, Rules: sdk.FeatureRules{ProjectKeys: []string{"ANOTHER"}}}
+	uri := api.Router.GetRoute(http.MethodPut, api.putAdminFeatureHandler, map[string]string{"name": name})
+	req := assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodPut, uri, f)
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, feature.IsEnabled(context.TODO(), api.Cache, name, proj.Key))
+
+	// Update the feature to enable it for the project
+	f.Rules.ProjectKeys = append(f.Rules.ProjectKeys, proj.Key)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodPut, uri, f)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, feature.IsEnabled(context.TODO(), api.Cache, name, proj.Key))
+
+	uri = api.Router.GetRoute(http.MethodGet, api.getAdminFeaturesHandler, nil)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, uri, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var fs []sdk.Feature
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fs))
+	var found bool
+	for _, f := range fs {
+		if f.Name == name {
+			found = true
+			assert.Equal(t, []string{"ANOTHER", proj.Key}, f.Rules.ProjectKeys)
+		}
+	}
+	assert.True(t, found)
+
+	uri = api.Router.GetRoute(http.MethodDelete, api.deleteAdminFeatureHandler, map[string]string{"name": name})
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodDelete, uri, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	_, err := feature.LoadByName(db, name)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+}
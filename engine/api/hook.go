@@ -99,3 +99,50 @@ func (api *API) getHookPollingVCSEvents() service.Handler {
 		return service.WriteJSON(w, repoEvents, http.StatusOK)
 	}
 }
+
+func (api *API) getHookChangedFilesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		// This handler can only be called by a service managed by an admin
+		if _, isService := api.isService(ctx); !isService && !isAdmin(ctx) {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		uuid := mux.Vars(r)["uuid"]
+		base := r.FormValue("base")
+		head := r.FormValue("head")
+		if base == "" || head == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "base and head are mandatory")
+		}
+
+		h, err := workflow.LoadHookByUUID(api.mustDB(), uuid)
+		if err != nil {
+			return err
+		}
+		if h.Config[sdk.HookConfigVCSServer].Value == "" || h.Config[sdk.HookConfigRepoFullName].Value == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "hook %s is not linked to a repository", uuid)
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, h.Config[sdk.HookConfigProject].Value, nil)
+		if err != nil {
+			return err
+		}
+
+		vcsServer := repositoriesmanager.GetProjectVCSServer(proj, h.Config[sdk.HookConfigVCSServer].Value)
+		client, err := repositoriesmanager.AuthorizedClient(ctx, api.mustDB(), api.Cache, proj.Key, vcsServer)
+		if err != nil {
+			return err
+		}
+
+		files, err := client.ChangedFilesBetweenRefs(ctx, h.Config[sdk.HookConfigRepoFullName].Value, base, head)
+		if sdk.ErrorIs(err, sdk.ErrNotImplemented) {
+			return sdk.NewErrorFrom(sdk.ErrHookChangedFilesUnknown, "repository manager %s cannot compare %s and %s", vcsServer.Name, base, head)
+		}
+		if err != nil {
+			return sdk.WrapError(err, "cannot get changed files between %s and %s", base, head)
+		}
+		if files == nil {
+			files = []string{}
+		}
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/mail"
 	"net/smtp"
 	"text/template"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -59,6 +60,20 @@ Regards,
 CDS Team
 `
 
+const templateConsumerExpireSoon = `Hi {{.Username}},
+
+Your consumer "{{.ConsumerName}}" will expire on {{.ExpireAt}}.
+After this date, its signin token and its sessions will not be accepted anymore.
+
+If you still need it, create a new consumer with:
+
+$ cdsctl consumer new
+
+Regards,
+--
+CDS Team
+`
+
 // Init initializes configuration
 func Init(user, password, from, host, port string, tls, disable bool) {
 	smtpUser = user
@@ -171,6 +186,22 @@ func SendMailResetToken(ctx context.Context, userMail, username, token, callback
 	return SendEmail(ctx, "[CDS] Your password was reset", &mailContent, userMail, false)
 }
 
+// SendMailConsumerExpireSoon send mail to remind a user that one of its consumers will expire.
+func SendMailConsumerExpireSoon(ctx context.Context, userMail, username, consumerName string, expireAt time.Time) error {
+	var mailContent bytes.Buffer
+	t, err := template.New("Email template").Parse(templateConsumerExpireSoon)
+	if err != nil {
+		return sdk.WrapError(err, "error with parsing template")
+	}
+	if err := t.Execute(&mailContent, struct{ Username, ConsumerName, ExpireAt string }{
+		username, consumerName, expireAt.Format(time.RFC1123),
+	}); err != nil {
+		return sdk.WrapError(err, "cannot execute template")
+	}
+
+	return SendEmail(ctx, "[CDS] Your consumer "+consumerName+" will expire soon", &mailContent, userMail, false)
+}
+
 func createTemplate(templ, callbackURL, callbackAPIURL, username, token string) (bytes.Buffer, error) {
 	var b bytes.Buffer
 
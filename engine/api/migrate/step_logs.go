@@ -0,0 +1,31 @@
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// MoveStepLogsToObjectStore moves all step logs stored in database to the objectstore.
+func MoveStepLogsToObjectStore(ctx context.Context, db *gorp.DbMap) error {
+	var total int
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := workflow.MoveStepLogsToStorage(ctx, db, 100)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			log.Info(ctx, "migrate.MoveStepLogsToObjectStore> %d step logs moved", total)
+			return nil
+		}
+		total += n
+		time.Sleep(10 * time.Millisecond) // avoid DDOS the database
+	}
+}
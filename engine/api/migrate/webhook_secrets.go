@@ -0,0 +1,20 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// EncryptWebHookSecrets encrypts the webhook secrets stored in clear in database.
+func EncryptWebHookSecrets(ctx context.Context, db *gorp.DbMap) error {
+	n, err := workflow.EncryptWebHookSecrets(db)
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "migrate.EncryptWebHookSecrets> %d hooks and workflows updated", n)
+	return nil
+}
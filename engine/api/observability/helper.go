@@ -33,6 +33,7 @@ const (
 	TagPipeline           = "pipeline"
 	TagPipelineDeep       = "pipeline_deep"
 	TagWorker             = "worker"
+	TagWorkerModel        = "worker_model"
 	TagToken              = "token"
 	TagPermission         = "permission"
 )
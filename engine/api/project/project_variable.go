@@ -198,6 +198,12 @@ func InsertVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid variable name. It should match %s", sdk.NamePattern))
 	}
 
+	if variable.Type == sdk.ExternalSecretVariable {
+		if _, err := secret.CheckReference(proj.Key, variable.Value); err != nil {
+			return err
+		}
+	}
+
 	query := `INSERT INTO project_variable(project_id, var_name, var_value, cipher_value, var_type)
 		  VALUES($1, $2, $3, $4, $5) RETURNING id`
 
@@ -236,6 +242,12 @@ func UpdateVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 		return sdk.NewError(sdk.ErrInvalidName, fmt.Errorf("Invalid variable name. It should match %s", sdk.NamePattern))
 	}
 
+	if variable.Type == sdk.ExternalSecretVariable {
+		if _, err := secret.CheckReference(proj.Key, variable.Value); err != nil {
+			return err
+		}
+	}
+
 	// If we are updating a batch of variables, some of them might be secrets, we don't want to crush the value
 	if sdk.NeedPlaceholder(variable.Type) && variable.Value == sdk.PasswordPlaceholder {
 		varValue = previousVar.Value
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getProjectConcurrencyLocksHandler returns the node runs holding or waiting for the concurrency groups of the project
+func (api *API) getProjectConcurrencyLocksHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		locks, err := workflow.LoadNodeRunLocksByProjectID(ctx, api.mustDB(), proj.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, locks, http.StatusOK)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 func (api *API) getProjectIntegrationHandler() service.Handler {
@@ -35,6 +36,66 @@ func (api *API) getProjectIntegrationHandler() service.Handler {
 	}
 }
 
+func (api *API) getProjectIntegrationEventsStatusHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars[permProjectKey]
+		integrationName := vars["integrationName"]
+
+		projInt, err := integration.LoadProjectIntegrationByName(api.mustDB(), projectKey, integrationName, false)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot load integration %s/%s", projectKey, integrationName)
+		}
+		if !projInt.Model.Event {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "integration %s is not an event integration", integrationName)
+		}
+
+		status, err := event.GetEventIntegrationStatus(projInt.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, status, http.StatusOK)
+	}
+}
+
+func (api *API) postProjectIntegrationEventsReplayHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars[permProjectKey]
+		integrationName := vars["integrationName"]
+
+		var req sdk.EventReplayRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "Cannot read body")
+		}
+		if req.From.IsZero() {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing replay start date")
+		}
+		if !req.To.IsZero() && req.To.Before(req.From) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid replay end date")
+		}
+
+		projInt, err := integration.LoadProjectIntegrationByName(api.mustDB(), projectKey, integrationName, true)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot load integration %s/%s", projectKey, integrationName)
+		}
+		if !projInt.Model.Event {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "integration %s is not an event integration", integrationName)
+		}
+
+		sdk.GoRoutine(api.Router.Background, "event.ReplayEvents-"+projectKey+"-"+integrationName, func(ctx context.Context) {
+			n, err := event.ReplayEvents(ctx, api.mustDB(), projInt, projectKey, req)
+			if err != nil {
+				log.Error(ctx, "postProjectIntegrationEventsReplayHandler> replay on integration %s/%s stopped after %d events: %v", projectKey, integrationName, n, err)
+				return
+			}
+			log.Info(ctx, "postProjectIntegrationEventsReplayHandler> %d events replayed on integration %s/%s", n, projectKey, integrationName)
+		})
+
+		return service.WriteJSON(w, nil, http.StatusAccepted)
+	}
+}
+
 func (api *API) putProjectIntegrationHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -40,6 +40,11 @@ func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMa
 			if err := workflows(ctx, DBFunc(), store, workflowRunsMarkToDelete); err != nil {
 				log.Warning(ctx, "purge> Error on workflows : %v", err)
 			}
+
+			log.Debug("purge> Applying workflow retention policies...")
+			if err := retentionPolicies(ctx, DBFunc(), workflowRunsMarkToDelete); err != nil {
+				log.Warning(ctx, "purge> Error on retentionPolicies : %v", err)
+			}
 		}
 	}
 }
@@ -158,6 +163,11 @@ func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, store c
 			continue
 		}
 
+		if err := workflow.DeleteNodeRunLogsFromStorage(ctx, db, workflowRunID); err != nil {
+			log.Error(ctx, "deleteWorkflowRunsHistory> error while deleting logs: %v", err)
+			continue
+		}
+
 		res, err := db.Exec("DELETE FROM workflow_run WHERE workflow_run.id = $1", workflowRunID)
 		if err != nil {
 			log.Error(ctx, "deleteWorkflowRunsHistory> unable to delete workflow run %d: %v", workflowRunID, err)
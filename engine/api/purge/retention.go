@@ -0,0 +1,82 @@
+package purge
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"go.opencensus.io/stats"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// maxRunsMarkedToDelete is the max count of workflow runs marked to delete for a workflow at each purge.
+const maxRunsMarkedToDelete = 100
+
+// retentionPolicies applies the retention policies of all the workflows that have one.
+func retentionPolicies(ctx context.Context, db *gorp.DbMap, workflowRunsMarkToDelete *stats.Int64Measure) error {
+	res := []struct {
+		ID              int64          `db:"id"`
+		RetentionPolicy sql.NullString `db:"retention_policy"`
+	}{}
+	if _, err := db.Select(&res, "SELECT id, retention_policy FROM workflow WHERE retention_policy IS NOT NULL AND to_delete = false ORDER BY id ASC"); err != nil {
+		return sdk.WrapError(err, "unable to load workflows with retention policy")
+	}
+
+	for _, r := range res {
+		wf := sdk.Workflow{ID: r.ID, RetentionPolicy: &sdk.WorkflowRetentionPolicy{}}
+		if err := gorpmapping.JSONNullString(r.RetentionPolicy, wf.RetentionPolicy); err != nil {
+			log.Error(ctx, "purge.retentionPolicies> unable to read retention policy for workflow %d: %v", r.ID, err)
+			continue
+		}
+		if _, err := ApplyRetentionPolicy(ctx, db, wf, false, workflowRunsMarkToDelete); err != nil {
+			log.Error(ctx, "purge.retentionPolicies> unable to apply retention policy for workflow %d: %v", r.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// ApplyRetentionPolicy evaluates the retention policy of the workflow and marks to delete the runs that should not be kept,
+// artifacts and logs of these runs will be removed with them. Nothing is marked with dry run, evaluation result is
+// returned for all the runs of the workflow.
+func ApplyRetentionPolicy(ctx context.Context, db gorp.SqlExecutor, wf sdk.Workflow, dryRun bool, workflowRunsMarkToDelete *stats.Int64Measure) ([]sdk.WorkflowRunRetentionResult, error) {
+	if wf.RetentionPolicy == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "no retention policy defined for workflow %s", wf.Name)
+	}
+
+	runs, err := workflow.LoadRunsRetention(db, wf.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := wf.RetentionPolicy.Evaluate(runs, time.Now())
+	if dryRun {
+		return res, nil
+	}
+
+	var ids []int64
+	for i := range res {
+		if !res[i].Keep && len(ids) < maxRunsMarkedToDelete {
+			ids = append(ids, res[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	if err := workflow.MarkWorkflowRunsAsDelete(db, ids); err != nil {
+		return nil, err
+	}
+	log.Info(ctx, "purge.ApplyRetentionPolicy> %d runs marked to delete for workflow %d", len(ids), wf.ID)
+	if workflowRunsMarkToDelete != nil {
+		observability.Record(ctx, workflowRunsMarkToDelete, int64(len(ids)))
+	}
+
+	return res, nil
+}
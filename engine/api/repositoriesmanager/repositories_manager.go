@@ -202,6 +202,8 @@ func (c *vcsClient) doJSONRequest(ctx context.Context, method, path string, in i
 			err = sdk.WrapError(sdk.ErrNotFound, "%s", err)
 		case http.StatusForbidden:
 			err = sdk.WrapError(sdk.ErrForbidden, "%s", err)
+		case http.StatusNotImplemented:
+			err = sdk.WrapError(sdk.ErrNotImplemented, "%s", err)
 		default:
 			err = sdk.WrapError(sdk.ErrUnknownError, "%s", err)
 		}
@@ -364,6 +366,15 @@ func (c *vcsClient) CommitsBetweenRefs(ctx context.Context, fullname, base, head
 	return commits, nil
 }
 
+func (c *vcsClient) ChangedFilesBetweenRefs(ctx context.Context, fullname, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/vcs/%s/repos/%s/changes?base=%s&head=%s", c.name, fullname, url.QueryEscape(base), url.QueryEscape(head))
+	if _, err := c.doJSONRequest(ctx, "GET", path, nil, &files); err != nil {
+		return nil, sdk.WrapError(err, "unable to find changed files on repository %s from %s", fullname, c.name)
+	}
+	return files, nil
+}
+
 func (c *vcsClient) Commit(ctx context.Context, fullname, hash string) (sdk.VCSCommit, error) {
 	commit := sdk.VCSCommit{}
 	path := fmt.Sprintf("/vcs/%s/repos/%s/commits/%s", c.name, fullname, hash)
@@ -404,6 +415,23 @@ func (c *vcsClient) PullRequestComment(ctx context.Context, fullname string, id
 	return nil
 }
 
+func (c *vcsClient) PullRequestComments(ctx context.Context, fullname string, id int) ([]sdk.VCSPullRequestComment, error) {
+	comments := []sdk.VCSPullRequestComment{}
+	path := fmt.Sprintf("/vcs/%s/repos/%s/pullrequests/%d/comments", c.name, fullname, id)
+	if _, err := c.doJSONRequest(ctx, "GET", path, nil, &comments); err != nil {
+		return nil, sdk.WrapError(err, "unable to get pullrequest comments on repository %s from %s", fullname, c.name)
+	}
+	return comments, nil
+}
+
+func (c *vcsClient) PullRequestCommentEdit(ctx context.Context, fullname string, id int, comment sdk.VCSPullRequestComment) error {
+	path := fmt.Sprintf("/vcs/%s/repos/%s/pullrequests/%d/comments/%d", c.name, fullname, id, comment.ID)
+	if _, err := c.doJSONRequest(ctx, "PUT", path, comment, nil); err != nil {
+		return sdk.WrapError(err, "unable to edit pullrequest comment %d on repository %s from %s", comment.ID, fullname, c.name)
+	}
+	return nil
+}
+
 func (c *vcsClient) PullRequestCreate(ctx context.Context, fullname string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	path := fmt.Sprintf("/vcs/%s/repos/%s/pullrequests", c.name, fullname)
 	if _, err := c.doJSONRequest(ctx, "POST", path, pr, &pr); err != nil {
@@ -502,6 +502,15 @@ func MaintenanceAware() HandlerConfigParam {
 	return f
 }
 
+// CrossProject set the route as returning data of all the projects of the consumer,
+// it is refused to consumers restricted to some projects
+func CrossProject() HandlerConfigParam {
+	f := func(rc *service.HandlerConfig) {
+		rc.CrossProject = true
+	}
+	return f
+}
+
 // EnableTracing on a route
 func EnableTracing() HandlerConfigParam {
 	f := func(rc *service.HandlerConfig) {
@@ -21,6 +21,8 @@ const (
 	jwtCookieName  = "jwt_token"
 	xsrfHeaderName = "X-XSRF-TOKEN"
 	xsrfCookieName = "xsrf_token"
+
+	consumerLastUsedPeriod = 5 * time.Minute
 )
 
 func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
@@ -74,6 +76,17 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 		if c.Disabled {
 			return ctx, sdk.WrapError(sdk.ErrUnauthorized, "consumer (%s) is disabled", c.ID)
 		}
+		if c.IsExpired() {
+			return ctx, sdk.WrapError(sdk.ErrUnauthorized, "consumer (%s) has expired", c.ID)
+		}
+		// Track the consumer usage, the date is updated at most once per period to limit database writes
+		if c.LastUsed == nil || time.Since(*c.LastUsed) > consumerLastUsedPeriod {
+			now := time.Now()
+			if err := authentication.UpdateConsumerLastUsed(api.mustDB(), c.ID, now); err != nil {
+				log.Error(ctx, "authMiddleware> %v", err)
+			}
+			c.LastUsed = &now
+		}
 		// If the driver was disabled for the consumer that was found, ignore it
 		if _, ok := api.AuthenticationDrivers[c.Type]; ok {
 			if err := user.LoadOptions.WithContacts(ctx, api.mustDB(), c.AuthentifiedUser); err != nil {
@@ -107,6 +120,11 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 			}
 		}
 
+		// Check that the route is allowed for a restricted consumer
+		if err := api.checkConsumerRestrictions(ctx, req.Method, mux.Vars(req), rc); err != nil {
+			return ctx, err
+		}
+
 		// Check that permission are valid for current route and consumer
 		if err := api.checkPermission(ctx, mux.Vars(req), rc.PermissionLevel); err != nil {
 			return ctx, err
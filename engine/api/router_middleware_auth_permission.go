@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/ovh/cds/engine/api/authentication"
 	"github.com/ovh/cds/engine/api/cache"
@@ -15,6 +16,7 @@ import (
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/workermodel"
 	"github.com/ovh/cds/engine/api/workflowtemplate"
+	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -50,6 +52,52 @@ func (api *API) checkPermission(ctx context.Context, routeVar map[string]string,
 	return nil
 }
 
+// checkConsumerRestrictions checks that the route is allowed for a consumer restricted to some projects, workflows or http methods.
+// Routes that give access to projects without a project key in their path, like the events stream, the navbar or the timeline,
+// are refused to consumers restricted to some projects.
+func (api *API) checkConsumerRestrictions(ctx context.Context, method string, routeVars map[string]string, rc *service.HandlerConfig) error {
+	consumer := getAPIConsumer(ctx)
+	restrictions := consumer.Restrictions
+
+	if !restrictions.AllowMethod(method) {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "consumer %s is restricted to %s requests", consumer.Name, strings.Join(restrictions.Methods, ", "))
+	}
+	if !restrictions.HasProjectRestriction() {
+		return nil
+	}
+
+	projectKey := routeVars["permProjectKey"]
+	if projectKey == "" {
+		projectKey = routeVars["key"]
+	}
+	workflowName := routeVars["permWorkflowName"]
+
+	switch {
+	case projectKey != "" && workflowName != "":
+		if !restrictions.AllowWorkflow(projectKey, workflowName) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "consumer %s is not allowed to access workflow %s/%s", consumer.Name, projectKey, workflowName)
+		}
+	case projectKey != "":
+		// A consumer restricted to some workflows can read the project of its workflows
+		if !restrictions.AllowProject(projectKey) &&
+			!(rc.PermissionLevel <= sdk.PermissionRead && restrictions.HasWorkflowInProject(projectKey)) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "consumer %s is not allowed to access project %s", consumer.Name, projectKey)
+		}
+	default:
+		if rc.CrossProject {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "consumer %s is restricted to some projects", consumer.Name)
+		}
+		for _, s := range rc.AllowedScopes {
+			switch s {
+			case sdk.AuthConsumerScopeProject, sdk.AuthConsumerScopeRun, sdk.AuthConsumerScopeRunExecution:
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "consumer %s is restricted to some projects", consumer.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (api *API) checkJobIDPermissions(ctx context.Context, jobID string, perm int, routeVars map[string]string) error {
 	ctx, end := observability.Span(ctx, "api.checkJobIDPermissions")
 	defer end()
@@ -2,21 +2,25 @@ package api
 
 import (
 	"context"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/authentication/local"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/workermodel"
 	"github.com/ovh/cds/engine/api/workflowtemplate"
+	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -1085,3 +1089,93 @@ func Test_checkActionBuiltinPermissions(t *testing.T) {
 	assert.Error(t, api.checkActionBuiltinPermissions(context.TODO(), sdk.RandomString(10), sdk.PermissionRead, nil), "error should be returned for random action name")
 	assert.NoError(t, api.checkActionBuiltinPermissions(context.TODO(), scriptAction.Name, sdk.PermissionRead, nil), "no error should be returned for valid action name")
 }
+
+func Test_checkConsumerRestrictions(t *testing.T) {
+	api := &API{}
+
+	consumer := &sdk.AuthConsumer{
+		Name: "restricted",
+		Restrictions: sdk.AuthConsumerRestrictions{
+			ProjectKeys: []string{"PROJ1"},
+			Workflows: []sdk.AuthConsumerRestrictionWorkflow{
+				{ProjectKey: "PROJ2", WorkflowName: "build"},
+			},
+			Methods: sdk.AuthConsumerReadOnlyMethods,
+		},
+	}
+	ctx := context.WithValue(context.Background(), contextAPIConsumer, consumer)
+
+	read := &service.HandlerConfig{PermissionLevel: sdk.PermissionRead, AllowedScopes: []sdk.AuthConsumerScope{sdk.AuthConsumerScopeProject}}
+	write := &service.HandlerConfig{PermissionLevel: sdk.PermissionReadWriteExecute, AllowedScopes: []sdk.AuthConsumerScope{sdk.AuthConsumerScopeProject}}
+	userRoute := &service.HandlerConfig{PermissionLevel: sdk.PermissionRead, AllowedScopes: []sdk.AuthConsumerScope{sdk.AuthConsumerScopeUser}}
+	noScopeRoute := &service.HandlerConfig{PermissionLevel: sdk.PermissionRead}
+	crossProjectRoute := &service.HandlerConfig{PermissionLevel: sdk.PermissionRead, CrossProject: true}
+
+	cases := []struct {
+		name    string
+		method  string
+		vars    map[string]string
+		rc      *service.HandlerConfig
+		allowed bool
+	}{
+		{"allowed project", http.MethodGet, map[string]string{"permProjectKey": "PROJ1"}, read, true},
+		{"workflow of allowed project", http.MethodGet, map[string]string{"key": "PROJ1", "permWorkflowName": "deploy"}, read, true},
+		{"allowed workflow", http.MethodGet, map[string]string{"key": "PROJ2", "permWorkflowName": "build"}, read, true},
+		{"other workflow", http.MethodGet, map[string]string{"key": "PROJ2", "permWorkflowName": "deploy"}, read, false},
+		{"read project of allowed workflow", http.MethodGet, map[string]string{"permProjectKey": "PROJ2"}, read, true},
+		{"write project of allowed workflow", http.MethodGet, map[string]string{"permProjectKey": "PROJ2"}, write, false},
+		{"other project", http.MethodGet, map[string]string{"permProjectKey": "PROJ3"}, read, false},
+		{"project route without key", http.MethodGet, map[string]string{}, read, false},
+		{"user route", http.MethodGet, map[string]string{}, userRoute, true},
+		{"route without scope", http.MethodGet, map[string]string{}, noScopeRoute, true},
+		{"events or navbar route", http.MethodGet, map[string]string{}, crossProjectRoute, false},
+		{"user timeline route", http.MethodGet, map[string]string{}, &service.HandlerConfig{PermissionLevel: sdk.PermissionRead, AllowedScopes: []sdk.AuthConsumerScope{sdk.AuthConsumerScopeUser}, CrossProject: true}, false},
+		{"write method", http.MethodPost, map[string]string{"permProjectKey": "PROJ1"}, read, false},
+	}
+	for _, c := range cases {
+		err := api.checkConsumerRestrictions(ctx, c.method, c.vars, c.rc)
+		if c.allowed {
+			assert.NoError(t, err, c.name)
+		} else {
+			assert.True(t, sdk.ErrorIs(err, sdk.ErrForbidden), c.name)
+		}
+	}
+
+	// Without restrictions everything is allowed
+	ctx = context.WithValue(context.Background(), contextAPIConsumer, &sdk.AuthConsumer{})
+	assert.NoError(t, api.checkConsumerRestrictions(ctx, http.MethodDelete, map[string]string{"permProjectKey": "PROJ3"}, write))
+}
+
+func Test_crossProjectRoutes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := &API{Router: newRouter(mux.NewRouter(), ""), Cache: cache.NewLocalStore(ctx, 60)}
+	api.Router.Background = ctx
+	api.InitRouter()
+
+	// Routes without project key that return data of several projects are refused to consumers restricted to some projects
+	routes := []struct {
+		uri    string
+		method string
+	}{
+		{"/user/timeline", http.MethodGet},
+		{"/user/favorite", http.MethodPost},
+		{"/ui/navbar", http.MethodGet},
+		{"/bookmarks", http.MethodGet},
+		{"/events", http.MethodGet},
+		{"/action/{permGroupName}/{permActionName}/usage", http.MethodGet},
+		{"/worker/model/{permGroupName}/{permModelName}/usage", http.MethodGet},
+		{"/template/{permGroupName}/{permTemplateSlug}/apply", http.MethodPost},
+		{"/template/{permGroupName}/{permTemplateSlug}/bulk", http.MethodPost},
+		{"/template/{permGroupName}/{permTemplateSlug}/instance", http.MethodGet},
+		{"/template/{permGroupName}/{permTemplateSlug}/usage", http.MethodGet},
+		{"/template/{permGroupName}/{permTemplateSlug}/upgrade", http.MethodGet},
+	}
+	for _, r := range routes {
+		cfg, ok := api.Router.mapRouterConfigs[r.uri]
+		require.True(t, ok, r.uri)
+		require.NotNil(t, cfg.Config[r.method], r.uri)
+		assert.True(t, cfg.Config[r.method].CrossProject, r.uri)
+	}
+}
@@ -0,0 +1,170 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Provider resolves external secrets references.
+type Provider interface {
+	// Get returns the secret value stored at path. If key is not empty, the secret
+	// is expected to be a set of key/values and only the value of key is returned.
+	Get(ctx context.Context, path, key string) (string, error)
+}
+
+// registeredProvider is a provider with the root of the project namespaces
+type registeredProvider struct {
+	Provider
+	pathPrefix string
+}
+
+var (
+	providers   = map[string]registeredProvider{}
+	providersMu sync.RWMutex
+)
+
+// RegisterProvider registers a provider for the given name (ie. file, vault).
+// A project can only reference the secrets stored under <pathPrefix>/<project key>/.
+func RegisterProvider(name string, p Provider, pathPrefix string) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = registeredProvider{Provider: p, pathPrefix: strings.Trim(pathPrefix, "/")}
+}
+
+// projectNamespace returns the path under which the secrets of a project are stored for a provider.
+func projectNamespace(pathPrefix, projectKey string) string {
+	return path.Join(pathPrefix, projectKey) + "/"
+}
+
+// CheckReference parses an external secret reference and checks that it can be used by the project:
+// its provider must be registered and its path must be in the namespace of the project.
+func CheckReference(projectKey, reference string) (sdk.ExternalSecretReference, error) {
+	ref, _, err := checkReference(projectKey, reference)
+	return ref, err
+}
+
+func checkReference(projectKey, reference string) (sdk.ExternalSecretReference, Provider, error) {
+	ref, err := sdk.ParseExternalSecretReference(reference)
+	if err != nil {
+		return ref, nil, err
+	}
+
+	providersMu.RLock()
+	p, has := providers[ref.Provider]
+	providersMu.RUnlock()
+	if !has {
+		return ref, nil, sdk.NewErrorFrom(sdk.ErrInvalidExternalSecret, "unknown secret provider %q", ref.Provider)
+	}
+
+	namespace := projectNamespace(p.pathPrefix, projectKey)
+	ref.Path = path.Clean(ref.Path)
+	if projectKey == "" || !strings.HasPrefix(ref.Path, namespace) {
+		return ref, nil, sdk.NewErrorFrom(sdk.ErrInvalidExternalSecret, "secret path of %s must be in the namespace %s of project %s", reference, namespace, projectKey)
+	}
+	return ref, p.Provider, nil
+}
+
+// Resolve checks an external secret reference of a project and returns its value from the matching provider.
+func Resolve(ctx context.Context, projectKey, reference string) (string, error) {
+	ref, p, err := checkReference(projectKey, reference)
+	if err != nil {
+		return "", err
+	}
+	return p.Get(ctx, ref.Path, ref.Key)
+}
+
+// getSecretKey returns the value of key from a set of key/values.
+func getSecretKey(data map[string]interface{}, path, key string) (string, error) {
+	v, has := data[key]
+	if !has || v == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrExternalSecretNotFound, "no key %q found for secret %s", key, path)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// FileProvider reads secrets from files under a base directory.
+// Without key the whole content of the file is returned, else the file must contain a JSON object.
+type FileProvider struct {
+	BaseDirectory string
+}
+
+// Get implements Provider.
+func (f FileProvider) Get(ctx context.Context, path, key string) (string, error) {
+	base, err := filepath.Abs(f.BaseDirectory)
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+	filename := filepath.Join(base, filepath.FromSlash(path))
+	if !strings.HasPrefix(filename, base+string(os.PathSeparator)) {
+		return "", sdk.NewErrorFrom(sdk.ErrInvalidExternalSecret, "secret path %q is outside of the provider directory", path)
+	}
+
+	btes, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return "", sdk.NewErrorFrom(sdk.ErrExternalSecretNotFound, "no secret found at %s", path)
+	}
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to read secret %s", path)
+	}
+
+	if key == "" {
+		return strings.TrimSuffix(string(btes), "\n"), nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(btes, &data); err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrInvalidExternalSecret, "secret %s is not a valid JSON object", path)
+	}
+	return getSecretKey(data, path, key)
+}
+
+// VaultProvider reads secrets from a Vault compatible HTTP API.
+// Both KV v1 and v2 secrets engines are supported, default key is "value".
+type VaultProvider struct {
+	secret *Secret
+}
+
+// NewVaultProvider returns a vault provider for given address and token.
+func NewVaultProvider(addr, token string) (*VaultProvider, error) {
+	s, err := New(token, addr)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to init vault client")
+	}
+	return &VaultProvider{secret: s}, nil
+}
+
+// Get implements Provider.
+func (v *VaultProvider) Get(ctx context.Context, path, key string) (string, error) {
+	conf, err := v.secret.Client.Logical().Read(path)
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to read secret %s from vault", path)
+	}
+	if conf == nil || conf.Data == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrExternalSecretNotFound, "no secret found at %s", path)
+	}
+
+	data := conf.Data
+	// KV v2 secrets engine nests values in a data field with metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	if key == "" {
+		key = "value"
+	}
+	return getSecretKey(data, path, key)
+}
@@ -0,0 +1,109 @@
+package secret
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "PROJ", "my-app"), os.FileMode(0700)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "PROJ", "my-app", "token"), []byte("s3cr3t\n"), os.FileMode(0600)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "PROJ", "my-app", "db.json"), []byte(`{"password": "p4ss"}`), os.FileMode(0600)))
+
+	RegisterProvider("file", FileProvider{BaseDirectory: dir}, "")
+
+	v, err := Resolve(context.TODO(), "PROJ", "file:PROJ/my-app/token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", v)
+
+	v, err = Resolve(context.TODO(), "PROJ", "file:PROJ/my-app/db.json#password")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ss", v)
+
+	_, err = Resolve(context.TODO(), "PROJ", "file:PROJ/my-app/db.json#user")
+	assert.Error(t, err)
+
+	_, err = Resolve(context.TODO(), "PROJ", "file:PROJ/../../etc/passwd")
+	assert.Error(t, err)
+
+	_, err = Resolve(context.TODO(), "PROJ", "unknown:PROJ/my-app/token")
+	assert.Error(t, err)
+
+	// Another project can't read the secrets of PROJ
+	_, err = Resolve(context.TODO(), "OTHER", "file:PROJ/my-app/token")
+	assert.Error(t, err)
+}
+
+func TestCheckReference(t *testing.T) {
+	RegisterProvider("test", FileProvider{}, "/secret/data/cds/")
+
+	ref, err := CheckReference("PROJ", "test:secret/data/cds/PROJ/my-app#password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret/data/cds/PROJ/my-app", ref.Path)
+	assert.Equal(t, "password", ref.Key)
+
+	for _, r := range []string{
+		"test:secret/data/cds/PROJ",
+		"test:secret/data/cds/PROJECT/my-app",
+		"test:secret/data/cds/PROJ/../OTHER/my-app",
+		"test:/secret/data/cds/PROJ/my-app",
+		"test:secret/data/other/my-app",
+		"unknown:secret/data/cds/PROJ/my-app",
+	} {
+		_, err := CheckReference("PROJ", r)
+		assert.Error(t, err, r)
+	}
+
+	_, err = CheckReference("", "test:secret/data/cds/my-app")
+	assert.Error(t, err)
+}
+
+func TestVaultProvider(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/PROJ/my-app":
+			w.Write([]byte(`{"data": {"value": "v1-secret", "password": "v1-password"}}`)) // nolint
+		case "/v1/secret/data/PROJ/my-app":
+			w.Write([]byte(`{"data": {"data": {"password": "v2-password"}, "metadata": {"version": 2}}}`)) // nolint
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	p, err := NewVaultProvider(ts.URL, "my-token")
+	require.NoError(t, err)
+	RegisterProvider("vault", p, "secret")
+
+	v, err := Resolve(context.TODO(), "PROJ", "vault:secret/PROJ/my-app")
+	require.NoError(t, err)
+	assert.Equal(t, "v1-secret", v)
+
+	v, err = Resolve(context.TODO(), "PROJ", "vault:secret/PROJ/my-app#password")
+	require.NoError(t, err)
+	assert.Equal(t, "v1-password", v)
+
+	RegisterProvider("vault", p, "secret/data")
+	v, err = Resolve(context.TODO(), "PROJ", "vault:secret/data/PROJ/my-app#password")
+	require.NoError(t, err)
+	assert.Equal(t, "v2-password", v)
+
+	_, err = Resolve(context.TODO(), "PROJ", "vault:secret/data/PROJ/unknown")
+	assert.Error(t, err)
+}
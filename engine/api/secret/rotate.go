@@ -0,0 +1,241 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// RotationTarget is a table column that contains encrypted values. Keys are the integer columns of the primary
+// key of the table (default to id). A JSON column contains base64 encoded encrypted values in its strings.
+type RotationTarget struct {
+	Table  string
+	Column string
+	Keys   []string
+	JSON   bool
+}
+
+func (t RotationTarget) keys() []string {
+	if len(t.Keys) == 0 {
+		return []string{"id"}
+	}
+	return t.Keys
+}
+
+// RotationTargets lists all columns that store values encrypted with secret.Encrypt.
+var RotationTargets = []RotationTarget{
+	{Table: "project_variable", Column: "cipher_value"},
+	{Table: "application_variable", Column: "cipher_value"},
+	{Table: "environment_variable", Column: "cipher_value"},
+	{Table: "project_key", Column: "private"},
+	{Table: "application_key", Column: "private"},
+	{Table: "environment_key", Column: "private"},
+	{Table: "project", Column: "vcs_servers"},
+	{Table: "project_variable_audit", Column: "variable_before", JSON: true},
+	{Table: "project_variable_audit", Column: "variable_after", JSON: true},
+	{Table: "application_variable_audit", Column: "variable_before", JSON: true},
+	{Table: "application_variable_audit", Column: "variable_after", JSON: true},
+	{Table: "environment_variable_audit", Column: "variable_before", JSON: true},
+	{Table: "environment_variable_audit", Column: "variable_after", JSON: true},
+	{Table: "application", Column: "vcs_strategy", JSON: true},
+	{Table: "application_deployment_strategy", Column: "config", Keys: []string{"application_id", "project_integration_id"}, JSON: true},
+	{Table: "project_integration", Column: "config", JSON: true},
+	{Table: "integration_model", Column: "public_configurations", JSON: true},
+	{Table: "worker_model", Column: "model", JSON: true},
+	{Table: "w_node_hook", Column: "config", JSON: true},
+	{Table: "workflow", Column: "workflow_data", JSON: true},
+}
+
+// RotationProgress is reported after each batch of a rotation.
+type RotationProgress struct {
+	Target  RotationTarget
+	Total   int64
+	Done    int64
+	Rotated int64
+}
+
+// RotateTarget re-encrypts with the newest key of the keyring all values of a column, by batch of given size.
+// Each batch is commited in its own transaction, so rotation can be stopped and started again safely.
+func RotateTarget(ctx context.Context, db *gorp.DbMap, target RotationTarget, batchSize int, progress func(RotationProgress)) (RotationProgress, error) {
+	p := RotationProgress{Target: target}
+	if currentKey() == nil {
+		return p, sdk.WithStack(fmt.Errorf("no keyring key configured, nothing to rotate"))
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	var err error
+	p.Total, err = db.SelectInt(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IS NOT NULL", target.Table, target.Column))
+	if err != nil {
+		return p, sdk.WrapError(err, "unable to count rows of %s", target.Table)
+	}
+
+	lastKey := make([]int64, len(target.keys()))
+	for {
+		if err := ctx.Err(); err != nil {
+			return p, sdk.WithStack(err)
+		}
+
+		n, last, rotated, err := rotateBatch(db, target, lastKey, batchSize)
+		if err != nil {
+			return p, err
+		}
+		if n == 0 {
+			return p, nil
+		}
+		lastKey = last
+		p.Done += n
+		p.Rotated += rotated
+		if progress != nil {
+			progress(p)
+		}
+	}
+}
+
+func rotateBatch(db *gorp.DbMap, target RotationTarget, fromKey []int64, batchSize int) (int64, []int64, int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, nil, 0, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	keys := target.keys()
+	params := make([]string, len(keys))
+	conditions := make([]string, len(keys))
+	args := make([]interface{}, len(keys))
+	for i := range keys {
+		params[i] = fmt.Sprintf("$%d", i+1)
+		conditions[i] = fmt.Sprintf("%s = $%d", keys[i], i+2)
+		args[i] = fromKey[i]
+	}
+	keyList := strings.Join(keys, ", ")
+
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE (%s) > (%s) AND %s IS NOT NULL ORDER BY %s LIMIT $%d FOR UPDATE",
+		keyList, target.Column, target.Table, keyList, strings.Join(params, ", "), target.Column, keyList, len(keys)+1)
+	rows, err := tx.Query(query, append(args, batchSize)...)
+	if err != nil {
+		return 0, nil, 0, sdk.WrapError(err, "unable to load %s", target.Table)
+	}
+
+	type row struct {
+		key   []int64
+		value []byte
+	}
+	var batch []row
+	for rows.Next() {
+		r := row{key: make([]int64, len(keys))}
+		dest := make([]interface{}, len(keys)+1)
+		for i := range r.key {
+			dest[i] = &r.key[i]
+		}
+		dest[len(keys)] = &r.value
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close() // nolint
+			return 0, nil, 0, sdk.WrapError(err, "unable to scan %s", target.Table)
+		}
+		batch = append(batch, r)
+	}
+	rows.Close() // nolint
+
+	var rotated int64
+	for _, r := range batch {
+		var value interface{}
+		var changed bool
+		if target.JSON {
+			var v []byte
+			v, changed, err = RotateJSON(r.value)
+			value = string(v)
+		} else {
+			value, changed, err = Rotate(r.value)
+		}
+		if err != nil {
+			return 0, nil, 0, sdk.WrapError(err, "unable to rotate %s %v", target.Table, r.key)
+		}
+		if !changed {
+			continue
+		}
+		query := fmt.Sprintf("UPDATE %s SET %s = $1 WHERE %s", target.Table, target.Column, strings.Join(conditions, " AND "))
+		args := []interface{}{value}
+		for _, k := range r.key {
+			args = append(args, k)
+		}
+		if _, err := tx.Exec(query, args...); err != nil {
+			return 0, nil, 0, sdk.WrapError(err, "unable to update %s %v", target.Table, r.key)
+		}
+		rotated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, 0, sdk.WithStack(err)
+	}
+
+	if len(batch) == 0 {
+		return 0, fromKey, 0, nil
+	}
+	return int64(len(batch)), batch[len(batch)-1].key, rotated, nil
+}
+
+// RotateJSON rotates all base64 encoded encrypted values found in the strings of a JSON document.
+// The document is returned unchanged if no value needs rotation.
+func RotateJSON(data []byte) ([]byte, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false, sdk.WrapError(err, "invalid json value")
+	}
+
+	doc, changed, err := rotateJSONValue(doc)
+	if err != nil || !changed {
+		return data, false, err
+	}
+
+	res, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, sdk.WithStack(err)
+	}
+	return res, true, nil
+}
+
+func rotateJSONValue(v interface{}) (interface{}, bool, error) {
+	var changed bool
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k := range t {
+			nv, c, err := rotateJSONValue(t[k])
+			if err != nil {
+				return nil, false, err
+			}
+			t[k] = nv
+			changed = changed || c
+		}
+	case []interface{}:
+		for i := range t {
+			nv, c, err := rotateJSONValue(t[i])
+			if err != nil {
+				return nil, false, err
+			}
+			t[i] = nv
+			changed = changed || c
+		}
+	case string:
+		b, err := base64.StdEncoding.DecodeString(t)
+		if err != nil || !(bytes.HasPrefix(b, []byte(prefix)) || bytes.HasPrefix(b, []byte(keyringPrefix))) {
+			return v, false, nil
+		}
+		res, c, err := Rotate(b)
+		if err != nil || !c {
+			return v, false, err
+		}
+		return base64.StdEncoding.EncodeToString(res), true, nil
+	}
+	return v, changed, nil
+}
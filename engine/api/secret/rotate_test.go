@@ -0,0 +1,137 @@
+package secret
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// encryptedColumns lists for each source file calling secret.Encrypt* the column where each encrypted value is stored,
+// in the order of the calls. A new call must be listed here and its column added to RotationTargets.
+var encryptedColumns = map[string][]string{
+	"api/application/application_integration.go": {"application_deployment_strategy.config"},
+	"api/application/application_variable.go":    {"application_variable.cipher_value", "application_variable.cipher_value"},
+	"api/application/application_vcs.go":         {"application.vcs_strategy"},
+	"api/application/dao_key.go":                 {"application_key.private"},
+	"api/application/gorp_model.go":              {"application_variable_audit.variable_before", "application_variable_audit.variable_after"},
+	"api/environment/dao_key.go":                 {"environment_key.private"},
+	"api/environment/environment_variable.go":    {"environment_variable.cipher_value", "environment_variable.cipher_value"},
+	"api/environment/gorp_model.go":              {"environment_variable_audit.variable_before", "environment_variable_audit.variable_after"},
+	"api/integration/dao_integration.go":         {"project_integration.config", "project_integration.config"},
+	"api/integration/dao_model.go":               {"integration_model.public_configurations"},
+	"api/project/dao_key.go":                     {"project_key.private"},
+	"api/project/gorp_model.go":                  {"project.vcs_servers", "project_variable_audit.variable_before", "project_variable_audit.variable_after"},
+	"api/project/project_variable.go":            {"project_variable.cipher_value", "project_variable.cipher_value"},
+	"api/repositoriesmanager/dao.go":             {"project.vcs_servers", "project.vcs_servers", "project.vcs_servers"},
+	"api/workermodel/gorp_model.go":              {"worker_model.model"},
+	"api/workflow/dao.go":                        {"workflow.workflow_data"},
+	"api/workflow/dao_data_hook.go":              {"w_node_hook.config"},
+}
+
+func TestRotationTargetsRegistered(t *testing.T) {
+	registered := map[string]bool{}
+	for _, target := range RotationTargets {
+		registered[target.Table+"."+target.Column] = true
+	}
+
+	calls := regexp.MustCompile(`secret\.(EncryptS|EncryptValue|Encrypt)\b`)
+	found := map[string]bool{}
+	err := filepath.Walk("../..", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && (info.Name() == "secret" || info.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		btes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		n := len(calls.FindAll(btes, -1))
+		if n == 0 {
+			return nil
+		}
+
+		rel, _ := filepath.Rel("../..", path)
+		rel = filepath.ToSlash(rel)
+		found[rel] = true
+		columns, ok := encryptedColumns[rel]
+		if !ok || len(columns) != n {
+			t.Errorf("%s encrypts %d values but %d columns are registered in encryptedColumns", rel, n, len(columns))
+		}
+		for _, c := range columns {
+			if !registered[c] {
+				t.Errorf("encrypted column %s written by %s is missing from RotationTargets", c, rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk failed: %s", err)
+	}
+
+	for f := range encryptedColumns {
+		if !found[f] {
+			t.Errorf("%s does not encrypt values anymore, it should be removed from encryptedColumns", f)
+		}
+	}
+}
+
+func TestRotateJSON(t *testing.T) {
+	if err := Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf"); err != nil {
+		t.Fatalf("Init failed: %s", err)
+	}
+	legacy, err := EncryptValue("my-password")
+	if err != nil {
+		t.Fatalf("EncryptValue failed: %s", err)
+	}
+
+	if err := Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf", Key{Timestamp: 1, Value: []byte("ho9Ru0eiph1ooXaezae7ahdeiTh9aeNg")}); err != nil {
+		t.Fatalf("Init failed: %s", err)
+	}
+	defer func() { keyring = nil }()
+
+	doc := `{"name":"my-registry","port":12345678901234567,"config":{"token":{"type":"password","value":"` + legacy + `"},"host":{"type":"string","value":"aG9zdA=="}},"list":["` + legacy + `"]}`
+	res, changed, err := RotateJSON([]byte(doc))
+	if err != nil || !changed {
+		t.Fatalf("RotateJSON failed: %v (changed: %v)", err, changed)
+	}
+
+	var v struct {
+		Name   string
+		Port   json.Number
+		Config map[string]struct{ Type, Value string }
+		List   []string
+	}
+	if err := json.Unmarshal(res, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	if v.Name != "my-registry" || v.Port.String() != "12345678901234567" || v.Config["host"].Value != "aG9zdA==" {
+		t.Fatalf("Clear values should be unchanged, got %s", res)
+	}
+	for _, value := range []string{v.Config["token"].Value, v.List[0]} {
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			t.Fatalf("Invalid base64 value: %s", err)
+		}
+		if NeedRotation(b) {
+			t.Fatalf("Value should have been rotated")
+		}
+		clear, err := DecryptValue(value)
+		if err != nil || clear != "my-password" {
+			t.Fatalf("Fail: Expected 'my-password', got '%s' (%v)", clear, err)
+		}
+	}
+
+	if _, changed, err := RotateJSON(res); err != nil || changed {
+		t.Fatalf("Rotated document should not change: %v (changed: %v)", err, changed)
+	}
+}
@@ -1,6 +1,7 @@
 package secret
 
 import (
+	"bytes"
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
@@ -11,8 +12,12 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
 
+	"golang.org/x/crypto/hkdf"
+
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 
@@ -27,8 +32,10 @@ const (
 )
 
 var (
-	key    []byte
-	prefix = "3DICC3It"
+	key           []byte
+	keyring       []Key
+	prefix        = "3DICC3It"
+	keyringPrefix = "3DICC3Ik"
 )
 
 type Secret struct {
@@ -36,10 +43,68 @@ type Secret struct {
 	Client *vault.Client
 }
 
-// Init secrets: cipherKey
-// cipherKey is set from viper configuration
-func Init(cipherKey string) {
-	key = []byte(cipherKey)
+// Key is a versioned cipher key, identified by its creation timestamp.
+// Separate encryption and MAC keys are derived from its value.
+type Key struct {
+	Timestamp int64
+	Value     []byte
+	cipherKey []byte
+	macKey    []byte
+}
+
+// deriveKey derives a key of ckeySize bytes from given secret for the given usage
+func deriveKey(secret []byte, usage string) ([]byte, error) {
+	k := make([]byte, ckeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte("cds-secret-"+usage)), k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Init secrets: cipherKey and keyring
+// cipherKey is set from viper configuration, it is used to decrypt values that were encrypted
+// before the keyring was configured. If a keyring is given, the newest key is used to encrypt.
+func Init(cipherKey string, keys ...Key) error {
+	key = nil
+	if cipherKey != "" {
+		key = []byte(cipherKey)
+	}
+	keyring = make([]Key, len(keys))
+	copy(keyring, keys)
+	sort.Slice(keyring, func(i, j int) bool { return keyring[i].Timestamp < keyring[j].Timestamp })
+	for i, k := range keyring {
+		if len(k.Value) != ckeySize {
+			return fmt.Errorf("invalid secret keyring key %d, it should be %d bytes (%d)", k.Timestamp, ckeySize, len(k.Value))
+		}
+		if i > 0 && keyring[i-1].Timestamp == k.Timestamp {
+			return fmt.Errorf("duplicate secret keyring key %d", k.Timestamp)
+		}
+		var err error
+		if keyring[i].cipherKey, err = deriveKey(k.Value, "encryption"); err != nil {
+			return err
+		}
+		if keyring[i].macKey, err = deriveKey(k.Value, "mac"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentKey returns the newest key of the keyring, nil if keyring is empty.
+func currentKey() *Key {
+	if len(keyring) == 0 {
+		return nil
+	}
+	return &keyring[len(keyring)-1]
+}
+
+func getKey(timestamp int64) *Key {
+	for i := range keyring {
+		if keyring[i].Timestamp == timestamp {
+			return &keyring[i]
+		}
+	}
+	return nil
 }
 
 // Create new secret client
@@ -79,37 +144,49 @@ func (secret *Secret) GetFromVault(s string) (string, error) {
 // Encrypt data using aes+hmac algorithm
 // Init() must be called before any encryption
 func Encrypt(data []byte) ([]byte, error) {
+	if k := currentKey(); k != nil {
+		ct, err := seal(k.cipherKey, k.macKey, data)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(keyringPrefix+strconv.FormatInt(k.Timestamp, 10)+":"), ct...), nil
+	}
+
 	// Check key is ready
 	if key == nil {
 		log.Error(context.TODO(), "Missing key, init failed?")
 		return nil, sdk.ErrSecretKeyFetchFailed
 	}
-	// generate nonce
-	nonce := make([]byte, nonceSize)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, err
-	}
-	// init aes cipher
-	c, err := aes.NewCipher(key)
+	ct, err := seal(key, key[ckeySize:], data)
 	if err != nil {
 		return nil, err
 	}
-	ctr := cipher.NewCTR(c, nonce)
-	// encrypt data
-	ct := make([]byte, len(data))
-	ctr.XORKeyStream(ct, data)
-	// add hmac
-	h := hmac.New(sha256.New, key[ckeySize:])
-	ct = append(nonce, ct...)
-	h.Write(ct)
-	ct = h.Sum(ct)
-
 	return append([]byte(prefix), ct...), nil
 }
 
 // Decrypt data using aes+hmac algorithm
 // Init() must be called before any decryption
 func Decrypt(data []byte) ([]byte, error) {
+	if strings.HasPrefix(string(data), keyringPrefix) {
+		data = []byte(strings.TrimPrefix(string(data), keyringPrefix))
+		i := bytes.IndexByte(data, ':')
+		if i < 0 {
+			log.Error(context.TODO(), "cannot decrypt secret, missing key id")
+			return nil, sdk.ErrInvalidSecretFormat
+		}
+		timestamp, err := strconv.ParseInt(string(data[:i]), 10, 64)
+		if err != nil {
+			log.Error(context.TODO(), "cannot decrypt secret, invalid key id %q", data[:i])
+			return nil, sdk.ErrInvalidSecretFormat
+		}
+		k := getKey(timestamp)
+		if k == nil {
+			log.Error(context.TODO(), "Missing key %d in keyring, init failed?", timestamp)
+			return nil, sdk.ErrSecretKeyFetchFailed
+		}
+		return open(k.cipherKey, k.macKey, data[i+1:])
+	}
+
 	if !strings.HasPrefix(string(data), prefix) {
 		return data, nil
 	}
@@ -119,7 +196,64 @@ func Decrypt(data []byte) ([]byte, error) {
 		log.Error(context.TODO(), "Missing key, init failed?")
 		return nil, sdk.ErrSecretKeyFetchFailed
 	}
+	return open(key[:ckeySize], key[ckeySize:], data)
+}
+
+// NeedRotation returns true if data is encrypted with another key than the newest key of the keyring.
+func NeedRotation(data []byte) bool {
+	k := currentKey()
+	if k == nil {
+		return false
+	}
+	if strings.HasPrefix(string(data), prefix) {
+		return true
+	}
+	return strings.HasPrefix(string(data), keyringPrefix) &&
+		!strings.HasPrefix(string(data), keyringPrefix+strconv.FormatInt(k.Timestamp, 10)+":")
+}
+
+// Rotate decrypts data and encrypts it again with the newest key of the keyring.
+// Data that don't need rotation are returned unchanged.
+func Rotate(data []byte) ([]byte, bool, error) {
+	if !NeedRotation(data) {
+		return data, false, nil
+	}
+	clear, err := Decrypt(data)
+	if err != nil {
+		return nil, false, err
+	}
+	res, err := Encrypt(clear)
+	if err != nil {
+		return nil, false, err
+	}
+	return res, true, nil
+}
+
+// seal encrypts data with AES-CTR and appends a HMAC-SHA256 of the nonce and the ciphertext.
+// Values encrypted with the legacy cipher key have no MAC key, the keyring keys always have one.
+func seal(ckey, mkey, data []byte) ([]byte, error) {
+	// generate nonce
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	// init aes cipher
+	c, err := aes.NewCipher(ckey)
+	if err != nil {
+		return nil, err
+	}
+	ctr := cipher.NewCTR(c, nonce)
+	// encrypt data
+	ct := make([]byte, len(data))
+	ctr.XORKeyStream(ct, data)
+	// add hmac
+	h := hmac.New(sha256.New, mkey)
+	ct = append(nonce, ct...)
+	h.Write(ct)
+	return h.Sum(ct), nil
+}
 
+func open(ckey, mkey, data []byte) ([]byte, error) {
 	if len(data) < (nonceSize + macSize) {
 		log.Error(context.TODO(), "cannot decrypt secret, got invalid data")
 		return nil, sdk.ErrInvalidSecretFormat
@@ -131,14 +265,14 @@ func Decrypt(data []byte) ([]byte, error) {
 	out := make([]byte, macStart-nonceSize)
 	data = data[:macStart]
 	// check hmac
-	h := hmac.New(sha256.New, key[ckeySize:])
+	h := hmac.New(sha256.New, mkey)
 	h.Write(data)
 	mac := h.Sum(nil)
 	if !hmac.Equal(mac, tag) {
 		return nil, fmt.Errorf("invalid hmac")
 	}
 	// uncipher data
-	c, err := aes.NewCipher(key[:ckeySize])
+	c, err := aes.NewCipher(ckey)
 	if err != nil {
 		return nil, err
 	}
@@ -232,6 +366,12 @@ func DecryptValue(v string) (string, error) {
 	return string(secret), nil
 }
 
+// IsEncryptedValue returns true if v has been encrypted by EncryptValue.
+func IsEncryptedValue(v string) bool {
+	b, err := base64.StdEncoding.DecodeString(v)
+	return err == nil && (bytes.HasPrefix(b, []byte(prefix)) || bytes.HasPrefix(b, []byte(keyringPrefix)))
+}
+
 // EncryptValue encrypt value for password.
 func EncryptValue(v string) (string, error) {
 	encryptedSecret, err := Encrypt([]byte(v))
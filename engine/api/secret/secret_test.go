@@ -70,6 +70,21 @@ func TestClear(t *testing.T) {
 	}
 }
 
+func TestIsEncryptedValue(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xb")
+
+	v, err := EncryptValue("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	if err != nil {
+		t.Fatalf("EncryptValue failed: %s", err)
+	}
+	if !IsEncryptedValue(v) {
+		t.Fatalf("Fail: %s should be encrypted", v)
+	}
+	if IsEncryptedValue("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef") {
+		t.Fatalf("Fail: an hex value should not be encrypted")
+	}
+}
+
 func TestDecryptS(t *testing.T) {
 
 	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xb")
@@ -99,3 +114,97 @@ func TestDecryptS(t *testing.T) {
 	}
 
 }
+
+func TestKeyring(t *testing.T) {
+	data := []byte("Hello world !")
+
+	// Value encrypted with the legacy key
+	if err := Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf"); err != nil {
+		t.Fatalf("Init failed: %s", err)
+	}
+	legacy, err := Encrypt(data)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+	if NeedRotation(legacy) {
+		t.Fatalf("Legacy value should not need rotation without keyring")
+	}
+
+	// Value encrypted with the first key of the keyring
+	if err := Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf", Key{Timestamp: 1, Value: []byte("ho9Ru0eiph1ooXaezae7ahdeiTh9aeNg")}); err != nil {
+		t.Fatalf("Init failed: %s", err)
+	}
+	v1, err := Encrypt(data)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+	if !bytes.HasPrefix(v1, []byte(keyringPrefix+"1:")) {
+		t.Fatalf("Encrypted value should be tagged with key id 1, got %q", v1[:12])
+	}
+
+	// Add a newer key
+	if err := Init("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf",
+		Key{Timestamp: 2, Value: []byte("Iethee4eeb2aiphaichoo4eim0eiTh9z")},
+		Key{Timestamp: 1, Value: []byte("ho9Ru0eiph1ooXaezae7ahdeiTh9aeNg")},
+	); err != nil {
+		t.Fatalf("Init failed: %s", err)
+	}
+
+	for _, ct := range [][]byte{legacy, v1} {
+		clear, err := Decrypt(ct)
+		if err != nil {
+			t.Fatalf("Decrypt failed: %s", err)
+		}
+		if bytes.Compare(clear, data) != 0 {
+			t.Fatalf("Fail: Expected '%s', got '%s'", data, clear)
+		}
+		if !NeedRotation(ct) {
+			t.Fatalf("Value should need rotation")
+		}
+		rotated, changed, err := Rotate(ct)
+		if err != nil || !changed {
+			t.Fatalf("Rotate failed: %v (changed: %v)", err, changed)
+		}
+		if !bytes.HasPrefix(rotated, []byte(keyringPrefix+"2:")) || NeedRotation(rotated) {
+			t.Fatalf("Rotated value should be tagged with key id 2")
+		}
+		clear, err = Decrypt(rotated)
+		if err != nil {
+			t.Fatalf("Decrypt failed: %s", err)
+		}
+		if bytes.Compare(clear, data) != 0 {
+			t.Fatalf("Fail: Expected '%s', got '%s'", data, clear)
+		}
+	}
+
+	// Unknown key
+	if err := Init("", Key{Timestamp: 3, Value: []byte("Iethee4eeb2aiphaichoo4eim0eiTh9z")}); err != nil {
+		t.Fatalf("Init failed: %s", err)
+	}
+	if _, err := Decrypt(v1); err == nil {
+		t.Fatalf("Decrypt should have failed with an unknown key")
+	}
+
+	if err := Init("", Key{Timestamp: 3, Value: []byte("tooshort")}); err == nil {
+		t.Fatalf("Init should have failed with an invalid key")
+	}
+
+	// Values encrypted with the keyring are authenticated
+	if err := Init("", Key{Timestamp: 4, Value: []byte("Iethee4eeb2aiphaichoo4eim0eiTh9z")}); err != nil {
+		t.Fatalf("Init failed: %s", err)
+	}
+	if len(keyring[0].macKey) != ckeySize || bytes.Equal(keyring[0].macKey, keyring[0].cipherKey) {
+		t.Fatalf("Encryption and MAC keys should be derived from the keyring key")
+	}
+	v4, err := Encrypt(data)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %s", err)
+	}
+	tampered := make([]byte, len(v4))
+	copy(tampered, v4)
+	tampered[len(keyringPrefix)+len("4:")+nonceSize] ^= 0xff
+	if _, err := Decrypt(tampered); err == nil {
+		t.Fatalf("Decrypt should have failed with a tampered value")
+	}
+	keyring = nil
+}
@@ -16,9 +16,11 @@ import (
 	"github.com/gorilla/mux"
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/api/workflowtemplate"
 	"github.com/ovh/cds/engine/service"
@@ -104,10 +106,14 @@ func (api *API) postTemplateHandler() service.Handler {
 		}
 
 		data.Version = 0
+		data.SemVer = ""
 
 		if !isGroupAdmin(ctx, grp) && !isAdmin(ctx) {
 			return sdk.WithStack(sdk.ErrForbidden)
 		}
+		if err := api.checkTemplateRepositoryPermissions(ctx, data.Repository); err != nil {
+			return err
+		}
 
 		// execute template with no instance only to check if parsing is ok
 		if _, err := workflowtemplate.Execute(&data, nil); err != nil {
@@ -228,9 +234,14 @@ func (api *API) putTemplateHandler() service.Handler {
 			}
 		}
 
+		if err := api.checkTemplateRepositoryPermissions(ctx, data.Repository); err != nil {
+			return err
+		}
+
 		// update fields from request data
 		clone := sdk.WorkflowTemplate(*old)
 		clone.Update(data)
+		clone.Repository = data.Repository
 
 		// execute template with no instance only to check if parsing is ok
 		if _, err := workflowtemplate.Execute(&clone, nil); err != nil {
@@ -284,7 +295,9 @@ func (api *API) deleteTemplateHandler() service.Handler {
 	}
 }
 
-func (api *API) applyTemplate(ctx context.Context, u sdk.Identifiable, p *sdk.Project, wt *sdk.WorkflowTemplate, req sdk.WorkflowTemplateRequest) (sdk.WorkflowTemplateResult, error) {
+// applyTemplate executes the template and stores its instance. For an as code workflow the generated files are
+// proposed in a pull request, so the applied version is only stored as pending on the instance until it is merged.
+func (api *API) applyTemplate(ctx context.Context, u sdk.Identifiable, p *sdk.Project, wt *sdk.WorkflowTemplate, req sdk.WorkflowTemplateRequest, asCode bool) (sdk.WorkflowTemplateResult, error) {
 	var result sdk.WorkflowTemplateResult
 
 	tx, err := api.mustDB().Begin()
@@ -319,22 +332,41 @@ func (api *API) applyTemplate(ctx context.Context, u sdk.Identifiable, p *sdk.Pr
 		wti = nil
 	}
 
+	var pending *sdk.WorkflowTemplateInstancePending
+	if asCode && !req.Detached {
+		pending = &sdk.WorkflowTemplateInstancePending{
+			WorkflowTemplateVersion: wt.Version,
+			WorkflowTemplateSemVer:  wt.SemVer,
+			Request:                 req,
+		}
+	}
+
 	// if a previous instance exist for the same workflow update it, else create a new one
 	var old *sdk.WorkflowTemplateInstance
 	if wti != nil {
 		clone := sdk.WorkflowTemplateInstance(*wti)
 		old = &clone
-		wti.WorkflowTemplateVersion = wt.Version
-		wti.Request = req
+		if pending != nil {
+			wti.Pending = pending
+		} else {
+			wti.WorkflowTemplateVersion = wt.Version
+			wti.WorkflowTemplateSemVer = wt.SemVer
+			wti.Request = req
+			wti.Pending = nil
+		}
 		if err := workflowtemplate.UpdateInstance(tx, wti); err != nil {
 			return result, err
 		}
 	} else {
 		wti = &sdk.WorkflowTemplateInstance{
-			ProjectID:               p.ID,
-			WorkflowTemplateID:      wt.ID,
-			WorkflowTemplateVersion: wt.Version,
-			Request:                 req,
+			ProjectID:          p.ID,
+			WorkflowTemplateID: wt.ID,
+			Request:            req,
+			Pending:            pending,
+		}
+		if pending == nil {
+			wti.WorkflowTemplateVersion = wt.Version
+			wti.WorkflowTemplateSemVer = wt.SemVer
 		}
 
 		// only store the new instance if request is not for a detached workflow
@@ -348,8 +380,10 @@ func (api *API) applyTemplate(ctx context.Context, u sdk.Identifiable, p *sdk.Pr
 		}
 	}
 
-	// execute template with request
-	result, err = workflowtemplate.Execute(wt, wti)
+	// execute template with request, a pending instance is executed with its pending request
+	applied := sdk.WorkflowTemplateInstance(*wti)
+	applied.Request = req
+	result, err = workflowtemplate.Execute(wt, &applied)
 	if err != nil {
 		return result, err
 	}
@@ -422,6 +456,10 @@ func (api *API) postTemplateApplyHandler() service.Handler {
 		if err := service.UnmarshalBody(r, &req); err != nil {
 			return err
 		}
+		wt, err = api.getTemplateForRequest(ctx, wt, req)
+		if err != nil {
+			return err
+		}
 		if err := wt.CheckParams(req); err != nil {
 			return err
 		}
@@ -451,7 +489,7 @@ func (api *API) postTemplateApplyHandler() service.Handler {
 			return err
 		}
 
-		res, err := api.applyTemplate(ctx, getAPIConsumer(ctx), p, wt, req)
+		res, err := api.applyTemplate(ctx, getAPIConsumer(ctx), p, wt, req, false)
 		if err != nil {
 			return err
 		}
@@ -505,7 +543,8 @@ func (api *API) postTemplateBulkHandler() service.Handler {
 			return err
 		}
 		m := make(map[string]struct{}, len(req.Operations))
-		for _, o := range req.Operations {
+		wts := make([]*sdk.WorkflowTemplate, len(req.Operations))
+		for i, o := range req.Operations {
 			// check for duplicated request
 			key := fmt.Sprintf("%s-%s", o.Request.ProjectKey, o.Request.WorkflowName)
 			if _, ok := m[key]; ok {
@@ -513,8 +552,12 @@ func (api *API) postTemplateBulkHandler() service.Handler {
 			}
 			m[key] = struct{}{}
 
-			// check request params
-			if err := wt.CheckParams(o.Request); err != nil {
+			// check request params with the version of the template that matches the request
+			wts[i], err = api.getTemplateForRequest(ctx, wt, o.Request)
+			if err != nil {
+				return err
+			}
+			if err := wts[i].CheckParams(o.Request); err != nil {
 				return err
 			}
 		}
@@ -582,8 +625,19 @@ func (api *API) postTemplateBulkHandler() service.Handler {
 						continue
 					}
 
+					// an existing as code workflow can't be overridden, the generated files are proposed in a pull request
+					wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, p, bulk.Operations[i].Request.WorkflowName, workflow.LoadOptions{})
+					if err != nil && !sdk.ErrorIs(err, sdk.ErrWorkflowNotFound) {
+						if errD := errorDefer(err); errD != nil {
+							log.Error(ctx, "%v", errD)
+							return
+						}
+						continue
+					}
+					asCode := wf != nil && wf.FromRepository != ""
+
 					// apply and import workflow
-					res, err := api.applyTemplate(ctx, consumer, p, wt, bulk.Operations[i].Request)
+					res, err := api.applyTemplate(ctx, consumer, p, wts[i], bulk.Operations[i].Request, asCode)
 					if err != nil {
 						if errD := errorDefer(err); errD != nil {
 							log.Error(ctx, "%v", errD)
@@ -593,7 +647,7 @@ func (api *API) postTemplateBulkHandler() service.Handler {
 					}
 
 					buf := new(bytes.Buffer)
-					if err := workflowtemplate.Tar(ctx, wt, res, buf); err != nil {
+					if err := workflowtemplate.Tar(ctx, wts[i], res, buf); err != nil {
 						if errD := errorDefer(err); errD != nil {
 							log.Error(ctx, "%v", errD)
 							return
@@ -601,15 +655,30 @@ func (api *API) postTemplateBulkHandler() service.Handler {
 						continue
 					}
 
-					tr := tar.NewReader(buf)
+					if asCode {
+						prLink, err := api.pushTemplateAsCode(ctx, consumer, p.Key, wf, wts[i], buf)
+						if err == nil {
+							err = api.setTemplateInstancePendingPullRequest(p, wts[i], bulk.Operations[i].Request, prLink)
+						}
+						if err != nil {
+							if errD := errorDefer(err); errD != nil {
+								log.Error(ctx, "%v", errD)
+								return
+							}
+							continue
+						}
+						bulk.Operations[i].PullRequestURL = prLink
+					} else {
+						tr := tar.NewReader(buf)
 
-					_, _, err = workflow.Push(ctx, api.mustDB(), api.Cache, p, tr, nil, consumer, project.DecryptWithBuiltinKey)
-					if err != nil {
-						if errD := errorDefer(sdk.WrapError(err, "cannot push generated workflow")); errD != nil {
-							log.Error(ctx, "%v", errD)
-							return
+						_, _, err = workflow.Push(ctx, api.mustDB(), api.Cache, p, tr, nil, consumer, project.DecryptWithBuiltinKey)
+						if err != nil {
+							if errD := errorDefer(sdk.WrapError(err, "cannot push generated workflow")); errD != nil {
+								log.Error(ctx, "%v", errD)
+								return
+							}
+							continue
 						}
-						continue
 					}
 
 					bulk.Operations[i].Status = sdk.OperationStatusDone
@@ -935,6 +1004,355 @@ func (api *API) getTemplateUsageHandler() service.Handler {
 	}
 }
 
+func (api *API) postTemplatePublishHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		groupName := vars["permGroupName"]
+		templateSlug := vars["permTemplateSlug"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), groupName)
+		if err != nil {
+			return err
+		}
+
+		if !isGroupAdmin(ctx, g) && !isAdmin(ctx) {
+			return sdk.WithStack(sdk.ErrInvalidGroupAdmin)
+		}
+
+		old, err := workflowtemplate.LoadBySlugAndGroupID(ctx, api.mustDB(), templateSlug, g.ID, workflowtemplate.LoadOptions.Default)
+		if err != nil {
+			return err
+		}
+		if !old.IsFromRepository() {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "template %s/%s is not maintained in a repository", groupName, templateSlug)
+		}
+		if err := api.checkTemplateRepositoryPermissions(ctx, old.Repository); err != nil {
+			return err
+		}
+
+		var req sdk.WorkflowTemplatePublish
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		version, err := sdk.WorkflowTemplateVersionFromTag(req.Tag)
+		if err != nil {
+			return err
+		}
+
+		versions, err := workflowtemplate.LoadVersionsByTemplateID(ctx, api.mustDB(), old.ID)
+		if err != nil {
+			return err
+		}
+		for i := range versions {
+			if versions[i].Version == version.String() {
+				return sdk.NewErrorFrom(sdk.ErrAlreadyExist, "version %s of template %s/%s is already published", version, groupName, templateSlug)
+			}
+		}
+
+		consumer := getAPIConsumer(ctx)
+
+		data, err := api.readTemplateFromRepository(ctx, consumer, old.Repository, req.Tag)
+		if err != nil {
+			return err
+		}
+
+		// the published version keeps the identity of the template, only its content comes from the repository
+		clone := sdk.WorkflowTemplate(*old)
+		clone.Update(data)
+		clone.Name = old.Name
+		clone.Slug = old.Slug
+		clone.GroupID = old.GroupID
+		clone.ImportURL = ""
+		clone.SemVer = version.String()
+
+		// execute template with no instance only to check if parsing is ok
+		if _, err := workflowtemplate.Execute(&clone, nil); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "cannot start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		wtv := sdk.WorkflowTemplateVersion{
+			WorkflowTemplateID: old.ID,
+			Version:            version.String(),
+			Tag:                req.Tag,
+			Template:           clone,
+			Author:             consumer.GetUsername(),
+			Created:            time.Now(),
+		}
+		if err := workflowtemplate.InsertVersion(tx, &wtv); err != nil {
+			return err
+		}
+
+		// the template itself follows the greatest published version
+		latest, err := sdk.LatestWorkflowTemplateVersion(append(versions, wtv), "")
+		if err != nil {
+			return err
+		}
+		isLatest := latest.Version == wtv.Version
+		if isLatest {
+			if err := workflowtemplate.Update(tx, &clone); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "cannot commit transaction")
+		}
+
+		if isLatest {
+			newTemplate, err := workflowtemplate.LoadByID(ctx, api.mustDB(), clone.ID, workflowtemplate.LoadOptions.Default)
+			if err != nil {
+				return err
+			}
+			event.PublishWorkflowTemplateUpdate(ctx, *old, *newTemplate, fmt.Sprintf("Publish version %s from tag %s", wtv.Version, wtv.Tag), consumer)
+		}
+
+		return service.WriteJSON(w, wtv, http.StatusOK)
+	}
+}
+
+func (api *API) getTemplateVersionsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		groupName := vars["permGroupName"]
+		templateSlug := vars["permTemplateSlug"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), groupName)
+		if err != nil {
+			return err
+		}
+
+		wt, err := workflowtemplate.LoadBySlugAndGroupID(ctx, api.mustDB(), templateSlug, g.ID)
+		if err != nil {
+			return err
+		}
+
+		versions, err := workflowtemplate.LoadVersionsByTemplateID(ctx, api.mustDB(), wt.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, versions, http.StatusOK)
+	}
+}
+
+func (api *API) getTemplateUpgradesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		groupName := vars["permGroupName"]
+		templateSlug := vars["permTemplateSlug"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), groupName)
+		if err != nil {
+			return err
+		}
+
+		wt, err := workflowtemplate.LoadBySlugAndGroupID(ctx, api.mustDB(), templateSlug, g.ID)
+		if err != nil {
+			return err
+		}
+
+		var versions []sdk.WorkflowTemplateVersion
+		if wt.IsFromRepository() {
+			versions, err = workflowtemplate.LoadVersionsByTemplateID(ctx, api.mustDB(), wt.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		ps, err := project.LoadAll(ctx, api.mustDB(), api.Cache)
+		if err != nil {
+			return err
+		}
+
+		is, err := workflowtemplate.GetInstancesByTemplateIDAndProjectIDs(api.mustDB(), wt.ID, sdk.ProjectsToIDs(ps))
+		if err != nil {
+			return err
+		}
+
+		mProjects := make(map[int64]sdk.Project, len(ps))
+		for i := range ps {
+			mProjects[ps[i].ID] = ps[i]
+		}
+		isPointers := make([]*sdk.WorkflowTemplateInstance, len(is))
+		for i := range is {
+			p := mProjects[is[i].ProjectID]
+			is[i].Project = &p
+			isPointers[i] = &is[i]
+		}
+		if err := workflow.AggregateOnWorkflowTemplateInstance(api.mustDB(), isPointers...); err != nil {
+			return err
+		}
+
+		upgrades := make([]sdk.WorkflowTemplateInstanceUpgrade, len(is))
+		for i := range is {
+			upgrades[i] = sdk.NewWorkflowTemplateInstanceUpgrade(*wt, versions, is[i])
+		}
+
+		return service.WriteJSON(w, upgrades, http.StatusOK)
+	}
+}
+
+// getTemplateForRequest returns the template to apply for given request. For a template maintained in a repository
+// it is the greatest published version that matches the requested range.
+func (api *API) getTemplateForRequest(ctx context.Context, wt *sdk.WorkflowTemplate, req sdk.WorkflowTemplateRequest) (*sdk.WorkflowTemplate, error) {
+	if !wt.IsFromRepository() {
+		if req.Version != "" {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "template %s is not versioned, version %s can't be requested", wt.Slug, req.Version)
+		}
+		return wt, nil
+	}
+
+	versions, err := workflowtemplate.LoadVersionsByTemplateID(ctx, api.mustDB(), wt.ID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := sdk.LatestWorkflowTemplateVersion(versions, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	res := v.Template
+	res.ID = wt.ID
+	res.GroupID = wt.GroupID
+	res.Group = wt.Group
+	res.Slug = wt.Slug
+	res.Version = wt.Version
+	res.SemVer = v.Version
+	return &res, nil
+}
+
+// checkTemplateRepositoryPermissions checks that the consumer can write on the project of a template repository,
+// as the VCS credentials and the keys of this project are used to read the template files.
+func (api *API) checkTemplateRepositoryPermissions(ctx context.Context, repo sdk.WorkflowTemplateRepository) error {
+	if repo.ProjectKey == "" || isAdmin(ctx) {
+		return nil
+	}
+	if err := api.checkProjectPermissions(ctx, repo.ProjectKey, sdk.PermissionReadWriteExecute, nil); err != nil {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "write permission on project %s required to use its repository for a template", repo.ProjectKey)
+	}
+	return nil
+}
+
+// readTemplateFromRepository checks out given tag of the template repository and reads the template files.
+func (api *API) readTemplateFromRepository(ctx context.Context, u sdk.Identifiable, repo sdk.WorkflowTemplateRepository, tag string) (sdk.WorkflowTemplate, error) {
+	var wt sdk.WorkflowTemplate
+
+	p, err := project.Load(api.mustDB(), api.Cache, repo.ProjectKey, project.LoadOptions.WithClearKeys)
+	if err != nil {
+		return wt, sdk.WrapError(err, "cannot load project %s", repo.ProjectKey)
+	}
+	app, err := application.LoadByName(api.mustDB(), api.Cache, repo.ProjectKey, repo.ApplicationName)
+	if err != nil {
+		return wt, sdk.WrapError(err, "cannot load application %s", repo.ApplicationName)
+	}
+	if app.VCSServer == "" || app.RepositoryFullname == "" {
+		return wt, sdk.NewErrorFrom(sdk.ErrRepoNotFound, "application %s is not linked to a repository", app.Name)
+	}
+
+	vcsServer := repositoriesmanager.GetProjectVCSServer(p, app.VCSServer)
+	client, err := repositoriesmanager.AuthorizedClient(ctx, api.mustDB(), api.Cache, p.Key, vcsServer)
+	if err != nil {
+		return wt, sdk.NewErrorWithStack(err, sdk.ErrNoReposManagerClientAuth)
+	}
+	r, err := client.RepoByFullname(ctx, app.RepositoryFullname)
+	if err != nil {
+		return wt, sdk.WrapError(err, "cannot get repo %s", app.RepositoryFullname)
+	}
+
+	if app.RepositoryStrategy.SSHKey == "" {
+		if err := application.DecryptVCSStrategyPassword(app); err != nil {
+			return wt, sdk.WrapError(err, "unable to decrypt vcs strategy")
+		}
+	}
+
+	ope := sdk.Operation{
+		VCSServer:          app.VCSServer,
+		RepoFullName:       app.RepositoryFullname,
+		URL:                r.HTTPCloneURL,
+		RepositoryStrategy: app.RepositoryStrategy,
+		Setup: sdk.OperationSetup{
+			Checkout: sdk.OperationCheckout{Tag: tag},
+		},
+		LoadFiles: sdk.OperationLoadFiles{
+			Pattern: repo.Path + "/*.yml",
+		},
+		User: sdk.User{
+			Username: u.GetUsername(),
+			Email:    u.GetEmail(),
+		},
+	}
+	if app.RepositoryStrategy.ConnectionType == "ssh" {
+		ope.URL = r.SSHCloneURL
+	}
+
+	if err := workflow.PostRepositoryOperation(ctx, api.mustDB(), *p, &ope, nil); err != nil {
+		return wt, sdk.WrapError(err, "cannot create repository operation")
+	}
+	if err := workflow.PollRepositoryOperation(ctx, api.mustDB(), api.Cache, &ope); err != nil {
+		return wt, sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot read template files at tag %s: %v", tag, sdk.Cause(err))
+	}
+
+	tr, err := workflow.ReadCDSFiles(ope.LoadFiles.Results)
+	if err != nil {
+		return wt, sdk.WrapError(err, "unable to read template files")
+	}
+	return ReadFromTar(tr)
+}
+
+// pushTemplateAsCode opens a pull request with the files generated by a template on the repository of an as code
+// workflow and returns the link of the pull request.
+func (api *API) pushTemplateAsCode(ctx context.Context, u sdk.Identifiable, projectKey string, wf *sdk.Workflow, wt *sdk.WorkflowTemplate, files io.Reader) (string, error) {
+	p, err := project.Load(api.mustDB(), api.Cache, projectKey, project.LoadOptions.WithClearKeys)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot load project %s", projectKey)
+	}
+
+	version := wt.SemVer
+	if version == "" {
+		version = fmt.Sprintf("%d", wt.Version)
+	}
+	message := fmt.Sprintf("chore: Upgrade workflow %s to template %s/%s %s [@%s]", wf.Name, wt.Group.Name, wt.Slug, version, u.GetUsername())
+
+	ope, err := workflow.PushAsCode(ctx, api.mustDB(), api.Cache, p, wf, u, files, message)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot push generated workflow on repository")
+	}
+	workflow.UpdateWorkflowAsCodeResult(ctx, api.mustDB(), api.Cache, p, ope, wf, u)
+	if ope.Status == sdk.OperationStatusError {
+		return "", sdk.NewErrorFrom(sdk.ErrUnknownError, "cannot open pull request: %s", ope.Error)
+	}
+
+	return ope.Setup.Push.PRLink, nil
+}
+
+// setTemplateInstancePendingPullRequest keeps the pull request that proposes the pending version of an instance,
+// the instance is upgraded when the as code events of the workflow are synchronized after the merge.
+func (api *API) setTemplateInstancePendingPullRequest(p *sdk.Project, wt *sdk.WorkflowTemplate, req sdk.WorkflowTemplateRequest, prLink string) error {
+	wtis, err := workflowtemplate.GetInstancesByTemplateIDAndProjectIDAndRequestWorkflowName(api.mustDB(), wt.ID, p.ID, req.WorkflowName)
+	if err != nil {
+		return err
+	}
+	for i := range wtis {
+		if wtis[i].Pending == nil {
+			continue
+		}
+		wtis[i].Pending.PullRequestURL = prLink
+		if err := workflowtemplate.UpdateInstance(api.mustDB(), &wtis[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ReadFromTar returns a workflow template from given tar reader.
 func ReadFromTar(tr *tar.Reader) (sdk.WorkflowTemplate, error) {
 	var wt sdk.WorkflowTemplate
@@ -159,3 +159,72 @@ jobs:
 
 	assert.Equal(t, 2, len(result.Operations))
 }
+
+func Test_postTemplateApplyHandlerWithVersion(t *testing.T) {
+	api, db, _, end := newTestAPI(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	_, jwt := assets.InsertAdminUser(t, api.mustDB())
+	g, err := group.LoadByName(context.TODO(), api.mustDB(), "shared.infra")
+	assert.NoError(t, err)
+
+	proj := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+
+	name := sdk.RandomString(10)
+	template := &sdk.WorkflowTemplate{
+		GroupID:    g.ID,
+		Name:       name,
+		Slug:       slug.Convert(name),
+		Repository: sdk.WorkflowTemplateRepository{ProjectKey: proj.Key, ApplicationName: "templates", Path: "template"},
+	}
+	assert.NoError(t, workflowtemplate.Insert(db, template))
+
+	// each published version generates a workflow with a different description
+	for _, v := range []string{"1.0.0", "1.0.1", "1.1.0"} {
+		snapshot := *template
+		snapshot.SemVer = v
+		snapshot.Workflow = base64.StdEncoding.EncodeToString([]byte(
+			`name: [[.name]]
+version: v1.0
+description: ` + v + `
+workflow:
+  Node-1:
+    pipeline: ` + name,
+		))
+		assert.NoError(t, workflowtemplate.InsertVersion(db, &sdk.WorkflowTemplateVersion{
+			WorkflowTemplateID: template.ID,
+			Version:            v,
+			Tag:                "v" + v,
+			Template:           snapshot,
+		}))
+	}
+
+	uri := api.Router.GetRoute("POST", api.postTemplateApplyHandler, map[string]string{
+		"permGroupName":    g.Name,
+		"permTemplateSlug": template.Slug,
+	})
+	test.NotEmpty(t, uri)
+
+	wtr := sdk.WorkflowTemplateRequest{
+		ProjectKey:   proj.Key,
+		WorkflowName: sdk.RandomString(10),
+		Version:      "~1.0",
+	}
+	req := assets.NewJWTAuthentifiedRequest(t, jwt, "POST", uri, wtr)
+	rec := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+
+	wti, err := workflowtemplate.GetInstanceByWorkflowNameAndTemplateIDAndProjectID(db, wtr.WorkflowName, template.ID, proj.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, wti) {
+		assert.Equal(t, "1.0.1", wti.WorkflowTemplateSemVer)
+	}
+
+	// no version matches the range
+	wtr.Version = "2"
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, "POST", uri, wtr)
+	rec = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 404, rec.Code)
+}
@@ -149,6 +149,10 @@ func (api *API) getWorkflowHandler() service.Handler {
 
 		//We filter project and workflow configurtaion key, because they are always set on insertHooks
 		w1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		// Webhook secrets are only readable by users who can edit the workflow
+		if !w1.Permissions.Writable {
+			w1.FilterHooksConfig(sdk.HookConfigWebHookSecret)
+		}
 		return service.WriteJSON(w, w1, http.StatusOK)
 	}
 }
@@ -615,13 +619,22 @@ func (api *API) deleteWorkflowHandler() service.Handler {
 					return
 				}
 
+				// step logs stored in the objectstore are removed once the runs are deleted with the workflow
+				nodeRunIDs, err := workflow.LoadWorkflowNodeRunIDsWithLogChunks(txg, oldW.ID)
+				if err != nil {
+					log.Error(ctx, "deleteWorkflowHandler> unable to load node runs with logs: %v", err)
+					return
+				}
+
 				if err := workflow.Delete(context.Background(), txg, api.Cache, p, oldW); err != nil {
 					log.Error(ctx, "deleteWorkflowHandler> unable to delete workflow: %v", err)
 					return
 				}
 				if err := txg.Commit(); err != nil {
 					log.Error(ctx, "deleteWorkflowHandler> Cannot commit transaction: %v", err)
+					return
 				}
+				workflow.DeleteNodeRunLogsContainers(ctx, nodeRunIDs)
 				event.PublishWorkflowDelete(ctx, key, *oldW, getAPIConsumer((ctx)))
 			}, api.PanicDump())
 
@@ -701,6 +714,11 @@ func (api *API) getWorkflowHookHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to get hook %s task and executions", uuid)
 		}
 
+		delete(task.Config, sdk.HookConfigWebHookSecret)
+		for i := range task.Executions {
+			delete(task.Executions[i].Config, sdk.HookConfigWebHookSecret)
+		}
+
 		return service.WriteJSON(w, task, http.StatusOK)
 	}
 }
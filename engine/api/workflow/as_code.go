@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -13,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflowtemplate"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/log"
@@ -22,6 +24,29 @@ var CacheOperationKey = cache.Key("repositories", "operation", "push")
 
 // UpdateAsCode does a workflow pull and start an operation to push cds files into the git repository
 func UpdateAsCode(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Project, wf *sdk.Workflow, u sdk.Identifiable, encryptFunc sdk.EncryptFunc) (*sdk.Operation, error) {
+	// Export workflow
+	pull, err := Pull(ctx, db, store, proj, wf.Name, exportentities.FormatYAML, encryptFunc, exportentities.WorkflowSkipIfOnlyOneRepoWebhook)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot pull workflow")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := pull.Tar(ctx, buf); err != nil {
+		return nil, sdk.WrapError(err, "cannot tar pulled workflow")
+	}
+
+	var message string
+	if wf.FromRepository == "" {
+		message = fmt.Sprintf("feat: Enable workflow as code [@%s]", u.GetUsername())
+	} else {
+		message = fmt.Sprintf("chore: Update workflow [@%s]", u.GetUsername())
+	}
+
+	return PushAsCode(ctx, db, store, proj, wf, u, buf, message)
+}
+
+// PushAsCode starts an operation to push given tar of cds files on a new branch of the workflow's repository
+func PushAsCode(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Project, wf *sdk.Workflow, u sdk.Identifiable, files io.Reader, message string) (*sdk.Operation, error) {
 	// Get repository
 	if wf.WorkflowData.Node.Context == nil || wf.WorkflowData.Node.Context.ApplicationID == 0 {
 		return nil, sdk.WithStack(sdk.ErrApplicationNotFound)
@@ -42,17 +67,6 @@ func UpdateAsCode(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *
 		return nil, sdk.WrapError(errR, "cannot get repo %s", app.RepositoryFullname)
 	}
 
-	// Export workflow
-	pull, err := Pull(ctx, db, store, proj, wf.Name, exportentities.FormatYAML, encryptFunc, exportentities.WorkflowSkipIfOnlyOneRepoWebhook)
-	if err != nil {
-		return nil, sdk.WrapError(err, "cannot pull workflow")
-	}
-
-	buf := new(bytes.Buffer)
-	if err := pull.Tar(ctx, buf); err != nil {
-		return nil, sdk.WrapError(err, "cannot tar pulled workflow")
-	}
-
 	var vcsStrategy = app.RepositoryStrategy
 
 	if vcsStrategy.SSHKey != "" {
@@ -77,7 +91,7 @@ func UpdateAsCode(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *
 		Setup: sdk.OperationSetup{
 			Push: sdk.OperationPush{
 				FromBranch: fmt.Sprintf("cdsAsCode-%d", time.Now().Unix()),
-				Message:    "",
+				Message:    message,
 			},
 		},
 		User: sdk.User{
@@ -92,14 +106,8 @@ func UpdateAsCode(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *
 		ope.URL = repo.HTTPCloneURL
 	}
 
-	if wf.FromRepository == "" {
-		ope.Setup.Push.Message = fmt.Sprintf("feat: Enable workflow as code [@%s]", u.GetUsername())
-	} else {
-		ope.Setup.Push.Message = fmt.Sprintf("chore: Update workflow [@%s]", u.GetUsername())
-	}
-
 	multipartData := &services.MultiPartData{
-		Reader:      buf,
+		Reader:      files,
 		ContentType: "application/tar",
 	}
 	if err := PostRepositoryOperation(ctx, db, *proj, &ope, multipartData); err != nil {
@@ -110,7 +118,7 @@ func UpdateAsCode(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *
 		log.Error(ctx, "cannot SetWithTTL: %s: %v", k, err)
 	}
 
-	log.Debug("workflow.UpdateAsCode> ope: %+v", ope)
+	log.Debug("workflow.PushAsCode> ope: %+v", ope)
 
 	return &ope, nil
 }
@@ -165,6 +173,9 @@ func SyncAsCodeEvent(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 		}
 		// If event ended, delete it from db
 		if merged || closed {
+			if err := syncTemplateInstancesPending(ctx, db, proj, event, merged, u); err != nil {
+				return err
+			}
 			if err := deleteAsCodeEvent(db, event); err != nil {
 				return err
 			}
@@ -178,6 +189,28 @@ func SyncAsCodeEvent(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 	return nil
 }
 
+// syncTemplateInstancesPending upgrades the template instances to the version proposed in a merged pull request,
+// the pending version is dropped if the pull request was closed without being merged.
+func syncTemplateInstancesPending(ctx context.Context, db gorp.SqlExecutor, proj *sdk.Project, asCodeEvent sdk.AsCodeEvent, merged bool, u sdk.Identifiable) error {
+	wtis, err := workflowtemplate.GetInstancesByProjectIDAndPendingPullRequestURL(db, proj.ID, asCodeEvent.PullRequestURL)
+	if err != nil {
+		return err
+	}
+	for i := range wtis {
+		old := sdk.WorkflowTemplateInstance(wtis[i])
+		if merged {
+			wtis[i].ApplyPending()
+		} else {
+			wtis[i].Pending = nil
+		}
+		if err := workflowtemplate.UpdateInstance(db, &wtis[i]); err != nil {
+			return err
+		}
+		event.PublishWorkflowTemplateInstanceUpdate(ctx, old, wtis[i], u)
+	}
+	return nil
+}
+
 // UpdateWorkflowAsCodeResult pulls repositories operation and the create pullrequest + update workflow
 func UpdateWorkflowAsCodeResult(ctx context.Context, db *gorp.DbMap, store cache.Store, p *sdk.Project, ope *sdk.Operation, wf *sdk.Workflow, u sdk.Identifiable) {
 	counter := 0
@@ -23,6 +23,7 @@ import (
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -146,12 +147,13 @@ func (w *Workflow) PostInsert(db gorp.SqlExecutor) error {
 // PostGet is a db hook
 func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	var res = struct {
-		Metadata     sql.NullString `db:"metadata"`
-		PurgeTags    sql.NullString `db:"purge_tags"`
-		WorkflowData sql.NullString `db:"workflow_data"`
+		Metadata        sql.NullString `db:"metadata"`
+		PurgeTags       sql.NullString `db:"purge_tags"`
+		RetentionPolicy sql.NullString `db:"retention_policy"`
+		WorkflowData    sql.NullString `db:"workflow_data"`
 	}{}
 
-	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
+	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, retention_policy, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
 		return sdk.WrapError(err, "PostGet> Unable to load marshalled workflow")
 	}
 
@@ -167,10 +169,24 @@ func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	}
 	w.PurgeTags = purgeTags
 
+	if res.RetentionPolicy.Valid {
+		w.RetentionPolicy = &sdk.WorkflowRetentionPolicy{}
+		if err := gorpmapping.JSONNullString(res.RetentionPolicy, w.RetentionPolicy); err != nil {
+			return err
+		}
+	}
+
 	data := &sdk.WorkflowData{}
 	if err := gorpmapping.JSONNullString(res.WorkflowData, data); err != nil {
 		return sdk.WrapError(err, "Unable to unmarshall workflow data")
 	}
+	for _, n := range data.Array() {
+		for i := range n.Hooks {
+			if err := decryptHookSecret(n.Hooks[i].Config); err != nil {
+				return sdk.WrapError(err, "cannot decrypt secret of hook %s", n.Hooks[i].UUID)
+			}
+		}
+	}
 	if data.Node.ID != 0 {
 		w.WorkflowData = data
 	}
@@ -212,11 +228,23 @@ func (w *Workflow) PostUpdate(db gorp.SqlExecutor) error {
 		return errPt
 	}
 
-	data, errD := gorpmapping.JSONToNullString(w.WorkflowData)
+	wfData, err := encryptHooksSecrets(w.WorkflowData)
+	if err != nil {
+		return err
+	}
+	data, errD := gorpmapping.JSONToNullString(wfData)
 	if errD != nil {
 		return sdk.WrapError(errD, "Workflow.PostUpdate> Unable to marshall workflow data")
 	}
-	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3 where id = $2", pt, w.ID, data); err != nil {
+	var retentionPolicy sql.NullString
+	if w.RetentionPolicy != nil {
+		var err error
+		retentionPolicy, err = gorpmapping.JSONToNullString(w.RetentionPolicy)
+		if err != nil {
+			return sdk.WrapError(err, "Workflow.PostUpdate> Unable to marshall retention policy")
+		}
+	}
+	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3, retention_policy = $4 where id = $2", pt, w.ID, data, retentionPolicy); err != nil {
 		return err
 	}
 
@@ -229,6 +257,49 @@ func (w *Workflow) PostUpdate(db gorp.SqlExecutor) error {
 	return nil
 }
 
+// encryptHooksSecrets returns a copy of the workflow data with the webhook secrets encrypted. The workflow data is
+// returned as is if it has no secret to encrypt.
+func encryptHooksSecrets(data *sdk.WorkflowData) (*sdk.WorkflowData, error) {
+	if !hasHooksSecrets(data) {
+		return data, nil
+	}
+
+	btes, err := json.Marshal(data)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	var res sdk.WorkflowData
+	if err := json.Unmarshal(btes, &res); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	for _, n := range res.Array() {
+		for i := range n.Hooks {
+			s, has := n.Hooks[i].Config[sdk.HookConfigWebHookSecret]
+			if !has || s.Value == "" || secret.IsEncryptedValue(s.Value) {
+				continue
+			}
+			s.Value, err = secret.EncryptValue(s.Value)
+			if err != nil {
+				return nil, sdk.WrapError(err, "cannot encrypt secret of hook %s", n.Hooks[i].UUID)
+			}
+			n.Hooks[i].Config[sdk.HookConfigWebHookSecret] = s
+		}
+	}
+	return &res, nil
+}
+
+// hasHooksSecrets returns true if a hook of the workflow data has a webhook secret
+func hasHooksSecrets(data *sdk.WorkflowData) bool {
+	for _, n := range data.Array() {
+		for _, h := range n.Hooks {
+			if h.Config[sdk.HookConfigWebHookSecret].Value != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // LoadAll loads all workflows for a project. All users in a project can list all workflows in a project
 func LoadAll(db gorp.SqlExecutor, projectKey string) (sdk.Workflows, error) {
 	res := sdk.Workflows{}
@@ -1109,6 +1180,12 @@ func IsValid(ctx context.Context, store cache.Store, db gorp.SqlExecutor, w *sdk
 		return err
 	}
 
+	if w.RetentionPolicy != nil {
+		if err := w.RetentionPolicy.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	nodesArray := w.WorkflowData.Array()
 	for i := range nodesArray {
 		n := nodesArray[i]
@@ -58,8 +58,8 @@ func insertNodeContextData(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) er
 	}
 
 	for _, cond := range n.Context.Conditions.PlainConditions {
-		if _, ok := sdk.WorkflowConditionsOperators[cond.Operator]; !ok {
-			return sdk.ErrWorkflowConditionBadOperator
+		if err := cond.IsValid(); err != nil {
+			return err
 		}
 	}
 
@@ -71,6 +71,15 @@ func insertNodeContextData(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) er
 
 	tempContext.Mutex = n.Context.Mutex
 
+	if n.Context.Concurrency != nil {
+		if err := n.Context.Concurrency.IsValid(); err != nil {
+			return err
+		}
+		if n.Context.Concurrency.Scope == sdk.ConcurrencyScopeEnvironment && n.Context.EnvironmentID == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWorkflowConcurrencyInvalid, "concurrency group %s of node %s is scoped to an environment but the node has no environment", n.Context.Concurrency.Group, n.Name)
+		}
+	}
+
 	if n.Context.PipelineID != 0 {
 		//Checks pipeline parameters
 		if len(n.Context.DefaultPipelineParameters) > 0 {
@@ -5,9 +5,53 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
 
+// PreInsert is a db hook, the webhook secret is encrypted in a copy of the config of the hook
+func (h *dbNodeHookData) PreInsert(db gorp.SqlExecutor) error {
+	s, has := h.Config[sdk.HookConfigWebHookSecret]
+	if !has || s.Value == "" || secret.IsEncryptedValue(s.Value) {
+		return nil
+	}
+	var err error
+	s.Value, err = secret.EncryptValue(s.Value)
+	if err != nil {
+		return sdk.WrapError(err, "cannot encrypt secret of hook %s", h.UUID)
+	}
+	h.Config = h.Config.Clone()
+	h.Config[sdk.HookConfigWebHookSecret] = s
+	return nil
+}
+
+// PreUpdate is a db hook
+func (h *dbNodeHookData) PreUpdate(db gorp.SqlExecutor) error {
+	return h.PreInsert(db)
+}
+
+// PostGet is a db hook
+func (h *dbNodeHookData) PostGet(db gorp.SqlExecutor) error {
+	return decryptHookSecret(h.Config)
+}
+
+// decryptHookSecret decrypts the webhook secret of a hook config loaded from database.
+// Secrets stored in clear by previous versions are kept as is.
+func decryptHookSecret(cfg sdk.WorkflowNodeHookConfig) error {
+	s, has := cfg[sdk.HookConfigWebHookSecret]
+	if !has || !secret.IsEncryptedValue(s.Value) {
+		return nil
+	}
+	var err error
+	s.Value, err = secret.DecryptValue(s.Value)
+	if err != nil {
+		return sdk.WrapError(err, "cannot decrypt webhook secret")
+	}
+	cfg[sdk.HookConfigWebHookSecret] = s
+	return nil
+}
+
 // CountRepositoryWebHooksByApplication count repository webhooks by application id
 func CountRepositoryWebHooksByApplication(db gorp.SqlExecutor, appID int64) (int64, error) {
 	query := `
@@ -92,3 +136,50 @@ func insertNodeHookData(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) error
 	}
 	return nil
 }
+
+// EncryptWebHookSecrets encrypts the webhook secrets stored in clear by previous versions, in the hooks and in the
+// data of the workflows. It returns the number of updated hooks and workflows.
+func EncryptWebHookSecrets(db gorp.SqlExecutor) (int, error) {
+	var hooks []dbNodeHookData
+	if _, err := db.Select(&hooks, "SELECT * FROM w_node_hook WHERE config::text LIKE '%"+sdk.HookConfigWebHookSecret+"%'"); err != nil {
+		return 0, sdk.WrapError(err, "cannot load hooks")
+	}
+	var n int
+	for i := range hooks {
+		// secrets are decrypted when loaded and encrypted again when saved
+		if _, err := db.Update(&hooks[i]); err != nil {
+			return n, sdk.WrapError(err, "cannot update hook %s", hooks[i].UUID)
+		}
+		n++
+	}
+
+	var workflows []struct {
+		ID           int64          `db:"id"`
+		WorkflowData sql.NullString `db:"workflow_data"`
+	}
+	if _, err := db.Select(&workflows, "SELECT id, workflow_data FROM workflow WHERE workflow_data::text LIKE '%"+sdk.HookConfigWebHookSecret+"%'"); err != nil {
+		return n, sdk.WrapError(err, "cannot load workflows")
+	}
+	for _, w := range workflows {
+		var data sdk.WorkflowData
+		if err := gorpmapping.JSONNullString(w.WorkflowData, &data); err != nil {
+			return n, sdk.WrapError(err, "cannot unmarshal data of workflow %d", w.ID)
+		}
+		encrypted, err := encryptHooksSecrets(&data)
+		if err != nil {
+			return n, err
+		}
+		if encrypted == &data {
+			continue
+		}
+		s, err := gorpmapping.JSONToNullString(encrypted)
+		if err != nil {
+			return n, sdk.WrapError(err, "cannot marshal data of workflow %d", w.ID)
+		}
+		if _, err := db.Exec("UPDATE workflow SET workflow_data = $1 WHERE id = $2", s, w.ID); err != nil {
+			return n, sdk.WrapError(err, "cannot update workflow %d", w.ID)
+		}
+		n++
+	}
+	return n, nil
+}
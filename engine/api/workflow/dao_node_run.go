@@ -222,6 +222,22 @@ func LoadNodeRunByID(db gorp.SqlExecutor, id int64, loadOpts LoadRunOptions) (*s
 		r.StaticFiles = staticFiles
 	}
 
+	if loadOpts.WithCoverage {
+		cov, errCov := LoadCoverageReport(db, r.ID)
+		if errCov != nil && !sdk.ErrorIs(errCov, sdk.ErrNotFound) {
+			return nil, sdk.WrapError(errCov, "LoadNodeRunByID>Error loading coverage for run %d", r.ID)
+		}
+		r.Coverage = cov
+	}
+
+	if loadOpts.WithVulnerabilities {
+		vuln, errV := loadVulnerabilityReport(db, r.ID)
+		if errV != nil && !sdk.ErrorIs(errV, sdk.ErrNotFound) {
+			return nil, sdk.WrapError(errV, "LoadNodeRunByID>Error loading vulnerability report for run %d", r.ID)
+		}
+		r.VulnerabilitiesReport = vuln
+	}
+
 	return r, nil
 
 }
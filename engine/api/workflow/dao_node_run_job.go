@@ -286,6 +286,35 @@ func LoadDeadNodeJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.St
 	return deadJobs, nil
 }
 
+// loadTimedOutNodeJobRuns returns the building jobs that are running for longer than their timeout and the given
+// grace period, the grace period lets the worker fail the job by itself.
+func loadTimedOutNodeJobRuns(db gorp.SqlExecutor, gracePeriod time.Duration) ([]sdk.WorkflowNodeJobRun, error) {
+	var jobsDB []JobRun
+	query := `SELECT workflow_node_run_job.* FROM workflow_node_run_job WHERE status = $1 AND COALESCE(job->'action'->>'timeout', '') <> ''`
+	if _, err := db.Select(&jobsDB, query, sdk.StatusBuilding); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WithStack(err)
+	}
+
+	var jobs []sdk.WorkflowNodeJobRun
+	for i := range jobsDB {
+		jr, err := jobsDB[i].WorkflowNodeRunJob()
+		if err != nil {
+			return nil, err
+		}
+		timeout, err := sdk.ParseActionTimeout(jr.Job.Action.Timeout)
+		if err != nil || timeout == 0 {
+			continue
+		}
+		if time.Since(jr.Start) > timeout+gracePeriod {
+			jobs = append(jobs, jr)
+		}
+	}
+	return jobs, nil
+}
+
 //LoadAndLockNodeJobRunWait load for update a NodeJobRun given its ID
 func LoadAndLockNodeJobRunWait(ctx context.Context, db gorp.SqlExecutor, store cache.Store, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	j := JobRun{}
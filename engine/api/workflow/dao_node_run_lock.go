@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbNodeRunLock sdk.WorkflowNodeRunLock
+
+func getNodeRunLocks(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.WorkflowNodeRunLock, error) {
+	var dbLocks []dbNodeRunLock
+	if err := gorpmapping.GetAll(ctx, db, q, &dbLocks); err != nil {
+		return nil, sdk.WrapError(err, "cannot load node run locks")
+	}
+	locks := make([]sdk.WorkflowNodeRunLock, len(dbLocks))
+	for i := range dbLocks {
+		locks[i] = sdk.WorkflowNodeRunLock(dbLocks[i])
+	}
+	return locks, nil
+}
+
+func getNodeRunLock(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.WorkflowNodeRunLock, error) {
+	var dbLock dbNodeRunLock
+	found, err := gorpmapping.Get(ctx, db, q, &dbLock)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load node run lock")
+	}
+	if !found {
+		return nil, nil
+	}
+	lock := sdk.WorkflowNodeRunLock(dbLock)
+	return &lock, nil
+}
+
+// LoadNodeRunLocksByProjectID returns the node runs holding or waiting for the concurrency groups of a project
+func LoadNodeRunLocksByProjectID(ctx context.Context, db gorp.SqlExecutor, projectID int64) ([]sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_lock
+		WHERE project_id = $1
+		ORDER BY lock_key, id`).Args(projectID)
+	return getNodeRunLocks(ctx, db, query)
+}
+
+// LoadNodeRunLocksByWorkflowRunID returns the locks held or awaited by the node runs of a workflow run,
+// with the holder of the groups awaited by the run
+func LoadNodeRunLocksByWorkflowRunID(ctx context.Context, db gorp.SqlExecutor, workflowRunID int64) ([]sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_lock
+		WHERE workflow_run_id = $1
+		ORDER BY id`).Args(workflowRunID)
+	locks, err := getNodeRunLocks(ctx, db, query)
+	if err != nil {
+		return nil, err
+	}
+	for i := range locks {
+		if locks[i].Status != sdk.ConcurrencyLockPending {
+			continue
+		}
+		locks[i].Holder, err = loadNodeRunLockHolder(ctx, db, locks[i].Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return locks, nil
+}
+
+// lockConcurrencyGroup serializes the transactions that acquire or release the lock of a concurrency group,
+// the advisory lock is released at the end of the transaction.
+func lockConcurrencyGroup(db gorp.SqlExecutor, key string) error {
+	if _, err := db.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", key); err != nil {
+		return sdk.WrapError(err, "cannot lock concurrency group %s", key)
+	}
+	return nil
+}
+
+func loadNodeRunLockByNodeRunID(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) (*sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`SELECT * FROM workflow_node_run_lock WHERE workflow_node_run_id = $1`).Args(nodeRunID)
+	return getNodeRunLock(ctx, db, query)
+}
+
+func loadNodeRunLockHolder(ctx context.Context, db gorp.SqlExecutor, key string) (*sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`SELECT * FROM workflow_node_run_lock WHERE lock_key = $1 AND status = $2`).Args(key, sdk.ConcurrencyLockHolding)
+	return getNodeRunLock(ctx, db, query)
+}
+
+// loadNextPendingNodeRunLock returns the oldest node run waiting for the lock
+func loadNextPendingNodeRunLock(ctx context.Context, db gorp.SqlExecutor, key string) (*sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_lock
+		WHERE lock_key = $1 AND status = $2
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`).Args(key, sdk.ConcurrencyLockPending)
+	return getNodeRunLock(ctx, db, query)
+}
+
+func loadPendingNodeRunLocks(ctx context.Context, db gorp.SqlExecutor, key string) ([]sdk.WorkflowNodeRunLock, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_lock
+		WHERE lock_key = $1 AND status = $2
+		ORDER BY id`).Args(key, sdk.ConcurrencyLockPending)
+	return getNodeRunLocks(ctx, db, query)
+}
+
+func insertNodeRunLock(db gorp.SqlExecutor, lock *sdk.WorkflowNodeRunLock) error {
+	dbLock := dbNodeRunLock(*lock)
+	if err := gorpmapping.Insert(db, &dbLock); err != nil {
+		return sdk.WrapError(err, "cannot insert lock for node run %d", lock.WorkflowNodeRunID)
+	}
+	*lock = sdk.WorkflowNodeRunLock(dbLock)
+	return nil
+}
+
+// insertNodeRunLockHolder tries to take the lock, it returns false if another node run is holding it
+func insertNodeRunLockHolder(db gorp.SqlExecutor, lock *sdk.WorkflowNodeRunLock) (bool, error) {
+	id, err := db.SelectInt(`
+		INSERT INTO workflow_node_run_lock (project_id, lock_key, status, workflow_id, workflow_name, workflow_run_id, num, workflow_node_run_id, workflow_node_name, created)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (lock_key) WHERE status = 'holding' DO NOTHING
+		RETURNING id`,
+		lock.ProjectID, lock.Key, sdk.ConcurrencyLockHolding, lock.WorkflowID, lock.WorkflowName, lock.WorkflowRunID, lock.Number, lock.WorkflowNodeRunID, lock.WorkflowNodeName, lock.Created)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot take lock %s for node run %d", lock.Key, lock.WorkflowNodeRunID)
+	}
+	if id == 0 {
+		return false, nil
+	}
+	lock.ID = id
+	lock.Status = sdk.ConcurrencyLockHolding
+	return true, nil
+}
+
+// updateNodeRunLockHolder gives the lock to a pending node run if nobody is holding it
+func updateNodeRunLockHolder(db gorp.SqlExecutor, lock *sdk.WorkflowNodeRunLock) (bool, error) {
+	res, err := db.Exec(`
+		UPDATE workflow_node_run_lock SET status = $2
+		WHERE id = $1
+		AND NOT EXISTS (SELECT 1 FROM workflow_node_run_lock WHERE lock_key = $3 AND status = $2)`,
+		lock.ID, sdk.ConcurrencyLockHolding, lock.Key)
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot give lock %s to node run %d", lock.Key, lock.WorkflowNodeRunID)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return false, nil
+	}
+	lock.Status = sdk.ConcurrencyLockHolding
+	return true, nil
+}
+
+func deleteNodeRunLock(db gorp.SqlExecutor, id int64) error {
+	if _, err := db.Exec("DELETE FROM workflow_node_run_lock WHERE id = $1", id); err != nil {
+		return sdk.WrapError(err, "cannot delete node run lock %d", id)
+	}
+	return nil
+}
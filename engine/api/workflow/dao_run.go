@@ -113,6 +113,17 @@ func (r *Run) PostInsert(db gorp.SqlExecutor) error {
 	if errw != nil {
 		return sdk.WrapError(errw, "Unable to marshal workflow")
 	}
+	// The webhook secrets are not needed by the run, they are not stored with its workflow
+	if hasHooksSecrets(r.Workflow.WorkflowData) {
+		var wf sdk.Workflow
+		if err := json.Unmarshal(w, &wf); err != nil {
+			return sdk.WrapError(err, "Unable to unmarshal workflow")
+		}
+		wf.FilterHooksConfig(sdk.HookConfigWebHookSecret)
+		if w, errw = json.Marshal(wf); errw != nil {
+			return sdk.WrapError(errw, "Unable to marshal workflow")
+		}
+	}
 
 	jtr, erri := json.Marshal(r.JoinTriggersRun)
 	if erri != nil {
@@ -723,6 +734,11 @@ func PurgeWorkflowRun(ctx context.Context, db gorp.SqlExecutor, wf sdk.Workflow,
 		Ids string `json:"ids" db:"ids"`
 	}{}
 
+	if wf.RetentionPolicy != nil {
+		log.Debug("PurgeWorkflowRun> workflow has a retention policy, skipping purge with history length")
+		return nil
+	}
+
 	if wf.HistoryLength == 0 {
 		log.Debug("PurgeWorkflowRun> history length equals 0, skipping purge")
 		return nil
@@ -0,0 +1,35 @@
+package workflow
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadRunsRetention returns the data needed to evaluate a retention policy for the runs of a workflow
+// that are not already marked to delete, sorted by number desc.
+func LoadRunsRetention(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowRunRetention, error) {
+	var runs []sdk.WorkflowRunRetention
+	query := `
+		SELECT workflow_run.id, workflow_run.num, workflow_run.status, workflow_run.last_modified,
+			COALESCE((SELECT value FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = $2), '') AS branch,
+			EXISTS(SELECT 1 FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = $3) AS tagged,
+			EXISTS(SELECT 1 FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = $4) AS released,
+			EXISTS(SELECT 1 FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = $5) AS deployed,
+			(SELECT COUNT(1) FROM workflow_node_run_artifacts WHERE workflow_run_id = workflow_run.id) AS artifacts_count,
+			(SELECT COALESCE(SUM(size), 0) FROM workflow_node_run_artifacts WHERE workflow_run_id = workflow_run.id) AS artifacts_size
+		FROM workflow_run
+		WHERE workflow_run.workflow_id = $1
+		AND workflow_run.to_delete = false
+		ORDER BY workflow_run.num DESC`
+	if _, err := db.Select(&runs, query, workflowID, tagGitBranch, tagGitTag, tagRelease, tagEnvironment); err != nil {
+		return nil, sdk.WrapError(err, "cannot load runs retention data for workflow %d", workflowID)
+	}
+	return runs, nil
+}
+
+// TagRunAsReleased adds the release tag to the workflow run, released runs can be kept by retention policies.
+func TagRunAsReleased(db gorp.SqlExecutor, wr *sdk.WorkflowRun, release string) error {
+	wr.Tag(tagRelease, release)
+	return UpdateWorkflowRunTags(db, wr)
+}
@@ -25,9 +25,18 @@ type ProcessorReport struct {
 	jobs      []sdk.WorkflowNodeJobRun
 	nodes     []sdk.WorkflowNodeRun
 	workflows []sdk.WorkflowRun
+	secrets   []resolvedSecret
 	errors    []error
 }
 
+// resolvedSecret is an external secret resolved for a job run, it is audited once the job is taken
+type resolvedSecret struct {
+	run     sdk.WorkflowRun
+	nodeRun *sdk.WorkflowNodeRun
+	name    string
+	ref     sdk.ExternalSecretReference
+}
+
 // WorkflowRuns returns the list of concerned workflow runs
 func (r *ProcessorReport) WorkflowRuns() []sdk.WorkflowRun {
 	if r == nil {
@@ -57,6 +66,8 @@ func (r *ProcessorReport) Add(ctx context.Context, i ...interface{}) {
 			r.workflows = append(r.workflows, x)
 		case *sdk.WorkflowRun:
 			r.workflows = append(r.workflows, *x)
+		case resolvedSecret:
+			r.secrets = append(r.secrets, x)
 		default:
 			log.Warning(ctx, "ProcessorReport> unknown type %T", w)
 		}
@@ -71,6 +82,7 @@ func (r *ProcessorReport) All() []interface{} {
 	res = append(res, sdk.InterfaceSlice(r.workflows)...)
 	res = append(res, sdk.InterfaceSlice(r.nodes)...)
 	res = append(res, sdk.InterfaceSlice(r.jobs)...)
+	res = append(res, sdk.InterfaceSlice(r.secrets)...)
 	res = append(res, sdk.InterfaceSlice(r.errors)...)
 	return res
 }
@@ -249,6 +261,9 @@ func TakeNodeJobRun(ctx context.Context, dbFunc func() *gorp.DbMap, db gorp.SqlE
 	if err := checkStatusWaiting(ctx, store, jobID, job.Status); err != nil {
 		return nil, report, err
 	}
+	if job.Queued.After(time.Now()) {
+		return nil, report, sdk.WrapError(sdk.ErrJobNotReady, "job %d will be retried at %s", jobID, job.Queued)
+	}
 
 	job.Model = workerModel
 	job.Job.WorkerName = workerName
@@ -398,11 +413,12 @@ func LoadNodeJobRunKeys(ctx context.Context, db gorp.SqlExecutor, p *sdk.Project
 	return params, secrets, nil
 }
 
-// LoadSecrets loads all secrets for a job run
-func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNodeRun, w *sdk.WorkflowRun, pv []sdk.Variable) ([]sdk.Variable, error) {
+// LoadSecrets loads all secrets for a job run, external secrets are resolved from their provider.
+// When a report is given, the resolved external secrets are added to it to be audited by SendEvent once the transaction is committed.
+func LoadSecrets(ctx context.Context, db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNodeRun, w *sdk.WorkflowRun, pv []sdk.Variable, report *ProcessorReport) ([]sdk.Variable, error) {
 	var secrets []sdk.Variable
 
-	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable)
+	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable, sdk.ExternalSecretVariable)
 	pv = sdk.VariablesPrefix(pv, "cds.proj.")
 	secrets = append(secrets, pv...)
 
@@ -435,7 +451,7 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 			if errA != nil {
 				return nil, sdk.WrapError(errA, "LoadSecrets> Cannot load application variables")
 			}
-			av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable)
+			av = sdk.VariablesFilter(appv, sdk.SecretVariable, sdk.KeyVariable, sdk.ExternalSecretVariable)
 			av = sdk.VariablesPrefix(av, "cds.app.")
 
 			if err := application.DecryptVCSStrategyPassword(app); err != nil {
@@ -456,7 +472,7 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 			if errE != nil {
 				return nil, sdk.WrapError(errE, "LoadSecrets> Cannot load environment variables")
 			}
-			ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable)
+			ev = sdk.VariablesFilter(envv, sdk.SecretVariable, sdk.KeyVariable, sdk.ExternalSecretVariable)
 			ev = sdk.VariablesPrefix(ev, "cds.env.")
 		}
 		secrets = append(secrets, ev...)
@@ -508,6 +524,16 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 	//Decrypt secrets
 	for i := range secrets {
 		s := &secrets[i]
+		if s.Type == sdk.ExternalSecretVariable {
+			ref, err := resolveExternalSecret(ctx, w.Workflow.ProjectKey, s)
+			if err != nil {
+				return nil, err
+			}
+			if report != nil {
+				report.Add(ctx, resolvedSecret{run: *w, nodeRun: nodeRun, name: s.Name, ref: ref})
+			}
+			continue
+		}
 		if err := secret.DecryptVariable(s); err != nil {
 			return nil, sdk.WrapError(err, "Unable to decrypt variables")
 		}
@@ -515,6 +541,21 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 	return secrets, nil
 }
 
+// resolveExternalSecret replaces the reference of an external secret variable of a project by its value
+func resolveExternalSecret(ctx context.Context, projectKey string, v *sdk.Variable) (sdk.ExternalSecretReference, error) {
+	ref, err := secret.CheckReference(projectKey, v.Value)
+	if err != nil {
+		return ref, sdk.WrapError(err, "invalid external secret %s", v.Name)
+	}
+	value, err := secret.Resolve(ctx, projectKey, v.Value)
+	if err != nil {
+		return ref, sdk.WrapError(err, "unable to resolve external secret %s", v.Name)
+	}
+	v.Value = value
+	v.Type = sdk.SecretVariable
+	return ref, nil
+}
+
 //BookNodeJobRun  Book a job for a hatchery
 func BookNodeJobRun(ctx context.Context, store cache.Store, id int64, hatchery *sdk.Service) (*sdk.Service, error) {
 	k := keyBookJob(id)
@@ -554,12 +595,16 @@ func FreeNodeJobRun(ctx context.Context, store cache.Store, id int64) error {
 }
 
 //AddLog adds a build log
-func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
+func AddLog(ctx context.Context, db *gorp.DbMap, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
 	if job != nil {
 		logs.JobID = job.ID
 		logs.NodeRunID = job.WorkflowNodeRunID
 	}
 
+	if logStorage != nil {
+		return sdk.WrapError(addLogChunk(ctx, db, logs), "cannot add log chunk")
+	}
+
 	// check if log exists without loading data but with log size
 	exists, size, err := ExistsStepLog(db, logs.JobID, logs.StepOrder)
 	if err != nil {
@@ -619,27 +664,8 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 	ctx, end = observability.Span(ctx, "workflow.RestartWorkflowNodeJob")
 	defer end()
 
-	for iS := range wNodeJob.Job.StepStatus {
-		step := &wNodeJob.Job.StepStatus[iS]
-		if step.Status == sdk.StatusNeverBuilt || step.Status == sdk.StatusSkipped || step.Status == sdk.StatusDisabled {
-			continue
-		}
-		l, errL := LoadStepLogs(db, wNodeJob.ID, int64(step.StepOrder))
-		if errL != nil {
-			return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while load step logs")
-		}
-		wNodeJob.Job.Reason = "Killed (Reason: Timeout)\n"
-		step.Status = sdk.StatusWaiting
-		step.Done = time.Time{}
-		if l != nil { // log could be nil here
-			l.Done = nil
-			logbuf := bytes.NewBufferString(l.Val)
-			logbuf.WriteString("\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n")
-			l.Val = logbuf.String()
-			if err := updateLog(db, l); err != nil {
-				return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while update step log")
-			}
-		}
+	if err := resetNodeJobRunSteps(ctx, db, &wNodeJob, "Killed (Reason: Timeout)\n", "\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n"); err != nil {
+		return err
 	}
 
 	nodeRun, errNR := LoadAndLockNodeRunByID(ctx, db, wNodeJob.WorkflowNodeRunID)
@@ -666,3 +692,48 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 
 	return nil
 }
+
+// resetNodeJobRunSteps sets the started steps of a job back to waiting and appends the separator to their logs
+func resetNodeJobRunSteps(ctx context.Context, db gorp.SqlExecutor, wNodeJob *sdk.WorkflowNodeJobRun, reason, separator string) error {
+	for iS := range wNodeJob.Job.StepStatus {
+		step := &wNodeJob.Job.StepStatus[iS]
+		if step.Status == sdk.StatusNeverBuilt || step.Status == sdk.StatusSkipped || step.Status == sdk.StatusDisabled {
+			continue
+		}
+		wNodeJob.Job.Reason = reason
+		step.Status = sdk.StatusWaiting
+		step.Done = time.Time{}
+		if logStorage != nil {
+			exists, _, err := existsStepLogChunks(db, wNodeJob.ID, int64(step.StepOrder))
+			if err != nil {
+				return sdk.WrapError(err, "RestartWorkflowNodeJob> error while load step logs")
+			}
+			if exists {
+				// the chunk is stored in the transaction of the restart, see storeLogChunk for a rollback
+				if _, err := storeLogChunk(db, &sdk.Log{
+					JobID:     wNodeJob.ID,
+					NodeRunID: wNodeJob.WorkflowNodeRunID,
+					StepOrder: int64(step.StepOrder),
+					Val:       separator,
+				}); err != nil {
+					return sdk.WrapError(err, "RestartWorkflowNodeJob> error while update step log")
+				}
+				continue
+			}
+		}
+		l, errL := LoadStepLogs(ctx, db, wNodeJob.ID, int64(step.StepOrder))
+		if errL != nil {
+			return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while load step logs")
+		}
+		if l != nil { // log could be nil here
+			l.Done = nil
+			logbuf := bytes.NewBufferString(l.Val)
+			logbuf.WriteString(separator)
+			l.Val = logbuf.String()
+			if err := updateLog(db, l); err != nil {
+				return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while update step log")
+			}
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -32,8 +33,15 @@ func ExistsStepLog(db gorp.SqlExecutor, id int64, order int64) (bool, int64, err
 }
 
 //LoadStepLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
-func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
+//If log storage is enabled, logs are loaded from the objectstore with a fallback on database for logs not moved yet
+func LoadStepLogs(ctx context.Context, db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
 	log.Debug("LoadStepLogs> workflow_node_run_job_id = %d", id)
+	if logStorage != nil {
+		logs, err := loadStepLogsFromStorage(ctx, db, id, order)
+		if err != nil || logs != nil {
+			return logs, err
+		}
+	}
 	query := `
 		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
 		FROM workflow_node_run_job_logs
@@ -0,0 +1,482 @@
+package workflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// logStorage is the objectstore driver used to store step logs, logs are stored in database if nil
+var logStorage objectstore.Driver
+
+// SetLogStorage sets the objectstore driver used to store step logs. Each call to AddLog appends
+// a compressed chunk in the objectstore, chunks are merged when the step is done and only an index
+// of the chunks is kept in database.
+func SetLogStorage(driver objectstore.Driver) {
+	logStorage = driver
+}
+
+// LogStorageEnabled returns true if step logs are stored in the objectstore.
+func LogStorageEnabled() bool {
+	return logStorage != nil
+}
+
+// logChunk is the database index of a step log chunk stored in the objectstore
+type logChunk struct {
+	ID           int64
+	JobID        int64
+	NodeRunID    int64
+	StepOrder    int64
+	Index        int64
+	Size         int64
+	Start        *time.Time
+	LastModified *time.Time
+	Done         *time.Time
+}
+
+// GetName implements objectstore.Object
+func (c logChunk) GetName() string {
+	return fmt.Sprintf("%d-%d-%d.log.gz", c.JobID, c.StepOrder, c.Index)
+}
+
+// GetPath implements objectstore.Object
+func (c logChunk) GetPath() string {
+	return logChunkContainer(c.NodeRunID)
+}
+
+func logChunkContainer(nodeRunID int64) string {
+	return fmt.Sprintf("logs-%d", nodeRunID)
+}
+
+// loadStepLogChunks returns the index of all the chunks of a step log
+func loadStepLogChunks(db gorp.SqlExecutor, jobID, stepOrder int64) ([]logChunk, error) {
+	query := `
+		SELECT id, workflow_node_run_job_id, workflow_node_run_id, step_order, chunk_index, size, start, last_modified, done
+		FROM workflow_node_run_job_log_chunk
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2
+		ORDER BY chunk_index`
+	rows, err := db.Query(query, jobID, stepOrder)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer rows.Close() // nolint
+
+	var chunks []logChunk
+	for rows.Next() {
+		var c logChunk
+		var s, m, d pq.NullTime
+		if err := rows.Scan(&c.ID, &c.JobID, &c.NodeRunID, &c.StepOrder, &c.Index, &c.Size, &s, &m, &d); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		if s.Valid {
+			c.Start = &s.Time
+		}
+		if m.Valid {
+			c.LastModified = &m.Time
+		}
+		if d.Valid {
+			c.Done = &d.Time
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
+
+// existsStepLogChunks returns the uncompressed size of step log chunks if exists.
+func existsStepLogChunks(db gorp.SqlExecutor, jobID, stepOrder int64) (bool, int64, error) {
+	query := `
+		SELECT COUNT(id), COALESCE(SUM(size), 0)
+		FROM workflow_node_run_job_log_chunk
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+	var count, size int64
+	if err := db.QueryRow(query, jobID, stepOrder).Scan(&count, &size); err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	return count > 0, size, nil
+}
+
+// nextLogChunkIndex reserves the index of the next chunk of a step log. The upsert on the per step counter
+// serializes concurrent calls for the same step, the counter starts after the chunks that already exist.
+func nextLogChunkIndex(db gorp.SqlExecutor, logs *sdk.Log) (int64, error) {
+	var index int64
+	if err := db.QueryRow(`
+		INSERT INTO workflow_node_run_job_log_chunk_index (workflow_node_run_job_id, workflow_node_run_id, step_order, last_index)
+		VALUES ($1, $2, $3, (
+			SELECT COALESCE(MAX(chunk_index) + 1, 0)
+			FROM workflow_node_run_job_log_chunk
+			WHERE workflow_node_run_job_id = $1 AND step_order = $3
+		))
+		ON CONFLICT (workflow_node_run_job_id, step_order)
+		DO UPDATE SET last_index = workflow_node_run_job_log_chunk_index.last_index + 1
+		RETURNING last_index`, logs.JobID, logs.NodeRunID, logs.StepOrder).Scan(&index); err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	return index, nil
+}
+
+// addLogChunk compresses and stores the given logs as a new chunk of the step log, the chunks of a step are
+// merged into one object when the step is done.
+func addLogChunk(ctx context.Context, db *gorp.DbMap, logs *sdk.Log) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	c, err := storeLogChunk(tx, logs)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		deleteLogChunkObjects(ctx, []logChunk{c})
+		return sdk.WithStack(err)
+	}
+
+	if logs.Done != nil {
+		return mergeStepLogChunks(ctx, db, logs)
+	}
+	return nil
+}
+
+// storeLogChunk reserves an index for the given logs, inserts the chunk in database and stores it in the objectstore.
+// Stored objects should be removed by the caller if its transaction is rolled back. If it isn't, the index is freed
+// by the rollback and the object is overridden by the next chunk of the step or removed with the container.
+func storeLogChunk(db gorp.SqlExecutor, logs *sdk.Log) (logChunk, error) {
+	index, err := nextLogChunkIndex(db, logs)
+	if err != nil {
+		return logChunk{}, err
+	}
+
+	now := time.Now()
+	c := logChunk{
+		JobID:        logs.JobID,
+		NodeRunID:    logs.NodeRunID,
+		StepOrder:    logs.StepOrder,
+		Index:        index,
+		Size:         int64(len(logs.Val)),
+		Start:        logs.Start,
+		LastModified: logs.LastModified,
+		Done:         logs.Done,
+	}
+	if c.Start == nil {
+		c.Start = &now
+	}
+	if c.LastModified == nil {
+		c.LastModified = &now
+	}
+
+	if err := insertLogChunk(db, &c); err != nil {
+		return c, err
+	}
+	if err := storeLogChunkObject(c, []byte(logs.Val)); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func insertLogChunk(db gorp.SqlExecutor, c *logChunk) error {
+	query := `
+		INSERT INTO workflow_node_run_job_log_chunk (workflow_node_run_job_id, workflow_node_run_id, step_order, chunk_index, size, start, last_modified, done)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+	return sdk.WithStack(db.QueryRow(query, c.JobID, c.NodeRunID, c.StepOrder, c.Index, c.Size, c.Start, c.LastModified, c.Done).Scan(&c.ID))
+}
+
+func storeLogChunkObject(c logChunk, value []byte) error {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	if _, err := gz.Write(value); err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := gz.Close(); err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := logStorage.Store(c, ioutil.NopCloser(buf)); err != nil {
+		return sdk.WrapError(err, "cannot store log chunk %s/%s", c.GetPath(), c.GetName())
+	}
+	return nil
+}
+
+func deleteLogChunkObjects(ctx context.Context, chunks []logChunk) {
+	for _, c := range chunks {
+		if err := logStorage.Delete(ctx, c); err != nil {
+			log.Error(ctx, "cannot delete log chunk %s/%s: %v", c.GetPath(), c.GetName(), err)
+		}
+	}
+}
+
+// mergeStepLogChunks replaces all the chunks of a step log by one object. The merged chunk takes a new index,
+// reserving it locks the step counter so the chunks sent during the merge are added after it.
+func mergeStepLogChunks(ctx context.Context, db *gorp.DbMap, logs *sdk.Log) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	index, err := nextLogChunkIndex(tx, logs)
+	if err != nil {
+		return err
+	}
+	chunks, err := loadStepLogChunks(tx, logs.JobID, logs.StepOrder)
+	if err != nil {
+		return err
+	}
+	if len(chunks) < 2 {
+		return nil
+	}
+
+	merged := logChunk{
+		JobID:     logs.JobID,
+		NodeRunID: chunks[0].NodeRunID,
+		StepOrder: logs.StepOrder,
+		Index:     index,
+		Start:     chunks[0].Start,
+	}
+	values, err := fetchLogChunks(ctx, chunks)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	for i, c := range chunks {
+		buf.Write(values[i])
+		merged.Size += c.Size
+		if c.LastModified != nil {
+			merged.LastModified = c.LastModified
+		}
+		merged.Done = c.Done
+	}
+
+	if _, err := tx.Exec("DELETE FROM workflow_node_run_job_log_chunk WHERE workflow_node_run_job_id = $1 AND step_order = $2", logs.JobID, logs.StepOrder); err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := insertLogChunk(tx, &merged); err != nil {
+		return err
+	}
+	if err := storeLogChunkObject(merged, buf.Bytes()); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		deleteLogChunkObjects(ctx, []logChunk{merged})
+		return sdk.WithStack(err)
+	}
+
+	deleteLogChunkObjects(ctx, chunks)
+	return nil
+}
+
+// maxLogChunkFetches is the number of chunks of a step log fetched at the same time
+const maxLogChunkFetches = 10
+
+// fetchLogChunks fetches and uncompresses the given chunks concurrently, values are returned in the chunks order.
+func fetchLogChunks(ctx context.Context, chunks []logChunk) ([][]byte, error) {
+	values := make([][]byte, len(chunks))
+	errs := make([]error, len(chunks))
+
+	sem := make(chan struct{}, maxLogChunkFetches)
+	var wg sync.WaitGroup
+	for i := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			values[i], errs[i] = fetchLogChunk(ctx, chunks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func fetchLogChunk(ctx context.Context, c logChunk) ([]byte, error) {
+	r, err := logStorage.Fetch(ctx, c)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot fetch log chunk %s/%s", c.GetPath(), c.GetName())
+	}
+	defer r.Close() // nolint
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot read log chunk %s/%s", c.GetPath(), c.GetName())
+	}
+	b, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot read log chunk %s/%s", c.GetPath(), c.GetName())
+	}
+	return b, nil
+}
+
+// loadStepLogsFromStorage fetches and concatenates all the chunks of a step log, returns nil if there is no chunk.
+func loadStepLogsFromStorage(ctx context.Context, db gorp.SqlExecutor, jobID, stepOrder int64) (*sdk.Log, error) {
+	chunks, err := loadStepLogChunks(db, jobID, stepOrder)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	logs := &sdk.Log{
+		ID:        chunks[0].ID,
+		JobID:     jobID,
+		NodeRunID: chunks[0].NodeRunID,
+		StepOrder: stepOrder,
+		Start:     chunks[0].Start,
+	}
+
+	values, err := fetchLogChunks(ctx, chunks)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	for i, c := range chunks {
+		buf.Write(values[i])
+		if c.LastModified != nil {
+			logs.LastModified = c.LastModified
+		}
+		logs.Done = c.Done
+	}
+	logs.Val = buf.String()
+
+	return logs, nil
+}
+
+// DeleteNodeRunLogsFromStorage removes the step logs chunks of all the node runs of a workflow run from the objectstore.
+// Database index is removed with the node runs.
+func DeleteNodeRunLogsFromStorage(ctx context.Context, db gorp.SqlExecutor, workflowRunID int64) error {
+	if logStorage == nil {
+		return nil
+	}
+
+	ids, err := loadNodeRunIDsWithLogChunks(db, "workflow_run.id = $1", workflowRunID)
+	if err != nil {
+		return err
+	}
+	DeleteNodeRunLogsContainers(ctx, ids)
+	return nil
+}
+
+// LoadWorkflowNodeRunIDsWithLogChunks returns the ids of the node runs of a workflow with step logs in the objectstore.
+// The ids should be loaded before the workflow is deleted to remove the logs once the deletion is committed.
+func LoadWorkflowNodeRunIDsWithLogChunks(db gorp.SqlExecutor, workflowID int64) ([]int64, error) {
+	if logStorage == nil {
+		return nil, nil
+	}
+	return loadNodeRunIDsWithLogChunks(db, "workflow_run.workflow_id = $1", workflowID)
+}
+
+func loadNodeRunIDsWithLogChunks(db gorp.SqlExecutor, where string, arg int64) ([]int64, error) {
+	var ids []int64
+	_, err := db.Select(&ids, `
+		SELECT DISTINCT workflow_node_run_job_log_chunk.workflow_node_run_id
+		FROM workflow_node_run_job_log_chunk
+		JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_job_log_chunk.workflow_node_run_id
+		JOIN workflow_run ON workflow_run.id = workflow_node_run.workflow_run_id
+		WHERE `+where, arg)
+	if err != nil && sdk.Cause(err) != sql.ErrNoRows {
+		return nil, sdk.WithStack(err)
+	}
+	return ids, nil
+}
+
+// DeleteNodeRunLogsContainers removes the step logs of the given node runs from the objectstore.
+func DeleteNodeRunLogsContainers(ctx context.Context, nodeRunIDs []int64) {
+	if logStorage == nil {
+		return
+	}
+	for _, nodeRunID := range nodeRunIDs {
+		if err := logStorage.DeleteContainer(ctx, logChunkContainer(nodeRunID)); err != nil {
+			log.Error(ctx, "DeleteNodeRunLogsContainers> cannot delete logs of node run %d: %v", nodeRunID, err)
+		}
+	}
+}
+
+// MoveStepLogsToStorage moves a batch of step logs stored in database to the objectstore.
+// It returns the number of moved step logs.
+func MoveStepLogsToStorage(ctx context.Context, db *gorp.DbMap, limit int) (int, error) {
+	if logStorage == nil {
+		return 0, sdk.WithStack(fmt.Errorf("log storage is not enabled"))
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	query := `
+		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
+		FROM workflow_node_run_job_logs
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+	rows, err := tx.Query(query, limit)
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	var logs []sdk.Log
+	for rows.Next() {
+		var l sdk.Log
+		var s, m, d pq.NullTime
+		if err := rows.Scan(&l.ID, &l.JobID, &l.NodeRunID, &s, &m, &d, &l.StepOrder, &l.Val); err != nil {
+			rows.Close() // nolint
+			return 0, sdk.WithStack(err)
+		}
+		if s.Valid {
+			l.Start = &s.Time
+		}
+		if m.Valid {
+			l.LastModified = &m.Time
+		}
+		if d.Valid {
+			l.Done = &d.Time
+		}
+		logs = append(logs, l)
+	}
+	rows.Close() // nolint
+
+	// objects stored for a batch that is not committed are removed
+	var stored []logChunk
+	var committed bool
+	defer func() {
+		if !committed {
+			deleteLogChunkObjects(ctx, stored)
+		}
+	}()
+
+	for i := range logs {
+		c, err := storeLogChunk(tx, &logs[i])
+		if err != nil {
+			return 0, sdk.WrapError(err, "cannot move log %d", logs[i].ID)
+		}
+		stored = append(stored, c)
+		if _, err := tx.Exec("DELETE FROM workflow_node_run_job_logs WHERE id = $1", logs[i].ID); err != nil {
+			return 0, sdk.WithStack(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	committed = true
+	return len(logs), nil
+}
@@ -0,0 +1,241 @@
+package workflow_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// testStartRunWithOneJob starts a run of a workflow with one pipeline of one job and returns the run and its job.
+func testStartRunWithOneJob(t *testing.T, db *gorp.DbMap, store cache.Store) (*sdk.WorkflowRun, sdk.WorkflowNodeJobRun) {
+	u, _ := assets.InsertAdminUser(t, db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, store, key, key)
+
+	pip := sdk.Pipeline{ProjectID: proj.ID, ProjectKey: proj.Key, Name: "pip1"}
+	require.NoError(t, pipeline.InsertPipeline(db, store, proj, &pip))
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	require.NoError(t, pipeline.InsertStage(db, s))
+	j := &sdk.Job{Enabled: true, Action: sdk.Action{Enabled: true}}
+	require.NoError(t, pipeline.InsertJob(db, j, s.ID, &pip))
+
+	proj, err := project.LoadByID(db, store, proj.ID, project.LoadOptions.WithPipelines, project.LoadOptions.WithGroups)
+	require.NoError(t, err)
+
+	w := sdk.Workflow{
+		Name:       "test_logs",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Name:    "node1",
+				Ref:     "node1",
+				Type:    sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{PipelineID: pip.ID},
+			},
+		},
+	}
+	require.NoError(t, workflow.Insert(context.TODO(), db, store, &w, proj))
+	w1, err := workflow.Load(context.TODO(), db, store, proj, w.Name, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+
+	wr, err := workflow.CreateRun(db, w1, nil, u)
+	require.NoError(t, err)
+	wr.Workflow = *w1
+	consumer, _ := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	_, err = workflow.StartWorkflowRun(context.TODO(), db, store, proj, wr, &sdk.WorkflowRunPostHandlerOption{
+		Manual: &sdk.WorkflowNodeRunManual{Username: u.Username},
+	}, consumer, nil)
+	require.NoError(t, err)
+
+	lastRun, err := workflow.LoadLastRun(db, proj.Key, w1.Name, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	nodeRuns := lastRun.WorkflowNodeRuns[w1.WorkflowData.Node.ID]
+	require.Len(t, nodeRuns, 1)
+	require.Len(t, nodeRuns[0].Stages[0].RunJobs, 1)
+	return lastRun, nodeRuns[0].Stages[0].RunJobs[0]
+}
+
+func testSetLogStorage(t *testing.T) func() {
+	_, end := testSetLogStorageInDir(t)
+	return end
+}
+
+func testSetLogStorageInDir(t *testing.T) (string, func()) {
+	basedir, err := ioutil.TempDir("", "logs")
+	require.NoError(t, err)
+	storage, err := objectstore.Init(context.Background(), objectstore.Config{
+		Kind: objectstore.Filesystem,
+		Options: objectstore.ConfigOptions{
+			Filesystem: objectstore.ConfigOptionsFilesystem{Basedir: basedir},
+		},
+	})
+	require.NoError(t, err)
+	workflow.SetLogStorage(storage)
+	return basedir, func() {
+		workflow.SetLogStorage(nil)
+		os.RemoveAll(basedir) // nolint
+	}
+}
+
+// testCountFiles returns the number of files stored in the given directory and its sub directories.
+func testCountFiles(t *testing.T, dir string) int {
+	var n int
+	require.NoError(t, filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			n++
+		}
+		return err
+	}))
+	return n
+}
+
+func TestAddLogToStorage(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	_, job := testStartRunWithOneJob(t, db, store)
+	defer testSetLogStorage(t)()
+	require.True(t, workflow.LogStorageEnabled())
+
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "first line\n"}, workflow.DefaultMaxLogSize))
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "second line\n"}, workflow.DefaultMaxLogSize))
+
+	logs, err := workflow.LoadStepLogs(context.TODO(), db, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, logs)
+	assert.Equal(t, "first line\nsecond line\n", logs.Val)
+	assert.Equal(t, job.WorkflowNodeRunID, logs.NodeRunID)
+
+	// Nothing is stored in database
+	dbLogs, err := workflow.LoadLogs(db, job.ID)
+	require.NoError(t, err)
+	assert.Empty(t, dbLogs)
+
+	// Other step has no log
+	logs, err = workflow.LoadStepLogs(context.TODO(), db, job.ID, 1)
+	require.NoError(t, err)
+	assert.Nil(t, logs)
+}
+
+func TestAddLogToStorageMergesChunks(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	wr, job := testStartRunWithOneJob(t, db, store)
+	basedir, endStorage := testSetLogStorageInDir(t)
+	defer endStorage()
+
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "first line\n"}, workflow.DefaultMaxLogSize))
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "second line\n"}, workflow.DefaultMaxLogSize))
+	assert.Equal(t, 2, testCountFiles(t, basedir))
+
+	// Chunks are merged into one object when the step is done
+	now := time.Now()
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "last line\n", Done: &now}, workflow.DefaultMaxLogSize))
+	assert.Equal(t, 1, testCountFiles(t, basedir))
+
+	logs, err := workflow.LoadStepLogs(context.TODO(), db, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, logs)
+	assert.Equal(t, "first line\nsecond line\nlast line\n", logs.Val)
+	assert.NotNil(t, logs.Done)
+
+	// Logs sent after the merge are added after the merged chunk
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "late line\n"}, workflow.DefaultMaxLogSize))
+	logs, err = workflow.LoadStepLogs(context.TODO(), db, job.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line\nlast line\nlate line\n", logs.Val)
+
+	// Node runs with logs are known before the workflow is deleted
+	ids, err := workflow.LoadWorkflowNodeRunIDsWithLogChunks(db, wr.WorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{job.WorkflowNodeRunID}, ids)
+	workflow.DeleteNodeRunLogsContainers(context.TODO(), ids)
+	assert.Equal(t, 0, testCountFiles(t, basedir))
+}
+
+func TestAddLogToStorageConcurrently(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	_, job := testStartRunWithOneJob(t, db, store)
+	defer testSetLogStorage(t)()
+
+	// Chunks of the same step sent at the same time get distinct indexes
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: fmt.Sprintf("line %d\n", i)}, workflow.DefaultMaxLogSize)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	logs, err := workflow.LoadStepLogs(context.TODO(), db, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, logs)
+	assert.Equal(t, 20, strings.Count(logs.Val, "\n"))
+	for i := 0; i < 20; i++ {
+		assert.Contains(t, logs.Val, fmt.Sprintf("line %d\n", i))
+	}
+}
+
+func TestMoveStepLogsToStorage(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	wr, job := testStartRunWithOneJob(t, db, store)
+
+	// Logs stored in database before the log storage was enabled
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "stored in database\n"}, workflow.DefaultMaxLogSize))
+
+	defer testSetLogStorage(t)()
+	for {
+		n, err := workflow.MoveStepLogsToStorage(context.TODO(), db, 100)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+	}
+
+	dbLogs, err := workflow.LoadLogs(db, job.ID)
+	require.NoError(t, err)
+	assert.Empty(t, dbLogs)
+
+	require.NoError(t, workflow.AddLog(context.TODO(), db, &job, &sdk.Log{Val: "stored in objectstore\n"}, workflow.DefaultMaxLogSize))
+	logs, err := workflow.LoadStepLogs(context.TODO(), db, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, logs)
+	assert.Equal(t, "stored in database\nstored in objectstore\n", logs.Val)
+
+	// Chunks are removed from the objectstore with the run
+	require.NoError(t, workflow.DeleteNodeRunLogsFromStorage(context.TODO(), db, wr.ID))
+	_, err = workflow.LoadStepLogs(context.TODO(), db, job.ID, 0)
+	assert.Error(t, err)
+}
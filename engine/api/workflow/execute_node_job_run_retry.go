@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// RetryNodeJobRun puts back the job run in queue if its retry policy matches the result sent by the worker.
+// It returns false if the job should not be retried. The new attempt is not visible in queue before the backoff delay.
+func RetryNodeJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, job *sdk.WorkflowNodeJobRun, res sdk.Result) (bool, error) {
+	retry := job.Job.Action.Retry
+	// A stopped job is not retried
+	if retry == nil || job.Status != sdk.StatusBuilding || job.Attempt >= retry.Max || !retry.Match(res) {
+		return false, nil
+	}
+
+	var end func()
+	ctx, end = observability.Span(ctx, "workflow.RetryNodeJobRun")
+	defer end()
+
+	job.Attempt++
+	delay := retry.Delay(job.Attempt)
+
+	reason := res.Reason
+	if reason == "" {
+		reason = res.Status
+	}
+	separator := fmt.Sprintf("\n\n\n-=-=-=-=-=- Attempt %d failed: job replaced in queue -=-=-=-=-=-\n\n\n", job.Attempt)
+	if err := resetNodeJobRunSteps(ctx, db, job, fmt.Sprintf("Attempt %d failed: %s\n", job.Attempt, reason), separator); err != nil {
+		return false, err
+	}
+
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobRetry.ID, Args: []interface{}{reason, job.Attempt + 1, retry.Max + 1, delay.String()}},
+	}}
+	if err := AddSpawnInfosNodeJobRun(db, job.ID, PrepareSpawnInfos(infos)); err != nil {
+		return false, sdk.WrapError(err, "cannot save spawn info on node job run %d", job.ID)
+	}
+
+	job.Status = sdk.StatusWaiting
+	job.Queued = time.Now().Add(delay)
+	job.Start = time.Time{}
+	job.Job.WorkerName = ""
+	job.Job.WorkerID = ""
+	if err := UpdateNodeJobRun(ctx, db, job); err != nil {
+		return false, sdk.WrapError(err, "cannot update node job run %d", job.ID)
+	}
+	if _, err := db.Exec("UPDATE workflow_node_run_job SET worker_id = NULL WHERE id = $1", job.ID); err != nil {
+		return false, sdk.WrapError(err, "cannot release worker of node job run %d", job.ID)
+	}
+
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, job.WorkflowNodeRunID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := SyncNodeRunRunJob(ctx, db, nodeRun, *job); err != nil {
+		return false, sdk.WrapError(err, "cannot sync node job run %d", job.ID)
+	}
+	for i := range nodeRun.Stages {
+		for j := range nodeRun.Stages[i].RunJobs {
+			runJob := &nodeRun.Stages[i].RunJobs[j]
+			if runJob.ID == job.ID {
+				runJob.Status = job.Status
+				runJob.Attempt = job.Attempt
+				runJob.Queued = job.Queued
+				runJob.Start = job.Start
+			}
+		}
+	}
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return false, sdk.WrapError(err, "cannot update node run %d", nodeRun.ID)
+	}
+
+	// The job can be booked again by any hatchery
+	if err := store.Delete(keyBookJob(job.ID)); err != nil {
+		log.Error(ctx, "RetryNodeJobRun> cannot release booking of job %d: %v", job.ID, err)
+	}
+
+	log.Info(ctx, "RetryNodeJobRun> job %d replaced in queue for attempt %d/%d in %s", job.ID, job.Attempt+1, retry.Max+1, delay)
+	return true, nil
+}
@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestRetryNodeJobRunSkipped(t *testing.T) {
+	exitCode := 3
+	failed := sdk.Result{Status: sdk.StatusFail, ExitCode: &exitCode}
+
+	newJob := func(retry *sdk.ActionRetry, status string, attempt int) *sdk.WorkflowNodeJobRun {
+		return &sdk.WorkflowNodeJobRun{
+			ID:      1,
+			Status:  status,
+			Attempt: attempt,
+			Job:     sdk.ExecutedJob{Job: sdk.Job{Action: sdk.Action{Retry: retry}}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		job  *sdk.WorkflowNodeJobRun
+		res  sdk.Result
+	}{
+		{name: "no retry policy", job: newJob(nil, sdk.StatusBuilding, 0), res: failed},
+		{name: "stopped job", job: newJob(&sdk.ActionRetry{Max: 2}, sdk.StatusStopped, 0), res: failed},
+		{name: "max attempts reached", job: newJob(&sdk.ActionRetry{Max: 2}, sdk.StatusBuilding, 2), res: failed},
+		{name: "success", job: newJob(&sdk.ActionRetry{Max: 2}, sdk.StatusBuilding, 0), res: sdk.Result{Status: sdk.StatusSuccess}},
+		{name: "other exit code", job: newJob(&sdk.ActionRetry{Max: 2, On: []string{"4", sdk.ActionRetryOnTimeout}}, sdk.StatusBuilding, 0), res: failed},
+	}
+	for _, tt := range tests {
+		attempt := tt.job.Attempt
+		// The database is not used when the job is not retried
+		retried, err := RetryNodeJobRun(context.TODO(), nil, nil, tt.job, tt.res)
+		require.NoError(t, err, tt.name)
+		assert.False(t, retried, tt.name)
+		assert.Equal(t, attempt, tt.job.Attempt, tt.name)
+	}
+}
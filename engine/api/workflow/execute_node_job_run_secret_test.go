@@ -0,0 +1,50 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+type testSecretProvider map[string]string
+
+func (p testSecretProvider) Get(_ context.Context, path, _ string) (string, error) {
+	return p[path], nil
+}
+
+func TestLoadSecretsAuditsExternalSecretsInReport(t *testing.T) {
+	secret.RegisterProvider("test", testSecretProvider{"PROJ/my-app/token": "s3cr3t"}, "")
+
+	wr := &sdk.WorkflowRun{Number: 1, Workflow: sdk.Workflow{Name: "demo", ProjectKey: "PROJ"}}
+	pv := []sdk.Variable{{Name: "token", Type: sdk.ExternalSecretVariable, Value: "test:PROJ/my-app/token"}}
+
+	// Without report the secret is only resolved, as for job details
+	secrets, err := LoadSecrets(context.TODO(), nil, nil, nil, wr, pv, nil)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "s3cr3t", secrets[0].Value)
+	assert.Equal(t, sdk.SecretVariable, secrets[0].Type)
+
+	// When a job is taken, the resolution is kept in the report to be published after the commit
+	report := new(ProcessorReport)
+	_, err = LoadSecrets(context.TODO(), nil, nil, nil, wr, pv, report)
+	require.NoError(t, err)
+	require.Len(t, report.secrets, 1)
+	assert.Equal(t, "cds.proj.token", report.secrets[0].name)
+	assert.Equal(t, sdk.ExternalSecretReference{Provider: "test", Path: "PROJ/my-app/token"}, report.secrets[0].ref)
+
+	// A reference outside of the namespace of the project is refused
+	other := &sdk.WorkflowRun{Number: 1, Workflow: sdk.Workflow{Name: "demo", ProjectKey: "OTHER"}}
+	_, err = LoadSecrets(context.TODO(), nil, nil, nil, other, pv, nil)
+	assert.Error(t, err)
+
+	// Merged reports keep the resolved secrets
+	merged, err := new(ProcessorReport).Merge(context.TODO(), report, nil)
+	require.NoError(t, err)
+	assert.Len(t, merged.secrets, 1)
+}
@@ -196,6 +196,19 @@ func executeNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 			if errSync != nil {
 				return report, errSync
 			}
+
+			// Queue the next variants of matrix jobs, or stop them on failure
+			r, updated, errM := processMatrixJobs(ctx, db, store, stage, wr, nr)
+			report, errM = report.Merge(ctx, r, errM)
+			if errM != nil {
+				return report, errM
+			}
+			if updated {
+				end, errSync = syncStage(ctx, db, store, stage)
+				if errSync != nil {
+					return report, errSync
+				}
+			}
 			if !end {
 				break
 			} else {
@@ -274,6 +287,13 @@ func executeNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 			return nil, sdk.WrapError(err, "Unable to delete node %d job runs ", nr.ID)
 		}
 
+		//Release the concurrency group held by the node run
+		r2, err := releaseConcurrencyLock(ctx, db, store, proj, nr)
+		report, err = report.Merge(ctx, r2, err)
+		if err != nil {
+			return nil, sdk.WrapError(err, "Unable to release concurrency group")
+		}
+
 		var hasMutex bool
 		var nodeName string
 
@@ -331,6 +351,18 @@ func executeNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 				return nil, sdk.WrapError(err, "Unable to update workflow run %d after mutex release", workflowRun.ID)
 			}
 
+			waitingNode := workflowRun.Workflow.WorkflowData.NodeByID(waitingRun.WorkflowNodeID)
+			if waitingNode != nil {
+				r0, acquired, err := acquireConcurrencyLock(ctx, db, proj, workflowRun, waitingNode, waitingRun)
+				report, err = report.Merge(ctx, r0, err)
+				if err != nil {
+					return nil, sdk.WrapError(err, "Unable to check concurrency group")
+				}
+				if !acquired {
+					return report, nil
+				}
+			}
+
 			log.Debug("workflow.execute> process the node run %d because mutex has been released", waitingRun.ID)
 			r, err := executeNodeRun(ctx, db, store, proj, waitingRun)
 			report, err = report.Merge(ctx, r, err)
@@ -377,118 +409,172 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 
 	skippedOrDisabledJobs := 0
 	failedJobs := 0
+	jobRuns := 0
+	rollback := isRollbackNodeRun(nr)
 	//Browse the jobs
 	for j := range stage.Jobs {
 		job := &stage.Jobs[j]
 
-		// errors generated in the loop will be added to job run spawn info
-		spawnErrs := sdk.MultiError{}
-
-		//Process variables for the jobs
-		_, next = observability.Span(ctx, "workflow..getNodeJobRunParameters")
-		jobParams, err := getNodeJobRunParameters(db, *job, nr, stage)
-		next()
-		if err != nil {
-			spawnErrs.Join(*err)
+		// A rollback only runs the DeployApplication steps, the other jobs are disabled
+		if rollback {
+			rollbackJob, ok := sdk.RollbackJob(*job)
+			rollbackJob.Enabled = rollbackJob.Enabled && ok
+			*job = rollbackJob
 		}
 
-		_, next = observability.Span(ctx, "workflow.processNodeJobRunRequirements")
-		jobRequirements, containsService, wm, err := processNodeJobRunRequirements(ctx, db, *job, nr, sdk.Groups(groups).ToIDs(), integrationPluginBinaries)
-		next()
-		if err != nil {
-			spawnErrs.Join(*err)
+		// A matrix job is run once per variant, up to max parallel variants at the same time
+		matrixes := []*sdk.WorkflowNodeJobRunMatrix{nil}
+		if job.Action.Matrix != nil && stage.Enabled && job.Enabled && conditionsOK {
+			matrixes = nil
+			variants := job.Action.Matrix.Variants()
+			for i := range variants {
+				if job.Action.Matrix.MaxParallel > 0 && i >= job.Action.Matrix.MaxParallel {
+					break
+				}
+				matrixes = append(matrixes, &sdk.WorkflowNodeJobRunMatrix{Index: i, Total: len(variants), Variant: variants[i]})
+			}
 		}
 
-		// check that children actions used by job can be used by the project
-		if err := action.CheckChildrenForGroupIDsWithLoop(ctx, db, &job.Action, sdk.Groups(groups).ToIDs()); err != nil {
-			spawnErrs.Append(err)
-		}
+		for _, m := range matrixes {
+			wjob, err := addJobRunToQueue(ctx, db, job, m, stage, wr, nr, groups, integrationPluginBinaries, conditionsOK)
+			if err != nil {
+				return report, err
+			}
+			jobRuns++
+			if !stage.Enabled || !job.Enabled || !conditionsOK {
+				skippedOrDisabledJobs++
+			}
+			if wjob.Status == sdk.StatusFail {
+				failedJobs++
+			}
 
-		// add requirements in job parameters, to use them as {{.job.requirement...}} in job
-		_, next = observability.Span(ctx, "workflow.prepareRequirementsToNodeJobRunParameters")
-		jobParams = append(jobParams, prepareRequirementsToNodeJobRunParameters(jobRequirements)...)
-		next()
+			//Put the job run in database
+			stage.RunJobs = append(stage.RunJobs, *wjob)
 
-		//Create the job run
-		wjob := sdk.WorkflowNodeJobRun{
-			ProjectID:                 wr.ProjectID,
-			WorkflowNodeRunID:         nr.ID,
-			Start:                     time.Time{},
-			Queued:                    time.Now(),
-			Status:                    sdk.StatusWaiting,
-			Parameters:                jobParams,
-			ExecGroups:                groups,
-			IntegrationPluginBinaries: integrationPluginBinaries,
-			Job: sdk.ExecutedJob{
-				Job: *job,
-			},
-			Header:          nr.Header,
-			ContainsService: containsService,
-		}
-		if wm != nil {
-			wjob.ModelType = wm.Type
-		}
-		wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
-
-		if !stage.Enabled || !wjob.Job.Enabled {
-			wjob.Status = sdk.StatusDisabled
-			skippedOrDisabledJobs++
-		} else if !conditionsOK {
-			wjob.Status = sdk.StatusSkipped
-			skippedOrDisabledJobs++
+			report.Add(ctx, *wjob)
 		}
+	}
 
-		// If there is any error in the previous operation, mark the job as failed
-		if !spawnErrs.IsEmpty() {
-			failedJobs++
-			wjob.Status = sdk.StatusFail
+	if skippedOrDisabledJobs == jobRuns {
+		stage.Status = sdk.StatusSkipped
+	}
 
-			for _, e := range spawnErrs {
-				msg := sdk.SpawnMsg{
-					ID: sdk.MsgSpawnInfoJobError.ID,
-				}
-				msg.Args = []interface{}{sdk.Cause(e).Error()}
-				wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
-					APITime:    time.Now(),
-					Message:    msg,
-					RemoteTime: time.Now(),
-				})
-			}
-		} else {
-			wjob.SpawnInfos = []sdk.SpawnInfo{{
-				APITime:    time.Now(),
-				Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID},
-				RemoteTime: time.Now(),
-			}}
-		}
+	if failedJobs > 0 {
+		stage.Status = sdk.StatusFail
+	}
 
-		// insert in database
-		_, next = observability.Span(ctx, "workflow.insertWorkflowNodeJobRun")
-		if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
-			next()
-			return report, sdk.WrapError(err, "unable to insert in table workflow_node_run_job")
-		}
-		next()
+	return report, nil
+}
+
+// isRollbackNodeRun returns true if the node run was relaunched by a rollback of an application deployment
+func isRollbackNodeRun(nr *sdk.WorkflowNodeRun) bool {
+	return sdk.ParameterValue(nr.BuildParameters, sdk.DeployRollbackVersionParameter) != ""
+}
 
-		if err := AddSpawnInfosNodeJobRun(db, wjob.ID, PrepareSpawnInfos(wjob.SpawnInfos)); err != nil {
-			return nil, sdk.WrapError(err, "cannot save spawn info job %d", wjob.ID)
+// addJobRunToQueue creates the job run of a job, or of a variant of a matrix job
+func addJobRunToQueue(ctx context.Context, db gorp.SqlExecutor, job *sdk.Job, matrix *sdk.WorkflowNodeJobRunMatrix, stage *sdk.Stage, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun,
+	groups []sdk.Group, integrationPluginBinaries []sdk.GRPCPluginBinary, conditionsOK bool) (*sdk.WorkflowNodeJobRun, error) {
+	// errors generated in the loop will be added to job run spawn info
+	spawnErrs := sdk.MultiError{}
+
+	//Process variables for the jobs
+	_, next := observability.Span(ctx, "workflow..getNodeJobRunParameters")
+	jobParams, err := getNodeJobRunParameters(db, *job, nr, stage)
+	next()
+	if err != nil {
+		spawnErrs.Join(*err)
+	}
+
+	// The variant of a matrix job overrides its os-architecture and model requirements
+	jobVariant := *job
+	if matrix != nil {
+		for _, p := range matrix.Variant.Parameters() {
+			sdk.AddParameter(&jobParams, p.Name, p.Type, p.Value)
 		}
+		jobVariant.Action.Requirements = matrix.Variant.Requirements(job.Action.Requirements)
+	}
 
-		//Put the job run in database
-		stage.RunJobs = append(stage.RunJobs, wjob)
+	_, next = observability.Span(ctx, "workflow.processNodeJobRunRequirements")
+	jobRequirements, containsService, wm, err := processNodeJobRunRequirements(ctx, db, jobVariant, nr, sdk.Groups(groups).ToIDs(), integrationPluginBinaries)
+	next()
+	if err != nil {
+		spawnErrs.Join(*err)
+	}
 
-		report.Add(ctx, wjob)
+	// check that children actions used by job can be used by the project
+	if err := action.CheckChildrenForGroupIDsWithLoop(ctx, db, &job.Action, sdk.Groups(groups).ToIDs()); err != nil {
+		spawnErrs.Append(err)
 	}
 
-	if skippedOrDisabledJobs == len(stage.Jobs) {
-		stage.Status = sdk.StatusSkipped
+	// add requirements in job parameters, to use them as {{.job.requirement...}} in job
+	_, next = observability.Span(ctx, "workflow.prepareRequirementsToNodeJobRunParameters")
+	jobParams = append(jobParams, prepareRequirementsToNodeJobRunParameters(jobRequirements)...)
+	next()
+
+	//Create the job run
+	wjob := sdk.WorkflowNodeJobRun{
+		ProjectID:                 wr.ProjectID,
+		WorkflowNodeRunID:         nr.ID,
+		Start:                     time.Time{},
+		Queued:                    time.Now(),
+		Status:                    sdk.StatusWaiting,
+		Parameters:                jobParams,
+		ExecGroups:                groups,
+		IntegrationPluginBinaries: integrationPluginBinaries,
+		Job: sdk.ExecutedJob{
+			Job: *job,
+		},
+		Header:          nr.Header,
+		ContainsService: containsService,
+		Matrix:          matrix,
+	}
+	if wm != nil {
+		wjob.ModelType = wm.Type
 	}
+	wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
 
-	if failedJobs > 0 {
-		stage.Status = sdk.StatusFail
+	if !stage.Enabled || !wjob.Job.Enabled {
+		wjob.Status = sdk.StatusDisabled
+	} else if !conditionsOK {
+		wjob.Status = sdk.StatusSkipped
 	}
 
-	return report, nil
+	// If there is any error in the previous operation, mark the job as failed
+	if !spawnErrs.IsEmpty() {
+		wjob.Status = sdk.StatusFail
+
+		for _, e := range spawnErrs {
+			msg := sdk.SpawnMsg{
+				ID: sdk.MsgSpawnInfoJobError.ID,
+			}
+			msg.Args = []interface{}{sdk.Cause(e).Error()}
+			wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+				APITime:    time.Now(),
+				Message:    msg,
+				RemoteTime: time.Now(),
+			})
+		}
+	} else {
+		wjob.SpawnInfos = []sdk.SpawnInfo{{
+			APITime:    time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID},
+			RemoteTime: time.Now(),
+		}}
+	}
+
+	// insert in database
+	_, next = observability.Span(ctx, "workflow.insertWorkflowNodeJobRun")
+	if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
+		next()
+		return nil, sdk.WrapError(err, "unable to insert in table workflow_node_run_job")
+	}
+	next()
+
+	if err := AddSpawnInfosNodeJobRun(db, wjob.ID, PrepareSpawnInfos(wjob.SpawnInfos)); err != nil {
+		return nil, sdk.WrapError(err, "cannot save spawn info job %d", wjob.ID)
+	}
+
+	return &wjob, nil
 }
 
 func getIntegrationPluginBinaries(db gorp.SqlExecutor, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) ([]sdk.GRPCPluginBinary, error) {
@@ -811,6 +897,21 @@ func StopWorkflowNodeRun(ctx context.Context, dbFunc func() *gorp.DbMap, store c
 	report.Merge(ctx, r1, nil) // nolint
 	report.Add(ctx, nodeRun)
 
+	// Release the concurrency group held by the node run, or remove it from the queue
+	tx, errTx := dbFunc().Begin()
+	if errTx != nil {
+		return report, sdk.WrapError(errTx, "unable to create transaction")
+	}
+	defer tx.Rollback() //nolint
+	r2, errR := releaseConcurrencyLock(ctx, tx, store, proj, &nodeRun)
+	if errR != nil {
+		return report, sdk.WrapError(errR, "unable to release concurrency group")
+	}
+	if err := tx.Commit(); err != nil {
+		return report, sdk.WithStack(err)
+	}
+	report.Merge(ctx, r2, nil) // nolint
+
 	return report, nil
 }
 
@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+)
+
+// processMatrixJobs queues the next variants of the matrix jobs of a stage, up to their max parallel variants,
+// and stops the variants of a fail-fast matrix as soon as one of them failed.
+// It returns true if a job run of the stage has been queued or stopped.
+func processMatrixJobs(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stage *sdk.Stage, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) (*ProcessorReport, bool, error) {
+	var end func()
+	ctx, end = observability.Span(ctx, "workflow.processMatrixJobs")
+	defer end()
+
+	report := new(ProcessorReport)
+
+	var groups []sdk.Group
+	var integrationPluginBinaries []sdk.GRPCPluginBinary
+	var updated bool
+	for j := range stage.Jobs {
+		job := &stage.Jobs[j]
+		if job.Action.Matrix == nil {
+			continue
+		}
+
+		var runJobs []*sdk.WorkflowNodeJobRun
+		var running int
+		var failed *sdk.WorkflowNodeJobRun
+		for i := range stage.RunJobs {
+			rj := &stage.RunJobs[i]
+			if rj.Matrix == nil || rj.Job.PipelineActionID != job.PipelineActionID {
+				continue
+			}
+			runJobs = append(runJobs, rj)
+			if !sdk.StatusIsTerminated(rj.Status) {
+				running++
+			}
+			if rj.Status == sdk.StatusFail && failed == nil {
+				failed = rj
+			}
+		}
+		// The job was disabled or skipped
+		if len(runJobs) == 0 {
+			continue
+		}
+
+		if failed != nil && job.Action.Matrix.FailFast {
+			for _, rj := range runJobs {
+				if sdk.StatusIsTerminated(rj.Status) {
+					continue
+				}
+				if err := stopMatrixJobRun(ctx, db, store, rj, failed.Matrix.Variant); err != nil {
+					return nil, false, err
+				}
+				report.Add(ctx, *rj)
+				updated = true
+			}
+			continue
+		}
+
+		total := runJobs[0].Matrix.Total
+		maxParallel := job.Action.Matrix.MaxParallel
+		if len(runJobs) >= total || (maxParallel > 0 && running >= maxParallel) {
+			continue
+		}
+
+		if groups == nil {
+			var err error
+			integrationPluginBinaries, err = getIntegrationPluginBinaries(db, wr, nr)
+			if err != nil {
+				return nil, false, sdk.WrapError(err, "unable to get integration plugins requirement")
+			}
+			groups, err = getExecutablesGroups(wr, nr)
+			if err != nil {
+				return nil, false, sdk.WrapError(err, "error getting job executables groups")
+			}
+		}
+
+		variants := job.Action.Matrix.Variants()
+		for i := len(runJobs); i < total && i < len(variants); i++ {
+			if maxParallel > 0 && running >= maxParallel {
+				break
+			}
+			wjob, err := addJobRunToQueue(ctx, db, job, &sdk.WorkflowNodeJobRunMatrix{Index: i, Total: total, Variant: variants[i]}, stage, wr, nr, groups, integrationPluginBinaries, true)
+			if err != nil {
+				return nil, false, err
+			}
+			stage.RunJobs = append(stage.RunJobs, *wjob)
+			report.Add(ctx, *wjob)
+			running++
+			updated = true
+		}
+	}
+
+	return report, updated, nil
+}
+
+// stopMatrixJobRun stops a variant of a matrix job, the worker stops the job when it sees that it is no more building
+func stopMatrixJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, rj *sdk.WorkflowNodeJobRun, failedVariant sdk.JobMatrixVariant) error {
+	njr, err := LoadAndLockNodeJobRunWait(ctx, db, store, rj.ID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load node job run %d", rj.ID)
+	}
+	if sdk.StatusIsTerminated(njr.Status) {
+		rj.Status = njr.Status
+		return nil
+	}
+
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobMatrixFailFast.ID, Args: []interface{}{failedVariant.String()}},
+	}}
+	if err := AddSpawnInfosNodeJobRun(db, njr.ID, PrepareSpawnInfos(infos)); err != nil {
+		return sdk.WrapError(err, "cannot save spawn info on node job run %d", njr.ID)
+	}
+
+	njr.Status = sdk.StatusStopped
+	njr.Done = time.Now()
+	for i := range njr.Job.StepStatus {
+		if !sdk.StatusIsTerminated(njr.Job.StepStatus[i].Status) {
+			njr.Job.StepStatus[i].Status = sdk.StatusStopped
+			njr.Job.StepStatus[i].Done = njr.Done
+		}
+	}
+	if err := UpdateNodeJobRun(ctx, db, njr); err != nil {
+		return sdk.WrapError(err, "cannot stop node job run %d", njr.ID)
+	}
+
+	rj.Status = njr.Status
+	rj.Done = njr.Done
+	rj.Job = njr.Job
+	return nil
+}
@@ -69,6 +69,7 @@ type JobRun struct {
 	Parameters                sql.NullString `db:"variables"`
 	Status                    string         `db:"status"`
 	Retry                     int            `db:"retry"`
+	Attempt                   int            `db:"attempt"`
 	Queued                    time.Time      `db:"queued"`
 	Start                     time.Time      `db:"start"`
 	Done                      time.Time      `db:"done"`
@@ -79,6 +80,7 @@ type JobRun struct {
 	ContainsService           bool           `db:"contains_service"`
 	ModelType                 sql.NullString `db:"model_type"`
 	Header                    sql.NullString `db:"header"`
+	Matrix                    sql.NullString `db:"matrix"`
 }
 
 // ToJobRun transform the JobRun with data of the provided sdk.WorkflowNodeJobRun
@@ -96,6 +98,7 @@ func (j *JobRun) ToJobRun(jr *sdk.WorkflowNodeJobRun) (err error) {
 	}
 	j.Status = jr.Status
 	j.Retry = jr.Retry
+	j.Attempt = jr.Attempt
 	j.Queued = jr.Queued
 	j.Start = jr.Start
 	j.Done = jr.Done
@@ -114,6 +117,12 @@ func (j *JobRun) ToJobRun(jr *sdk.WorkflowNodeJobRun) (err error) {
 	if err != nil {
 		return sdk.WrapError(err, "column header")
 	}
+	if jr.Matrix != nil {
+		j.Matrix, err = gorpmapping.JSONToNullString(jr.Matrix)
+		if err != nil {
+			return sdk.WrapError(err, "column matrix")
+		}
+	}
 	return nil
 }
 
@@ -125,6 +134,7 @@ func (j JobRun) WorkflowNodeRunJob() (sdk.WorkflowNodeJobRun, error) {
 		WorkflowNodeRunID: j.WorkflowNodeRunID,
 		Status:            j.Status,
 		Retry:             j.Retry,
+		Attempt:           j.Attempt,
 		Queued:            j.Queued,
 		QueuedSeconds:     time.Now().Unix() - j.Queued.Unix(),
 		Start:             j.Start,
@@ -147,6 +157,9 @@ func (j JobRun) WorkflowNodeRunJob() (sdk.WorkflowNodeJobRun, error) {
 	if err := gorpmapping.JSONNullString(j.Header, &jr.Header); err != nil {
 		return jr, sdk.WrapError(err, "header")
 	}
+	if err := gorpmapping.JSONNullString(j.Matrix, &jr.Matrix); err != nil {
+		return jr, sdk.WrapError(err, "matrix")
+	}
 	if j.ModelType.Valid {
 		jr.ModelType = j.ModelType.String
 	}
@@ -219,4 +232,5 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbNodeOutGoingHookData{}, "w_node_outgoing_hook", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeJoinData{}, "w_node_join", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbAsCodeEvents{}, "workflow_as_code_events", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbNodeRunLock{}, "workflow_node_run_lock", true, "id"))
 }
@@ -2,6 +2,8 @@ package workflow
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-gorp/gorp"
 
@@ -12,6 +14,12 @@ import (
 
 const maxRetry = 3
 
+// jobTimeoutGracePeriod is the time given to a worker to send the result of a timed out job before the api fails it
+const jobTimeoutGracePeriod = time.Minute
+
+// LoadProjectByNodeJobRunIDFunc loads the project of a node job run with its variables
+type LoadProjectByNodeJobRunIDFunc func(ctx context.Context, db gorp.SqlExecutor, store cache.Store, nodeJobRunID int64) (*sdk.Project, error)
+
 // restartDeadJob restart all jobs which are building but without worker
 func restartDeadJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store) error {
 	db := DBFunc()
@@ -54,3 +62,91 @@ func restartDeadJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.
 
 	return nil
 }
+
+// failTimedOutJobs fails the jobs that are building for longer than their timeout, when the worker is not able
+// to do it, and puts them back in queue if their retry policy matches the timeout.
+func failTimedOutJobs(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, loadProject LoadProjectByNodeJobRunIDFunc) error {
+	db := DBFunc()
+	jobs, err := loadTimedOutNodeJobRuns(db, jobTimeoutGracePeriod)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load timed out node job runs")
+	}
+
+	for _, j := range jobs {
+		if err := failTimedOutJob(ctx, db, store, loadProject, j.ID); err != nil {
+			log.Error(ctx, "failTimedOutJobs> cannot fail node job run %d: %v", j.ID, err)
+		}
+	}
+	return nil
+}
+
+func failTimedOutJob(ctx context.Context, db *gorp.DbMap, store cache.Store, loadProject LoadProjectByNodeJobRunIDFunc, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	// the job is skipped if the worker is sending its result
+	job, err := LoadAndLockNodeJobRunSkipLocked(ctx, tx, store, id)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrLocked) {
+			return nil
+		}
+		return err
+	}
+	if job.Status != sdk.StatusBuilding {
+		return nil
+	}
+
+	timeout, err := sdk.ParseActionTimeout(job.Job.Action.Timeout)
+	if err != nil {
+		return err
+	}
+	res := sdk.Result{
+		BuildID:  job.ID,
+		Status:   sdk.StatusFail,
+		TimedOut: true,
+		Reason:   fmt.Sprintf("Job timed out after %s", timeout),
+	}
+
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTimeout.ID, Args: []interface{}{timeout.String()}},
+	}}
+	if err := AddSpawnInfosNodeJobRun(tx, job.ID, PrepareSpawnInfos(infos)); err != nil {
+		return sdk.WrapError(err, "cannot save spawn info on node job run %d", job.ID)
+	}
+
+	retried, err := RetryNodeJobRun(ctx, tx, store, job, res)
+	if err != nil {
+		return err
+	}
+	if retried {
+		return sdk.WithStack(tx.Commit())
+	}
+
+	proj, err := loadProject(ctx, tx, store, job.ID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project of node job run %d", job.ID)
+	}
+
+	now := time.Now()
+	job.Job.Reason = res.Reason
+	for i := range job.Job.StepStatus {
+		if job.Job.StepStatus[i].Status == sdk.StatusBuilding {
+			job.Job.StepStatus[i].Status = sdk.StatusFail
+			job.Job.StepStatus[i].Done = now
+		}
+	}
+	report, err := UpdateNodeJobRunStatus(ctx, tx, store, proj, job, sdk.StatusFail)
+	if err != nil {
+		return sdk.WrapError(err, "cannot update node job run %d", job.ID)
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	go SendEvent(context.Background(), db, proj.Key, report)
+	return nil
+}
@@ -2,6 +2,8 @@ package workflow
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -96,6 +98,7 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 	hookToUpdate := make(map[string]sdk.NodeHook)
 	for i := range wf.WorkflowData.Node.Hooks {
 		h := &wf.WorkflowData.Node.Hooks[i]
+		var previousHook *sdk.NodeHook
 		if h.UUID == "" && h.Ref == "" {
 			nodeName := wf.WorkflowData.Node.Name
 			if len(nodeName) > 45 {
@@ -104,20 +107,24 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 			h.Ref = fmt.Sprintf("%s.%d", nodeName, i)
 		} else if h.UUID == "" && h.Ref != "" && oldHooksByRef != nil {
 			// search previous hook configuration by ref
-			previousHook, has := oldHooksByRef[h.Ref]
-			if has {
+			if previous, has := oldHooksByRef[h.Ref]; has {
+				previousHook = &previous
 				h.UUID = previousHook.UUID
+				keepWebHookSecret(h, previousHook)
 				// If previous hook is the same, we do nothing
-				if h.Equals(previousHook) {
+				if h.Equals(*previousHook) {
 					continue
 				}
 			}
 		} else if oldHooks != nil {
 			// search previous hook configuration by uuid
-			previousHook, has := oldHooks[h.UUID]
-			// If previous hook is the same, we do nothing
-			if has && h.Equals(*previousHook) {
-				continue
+			if previous, has := oldHooks[h.UUID]; has {
+				previousHook = previous
+				keepWebHookSecret(h, previousHook)
+				// If previous hook is the same, we do nothing
+				if h.Equals(*previousHook) {
+					continue
+				}
 			}
 		}
 		// initialize a UUID is there no uuid
@@ -151,6 +158,14 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 			}
 		}
 
+		// Secrets are generated for new webhooks and for repository webhooks, as the repository is configured by CDS.
+		// Existing webhooks without secret keep working until their secret is rotated.
+		if previousHook == nil || h.HookModelName == sdk.RepositoryWebHookModelName {
+			if err := initWebHookSecret(h); err != nil {
+				return err
+			}
+		}
+
 		if err := updateSchedulerPayload(ctx, db, store, p, wf, h); err != nil {
 			return err
 		}
@@ -289,6 +304,7 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 		URL:      h.Config["webHookURL"].Value,
 		Events:   valueSlitted,
 		Workflow: true,
+		Secret:   h.Config[sdk.HookConfigWebHookSecret].Value,
 	}
 	if err := client.CreateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot create hook on repository: %+v", vcsHook)
@@ -341,6 +357,7 @@ func updateVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 		URL:      h.Config["webHookURL"].Value,
 		Events:   valueSlitted,
 		Workflow: true,
+		Secret:   h.Config[sdk.HookConfigWebHookSecret].Value,
 	}
 	if err := client.UpdateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot update hook on repository: %+v", vcsHook)
@@ -411,3 +428,98 @@ func DefaultPayload(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 
 	return defaultPayload, nil
 }
+
+func isWebHookWithSecret(h *sdk.NodeHook) bool {
+	return h.HookModelName == sdk.WebHookModelName || h.HookModelName == sdk.RepositoryWebHookModelName
+}
+
+// keepWebHookSecret sets the secret of the previous hook, secrets are not exported nor sent to users without write permission
+func keepWebHookSecret(h *sdk.NodeHook, previous *sdk.NodeHook) {
+	if !isWebHookWithSecret(h) || h.Config[sdk.HookConfigWebHookSecret].Value != "" {
+		return
+	}
+	if s, has := previous.Config[sdk.HookConfigWebHookSecret]; has && s.Value != "" {
+		if h.Config == nil {
+			h.Config = sdk.WorkflowNodeHookConfig{}
+		}
+		h.Config[sdk.HookConfigWebHookSecret] = s
+	}
+}
+
+// initWebHookSecret generates a secret for a webhook if it has no secret
+func initWebHookSecret(h *sdk.NodeHook) error {
+	if !isWebHookWithSecret(h) || h.Config[sdk.HookConfigWebHookSecret].Value != "" {
+		return nil
+	}
+	return setWebHookSecret(h)
+}
+
+func setWebHookSecret(h *sdk.NodeHook) error {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return sdk.WrapError(err, "cannot generate webhook secret")
+	}
+	if h.Config == nil {
+		h.Config = sdk.WorkflowNodeHookConfig{}
+	}
+	h.Config[sdk.HookConfigWebHookSecret] = sdk.WorkflowNodeHookConfigValue{
+		Value:        hex.EncodeToString(bs),
+		Configurable: false,
+		Type:         sdk.HookConfigTypeString,
+	}
+	return nil
+}
+
+// RotateHookSecret generates a new secret for a webhook. The repository configuration is updated first, then the hook
+// in database and the hooks service last. If a step fails, the previous secret is restored on the repository.
+func RotateHookSecret(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, wf *sdk.Workflow, uuid string) (*sdk.NodeHook, error) {
+	h, has := wf.WorkflowData.GetHooks()[uuid]
+	if !has {
+		return nil, sdk.WrapError(sdk.ErrNotFound, "cannot find hook %s on workflow %s", uuid, wf.Name)
+	}
+	if !isWebHookWithSecret(h) {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "only webhooks and repository webhooks have a secret")
+	}
+
+	previous := *h
+	previous.Config = h.Config.Clone()
+	if err := setWebHookSecret(h); err != nil {
+		return nil, err
+	}
+
+	hasVCSHook := h.HookModelName == sdk.RepositoryWebHookModelName && h.Config[sdk.HookConfigWebHookID].Value != ""
+	if hasVCSHook {
+		if err := updateVCSConfiguration(ctx, db, store, p, h); err != nil {
+			return nil, sdk.WrapError(err, "cannot update vcs configuration")
+		}
+	}
+
+	if err := rotateHookSecret(ctx, db, h); err != nil {
+		if hasVCSHook {
+			if errR := updateVCSConfiguration(ctx, db, store, p, &previous); errR != nil {
+				log.Error(ctx, "RotateHookSecret> cannot restore vcs configuration of hook %s: %v", h.UUID, errR)
+			}
+		}
+		return nil, err
+	}
+	return h, nil
+}
+
+// rotateHookSecret saves the new secret of the hook in database then sends it to the hooks service
+func rotateHookSecret(ctx context.Context, db gorp.SqlExecutor, h *sdk.NodeHook) error {
+	dbHook := dbNodeHookData(*h)
+	if _, err := db.Update(&dbHook); err != nil {
+		return sdk.WrapError(err, "unable to update hook %s", h.UUID)
+	}
+
+	srvs, err := services.LoadAllByType(ctx, db, services.TypeHooks)
+	if err != nil {
+		return sdk.WrapError(err, "unable to get services dao")
+	}
+	hooks := map[string]sdk.NodeHook{h.UUID: *h}
+	_, code, errHooks := services.DoJSONRequest(ctx, db, srvs, http.MethodPost, "/task/bulk", hooks, &hooks)
+	if errHooks != nil || code >= 400 {
+		return sdk.WrapError(errHooks, "unable to update hook %s [%d]", h.UUID, code)
+	}
+	return nil
+}
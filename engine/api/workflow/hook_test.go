@@ -0,0 +1,33 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWebHookSecret(t *testing.T) {
+	h := &sdk.NodeHook{HookModelName: sdk.WebHookModelName, Config: sdk.WorkflowNodeHookConfig{}}
+	require.NoError(t, initWebHookSecret(h))
+	secret := h.Config[sdk.HookConfigWebHookSecret].Value
+	assert.Len(t, secret, 64)
+	assert.False(t, h.Config[sdk.HookConfigWebHookSecret].Configurable)
+
+	// an existing secret is kept
+	require.NoError(t, initWebHookSecret(h))
+	assert.Equal(t, secret, h.Config[sdk.HookConfigWebHookSecret].Value)
+
+	// the secret is not exported, it is taken from the previous hook on import
+	imported := &sdk.NodeHook{HookModelName: sdk.WebHookModelName}
+	keepWebHookSecret(imported, h)
+	assert.Equal(t, secret, imported.Config[sdk.HookConfigWebHookSecret].Value)
+
+	// other hooks have no secret
+	scheduler := &sdk.NodeHook{HookModelName: sdk.SchedulerModelName, Config: sdk.WorkflowNodeHookConfig{}}
+	require.NoError(t, initWebHookSecret(scheduler))
+	_, has := scheduler.Config[sdk.HookConfigWebHookSecret]
+	assert.False(t, has)
+}
@@ -13,7 +13,7 @@ import (
 var baseUIURL, defaultOS, defaultArch string
 
 //Initialize starts goroutines for workflows
-func Initialize(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, uiURL, confDefaultOS, confDefaultArch string) {
+func Initialize(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, uiURL, confDefaultOS, confDefaultArch string, loadProject LoadProjectByNodeJobRunIDFunc) {
 	baseUIURL = uiURL
 	defaultOS = confDefaultOS
 	defaultArch = confDefaultArch
@@ -34,6 +34,9 @@ func Initialize(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Stor
 			if err := restartDeadJob(ctx, DBFunc, store); err != nil {
 				log.Warning(ctx, "workflow.restartDeadJob> Error on restartDeadJob : %v", err)
 			}
+			if err := failTimedOutJobs(ctx, DBFunc, store, loadProject); err != nil {
+				log.Warning(ctx, "workflow.failTimedOutJobs> Error on failTimedOutJobs : %v", err)
+			}
 		case <-tickStop.C:
 			if err := stopRunsBlocked(ctx, db); err != nil {
 				log.Warning(ctx, "workflow.stopRunsBlocked> Error on stopRunsBlocked : %v", err)
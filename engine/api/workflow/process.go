@@ -62,9 +62,13 @@ func checkCondition(ctx context.Context, wr *sdk.WorkflowRun, conditions sdk.Wor
 	}
 	if errc != nil {
 		log.Warning(ctx, "processWorkflowNodeRun> WorkflowCheckConditions error: %s", errc)
+		msg := fmt.Sprintf("Error on LUA Condition: %v", errc)
+		if conditions.LuaScript == "" {
+			msg = fmt.Sprintf("Error on Condition: %v", errc)
+		}
 		AddWorkflowRunInfo(wr, true, sdk.SpawnMsg{
 			ID:   sdk.MsgWorkflowError.ID,
-			Args: []interface{}{fmt.Sprintf("Error on LUA Condition: %v", errc)},
+			Args: []interface{}{msg},
 		})
 		return false
 	}
@@ -0,0 +1,227 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// concurrencyLockKey returns the key of the lock shared by the nodes of the concurrency group
+func concurrencyLockKey(proj *sdk.Project, wr *sdk.WorkflowRun, n *sdk.Node) (string, error) {
+	c := n.Context.Concurrency
+	var envName string
+	if c.Scope == sdk.ConcurrencyScopeEnvironment {
+		env, has := wr.Workflow.Environments[n.Context.EnvironmentID]
+		if !has {
+			return "", sdk.NewErrorFrom(sdk.ErrWorkflowConcurrencyInvalid, "concurrency group %s of pipeline %s is scoped to an environment but the pipeline has no environment", c.Group, n.Name)
+		}
+		envName = env.Name
+	}
+	return c.Key(proj.Key, envName), nil
+}
+
+// acquireConcurrencyLock tries to take the lock of the concurrency group of the node for the node run.
+// It returns false if the lock is held by another node run, the node run is then queued until the lock is released.
+func acquireConcurrencyLock(ctx context.Context, db gorp.SqlExecutor, proj *sdk.Project, wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun) (*ProcessorReport, bool, error) {
+	report := new(ProcessorReport)
+	if n.Context == nil || n.Context.Concurrency == nil {
+		return report, true, nil
+	}
+
+	ctx, end := observability.Span(ctx, "workflow.acquireConcurrencyLock")
+	defer end()
+
+	key, err := concurrencyLockKey(proj, wr, n)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := lockConcurrencyGroup(db, key); err != nil {
+		return nil, false, err
+	}
+
+	// The node run may already be queued, ie. when it is released from a mutex
+	existing, err := loadNodeRunLockByNodeRunID(ctx, db, nr.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return report, existing.Status == sdk.ConcurrencyLockHolding, nil
+	}
+
+	lock := sdk.WorkflowNodeRunLock{
+		ProjectID:         proj.ID,
+		Key:               key,
+		Status:            sdk.ConcurrencyLockPending,
+		WorkflowID:        wr.WorkflowID,
+		WorkflowName:      wr.Workflow.Name,
+		WorkflowRunID:     wr.ID,
+		Number:            wr.Number,
+		WorkflowNodeRunID: nr.ID,
+		WorkflowNodeName:  nr.WorkflowNodeName,
+		Created:           time.Now(),
+	}
+
+	pendings, err := loadPendingNodeRunLocks(ctx, db, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if n.Context.Concurrency.Policy == sdk.ConcurrencyPolicyCancelPending {
+		for _, p := range pendings {
+			r, err := cancelPendingNodeRun(ctx, db, p, n.Context.Concurrency.Group)
+			if err != nil {
+				return nil, false, err
+			}
+			_, _ = report.Merge(ctx, r, nil)
+		}
+		pendings = nil
+	}
+
+	// Runs queued before this one go first
+	var acquired bool
+	if len(pendings) == 0 {
+		acquired, err = insertNodeRunLockHolder(db, &lock)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if acquired {
+		return report, true, nil
+	}
+
+	if err := insertNodeRunLock(db, &lock); err != nil {
+		return nil, false, err
+	}
+
+	holder, err := loadNodeRunLockHolder(ctx, db, key)
+	if err != nil {
+		return nil, false, err
+	}
+	log.Debug("workflow.acquireConcurrencyLock> node run %d is waiting for %s", nr.ID, key)
+	if holder != nil {
+		AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeConcurrencyLocked.ID,
+			Args: []interface{}{nr.WorkflowNodeName, n.Context.Concurrency.Group, holder.WorkflowName, holder.Number, holder.WorkflowNodeName},
+		})
+		if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+			return nil, false, sdk.WrapError(err, "unable to update workflow run")
+		}
+	}
+	return report, false, nil
+}
+
+// cancelPendingNodeRun stops a node run waiting for a concurrency group in favor of a newer one
+func cancelPendingNodeRun(ctx context.Context, db gorp.SqlExecutor, lock sdk.WorkflowNodeRunLock, group string) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	nr, err := LoadNodeRunByID(db, lock.WorkflowNodeRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load node run %d", lock.WorkflowNodeRunID)
+	}
+	if err := deleteNodeRunLock(db, lock.ID); err != nil {
+		return nil, err
+	}
+	if sdk.StatusIsTerminated(nr.Status) {
+		return report, nil
+	}
+
+	nr.Status = sdk.StatusStopped
+	nr.Done = time.Now()
+	if err := updateNodeRunStatusAndStage(db, nr); err != nil {
+		return nil, sdk.WrapError(err, "unable to stop node run %d", nr.ID)
+	}
+	report.Add(ctx, *nr)
+
+	wr, err := LoadRunByID(db, nr.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow run %d", nr.WorkflowRunID)
+	}
+	AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeConcurrencyCancelled.ID,
+		Args: []interface{}{nr.WorkflowNodeName, group},
+	})
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run %d", wr.ID)
+	}
+	r, err := ResyncWorkflowRunStatus(ctx, db, wr)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to resync workflow run %d", wr.ID)
+	}
+	return report.Merge(ctx, r, nil)
+}
+
+// releaseConcurrencyLock releases the lock held (or awaited) by a terminated node run
+// and executes the next node run waiting for it.
+func releaseConcurrencyLock(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, nr *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	lock, err := loadNodeRunLockByNodeRunID(ctx, db, nr.ID)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return report, nil
+	}
+
+	ctx, end := observability.Span(ctx, "workflow.releaseConcurrencyLock")
+	defer end()
+
+	if err := lockConcurrencyGroup(db, lock.Key); err != nil {
+		return nil, err
+	}
+	// the lock may have been released by another transaction while waiting for the group
+	lock, err = loadNodeRunLockByNodeRunID(ctx, db, nr.ID)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return report, nil
+	}
+
+	if err := deleteNodeRunLock(db, lock.ID); err != nil {
+		return nil, err
+	}
+	if lock.Status != sdk.ConcurrencyLockHolding {
+		return report, nil
+	}
+
+	next, err := loadNextPendingNodeRunLock(ctx, db, lock.Key)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return report, nil
+	}
+	acquired, err := updateNodeRunLockHolder(db, next)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return report, nil
+	}
+
+	waitingRun, err := LoadNodeRunByID(db, next.WorkflowNodeRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load node run %d", next.WorkflowNodeRunID)
+	}
+	workflowRun, err := LoadRunByID(db, waitingRun.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow run %d", waitingRun.WorkflowRunID)
+	}
+	AddWorkflowRunInfo(workflowRun, false, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeConcurrencyRelease.ID,
+		Args: []interface{}{lock.Key, waitingRun.WorkflowNodeName},
+	})
+	if err := UpdateWorkflowRun(ctx, db, workflowRun); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run %d after concurrency group release", workflowRun.ID)
+	}
+
+	log.Debug("workflow.releaseConcurrencyLock> process the node run %d because %s has been released", waitingRun.ID, lock.Key)
+	r, err := executeNodeRun(ctx, db, store, proj, waitingRun)
+	return report.Merge(ctx, r, err)
+}
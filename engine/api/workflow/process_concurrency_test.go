@@ -0,0 +1,234 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+type testConcurrencyProject struct {
+	db       *gorp.DbMap
+	store    cache.Store
+	proj     *sdk.Project
+	pip      sdk.Pipeline
+	user     *sdk.AuthentifiedUser
+	consumer *sdk.AuthConsumer
+}
+
+// testInsertConcurrencyProject creates a project with a pipeline of one job, to be shared by workflows using a concurrency group.
+func testInsertConcurrencyProject(t *testing.T, db *gorp.DbMap, store cache.Store) *testConcurrencyProject {
+	u, _ := assets.InsertAdminUser(t, db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, store, key, key)
+
+	pip := sdk.Pipeline{ProjectID: proj.ID, ProjectKey: proj.Key, Name: "deploy"}
+	require.NoError(t, pipeline.InsertPipeline(db, store, proj, &pip))
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	require.NoError(t, pipeline.InsertStage(db, s))
+	j := &sdk.Job{Enabled: true, Action: sdk.Action{Enabled: true}}
+	require.NoError(t, pipeline.InsertJob(db, j, s.ID, &pip))
+
+	proj, err := project.LoadByID(db, store, proj.ID, project.LoadOptions.WithPipelines, project.LoadOptions.WithGroups)
+	require.NoError(t, err)
+
+	consumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	return &testConcurrencyProject{db: db, store: store, proj: proj, pip: pip, user: u, consumer: consumer}
+}
+
+// insertWorkflow inserts a workflow with one node in the concurrency group "deploy".
+func (p *testConcurrencyProject) insertWorkflow(t *testing.T, name, policy string) *sdk.Workflow {
+	w := sdk.Workflow{
+		Name:       name,
+		ProjectID:  p.proj.ID,
+		ProjectKey: p.proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "deploy",
+				Ref:  "deploy",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID:  p.pip.ID,
+					Concurrency: &sdk.NodeConcurrency{Group: "deploy", Policy: policy},
+				},
+			},
+		},
+	}
+	require.NoError(t, workflow.Insert(context.TODO(), p.db, p.store, &w, p.proj))
+	w1, err := workflow.Load(context.TODO(), p.db, p.store, p.proj, w.Name, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+	return w1
+}
+
+// run starts a run of the workflow and returns it with its node run.
+func (p *testConcurrencyProject) run(t *testing.T, w *sdk.Workflow) (*sdk.WorkflowRun, sdk.WorkflowNodeRun) {
+	wr, err := workflow.CreateRun(p.db, w, nil, p.user)
+	require.NoError(t, err)
+	wr.Workflow = *w
+	_, err = workflow.StartWorkflowRun(context.TODO(), p.db, p.store, p.proj, wr, &sdk.WorkflowRunPostHandlerOption{
+		Manual: &sdk.WorkflowNodeRunManual{Username: p.user.Username},
+	}, p.consumer, nil)
+	require.NoError(t, err)
+	return p.loadRun(t, w, wr.Number)
+}
+
+func (p *testConcurrencyProject) loadRun(t *testing.T, w *sdk.Workflow, number int64) (*sdk.WorkflowRun, sdk.WorkflowNodeRun) {
+	wr, err := workflow.LoadRun(context.TODO(), p.db, p.proj.Key, w.Name, number, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	nodeRuns := wr.WorkflowNodeRuns[w.WorkflowData.Node.ID]
+	require.Len(t, nodeRuns, 1)
+	return wr, nodeRuns[0]
+}
+
+// jobIDs returns the ids of the jobs of a node run that are in the queue.
+func (p *testConcurrencyProject) jobIDs(t *testing.T, nr sdk.WorkflowNodeRun) []int64 {
+	ids, err := workflow.LoadNodeJobRunIDByNodeRunID(p.db, nr.ID)
+	require.NoError(t, err)
+	return ids
+}
+
+func (p *testConcurrencyProject) locks(t *testing.T, wr *sdk.WorkflowRun) []sdk.WorkflowNodeRunLock {
+	locks, err := workflow.LoadNodeRunLocksByWorkflowRunID(context.TODO(), p.db, wr.ID)
+	require.NoError(t, err)
+	return locks
+}
+
+func hasRunInfo(wr *sdk.WorkflowRun, msg *sdk.Message) bool {
+	for _, i := range wr.Infos {
+		if i.Message.ID == msg.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConcurrencyGroupQueue(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	p := testInsertConcurrencyProject(t, db, store)
+	w1 := p.insertWorkflow(t, "deploy-1", "")
+	w2 := p.insertWorkflow(t, "deploy-2", "")
+
+	// The first run takes the group and its job is queued
+	wr1, nr1 := p.run(t, w1)
+	require.Len(t, p.jobIDs(t, nr1), 1)
+	locks := p.locks(t, wr1)
+	require.Len(t, locks, 1)
+	assert.Equal(t, sdk.ConcurrencyLockHolding, locks[0].Status)
+	assert.Equal(t, p.proj.Key+"/deploy", locks[0].Key)
+	assert.Nil(t, locks[0].Holder)
+
+	// The second one waits for the group, the run shows the holder
+	wr2, nr2 := p.run(t, w2)
+	assert.Empty(t, p.jobIDs(t, nr2))
+	assert.Equal(t, sdk.StatusWaiting, nr2.Status)
+	assert.True(t, hasRunInfo(wr2, sdk.MsgWorkflowNodeConcurrencyLocked))
+	locks = p.locks(t, wr2)
+	require.Len(t, locks, 1)
+	assert.Equal(t, sdk.ConcurrencyLockPending, locks[0].Status)
+	require.NotNil(t, locks[0].Holder)
+	assert.Equal(t, w1.Name, locks[0].Holder.WorkflowName)
+	assert.Equal(t, wr1.Number, locks[0].Holder.Number)
+	assert.Equal(t, nr1.ID, locks[0].Holder.WorkflowNodeRunID)
+
+	// When the job of the first run fails, the group is given to the second run
+	jobs := p.jobIDs(t, nr1)
+	job, err := workflow.LoadNodeJobRun(context.TODO(), db, store, jobs[0])
+	require.NoError(t, err)
+	_, err = workflow.UpdateNodeJobRunStatus(context.TODO(), db, store, p.proj, job, sdk.StatusFail)
+	require.NoError(t, err)
+
+	assert.Empty(t, p.locks(t, wr1))
+	wr2, nr2 = p.loadRun(t, w2, wr2.Number)
+	assert.Len(t, p.jobIDs(t, nr2), 1)
+	assert.True(t, hasRunInfo(wr2, sdk.MsgWorkflowNodeConcurrencyRelease))
+	locks = p.locks(t, wr2)
+	require.Len(t, locks, 1)
+	assert.Equal(t, sdk.ConcurrencyLockHolding, locks[0].Status)
+}
+
+func TestConcurrencyGroupCancelPending(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	p := testInsertConcurrencyProject(t, db, store)
+	w1 := p.insertWorkflow(t, "deploy-1", sdk.ConcurrencyPolicyCancelPending)
+	w2 := p.insertWorkflow(t, "deploy-2", sdk.ConcurrencyPolicyCancelPending)
+	w3 := p.insertWorkflow(t, "deploy-3", sdk.ConcurrencyPolicyCancelPending)
+
+	wr1, _ := p.run(t, w1)
+	wr2, _ := p.run(t, w2)
+	require.Len(t, p.locks(t, wr2), 1)
+
+	// A newer run cancels the one waiting for the group, not the holder
+	wr3, nr3 := p.run(t, w3)
+	assert.Empty(t, p.jobIDs(t, nr3))
+	locks := p.locks(t, wr3)
+	require.Len(t, locks, 1)
+	assert.Equal(t, sdk.ConcurrencyLockPending, locks[0].Status)
+
+	wr2, nr2 := p.loadRun(t, w2, wr2.Number)
+	assert.Equal(t, sdk.StatusStopped, nr2.Status)
+	assert.Equal(t, sdk.StatusStopped, wr2.Status)
+	assert.True(t, hasRunInfo(wr2, sdk.MsgWorkflowNodeConcurrencyCancelled))
+	assert.Empty(t, p.locks(t, wr2))
+
+	locks = p.locks(t, wr1)
+	require.Len(t, locks, 1)
+	assert.Equal(t, sdk.ConcurrencyLockHolding, locks[0].Status)
+}
+
+func TestConcurrencyGroupReleaseOnStop(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	p := testInsertConcurrencyProject(t, db, store)
+	w1 := p.insertWorkflow(t, "deploy-1", "")
+	w2 := p.insertWorkflow(t, "deploy-2", "")
+	w3 := p.insertWorkflow(t, "deploy-3", "")
+
+	wr1, nr1 := p.run(t, w1)
+	wr2, nr2 := p.run(t, w2)
+	wr3, nr3 := p.run(t, w3)
+	assert.Empty(t, p.jobIDs(t, nr2))
+	assert.Empty(t, p.jobIDs(t, nr3))
+
+	stopInfos := sdk.SpawnInfo{
+		APITime: time.Now(),
+		Message: sdk.SpawnMsg{ID: sdk.MsgWorkflowNodeStop.ID, Args: []interface{}{p.user.Username}},
+	}
+	dbFunc := func() *gorp.DbMap { return db }
+
+	// Stopping a run waiting for the group removes it from the queue
+	_, err := workflow.StopWorkflowNodeRun(context.TODO(), dbFunc, store, p.proj, nr2, stopInfos)
+	require.NoError(t, err)
+	assert.Empty(t, p.locks(t, wr2))
+	locks := p.locks(t, wr3)
+	require.Len(t, locks, 1)
+	assert.Equal(t, sdk.ConcurrencyLockPending, locks[0].Status)
+
+	// Stopping the holder gives the group to the next run
+	_, err = workflow.StopWorkflowNodeRun(context.TODO(), dbFunc, store, p.proj, nr1, stopInfos)
+	require.NoError(t, err)
+	assert.Empty(t, p.locks(t, wr1))
+	_, nr3 = p.loadRun(t, w3, wr3.Number)
+	assert.Len(t, p.jobIDs(t, nr3), 1)
+	locks = p.locks(t, wr3)
+	require.Len(t, locks, 1)
+	assert.Equal(t, sdk.ConcurrencyLockHolding, locks[0].Status)
+}
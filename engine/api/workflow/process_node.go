@@ -384,6 +384,16 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		//Mutex is free, continue
 	}
 
+	//Check the concurrency group of the node, the node run is queued if the group is held by another node run
+	r0, acquired, err := acquireConcurrencyLock(ctx, db, proj, wr, n, nr)
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "unable to check concurrency group")
+	}
+	_, _ = report.Merge(ctx, r0, nil)
+	if !acquired {
+		return report, true, nil
+	}
+
 	//Execute the node run !
 	r1, err := executeNodeRun(ctx, db, store, proj, nr)
 	if err != nil {
@@ -556,5 +566,14 @@ func computeBuildParameters(wr *sdk.WorkflowRun, run *sdk.WorkflowNodeRun, paren
 		})
 	}
 
+	// ROLLBACK OF A DEPLOYMENT
+	if manual != nil && manual.RollbackVersion != "" {
+		params = append(params, sdk.Parameter{
+			Name:  sdk.DeployRollbackVersionParameter,
+			Type:  sdk.StringParameter,
+			Value: manual.RollbackVersion,
+		})
+	}
+
 	return params, nil
 }
@@ -14,7 +14,8 @@ import (
 )
 
 func getNodeJobRunParameters(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun, stage *sdk.Stage) ([]sdk.Parameter, *sdk.MultiError) {
-	params := run.BuildParameters
+	// copy the build parameters, they must not be shared by the job runs
+	params := append([]sdk.Parameter{}, run.BuildParameters...)
 	tmp := map[string]string{
 		"cds.stage": stage.Name,
 		"cds.job":   j.Action.Name,
@@ -77,6 +77,10 @@ func processAllNodesTriggers(ctx context.Context, db gorp.SqlExecutor, store cac
 	for k := range wr.WorkflowNodeRuns {
 		// only check the last node run
 		nodeRun := &wr.WorkflowNodeRuns[k][0]
+		// a rollback only redeploys a previous version, it does not trigger the children again
+		if isRollbackNodeRun(nodeRun) {
+			continue
+		}
 
 		//Trigger only if the node is over (successful or not)
 		if sdk.StatusIsTerminated(nodeRun.Status) && nodeRun.Status != sdk.StatusNeverBuilt {
@@ -121,7 +125,7 @@ func processAllJoins(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 				break
 			}
 
-			if !sdk.StatusIsTerminated(nodeRun.Status) || nodeRun.Status == sdk.StatusFail || nodeRun.Status == sdk.StatusNeverBuilt || nodeRun.Status == sdk.StatusStopped {
+			if !sdk.StatusIsTerminated(nodeRun.Status) || nodeRun.Status == sdk.StatusFail || nodeRun.Status == sdk.StatusNeverBuilt || nodeRun.Status == sdk.StatusStopped || isRollbackNodeRun(nodeRun) {
 				ok = false
 				break
 			}
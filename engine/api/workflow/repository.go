@@ -55,7 +55,7 @@ func CreateFromRepository(ctx context.Context, db *gorp.DbMap, store cache.Store
 		return nil, sdk.WrapError(err, "unable to post repository operation")
 	}
 
-	if err := pollRepositoryOperation(ctx, db, store, &ope); err != nil {
+	if err := PollRepositoryOperation(ctx, db, store, &ope); err != nil {
 		return nil, sdk.WrapError(err, "cannot analyse repository")
 	}
 
@@ -226,7 +226,8 @@ func extractFromCDSFiles(ctx context.Context, tr *tar.Reader) (*exportedEntities
 	return &res, nil
 }
 
-func pollRepositoryOperation(c context.Context, db gorp.SqlExecutor, store cache.Store, ope *sdk.Operation) error {
+// PollRepositoryOperation waits for the end of a repository operation
+func PollRepositoryOperation(c context.Context, db gorp.SqlExecutor, store cache.Store, ope *sdk.Operation) error {
 	tickTimeout := time.NewTicker(10 * time.Minute)
 	tickPoll := time.NewTicker(2 * time.Second)
 	defer tickTimeout.Stop()
@@ -234,10 +235,10 @@ func pollRepositoryOperation(c context.Context, db gorp.SqlExecutor, store cache
 		select {
 		case <-c.Done():
 			if c.Err() != nil {
-				return sdk.WrapError(c.Err(), "pollRepositoryOperation> Exiting")
+				return sdk.WrapError(c.Err(), "PollRepositoryOperation> Exiting")
 			}
 		case <-tickTimeout.C:
-			return sdk.WrapError(sdk.ErrRepoOperationTimeout, "pollRepositoryOperation> Timeout analyzing repository")
+			return sdk.WrapError(sdk.ErrRepoOperationTimeout, "PollRepositoryOperation> Timeout analyzing repository")
 		case <-tickPoll.C:
 			if err := GetRepositoryOperation(c, db, ope); err != nil {
 				return sdk.WrapError(err, "Cannot get repository operation status")
@@ -25,6 +25,7 @@ const (
 	tagGitURL        = "git.url"
 	tagGitHTTPURL    = "git.http_url"
 	tagGitServer     = "git.server"
+	tagRelease       = "release"
 )
 
 //RunFromHook is the entry point to trigger a workflow from a hook
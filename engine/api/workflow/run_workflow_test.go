@@ -621,19 +621,19 @@ queueRun:
 			t.Fatal(err)
 		}
 
-		secrets, err := workflow.LoadSecrets(db, cache, nodeRun, workflowRun, proj.Variable)
+		secrets, err := workflow.LoadSecrets(context.TODO(), db, cache, nodeRun, workflowRun, proj.Variable, nil)
 		assert.NoError(t, err)
 		assert.Len(t, secrets, 1)
 
 		//TestAddLog
-		assert.NoError(t, workflow.AddLog(db, j, &sdk.Log{
+		assert.NoError(t, workflow.AddLog(context.TODO(), db, j, &sdk.Log{
 			Val: "This is a log",
 		}, workflow.DefaultMaxLogSize))
 		if t.Failed() {
 			tx.Rollback()
 			t.FailNow()
 		}
-		assert.NoError(t, workflow.AddLog(db, j, &sdk.Log{
+		assert.NoError(t, workflow.AddLog(context.TODO(), db, j, &sdk.Log{
 			Val: "This is another log",
 		}, workflow.DefaultMaxLogSize))
 		if t.Failed() {
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/fatih/structs"
 	"github.com/go-gorp/gorp"
+	"github.com/ovh/venom"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/event"
@@ -63,6 +65,10 @@ func SendEvent(ctx context.Context, db gorp.SqlExecutor, key string, report *Pro
 		}
 		event.PublishWorkflowNodeJobRun(ctx, db, key, *wr, jobrun)
 	}
+
+	for _, s := range report.secrets {
+		event.PublishWorkflowRunSecretResolved(ctx, s.run, s.nodeRun, s.name, s.ref)
+	}
 }
 
 // ResyncCommitStatus resync commit status for a workflow run
@@ -250,6 +256,16 @@ func sendVCSEventStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 		return err
 	}
 
+	// Check runs report failed tests and vulnerabilities once the node run is over
+	if vcsConf.Checks && sdk.StatusIsTerminated(nodeRun.Status) {
+		nr, err := LoadNodeRunByID(db, nodeRun.ID, LoadRunOptions{WithTests: true, WithVulnerabilities: true})
+		if err != nil {
+			log.Error(ctx, "sendVCSEventStatus> unable to load node run %d: %v", nodeRun.ID, err)
+		} else {
+			eventWNR.Annotations = vcsCheckAnnotations(nr)
+		}
+	}
+
 	if vcsConf.Type == "gerrit" {
 		// Get gerrit variable
 		var project, changeID, branch, revision, url string
@@ -304,6 +320,59 @@ func sendVCSEventStatus(ctx context.Context, db gorp.SqlExecutor, store cache.St
 	return nil
 }
 
+// vcsCheckAnnotations returns the failed tests and the vulnerabilities of a node run
+func vcsCheckAnnotations(nodeRun *sdk.WorkflowNodeRun) []sdk.VCSCheckAnnotation {
+	var annotations []sdk.VCSCheckAnnotation
+	if nodeRun.Tests != nil {
+		for _, ts := range nodeRun.Tests.TestSuites {
+			for _, tc := range ts.TestCases {
+				for _, failures := range [][]venom.Failure{tc.Failures, tc.Errors} {
+					for _, f := range failures {
+						msg := f.Message
+						if f.Value != "" {
+							msg = strings.TrimSpace(msg + "\n" + f.Value)
+						}
+						annotations = append(annotations, sdk.VCSCheckAnnotation{
+							Path:    ts.Name,
+							Level:   sdk.VCSCheckAnnotationFailure,
+							Title:   fmt.Sprintf("Test %s failed", tc.Name),
+							Message: msg,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	for _, v := range nodeRun.VulnerabilitiesReport.Report.Vulnerabilities {
+		if v.Ignored {
+			continue
+		}
+		level := sdk.VCSCheckAnnotationWarning
+		switch v.Severity {
+		case sdk.SeverityHigh, sdk.SeverityCritical, sdk.SeverityDefcon1:
+			level = sdk.VCSCheckAnnotationFailure
+		case sdk.SeverityUnknown, sdk.SeverityNegligible, sdk.SeverityLow:
+			level = sdk.VCSCheckAnnotationNotice
+		}
+		path := v.Origin
+		if path == "" {
+			path = v.Component
+		}
+		title := v.Title
+		if v.CVE != "" {
+			title = v.CVE + ": " + title
+		}
+		annotations = append(annotations, sdk.VCSCheckAnnotation{
+			Path:    path,
+			Level:   level,
+			Title:   title,
+			Message: fmt.Sprintf("%s %s (%s) %s", v.Component, v.Version, v.Severity, v.Description),
+		})
+	}
+	return annotations
+}
+
 func sendVCSPullRequestComment(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun) error {
 	log.Debug("Send pull-request comment for node run %d", nodeRun.ID)
 
@@ -359,6 +428,22 @@ func sendVCSPullRequestComment(ctx context.Context, db gorp.SqlExecutor, store c
 			return nil
 		}
 
+		// Post or update the summary of the whole workflow run once the node run is over
+		if notif.Settings.Template.SummaryComment != nil && *notif.Settings.Template.SummaryComment {
+			if !sdk.StatusIsTerminated(nodeRun.Status) {
+				return nil
+			}
+			for _, pr := range prs {
+				if pr.Head.Branch.DisplayID == nodeRun.VCSBranch && pr.Head.Branch.LatestCommit == nodeRun.VCSHash && !pr.Merged && !pr.Closed {
+					if err := sendVCSPullRequestSummary(ctx, db, client, proj, wr, app.RepositoryFullname, pr); err != nil {
+						log.Error(ctx, "sendVCSPullRequestComment> unable to send PR summary: %v", err)
+					}
+					break
+				}
+			}
+			return nil
+		}
+
 		//Send comment on pull request
 		if nodeRun.Status == sdk.StatusFail || nodeRun.Status == sdk.StatusStopped || notif.Settings.OnSuccess == sdk.UserNotificationAlways {
 			for _, pr := range prs {
@@ -0,0 +1,193 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// maxSummaryFailedTests limits the number of failed tests listed in a pull request summary
+const maxSummaryFailedTests = 20
+
+// vcsSummaryMarker returns the marker used to find the summary comment of a workflow on a pull request.
+// It is an empty markdown link reference, which is not rendered by any VCS, unlike HTML comments on Bitbucket Server.
+func vcsSummaryMarker(projectKey, workflowName string) string {
+	return fmt.Sprintf("[//]: # (cds-summary:%s/%s)", projectKey, workflowName)
+}
+
+// sendVCSPullRequestSummary posts the summary of the workflow run on the pull request, or updates it if it was already posted
+func sendVCSPullRequestSummary(ctx context.Context, db gorp.SqlExecutor, client sdk.VCSAuthorizedClient, proj *sdk.Project, wr *sdk.WorkflowRun, repo string, pr sdk.VCSPullRequest) error {
+	nodeRuns := make([]sdk.WorkflowNodeRun, 0, len(wr.WorkflowNodeRuns))
+	for _, runs := range wr.WorkflowNodeRuns {
+		if len(runs) == 0 {
+			continue
+		}
+		nr, err := LoadNodeRunByID(db, runs[0].ID, LoadRunOptions{WithTests: true, WithCoverage: true, WithVulnerabilities: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run %d", runs[0].ID)
+		}
+		nodeRuns = append(nodeRuns, *nr)
+	}
+
+	body := vcsPullRequestSummary(proj.Key, wr, nodeRuns)
+	marker := vcsSummaryMarker(proj.Key, wr.Workflow.Name)
+
+	comments, err := client.PullRequestComments(ctx, repo, pr.ID)
+	if err != nil {
+		return sdk.WrapError(err, "unable to list comments of pull request %d", pr.ID)
+	}
+	for _, c := range comments {
+		// Only the comments posted by CDS are updated, even if someone else copied the marker
+		if !c.PostedByCDS || !strings.Contains(c.Body, marker) {
+			continue
+		}
+		if c.Body == body {
+			log.Debug("sendVCSPullRequestSummary> summary of %s is up to date on pull request %d", marker, pr.ID)
+			return nil
+		}
+		c.Body = body
+		return client.PullRequestCommentEdit(ctx, repo, pr.ID, c)
+	}
+
+	return client.PullRequestComment(ctx, repo, pr.ID, body)
+}
+
+// vcsPullRequestSummary builds the markdown summary of a workflow run: node statuses, failed tests, coverage and new vulnerabilities
+func vcsPullRequestSummary(projectKey string, wr *sdk.WorkflowRun, nodeRuns []sdk.WorkflowNodeRun) string {
+	sort.Slice(nodeRuns, func(i, j int) bool { return nodeRuns[i].ID < nodeRuns[j].ID })
+
+	var b strings.Builder
+	b.WriteString(vcsSummaryMarker(projectKey, wr.Workflow.Name) + "\n")
+	fmt.Fprintf(&b, "### CDS %s/%s #%d.%d %s\n\n", projectKey, wr.Workflow.Name, wr.Number, wr.LastSubNumber, summaryStatusIcon(wr.Status))
+
+	b.WriteString("| Pipeline | Status | Tests | Coverage | New vulnerabilities |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	var failedTests, matrixJobs []string
+	for _, nr := range nodeRuns {
+		name := nr.WorkflowNodeName
+		if url := sdk.ParameterFind(nr.BuildParameters, "cds.ui.pipeline.run"); url != nil && url.Value != "" {
+			name = fmt.Sprintf("[%s](%s)", name, url.Value)
+		}
+		fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s |\n", name, summaryStatusIcon(nr.Status), nr.Status,
+			summaryTests(nr), summaryCoverage(nr.Coverage), summaryNewVulnerabilities(nr.VulnerabilitiesReport))
+		matrixJobs = append(matrixJobs, summaryMatrixJobs(nr)...)
+
+		if nr.Tests == nil {
+			continue
+		}
+		for _, ts := range nr.Tests.TestSuites {
+			for _, tc := range ts.TestCases {
+				if len(tc.Failures) > 0 || len(tc.Errors) > 0 {
+					failedTests = append(failedTests, fmt.Sprintf("%s: %s / %s", nr.WorkflowNodeName, ts.Name, tc.Name))
+				}
+			}
+		}
+	}
+
+	if len(matrixJobs) > 0 {
+		b.WriteString("\n#### Matrix jobs\n\n")
+		for _, m := range matrixJobs {
+			fmt.Fprintf(&b, "* %s\n", m)
+		}
+	}
+
+	if len(failedTests) > 0 {
+		b.WriteString("\n#### Failed tests\n\n")
+		for i, t := range failedTests {
+			if i == maxSummaryFailedTests {
+				fmt.Fprintf(&b, "* ... and %d more\n", len(failedTests)-maxSummaryFailedTests)
+				break
+			}
+			fmt.Fprintf(&b, "* %s\n", t)
+		}
+	}
+
+	return b.String()
+}
+
+func summaryStatusIcon(status string) string {
+	switch status {
+	case sdk.StatusSuccess:
+		return "✔"
+	case sdk.StatusFail:
+		return "✘"
+	case sdk.StatusStopped:
+		return "■"
+	}
+	return "-"
+}
+
+// summaryMatrixJobs returns a line per matrix job of the node run, with the status of each variant
+func summaryMatrixJobs(nr sdk.WorkflowNodeRun) []string {
+	var lines []string
+	for _, s := range nr.Stages {
+		for _, g := range sdk.GroupRunJobsByJob(s.RunJobs) {
+			if g.RunJobs[0].Matrix == nil {
+				continue
+			}
+			variants := make([]string, 0, len(g.RunJobs)+1)
+			for _, rj := range g.RunJobs {
+				variants = append(variants, fmt.Sprintf("%s %s", summaryStatusIcon(rj.Status), rj.Matrix.Variant))
+			}
+			if n := g.Total - len(g.RunJobs); n > 0 {
+				variants = append(variants, fmt.Sprintf("%d not run", n))
+			}
+			lines = append(lines, fmt.Sprintf("%s / %s %s: %s", nr.WorkflowNodeName, g.Name, summaryStatusIcon(g.Status), strings.Join(variants, " · ")))
+		}
+	}
+	return lines
+}
+
+func summaryTests(nr sdk.WorkflowNodeRun) string {
+	if nr.Tests == nil || nr.Tests.Total == 0 {
+		return "-"
+	}
+	if nr.Tests.TotalKO > 0 {
+		return fmt.Sprintf("%d/%d ✘", nr.Tests.TotalKO, nr.Tests.Total)
+	}
+	return fmt.Sprintf("%d ✔", nr.Tests.Total)
+}
+
+func summaryCoverage(cov sdk.WorkflowNodeRunCoverage) string {
+	if cov.Report.TotalLines == 0 {
+		return "-"
+	}
+	current := float64(cov.Report.CoveredLines) * 100 / float64(cov.Report.TotalLines)
+	res := fmt.Sprintf("%.2f%%", current)
+	if cov.Trend.DefaultBranch.TotalLines > 0 {
+		def := float64(cov.Trend.DefaultBranch.CoveredLines) * 100 / float64(cov.Trend.DefaultBranch.TotalLines)
+		res += fmt.Sprintf(" (%+.2f%%)", current-def)
+	}
+	return res
+}
+
+// summaryNewVulnerabilities compares the vulnerabilities of the node run with the default branch, or with the previous run if there is none
+func summaryNewVulnerabilities(vulns sdk.WorkflowNodeRunVulnerabilityReport) string {
+	ref := vulns.Report.DefaultBranchSummary
+	if ref == nil {
+		ref = vulns.Report.PreviousRunSummary
+	}
+
+	severities := make([]string, 0, len(vulns.Report.Summary))
+	for s := range vulns.Report.Summary {
+		severities = append(severities, s)
+	}
+	sort.Strings(severities)
+
+	var news []string
+	for _, s := range severities {
+		if n := vulns.Report.Summary[s] - ref[s]; n > 0 {
+			news = append(news, fmt.Sprintf("%d %s", n, s))
+		}
+	}
+	if len(news) == 0 {
+		return "-"
+	}
+	return strings.Join(news, ", ")
+}
@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/sguiheux/go-coverage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_vcsPullRequestSummary(t *testing.T) {
+	wr := &sdk.WorkflowRun{
+		Number:        12,
+		LastSubNumber: 1,
+		Status:        sdk.StatusFail,
+		Workflow:      sdk.Workflow{Name: "demo"},
+	}
+	nodeRuns := []sdk.WorkflowNodeRun{
+		{
+			ID:               2,
+			WorkflowNodeName: "deploy",
+			Status:           sdk.StatusSkipped,
+		},
+		{
+			ID:               1,
+			WorkflowNodeName: "build",
+			Status:           sdk.StatusFail,
+			BuildParameters: []sdk.Parameter{
+				{Name: "cds.ui.pipeline.run", Value: "https://cds/project/PROJ/workflow/demo/run/12/node/1"},
+			},
+			Tests: &venom.Tests{
+				Total:   3,
+				TotalKO: 1,
+				TestSuites: []venom.TestSuite{
+					{
+						Name: "api",
+						TestCases: []venom.TestCase{
+							{Name: "TestOK"},
+							{Name: "TestKO", Failures: []venom.Failure{{Message: "boom"}}},
+						},
+					},
+				},
+			},
+			Coverage: sdk.WorkflowNodeRunCoverage{
+				Report: coverage.Report{TotalLines: 200, CoveredLines: 150},
+				Trend: sdk.WorkflowNodeRunCoverageTrends{
+					DefaultBranch: coverage.Report{TotalLines: 200, CoveredLines: 160},
+				},
+			},
+			VulnerabilitiesReport: sdk.WorkflowNodeRunVulnerabilityReport{
+				Report: sdk.WorkflowNodeRunVulnerability{
+					Summary:              map[string]int64{"high": 2, "low": 1},
+					DefaultBranchSummary: map[string]int64{"high": 1, "low": 1},
+				},
+			},
+			Stages: []sdk.Stage{{
+				Name: "Test",
+				RunJobs: []sdk.WorkflowNodeJobRun{
+					{
+						Status: sdk.StatusSuccess,
+						Job:    sdk.ExecutedJob{Job: sdk.Job{PipelineActionID: 1, Action: sdk.Action{Name: "unit"}}},
+						Matrix: &sdk.WorkflowNodeJobRunMatrix{Index: 0, Total: 3, Variant: sdk.JobMatrixVariant{"go": "1.13"}},
+					},
+					{
+						Status: sdk.StatusFail,
+						Job:    sdk.ExecutedJob{Job: sdk.Job{PipelineActionID: 1, Action: sdk.Action{Name: "unit"}}},
+						Matrix: &sdk.WorkflowNodeJobRunMatrix{Index: 1, Total: 3, Variant: sdk.JobMatrixVariant{"go": "1.14"}},
+					},
+					{
+						Status: sdk.StatusSuccess,
+						Job:    sdk.ExecutedJob{Job: sdk.Job{PipelineActionID: 2, Action: sdk.Action{Name: "lint"}}},
+					},
+				},
+			}},
+		},
+	}
+
+	summary := vcsPullRequestSummary("PROJ", wr, nodeRuns)
+	assert.Equal(t, `[//]: # (cds-summary:PROJ/demo)
+### CDS PROJ/demo #12.1 ✘
+
+| Pipeline | Status | Tests | Coverage | New vulnerabilities |
+|---|---|---|---|---|
+| [build](https://cds/project/PROJ/workflow/demo/run/12/node/1) | ✘ Fail | 1/3 ✘ | 75.00% (-5.00%) | 1 high |
+| deploy | - Skipped | - | - | - |
+
+#### Matrix jobs
+
+* build / unit ✘: ✔ go=1.13 · ✘ go=1.14 · 1 not run
+
+#### Failed tests
+
+* build: api / TestKO
+`, summary)
+}
+
+// summaryVCSClient records the comments posted and edited on a pull request
+type summaryVCSClient struct {
+	sdk.VCSAuthorizedClient
+	comments []sdk.VCSPullRequestComment
+	posted   []string
+	edited   []sdk.VCSPullRequestComment
+}
+
+func (c *summaryVCSClient) PullRequestComments(context.Context, string, int) ([]sdk.VCSPullRequestComment, error) {
+	return c.comments, nil
+}
+
+func (c *summaryVCSClient) PullRequestComment(_ context.Context, _ string, _ int, body string) error {
+	c.posted = append(c.posted, body)
+	return nil
+}
+
+func (c *summaryVCSClient) PullRequestCommentEdit(_ context.Context, _ string, _ int, comment sdk.VCSPullRequestComment) error {
+	c.edited = append(c.edited, comment)
+	return nil
+}
+
+func Test_sendVCSPullRequestSummary(t *testing.T) {
+	proj := &sdk.Project{Key: "PROJ"}
+	wr := &sdk.WorkflowRun{Number: 12, Status: sdk.StatusSuccess, Workflow: sdk.Workflow{Name: "demo"}}
+	pr := sdk.VCSPullRequest{ID: 5}
+	marker := vcsSummaryMarker(proj.Key, wr.Workflow.Name)
+
+	// A comment with the marker which was not posted by CDS is left untouched
+	client := &summaryVCSClient{comments: []sdk.VCSPullRequestComment{
+		{ID: 1, Body: marker + "\ncopied by someone else", User: sdk.VCSAuthor{Name: "alice"}},
+	}}
+	require.NoError(t, sendVCSPullRequestSummary(context.TODO(), nil, client, proj, wr, "cds/demo", pr))
+	assert.Empty(t, client.edited)
+	require.Len(t, client.posted, 1)
+	assert.Contains(t, client.posted[0], marker)
+
+	// The comment posted by CDS is updated
+	client.comments = append(client.comments, sdk.VCSPullRequestComment{ID: 2, Body: marker + "\nprevious run", PostedByCDS: true})
+	client.posted = nil
+	require.NoError(t, sendVCSPullRequestSummary(context.TODO(), nil, client, proj, wr, "cds/demo", pr))
+	assert.Empty(t, client.posted)
+	require.Len(t, client.edited, 1)
+	assert.Equal(t, int64(2), client.edited[0].ID)
+}
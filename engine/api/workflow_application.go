@@ -115,6 +115,10 @@ func (api *API) releaseApplicationWorkflowHandler() service.Handler {
 			}
 		}
 
+		if err := workflow.TagRunAsReleased(api.mustDB(), workflowRun, req.TagName); err != nil {
+			return err
+		}
+
 		return nil
 	}
 }
@@ -31,6 +31,42 @@ func (api *API) getWorkflowHooksHandler() service.Handler {
 	}
 }
 
+func (api *API) postWorkflowHookSecretRotateHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		uuid := vars["uuid"]
+
+		p, err := project.Load(api.mustDB(), api.Cache, key, project.LoadOptions.WithIntegrations)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, p, workflowName, workflow.LoadOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow %s/%s", key, workflowName)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "unable to start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		h, err := workflow.RotateHookSecret(ctx, tx, api.Cache, p, wf, uuid)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "unable to commit transaction")
+		}
+
+		return service.WriteJSON(w, h, http.StatusOK)
+	}
+}
+
 func (api *API) getWorkflowOutgoingHookModelsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		m, err := workflow.LoadOutgoingHookModels(api.mustDB())
@@ -263,7 +299,7 @@ func (api *API) postWorkflowJobHookCallbackHandler() service.Handler {
 			return sdk.WrapError(err, "Cannot load project variable")
 		}
 
-		secrets, errSecret := workflow.LoadSecrets(tx, api.Cache, nil, wr, pv)
+		secrets, errSecret := workflow.LoadSecrets(ctx, tx, api.Cache, nil, wr, pv, nil)
 		if errSecret != nil {
 			return sdk.WrapError(errSecret, "postWorkflowJobHookCallbackHandler> Cannot load secrets")
 		}
@@ -322,7 +358,7 @@ func (api *API) getWorkflowJobHookDetailsHandler() service.Handler {
 			return sdk.WrapError(err, "cannot load project variable")
 		}
 
-		secrets, errSecret := workflow.LoadSecrets(db, api.Cache, nil, wr, pv)
+		secrets, errSecret := workflow.LoadSecrets(ctx, db, api.Cache, nil, wr, pv, nil)
 		if errSecret != nil {
 			return sdk.WrapError(errSecret, "cannot load secrets")
 		}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,9 +18,12 @@ import (
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -265,3 +272,165 @@ func Test_putWorkflowHookModelHandlerAsLambdaUser(t *testing.T) {
 	assert.Equal(t, 403, rec.Code)
 
 }
+
+func Test_postWorkflowHookSecretRotateHandler(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(t, db)
+	require.NoError(t, workflow.CreateBuiltinWorkflowHookModels(db))
+	repoHookModel, err := workflow.LoadHookModelByName(db, sdk.RepositoryWebHookModel.Name)
+	require.NoError(t, err)
+
+	mockVCSservice, _ := assets.InsertService(t, db, "Test_postWorkflowHookSecretRotateHandler_TypeVCS", services.TypeVCS)
+	defer func() {
+		_ = services.Delete(db, mockVCSservice)
+	}()
+	mockHookservice, _ := assets.InsertService(t, db, "Test_postWorkflowHookSecretRotateHandler_TypeHooks", services.TypeHooks)
+	defer func() {
+		_ = services.Delete(db, mockHookservice)
+	}()
+
+	// Calls to the repository and to the hooks service, in order
+	var calls []string
+	var failHooksService bool
+	services.HTTPClient = mock(
+		func(r *http.Request) (*http.Response, error) {
+			body := new(bytes.Buffer)
+			w := new(http.Response)
+			enc := json.NewEncoder(body)
+			w.Body = ioutil.NopCloser(body)
+
+			switch r.URL.String() {
+			case "/vcs/github/repos/foo/bar/branches":
+				if err := enc.Encode([]sdk.VCSBranch{{DisplayID: "master", Default: true}}); err != nil {
+					return writeError(w, err)
+				}
+			case "/task/bulk":
+				hooks := map[string]sdk.NodeHook{}
+				if err := service.UnmarshalBody(r, &hooks); err != nil {
+					return nil, sdk.WithStack(err)
+				}
+				for _, h := range hooks {
+					calls = append(calls, "hooks:"+h.Config[sdk.HookConfigWebHookSecret].Value)
+				}
+				if failHooksService {
+					return writeError(w, sdk.ErrUnknownError)
+				}
+				if err := enc.Encode(hooks); err != nil {
+					return writeError(w, err)
+				}
+			case "/vcs/github/webhooks":
+				if err := enc.Encode(repositoriesmanager.WebhooksInfos{WebhooksSupported: true}); err != nil {
+					return writeError(w, err)
+				}
+			case "/vcs/github/repos/foo/bar/hooks":
+				hook := sdk.VCSHook{}
+				if err := service.UnmarshalBody(r, &hook); err != nil {
+					return nil, sdk.WithStack(err)
+				}
+				hook.ID = "666"
+				if err := enc.Encode(hook); err != nil {
+					return writeError(w, err)
+				}
+			case "/vcs/github/repos/foo/bar/hooks?url=&id=666":
+				hook := sdk.VCSHook{}
+				if err := service.UnmarshalBody(r, &hook); err != nil {
+					return nil, sdk.WithStack(err)
+				}
+				calls = append(calls, "vcs:"+hook.Secret)
+				if err := enc.Encode(hook); err != nil {
+					return writeError(w, err)
+				}
+			default:
+				t.Fatalf("unknown route %s", r.URL.String())
+			}
+			return w, nil
+		},
+	)
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key)
+	require.NoError(t, repositoriesmanager.InsertForProject(db, proj, &sdk.ProjectVCSServer{
+		Name: "github",
+		Data: map[string]string{"token": "foo", "secret": "bar"},
+	}))
+	pip := sdk.Pipeline{Name: "pipeline1", ProjectID: proj.ID}
+	require.NoError(t, pipeline.InsertPipeline(db, api.Cache, proj, &pip))
+	app := sdk.Application{
+		ProjectID:          proj.ID,
+		Name:               sdk.RandomString(10),
+		RepositoryFullname: "foo/bar",
+		VCSServer:          "github",
+	}
+	require.NoError(t, application.Insert(db, api.Cache, proj, &app))
+	require.NoError(t, repositoriesmanager.InsertForApplication(db, &app, proj.Key))
+
+	wf := sdk.Workflow{
+		Name: sdk.RandomString(10),
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Type:    sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{PipelineID: pip.ID, ApplicationID: app.ID},
+				Hooks: []sdk.NodeHook{{
+					Config:        repoHookModel.DefaultConfig.Clone(),
+					HookModelName: repoHookModel.Name,
+					HookModelID:   repoHookModel.ID,
+				}},
+			},
+		},
+	}
+	uri := router.GetRoute("POST", api.postWorkflowHandler, map[string]string{"permProjectKey": proj.Key})
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, &wf)
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 201, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &wf))
+	require.Len(t, wf.WorkflowData.Node.Hooks, 1)
+	uuid := wf.WorkflowData.Node.Hooks[0].UUID
+
+	loadSecret := func() string {
+		w, err := workflow.Load(context.TODO(), db, api.Cache, proj, wf.Name, workflow.LoadOptions{})
+		require.NoError(t, err)
+		h, has := w.WorkflowData.GetHooks()[uuid]
+		require.True(t, has)
+		return h.Config[sdk.HookConfigWebHookSecret].Value
+	}
+	secret := loadSecret()
+	require.NotEmpty(t, secret)
+
+	uri = router.GetRoute("POST", api.postWorkflowHookSecretRotateHandler, map[string]string{
+		"key":              proj.Key,
+		"permWorkflowName": wf.Name,
+		"uuid":             uuid,
+	})
+	require.NotEmpty(t, uri)
+
+	// If the hooks service can't be updated, the previous secret is restored on the repository
+	calls = nil
+	failHooksService = true
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 500, rec.Code)
+	require.True(t, len(calls) >= 3, "calls: %v", calls)
+	newSecret := strings.TrimPrefix(calls[0], "vcs:")
+	assert.NotEqual(t, secret, newSecret)
+	assert.Equal(t, "hooks:"+newSecret, calls[1])
+	assert.Equal(t, "vcs:"+secret, calls[len(calls)-1])
+	assert.Equal(t, secret, loadSecret())
+
+	// Otherwise the repository is updated first and the hooks service last
+	calls = nil
+	failHooksService = false
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+	var h sdk.NodeHook
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &h))
+	newSecret = h.Config[sdk.HookConfigWebHookSecret].Value
+	assert.NotEqual(t, secret, newSecret)
+	assert.Equal(t, []string{"vcs:" + newSecret, "hooks:" + newSecret}, calls)
+	assert.Equal(t, newSecret, loadSecret())
+}
@@ -140,7 +140,7 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 		return nil, sdk.WrapError(err, "Cannot load project variable")
 	}
 
-	secrets, errSecret := workflow.LoadSecrets(tx, store, noderun, workflowRun, pv)
+	secrets, errSecret := workflow.LoadSecrets(ctx, tx, store, noderun, workflowRun, pv, report)
 	if errSecret != nil {
 		return nil, sdk.WrapError(errSecret, "Cannot load secrets")
 	}
@@ -392,6 +392,11 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 		return nil, sdk.WrapError(errj, "cannot load node run job %d", res.BuildID)
 	}
 
+	// the job may have been timed out by the api and put back in queue for another worker
+	if job.Job.WorkerID != wr.ID {
+		return nil, sdk.NewErrorFrom(sdk.ErrJobNotBooked, "job %d is not taken by worker %s", job.ID, wr.Name)
+	}
+
 	observability.Current(ctx,
 		observability.Tag(observability.TagWorkflowNodeJobRun, res.BuildID),
 		observability.Tag(observability.TagWorkflowNodeRun, job.WorkflowNodeRunID),
@@ -459,6 +464,20 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 		return nil, sdk.WrapError(err, "Cannot update worker %s status", wr.ID)
 	}
 
+	// Put the job back in queue if it should be retried
+	retried, err := workflow.RetryNodeJobRun(ctx, tx, store, job, *res)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot retry job %d", job.ID)
+	}
+	if retried {
+		if err := tx.Commit(); err != nil {
+			return nil, sdk.WrapError(err, "Cannot commit tx")
+		}
+		report := new(workflow.ProcessorReport)
+		report.Add(ctx, *job)
+		return report, nil
+	}
+
 	// Update action status
 	log.Debug("postJobResult> Updating %d to %s in queue", job.ID, res.Status)
 	newDBFunc := func() *gorp.DbMap {
@@ -527,7 +546,7 @@ func (api *API) postWorkflowJobLogsHandler() service.Handler {
 
 		log.Debug("postWorkflowJobLogsHandler> Logs: %+v", logs)
 
-		if err := workflow.AddLog(api.mustDB(), pbJob, &logs, api.Config.Log.StepMaxSize); err != nil {
+		if err := workflow.AddLog(ctx, api.mustDB(), pbJob, &logs, api.Config.Log.StepMaxSize); err != nil {
 			return err
 		}
 
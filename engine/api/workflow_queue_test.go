@@ -491,6 +491,117 @@ func Test_postWorkflowJobResultHandler(t *testing.T) {
 	assert.Contains(t, rootRun.BuildParameters, sdk.Parameter{Name: "cds.build.newVar", Type: sdk.StringParameter, Value: "newVal"})
 }
 
+func Test_postWorkflowJobResultHandlerWithRetry(t *testing.T) {
+	api, _, router, end := newTestAPI(t)
+	defer end()
+	ctx := testRunWorkflow(t, api, router)
+	testGetWorkflowJobAsWorker(t, api, router, &ctx)
+	require.NotNil(t, ctx.job)
+
+	// Retry the job once when it fails with exit code 3
+	job, err := workflow.LoadNodeJobRun(context.TODO(), api.mustDB(), api.Cache, ctx.job.ID)
+	require.NoError(t, err)
+	job.Job.Action.Retry = &sdk.ActionRetry{Max: 1, On: []string{"3"}}
+	require.NoError(t, workflow.UpdateNodeJobRun(context.TODO(), api.mustDB(), job))
+
+	takeAndFail := func(exitCode int) {
+		uri := router.GetRoute("POST", api.postTakeWorkflowJobHandler, map[string]string{
+			"id": fmt.Sprintf("%d", ctx.job.ID),
+		})
+		test.NotEmpty(t, uri)
+		req := assets.NewJWTAuthentifiedRequest(t, ctx.workerToken, "POST", uri, nil)
+		rec := httptest.NewRecorder()
+		router.Mux.ServeHTTP(rec, req)
+		require.Equal(t, 200, rec.Code)
+
+		uri = router.GetRoute("POST", api.postWorkflowJobResultHandler, map[string]string{
+			"permJobID": fmt.Sprintf("%d", ctx.job.ID),
+		})
+		test.NotEmpty(t, uri)
+		req = assets.NewJWTAuthentifiedRequest(t, ctx.workerToken, "POST", uri, sdk.Result{
+			Duration:   "10",
+			Status:     sdk.StatusFail,
+			Reason:     "command failure",
+			ExitCode:   &exitCode,
+			RemoteTime: time.Now(),
+			BuildID:    ctx.job.ID,
+		})
+		rec = httptest.NewRecorder()
+		router.Mux.ServeHTTP(rec, req)
+		require.Equal(t, 204, rec.Code)
+	}
+
+	// First failure matches the retry policy, the job is back in queue
+	takeAndFail(3)
+	job, err = workflow.LoadNodeJobRun(context.TODO(), api.mustDB(), api.Cache, ctx.job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusWaiting, job.Status)
+	assert.Equal(t, 1, job.Attempt)
+	assert.Empty(t, job.Job.WorkerID)
+
+	// Second failure exceeds the max attempts, the job fails
+	takeAndFail(3)
+	nodeRun, err := workflow.LoadNodeRunByID(api.mustDB(), ctx.run.RootRun().ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, nodeRun.Status)
+	require.Len(t, nodeRun.Stages[0].RunJobs, 1)
+	assert.Equal(t, 1, nodeRun.Stages[0].RunJobs[0].Attempt)
+}
+
+func Test_postWorkflowJobResultHandlerAfterTimeout(t *testing.T) {
+	api, _, router, end := newTestAPI(t)
+	defer end()
+	ctx := testRunWorkflow(t, api, router)
+	testGetWorkflowJobAsWorker(t, api, router, &ctx)
+	require.NotNil(t, ctx.job)
+
+	job, err := workflow.LoadNodeJobRun(context.TODO(), api.mustDB(), api.Cache, ctx.job.ID)
+	require.NoError(t, err)
+	job.Job.Action.Timeout = "1m"
+	job.Job.Action.Retry = &sdk.ActionRetry{Max: 1, On: []string{sdk.ActionRetryOnTimeout}}
+	require.NoError(t, workflow.UpdateNodeJobRun(context.TODO(), api.mustDB(), job))
+
+	uri := router.GetRoute("POST", api.postTakeWorkflowJobHandler, map[string]string{
+		"id": fmt.Sprintf("%d", ctx.job.ID),
+	})
+	test.NotEmpty(t, uri)
+	req := assets.NewJWTAuthentifiedRequest(t, ctx.workerToken, "POST", uri, nil)
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+
+	// The api times out the job and puts it back in queue
+	job, err = workflow.LoadNodeJobRun(context.TODO(), api.mustDB(), api.Cache, ctx.job.ID)
+	require.NoError(t, err)
+	retried, err := workflow.RetryNodeJobRun(context.TODO(), api.mustDB(), api.Cache, job, sdk.Result{
+		BuildID:  job.ID,
+		Status:   sdk.StatusFail,
+		TimedOut: true,
+	})
+	require.NoError(t, err)
+	require.True(t, retried)
+
+	// The late result of the worker is ignored
+	uri = router.GetRoute("POST", api.postWorkflowJobResultHandler, map[string]string{
+		"permJobID": fmt.Sprintf("%d", ctx.job.ID),
+	})
+	test.NotEmpty(t, uri)
+	req = assets.NewJWTAuthentifiedRequest(t, ctx.workerToken, "POST", uri, sdk.Result{
+		Duration:   "10",
+		Status:     sdk.StatusSuccess,
+		RemoteTime: time.Now(),
+		BuildID:    ctx.job.ID,
+	})
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 400, rec.Code)
+
+	job, err = workflow.LoadNodeJobRun(context.TODO(), api.mustDB(), api.Cache, ctx.job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusWaiting, job.Status)
+	assert.Equal(t, 1, job.Attempt)
+}
+
 func Test_postWorkflowJobTestsResultsHandler(t *testing.T) {
 	api, _, router, end := newTestAPI(t)
 	defer end()
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/purge"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) applyWorkflowRetentionPolicy(ctx context.Context, r *http.Request, dryRun bool) ([]sdk.WorkflowRunRetentionResult, error) {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	name := vars["permWorkflowName"]
+
+	proj, err := project.Load(api.mustDB(), api.Cache, key)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load project %s", key)
+	}
+
+	wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, workflow.LoadOptions{Minimal: true})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load workflow %s", name)
+	}
+
+	return purge.ApplyRetentionPolicy(ctx, api.mustDB(), *wf, dryRun, api.Metrics.WorkflowRunsMarkToDelete)
+}
+
+// getWorkflowPurgeDryRunHandler returns for each run of the workflow if it will be kept by the retention policy.
+func (api *API) getWorkflowPurgeDryRunHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		res, err := api.applyWorkflowRetentionPolicy(ctx, r, true)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+// postWorkflowPurgeHandler marks to delete the runs of the workflow that are not kept by the retention policy.
+func (api *API) postWorkflowPurgeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		res, err := api.applyWorkflowRetentionPolicy(ctx, r, false)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
@@ -15,11 +15,13 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/authentication"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/purge"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -349,6 +351,12 @@ func (api *API) getWorkflowRunHandler() service.Handler {
 			}
 		}
 
+		// Concurrency groups held or awaited by the run, with the holder of the awaited ones
+		run.ConcurrencyLocks, err = workflow.LoadNodeRunLocksByWorkflowRunID(ctx, api.mustDB(), run.ID)
+		if err != nil {
+			return err
+		}
+
 		run.Translate(r.Header.Get("Accept-Language"))
 
 		return service.WriteJSON(w, run, http.StatusOK)
@@ -946,6 +954,81 @@ func (api *API) postWorkflowRunHandler() service.Handler {
 	}
 }
 
+// postWorkflowRunCheckRerunHandler runs again a workflow node on behalf of the VCS user that asked for it from a check run.
+// The VCS user must be linked to a CDS user that can execute the node.
+func (api *API) postWorkflowRunCheckRerunHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if _, isService := api.isService(ctx); !isService {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+
+		var rerun sdk.WorkflowCheckRunRerun
+		if err := service.UnmarshalBody(r, &rerun); err != nil {
+			return err
+		}
+
+		c, err := authentication.LoadConsumerByTypeAndUserExternalID(ctx, api.mustDB(), rerun.ConsumerType, rerun.ExternalUserID,
+			authentication.LoadConsumerOptions.WithAuthentifiedUser)
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "no CDS user is linked to %s user %s", rerun.ConsumerType, rerun.ExternalUserID)
+			}
+			return err
+		}
+
+		p, err := project.Load(api.mustDB(), api.Cache, key,
+			project.LoadOptions.WithVariables,
+			project.LoadOptions.WithFeatures,
+			project.LoadOptions.WithIntegrations,
+			project.LoadOptions.WithApplicationVariables,
+			project.LoadOptions.WithApplicationWithDeploymentStrategies,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithPipelines,
+		)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		lastRun, err := workflow.LoadRun(ctx, api.mustDB(), key, name, number, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run")
+		}
+
+		fromNode := lastRun.Workflow.WorkflowData.NodeByID(rerun.FromNodeID)
+		if fromNode == nil {
+			return sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "unable to find node %d", rerun.FromNodeID)
+		}
+		if !permission.AccessToWorkflowNode(ctx, api.mustDB(), &lastRun.Workflow, fromNode, c, sdk.PermissionReadExecute) {
+			return sdk.WrapError(sdk.ErrNoPermExecution, "user %s has not enough right on node %s", c.GetUsername(), fromNode.Name)
+		}
+
+		opts := &sdk.WorkflowRunPostHandlerOption{
+			Number:      &number,
+			FromNodeIDs: []int64{rerun.FromNodeID},
+			Manual: &sdk.WorkflowNodeRunManual{
+				Username: c.GetUsername(),
+				Fullname: c.GetFullname(),
+				Email:    c.GetEmail(),
+			},
+		}
+		lastRun.Status = sdk.StatusWaiting
+
+		sdk.GoRoutine(context.Background(), fmt.Sprintf("api.initWorkflowRun-%d", lastRun.ID), func(ctx context.Context) {
+			api.initWorkflowRun(ctx, api.mustDB(), api.Cache, p, &lastRun.Workflow, lastRun, opts, c)
+		}, api.PanicDump())
+
+		return service.WriteJSON(w, lastRun, http.StatusAccepted)
+	}
+}
+
 func (api *API) initWorkflowRun(ctx context.Context, db *gorp.DbMap, cache cache.Store, p *sdk.Project, wf *sdk.Workflow,
 	wfRun *sdk.WorkflowRun, opts *sdk.WorkflowRunPostHandlerOption, u *sdk.AuthConsumer) {
 	var asCodeInfosMsg []sdk.Message
@@ -1016,6 +1099,8 @@ func (api *API) initWorkflowRun(ctx context.Context, db *gorp.DbMap, cache cache
 			}
 		}
 		wfRun.Workflow = *wf
+		// Webhook secrets must not be visible in runs
+		wfRun.Workflow.FilterHooksConfig(sdk.HookConfigWebHookSecret)
 	}
 
 	r1, errS := workflow.StartWorkflowRun(ctx, db, cache, p, wfRun, opts, u, asCodeInfosMsg)
@@ -1030,6 +1115,12 @@ func (api *API) initWorkflowRun(ctx context.Context, db *gorp.DbMap, cache cache
 
 	// Purge workflow run
 	sdk.GoRoutine(ctx, "workflow.PurgeWorkflowRun", func(ctx context.Context) {
+		if wf.RetentionPolicy != nil {
+			if _, err := purge.ApplyRetentionPolicy(ctx, db, *wf, false, api.Metrics.WorkflowRunsMarkToDelete); err != nil {
+				log.Error(ctx, "purge.ApplyRetentionPolicy> error %v", err)
+			}
+			return
+		}
 		if err := workflow.PurgeWorkflowRun(ctx, db, *wf, api.Metrics.WorkflowRunsMarkToDelete); err != nil {
 			log.Error(ctx, "workflow.PurgeWorkflowRun> error %v", err)
 		}
@@ -1318,7 +1409,7 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 				stepOrder, runJobID, nodeRunID, number, workflowName, projectKey)
 		}
 
-		logs, errL := workflow.LoadStepLogs(api.mustDB(), runJobID, stepOrder)
+		logs, errL := workflow.LoadStepLogs(ctx, api.mustDB(), runJobID, stepOrder)
 		if errL != nil {
 			return sdk.WrapError(errL, "cannot load log for runJob %d on step %d", runJobID, stepOrder)
 		}
@@ -2213,13 +2213,13 @@ func initGetWorkflowNodeRunJobTest(t *testing.T, api *API, db *gorp.DbMap) (*sdk
 	require.NoError(t, errUJ)
 
 	// Add log
-	require.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	require.NoError(t, workflow.AddLog(context.TODO(), api.mustDB(), jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
 	}, 15))
 
 	// Add truncated log
-	require.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	require.NoError(t, workflow.AddLog(context.TODO(), api.mustDB(), jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
 	}, 15))
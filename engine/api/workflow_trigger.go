@@ -91,6 +91,9 @@ func (api *API) getWorkflowTriggerConditionHandler() service.Handler {
 		if sdk.ParameterFind(params, "git.tag") == nil {
 			data.ConditionNames = append(data.ConditionNames, "git.tag")
 		}
+		if sdk.ParameterFind(params, "git.changed_files") == nil {
+			data.ConditionNames = append(data.ConditionNames, "git.changed_files")
+		}
 
 		for _, p := range params {
 			data.ConditionNames = append(data.ConditionNames, p.Name)
@@ -112,6 +115,7 @@ func (api *API) getWorkflowTriggerHookConditionHandler() service.Handler {
 
 		data.ConditionNames = append(data.ConditionNames, sdk.BasicGitVariableNames...)
 		data.ConditionNames = append(data.ConditionNames, "git.tag")
+		data.ConditionNames = append(data.ConditionNames, "git.changed_files")
 		data.ConditionNames = append(data.ConditionNames, "payload")
 
 		sort.Strings(data.ConditionNames)
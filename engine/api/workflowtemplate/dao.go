@@ -224,6 +224,21 @@ func GetInstancesByTemplateIDAndProjectIDAndRequestWorkflowName(db gorp.SqlExecu
 	return wtis, nil
 }
 
+// GetInstancesByProjectIDAndPendingPullRequestURL returns all workflow template instances of a project with a pending version
+// proposed in given pull request.
+func GetInstancesByProjectIDAndPendingPullRequestURL(db gorp.SqlExecutor, projectID int64, pullRequestURL string) ([]sdk.WorkflowTemplateInstance, error) {
+	wtis := []sdk.WorkflowTemplateInstance{}
+
+	if _, err := db.Select(&wtis,
+		"SELECT * FROM workflow_template_instance WHERE project_id = $1 AND (pending->>'pull_request_url')::text = $2",
+		projectID, pullRequestURL,
+	); err != nil {
+		return nil, sdk.WrapError(err, "cannot get workflow template instances")
+	}
+
+	return wtis, nil
+}
+
 // GetInstancesByWorkflowIDs returns all workflow template instances by workflow ids.
 func GetInstancesByWorkflowIDs(ctx context.Context, db gorp.SqlExecutor, workflowIDs []int64) ([]sdk.WorkflowTemplateInstance, error) {
 	wtis := []sdk.WorkflowTemplateInstance{}
@@ -328,3 +343,46 @@ func GetBulkByIDAndTemplateID(db gorp.SqlExecutor, id, templateID int64) (*sdk.W
 
 	return &b, nil
 }
+
+// InsertVersion for workflow template in database.
+func InsertVersion(db gorp.SqlExecutor, wtv *sdk.WorkflowTemplateVersion) error {
+	return sdk.WrapError(gorpmapping.Insert(db, wtv), "unable to insert version %s for template %d",
+		wtv.Version, wtv.WorkflowTemplateID)
+}
+
+// LoadVersionsByTemplateID returns all published versions of a workflow template.
+func LoadVersionsByTemplateID(ctx context.Context, db gorp.SqlExecutor, templateID int64) ([]sdk.WorkflowTemplateVersion, error) {
+	wtvs := []sdk.WorkflowTemplateVersion{}
+
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_template_version
+    WHERE workflow_template_id = $1
+    ORDER BY created DESC
+  `).Args(templateID)
+	if err := gorpmapping.GetAll(ctx, db, query, &wtvs); err != nil {
+		return nil, sdk.WrapError(err, "cannot get versions of workflow template %d", templateID)
+	}
+
+	return wtvs, nil
+}
+
+// LoadVersionByTemplateIDAndVersion returns a published version of a workflow template.
+func LoadVersionByTemplateIDAndVersion(ctx context.Context, db gorp.SqlExecutor, templateID int64, version string) (*sdk.WorkflowTemplateVersion, error) {
+	var wtv sdk.WorkflowTemplateVersion
+
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_template_version
+    WHERE workflow_template_id = $1 AND version = $2
+  `).Args(templateID, version)
+	found, err := gorpmapping.Get(ctx, db, query, &wtv)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get version %s of workflow template %d", version, templateID)
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrWorkflowTemplateVersionNotFound)
+	}
+
+	return &wtv, nil
+}
@@ -99,3 +99,44 @@ func TestCRUD(t *testing.T) {
 		assert.Nil(t, workflowtemplate.Delete(db, &tmpls[i]), "No err should be returned when removing a template")
 	}
 }
+
+func TestCRUDVersion(t *testing.T) {
+	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	grp := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	defer assets.DeleteTestGroup(t, db, grp)
+
+	tmpl := sdk.WorkflowTemplate{
+		GroupID:    grp.ID,
+		Slug:       "tmpl-1",
+		Name:       "Template 1",
+		Repository: sdk.WorkflowTemplateRepository{ProjectKey: "PROJ", ApplicationName: "app", Path: "template"},
+	}
+	assert.NoError(t, workflowtemplate.Insert(db, &tmpl))
+	defer func() { assert.NoError(t, workflowtemplate.Delete(db, &tmpl)) }()
+
+	for _, v := range []string{"1.0.0", "1.1.0"} {
+		tmpl.SemVer = v
+		assert.NoError(t, workflowtemplate.InsertVersion(db, &sdk.WorkflowTemplateVersion{
+			WorkflowTemplateID: tmpl.ID,
+			Version:            v,
+			Tag:                "v" + v,
+			Template:           tmpl,
+		}))
+	}
+	assert.Error(t, workflowtemplate.InsertVersion(db, &sdk.WorkflowTemplateVersion{WorkflowTemplateID: tmpl.ID, Version: "1.0.0"}))
+
+	versions, err := workflowtemplate.LoadVersionsByTemplateID(context.TODO(), db, tmpl.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(versions))
+
+	version, err := workflowtemplate.LoadVersionByTemplateIDAndVersion(context.TODO(), db, tmpl.ID, "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", version.Tag)
+	assert.Equal(t, "1.0.0", version.Template.SemVer)
+	assert.Equal(t, "app", version.Template.Repository.ApplicationName)
+
+	_, err = workflowtemplate.LoadVersionByTemplateIDAndVersion(context.TODO(), db, tmpl.ID, "2.0.0")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWorkflowTemplateVersionNotFound))
+}
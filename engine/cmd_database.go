@@ -20,6 +20,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/database/dbmigrate"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 )
@@ -28,6 +29,7 @@ func init() {
 	databaseCmd.AddCommand(databaseUpgradeCmd)
 	databaseCmd.AddCommand(databaseDowngradeCmd)
 	databaseCmd.AddCommand(databaseStatusCmd)
+	databaseCmd.AddCommand(databaseRotateSecretsCmd)
 
 	setCommonFlags := func(cmd *cobra.Command) {
 		pflags := cmd.Flags()
@@ -53,6 +55,13 @@ func init() {
 	databaseDowngradeCmd.Flags().IntVarP(&sqlMigrateLimitDown, "limit", "", 1, "Max number of migrations to apply (0 = unlimited)")
 
 	setCommonFlags(databaseStatusCmd)
+
+	databaseRotateSecretsCmd.Flags().StringVar(&flagRotateSecretsConfigFile, "config", "", "API config file")
+	databaseRotateSecretsCmd.Flags().StringVar(&flagRotateSecretsRemoteConfig, "remote-config", "", "(optional) consul configuration store")
+	databaseRotateSecretsCmd.Flags().StringVar(&flagRotateSecretsRemoteConfigKey, "remote-config-key", "cds/config.api.toml", "(optional) consul configuration store key")
+	databaseRotateSecretsCmd.Flags().StringVar(&flagRotateSecretsVaultAddr, "vault-addr", "", "(optional) Vault address to fetch secrets from vault (example: https://vault.mydomain.net:8200)")
+	databaseRotateSecretsCmd.Flags().StringVar(&flagRotateSecretsVaultToken, "vault-token", "", "(optional) Vault token to fetch secrets from vault")
+	databaseRotateSecretsCmd.Flags().IntVar(&flagRotateSecretsBatchSize, "batch-size", 100, "Number of rows re-encrypted in each transaction")
 }
 
 var (
@@ -61,6 +70,13 @@ var (
 	sqlMigrateLimitUp   int
 	sqlMigrateLimitDown int
 	connFactory         = &database.DBConnectionFactory{}
+
+	flagRotateSecretsConfigFile      string
+	flagRotateSecretsRemoteConfig    string
+	flagRotateSecretsRemoteConfigKey string
+	flagRotateSecretsVaultAddr       string
+	flagRotateSecretsVaultToken      string
+	flagRotateSecretsBatchSize       int
 )
 
 var databaseCmd = &cobra.Command{
@@ -94,6 +110,19 @@ var databaseStatusCmd = &cobra.Command{
 	Run:   databaseStatusCmdFunc,
 }
 
+var databaseRotateSecretsCmd = &cobra.Command{
+	Use:   "rotate-secrets",
+	Short: "Re-encrypt all stored secrets with the newest key of the keyring",
+	Long: `Re-encrypts project, application and environment variables and keys with the newest key
+of the API secrets keyring. Values are processed by batch, each batch in its own transaction,
+so the command can safely be stopped and run again.
+
+The API configuration is used to get the database settings and the keyring; all keys used
+by existing values (including the legacy secrets key) must still be configured.`,
+	Example: `engine database rotate-secrets --config config.toml --batch-size 500`,
+	Run:     databaseRotateSecretsCmdFunc,
+}
+
 type statusRow struct {
 	ID        string
 	Migrated  bool
@@ -312,3 +341,35 @@ func printMigration(m *migrate.PlannedMigration, dir migrate.MigrationDirection)
 		panic("Not reached")
 	}
 }
+
+func databaseRotateSecretsCmdFunc(cmd *cobra.Command, args []string) {
+	conf := configImport([]string{"api"}, flagRotateSecretsConfigFile, flagRotateSecretsRemoteConfig, flagRotateSecretsRemoteConfigKey, flagRotateSecretsVaultAddr, flagRotateSecretsVaultToken, false)
+	if conf.API == nil {
+		sdk.Exit("Unable to start: missing api configuration")
+	}
+	if len(conf.API.Secrets.Keyring) == 0 {
+		sdk.Exit("Error: no secrets keyring configured in the api configuration\n")
+	}
+
+	if err := secret.Init(conf.API.Secrets.Key, conf.API.SecretKeyring()...); err != nil {
+		sdk.Exit("Error: %v\n", err)
+	}
+
+	dbConf := conf.API.Database
+	var err error
+	connFactory, err = database.Init(context.TODO(), dbConf.User, dbConf.Role, dbConf.Password, dbConf.Name, dbConf.Host, dbConf.Port, dbConf.SSLMode, dbConf.ConnectTimeout, dbConf.Timeout, dbConf.MaxConn)
+	if err != nil {
+		sdk.Exit("Error: %v\n", err)
+	}
+
+	for _, target := range secret.RotationTargets {
+		fmt.Printf("Rotating %s.%s...\n", target.Table, target.Column)
+		p, err := secret.RotateTarget(context.Background(), connFactory.GetDBMap(), target, flagRotateSecretsBatchSize, func(p secret.RotationProgress) {
+			fmt.Printf("  %s: %d/%d processed, %d re-encrypted\n", p.Target.Table, p.Done, p.Total, p.Rotated)
+		})
+		if err != nil {
+			sdk.Exit("Error on %s: %v\n", target.Table, err)
+		}
+		fmt.Printf("%s.%s done: %d values re-encrypted\n", target.Table, target.Column, p.Rotated)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/elasticsearch"
+	"github.com/ovh/cds/engine/hatchery/docker"
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
@@ -65,6 +66,7 @@ This is the core component of CDS.
 They are the components responsible for spawning workers. Supported integrations/orchestrators are:
 
 * Local machine
+* Docker or Podman on a single host
 * Openstack
 * Docker Swarm
 * Openstack
@@ -81,7 +83,7 @@ This component operates CDS VCS connectivity
 
 Start all of this with a single command:
 
-	$ engine start [api] [hatchery:local] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [migrate] [ui]
+	$ engine start [api] [hatchery:local] [hatchery:docker] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [migrate] [ui]
 
 All the services are using the same configuration file format.
 
@@ -165,6 +167,14 @@ See $ engine config command for more details.
 				names = append(names, conf.Hatchery.Local.Name)
 				types = append(types, services.TypeHatchery)
 
+			case "hatchery:docker":
+				if conf.Hatchery.Docker == nil {
+					sdk.Exit("Unable to start: missing service %s configuration", a)
+				}
+				serviceConfs = append(serviceConfs, serviceConf{arg: a, service: docker.New(), cfg: *conf.Hatchery.Docker})
+				names = append(names, conf.Hatchery.Docker.Name)
+				types = append(types, services.TypeHatchery)
+
 			case "hatchery:kubernetes":
 				if conf.Hatchery.Kubernetes == nil {
 					sdk.Exit("Unable to start: missing service %s configuration", a)
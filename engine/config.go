@@ -22,6 +22,7 @@ import (
 	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/elasticsearch"
+	"github.com/ovh/cds/engine/hatchery/docker"
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
@@ -50,7 +51,7 @@ func configBootstrap(args []string) Configuration {
 	if len(args) == 0 {
 		args = []string{
 			"api", "ui", "migrate", "hooks", "vcs", "repositories", "elasticsearch",
-			"hatchery:local", "hatchery:kubernetes", "hatchery:marathon", "hatchery:openstack", "hatchery:swarm", "hatchery:vsphere",
+			"hatchery:local", "hatchery:docker", "hatchery:kubernetes", "hatchery:marathon", "hatchery:openstack", "hatchery:swarm", "hatchery:vsphere",
 		}
 	}
 
@@ -90,6 +91,10 @@ func configBootstrap(args []string) Configuration {
 			conf.Hatchery.Local = &local.HatcheryConfiguration{}
 			defaults.SetDefaults(conf.Hatchery.Local)
 			conf.Hatchery.Local.Name = "cds-hatchery-local-" + namesgenerator.GetRandomNameCDS(0)
+		case "hatchery:docker":
+			conf.Hatchery.Docker = &docker.HatcheryConfiguration{}
+			defaults.SetDefaults(conf.Hatchery.Docker)
+			conf.Hatchery.Docker.Name = "cds-hatchery-docker-" + namesgenerator.GetRandomNameCDS(0)
 		case "hatchery:kubernetes":
 			conf.Hatchery.Kubernetes = &kubernetes.HatcheryConfiguration{}
 			defaults.SetDefaults(conf.Hatchery.Kubernetes)
@@ -132,12 +137,15 @@ func configBootstrap(args []string) Configuration {
 			defaults.SetDefaults(&gitlab)
 			var gerrit vcs.GerritServerConfiguration
 			defaults.SetDefaults(&gerrit)
+			var gitea vcs.GiteaServerConfiguration
+			defaults.SetDefaults(&gitea)
 			conf.VCS.Servers = map[string]vcs.ServerConfiguration{
 				"github":         vcs.ServerConfiguration{URL: "https://github.com", Github: &github},
 				"bitbucket":      vcs.ServerConfiguration{URL: "https://mybitbucket.com", Bitbucket: &bitbucket},
 				"bitbucketcloud": vcs.ServerConfiguration{BitbucketCloud: &bitbucketcloud},
 				"gitlab":         vcs.ServerConfiguration{URL: "https://gitlab.com", Gitlab: &gitlab},
 				"gerrit":         vcs.ServerConfiguration{URL: "http://localhost:8080", Gerrit: &gerrit},
+				"gitea":          vcs.ServerConfiguration{URL: "https://gitea.com", Gitea: &gitea},
 			}
 			conf.VCS.Name = "cds-vcs-" + namesgenerator.GetRandomNameCDS(0)
 		case "repositories":
@@ -417,6 +425,33 @@ func configSetStartupData(conf *Configuration) (string, error) {
 			privateKeyPEM, _ := jws.ExportPrivateKey(privateKey)
 			h.Swarm.RSAPrivateKey = string(privateKeyPEM)
 		}
+		if h.Docker != nil {
+			var cfg = api.StartupConfigService{
+				ID:          sdk.UUID(),
+				Name:        "hatchery:docker",
+				Description: "Autogenerated configuration for docker hatchery",
+				ServiceType: services.TypeHatchery,
+			}
+
+			var c = sdk.AuthConsumer{
+				ID:          cfg.ID,
+				Name:        cfg.Name,
+				Description: cfg.Description,
+				Type:        sdk.ConsumerBuiltin,
+				Data:        map[string]string{},
+				IssuedAt:    iat,
+			}
+
+			h.Docker.API.Token, err = builtin.NewSigninConsumerToken(&c)
+			if err != nil {
+				return "", err
+			}
+
+			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
+			privateKey, _ := jws.NewRandomRSAKey()
+			privateKeyPEM, _ := jws.ExportPrivateKey(privateKey)
+			h.Docker.RSAPrivateKey = string(privateKeyPEM)
+		}
 		if h.Marathon != nil {
 			var cfg = api.StartupConfigService{
 				ID:          sdk.UUID(),
@@ -704,6 +739,24 @@ func getInitTokenFromExistingConfiguration(conf Configuration) (string, error) {
 
 			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
 		}
+		if h.Docker != nil {
+			consumerID, iat, err := builtin.CheckSigninConsumerToken(h.Docker.API.Token)
+			if err != nil {
+				return "", fmt.Errorf("cannot parse hatchery:docker signin token: %v", err)
+			}
+			if iat < globalIAT {
+				globalIAT = iat
+			}
+
+			var cfg = api.StartupConfigService{
+				ID:          consumerID,
+				Name:        "hatchery:docker",
+				Description: "Autogenerated configuration for docker hatchery",
+				ServiceType: services.TypeHatchery,
+			}
+
+			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
+		}
 		if h.Marathon != nil {
 			consumerID, iat, err := builtin.CheckSigninConsumerToken(h.Marathon.API.Token)
 			if err != nil {
@@ -45,6 +45,49 @@ func (s *Service) getEventsHandler() service.Handler {
 	}
 }
 
+func (s *Service) getEventsHistoryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if s.Cfg.ElasticSearch.IndexEvents == "" {
+			return sdk.WrapError(sdk.ErrNotFound, "No events index found")
+		}
+
+		var filter sdk.EventHistoryFilter
+		if err := service.UnmarshalBody(r, &filter); err != nil {
+			return sdk.WrapError(err, "Unable to read body")
+		}
+		if filter.ProjectKey == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing project key")
+		}
+		if filter.Size <= 0 || filter.Size > 100 {
+			filter.Size = 100
+		}
+
+		timeRange := elastic.NewRangeQuery("timestamp").Gte(filter.From)
+		if !filter.To.IsZero() {
+			timeRange.Lte(filter.To)
+		}
+		boolQuery := elastic.NewBoolQuery().
+			Must(elastic.NewQueryStringQuery(fmt.Sprintf("project_key:%s", filter.ProjectKey))).
+			Filter(timeRange)
+
+		// Pages are fetched after the last event of the previous page, unlike from/size paging it is not limited to
+		// the max result window of the index. The id breaks ties between events of the same date.
+		search := esClient.Search().Index(s.Cfg.ElasticSearch.IndexEvents).Query(boolQuery).Sort("timestamp", true).Sort("_id", true).Size(filter.Size)
+		if len(filter.SearchAfter) > 0 {
+			search = search.SearchAfter(filter.SearchAfter...)
+		}
+		result, errR := search.Do(context.Background())
+		if errR != nil {
+			if strings.Contains(errR.Error(), indexNotFoundException) {
+				log.Warning(ctx, "elasticsearch> getEventsHistoryHandler> %v", errR.Error())
+				return service.WriteJSON(w, nil, http.StatusOK)
+			}
+			return sdk.WrapError(errR, "Cannot get result on index: %s", s.Cfg.ElasticSearch.IndexEvents)
+		}
+		return service.WriteJSON(w, result.Hits.Hits, http.StatusOK)
+	}
+}
+
 func (s *Service) postEventHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if s.Cfg.ElasticSearch.IndexEvents == "" {
@@ -0,0 +1,424 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const timeoutPullImage = 10 * time.Minute
+
+// New instanciates a new Hatchery Docker
+func New() *HatcheryDocker {
+	s := new(HatcheryDocker)
+	s.Router = &api.Router{
+		Mux: mux.NewRouter(),
+	}
+	return s
+}
+
+// InitHatchery connect the hatchery to the local container engine
+func (h *HatcheryDocker) InitHatchery(ctx context.Context) error {
+	httpClient := new(http.Client)
+	// max time for a pull, other requests use a lower timeout with context.WithTimeout
+	httpClient.Timeout = timeoutPullImage
+
+	opts := []func(*dockerclient.Client) error{dockerclient.WithHost(h.Config.Host), dockerclient.WithHTTPClient(httpClient)}
+	if h.Config.APIVersion != "" {
+		opts = append(opts, dockerclient.WithVersion(h.Config.APIVersion))
+	}
+	c, err := dockerclient.NewClientWithOpts(opts...)
+	if err != nil {
+		log.Error(ctx, "hatchery> docker> unable to create %s client for %s: %v", h.Config.Runtime, h.Config.Host, err)
+		return sdk.WithStack(err)
+	}
+
+	ctxPing, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if _, err := c.Ping(ctxPing); err != nil {
+		log.Error(ctx, "hatchery> docker> unable to ping %s on %s: %v", h.Config.Runtime, h.Config.Host, err)
+		return sdk.WithStack(err)
+	}
+	if h.Config.APIVersion == "" {
+		c.NegotiateAPIVersion(ctxPing)
+	}
+	h.client = c
+	log.Info(ctx, "hatchery> docker> connected to %s on %s (API version %s)", h.Config.Runtime, h.Config.Host, c.ClientVersion())
+
+	sdk.GoRoutine(context.Background(), "docker", func(ctx context.Context) { h.routines(ctx) })
+
+	return nil
+}
+
+// SpawnWorker starts a new worker container and the containers for its services
+// in a network dedicated to the job.
+func (h *HatcheryDocker) SpawnWorker(ctx context.Context, spawnArgs hatchery.SpawnArguments) error {
+	ctx, end := observability.Span(ctx, "docker.SpawnWorker")
+	defer end()
+
+	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly && !spawnArgs.Warm {
+		return sdk.WithStack(fmt.Errorf("unable to spawn worker, no Job ID and no Register"))
+	}
+	if spawnArgs.Model == nil {
+		return sdk.WithStack(sdk.ErrNoWorkerModel)
+	}
+
+	observability.Current(ctx, observability.Tag(observability.TagWorker, spawnArgs.WorkerName))
+	log.Debug("hatchery> docker> SpawnWorker> Spawning worker %s", spawnArgs.WorkerName)
+
+	//Memory for the worker
+	memory := int64(h.Config.DefaultMemory)
+	if spawnArgs.Model.ModelDocker.Memory != 0 {
+		memory = spawnArgs.Model.ModelDocker.Memory
+	}
+
+	// each job has its own network, shared only with its services
+	var network string
+	if spawnArgs.JobID > 0 {
+		network = spawnArgs.WorkerName + "-net"
+		if err := h.createNetwork(ctx, network, spawnArgs.WorkerName); err != nil {
+			return sdk.WrapError(err, "unable to create network %s for job %d", network, spawnArgs.JobID)
+		}
+	}
+
+	services := []string{}
+	if spawnArgs.JobID > 0 {
+		for _, r := range spawnArgs.Requirements {
+			switch r.Type {
+			case sdk.MemoryRequirement:
+				var err error
+				memory, err = strconv.ParseInt(r.Value, 10, 64)
+				if err != nil {
+					log.Warning(ctx, "hatchery> docker> SpawnWorker> Unable to parse memory requirement %s: %v", r.Value, err)
+					return sdk.WithStack(err)
+				}
+			case sdk.ServiceRequirement:
+				//name= <alias> => the name of the host put in /etc/hosts of the worker
+				//value= "postgres:latest env_1=blabla env_2=blabla" => we can add env variables in requirement name
+				img, envm := hatchery.ParseRequirementModel(r.Value)
+
+				serviceMemory := int64(1024)
+				if sm, ok := envm["CDS_SERVICE_MEMORY"]; ok {
+					i, err := strconv.ParseUint(sm, 10, 32)
+					if err != nil {
+						log.Warning(ctx, "hatchery> docker> SpawnWorker> Unable to parse service option CDS_SERVICE_MEMORY=%s : %s", sm, err)
+					} else {
+						serviceMemory = int64(i)
+					}
+				}
+
+				var cmdArgs []string
+				if sa, ok := envm["CDS_SERVICE_ARGS"]; ok {
+					cmdArgs = hatchery.ParseArgs(sa)
+				}
+
+				env := make([]string, 0, len(envm))
+				for key, val := range envm {
+					env = append(env, key+"="+val)
+				}
+
+				serviceName := r.Name + "-" + spawnArgs.WorkerName
+
+				//labels are used to make container cleanup easier. We "link" the service to its worker this way.
+				labels := map[string]string{
+					labelHatchery:       h.Config.Name,
+					labelServiceWorker:  spawnArgs.WorkerName,
+					labelServiceName:    serviceName,
+					labelServiceJobID:   strconv.FormatInt(spawnArgs.JobID, 10),
+					labelServiceID:      strconv.FormatInt(r.ID, 10),
+					labelServiceReqName: r.Name,
+					labelWorkerNetwork:  network,
+				}
+
+				args := containerArgs{
+					name:         serviceName,
+					image:        img,
+					network:      network,
+					networkAlias: r.Name,
+					cmd:          cmdArgs,
+					env:          env,
+					labels:       labels,
+					memory:       serviceMemory,
+				}
+				if err := h.createAndStartContainer(ctx, args, spawnArgs); err != nil {
+					log.Warning(ctx, "hatchery> docker> SpawnWorker> Unable to start service %s: %v", serviceName, err)
+					h.killAndRemoveWorker(ctx, spawnArgs.WorkerName)
+					return err
+				}
+				services = append(services, serviceName)
+			}
+		}
+	}
+
+	if spawnArgs.RegisterOnly {
+		spawnArgs.Model.ModelDocker.Cmd += " register"
+		memory = hatchery.MemoryRegisterContainer
+	}
+
+	//labels are used to make container cleanup easier
+	labels := map[string]string{
+		labelHatchery:           h.Config.Name,
+		labelWorkerModelPath:    spawnArgs.Model.Group.Name + "/" + spawnArgs.Model.Name,
+		labelWorkerName:         spawnArgs.WorkerName,
+		labelWorkerRequirements: strings.Join(services, ","),
+		labelWorkerNetwork:      network,
+	}
+
+	udataParam := sdk.WorkerArgs{
+		API:               h.Config.API.HTTP.URL,
+		Token:             spawnArgs.WorkerToken,
+		HTTPInsecure:      h.Config.API.HTTP.Insecure,
+		Name:              spawnArgs.WorkerName,
+		Model:             spawnArgs.Model.Group.Name + "/" + spawnArgs.Model.Name,
+		TTL:               h.Config.WorkerTTL,
+		HatcheryName:      h.Name(),
+		GraylogHost:       h.Config.Provision.WorkerLogsOptions.Graylog.Host,
+		GraylogPort:       h.Config.Provision.WorkerLogsOptions.Graylog.Port,
+		GraylogExtraKey:   h.Config.Provision.WorkerLogsOptions.Graylog.ExtraKey,
+		GraylogExtraValue: h.Config.Provision.WorkerLogsOptions.Graylog.ExtraValue,
+		WorkflowJobID:     spawnArgs.JobID,
+	}
+
+	tmpl, err := template.New("cmd").Parse(spawnArgs.Model.ModelDocker.Cmd)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, udataParam); err != nil {
+		return sdk.WithStack(err)
+	}
+	cmds := strings.Fields(spawnArgs.Model.ModelDocker.Shell)
+	cmds = append(cmds, buffer.String())
+
+	// copy envs to avoid data race
+	modelEnvs := make(map[string]string, len(spawnArgs.Model.ModelDocker.Envs))
+	for k, v := range spawnArgs.Model.ModelDocker.Envs {
+		modelEnvs[k] = v
+	}
+
+	envsWm := map[string]string{}
+	envsWm["CDS_FORCE_EXIT"] = "1"
+	envsWm["CDS_MODEL_MEMORY"] = fmt.Sprintf("%d", memory)
+	envsWm["CDS_API"] = udataParam.API
+	envsWm["CDS_TOKEN"] = udataParam.Token
+	envsWm["CDS_NAME"] = udataParam.Name
+	envsWm["CDS_MODEL_PATH"] = udataParam.Model
+	envsWm["CDS_HATCHERY_NAME"] = udataParam.HatcheryName
+	envsWm["CDS_FROM_WORKER_IMAGE"] = fmt.Sprintf("%v", udataParam.FromWorkerImage)
+	envsWm["CDS_INSECURE"] = fmt.Sprintf("%v", udataParam.HTTPInsecure)
+	if spawnArgs.JobID > 0 {
+		envsWm["CDS_BOOKED_WORKFLOW_JOB_ID"] = fmt.Sprintf("%d", spawnArgs.JobID)
+	}
+
+	envTemplated, err := sdk.TemplateEnvs(udataParam, modelEnvs)
+	if err != nil {
+		return err
+	}
+	for envName, envValue := range envTemplated {
+		envsWm[envName] = envValue
+	}
+
+	envs := make([]string, 0, len(envsWm))
+	for envName, envValue := range envsWm {
+		envs = append(envs, envName+"="+envValue)
+	}
+
+	args := containerArgs{
+		name:         spawnArgs.WorkerName,
+		image:        spawnArgs.Model.ModelDocker.Image,
+		network:      network,
+		networkAlias: "worker",
+		cmd:          cmds,
+		labels:       labels,
+		memory:       memory,
+		entryPoint:   []string{},
+		env:          envs,
+	}
+
+	//start the worker
+	if err := h.createAndStartContainer(ctx, args, spawnArgs); err != nil {
+		log.Warning(ctx, "hatchery> docker> SpawnWorker> Unable to start container %s with image %s: %v", args.name, args.image, err)
+		h.killAndRemoveWorker(ctx, spawnArgs.WorkerName)
+		return err
+	}
+
+	return nil
+}
+
+// ModelType returns type of hatchery
+func (*HatcheryDocker) ModelType() string {
+	return sdk.Docker
+}
+
+// CanSpawn checks if the model can be spawned by this hatchery. Docker options on model requirement
+// and volume requirements are not supported.
+func (h *HatcheryDocker) CanSpawn(ctx context.Context, model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	nbServices := 0
+	for _, r := range requirements {
+		switch r.Type {
+		case sdk.ServiceRequirement:
+			nbServices++
+		case sdk.VolumeRequirement:
+			log.Debug("hatchery> docker> CanSpawn> volume requirement is not supported")
+			return false
+		case sdk.ModelRequirement:
+			if len(strings.Fields(r.Value)) > 1 {
+				log.Debug("hatchery> docker> CanSpawn> docker options on model requirement are not supported")
+				return false
+			}
+		}
+	}
+
+	cs, err := h.getContainers(ctx)
+	if err != nil {
+		log.Error(ctx, "hatchery> docker> CanSpawn> Unable to list containers: %v", err)
+		return false
+	}
+
+	// the worker and its services should fit in the available containers
+	if len(cs)+1+nbServices > h.Config.MaxContainers {
+		log.Debug("hatchery> docker> CanSpawn> max containers reached. current:%d max:%d", len(cs), h.Config.MaxContainers)
+		return false
+	}
+
+	// ratioService: percent reserved for spawning worker with service requirement
+	if nbServices == 0 {
+		ratioService := h.Config.Provision.RatioService
+		if ratioService != nil && *ratioService >= 100 {
+			log.Debug("hatchery> docker> CanSpawn> ratioService 100 by conf - no spawn worker without CDS Service")
+			return false
+		}
+		if len(cs) > 0 {
+			percentFree := 100 - (100 * len(getWorkerContainers(cs)) / h.Config.MaxContainers)
+			if ratioService != nil && percentFree <= *ratioService {
+				log.Debug("hatchery> docker> CanSpawn> ratio reached. percentFree:%d ratioService:%d", percentFree, *ratioService)
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// getContainers returns all the containers started by the hatchery
+func (h *HatcheryDocker) getContainers(ctx context.Context) ([]types.Container, error) {
+	ctxList, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	cs, err := h.client.ContainerList(ctxList, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", labelHatchery+"="+h.Config.Name)),
+	})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to list containers on %s", h.Config.Host)
+	}
+	return cs, nil
+}
+
+// getWorkerContainers returns only the worker containers, without the services
+func getWorkerContainers(containers []types.Container) []types.Container {
+	res := []types.Container{}
+	for _, c := range containers {
+		if _, ok := c.Labels[labelWorkerName]; ok {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// WorkersStarted returns the number of instances started but
+// not necessarily register on CDS yet
+func (h *HatcheryDocker) WorkersStarted(ctx context.Context) []string {
+	cs, err := h.getContainers(ctx)
+	if err != nil {
+		log.Error(ctx, "hatchery> docker> WorkersStarted> Unable to list containers: %v", err)
+		return nil
+	}
+	res := make([]string, 0, len(cs))
+	for _, c := range getWorkerContainers(cs) {
+		res = append(res, c.Labels[labelWorkerName])
+	}
+	return res
+}
+
+// WorkersStartedByModel returns the number of started workers
+func (h *HatcheryDocker) WorkersStartedByModel(ctx context.Context, model *sdk.Model) int {
+	cs, err := h.getContainers(ctx)
+	if err != nil {
+		log.Error(ctx, "hatchery> docker> WorkersStartedByModel> Unable to list containers: %v", err)
+		return 0
+	}
+	// podman returns fully qualified image names, so the model is checked with its path
+	modelPath := model.Group.Name + "/" + model.Name
+	var count int
+	for _, c := range getWorkerContainers(cs) {
+		if c.Labels[labelWorkerModelPath] == modelPath {
+			count++
+		}
+	}
+	log.Debug("hatchery> docker> WorkersStartedByModel> %s \t %d", model.Name, count)
+	return count
+}
+
+// Serve start the hatchery server
+func (h *HatcheryDocker) Serve(ctx context.Context) error {
+	return h.CommonServe(ctx, h)
+}
+
+// Configuration returns Hatchery CommonConfiguration
+func (h *HatcheryDocker) Configuration() service.HatcheryCommonConfiguration {
+	return h.Config.HatcheryCommonConfiguration
+}
+
+// WorkerModelsEnabled returns Worker model enabled
+func (h *HatcheryDocker) WorkerModelsEnabled() ([]sdk.Model, error) {
+	return h.CDSClient().WorkerModelsEnabled()
+}
+
+// NeedRegistration return true if worker model need regsitration
+func (h *HatcheryDocker) NeedRegistration(ctx context.Context, m *sdk.Model) bool {
+	return m.NeedRegistration || m.LastRegistration.Unix() < m.UserLastModified.Unix()
+}
+
+func (h *HatcheryDocker) routines(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sdk.GoRoutine(ctx, "getServicesLogs", func(ctx context.Context) {
+				if err := h.getServicesLogs(ctx); err != nil {
+					log.Error(ctx, "hatchery> docker> Cannot get service logs: %v", err)
+				}
+			})
+
+			sdk.GoRoutine(ctx, "killAwolContainers", func(ctx context.Context) {
+				if err := h.killAwolContainers(ctx); err != nil {
+					log.Error(ctx, "hatchery> docker> Cannot kill awol containers: %v", err)
+				}
+			})
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "hatchery> docker> Exiting routines")
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Init returns the cdsclient config from the hatchery configuration
+func (h *HatcheryDocker) Init(config interface{}) (cdsclient.ServiceConfig, error) {
+	var cfg cdsclient.ServiceConfig
+	sConfig, ok := config.(HatcheryConfiguration)
+	if !ok {
+		return cfg, sdk.WithStack(fmt.Errorf("invalid docker hatchery configuration"))
+	}
+
+	cfg.Host = sConfig.API.HTTP.URL
+	cfg.Token = sConfig.API.Token
+	cfg.InsecureSkipVerifyTLS = sConfig.API.HTTP.Insecure
+	cfg.RequestSecondsTimeout = sConfig.API.RequestTimeout
+	return cfg, nil
+}
+
+// ApplyConfiguration apply an object of type HatcheryConfiguration after checking it
+func (h *HatcheryDocker) ApplyConfiguration(cfg interface{}) error {
+	if err := h.CheckConfiguration(cfg); err != nil {
+		return err
+	}
+
+	var ok bool
+	h.Config, ok = cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	if h.Config.Host == "" {
+		h.Config.Host = defaultHost(h.Config.Runtime)
+	}
+
+	h.HTTPURL = h.Config.URL
+	h.MaxHeartbeatFailures = h.Config.API.MaxHeartbeatFailures
+	h.Common.Common.ServiceName = h.Config.Name
+	h.Common.Common.ServiceType = services.TypeHatchery
+	var err error
+	h.Common.Common.PrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(h.Config.RSAPrivateKey))
+	if err != nil {
+		return fmt.Errorf("unable to parse RSA private Key: %v", err)
+	}
+
+	return nil
+}
+
+// defaultHost returns the default socket of given runtime, podman is expected to run rootless.
+func defaultHost(runtime string) string {
+	if runtime == RuntimePodman {
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtimeDir == "" {
+			runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
+		}
+		return "unix://" + runtimeDir + "/podman/podman.sock"
+	}
+	return "unix:///var/run/docker.sock"
+}
+
+// Status returns sdk.MonitoringStatus, implements interface service.Service
+func (h *HatcheryDocker) Status(ctx context.Context) sdk.MonitoringStatus {
+	m := h.CommonMonitoring()
+	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Workers", Value: fmt.Sprintf("%d/%d", len(h.WorkersStarted(ctx)), h.Config.Provision.MaxWorker), Status: sdk.MonitoringStatusOK})
+
+	status := sdk.MonitoringStatusOK
+	cs, err := h.getContainers(ctx)
+	if err != nil {
+		log.Warning(ctx, "hatchery> docker> %s> Status> Unable to list containers on %s: %s", h.Name(), h.Config.Host, err)
+		status = sdk.MonitoringStatusAlert
+	}
+	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Containers", Value: fmt.Sprintf("%d/%d", len(cs), h.Config.MaxContainers), Status: status})
+	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Runtime", Value: h.Config.Runtime + " " + h.Config.Host, Status: status})
+
+	return m
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (h *HatcheryDocker) CheckConfiguration(cfg interface{}) error {
+	hconfig, ok := cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	if hconfig.API.HTTP.URL == "" {
+		return fmt.Errorf("API HTTP(s) URL is mandatory")
+	}
+
+	if hconfig.API.Token == "" {
+		return fmt.Errorf("API Token URL is mandatory")
+	}
+
+	if hconfig.Runtime != RuntimeDocker && hconfig.Runtime != RuntimePodman {
+		return fmt.Errorf("runtime must be %s or %s", RuntimeDocker, RuntimePodman)
+	}
+
+	if hconfig.MaxContainers <= 0 {
+		return fmt.Errorf("max-containers must be > 0")
+	}
+	if hconfig.WorkerTTL <= 0 {
+		return fmt.Errorf("worker-ttl must be > 0")
+	}
+	if hconfig.DefaultMemory <= 1 {
+		return fmt.Errorf("worker-memory must be > 1")
+	}
+	if hconfig.CPUs < 0 || hconfig.PidsLimit < 0 {
+		return fmt.Errorf("cpus and pids-limit must be >= 0")
+	}
+
+	if hconfig.Name == "" {
+		return fmt.Errorf("please enter a name in your docker hatchery configuration")
+	}
+
+	return nil
+}
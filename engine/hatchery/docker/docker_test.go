@@ -0,0 +1,130 @@
+package docker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestListAwolWorkers(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Minute).Unix()
+	cs := []types.Container{
+		{ID: "1", State: "running", Created: old, Labels: map[string]string{labelWorkerName: "w-registered"}},
+		{ID: "2", State: "running", Created: old, Labels: map[string]string{labelWorkerName: "w-unknown"}},
+		{ID: "3", State: "running", Created: now.Unix(), Labels: map[string]string{labelWorkerName: "w-young"}},
+		{ID: "4", State: "exited", Created: now.Unix(), Labels: map[string]string{labelWorkerName: "w-exited"}},
+		{ID: "5", State: "running", Created: old, Labels: map[string]string{labelWorkerName: "w-disabled"}},
+		{ID: "6", State: "running", Created: old, Labels: map[string]string{labelServiceWorker: "w-registered"}},
+		{ID: "7", State: "running", Created: old, Labels: map[string]string{labelServiceWorker: "w-gone"}},
+		{ID: "8", State: "running", Created: now.Unix(), Labels: map[string]string{labelServiceWorker: "w-starting"}},
+		{ID: "9", State: "running", Created: old, Labels: map[string]string{labelServiceWorker: "w-unknown"}},
+	}
+	apiWorkers := []sdk.Worker{
+		{Name: "w-registered", Status: sdk.StatusBuilding},
+		{Name: "w-exited", Status: sdk.StatusBuilding},
+		{Name: "w-disabled", Status: sdk.StatusDisabled},
+	}
+
+	res := listAwolWorkers(cs, apiWorkers, now)
+	assert.Equal(t, []string{"w-unknown", "w-exited", "w-disabled", "w-gone"}, res)
+}
+
+func TestHatcheryDocker_killAwolContainers(t *testing.T) {
+	defer gock.Off()
+	h := initTestHatcheryDocker(t)
+
+	old := time.Now().Add(-2 * time.Minute).Unix()
+	containers := []types.Container{
+		{ID: "worker-1", State: "exited", Created: old, Labels: map[string]string{labelHatchery: "dockery", labelWorkerName: "w1", labelWorkerNetwork: "w1-net"}},
+		{ID: "service-1", State: "running", Created: old, Labels: map[string]string{labelHatchery: "dockery", labelServiceWorker: "w1", labelWorkerNetwork: "w1-net"}},
+		{ID: "worker-2", State: "running", Created: old, Labels: map[string]string{labelHatchery: "dockery", labelWorkerName: "w2", labelWorkerNetwork: "w2-net"}},
+	}
+	gock.New("https://lolcat.host").Get("/v6.66/containers/json").Times(2).Reply(http.StatusOK).JSON(containers)
+	gock.New("https://lolcat.api").Get("/worker").Reply(http.StatusOK).JSON([]sdk.Worker{{Name: "w2", Status: sdk.StatusBuilding}})
+
+	// w1 and its service are removed with their network
+	gock.New("https://lolcat.host").Delete("/v6.66/containers/worker-1").Reply(http.StatusNoContent)
+	gock.New("https://lolcat.host").Delete("/v6.66/containers/service-1").Reply(http.StatusNoContent)
+	gock.New("https://lolcat.host").Delete("/v6.66/networks/w1-net").Reply(http.StatusNoContent)
+
+	nets := []types.NetworkResource{
+		{ID: "net-w2", Name: "w2-net", Labels: map[string]string{labelHatchery: "dockery", labelWorkerNetwork: "w2-net"}, Created: time.Now().Add(-time.Hour)},
+		{ID: "net-w3", Name: "w3-net", Labels: map[string]string{labelHatchery: "dockery", labelWorkerNetwork: "w3-net"}, Created: time.Now()},
+		{ID: "net-w4", Name: "w4-net", Labels: map[string]string{labelHatchery: "dockery", labelWorkerNetwork: "w4-net"}, Created: time.Now().Add(-time.Hour)},
+	}
+	gock.New("https://lolcat.host").Get("/v6.66/networks").Reply(http.StatusOK).JSON(nets)
+	// only the old unused network is removed
+	gock.New("https://lolcat.host").Delete("/v6.66/networks/net-w4").Reply(http.StatusNoContent)
+
+	require.NoError(t, h.killAwolContainers(context.TODO()))
+	require.True(t, gock.IsDone())
+}
+
+func TestHatcheryDocker_CanSpawn(t *testing.T) {
+	defer gock.Off()
+	h := initTestHatcheryDocker(t)
+
+	containers := []types.Container{
+		{ID: "worker-1", Labels: map[string]string{labelHatchery: "dockery", labelWorkerName: "w1"}},
+		{ID: "service-1", Labels: map[string]string{labelHatchery: "dockery", labelServiceWorker: "w1"}},
+	}
+	gock.New("https://lolcat.host").Get("/v6.66/containers/json").Times(2).Reply(http.StatusOK).JSON(containers)
+
+	m := &sdk.Model{Name: "model"}
+	assert.True(t, h.CanSpawn(context.TODO(), m, 1, []sdk.Requirement{{Type: sdk.ServiceRequirement, Name: "pg", Value: "postgres:9.6"}}))
+	assert.False(t, h.CanSpawn(context.TODO(), m, 1, []sdk.Requirement{
+		{Type: sdk.ServiceRequirement, Name: "pg", Value: "postgres:9.6"},
+		{Type: sdk.ServiceRequirement, Name: "redis", Value: "redis"},
+	}), "max containers should be reached")
+	assert.False(t, h.CanSpawn(context.TODO(), m, 1, []sdk.Requirement{{Type: sdk.ModelRequirement, Value: "golang:1.13 --privileged"}}))
+	assert.False(t, h.CanSpawn(context.TODO(), m, 1, []sdk.Requirement{{Type: sdk.VolumeRequirement, Value: "type=bind,source=/tmp,destination=/tmp"}}))
+}
+
+func TestHatcheryDocker_resources(t *testing.T) {
+	h := New()
+	h.Config.CPUs = 1.5
+	h.Config.PidsLimit = 512
+
+	r := h.resources(2048)
+	assert.Equal(t, int64(2048*1024*1024), r.Memory)
+	assert.Equal(t, r.Memory, r.MemorySwap)
+	assert.Equal(t, int64(1500000000), r.NanoCPUs)
+	assert.Equal(t, int64(512), r.PidsLimit)
+
+	h.Config.CPUs = 0
+	h.Config.PidsLimit = 0
+	r = h.resources(0)
+	assert.Equal(t, int64(1024*1024*1024), r.Memory)
+	assert.Equal(t, int64(0), r.NanoCPUs)
+	assert.Equal(t, int64(0), r.PidsLimit)
+}
+
+func TestServiceLogFromLabels(t *testing.T) {
+	l, err := serviceLogFromLabels(map[string]string{labelWorkerName: "w1"})
+	require.NoError(t, err)
+	assert.Nil(t, l)
+
+	l, err = serviceLogFromLabels(map[string]string{labelServiceJobID: "12", labelServiceID: "3", labelServiceReqName: "pg"})
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	assert.Equal(t, int64(12), l.WorkflowNodeJobRunID)
+	assert.Equal(t, int64(3), l.ServiceRequirementID)
+	assert.Equal(t, "pg", l.ServiceRequirementName)
+
+	_, err = serviceLogFromLabels(map[string]string{labelServiceJobID: "abc"})
+	assert.Error(t, err)
+}
+
+func TestDefaultHost(t *testing.T) {
+	assert.Equal(t, "unix:///var/run/docker.sock", defaultHost(RuntimeDocker))
+	assert.Contains(t, defaultHost(RuntimePodman), "/podman/podman.sock")
+}
@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	dockerclient "github.com/docker/docker/client"
+
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// createNetwork creates the bridge network of a job
+func (h *HatcheryDocker) createNetwork(ctx context.Context, name, workerName string) error {
+	ctx, end := observability.Span(ctx, "docker.createNetwork", observability.Tag("network", name))
+	defer end()
+	log.Debug("hatchery> docker> createNetwork> Create network %s", name)
+
+	ctxCreate, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, err := h.client.NetworkCreate(ctxCreate, name, types.NetworkCreate{
+		Driver:         "bridge",
+		CheckDuplicate: true,
+		EnableIPv6:     h.Config.NetworkEnableIPv6,
+		Labels: map[string]string{
+			labelHatchery:      h.Config.Name,
+			labelWorkerNetwork: name,
+			labelWorkerName:    workerName,
+		},
+	})
+	return sdk.WithStack(err)
+}
+
+type containerArgs struct {
+	name, image, network, networkAlias string
+	cmd, env                           []string
+	labels                             map[string]string
+	memory                             int64
+	entryPoint                         strslice.StrSlice
+}
+
+// resources returns the resource limits of a container, memory is given in MB.
+func (h *HatcheryDocker) resources(memory int64) container.Resources {
+	//Memory is set to 1GB by default
+	if memory <= 4 {
+		memory = 1024
+	}
+	r := container.Resources{
+		Memory:     memory * 1024 * 1024, //from MB to B
+		MemorySwap: memory * 1024 * 1024, // no swap
+		NanoCPUs:   int64(h.Config.CPUs * 1e9),
+	}
+	if h.Config.PidsLimit > 0 {
+		r.PidsLimit = h.Config.PidsLimit
+	}
+	return r
+}
+
+// shortcut to create+start(=run) a container
+func (h *HatcheryDocker) createAndStartContainer(ctx context.Context, cArgs containerArgs, spawnArgs hatchery.SpawnArguments) error {
+	ctx, end := observability.Span(ctx, "docker.createAndStartContainer", observability.Tag(observability.TagWorker, cArgs.name))
+	defer end()
+
+	resources := h.resources(cArgs.memory)
+	log.Info(ctx, "hatchery> docker> createAndStartContainer> Create container %s from %s (memory=%dMB)", cArgs.name, cArgs.image, resources.Memory/1024/1024)
+
+	config := &container.Config{
+		Image:  cArgs.image,
+		Env:    cArgs.env,
+		Cmd:    cArgs.cmd,
+		Labels: cArgs.labels,
+	}
+	if cArgs.entryPoint != nil {
+		config.Entrypoint = cArgs.entryPoint
+	}
+
+	hostConfig := &container.HostConfig{Resources: resources}
+	networkingConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{},
+	}
+	if cArgs.network != "" {
+		// the container is only attached to the network of its job
+		hostConfig.NetworkMode = container.NetworkMode(cArgs.network)
+		networkingConfig.EndpointsConfig[cArgs.network] = &network.EndpointSettings{
+			Aliases: []string{cArgs.networkAlias, cArgs.name},
+		}
+	}
+
+	if err := h.checkImage(ctx, cArgs.image, spawnArgs); err != nil {
+		return err
+	}
+
+	ctxCreate, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	c, err := h.client.ContainerCreate(ctxCreate, config, hostConfig, networkingConfig, cArgs.name)
+	if err != nil {
+		return sdk.WrapError(err, "unable to create container %s", cArgs.name)
+	}
+
+	if err := h.client.ContainerStart(ctxCreate, c.ID, types.ContainerStartOptions{}); err != nil {
+		return sdk.WrapError(err, "unable to start container %s", cArgs.name)
+	}
+	return nil
+}
+
+// checkImage pulls the image if it is not available on the engine or if its tag is latest
+func (h *HatcheryDocker) checkImage(ctx context.Context, img string, spawnArgs hatchery.SpawnArguments) error {
+	// image names are resolved by the engine, podman adds the registry on short names
+	ctxInspect, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, _, err := h.client.ImageInspectWithRaw(ctxInspect, img)
+	if err != nil && !dockerclient.IsErrNotFound(err) {
+		log.Warning(ctx, "hatchery> docker> checkImage> Unable to inspect image %s: %v", img, err)
+	}
+	if err == nil && !strings.HasSuffix(img, ":latest") {
+		return nil
+	}
+
+	hatchery.SendSpawnInfo(ctx, h, spawnArgs.JobID, sdk.SpawnMsg{
+		ID:   sdk.MsgSpawnInfoHatcheryStartDockerPull.ID,
+		Args: []interface{}{h.Name(), img},
+	})
+	if err := h.pullImage(ctx, img, *spawnArgs.Model); err != nil {
+		hatchery.SendSpawnInfo(ctx, h, spawnArgs.JobID, sdk.SpawnMsg{
+			ID:   sdk.MsgSpawnInfoHatcheryEndDockerPullErr.ID,
+			Args: []interface{}{h.Name(), img, err},
+		})
+		return err
+	}
+	hatchery.SendSpawnInfo(ctx, h, spawnArgs.JobID, sdk.SpawnMsg{
+		ID:   sdk.MsgSpawnInfoHatcheryEndDockerPull.ID,
+		Args: []interface{}{h.Name(), img},
+	})
+	return nil
+}
+
+func (h *HatcheryDocker) pullImage(ctx context.Context, img string, model sdk.Model) error {
+	ctx, end := observability.Span(ctx, "docker.pullImage", observability.Tag("image", img))
+	defer end()
+	t0 := time.Now()
+
+	ctxPull, cancel := context.WithTimeout(ctx, timeoutPullImage)
+	defer cancel()
+
+	opts := types.ImageCreateOptions{}
+	if model.ModelDocker.Private {
+		registry := "index.docker.io"
+		if model.ModelDocker.Registry != "" {
+			urlParsed, err := url.Parse(model.ModelDocker.Registry)
+			if err != nil {
+				return sdk.WrapError(err, "cannot parse registry url %s", model.ModelDocker.Registry)
+			}
+			if urlParsed.Host == "" {
+				registry = urlParsed.Path
+			} else {
+				registry = urlParsed.Host
+			}
+		}
+		auth := fmt.Sprintf(`{"username": "%s", "password": "%s", "serveraddress": "%s"}`, model.ModelDocker.Username, model.ModelDocker.Password, registry)
+		opts.RegistryAuth = base64.StdEncoding.EncodeToString([]byte(auth))
+	}
+
+	res, err := h.client.ImageCreate(ctxPull, img, opts)
+	if err != nil {
+		return sdk.WrapError(err, "unable to pull image %s", img)
+	}
+	defer res.Close()
+	// the pull is done when the progress stream is fully read
+	if _, err := io.Copy(ioutil.Discard, res); err != nil {
+		return sdk.WrapError(err, "unable to pull image %s", img)
+	}
+
+	log.Info(ctx, "hatchery> docker> pullImage> pulling image %s - %.3f seconds elapsed", img, time.Since(t0).Seconds())
+	return nil
+}
@@ -0,0 +1,209 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	dockerclient "github.com/docker/docker/client"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// containers younger than this delay are not checked, the worker could be starting
+	containerGracePeriod = time.Minute
+	// networks are created before pulling images, so they are kept longer
+	networkGracePeriod = 10 * time.Minute
+)
+
+// killAwolContainers removes the workers unknown or disabled on the API, the services without worker
+// and the networks without containers.
+func (h *HatcheryDocker) killAwolContainers(ctx context.Context) error {
+	cs, err := h.getContainers(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctxList, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	apiWorkers, err := h.CDSClient().WorkerList(ctxList)
+	if err != nil {
+		return sdk.WrapError(err, "cannot get workers")
+	}
+
+	for _, name := range listAwolWorkers(cs, apiWorkers, time.Now()) {
+		log.Debug("hatchery> docker> killAwolContainers> Delete worker %s", name)
+		h.killAndRemoveWorker(ctx, name)
+	}
+
+	return h.killAwolNetworks(ctx, cs)
+}
+
+// listAwolWorkers returns the names of the workers that should be removed, services without worker are
+// returned with the name of their worker.
+func listAwolWorkers(cs []types.Container, apiWorkers []sdk.Worker, now time.Time) []string {
+	apiWorkersByName := make(map[string]sdk.Worker, len(apiWorkers))
+	for _, w := range apiWorkers {
+		apiWorkersByName[w.Name] = w
+	}
+
+	workerContainers := make(map[string]struct{})
+	var res []string
+	for _, c := range cs {
+		name, ok := c.Labels[labelWorkerName]
+		if !ok {
+			continue
+		}
+		workerContainers[name] = struct{}{}
+
+		if c.State != "exited" && c.State != "dead" && now.Add(-containerGracePeriod).Unix() < c.Created {
+			continue
+		}
+
+		// If there isn't any worker registered on the API or if it's disabled, kill the container
+		if w, ok := apiWorkersByName[name]; ok && w.Status != sdk.StatusDisabled && c.State == "running" {
+			continue
+		}
+		res = append(res, name)
+	}
+
+	// services whose worker container is gone
+	for _, c := range cs {
+		name, ok := c.Labels[labelServiceWorker]
+		if !ok {
+			continue
+		}
+		if _, ok := workerContainers[name]; ok || sdk.IsInArray(name, res) {
+			continue
+		}
+		if now.Add(-containerGracePeriod).Unix() < c.Created {
+			continue
+		}
+		res = append(res, name)
+	}
+
+	return res
+}
+
+// killAndRemoveWorker removes the worker container, its services and its network
+func (h *HatcheryDocker) killAndRemoveWorker(ctx context.Context, workerName string) {
+	cs, err := h.getContainers(ctx)
+	if err != nil {
+		log.Error(ctx, "hatchery> docker> killAndRemoveWorker> %v", err)
+		return
+	}
+
+	for _, c := range cs {
+		if c.Labels[labelWorkerName] != workerName && c.Labels[labelServiceWorker] != workerName {
+			continue
+		}
+		// If its a worker "register", check registration before deleting it
+		if strings.HasPrefix(workerName, "register-") && c.Labels[labelWorkerName] == workerName {
+			h.checkWorkerModelRegister(ctx, c)
+		}
+		if err := h.killAndRemoveContainer(ctx, c.ID); err != nil {
+			log.Error(ctx, "hatchery> docker> killAndRemoveWorker> %v", err)
+		}
+	}
+
+	ctxRemove, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := h.client.NetworkRemove(ctxRemove, workerName+"-net"); err != nil && !dockerclient.IsErrNotFound(err) {
+		log.Error(ctx, "hatchery> docker> killAndRemoveWorker> unable to remove network of worker %s: %v", workerName, err)
+	}
+}
+
+// checkWorkerModelRegister sends the logs of a register container to the API if the registration failed
+func (h *HatcheryDocker) checkWorkerModelRegister(ctx context.Context, c types.Container) {
+	modelPath := c.Labels[labelWorkerModelPath]
+	err := hatchery.CheckWorkerModelRegister(h, modelPath)
+	if err == nil {
+		return
+	}
+
+	spawnErr := sdk.SpawnErrorForm{Error: err.Error()}
+	ctxLogs, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	logsReader, err := h.client.ContainerLogs(ctxLogs, c.ID, types.ContainerLogsOptions{
+		ShowStderr: true,
+		ShowStdout: true,
+		Timestamps: true,
+	})
+	if err != nil {
+		spawnErr.Logs = []byte(fmt.Sprintf("unable to get container logs: %v", err))
+	} else {
+		defer logsReader.Close()
+		logs, err := ioutil.ReadAll(logsReader)
+		if err != nil {
+			log.Error(ctx, "hatchery> docker> checkWorkerModelRegister> cannot read logs of container %s: %v", c.ID, err)
+		}
+		spawnErr.Logs = logs
+	}
+
+	tuple := strings.SplitN(modelPath, "/", 2)
+	if len(tuple) != 2 {
+		return
+	}
+	if err := h.CDSClient().WorkerModelSpawnError(tuple[0], tuple[1], spawnErr); err != nil {
+		log.Error(ctx, "hatchery> docker> checkWorkerModelRegister> error on call client.WorkerModelSpawnError on worker model %s for register: %v", modelPath, err)
+	}
+}
+
+func (h *HatcheryDocker) killAndRemoveContainer(ctx context.Context, ID string) error {
+	log.Debug("hatchery> docker> killAndRemoveContainer> remove container %s", ID)
+	ctxRemove, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	if err := h.client.ContainerRemove(ctxRemove, ID, types.ContainerRemoveOptions{RemoveVolumes: true, Force: true}); err != nil {
+		// container could be already removed by a previous call
+		if !dockerclient.IsErrNotFound(err) && !strings.Contains(err.Error(), "is already in progress") {
+			return sdk.WrapError(err, "unable to remove container %s", ID)
+		}
+	}
+	return nil
+}
+
+// killAwolNetworks removes the networks of the hatchery which are not used by a container
+func (h *HatcheryDocker) killAwolNetworks(ctx context.Context, cs []types.Container) error {
+	ctxList, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	nets, err := h.client.NetworkList(ctxList, types.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", labelHatchery+"="+h.Config.Name)),
+	})
+	if err != nil {
+		return sdk.WrapError(err, "cannot get networks")
+	}
+
+	usedNetworks := make(map[string]struct{}, len(cs))
+	for _, c := range cs {
+		if n := c.Labels[labelWorkerNetwork]; n != "" {
+			usedNetworks[n] = struct{}{}
+		}
+	}
+
+	for _, n := range nets {
+		if _, ok := n.Labels[labelWorkerNetwork]; !ok {
+			continue
+		}
+		if _, ok := usedNetworks[n.Name]; ok {
+			continue
+		}
+		if time.Since(n.Created) < networkGracePeriod {
+			continue
+		}
+
+		log.Info(ctx, "hatchery> docker> killAwolNetworks> remove network %s (created on %v)", n.Name, n.Created)
+		ctxRemove, cancel := context.WithTimeout(ctx, 10*time.Second)
+		if err := h.client.NetworkRemove(ctxRemove, n.ID); err != nil && !dockerclient.IsErrNotFound(err) {
+			log.Warning(ctx, "hatchery> docker> killAwolNetworks> Unable to delete network %s: %v", n.Name, err)
+		}
+		cancel()
+	}
+	return nil
+}
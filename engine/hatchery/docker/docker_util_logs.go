@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"context"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// getServicesLogs sends the last logs of the service containers to the API
+func (h *HatcheryDocker) getServicesLogs(ctx context.Context) error {
+	cs, err := h.getContainers(ctx)
+	if err != nil {
+		return err
+	}
+
+	servicesLogs := make([]sdk.ServiceLog, 0, len(cs))
+	for _, c := range cs {
+		serviceLog, err := serviceLogFromLabels(c.Labels)
+		if err != nil {
+			log.Error(ctx, "hatchery> docker> getServicesLogs> invalid labels on container %s: %v", c.ID, err)
+			continue
+		}
+		if serviceLog == nil {
+			continue
+		}
+
+		ctxLogs, cancel := context.WithTimeout(ctx, time.Minute)
+		logsReader, err := h.client.ContainerLogs(ctxLogs, c.ID, types.ContainerLogsOptions{
+			Details:    true,
+			ShowStderr: true,
+			ShowStdout: true,
+			Timestamps: true,
+			Since:      "10s",
+		})
+		if err != nil {
+			log.Error(ctx, "hatchery> docker> getServicesLogs> cannot get logs for service container %s: %v", c.ID, err)
+			cancel()
+			continue
+		}
+		logs, err := ioutil.ReadAll(logsReader)
+		_ = logsReader.Close()
+		cancel()
+		if err != nil {
+			log.Error(ctx, "hatchery> docker> getServicesLogs> cannot read logs for service container %s: %v", c.ID, err)
+			continue
+		}
+
+		if len(logs) > 0 {
+			serviceLog.Val = string(logs)
+			servicesLogs = append(servicesLogs, *serviceLog)
+		}
+	}
+
+	if len(servicesLogs) == 0 {
+		return nil
+	}
+
+	ctxSend, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return sdk.WrapError(h.Client.QueueServiceLogs(ctxSend, servicesLogs), "cannot send service logs")
+}
+
+// serviceLogFromLabels returns nil if the container is not a service of a job
+func serviceLogFromLabels(labels map[string]string) (*sdk.ServiceLog, error) {
+	jobID, ok := labels[labelServiceJobID]
+	if !ok {
+		return nil, nil
+	}
+	serviceJobID, err := strconv.ParseInt(jobID, 10, 64)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot parse service job id %s", jobID)
+	}
+	serviceID, err := strconv.ParseInt(labels[labelServiceID], 10, 64)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot parse service id %s", labels[labelServiceID])
+	}
+	return &sdk.ServiceLog{
+		WorkflowNodeJobRunID:   serviceJobID,
+		ServiceRequirementID:   serviceID,
+		ServiceRequirementName: labels[labelServiceReqName],
+	}, nil
+}
@@ -0,0 +1,38 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	dockerclient "github.com/docker/docker/client"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func init() {
+	log.Initialize(&log.Conf{Level: "debug"})
+}
+
+func initTestHatcheryDocker(t *testing.T) *HatcheryDocker {
+	log.SetLogger(t)
+	httpClient := cdsclient.NewHTTPClient(1*time.Minute, false)
+	c, err := dockerclient.NewClientWithOpts(
+		dockerclient.WithHTTPClient(httpClient),
+		dockerclient.WithHost("https://lolcat.host"),
+		dockerclient.WithVersion("6.66"),
+	)
+	require.NoError(t, err)
+
+	gock.InterceptClient(httpClient)
+
+	h := New()
+	h.client = c
+	h.Config.Name = "dockery"
+	h.Config.MaxContainers = 4
+	h.Client = cdsclient.New(cdsclient.Config{Host: "https://lolcat.api"})
+	gock.InterceptClient(h.Client.HTTPClient())
+	return h
+}
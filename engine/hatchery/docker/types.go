@@ -0,0 +1,68 @@
+package docker
+
+import (
+	dockerclient "github.com/docker/docker/client"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/engine/service"
+)
+
+// Supported container runtimes
+const (
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+)
+
+// Labels set on containers and networks created by the hatchery
+const (
+	labelHatchery           = "hatchery"
+	labelWorkerName         = "worker_name"
+	labelWorkerModelPath    = "worker_model_path"
+	labelWorkerNetwork      = "worker_net"
+	labelServiceWorker      = "service_worker"
+	labelServiceJobID       = "service_job_id"
+	labelServiceID          = "service_id"
+	labelServiceName        = "service_name"
+	labelServiceReqName     = "service_req_name"
+	labelWorkerRequirements = "worker_requirements"
+)
+
+// HatcheryConfiguration is the configuration for hatchery
+type HatcheryConfiguration struct {
+	service.HatcheryCommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration"`
+
+	// Runtime is the container engine used to start workers
+	Runtime string `mapstructure:"runtime" toml:"runtime" default:"docker" commented:"false" comment:"Container runtime: docker or podman" json:"runtime"`
+
+	// Host is the socket of the container engine
+	Host string `mapstructure:"host" toml:"host" default:"" commented:"true" comment:"Socket of the container engine. Default: unix:///var/run/docker.sock for docker, unix://$XDG_RUNTIME_DIR/podman/podman.sock for a rootless podman" json:"host"`
+
+	// APIVersion is the version of the Docker API, negociated with the engine if empty
+	APIVersion string `mapstructure:"APIVersion" toml:"APIVersion" default:"" commented:"true" comment:"Docker API version, negociated with the container engine if empty" json:"APIVersion"`
+
+	// MaxContainers is the max number of containers (workers and services) started by the hatchery
+	MaxContainers int `mapstructure:"maxContainers" toml:"maxContainers" default:"10" commented:"false" comment:"Max Containers on Host managed by this Hatchery" json:"maxContainers"`
+
+	// DefaultMemory Worker default memory
+	DefaultMemory int `mapstructure:"defaultMemory" toml:"defaultMemory" default:"1024" commented:"false" comment:"Worker default memory in Mo" json:"defaultMemory"`
+
+	// CPUs is the max number of CPUs of each container
+	CPUs float64 `mapstructure:"cpus" toml:"cpus" default:"0" commented:"true" comment:"Max number of CPUs for each worker and service container, 0 means no limit" json:"cpus"`
+
+	// PidsLimit is the max number of processes of each container
+	PidsLimit int64 `mapstructure:"pidsLimit" toml:"pidsLimit" default:"0" commented:"true" comment:"Max number of processes for each worker and service container, 0 means no limit" json:"pidsLimit"`
+
+	// WorkerTTL Worker TTL (minutes)
+	WorkerTTL int `mapstructure:"workerTTL" toml:"workerTTL" default:"10" commented:"false" comment:"Worker TTL (minutes)" json:"workerTTL"`
+
+	// NetworkEnableIPv6 if true: set ipv6 to true
+	NetworkEnableIPv6 bool `mapstructure:"networkEnableIPv6" toml:"networkEnableIPv6" default:"false" commented:"false" comment:"if true: hatchery creates private network between services with ipv6 enabled" json:"networkEnableIPv6"`
+}
+
+// HatcheryDocker is a hatchery which starts workers as containers on a local Docker or Podman engine.
+// Each job gets its own network shared only with its services.
+type HatcheryDocker struct {
+	hatcheryCommon.Common
+	Config HatcheryConfiguration
+	client *dockerclient.Client
+}
@@ -0,0 +1,160 @@
+package hooks
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// emptyHash is sent as previous hash by repository managers when a ref is created
+const emptyHash = "0000000000000000000000000000000000000000"
+
+// maxPayloadCommits is the number of commits sent in a github push payload, files of older commits are missing
+const maxPayloadCommits = 20
+
+// changedFilesFromCommits returns the files touched by the given commits, without duplicates
+func changedFilesFromCommits(commits ...[]string) []string {
+	files := []string{}
+	seen := map[string]struct{}{}
+	for _, c := range commits {
+		for _, f := range c {
+			if _, has := seen[f]; has {
+				continue
+			}
+			seen[f] = struct{}{}
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// pathFilters returns the include and exclude patterns of a hook configuration, patterns are separated by ';'
+func pathFilters(cfg sdk.WorkflowNodeHookConfig) (include []string, exclude []string) {
+	split := func(key string) []string {
+		var res []string
+		for _, p := range strings.Split(cfg[key].Value, ";") {
+			if p = strings.TrimSpace(p); p != "" {
+				res = append(res, p)
+			}
+		}
+		return res
+	}
+	return split(sdk.HookConfigPathsInclude), split(sdk.HookConfigPathsExclude)
+}
+
+// matchPathFilters checks that at least one file matches an include pattern and no exclude pattern.
+// Without include pattern, all files are included.
+func matchPathFilters(files, include, exclude []string) bool {
+	for _, f := range files {
+		included := len(include) == 0
+		for _, p := range include {
+			if matchPath(p, f) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			continue
+		}
+		excluded := false
+		for _, p := range exclude {
+			if matchPath(p, f) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath checks a file path against a glob pattern. '*' and '?' do not match '/', '**' matches
+// any number of directories and a pattern ending with '/' matches everything under the directory.
+func matchPath(pattern, file string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	rs := []rune(pattern)
+	for i := 0; i < len(rs); i++ {
+		switch rest := string(rs[i:]); {
+		case strings.HasPrefix(rest, "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(rest, "**"):
+			b.WriteString(".*")
+			i++
+		case rs[i] == '*':
+			b.WriteString("[^/]*")
+		case rs[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(rs[i])))
+		}
+	}
+	b.WriteString("$")
+
+	r, err := regexp.Compile(b.String())
+	if err != nil {
+		return false
+	}
+	return r.MatchString(strings.TrimPrefix(file, "/"))
+}
+
+// vcsChangedFiles asks the repository manager for the files changed between the two commits of a push.
+// It returns false if the files cannot be computed, for a new ref or a repository manager that can't compare the commits.
+func (s *Service) vcsChangedFiles(uuid, before, after string) ([]string, bool, error) {
+	if before == "" || after == "" || before == emptyHash {
+		return nil, false, nil
+	}
+	files, err := s.Client.HookChangedFiles(uuid, before, after)
+	if sdk.ErrorIs(err, sdk.ErrHookChangedFilesUnknown) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "cannot get changed files between %s and %s", before, after)
+	}
+	return files, true, nil
+}
+
+// filterChangedFiles sets the changed files of a push in the payload and checks them against the path filters of the hook.
+// Files sent by the repository manager in the push payload are used first, then the repository manager is requested
+// if the hook has path filters. It returns false if the workflow must not be triggered.
+func (s *Service) filterChangedFiles(ctx context.Context, uuid string, cfg sdk.WorkflowNodeHookConfig, payload map[string]interface{}) (bool, error) {
+	include, exclude := pathFilters(cfg)
+
+	files, known := payload[GIT_CHANGED_FILES].([]string)
+	delete(payload, GIT_CHANGED_FILES)
+	// Path filters only apply on pushes, pull requests events are always sent
+	if _, isPR := payload[PR_ID]; isPR {
+		return true, nil
+	}
+
+	if !known && (len(include) > 0 || len(exclude) > 0) {
+		before, _ := payload[GIT_HASH_BEFORE].(string)
+		after, _ := payload[GIT_HASH].(string)
+		var err error
+		files, known, err = s.vcsChangedFiles(uuid, before, after)
+		if err != nil {
+			return false, err
+		}
+	}
+	if !known {
+		return true, nil
+	}
+
+	payload[GIT_CHANGED_FILES] = strings.Join(files, ",")
+	if (len(include) > 0 || len(exclude) > 0) && !matchPathFilters(files, include, exclude) {
+		log.Info(ctx, "hook %s: no changed file matches the path filters, workflow is not triggered", uuid)
+		return false, nil
+	}
+	return true, nil
+}
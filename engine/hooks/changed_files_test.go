@@ -0,0 +1,125 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		match   bool
+	}{
+		{"services/billing/", "services/billing/main.go", true},
+		{"services/billing/", "services/billing/api/handler.go", true},
+		{"services/billing/", "services/billing-v2/main.go", false},
+		{"/services/billing/**", "services/billing/api/handler.go", true},
+		{"services/*/main.go", "services/billing/main.go", true},
+		{"services/*/main.go", "services/billing/cmd/main.go", false},
+		{"services/**/main.go", "services/billing/cmd/main.go", true},
+		{"services/**/main.go", "services/main.go", true},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/content/index.md", true},
+		{"*.md", "docs/index.md", false},
+		{"docs/v?/index.md", "docs/v2/index.md", true},
+		{"docs/v?/index.md", "docs/v10/index.md", false},
+		{"go.mod", "go.mod", true},
+		{"go.mod", "tools/go.mod", false},
+		{"docs/(draft)/*", "docs/(draft)/todo.md", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, matchPath(tt.pattern, tt.file), "%s on %s", tt.pattern, tt.file)
+	}
+}
+
+func TestMatchPathFilters(t *testing.T) {
+	files := []string{"services/billing/main.go", "docs/billing.md"}
+
+	assert.True(t, matchPathFilters(files, []string{"services/billing/"}, nil))
+	assert.False(t, matchPathFilters(files, []string{"services/shipping/"}, nil))
+	assert.True(t, matchPathFilters(files, nil, []string{"**/*.md"}))
+	assert.False(t, matchPathFilters(files, []string{"services/billing/"}, []string{"**/*.go"}))
+	assert.False(t, matchPathFilters(files, nil, []string{"**/*.md", "**/*.go"}))
+	assert.False(t, matchPathFilters(nil, nil, []string{"**/*.md"}))
+}
+
+func TestFilterChangedFiles(t *testing.T) {
+	s := Service{}
+	cfg := sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigPathsInclude: {Value: "services/billing/; libs/**"},
+		sdk.HookConfigPathsExclude: {Value: "**/*.md"},
+	}
+
+	payload := map[string]interface{}{
+		GIT_HASH_BEFORE:   "70849c92d899f30f092ad74cd59a651e03a07902",
+		GIT_HASH:          "bb488dea35f140fcac3ffd04d2d01f0f29c75100",
+		GIT_CHANGED_FILES: []string{"services/billing/README.md", "libs/money/money.go"},
+	}
+	trigger, err := s.filterChangedFiles(context.TODO(), "123", cfg, payload)
+	require.NoError(t, err)
+	assert.True(t, trigger)
+	assert.Equal(t, "services/billing/README.md,libs/money/money.go", payload[GIT_CHANGED_FILES])
+
+	payload[GIT_CHANGED_FILES] = []string{"services/billing/README.md", "services/shipping/main.go"}
+	trigger, err = s.filterChangedFiles(context.TODO(), "123", cfg, payload)
+	require.NoError(t, err)
+	assert.False(t, trigger)
+
+	// Without changed files nor previous commit, the workflow is always triggered
+	payload = map[string]interface{}{
+		GIT_HASH_BEFORE: emptyHash,
+		GIT_HASH:        "bb488dea35f140fcac3ffd04d2d01f0f29c75100",
+	}
+	trigger, err = s.filterChangedFiles(context.TODO(), "123", cfg, payload)
+	require.NoError(t, err)
+	assert.True(t, trigger)
+	_, has := payload[GIT_CHANGED_FILES]
+	assert.False(t, has)
+
+	// Pull requests are not filtered
+	payload = map[string]interface{}{
+		PR_ID:             1,
+		GIT_CHANGED_FILES: []string{"services/shipping/main.go"},
+	}
+	trigger, err = s.filterChangedFiles(context.TODO(), "123", cfg, payload)
+	require.NoError(t, err)
+	assert.True(t, trigger)
+}
+
+func TestVCSChangedFiles(t *testing.T) {
+	defer gock.Off()
+	client := cdsclient.New(cdsclient.Config{Host: "http://lolcat.api", SessionToken: "xxx"})
+	gock.InterceptClient(client.(cdsclient.Raw).HTTPClient())
+	s := Service{Common: service.Common{Client: client}}
+
+	gock.New("http://lolcat.api").Get("/hook/123/changes").
+		MatchParam("base", "70849c92d899f30f092ad74cd59a651e03a07902").
+		MatchParam("head", "bb488dea35f140fcac3ffd04d2d01f0f29c75100").
+		Reply(200).JSON([]string{"services/billing/main.go"})
+	files, known, err := s.vcsChangedFiles("123", "70849c92d899f30f092ad74cd59a651e03a07902", "bb488dea35f140fcac3ffd04d2d01f0f29c75100")
+	require.NoError(t, err)
+	assert.True(t, known)
+	assert.Equal(t, []string{"services/billing/main.go"}, files)
+
+	// The repository manager can't compare the commits, files are unknown and the workflow is triggered
+	gock.New("http://lolcat.api").Get("/hook/123/changes").
+		Reply(sdk.ErrHookChangedFilesUnknown.Status).JSON(sdk.ExtractHTTPError(sdk.ErrHookChangedFilesUnknown, ""))
+	_, known, err = s.vcsChangedFiles("123", "70849c92d899f30f092ad74cd59a651e03a07902", "bb488dea35f140fcac3ffd04d2d01f0f29c75100")
+	require.NoError(t, err)
+	assert.False(t, known)
+
+	gock.New("http://lolcat.api").Get("/hook/123/changes").
+		Reply(sdk.ErrNotFound.Status).JSON(sdk.ExtractHTTPError(sdk.ErrNotFound, ""))
+	_, _, err = s.vcsChangedFiles("123", "70849c92d899f30f092ad74cd59a651e03a07902", "bb488dea35f140fcac3ffd04d2d01f0f29c75100")
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
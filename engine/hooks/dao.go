@@ -16,6 +16,8 @@ const (
 
 type dao struct {
 	store cache.Store
+	// secretKey encrypts the webhook secrets of the tasks
+	secretKey []byte
 }
 
 func (d *dao) FindAllTasks(ctx context.Context) ([]sdk.Task, error) {
@@ -33,6 +35,9 @@ func (d *dao) FindAllTasks(ctx context.Context) ([]sdk.Task, error) {
 
 	alltasks := make([]sdk.Task, nbTasks)
 	for i := 0; i < nbTasks; i++ {
+		if err := decryptTaskSecret(d.secretKey, tasks[i]); err != nil {
+			log.Error(ctx, "%v", err)
+		}
 		alltasks[i] = *tasks[i]
 	}
 
@@ -50,14 +55,22 @@ func (d *dao) FindTask(ctx context.Context, uuid string) *sdk.Task {
 	if err != nil {
 		log.Error(ctx, "cannot get from cache %s: %v", key, err)
 	}
-	if find {
-		return t
+	if !find {
+		return nil
 	}
-	return nil
+	// On error the secret stays encrypted, so the signatures of the webhook calls are rejected
+	if err := decryptTaskSecret(d.secretKey, t); err != nil {
+		log.Error(ctx, "%v", err)
+	}
+	return t
 }
 
 func (d *dao) SaveTask(r *sdk.Task) error {
-	return d.store.SetAdd(rootKey, r.UUID, r)
+	t, err := encryptTaskSecret(d.secretKey, r)
+	if err != nil {
+		return err
+	}
+	return d.store.SetAdd(rootKey, r.UUID, t)
 }
 
 func (d *dao) DeleteTask(ctx context.Context, r *sdk.Task) error {
@@ -73,9 +86,16 @@ func (d *dao) DeleteTask(ctx context.Context, r *sdk.Task) error {
 	return nil
 }
 
+// SaveTaskExecution saves an execution without the webhook secret of its task, it is not needed to process it
 func (d *dao) SaveTaskExecution(r *sdk.TaskExecution) error {
 	setKey := cache.Key(executionRootKey, r.Type, r.UUID)
 	execKey := fmt.Sprintf("%d", r.Timestamp)
+	if _, has := r.Config[sdk.HookConfigWebHookSecret]; has {
+		e := *r
+		e.Config = r.Config.Clone()
+		delete(e.Config, sdk.HookConfigWebHookSecret)
+		r = &e
+	}
 	return d.store.SetAdd(setKey, execKey, r)
 }
 
@@ -152,6 +152,14 @@ func (s *Service) doGerritExecution(e *sdk.TaskExecution) (*sdk.WorkflowNodeRunH
 
 	payload["payload"] = string(e.GerritEvent.Message)
 
+	trigger, err := s.filterChangedFiles(context.Background(), e.UUID, e.Config, payload)
+	if err != nil {
+		return nil, err
+	}
+	if !trigger {
+		return nil, nil
+	}
+
 	d := dump.NewDefaultEncoder()
 	d.ExtraFields.Type = false
 	d.ExtraFields.Len = false
@@ -209,6 +217,7 @@ func (s *Service) ComputeGerritStreamEvent(ctx context.Context, vcsServer string
 					Timestamp: time.Now().UnixNano(),
 					UUID:      h.UUID,
 					Status:    TaskExecutionScheduled,
+					Config:    gerritHook.Config,
 					GerritEvent: &sdk.GerritEventExecution{
 						Message: msg,
 					},
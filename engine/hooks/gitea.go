@@ -0,0 +1,128 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// giteaEmptyHash is the after hash sent by gitea when a branch is deleted
+const giteaEmptyHash = "0000000000000000000000000000000000000000"
+
+func (s *Service) generatePayloadFromGiteaRequest(ctx context.Context, t *sdk.TaskExecution, event string) (map[string]interface{}, error) {
+	projectKey := t.Config["project"].Value
+	workflowName := t.Config["workflow"].Value
+
+	var request GiteaWebHookEvent
+	if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read gitea request: %s", string(t.WebHook.RequestBody))
+	}
+
+	payload := make(map[string]interface{})
+	payload[GIT_EVENT] = event
+
+	switch {
+	case request.PullRequest != nil:
+		getPayloadFromGiteaPullRequest(payload, request.PullRequest)
+	case event == "delete":
+		// Gitea sends a delete event with the short name of the ref
+		if request.RefType == "branch" {
+			err := s.enqueueBranchDeletion(projectKey, workflowName, request.Ref)
+			return nil, sdk.WrapError(err, "cannot enqueue branch deletion")
+		}
+		// Nothing to run for a deleted tag
+		return nil, nil
+	case request.Ref != "":
+		branch := strings.TrimPrefix(request.Ref, "refs/heads/")
+		if request.After == giteaEmptyHash {
+			if strings.HasPrefix(request.Ref, "refs/tags/") {
+				return nil, nil
+			}
+			err := s.enqueueBranchDeletion(projectKey, workflowName, branch)
+			return nil, sdk.WrapError(err, "cannot enqueue branch deletion")
+		}
+		if err := s.stopBranchDeletionTask(ctx, branch); err != nil {
+			log.Error(ctx, "cannot stop branch deletion task for branch %s : %v", branch, err)
+		}
+
+		if !strings.HasPrefix(request.Ref, "refs/tags/") {
+			payload[GIT_BRANCH] = branch
+		} else {
+			payload[GIT_TAG] = strings.TrimPrefix(request.Ref, "refs/tags/")
+		}
+		if request.Before != "" && request.Before != giteaEmptyHash {
+			payload[GIT_HASH_BEFORE] = request.Before
+		}
+		if request.After != "" {
+			setGiteaPayloadHash(payload, request.After)
+		}
+	}
+
+	if request.Repository != nil {
+		payload[GIT_REPOSITORY] = request.Repository.FullName
+	}
+
+	headCommit := request.HeadCommit
+	if headCommit == nil && len(request.Commits) > 0 {
+		// Old versions of gitea do not send head_commit, the last pushed commit is the first one
+		headCommit = &request.Commits[0]
+	}
+	if headCommit != nil {
+		payload[GIT_MESSAGE] = headCommit.Message
+		payload[GIT_AUTHOR] = headCommit.Author.Username
+		payload[GIT_AUTHOR_EMAIL] = headCommit.Author.Email
+	}
+
+	user := request.Pusher
+	if user == nil {
+		user = request.Sender
+	}
+	if user != nil {
+		payload[CDS_TRIGGERED_BY_USERNAME] = user.Login
+		payload[CDS_TRIGGERED_BY_FULLNAME] = user.FullName
+		payload[CDS_TRIGGERED_BY_EMAIL] = user.Email
+	}
+
+	// Gitea sends a limited number of commits, older versions do not send the total count
+	if len(request.Commits) > 0 && len(request.Commits) == request.TotalCommits {
+		changes := make([][]string, 0, len(request.Commits)*3)
+		for _, c := range request.Commits {
+			changes = append(changes, c.Added, c.Removed, c.Modified)
+		}
+		payload[GIT_CHANGED_FILES] = changedFilesFromCommits(changes...)
+	}
+
+	for i := range request.Commits {
+		request.Commits[i].Added = nil
+		request.Commits[i].Removed = nil
+		request.Commits[i].Modified = nil
+	}
+	getPayloadStringVariable(ctx, payload, request)
+
+	return payload, nil
+}
+
+func getPayloadFromGiteaPullRequest(payload map[string]interface{}, pr *GiteaPullRequest) {
+	payload[PR_ID] = pr.Number
+	payload[PR_TITLE] = pr.Title
+	payload[PR_STATE] = pr.State
+	payload[GIT_BRANCH] = pr.Head.Ref
+	setGiteaPayloadHash(payload, pr.Head.Sha)
+	if pr.Head.Repo != nil {
+		payload[GIT_REPOSITORY_BEFORE] = pr.Head.Repo.FullName
+	}
+	payload[GIT_AUTHOR] = pr.User.Login
+	payload[GIT_AUTHOR_EMAIL] = pr.User.Email
+}
+
+func setGiteaPayloadHash(payload map[string]interface{}, hash string) {
+	payload[GIT_HASH] = hash
+	hashShort := hash
+	if len(hashShort) >= 7 {
+		hashShort = hashShort[:7]
+	}
+	payload[GIT_HASH_SHORT] = hashShort
+}
@@ -56,6 +56,15 @@ func (s *Service) generatePayloadFromGithubRequest(ctx context.Context, t *sdk.T
 		payload[GIT_MESSAGE] = request.Commits[0].Message
 	}
 
+	// Github sends at most 20 commits, files of older ones are unknown
+	if len(request.Commits) > 0 && len(request.Commits) < maxPayloadCommits {
+		changes := make([][]string, 0, len(request.Commits)*3)
+		for _, c := range request.Commits {
+			changes = append(changes, c.Added, c.Removed, c.Modified)
+		}
+		payload[GIT_CHANGED_FILES] = changedFilesFromCommits(changes...)
+	}
+
 	for i := range request.Commits {
 		request.Commits[i].Added = nil
 		request.Commits[i].Removed = nil
@@ -59,6 +59,14 @@ func (s *Service) generatePayloadFromGitlabRequest(ctx context.Context, t *sdk.T
 
 	getPayloadFromGitlabProject(payload, request.Project)
 	getPayloadFromGitlabCommit(payload, request.Commits)
+	// Gitlab sends at most 20 commits, files of older ones are unknown
+	if len(request.Commits) > 0 && len(request.Commits) == request.TotalCommitsCount {
+		changes := make([][]string, 0, len(request.Commits)*3)
+		for _, c := range request.Commits {
+			changes = append(changes, c.Added, c.Removed, c.Modified)
+		}
+		payload[GIT_CHANGED_FILES] = changedFilesFromCommits(changes...)
+	}
 	getPayloadStringVariable(ctx, payload, request)
 
 	return payload, nil
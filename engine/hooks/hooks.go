@@ -81,13 +81,21 @@ func (s *Service) Serve(c context.Context) error {
 
 	//Init the cache
 	var errCache error
-	s.Cache, errCache = cache.New(s.Cfg.Cache.Redis.Host, s.Cfg.Cache.Redis.Password, s.Cfg.Cache.TTL)
+	s.Cache, errCache = cache.New(ctx, s.Cfg.Cache.Mode, s.Cfg.Cache.Redis.Host, s.Cfg.Cache.Redis.Password, s.Cfg.Cache.TTL)
 	if errCache != nil {
 		return fmt.Errorf("Cannot connect to redis instance : %v", errCache)
 	}
 
 	//Init the DAO
-	s.Dao = dao{s.Cache}
+	secretKey, err := newSecretKey(s.Cfg.API.Token)
+	if err != nil {
+		return fmt.Errorf("cannot init webhook secrets encryption: %v", err)
+	}
+	s.Dao = dao{store: s.Cache, secretKey: secretKey}
+
+	if err := initMetrics(); err != nil {
+		return fmt.Errorf("cannot init metrics: %v", err)
+	}
 
 	// Get current maintenance state
 	var b bool
@@ -13,6 +13,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -46,6 +47,19 @@ func (s *Service) webhookHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to read request")
 		}
 
+		ctx = observability.ContextWithTag(ctx,
+			observability.TagServiceName, s.Name(),
+			observability.TagServiceType, s.Type(),
+		)
+		observability.Record(ctx, metrics.WebHooks, 1)
+
+		//Check the signature
+		if reason, err := verifyWebHookSignature(webHook, r.Header, req); err != nil {
+			observability.Record(observability.ContextWithTag(ctx, tagRejectReason, reason), metrics.WebHooksRejected, 1)
+			log.Warning(ctx, "webhookHandler> webhook %s rejected: %v", uuid, err)
+			return err
+		}
+
 		//Prepare a web hook execution
 		exec := &sdk.TaskExecution{
 			Timestamp: time.Now().UnixNano(),
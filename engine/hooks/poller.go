@@ -92,26 +92,38 @@ func (s *Service) doPollerTaskExecution(ctx context.Context, task *sdk.Task, tas
 		payloadValues["payload"] = string(payload.Value)
 	}
 
+	include, exclude := pathFilters(task.Config)
+
 	var hookEvents []sdk.WorkflowNodeRunHookEvent
 	if len(events.PushEvents) > 0 || len(events.PullRequestEvents) > 0 {
-		i := 0
-		hookEvents = make([]sdk.WorkflowNodeRunHookEvent, len(events.PushEvents)+len(events.PullRequestEvents))
+		hookEvents = make([]sdk.WorkflowNodeRunHookEvent, 0, len(events.PushEvents)+len(events.PullRequestEvents))
 		for _, pushEvent := range events.PushEvents {
 			payload := fillPayload(ctx, pushEvent)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
+			if len(include) > 0 || len(exclude) > 0 {
+				files, known, err := s.vcsChangedFiles(task.UUID, pushEvent.Before, pushEvent.Commit.Hash)
+				if err != nil {
+					return nil, err
+				}
+				if known {
+					payload[GIT_CHANGED_FILES] = strings.Join(files, ",")
+					if !matchPathFilters(files, include, exclude) {
+						log.Info(ctx, "hook %s: no changed file matches the path filters on %s, workflow is not triggered", task.UUID, pushEvent.Branch.DisplayID)
+						continue
+					}
+				}
+			}
+			hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
 				WorkflowNodeHookUUID: task.UUID,
 				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
-			}
-			i++
+			})
 		}
 
 		for _, pullRequestEvent := range events.PullRequestEvents {
 			payload := fillPayload(ctx, pullRequestEvent.Head)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
+			hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
 				WorkflowNodeHookUUID: task.UUID,
 				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
-			}
-			i++
+			})
 		}
 	}
 
@@ -0,0 +1,38 @@
+package hooks
+
+import (
+	"sync"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"github.com/ovh/cds/engine/api/observability"
+)
+
+const tagRejectReason = "reason"
+
+// Metrics contains the metric stats measures of the hooks service
+type Metrics struct {
+	WebHooks         *stats.Int64Measure
+	WebHooksRejected *stats.Int64Measure
+}
+
+var (
+	onceMetrics sync.Once
+	metrics     Metrics
+)
+
+func initMetrics() error {
+	var err error
+	onceMetrics.Do(func() {
+		metrics.WebHooks = stats.Int64("cds/webhooks", "number of received webhooks", stats.UnitDimensionless)
+		metrics.WebHooksRejected = stats.Int64("cds/webhooks_rejected", "number of webhooks rejected because of their signature", stats.UnitDimensionless)
+
+		tags := []tag.Key{observability.MustNewKey(observability.TagServiceType), observability.MustNewKey(observability.TagServiceName)}
+		err = observability.RegisterView(
+			observability.NewViewCount("cds/hooks/webhooks_count", metrics.WebHooks, tags),
+			observability.NewViewCount("cds/hooks/webhooks_rejected_count", metrics.WebHooksRejected, append(tags, observability.MustNewKey(tagRejectReason))),
+		)
+	})
+	return err
+}
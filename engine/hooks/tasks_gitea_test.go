@@ -0,0 +1,181 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func Test_doWebHookExecutionGitea(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(giteaPushEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader:  {"push"},
+				GithubHeader: {"push"},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "my-branch", hs[0].Payload["git.branch"])
+	assert.Equal(t, "alice", hs[0].Payload["git.author"])
+	assert.Equal(t, "Update README.md\n", hs[0].Payload["git.message"])
+	assert.Equal(t, "9f2e3b5d6c7a8e9f0a1b2c3d4e5f6a7b8c9d0e1f", hs[0].Payload["git.hash"])
+	assert.Equal(t, "cds/demo", hs[0].Payload["git.repository"])
+	assert.Equal(t, "alice", hs[0].Payload["cds.triggered_by.username"])
+}
+
+func Test_doWebHookExecutionGiteaPullRequest(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: sdk.WorkflowNodeHookConfigValue{
+				Value: "pull_request",
+			},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(giteaPullRequestEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader: {"pull_request"},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "5", hs[0].Payload["git.pr.id"])
+	assert.Equal(t, "Add changelog", hs[0].Payload["git.pr.title"])
+	assert.Equal(t, "open", hs[0].Payload["git.pr.state"])
+	assert.Equal(t, "feat/changelog", hs[0].Payload["git.branch"])
+	assert.Equal(t, "d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3", hs[0].Payload["git.hash"])
+	assert.Equal(t, "cds/demo", hs[0].Payload["git.repository"])
+	assert.Equal(t, "alice/demo", hs[0].Payload["git.repository.before"])
+}
+
+func Test_doWebHookExecutionGiteaDeleteTag(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			"project":  sdk.WorkflowNodeHookConfigValue{Value: "PROJ"},
+			"workflow": sdk.WorkflowNodeHookConfigValue{Value: "demo"},
+			sdk.HookConfigEventFilter: sdk.WorkflowNodeHookConfigValue{
+				Value: "push;delete",
+			},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(giteaDeleteTagEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader: {"delete"},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+	assert.Empty(t, hs)
+}
+
+func Test_getRepositoryHeaderGitea(t *testing.T) {
+	whe := &sdk.WebHookExecution{
+		RequestHeader: map[string][]string{
+			GiteaHeader:  {"push"},
+			GithubHeader: {"push"},
+		},
+	}
+	assert.Equal(t, GiteaHeader, getRepositoryHeader(whe, nil))
+	assert.Equal(t, "", getRepositoryHeader(whe, []string{"pull_request"}))
+}
+
+var giteaPushEvent = `
+{
+  "ref": "refs/heads/my-branch",
+  "before": "c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2",
+  "after": "9f2e3b5d6c7a8e9f0a1b2c3d4e5f6a7b8c9d0e1f",
+  "compare_url": "https://gitea.example.com/cds/demo/compare/c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2...9f2e3b5d6c7a8e9f0a1b2c3d4e5f6a7b8c9d0e1f",
+  "commits": [
+    {
+      "id": "9f2e3b5d6c7a8e9f0a1b2c3d4e5f6a7b8c9d0e1f",
+      "message": "Update README.md\n",
+      "url": "https://gitea.example.com/cds/demo/commit/9f2e3b5d6c7a8e9f0a1b2c3d4e5f6a7b8c9d0e1f",
+      "author": {"name": "Alice", "email": "alice@example.com", "username": "alice"},
+      "committer": {"name": "Alice", "email": "alice@example.com", "username": "alice"},
+      "verification": null,
+      "timestamp": "2020-03-02T10:00:00Z",
+      "added": [],
+      "removed": [],
+      "modified": ["README.md"]
+    }
+  ],
+  "head_commit": null,
+  "repository": {
+    "id": 12,
+    "owner": {"id": 3, "login": "cds", "full_name": "", "email": "cds@example.com", "avatar_url": "https://gitea.example.com/avatars/3", "username": "cds"},
+    "name": "demo",
+    "full_name": "cds/demo",
+    "description": "",
+    "private": false,
+    "fork": false,
+    "html_url": "https://gitea.example.com/cds/demo",
+    "ssh_url": "git@gitea.example.com:cds/demo.git",
+    "clone_url": "https://gitea.example.com/cds/demo.git",
+    "default_branch": "main"
+  },
+  "pusher": {"id": 7, "login": "alice", "full_name": "Alice", "email": "alice@example.com", "avatar_url": "https://gitea.example.com/avatars/7", "username": "alice"},
+  "sender": {"id": 7, "login": "alice", "full_name": "Alice", "email": "alice@example.com", "avatar_url": "https://gitea.example.com/avatars/7", "username": "alice"}
+}
+`
+
+var giteaPullRequestEvent = `
+{
+  "action": "opened",
+  "number": 5,
+  "pull_request": {
+    "id": 41,
+    "number": 5,
+    "user": {"id": 7, "login": "alice", "full_name": "Alice", "email": "alice@example.com", "username": "alice"},
+    "title": "Add changelog",
+    "body": "",
+    "state": "open",
+    "html_url": "https://gitea.example.com/cds/demo/pulls/5",
+    "merged": false,
+    "head": {"label": "feat/changelog", "ref": "feat/changelog", "sha": "d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3", "repo_id": 21, "repo": {"id": 21, "name": "demo", "full_name": "alice/demo"}},
+    "base": {"label": "main", "ref": "main", "sha": "c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2", "repo_id": 12, "repo": {"id": 12, "name": "demo", "full_name": "cds/demo"}}
+  },
+  "repository": {"id": 12, "name": "demo", "full_name": "cds/demo"},
+  "sender": {"id": 7, "login": "alice", "full_name": "Alice", "email": "alice@example.com", "username": "alice"}
+}
+`
+
+var giteaDeleteTagEvent = `
+{
+  "ref": "v1.0.0",
+  "ref_type": "tag",
+  "pusher_type": "user",
+  "repository": {"id": 12, "name": "demo", "full_name": "cds/demo"},
+  "sender": {"id": 7, "login": "alice", "full_name": "Alice", "email": "alice@example.com", "username": "alice"}
+}
+`
@@ -19,7 +19,8 @@ func setupTestHookService(t *testing.T) (Service, func()) {
 		t.Fatalf("Unable to connect to redis: %v", err)
 	}
 	s.Dao = dao{
-		store: store,
+		store:     store,
+		secretKey: make([]byte, 32),
 	}
 
 	cancel := func() {
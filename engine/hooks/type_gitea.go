@@ -0,0 +1,80 @@
+package hooks
+
+import "time"
+
+// GiteaWebHookEvent represents payload send by gitea (or forgejo) on push, delete and pull_request events
+type GiteaWebHookEvent struct {
+	Ref          string            `json:"ref"`
+	RefType      string            `json:"ref_type"`
+	Before       string            `json:"before"`
+	After        string            `json:"after"`
+	CompareURL   string            `json:"compare_url"`
+	Commits      []GiteaCommit     `json:"commits"`
+	TotalCommits int               `json:"total_commits"`
+	HeadCommit   *GiteaCommit      `json:"head_commit"`
+	Repository   *GiteaRepository  `json:"repository"`
+	Pusher       *GiteaUser        `json:"pusher"`
+	Sender       *GiteaUser        `json:"sender"`
+	Action       string            `json:"action"`
+	Number       int64             `json:"number"`
+	PullRequest  *GiteaPullRequest `json:"pull_request"`
+}
+
+type GiteaUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	Username  string `json:"username"`
+}
+
+type GiteaCommit struct {
+	ID        string      `json:"id"`
+	Message   string      `json:"message"`
+	URL       string      `json:"url"`
+	Author    GiteaAuthor `json:"author"`
+	Committer GiteaAuthor `json:"committer"`
+	Timestamp time.Time   `json:"timestamp"`
+	Added     []string    `json:"added"`
+	Removed   []string    `json:"removed"`
+	Modified  []string    `json:"modified"`
+}
+
+type GiteaAuthor struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+type GiteaRepository struct {
+	ID            int64     `json:"id"`
+	Owner         GiteaUser `json:"owner"`
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	HTMLURL       string    `json:"html_url"`
+	SSHURL        string    `json:"ssh_url"`
+	CloneURL      string    `json:"clone_url"`
+	DefaultBranch string    `json:"default_branch"`
+}
+
+type GiteaPullRequest struct {
+	ID      int64            `json:"id"`
+	Number  int64            `json:"number"`
+	User    GiteaUser        `json:"user"`
+	Title   string           `json:"title"`
+	State   string           `json:"state"`
+	HTMLURL string           `json:"html_url"`
+	Merged  bool             `json:"merged"`
+	Head    GiteaPRBranchRef `json:"head"`
+	Base    GiteaPRBranchRef `json:"base"`
+}
+
+type GiteaPRBranchRef struct {
+	Label string           `json:"label"`
+	Ref   string           `json:"ref"`
+	Sha   string           `json:"sha"`
+	Repo  *GiteaRepository `json:"repo"`
+}
@@ -39,7 +39,8 @@ type Configuration struct {
 	Disable          bool                            `toml:"disable" default:"false" comment:"Disable all hooks executions" json:"disable"`
 	API              service.APIServiceConfiguration `toml:"api" comment:"######################\n CDS API Settings \n######################" json:"api"`
 	Cache            struct {
-		TTL   int `toml:"ttl" default:"60" json:"ttl"`
+		TTL   int    `toml:"ttl" default:"60" json:"ttl"`
+		Mode  string `toml:"mode" default:"redis" comment:"redis or local. With local, the cache is kept in memory and redis settings are ignored, use it only for a single instance installation" json:"mode"`
 		Redis struct {
 			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax! <clustername>@sentinel1:26379,sentinel2:26379,sentinel3:26379" json:"host"`
 			Password string `toml:"password" json:"-"`
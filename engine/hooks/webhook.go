@@ -28,7 +28,13 @@ func (s *Service) doWebHookExecution(ctx context.Context, e *sdk.TaskExecution)
 }
 
 func getRepositoryHeader(whe *sdk.WebHookExecution, events []string) string {
-	if v, ok := whe.RequestHeader[GithubHeader]; ok && ((len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events)) {
+	// Gitea also sends the Github header, it must be checked first
+	if v, ok := whe.RequestHeader[GiteaHeader]; ok {
+		if (len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events) {
+			return GiteaHeader
+		}
+		return ""
+	} else if v, ok := whe.RequestHeader[GithubHeader]; ok && ((len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events)) {
 		return GithubHeader
 	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && ((len(events) == 0 && v[0] == "Push Hook") || sdk.IsInArray(v[0], events)) {
 		return GitlabHeader
@@ -60,6 +66,15 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		if payload != nil {
 			payloads = append(payloads, payload)
 		}
+	case GiteaHeader:
+		headerValue := t.WebHook.RequestHeader[GiteaHeader][0]
+		payload, err := s.generatePayloadFromGiteaRequest(ctx, t, headerValue)
+		if err != nil {
+			return nil, err
+		}
+		if payload != nil {
+			payloads = append(payloads, payload)
+		}
 	case GitlabHeader:
 		headerValue := t.WebHook.RequestHeader[GitlabHeader][0]
 		payload, err := s.generatePayloadFromGitlabRequest(ctx, t, headerValue)
@@ -90,6 +105,14 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 
 	hs := make([]sdk.WorkflowNodeRunHookEvent, 0, len(payloads))
 	for _, payload := range payloads {
+		trigger, err := s.filterChangedFiles(ctx, t.UUID, t.Config, payload)
+		if err != nil {
+			return nil, err
+		}
+		if !trigger {
+			continue
+		}
+
 		h := sdk.WorkflowNodeRunHookEvent{
 			WorkflowNodeHookUUID: t.UUID,
 		}
@@ -0,0 +1,96 @@
+package hooks
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// encryptedSecretPrefix marks the webhook secrets encrypted in the cache, secrets stored by previous versions are in clear
+const encryptedSecretPrefix = "cds-encrypted:"
+
+// newSecretKey derives the key that encrypts the webhook secrets in the cache from the token of the service.
+// If the token changes, the tasks are saved again with the new key when they are synchronized at startup.
+func newSecretKey(token string) ([]byte, error) {
+	if token == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing api token to encrypt webhook secrets")
+	}
+	k := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(token), nil, []byte("cds-hooks-webhook-secret")), k); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return k, nil
+}
+
+// encryptTaskSecret returns a copy of the task with its webhook secret encrypted
+func encryptTaskSecret(key []byte, t *sdk.Task) (*sdk.Task, error) {
+	s, has := t.Config[sdk.HookConfigWebHookSecret]
+	if !has || s.Value == "" || strings.HasPrefix(s.Value, encryptedSecretPrefix) {
+		return t, nil
+	}
+
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	s.Value = encryptedSecretPrefix + base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(s.Value), []byte(t.UUID)))
+
+	res := *t
+	res.Config = t.Config.Clone()
+	res.Config[sdk.HookConfigWebHookSecret] = s
+	return &res, nil
+}
+
+// decryptTaskSecret decrypts the webhook secret of a task loaded from the cache
+func decryptTaskSecret(key []byte, t *sdk.Task) error {
+	s, has := t.Config[sdk.HookConfigWebHookSecret]
+	if !has || !strings.HasPrefix(s.Value, encryptedSecretPrefix) {
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s.Value, encryptedSecretPrefix))
+	if err != nil {
+		return sdk.WrapError(err, "invalid webhook secret of task %s", t.UUID)
+	}
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return err
+	}
+	if len(data) < gcm.NonceSize() {
+		return sdk.NewErrorFrom(sdk.ErrInvalidSecretFormat, "invalid webhook secret of task %s", t.UUID)
+	}
+	clear, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(t.UUID))
+	if err != nil {
+		return sdk.WrapError(err, "cannot decrypt webhook secret of task %s", t.UUID)
+	}
+	s.Value = string(clear)
+	t.Config[sdk.HookConfigWebHookSecret] = s
+	return nil
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrSecretKeyFetchFailed, "missing key to encrypt webhook secrets")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,55 @@
+package hooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestTaskSecretEncryption(t *testing.T) {
+	key, err := newSecretKey("my-token")
+	require.NoError(t, err)
+
+	task := &sdk.Task{
+		UUID: "1cbf3792-126b-4111-884f-077bdee9523c",
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigWebHookSecret: {Value: "mysecret"},
+			sdk.HookConfigProject:       {Value: "PROJ"},
+		},
+	}
+
+	encrypted, err := encryptTaskSecret(key, task)
+	require.NoError(t, err)
+	assert.Equal(t, "mysecret", task.Config[sdk.HookConfigWebHookSecret].Value, "the task should not be modified")
+	assert.True(t, strings.HasPrefix(encrypted.Config[sdk.HookConfigWebHookSecret].Value, encryptedSecretPrefix))
+	assert.NotContains(t, encrypted.Config[sdk.HookConfigWebHookSecret].Value, "mysecret")
+	assert.Equal(t, "PROJ", encrypted.Config[sdk.HookConfigProject].Value)
+
+	// The secret is bound to the task
+	other := *encrypted
+	other.UUID = "another-task"
+	other.Config = encrypted.Config.Clone()
+	assert.Error(t, decryptTaskSecret(key, &other))
+
+	// With another token the secret stays encrypted
+	otherKey, err := newSecretKey("another-token")
+	require.NoError(t, err)
+	other.UUID = task.UUID
+	assert.Error(t, decryptTaskSecret(otherKey, &other))
+	assert.True(t, strings.HasPrefix(other.Config[sdk.HookConfigWebHookSecret].Value, encryptedSecretPrefix))
+
+	require.NoError(t, decryptTaskSecret(key, encrypted))
+	assert.Equal(t, "mysecret", encrypted.Config[sdk.HookConfigWebHookSecret].Value)
+
+	// Secrets saved in clear by previous versions are kept
+	legacy := &sdk.Task{Config: sdk.WorkflowNodeHookConfig{sdk.HookConfigWebHookSecret: {Value: "mysecret"}}}
+	require.NoError(t, decryptTaskSecret(key, legacy))
+	assert.Equal(t, "mysecret", legacy.Config[sdk.HookConfigWebHookSecret].Value)
+
+	_, err = newSecretKey("")
+	assert.Error(t, err)
+}
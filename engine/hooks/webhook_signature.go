@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Headers used by the repository managers and by CDS to sign webhooks
+const (
+	GithubSignatureHeader    = "X-Hub-Signature-256"
+	GiteaSignatureHeader     = "X-Gitea-Signature"
+	GitlabTokenHeader        = "X-Gitlab-Token"
+	BitbucketSignatureHeader = "X-Hub-Signature"
+	WebHookSignatureHeader   = "X-Cds-Signature-256"
+)
+
+// Reasons of webhook rejection
+const (
+	rejectReasonMissingSignature = "missing_signature"
+	rejectReasonInvalidSignature = "invalid_signature"
+	rejectReasonUnknownManager   = "unknown_repository_manager"
+)
+
+// verifyWebHookSignature checks the signature of a webhook call against the secret of the task.
+// Tasks without secret, registered before secrets were generated, are not checked.
+// It returns the reason of the rejection with the error.
+func verifyWebHookSignature(t *sdk.Task, header http.Header, body []byte) (string, error) {
+	secret := t.Config[sdk.HookConfigWebHookSecret].Value
+	if secret == "" {
+		return "", nil
+	}
+
+	var signatureHeader, signature string
+	switch t.Type {
+	case TypeRepoManagerWebHook:
+		// Gitea also sends the Github header, it must be checked first
+		switch {
+		case header.Get(GiteaHeader) != "":
+			if signature = header.Get(GiteaSignatureHeader); signature != "" {
+				signatureHeader = GiteaSignatureHeader
+			} else {
+				signatureHeader, signature = GithubSignatureHeader, strings.TrimPrefix(header.Get(GithubSignatureHeader), "sha256=")
+			}
+		case header.Get(GithubHeader) != "":
+			signatureHeader = GithubSignatureHeader
+			signature = strings.TrimPrefix(header.Get(GithubSignatureHeader), "sha256=")
+		case header.Get(GitlabHeader) != "":
+			// Gitlab doesn't sign the payload but sends the secret token
+			token := header.Get(GitlabTokenHeader)
+			if token == "" {
+				return rejectReasonMissingSignature, sdk.NewErrorFrom(sdk.ErrInvalidWebHookSignature, "missing header %s", GitlabTokenHeader)
+			}
+			if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+				return rejectReasonInvalidSignature, sdk.WithStack(sdk.ErrInvalidWebHookSignature)
+			}
+			return "", nil
+		case header.Get(BitbucketHeader) != "":
+			signatureHeader = BitbucketSignatureHeader
+			signature = strings.TrimPrefix(header.Get(BitbucketSignatureHeader), "sha256=")
+		default:
+			return rejectReasonUnknownManager, sdk.NewErrorFrom(sdk.ErrInvalidWebHookSignature, "unknown repository manager")
+		}
+	default:
+		signatureHeader = WebHookSignatureHeader
+		signature = strings.TrimPrefix(header.Get(WebHookSignatureHeader), "sha256=")
+	}
+
+	if signature == "" {
+		return rejectReasonMissingSignature, sdk.NewErrorFrom(sdk.ErrInvalidWebHookSignature, "missing header %s", signatureHeader)
+	}
+	if !checkHMACSignature(secret, body, signature) {
+		return rejectReasonInvalidSignature, sdk.WithStack(sdk.ErrInvalidWebHookSignature)
+	}
+	return "", nil
+}
+
+// checkHMACSignature returns true if the signature is the hex encoded HMAC-SHA256 of the body
+func checkHMACSignature(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint
+	return hmac.Equal(mac.Sum(nil), expected)
+}
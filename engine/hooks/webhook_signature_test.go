@@ -0,0 +1,131 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	secret := "mysecret"
+	signed := func(typ string) *sdk.Task {
+		return &sdk.Task{
+			Type: typ,
+			Config: sdk.WorkflowNodeHookConfig{
+				sdk.HookConfigWebHookSecret: {Value: secret},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		task   *sdk.Task
+		header http.Header
+		reason string
+	}{
+		{
+			name:   "hook without secret",
+			task:   &sdk.Task{Type: TypeWebHook, Config: sdk.WorkflowNodeHookConfig{}},
+			header: http.Header{},
+		},
+		{
+			name:   "webhook",
+			task:   signed(TypeWebHook),
+			header: http.Header{WebHookSignatureHeader: {"sha256=" + sign(secret, body)}},
+		},
+		{
+			name:   "webhook without signature",
+			task:   signed(TypeWebHook),
+			header: http.Header{},
+			reason: rejectReasonMissingSignature,
+		},
+		{
+			name:   "webhook with another secret",
+			task:   signed(TypeWebHook),
+			header: http.Header{WebHookSignatureHeader: {"sha256=" + sign("another", body)}},
+			reason: rejectReasonInvalidSignature,
+		},
+		{
+			name:   "webhook with invalid signature",
+			task:   signed(TypeWebHook),
+			header: http.Header{WebHookSignatureHeader: {"sha256=zz"}},
+			reason: rejectReasonInvalidSignature,
+		},
+		{
+			name:   "github",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{GithubHeader: {"push"}, GithubSignatureHeader: {"sha256=" + sign(secret, body)}},
+		},
+		{
+			name:   "github without signature",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{GithubHeader: {"push"}},
+			reason: rejectReasonMissingSignature,
+		},
+		{
+			name:   "gitea",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{GiteaHeader: {"push"}, GithubHeader: {"push"}, GiteaSignatureHeader: {sign(secret, body)}},
+		},
+		{
+			name:   "gitea with github signature",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{GiteaHeader: {"push"}, GithubHeader: {"push"}, GithubSignatureHeader: {"sha256=" + sign(secret, body)}},
+		},
+		{
+			name:   "gitlab",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{GitlabHeader: {"Push Hook"}, GitlabTokenHeader: {secret}},
+		},
+		{
+			name:   "gitlab with invalid token",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{GitlabHeader: {"Push Hook"}, GitlabTokenHeader: {"another"}},
+			reason: rejectReasonInvalidSignature,
+		},
+		{
+			name:   "bitbucket",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{BitbucketHeader: {"repo:refs_changed"}, BitbucketSignatureHeader: {"sha256=" + sign(secret, body)}},
+		},
+		{
+			name:   "bitbucket with invalid signature",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{BitbucketHeader: {"repo:push"}, BitbucketSignatureHeader: {"sha256=" + sign(secret, []byte("forged"))}},
+			reason: rejectReasonInvalidSignature,
+		},
+		{
+			name:   "unknown repository manager",
+			task:   signed(TypeRepoManagerWebHook),
+			header: http.Header{GithubSignatureHeader: {"sha256=" + sign(secret, body)}},
+			reason: rejectReasonUnknownManager,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := verifyWebHookSignature(tt.task, tt.header, body)
+			assert.Equal(t, tt.reason, reason)
+			if tt.reason == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.True(t, sdk.ErrorIs(err, sdk.ErrInvalidWebHookSignature))
+			}
+		})
+	}
+}
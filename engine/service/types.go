@@ -39,12 +39,13 @@ type HatcheryCommonConfiguration struct {
 		MaxHeartbeatFailures int    `toml:"maxHeartbeatFailures" default:"10" comment:"Maximum allowed consecutives failures on heatbeat routine" json:"maxHeartbeatFailures"`
 	} `toml:"api" json:"api"`
 	Provision struct {
-		Disabled                  bool `toml:"disabled" default:"false" comment:"Disabled provisioning. Format:true or false" json:"disabled"`
-		RatioService              *int `toml:"ratioService" default:"50" commented:"true" comment:"Percent reserved for spawning worker with service requirement" json:"ratioService,omitempty" mapstructure:"ratioService"`
-		MaxWorker                 int  `toml:"maxWorker" default:"10" comment:"Maximum allowed simultaneous workers" json:"maxWorker"`
-		MaxConcurrentProvisioning int  `toml:"maxConcurrentProvisioning" default:"10" comment:"Maximum allowed simultaneous workers provisioning" json:"maxConcurrentProvisioning"`
-		MaxConcurrentRegistering  int  `toml:"maxConcurrentRegistering" default:"2" comment:"Maximum allowed simultaneous workers registering. -1 to disable registering on this hatchery" json:"maxConcurrentRegistering"`
-		RegisterFrequency         int  `toml:"registerFrequency" default:"60" comment:"Check if some worker model have to be registered each n Seconds" json:"registerFrequency"`
+		Disabled                  bool                             `toml:"disabled" default:"false" comment:"Disabled provisioning. Format:true or false" json:"disabled"`
+		RatioService              *int                             `toml:"ratioService" default:"50" commented:"true" comment:"Percent reserved for spawning worker with service requirement" json:"ratioService,omitempty" mapstructure:"ratioService"`
+		MaxWorker                 int                              `toml:"maxWorker" default:"10" comment:"Maximum allowed simultaneous workers" json:"maxWorker"`
+		MaxConcurrentProvisioning int                              `toml:"maxConcurrentProvisioning" default:"10" comment:"Maximum allowed simultaneous workers provisioning" json:"maxConcurrentProvisioning"`
+		MaxConcurrentRegistering  int                              `toml:"maxConcurrentRegistering" default:"2" comment:"Maximum allowed simultaneous workers registering. -1 to disable registering on this hatchery" json:"maxConcurrentRegistering"`
+		RegisterFrequency         int                              `toml:"registerFrequency" default:"60" comment:"Check if some worker model have to be registered each n Seconds" json:"registerFrequency"`
+		Autoscaling               HatcheryAutoscalingConfiguration `toml:"autoscaling" comment:"Warm pools and autoscaling policies by worker model" json:"autoscaling" mapstructure:"autoscaling"`
 		WorkerLogsOptions         struct {
 			Graylog struct {
 				Host       string `toml:"host" comment:"Example: thot.ovh.com" json:"host"`
@@ -63,6 +64,31 @@ type HatcheryCommonConfiguration struct {
 	} `toml:"logOptions" comment:"Hatchery Log Configuration" json:"logOptions"`
 }
 
+// HatcheryAutoscalingConfiguration contains the warm pools of a hatchery.
+type HatcheryAutoscalingConfiguration struct {
+	Frequency int                                     `toml:"frequency" default:"30" comment:"Check warm pools each n Seconds" json:"frequency"`
+	Models    []HatcheryAutoscalingModelConfiguration `toml:"models" comment:"Autoscaling policy for a worker model" json:"models,omitempty" mapstructure:"models"`
+}
+
+// HatcheryAutoscalingModelConfiguration is the autoscaling policy of a worker model.
+// Warm workers are registered without job and take the next job they can run.
+type HatcheryAutoscalingModelConfiguration struct {
+	Model         string                                     `toml:"model" comment:"Worker model path. Example: shared.infra/go-official" json:"model"`
+	MinIdle       int                                        `toml:"minIdle" comment:"Number of idle workers kept ready" json:"minIdle" mapstructure:"minIdle"`
+	MaxIdle       int                                        `toml:"maxIdle" comment:"Maximum number of idle workers, 0 means no limit" json:"maxIdle" mapstructure:"maxIdle"`
+	IdleTimeout   int                                        `toml:"idleTimeout" comment:"Idle workers above the pool size are stopped after n Seconds, default 600" json:"idleTimeout" mapstructure:"idleTimeout"`
+	JobsPerWorker int                                        `toml:"jobsPerWorker" comment:"Start an idle worker for each n waiting jobs of this model, 0 disables queue depth scaling" json:"jobsPerWorker" mapstructure:"jobsPerWorker"`
+	Schedules     []HatcheryAutoscalingScheduleConfiguration `toml:"schedules" comment:"Override the pool size during a time range" json:"schedules,omitempty" mapstructure:"schedules"`
+}
+
+// HatcheryAutoscalingScheduleConfiguration overrides the pool size of a worker model during a time range.
+type HatcheryAutoscalingScheduleConfiguration struct {
+	Days    []string `toml:"days" comment:"Days of the week, example: [\"monday\", \"tuesday\"], every day if empty" json:"days,omitempty"`
+	Start   string   `toml:"start" comment:"Start time, example: 08:00" json:"start"`
+	End     string   `toml:"end" comment:"End time, example: 19:00" json:"end"`
+	MinIdle int      `toml:"minIdle" comment:"Number of idle workers kept ready during this time range" json:"minIdle" mapstructure:"minIdle"`
+}
+
 // Common is the struct representing a CDS µService
 type Common struct {
 	Client               cdsclient.Interface
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_job_log_chunk" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_node_run_job_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    step_order BIGINT NOT NULL,
+    chunk_index BIGINT NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    start TIMESTAMP WITH TIME ZONE,
+    last_modified TIMESTAMP WITH TIME ZONE,
+    done TIMESTAMP WITH TIME ZONE
+);
+
+SELECT create_unique_index('workflow_node_run_job_log_chunk', 'IDX_WORKFLOW_NODE_RUN_JOB_LOG_CHUNK_UNIQ', 'workflow_node_run_job_id,step_order,chunk_index');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_JOB_LOG_CHUNK_WORKFLOW_NODE_RUN', 'workflow_node_run_job_log_chunk', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_job_log_chunk";
@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_lock" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    lock_key VARCHAR(256) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    workflow_id BIGINT NOT NULL,
+    workflow_name VARCHAR(256) NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    num BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    workflow_node_name VARCHAR(256) NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_unique_index('workflow_node_run_lock', 'IDX_WORKFLOW_NODE_RUN_LOCK_NODE_RUN_UNIQ', 'workflow_node_run_id');
+SELECT create_index('workflow_node_run_lock', 'IDX_WORKFLOW_NODE_RUN_LOCK_KEY', 'lock_key,status');
+-- only one node run can hold a lock
+CREATE UNIQUE INDEX IF NOT EXISTS "IDX_WORKFLOW_NODE_RUN_LOCK_HOLDER_UNIQ" ON "workflow_node_run_lock" (lock_key) WHERE status = 'holding';
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_LOCK_WORKFLOW_NODE_RUN', 'workflow_node_run_lock', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_LOCK_PROJECT', 'workflow_node_run_lock', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_lock";
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE action ADD COLUMN timeout VARCHAR(32) DEFAULT '';
+ALTER TABLE action ADD COLUMN retry JSONB;
+ALTER TABLE action_edge ADD COLUMN timeout VARCHAR(32) DEFAULT '';
+ALTER TABLE action_edge ADD COLUMN retry JSONB;
+ALTER TABLE workflow_node_run_job ADD COLUMN attempt int DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE action DROP COLUMN timeout;
+ALTER TABLE action DROP COLUMN retry;
+ALTER TABLE action_edge DROP COLUMN timeout;
+ALTER TABLE action_edge DROP COLUMN retry;
+ALTER TABLE workflow_node_run_job DROP COLUMN attempt;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE auth_consumer ADD COLUMN restrictions JSONB NOT NULL DEFAULT '{}'::JSONB;
+ALTER TABLE auth_consumer ADD COLUMN expire_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE auth_consumer ADD COLUMN last_used TIMESTAMP WITH TIME ZONE;
+
+-- +migrate Down
+ALTER TABLE auth_consumer DROP COLUMN restrictions;
+ALTER TABLE auth_consumer DROP COLUMN expire_at;
+ALTER TABLE auth_consumer DROP COLUMN last_used;
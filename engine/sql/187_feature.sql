@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "feature" (
+  id BIGSERIAL PRIMARY KEY,
+  name VARCHAR(256) NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  rules JSONB NOT NULL DEFAULT '{}'::JSONB,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_unique_index('feature', 'IDX_FEATURE_NAME', 'name');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "feature";
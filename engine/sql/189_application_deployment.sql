@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "application_deployment" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    environment_name VARCHAR(256) NOT NULL DEFAULT '',
+    integration_name VARCHAR(256) NOT NULL DEFAULT '',
+    workflow_name VARCHAR(256) NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    num BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    deployment_id VARCHAR(256) NOT NULL DEFAULT '',
+    version VARCHAR(256) NOT NULL DEFAULT '',
+    strategy VARCHAR(50) NOT NULL DEFAULT '',
+    rollback_from VARCHAR(256) NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_index('application_deployment', 'IDX_APPLICATION_DEPLOYMENT_ENVIRONMENT', 'application_id,environment_name');
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_DEPLOYMENT_APPLICATION', 'application_deployment', 'application', 'application_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_DEPLOYMENT_PROJECT', 'application_deployment', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "application_deployment";
@@ -0,0 +1,23 @@
+-- +migrate Up
+ALTER TABLE "workflow_template" ADD COLUMN IF NOT EXISTS repository JSONB;
+ALTER TABLE "workflow_template" ADD COLUMN IF NOT EXISTS semver VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "workflow_template_instance" ADD COLUMN IF NOT EXISTS workflow_template_semver VARCHAR(256) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS "workflow_template_version" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_template_id BIGINT NOT NULL,
+    version VARCHAR(256) NOT NULL,
+    tag VARCHAR(256) NOT NULL,
+    template JSONB,
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_unique_index('workflow_template_version', 'IDX_WORKFLOW_TEMPLATE_VERSION_UNIQ', 'workflow_template_id,version');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEMPLATE_VERSION_TEMPLATE', 'workflow_template_version', 'workflow_template', 'workflow_template_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_template_version";
+ALTER TABLE "workflow_template_instance" DROP COLUMN IF EXISTS workflow_template_semver;
+ALTER TABLE "workflow_template" DROP COLUMN IF EXISTS semver;
+ALTER TABLE "workflow_template" DROP COLUMN IF EXISTS repository;
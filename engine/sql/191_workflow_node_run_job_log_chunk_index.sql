@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_job_log_chunk_index" (
+    workflow_node_run_job_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    step_order BIGINT NOT NULL,
+    last_index BIGINT NOT NULL
+);
+
+SELECT create_unique_index('workflow_node_run_job_log_chunk_index', 'IDX_WORKFLOW_NODE_RUN_JOB_LOG_CHUNK_INDEX_UNIQ', 'workflow_node_run_job_id,step_order');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_JOB_LOG_CHUNK_INDEX_WORKFLOW_NODE_RUN', 'workflow_node_run_job_log_chunk_index', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_job_log_chunk_index";
@@ -164,3 +164,35 @@ func (client *bitbucketcloudClient) CommitsBetweenRefs(ctx context.Context, repo
 
 	return commitsResult, nil
 }
+
+// ChangedFilesBetweenRefs returns the files changed between base and head, renamed files are listed with their previous path
+func (client *bitbucketcloudClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/repositories/%s/diffstat/%s..%s", repo, head, base)
+	params := url.Values{}
+	nextPage := 1
+	for {
+		if nextPage != 1 {
+			params.Set("page", fmt.Sprintf("%d", nextPage))
+		}
+
+		var response DiffStats
+		if err := client.do(ctx, "GET", "core", path, params, nil, &response); err != nil {
+			return nil, sdk.WrapError(err, "Unable to get diffstat")
+		}
+		for _, d := range response.Values {
+			if d.New != nil {
+				files = append(files, d.New.Path)
+			}
+			if d.Old != nil && (d.New == nil || d.Old.Path != d.New.Path) {
+				files = append(files, d.Old.Path)
+			}
+		}
+
+		if response.Next == "" {
+			break
+		}
+		nextPage++
+	}
+	return files, nil
+}
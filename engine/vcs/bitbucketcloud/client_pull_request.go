@@ -101,6 +101,16 @@ func (client *bitbucketcloudClient) PullRequestComment(ctx context.Context, repo
 	return nil
 }
 
+// PullRequestComments is not implemented
+func (client *bitbucketcloudClient) PullRequestComments(ctx context.Context, repo string, id int) ([]sdk.VCSPullRequestComment, error) {
+	return nil, sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// PullRequestCommentEdit is not implemented
+func (client *bitbucketcloudClient) PullRequestCommentEdit(ctx context.Context, repo string, id int, comment sdk.VCSPullRequestComment) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
 func (client *bitbucketcloudClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	path := fmt.Sprintf("/repos/%s/pulls", repo)
 	payload := map[string]string{
@@ -19,6 +19,7 @@ type WebhookCreate struct {
 	URL         string   `json:"url"`
 	Active      bool     `json:"active"`
 	Events      []string `json:"events"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 type Webhook struct {
@@ -47,6 +48,7 @@ type Webhook struct {
 	Type   string   `json:"type"`
 	Events []string `json:"events"`
 	UUID   string   `json:"uuid"`
+	Secret string   `json:"secret,omitempty"`
 }
 
 type Webhooks struct {
@@ -354,6 +356,25 @@ type Commits struct {
 	Previous string   `json:"previous,omitempty"`
 }
 
+type DiffStats struct {
+	Pagelen  int        `json:"pagelen"`
+	Page     int        `json:"page"`
+	Size     int64      `json:"size"`
+	Values   []DiffStat `json:"values"`
+	Next     string     `json:"next"`
+	Previous string     `json:"previous,omitempty"`
+}
+
+type DiffStat struct {
+	Status string        `json:"status"`
+	Old    *DiffStatFile `json:"old"`
+	New    *DiffStatFile `json:"new"`
+}
+
+type DiffStatFile struct {
+	Path string `json:"path"`
+}
+
 type Commit struct {
 	Rendered struct {
 		Message struct {
@@ -32,7 +32,7 @@ func getNewConsumer(t *testing.T) sdk.VCSServer {
 		t.SkipNow()
 	}
 
-	cache, err := cache.New(redisHost, redisPassword, 30)
+	cache, err := cache.New(context.TODO(), cache.ModeRedis, redisHost, redisPassword, 30)
 	if err != nil {
 		t.Fatalf("Unable to init cache (%s): %v", redisHost, err)
 	}
@@ -58,7 +58,7 @@ func newAuthorizedClient(t *testing.T) (string, string) {
 		t.SkipNow()
 	}
 
-	cache, err := cache.New(redisHost, redisPassword, 30)
+	cache, err := cache.New(context.TODO(), cache.ModeRedis, redisHost, redisPassword, 30)
 	if err != nil {
 		t.Fatalf("Unable to init cache (%s): %v", redisHost, err)
 	}
@@ -91,7 +91,7 @@ func getAuthorizedClient(t *testing.T) sdk.VCSAuthorizedClient {
 		t.SkipNow()
 	}
 
-	cache, err := cache.New(redisHost, redisPassword, 30)
+	cache, err := cache.New(context.TODO(), cache.ModeRedis, redisHost, redisPassword, 30)
 	if err != nil {
 		t.Fatalf("Unable to init cache (%s): %v", redisHost, err)
 	}
@@ -182,3 +182,52 @@ func (b *bitbucketClient) CommitsBetweenRefs(ctx context.Context, repo, base, he
 	}
 	return commits, nil
 }
+
+// ChangedFilesBetweenRefs returns the files changed between base and head, moved files are listed with their previous path
+func (b *bitbucketClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	var files []string
+	var changedFilesKey = cache.Key("vcs", "bitbucket", b.consumer.URL, repo, "compare/changes", "from@"+base, "to@"+head)
+
+	find, err := b.consumer.cache.Get(changedFilesKey, &files)
+	if err != nil {
+		log.Error(ctx, "cannot get from cache %s: %v", changedFilesKey, err)
+	}
+	if find {
+		return files, nil
+	}
+
+	response := ChangesResponse{}
+	path := fmt.Sprintf("/projects/%s/repos/%s/compare/changes", project, slug)
+	params := url.Values{}
+	params.Add("from", head)
+	params.Add("to", base)
+	for {
+		if response.NextPageStart != 0 {
+			params.Set("start", fmt.Sprintf("%d", response.NextPageStart))
+		}
+
+		if err := b.do(ctx, "GET", "core", path, params, nil, &response, nil); err != nil {
+			return nil, sdk.WrapError(err, "Unable to get changes %s", path)
+		}
+
+		for _, c := range response.Values {
+			files = append(files, c.Path.ToString)
+			if c.SrcPath != nil && c.SrcPath.ToString != "" {
+				files = append(files, c.SrcPath.ToString)
+			}
+		}
+		if response.IsLastPage {
+			break
+		}
+	}
+	//3 hours
+	if err := b.consumer.cache.SetWithTTL(changedFilesKey, files, 3*60*60); err != nil {
+		log.Error(ctx, "cannot SetWithTTL: %s: %v", changedFilesKey, err)
+	}
+	return files, nil
+}
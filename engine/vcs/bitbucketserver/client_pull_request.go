@@ -93,6 +93,92 @@ func (b *bitbucketClient) PullRequestComment(ctx context.Context, repo string, p
 	return b.do(ctx, "POST", "core", path, nil, values, nil, &options{asUser: true})
 }
 
+// PullRequestComments returns the comments posted on a pull request, read from its activities
+func (b *bitbucketClient) PullRequestComments(ctx context.Context, repo string, prID int) ([]sdk.VCSPullRequestComment, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/activities", project, slug, prID)
+	params := url.Values{}
+
+	author, err := b.commentAuthor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []sdk.VCSPullRequestComment
+	nextPage := 0
+	for {
+		if nextPage != 0 {
+			params.Set("start", fmt.Sprintf("%d", nextPage))
+		}
+
+		var response PullRequestActivitiesResponse
+		if err := b.do(ctx, "GET", "core", path, params, nil, &response, nil); err != nil {
+			return nil, sdk.WrapError(err, "Unable to get pullrequest activities")
+		}
+
+		for _, a := range response.Values {
+			if a.Action != "COMMENTED" || a.Comment == nil {
+				continue
+			}
+			comments = append(comments, sdk.VCSPullRequestComment{
+				ID:      a.Comment.ID,
+				Version: a.Comment.Version,
+				Body:    a.Comment.Text,
+				User: sdk.VCSAuthor{
+					Name:        a.Comment.Author.Name,
+					DisplayName: a.Comment.Author.DisplayName,
+					Email:       a.Comment.Author.EmailAddress,
+				},
+				PostedByCDS: a.Comment.Author.Name == author,
+			})
+		}
+
+		if response.IsLastPage {
+			break
+		} else {
+			nextPage = response.NextPageStart
+		}
+	}
+
+	return comments, nil
+}
+
+// commentAuthor returns the name of the account used to comment pull requests: the configured user, or the OAuth user
+func (b *bitbucketClient) commentAuthor(ctx context.Context) (string, error) {
+	if b.token != "" {
+		return b.username, nil
+	}
+	var u sdk.BitbucketServerActor
+	if err := b.do(ctx, "GET", "core", "username", nil, nil, &u, nil); err != nil {
+		return "", sdk.WrapError(err, "Unable to get current user")
+	}
+	return u.Name, nil
+}
+
+// PullRequestCommentEdit updates the text of an existing comment on a pull request
+func (b *bitbucketClient) PullRequestCommentEdit(ctx context.Context, repo string, prID int, comment sdk.VCSPullRequestComment) error {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	payload := map[string]interface{}{
+		"text":    comment.Body,
+		"version": comment.Version,
+	}
+	values, err := json.Marshal(payload)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/comments/%d", project, slug, prID, comment.ID)
+
+	return b.do(ctx, "PUT", "core", path, nil, values, nil, &options{asUser: true})
+}
+
 func (b *bitbucketClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	project, slug, err := getRepo(repo)
 	if err != nil {
@@ -71,6 +71,23 @@ type CommitsResponse struct {
 	IsLastPage    bool     `json:"isLastPage"`
 }
 
+type ChangesResponse struct {
+	Values        []Change `json:"values"`
+	Size          int      `json:"size"`
+	NextPageStart int      `json:"nextPageStart"`
+	IsLastPage    bool     `json:"isLastPage"`
+}
+
+type Change struct {
+	Type    string      `json:"type"`
+	Path    ChangePath  `json:"path"`
+	SrcPath *ChangePath `json:"srcPath,omitempty"`
+}
+
+type ChangePath struct {
+	ToString string `json:"toString"`
+}
+
 type Commit struct {
 	Hash      string `json:"id"`
 	Author    Author `json:"author"`
@@ -248,3 +265,17 @@ type PullRequestResponse struct {
 	NextPageStart int                              `json:"nextPageStart"`
 	IsLastPage    bool                             `json:"isLastPage"`
 }
+
+// PullRequestActivity is an entry of the activity stream of a pull request
+type PullRequestActivity struct {
+	ID      int64                       `json:"id"`
+	Action  string                      `json:"action"`
+	Comment *sdk.BitbucketServerComment `json:"comment,omitempty"`
+}
+
+type PullRequestActivitiesResponse struct {
+	Values        []PullRequestActivity `json:"values"`
+	Size          int                   `json:"size"`
+	NextPageStart int                   `json:"nextPageStart"`
+	IsLastPage    bool                  `json:"isLastPage"`
+}
@@ -2,6 +2,10 @@ package gerrit
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+
+	g "github.com/andygrunwald/go-gerrit"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -20,3 +24,33 @@ func (c *gerritClient) Commit(ctx context.Context, repo, hash string) (sdk.VCSCo
 func (c *gerritClient) CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]sdk.VCSCommit, error) {
 	return nil, nil
 }
+
+// ChangedFilesBetweenRefs returns the files changed by the head commit compared to its parent.
+// Gerrit only lists the files of a commit, so the changed files are unknown if base is not the parent of head.
+func (c *gerritClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	commit, _, err := c.client.Projects.GetCommit(repo, url.QueryEscape(head))
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to get commit %s on %s", head, repo)
+	}
+	if len(commit.Parents) != 1 || commit.Parents[0].Commit != base {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotImplemented, "cannot list files changed between %s and %s on gerrit, %s is not the parent of %s", base, head, base, head)
+	}
+
+	var infos map[string]g.FileInfo
+	u := fmt.Sprintf("projects/%s/commits/%s/files/", url.QueryEscape(repo), url.QueryEscape(head))
+	if _, err := c.client.Call("GET", u, nil, &infos); err != nil {
+		return nil, sdk.WrapError(err, "unable to list files of commit %s on %s", head, repo)
+	}
+	files := make([]string, 0, len(infos))
+	for f, info := range infos {
+		// Gerrit lists the commit message as a magic file
+		if f == "/COMMIT_MSG" || f == "/MERGE_LIST" {
+			continue
+		}
+		files = append(files, f)
+		if info.OldPath != "" {
+			files = append(files, info.OldPath)
+		}
+	}
+	return files, nil
+}
@@ -19,6 +19,16 @@ func (c *gerritClient) PullRequestComment(context.Context, string, int, string)
 	return nil
 }
 
+// PullRequestComments is not implemented
+func (c *gerritClient) PullRequestComments(context.Context, string, int) ([]sdk.VCSPullRequestComment, error) {
+	return nil, sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// PullRequestCommentEdit is not implemented
+func (c *gerritClient) PullRequestCommentEdit(context.Context, string, int, sdk.VCSPullRequestComment) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
 // PullRequestCreate create a new pullrequest
 func (c *gerritClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	return sdk.VCSPullRequest{}, nil
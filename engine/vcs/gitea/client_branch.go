@@ -0,0 +1,53 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Branches returns list of branches for a repo
+func (client *giteaClient) Branches(ctx context.Context, fullname string) ([]sdk.VCSBranch, error) {
+	repo, err := client.repoByFullname(ctx, fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []Branch
+	if err := client.getAll(ctx, repoPath(fullname, "branches"), nil, &branches); err != nil {
+		return nil, sdk.WrapError(err, "cannot list branches of %s", fullname)
+	}
+
+	res := make([]sdk.VCSBranch, 0, len(branches))
+	for _, b := range branches {
+		res = append(res, toVCSBranch(b, repo.DefaultBranch))
+	}
+	return res, nil
+}
+
+// Branch returns only detail of a branch
+func (client *giteaClient) Branch(ctx context.Context, fullname, theBranch string) (*sdk.VCSBranch, error) {
+	repo, err := client.repoByFullname(ctx, fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var branch Branch
+	if _, err := client.do(ctx, http.MethodGet, repoPath(fullname, "branches", url.PathEscape(theBranch)), nil, nil, &branch, nil); err != nil {
+		return nil, sdk.WrapError(err, "cannot get branch %s of %s", theBranch, fullname)
+	}
+
+	b := toVCSBranch(branch, repo.DefaultBranch)
+	return &b, nil
+}
+
+func toVCSBranch(b Branch, defaultBranch string) sdk.VCSBranch {
+	return sdk.VCSBranch{
+		ID:           "refs/heads/" + b.Name,
+		DisplayID:    b.Name,
+		LatestCommit: b.Commit.ID,
+		Default:      b.Name == defaultBranch,
+	}
+}
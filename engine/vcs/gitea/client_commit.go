@@ -0,0 +1,108 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Commits returns the commits of a branch from the commit 'until' (or the head of the branch) to the commit 'since' excluded.
+// Without 'since' commit, only the head commit is returned
+func (client *giteaClient) Commits(ctx context.Context, repo, theBranch, since, until string) ([]sdk.VCSCommit, error) {
+	ref := until
+	if ref == "" {
+		ref = theBranch
+	}
+	return client.commitsUntil(ctx, repo, ref, since)
+}
+
+// CommitsBetweenRefs returns the commits reachable from head that are not reachable from base
+func (client *giteaClient) CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]sdk.VCSCommit, error) {
+	baseCommit, err := client.Commit(ctx, repo, base)
+	if err != nil {
+		return nil, err
+	}
+	if baseCommit.Hash == "" {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return client.commitsUntil(ctx, repo, head, baseCommit.Hash)
+}
+
+// commitsUntil walks the history from ref until the given commit, which is excluded
+func (client *giteaClient) commitsUntil(ctx context.Context, repo, ref, stop string) ([]sdk.VCSCommit, error) {
+	var res []sdk.VCSCommit
+	params := url.Values{}
+	params.Set("sha", ref)
+	params.Set("limit", strconv.Itoa(pageLimit))
+	for page := 1; page <= maxPageCount; page++ {
+		params.Set("page", strconv.Itoa(page))
+		var commits []Commit
+		if _, err := client.do(ctx, http.MethodGet, repoPath(repo, "commits"), params, nil, &commits, nil); err != nil {
+			return nil, sdk.WrapError(err, "cannot list commits of %s from %s", repo, ref)
+		}
+		for _, c := range commits {
+			if stop == "" {
+				return []sdk.VCSCommit{toVCSCommit(c)}, nil
+			}
+			if c.SHA == stop {
+				return res, nil
+			}
+			res = append(res, toVCSCommit(c))
+		}
+		if len(commits) < pageLimit {
+			break
+		}
+	}
+	return res, nil
+}
+
+// Commit Get a single commit
+func (client *giteaClient) Commit(ctx context.Context, repo, hash string) (sdk.VCSCommit, error) {
+	var c Commit
+	if _, err := client.do(ctx, http.MethodGet, repoPath(repo, "git", "commits", url.PathEscape(hash)), nil, nil, &c, nil); err != nil {
+		return sdk.VCSCommit{}, sdk.WrapError(err, "cannot get commit %s of %s", hash, repo)
+	}
+	return toVCSCommit(c), nil
+}
+
+// ChangedFilesBetweenRefs returns the files changed by the commits reachable from head that are not reachable from base
+func (client *giteaClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	var compare Compare
+	if _, err := client.do(ctx, http.MethodGet, repoPath(repo, "compare", url.PathEscape(base)+"..."+url.PathEscape(head)), nil, nil, &compare, nil); err != nil {
+		return nil, sdk.WrapError(err, "cannot compare %s and %s on %s", base, head, repo)
+	}
+	var files []string
+	for _, c := range compare.Commits {
+		for _, f := range c.Files {
+			if !sdk.IsInArray(f.Filename, files) {
+				files = append(files, f.Filename)
+			}
+		}
+	}
+	return files, nil
+}
+
+func toVCSCommit(c Commit) sdk.VCSCommit {
+	commit := sdk.VCSCommit{
+		Hash:    c.SHA,
+		Message: c.Commit.Message,
+		URL:     c.HTMLURL,
+		Author: sdk.VCSAuthor{
+			Name:        c.Commit.Author.Name,
+			DisplayName: c.Commit.Author.Name,
+			Email:       c.Commit.Author.Email,
+		},
+	}
+	if c.Author != nil {
+		commit.Author.Name = c.Author.Login
+		commit.Author.Avatar = c.Author.AvatarURL
+	}
+	if d, err := time.Parse(time.RFC3339, c.Commit.Author.Date); err == nil {
+		commit.Timestamp = d.Unix() * 1000
+	}
+	return commit
+}
@@ -0,0 +1,35 @@
+package gitea
+
+import (
+	"context"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Gitea does not expose repository events, only webhooks are supported
+
+// GetEvents is not implemented on Gitea
+func (client *giteaClient) GetEvents(ctx context.Context, fullname string, dateRef time.Time) ([]interface{}, time.Duration, error) {
+	return nil, 0, sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// PushEvents is not implemented on Gitea
+func (client *giteaClient) PushEvents(ctx context.Context, fullname string, iEvents []interface{}) ([]sdk.VCSPushEvent, error) {
+	return nil, sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// CreateEvents is not implemented on Gitea
+func (client *giteaClient) CreateEvents(ctx context.Context, fullname string, iEvents []interface{}) ([]sdk.VCSCreateEvent, error) {
+	return nil, sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// DeleteEvents is not implemented on Gitea
+func (client *giteaClient) DeleteEvents(ctx context.Context, fullname string, iEvents []interface{}) ([]sdk.VCSDeleteEvent, error) {
+	return nil, sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// PullRequestEvents is not implemented on Gitea
+func (client *giteaClient) PullRequestEvents(ctx context.Context, fullname string, iEvents []interface{}) ([]sdk.VCSPullRequestEvent, error) {
+	return nil, sdk.WithStack(sdk.ErrNotImplemented)
+}
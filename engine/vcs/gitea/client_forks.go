@@ -0,0 +1,21 @@
+package gitea
+
+import (
+	"context"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ListForks returns the forks of a repository
+func (client *giteaClient) ListForks(ctx context.Context, fullname string) ([]sdk.VCSRepo, error) {
+	var repos []Repository
+	if err := client.getAll(ctx, repoPath(fullname, "forks"), nil, &repos); err != nil {
+		return nil, sdk.WrapError(err, "cannot list forks of %s", fullname)
+	}
+
+	res := make([]sdk.VCSRepo, 0, len(repos))
+	for _, r := range repos {
+		res = append(res, toVCSRepo(r))
+	}
+	return res, nil
+}
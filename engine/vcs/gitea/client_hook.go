@@ -0,0 +1,108 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// CreateHook creates a webhook on a repository, by default on push events
+func (client *giteaClient) CreateHook(ctx context.Context, fullname string, hook *sdk.VCSHook) error {
+	client.prepareHook(hook)
+
+	opts := CreateHookOption{
+		Type:   "gitea",
+		Active: true,
+		Events: hook.Events,
+		Config: map[string]string{
+			"url":          hook.URL,
+			"content_type": "json",
+		},
+	}
+	if hook.Secret != "" {
+		opts.Config["secret"] = hook.Secret
+	}
+	var created Hook
+	if _, err := client.do(ctx, http.MethodPost, repoPath(fullname, "hooks"), nil, opts, &created, nil); err != nil {
+		return sdk.WrapError(err, "cannot create webhook on %s", fullname)
+	}
+	hook.ID = strconv.FormatInt(created.ID, 10)
+	return nil
+}
+
+// UpdateHook updates the url and the events of a webhook
+func (client *giteaClient) UpdateHook(ctx context.Context, fullname string, hook *sdk.VCSHook) error {
+	client.prepareHook(hook)
+
+	active := true
+	opts := EditHookOption{
+		Active: &active,
+		Events: hook.Events,
+		Config: map[string]string{
+			"url":          hook.URL,
+			"content_type": "json",
+		},
+	}
+	if hook.Secret != "" {
+		opts.Config["secret"] = hook.Secret
+	}
+	if _, err := client.do(ctx, http.MethodPatch, repoPath(fullname, "hooks", hook.ID), nil, opts, nil, nil); err != nil {
+		return sdk.WrapError(err, "cannot update webhook %s on %s", hook.ID, fullname)
+	}
+	return nil
+}
+
+// GetHook returns the webhook of a repository with the given url
+func (client *giteaClient) GetHook(ctx context.Context, fullname, webhookURL string) (sdk.VCSHook, error) {
+	var hooks []Hook
+	if err := client.getAll(ctx, repoPath(fullname, "hooks"), nil, &hooks); err != nil {
+		return sdk.VCSHook{}, sdk.WrapError(err, "cannot list webhooks of %s", fullname)
+	}
+
+	for _, h := range hooks {
+		if h.Config["url"] == webhookURL {
+			return sdk.VCSHook{
+				ID:          strconv.FormatInt(h.ID, 10),
+				Name:        h.Type,
+				Events:      h.Events,
+				URL:         h.Config["url"],
+				ContentType: h.Config["content_type"],
+				Disable:     !h.Active,
+			}, nil
+		}
+	}
+
+	return sdk.VCSHook{}, sdk.WithStack(sdk.ErrNotFound)
+}
+
+// DeleteHook deletes a webhook of a repository
+func (client *giteaClient) DeleteHook(ctx context.Context, fullname string, hook sdk.VCSHook) error {
+	if hook.ID == "" {
+		h, err := client.GetHook(ctx, fullname, hook.URL)
+		if err != nil {
+			return err
+		}
+		hook.ID = h.ID
+	}
+	if _, err := client.do(ctx, http.MethodDelete, repoPath(fullname, "hooks", hook.ID), nil, nil, nil, nil); err != nil {
+		return sdk.WrapError(err, "cannot delete webhook %s on %s", hook.ID, fullname)
+	}
+	return nil
+}
+
+// prepareHook sets the default events of a hook and rewrites its url if a proxy is configured
+func (client *giteaClient) prepareHook(hook *sdk.VCSHook) {
+	if client.proxyURL != "" {
+		lastIndexSlash := strings.LastIndex(hook.URL, "/")
+		if client.proxyURL[len(client.proxyURL)-1] == '/' {
+			lastIndexSlash++
+		}
+		hook.URL = client.proxyURL + hook.URL[lastIndexSlash:]
+	}
+	if len(hook.Events) == 0 {
+		hook.Events = []string{"push"}
+	}
+}
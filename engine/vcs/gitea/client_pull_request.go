@@ -0,0 +1,153 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// PullRequest returns a pull request of a repository
+func (client *giteaClient) PullRequest(ctx context.Context, fullname string, id int) (sdk.VCSPullRequest, error) {
+	var pr PullRequest
+	if _, err := client.do(ctx, http.MethodGet, repoPath(fullname, "pulls", strconv.Itoa(id)), nil, nil, &pr, nil); err != nil {
+		return sdk.VCSPullRequest{}, sdk.WrapError(err, "cannot get pull request %d of %s", id, fullname)
+	}
+	return pr.ToVCSPullRequest(), nil
+}
+
+// PullRequests returns the opened pull requests of a repository
+func (client *giteaClient) PullRequests(ctx context.Context, fullname string) ([]sdk.VCSPullRequest, error) {
+	params := url.Values{}
+	params.Set("state", "open")
+	var prs []PullRequest
+	if err := client.getAll(ctx, repoPath(fullname, "pulls"), params, &prs); err != nil {
+		return nil, sdk.WrapError(err, "cannot list pull requests of %s", fullname)
+	}
+
+	res := make([]sdk.VCSPullRequest, 0, len(prs))
+	for _, pr := range prs {
+		res = append(res, pr.ToVCSPullRequest())
+	}
+	return res, nil
+}
+
+// PullRequestComment push a new comment on a pull request
+func (client *giteaClient) PullRequestComment(ctx context.Context, fullname string, id int, text string) error {
+	if client.DisableStatus {
+		log.Warning(ctx, "gitea.PullRequestComment>  ⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	path := repoPath(fullname, "issues", strconv.Itoa(id), "comments")
+	if _, err := client.do(ctx, http.MethodPost, path, nil, CreateIssueCommentOption{Body: text}, nil, &requestOptions{asUser: true}); err != nil {
+		return sdk.WrapError(err, "cannot comment pull request %d of %s", id, fullname)
+	}
+	return nil
+}
+
+// PullRequestComments returns the comments posted on a pull request
+func (client *giteaClient) PullRequestComments(ctx context.Context, fullname string, id int) ([]sdk.VCSPullRequestComment, error) {
+	var comments []Comment
+	if err := client.getAll(ctx, repoPath(fullname, "issues", strconv.Itoa(id), "comments"), nil, &comments); err != nil {
+		return nil, sdk.WrapError(err, "cannot list comments of pull request %d of %s", id, fullname)
+	}
+	author, err := client.commentAuthor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sdk.VCSPullRequestComment, 0, len(comments))
+	for _, c := range comments {
+		res = append(res, sdk.VCSPullRequestComment{
+			ID:   c.ID,
+			Body: c.Body,
+			User: sdk.VCSAuthor{
+				Name:        c.User.Login,
+				DisplayName: c.User.FullName,
+				Email:       c.User.Email,
+				Avatar:      c.User.AvatarURL,
+			},
+			PostedByCDS: c.User.Login == author,
+		})
+	}
+	return res, nil
+}
+
+// commentAuthor returns the login of the account used to comment pull requests: the configured user, or the OAuth user
+func (client *giteaClient) commentAuthor(ctx context.Context) (string, error) {
+	if client.token != "" {
+		return client.username, nil
+	}
+	var u User
+	if _, err := client.do(ctx, http.MethodGet, "/user", nil, nil, &u, nil); err != nil {
+		return "", sdk.WrapError(err, "cannot get authenticated user")
+	}
+	return u.Login, nil
+}
+
+// PullRequestCommentEdit updates the body of an existing comment on a pull request
+func (client *giteaClient) PullRequestCommentEdit(ctx context.Context, fullname string, id int, comment sdk.VCSPullRequestComment) error {
+	if client.DisableStatus {
+		log.Warning(ctx, "gitea.PullRequestCommentEdit>  ⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	path := repoPath(fullname, "issues", "comments", strconv.FormatInt(comment.ID, 10))
+	if _, err := client.do(ctx, http.MethodPatch, path, nil, EditIssueCommentOption{Body: comment.Body}, nil, &requestOptions{asUser: true}); err != nil {
+		return sdk.WrapError(err, "cannot edit comment %d of %s", comment.ID, fullname)
+	}
+	return nil
+}
+
+// PullRequestCreate opens a new pull request
+func (client *giteaClient) PullRequestCreate(ctx context.Context, fullname string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
+	opts := CreatePullRequestOption{
+		Title: pr.Title,
+		Head:  pr.Head.Branch.DisplayID,
+		Base:  pr.Base.Branch.DisplayID,
+	}
+	var created PullRequest
+	if _, err := client.do(ctx, http.MethodPost, repoPath(fullname, "pulls"), nil, opts, &created, &requestOptions{asUser: true}); err != nil {
+		return sdk.VCSPullRequest{}, sdk.WrapError(err, "cannot create pull request on %s", fullname)
+	}
+	return created.ToVCSPullRequest(), nil
+}
+
+// ToVCSPullRequest converts a gitea pull request to a CDS one
+func (pullr PullRequest) ToVCSPullRequest() sdk.VCSPullRequest {
+	return sdk.VCSPullRequest{
+		ID:     pullr.Number,
+		Title:  pullr.Title,
+		URL:    pullr.HTMLURL,
+		Base:   pullr.Base.toVCSPushEvent(),
+		Head:   pullr.Head.toVCSPushEvent(),
+		Closed: pullr.State == "closed",
+		Merged: pullr.Merged,
+		User: sdk.VCSAuthor{
+			Avatar:      pullr.User.AvatarURL,
+			DisplayName: pullr.User.FullName,
+			Name:        pullr.User.Login,
+			Email:       pullr.User.Email,
+		},
+	}
+}
+
+func (b PRBranchInfo) toVCSPushEvent() sdk.VCSPushEvent {
+	return sdk.VCSPushEvent{
+		Repo:     b.Repo.FullName,
+		CloneURL: b.Repo.CloneURL,
+		Branch: sdk.VCSBranch{
+			ID:           b.Ref,
+			DisplayID:    b.Ref,
+			LatestCommit: b.Sha,
+		},
+		Commit: sdk.VCSCommit{
+			Hash:    b.Sha,
+			Message: b.Label,
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package gitea
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Release Create a release on Gitea
+func (client *giteaClient) Release(ctx context.Context, fullname string, tagName string, title string, releaseNote string) (*sdk.VCSRelease, error) {
+	opts := CreateReleaseOption{
+		TagName: tagName,
+		Title:   title,
+		Note:    releaseNote,
+	}
+	var created Release
+	if _, err := client.do(ctx, http.MethodPost, repoPath(fullname, "releases"), nil, opts, &created, nil); err != nil {
+		return nil, sdk.WrapError(err, "cannot create release %s on %s", tagName, fullname)
+	}
+
+	return &sdk.VCSRelease{
+		ID:        created.ID,
+		UploadURL: repoPath(fullname, "releases", strconv.FormatInt(created.ID, 10), "assets"),
+	}, nil
+}
+
+// UploadReleaseFile Attach a file into the release
+func (client *giteaClient) UploadReleaseFile(ctx context.Context, repo string, releaseName string, uploadURL string, artifactName string, r io.ReadCloser) error {
+	defer r.Close()
+
+	// Stream the file in a multipart body
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("attachment", artifactName)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err) // nolint
+	}()
+
+	params := url.Values{}
+	params.Set("name", artifactName)
+	req, err := client.newRequest(ctx, http.MethodPost, uploadURL, params, pr, &requestOptions{contentType: mw.FormDataContentType()})
+	if err != nil {
+		pr.Close() // nolint
+		return err
+	}
+	if _, err := client.send(ctx, req, nil); err != nil {
+		pr.Close() // nolint
+		return sdk.WrapError(err, "cannot upload %s on release %s of %s", artifactName, releaseName, repo)
+	}
+	return nil
+}
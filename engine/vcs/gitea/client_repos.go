@@ -0,0 +1,64 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Repos list repositories that are accessible to the authenticated user
+func (client *giteaClient) Repos(ctx context.Context) ([]sdk.VCSRepo, error) {
+	var repos []Repository
+	if err := client.getAll(ctx, "/user/repos", nil, &repos); err != nil {
+		return nil, sdk.WrapError(err, "cannot list repositories")
+	}
+
+	res := make([]sdk.VCSRepo, 0, len(repos))
+	for _, r := range repos {
+		res = append(res, toVCSRepo(r))
+	}
+	return res, nil
+}
+
+// RepoByFullname Get only one repo
+func (client *giteaClient) RepoByFullname(ctx context.Context, fullname string) (sdk.VCSRepo, error) {
+	repo, err := client.repoByFullname(ctx, fullname)
+	if err != nil {
+		return sdk.VCSRepo{}, err
+	}
+	return toVCSRepo(repo), nil
+}
+
+func (client *giteaClient) repoByFullname(ctx context.Context, fullname string) (Repository, error) {
+	var repo Repository
+	if _, err := client.do(ctx, http.MethodGet, repoPath(fullname), nil, nil, &repo, nil); err != nil {
+		return repo, sdk.WrapError(err, "cannot get repository %s", fullname)
+	}
+	return repo, nil
+}
+
+// GrantWritePermission adds the configured user as a collaborator with write permission on the repository
+func (client *giteaClient) GrantWritePermission(ctx context.Context, fullname string) error {
+	if client.username == "" {
+		return nil
+	}
+	path := repoPath(fullname, "collaborators", client.username)
+	if _, err := client.do(ctx, http.MethodPut, path, nil, AddCollaboratorOption{Permission: "write"}, nil, nil); err != nil {
+		return sdk.WrapError(err, "cannot add %s as collaborator on %s", client.username, fullname)
+	}
+	return nil
+}
+
+func toVCSRepo(r Repository) sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           strconv.FormatInt(r.ID, 10),
+		Name:         r.Name,
+		Slug:         r.Name,
+		Fullname:     r.FullName,
+		URL:          r.HTMLURL,
+		HTTPCloneURL: r.CloneURL,
+		SSHCloneURL:  r.SSHURL,
+	}
+}
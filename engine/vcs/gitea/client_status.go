@@ -0,0 +1,134 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+type statusData struct {
+	desc         string
+	status       string
+	repoFullName string
+	hash         string
+	urlPipeline  string
+	context      string
+}
+
+// SetStatus creates a commit status for the hash of a workflow node run
+func (client *giteaClient) SetStatus(ctx context.Context, event sdk.Event) error {
+	if client.DisableStatus {
+		log.Warning(ctx, "gitea.SetStatus>  ⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	var data statusData
+	var err error
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}):
+		data, err = processEventWorkflowNodeRun(event, client.uiURL, client.DisableStatusDetail)
+	default:
+		log.Error(ctx, "gitea.SetStatus> Unknown event %v", event)
+		return nil
+	}
+	if err != nil {
+		return sdk.WrapError(err, "Cannot process Event")
+	}
+
+	if data.status == "" {
+		log.Debug("gitea.SetStatus> Do not process event for current status: %v", event)
+		return nil
+	}
+
+	opts := CreateStatusOption{
+		State:       data.status,
+		TargetURL:   data.urlPipeline,
+		Description: data.desc,
+		Context:     data.context,
+	}
+	var created Status
+	if _, err := client.do(ctx, http.MethodPost, repoPath(data.repoFullName, "statuses", data.hash), nil, opts, &created, nil); err != nil {
+		return sdk.WrapError(err, "cannot create status on %s for %s", data.repoFullName, data.hash)
+	}
+
+	log.Debug("gitea.SetStatus> Status %d %s created at %v", created.ID, created.Context, created.Created)
+	return nil
+}
+
+// ListStatuses returns the CDS statuses of a commit
+func (client *giteaClient) ListStatuses(ctx context.Context, repo string, ref string) ([]sdk.VCSCommitStatus, error) {
+	var ss []Status
+	if err := client.getAll(ctx, repoPath(repo, "commits", url.PathEscape(ref), "statuses"), nil, &ss); err != nil {
+		return nil, sdk.WrapError(err, "cannot list statuses of %s on %s", ref, repo)
+	}
+
+	vcsStatuses := make([]sdk.VCSCommitStatus, 0, len(ss))
+	for _, s := range ss {
+		if !strings.HasPrefix(s.Context, "CDS/") {
+			continue
+		}
+		vcsStatuses = append(vcsStatuses, sdk.VCSCommitStatus{
+			CreatedAt:  s.Created,
+			Decription: s.Context,
+			Ref:        ref,
+			State:      processGiteaState(s),
+		})
+	}
+	return vcsStatuses, nil
+}
+
+func processGiteaState(s Status) string {
+	switch s.State {
+	case "success":
+		return sdk.StatusSuccess
+	case "failure", "error":
+		return sdk.StatusFail
+	default:
+		return sdk.StatusBuilding
+	}
+}
+
+func processEventWorkflowNodeRun(event sdk.Event, cdsUIURL string, disabledStatusDetail bool) (statusData, error) {
+	data := statusData{}
+	var eventNR sdk.EventRunWorkflowNode
+	if err := mapstructure.Decode(event.Payload, &eventNR); err != nil {
+		return data, sdk.WrapError(err, "Error during consumption")
+	}
+
+	//We only manage status Success, Failure, Stopped and Building
+	switch eventNR.Status {
+	case sdk.StatusFail:
+		data.status = "failure"
+	case sdk.StatusSuccess:
+		data.status = "success"
+	case sdk.StatusStopped:
+		data.status = "error"
+	case sdk.StatusBuilding:
+		data.status = "pending"
+	default:
+		return data, nil
+	}
+	data.hash = eventNR.Hash
+	data.repoFullName = eventNR.RepositoryFullName
+
+	//CDS can avoid sending gitea target url in status, if it's disable
+	if !disabledStatusDetail {
+		data.urlPipeline = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d",
+			cdsUIURL,
+			event.ProjectKey,
+			event.WorkflowName,
+			eventNR.Number,
+		)
+	}
+
+	data.context = sdk.VCSCommitStatusDescription(event.ProjectKey, event.WorkflowName, eventNR)
+	data.desc = eventNR.NodeName + ": " + eventNR.Status
+	return data, nil
+}
@@ -0,0 +1,26 @@
+package gitea
+
+import (
+	"context"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Tags returns list of tags for a repo
+func (client *giteaClient) Tags(ctx context.Context, fullname string) ([]sdk.VCSTag, error) {
+	var tags []Tag
+	if err := client.getAll(ctx, repoPath(fullname, "tags"), nil, &tags); err != nil {
+		return nil, sdk.WrapError(err, "cannot list tags of %s", fullname)
+	}
+
+	res := make([]sdk.VCSTag, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, sdk.VCSTag{
+			Tag:     t.Name,
+			Sha:     t.ID,
+			Hash:    t.Commit.SHA,
+			Message: t.Message,
+		})
+	}
+	return res, nil
+}
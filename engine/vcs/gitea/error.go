@@ -0,0 +1,25 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Error wraps gitea error format
+type Error struct {
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("(gitea) %s", e.Message)
+}
+
+// errorAPI creates a new error
+func errorAPI(status int, body []byte) error {
+	var res Error
+	if err := json.Unmarshal(body, &res); err != nil || res.Message == "" {
+		res.Message = fmt.Sprintf("status code %d: %s", status, string(body))
+	}
+	return res
+}
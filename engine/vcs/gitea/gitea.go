@@ -0,0 +1,64 @@
+package gitea
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	_ sdk.VCSAuthorizedClient = &giteaClient{}
+	_ sdk.VCSServer           = &giteaConsumer{}
+)
+
+// giteaClient is a Gitea (or Forgejo) wrapper for CDS vcs. interface
+type giteaClient struct {
+	URL                 string
+	OAuthToken          string
+	RefreshToken        string
+	DisableStatus       bool
+	DisableStatusDetail bool
+	Cache               cache.Store
+	uiURL               string
+	proxyURL            string
+	username            string
+	token               string
+}
+
+// giteaConsumer implements vcs.Server and it's used to instantiate a giteaClient
+type giteaConsumer struct {
+	URL                      string `json:"url"`
+	ClientID                 string `json:"client-id"`
+	ClientSecret             string `json:"-"`
+	AuthorizationCallbackURL string
+	Cache                    cache.Store
+	uiURL                    string
+	proxyURL                 string
+	disableStatus            bool
+	disableStatusDetail      bool
+	username                 string
+	token                    string
+}
+
+// New creates a new Gitea consumer
+func New(clientID, clientSecret, giteaURL, callbackURL, uiURL, proxyURL, username, token string, store cache.Store, disableStatus, disableStatusDetail bool) sdk.VCSServer {
+	return &giteaConsumer{
+		URL:                      strings.TrimSuffix(giteaURL, "/"),
+		ClientID:                 clientID,
+		ClientSecret:             clientSecret,
+		AuthorizationCallbackURL: callbackURL,
+		Cache:                    store,
+		uiURL:                    uiURL,
+		proxyURL:                 proxyURL,
+		disableStatus:            disableStatus,
+		disableStatusDetail:      disableStatusDetail,
+		username:                 username,
+		token:                    token,
+	}
+}
+
+func (c *giteaClient) GetAccessToken(_ context.Context) string {
+	return c.OAuthToken
+}
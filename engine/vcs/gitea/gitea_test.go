@@ -0,0 +1,376 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+type recordedRequest struct {
+	Method        string
+	Path          string
+	Query         string
+	Authorization string
+	ContentType   string
+	Body          []byte
+}
+
+// newTestServer serves the recorded Gitea responses from testdata, routes are given as "METHOD path"
+func newTestServer(t *testing.T, routes map[string]string) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, recordedRequest{
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Query:         r.URL.RawQuery,
+			Authorization: r.Header.Get("Authorization"),
+			ContentType:   r.Header.Get("Content-Type"),
+			Body:          body,
+		})
+
+		fixture, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`)) // nolint
+			return
+		}
+		status := http.StatusOK
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+		if fixture == "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		b, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(b) // nolint
+	}))
+	return srv, &requests
+}
+
+func newTestClient(srv *httptest.Server) *giteaClient {
+	return &giteaClient{
+		URL:        srv.URL,
+		OAuthToken: "access-token",
+		uiURL:      "https://cds.example.com",
+		username:   "cds-bot",
+		token:      "bot-token",
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	log.SetLogger(t)
+	srv, requests := newTestServer(t, map[string]string{
+		"POST /login/oauth/access_token": "access_token.json",
+	})
+	defer srv.Close()
+	consumer := New("client-id", "client-secret", srv.URL+"/", "https://cds.example.com/repositories_manager/oauth2/callback", "", "", "", "", nil, false, false)
+
+	state, redirect, err := consumer.AuthorizeRedirect(context.TODO())
+	require.NoError(t, err)
+	assert.NotEmpty(t, state)
+	assert.True(t, strings.HasPrefix(redirect, srv.URL+"/login/oauth/authorize?"))
+	assert.Contains(t, redirect, "client_id=client-id")
+	assert.Contains(t, redirect, "state="+state)
+
+	accessToken, refreshToken, err := consumer.AuthorizeToken(context.TODO(), state, "code")
+	require.NoError(t, err)
+	assert.Equal(t, "new-access-token", accessToken)
+	assert.Equal(t, "new-refresh-token", refreshToken)
+	require.Len(t, *requests, 1)
+	assert.Contains(t, string((*requests)[0].Body), "grant_type=authorization_code")
+
+	// An expired token is refreshed
+	client, err := consumer.GetAuthorizedClient(context.TODO(), "old-access-token", "old-refresh-token", time.Now().Add(-2*time.Hour).Unix())
+	require.NoError(t, err)
+	assert.Equal(t, "new-access-token", client.GetAccessToken(context.TODO()))
+	require.Len(t, *requests, 2)
+	assert.Contains(t, string((*requests)[1].Body), "grant_type=refresh_token")
+
+	client, err = consumer.GetAuthorizedClient(context.TODO(), "valid-access-token", "refresh-token", time.Now().Unix())
+	require.NoError(t, err)
+	assert.Equal(t, "valid-access-token", client.GetAccessToken(context.TODO()))
+	assert.Len(t, *requests, 2)
+}
+
+func TestRepos(t *testing.T) {
+	log.SetLogger(t)
+	srv, requests := newTestServer(t, map[string]string{
+		"GET /api/v1/user/repos":           "repos.json",
+		"GET /api/v1/repos/cds/demo":       "repo.json",
+		"GET /api/v1/repos/cds/demo/forks": "forks.json",
+	})
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	repos, err := client.Repos(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	assert.Equal(t, "cds/demo", repos[0].Fullname)
+	assert.Equal(t, "Bearer access-token", (*requests)[0].Authorization)
+	assert.Contains(t, (*requests)[0].Query, "limit=50")
+
+	repo, err := client.RepoByFullname(context.TODO(), "cds/demo")
+	require.NoError(t, err)
+	assert.Equal(t, sdk.VCSRepo{
+		ID:           "12",
+		Name:         "demo",
+		Slug:         "demo",
+		Fullname:     "cds/demo",
+		URL:          "https://gitea.example.com/cds/demo",
+		HTTPCloneURL: "https://gitea.example.com/cds/demo.git",
+		SSHCloneURL:  "git@gitea.example.com:cds/demo.git",
+	}, repo)
+
+	_, err = client.RepoByFullname(context.TODO(), "cds/unknown")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+
+	forks, err := client.ListForks(context.TODO(), "cds/demo")
+	require.NoError(t, err)
+	require.Len(t, forks, 1)
+	assert.Equal(t, "alice/demo", forks[0].Fullname)
+}
+
+func TestBranchesAndTags(t *testing.T) {
+	log.SetLogger(t)
+	srv, requests := newTestServer(t, map[string]string{
+		"GET /api/v1/repos/cds/demo":                      "repo.json",
+		"GET /api/v1/repos/cds/demo/branches":             "branches.json",
+		"GET /api/v1/repos/cds/demo/branches/feat/readme": "branch.json",
+		"GET /api/v1/repos/cds/demo/tags":                 "tags.json",
+	})
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	branches, err := client.Branches(context.TODO(), "cds/demo")
+	require.NoError(t, err)
+	require.Len(t, branches, 2)
+	def := sdk.GetDefaultBranch(branches)
+	assert.Equal(t, "main", def.DisplayID)
+	assert.Equal(t, "refs/heads/main", def.ID)
+	assert.Equal(t, "c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2", def.LatestCommit)
+
+	branch, err := client.Branch(context.TODO(), "cds/demo", "feat/readme")
+	require.NoError(t, err)
+	assert.Equal(t, "feat/readme", branch.DisplayID)
+	assert.False(t, branch.Default)
+	assert.Equal(t, "/api/v1/repos/cds/demo/branches/feat/readme", (*requests)[len(*requests)-1].Path)
+
+	tags, err := client.Tags(context.TODO(), "cds/demo")
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "v1.0.0", tags[0].Tag)
+	assert.Equal(t, "c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2", tags[0].Hash)
+}
+
+func TestCommits(t *testing.T) {
+	log.SetLogger(t)
+	srv, requests := newTestServer(t, map[string]string{
+		"GET /api/v1/repos/cds/demo/commits":                                              "commits.json",
+		"GET /api/v1/repos/cds/demo/git/commits/a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0": "commit.json",
+		"GET /api/v1/repos/cds/demo/git/commits/v0.1.0":                                   "commit.json",
+	})
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	commit, err := client.Commit(context.TODO(), "cds/demo", "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0")
+	require.NoError(t, err)
+	assert.Equal(t, "Initial commit\n", commit.Message)
+	assert.Equal(t, "Bob", commit.Author.Name)
+	assert.Equal(t, time.Date(2020, 2, 28, 10, 0, 0, 0, time.UTC).Unix()*1000, commit.Timestamp)
+
+	commits, err := client.Commits(context.TODO(), "cds/demo", "main", "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0", "")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2", commits[0].Hash)
+	assert.Equal(t, "alice", commits[0].Author.Name)
+	assert.Equal(t, "https://gitea.example.com/avatars/7", commits[0].Author.Avatar)
+	assert.Contains(t, (*requests)[len(*requests)-1].Query, "sha=main")
+
+	commits, err = client.Commits(context.TODO(), "cds/demo", "main", "", "")
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+
+	commits, err = client.CommitsBetweenRefs(context.TODO(), "cds/demo", "v0.1.0", "main")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1", commits[1].Hash)
+}
+
+func TestPullRequests(t *testing.T) {
+	log.SetLogger(t)
+	srv, requests := newTestServer(t, map[string]string{
+		"GET /api/v1/repos/cds/demo/pulls":                "pulls.json",
+		"GET /api/v1/repos/cds/demo/pulls/5":              "pull.json",
+		"POST /api/v1/repos/cds/demo/pulls":               "pull.json",
+		"POST /api/v1/repos/cds/demo/issues/5/comments":   "",
+		"GET /api/v1/repos/cds/demo/issues/5/comments":    "comments.json",
+		"PATCH /api/v1/repos/cds/demo/issues/comments/41": "",
+	})
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	prs, err := client.PullRequests(context.TODO(), "cds/demo")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.Contains(t, (*requests)[0].Query, "state=open")
+
+	pr, err := client.PullRequest(context.TODO(), "cds/demo", 5)
+	require.NoError(t, err)
+	assert.Equal(t, 5, pr.ID)
+	assert.Equal(t, "Add changelog", pr.Title)
+	assert.Equal(t, "alice/demo", pr.Head.Repo)
+	assert.Equal(t, "feat/changelog", pr.Head.Branch.DisplayID)
+	assert.Equal(t, "d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3", pr.Head.Commit.Hash)
+	assert.Equal(t, "cds/demo", pr.Base.Repo)
+	assert.Equal(t, "alice", pr.User.Name)
+	assert.False(t, pr.Closed)
+
+	created, err := client.PullRequestCreate(context.TODO(), "cds/demo", sdk.VCSPullRequest{
+		Title: "Add changelog",
+		Head:  sdk.VCSPushEvent{Branch: sdk.VCSBranch{DisplayID: "feat/changelog"}},
+		Base:  sdk.VCSPushEvent{Branch: sdk.VCSBranch{DisplayID: "main"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, created.ID)
+	last := (*requests)[len(*requests)-1]
+	assert.Equal(t, "token bot-token", last.Authorization)
+	assert.JSONEq(t, `{"title":"Add changelog","head":"feat/changelog","base":"main"}`, string(last.Body))
+
+	require.NoError(t, client.PullRequestComment(context.TODO(), "cds/demo", 5, "Build succeeded"))
+	last = (*requests)[len(*requests)-1]
+	assert.Equal(t, "token bot-token", last.Authorization)
+	assert.JSONEq(t, `{"body":"Build succeeded"}`, string(last.Body))
+
+	comments, err := client.PullRequestComments(context.TODO(), "cds/demo", 5)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, int64(41), comments[0].ID)
+	assert.Equal(t, "cds-bot", comments[0].User.Name)
+	assert.True(t, comments[0].PostedByCDS)
+	assert.False(t, comments[1].PostedByCDS)
+
+	comments[0].Body = "Build failed"
+	require.NoError(t, client.PullRequestCommentEdit(context.TODO(), "cds/demo", 5, comments[0]))
+	last = (*requests)[len(*requests)-1]
+	assert.Equal(t, http.MethodPatch, last.Method)
+	assert.Equal(t, "token bot-token", last.Authorization)
+	assert.JSONEq(t, `{"body":"Build failed"}`, string(last.Body))
+}
+
+func TestHooks(t *testing.T) {
+	log.SetLogger(t)
+	srv, requests := newTestServer(t, map[string]string{
+		"GET /api/v1/repos/cds/demo/hooks":      "hooks.json",
+		"POST /api/v1/repos/cds/demo/hooks":     "hook.json",
+		"PATCH /api/v1/repos/cds/demo/hooks/9":  "hook.json",
+		"DELETE /api/v1/repos/cds/demo/hooks/8": "",
+	})
+	defer srv.Close()
+	client := newTestClient(srv)
+	client.proxyURL = "https://proxy.example.com/"
+
+	hook := sdk.VCSHook{URL: "https://cds.example.com/cds-hooks/webhook/123"}
+	require.NoError(t, client.CreateHook(context.TODO(), "cds/demo", &hook))
+	assert.Equal(t, "9", hook.ID)
+	assert.Equal(t, "https://proxy.example.com/123", hook.URL)
+	var body CreateHookOption
+	require.NoError(t, json.Unmarshal((*requests)[0].Body, &body))
+	assert.Equal(t, "gitea", body.Type)
+	assert.Equal(t, []string{"push"}, body.Events)
+	assert.Equal(t, "https://proxy.example.com/123", body.Config["url"])
+
+	hook.Events = []string{"push", "pull_request"}
+	require.NoError(t, client.UpdateHook(context.TODO(), "cds/demo", &hook))
+	assert.Equal(t, http.MethodPatch, (*requests)[1].Method)
+
+	found, err := client.GetHook(context.TODO(), "cds/demo", "https://cds.example.com/cds-hooks/webhook/123")
+	require.NoError(t, err)
+	assert.Equal(t, "8", found.ID)
+	assert.Equal(t, []string{"push", "pull_request"}, found.Events)
+
+	_, err = client.GetHook(context.TODO(), "cds/demo", "https://unknown.example.com")
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+
+	require.NoError(t, client.DeleteHook(context.TODO(), "cds/demo", sdk.VCSHook{URL: "https://cds.example.com/cds-hooks/webhook/123"}))
+	assert.Equal(t, http.MethodDelete, (*requests)[len(*requests)-1].Method)
+}
+
+func TestStatuses(t *testing.T) {
+	log.SetLogger(t)
+	hash := "c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2"
+	srv, requests := newTestServer(t, map[string]string{
+		"POST /api/v1/repos/cds/demo/statuses/" + hash:             "status.json",
+		"GET /api/v1/repos/cds/demo/commits/" + hash + "/statuses": "statuses.json",
+	})
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	event := sdk.Event{
+		EventType:    "sdk.EventRunWorkflowNode",
+		ProjectKey:   "PROJ",
+		WorkflowName: "demo",
+		Payload: structs.Map(sdk.EventRunWorkflowNode{
+			Number:             3,
+			NodeName:           "build",
+			Status:             sdk.StatusFail,
+			Hash:               hash,
+			RepositoryFullName: "cds/demo",
+		}),
+	}
+	require.NoError(t, client.SetStatus(context.TODO(), event))
+	require.Len(t, *requests, 1)
+	assert.JSONEq(t, `{"state":"failure","target_url":"https://cds.example.com/project/PROJ/workflow/demo/run/3","description":"build: Fail","context":"CDS/PROJ-demo-build"}`, string((*requests)[0].Body))
+
+	// Waiting node runs are not reported
+	event.Payload = structs.Map(sdk.EventRunWorkflowNode{Status: sdk.StatusWaiting, Hash: hash, RepositoryFullName: "cds/demo"})
+	require.NoError(t, client.SetStatus(context.TODO(), event))
+	assert.Len(t, *requests, 1)
+
+	statuses, err := client.ListStatuses(context.TODO(), "cds/demo", hash)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, sdk.StatusSuccess, statuses[0].State)
+	assert.Equal(t, "CDS/PROJ-demo-build", statuses[0].Decription)
+}
+
+func TestRelease(t *testing.T) {
+	log.SetLogger(t)
+	srv, requests := newTestServer(t, map[string]string{
+		"POST /api/v1/repos/cds/demo/releases":           "release.json",
+		"POST /api/v1/repos/cds/demo/releases/17/assets": "asset.json",
+	})
+	defer srv.Close()
+	client := newTestClient(srv)
+
+	release, err := client.Release(context.TODO(), "cds/demo", "v1.0.0", "Release v1.0.0", "First release")
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), release.ID)
+	assert.Equal(t, "/repos/cds/demo/releases/17/assets", release.UploadURL)
+
+	err = client.UploadReleaseFile(context.TODO(), "cds/demo", "v1.0.0", release.UploadURL, "demo.tar.gz", ioutil.NopCloser(strings.NewReader("hello world")))
+	require.NoError(t, err)
+	last := (*requests)[len(*requests)-1]
+	assert.Equal(t, "name=demo.tar.gz", last.Query)
+	assert.True(t, strings.HasPrefix(last.ContentType, "multipart/form-data"))
+	assert.Contains(t, string(last.Body), `name="attachment"; filename="demo.tar.gz"`)
+	assert.Contains(t, string(last.Body), "hello world")
+}
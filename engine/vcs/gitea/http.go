@@ -0,0 +1,178 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	apiPath      = "/api/v1"
+	pageLimit    = 50
+	maxPageCount = 20
+)
+
+// Gitea http var
+var (
+	httpClient = cdsclient.NewHTTPClient(time.Second*30, false)
+)
+
+func (consumer *giteaConsumer) postForm(url string, data url.Values) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(data.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, err
+	}
+	return res.StatusCode, resBody, nil
+}
+
+type requestOptions struct {
+	// asUser performs the request with the token of the configured user instead of the OAuth token
+	asUser      bool
+	contentType string
+}
+
+func (client *giteaClient) newRequest(ctx context.Context, method, path string, params url.Values, body io.Reader, opts *requestOptions) (*http.Request, error) {
+	uri := client.URL + apiPath + path
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(method, uri, body)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Accept", "application/json")
+	if opts != nil && opts.asUser && client.token != "" {
+		req.Header.Set("Authorization", "token "+client.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+client.OAuthToken)
+	}
+	if body != nil {
+		contentType := "application/json"
+		if opts != nil && opts.contentType != "" {
+			contentType = opts.contentType
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	log.Debug("Gitea API>> Request URL %s %s", method, req.URL.String())
+	return req, nil
+}
+
+// do sends a request on the Gitea API and unmarshals the response body in v if not nil
+func (client *giteaClient) do(ctx context.Context, method, path string, params url.Values, in interface{}, v interface{}, opts *requestOptions) (http.Header, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot marshal body")
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := client.newRequest(ctx, method, path, params, body, opts)
+	if err != nil {
+		return nil, err
+	}
+	return client.send(ctx, req, v)
+}
+
+func (client *giteaClient) send(ctx context.Context, req *http.Request, v interface{}) (http.Header, error) {
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, sdk.WrapError(err, "HTTP Error")
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	switch res.StatusCode {
+	case http.StatusNotFound:
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	case http.StatusForbidden:
+		return nil, sdk.WithStack(sdk.ErrForbidden)
+	case http.StatusUnauthorized:
+		return nil, sdk.WithStack(sdk.ErrUnauthorized)
+	}
+	if res.StatusCode >= 400 {
+		log.Warning(ctx, "giteaClient.do> %s %s: %s", req.Method, req.URL.Path, string(body))
+		return nil, sdk.NewError(sdk.ErrUnknownError, errorAPI(res.StatusCode, body))
+	}
+
+	if v != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, v); err != nil {
+			return nil, sdk.WrapError(err, "cannot unmarshal response")
+		}
+	}
+	return res.Header, nil
+}
+
+// getAll reads all the pages of a listing endpoint and unmarshals the items in v
+func (client *giteaClient) getAll(ctx context.Context, path string, params url.Values, v interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", strconv.Itoa(pageLimit))
+
+	var items []json.RawMessage
+	for page := 1; page <= maxPageCount; page++ {
+		params.Set("page", strconv.Itoa(page))
+		var pageItems []json.RawMessage
+		headers, err := client.do(ctx, http.MethodGet, path, params, nil, &pageItems, nil)
+		if err != nil {
+			return err
+		}
+		items = append(items, pageItems...)
+		if len(pageItems) < pageLimit || !hasNextPage(headers) {
+			break
+		}
+	}
+
+	b, err := json.Marshal(items)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	return sdk.WithStack(json.Unmarshal(b, v))
+}
+
+// hasNextPage checks the Link header sent by Gitea on paginated listings
+func hasNextPage(headers http.Header) bool {
+	link := headers.Get("Link")
+	if link == "" {
+		// Older versions of Gitea do not send the Link header, rely on the page size
+		return true
+	}
+	return strings.Contains(link, `rel="next"`)
+}
+
+func repoPath(repo string, elem ...string) string {
+	return fmt.Sprintf("/repos/%s", strings.Join(append([]string{repo}, elem...), "/"))
+}
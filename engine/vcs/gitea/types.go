@@ -0,0 +1,219 @@
+package gitea
+
+import "time"
+
+// AccessToken is the response of the OAuth2 access_token endpoint
+type AccessToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// User represents a Gitea user
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	UserName  string `json:"username"`
+}
+
+// Repository represents a Gitea repository
+type Repository struct {
+	ID            int64       `json:"id"`
+	Owner         User        `json:"owner"`
+	Name          string      `json:"name"`
+	FullName      string      `json:"full_name"`
+	Description   string      `json:"description"`
+	Private       bool        `json:"private"`
+	Fork          bool        `json:"fork"`
+	Parent        *Repository `json:"parent,omitempty"`
+	HTMLURL       string      `json:"html_url"`
+	SSHURL        string      `json:"ssh_url"`
+	CloneURL      string      `json:"clone_url"`
+	DefaultBranch string      `json:"default_branch"`
+}
+
+// PayloadCommit is the commit of a branch, or the commit of a push event
+type PayloadCommit struct {
+	ID        string       `json:"id"`
+	Message   string       `json:"message"`
+	URL       string       `json:"url"`
+	Author    *PayloadUser `json:"author"`
+	Committer *PayloadUser `json:"committer"`
+	Timestamp time.Time    `json:"timestamp"`
+	Added     []string     `json:"added"`
+	Removed   []string     `json:"removed"`
+	Modified  []string     `json:"modified"`
+}
+
+// PayloadUser is the author or the committer of a commit
+type PayloadUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	UserName string `json:"username"`
+}
+
+// Branch represents a Gitea branch
+type Branch struct {
+	Name   string        `json:"name"`
+	Commit PayloadCommit `json:"commit"`
+}
+
+// Tag represents a Gitea tag
+type Tag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	ID      string `json:"id"`
+	Commit  struct {
+		SHA string `json:"sha"`
+		URL string `json:"url"`
+	} `json:"commit"`
+}
+
+// CommitUser is the git identity of a commit author or committer
+type CommitUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date"`
+}
+
+// Commit represents a Gitea commit
+type Commit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message   string     `json:"message"`
+		Author    CommitUser `json:"author"`
+		Committer CommitUser `json:"committer"`
+	} `json:"commit"`
+	Author  *User `json:"author"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Files []CommitAffectedFile `json:"files"`
+}
+
+// CommitAffectedFile is a file changed by a commit
+type CommitAffectedFile struct {
+	Filename string `json:"filename"`
+}
+
+// Compare is the result of a comparison between two refs
+type Compare struct {
+	TotalCommits int      `json:"total_commits"`
+	Commits      []Commit `json:"commits"`
+}
+
+// PRBranchInfo is the head or the base of a pull request
+type PRBranchInfo struct {
+	Label  string     `json:"label"`
+	Ref    string     `json:"ref"`
+	Sha    string     `json:"sha"`
+	RepoID int64      `json:"repo_id"`
+	Repo   Repository `json:"repo"`
+}
+
+// PullRequest represents a Gitea pull request
+type PullRequest struct {
+	ID      int64        `json:"id"`
+	Number  int          `json:"number"`
+	User    User         `json:"user"`
+	Title   string       `json:"title"`
+	Body    string       `json:"body"`
+	State   string       `json:"state"`
+	HTMLURL string       `json:"html_url"`
+	Merged  bool         `json:"merged"`
+	Head    PRBranchInfo `json:"head"`
+	Base    PRBranchInfo `json:"base"`
+}
+
+// CreatePullRequestOption is the body to create a pull request
+type CreatePullRequestOption struct {
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+// Comment represents a comment on an issue or a pull request
+type Comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User User   `json:"user"`
+}
+
+// EditIssueCommentOption is the body to edit a comment
+type EditIssueCommentOption struct {
+	Body string `json:"body"`
+}
+
+// CreateIssueCommentOption is the body to comment an issue or a pull request
+type CreateIssueCommentOption struct {
+	Body string `json:"body"`
+}
+
+// Hook represents a Gitea repository webhook
+type Hook struct {
+	ID     int64             `json:"id"`
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// CreateHookOption is the body to create a webhook
+type CreateHookOption struct {
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// EditHookOption is the body to update a webhook
+type EditHookOption struct {
+	Config map[string]string `json:"config,omitempty"`
+	Events []string          `json:"events,omitempty"`
+	Active *bool             `json:"active,omitempty"`
+}
+
+// Status represents a Gitea commit status
+type Status struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"status"`
+	TargetURL   string    `json:"target_url"`
+	Description string    `json:"description"`
+	Context     string    `json:"context"`
+	Created     time.Time `json:"created_at"`
+}
+
+// CreateStatusOption is the body to create a commit status
+type CreateStatusOption struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+// Release represents a Gitea release
+type Release struct {
+	ID      int64  `json:"id"`
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CreateReleaseOption is the body to create a release
+type CreateReleaseOption struct {
+	TagName string `json:"tag_name"`
+	Title   string `json:"name"`
+	Note    string `json:"body"`
+}
+
+// AddCollaboratorOption is the body to add a collaborator on a repository
+type AddCollaboratorOption struct {
+	Permission string `json:"permission"`
+}
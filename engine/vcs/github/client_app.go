@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const appAcceptHeader = "application/vnd.github.machine-man-preview+json"
+
+// appInstallationToken is an access token of a GitHub App installation:
+// https://developer.github.com/v3/apps/#create-an-installation-access-token-for-an-app
+type appInstallationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// appJWT returns a token that authenticates CDS as the configured GitHub App, valid for 10 minutes:
+// https://developer.github.com/apps/building-github-apps/authenticating-with-github-apps/#authenticating-as-a-github-app
+func (c *githubClient) appJWT() (string, error) {
+	if c.appID == 0 || c.appPrivateKey == "" {
+		return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "GitHub App is not configured")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(c.appPrivateKey))
+	if err != nil {
+		return "", sdk.WrapError(err, "invalid GitHub App private key")
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.StandardClaims{
+		IssuedAt:  now.Add(-time.Minute).Unix(), // allow a clock drift with GitHub
+		ExpiresAt: now.Add(10 * time.Minute).Unix(),
+		Issuer:    strconv.FormatInt(c.appID, 10),
+	})
+	signed, err := token.SignedString(key)
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to sign GitHub App token")
+	}
+	return signed, nil
+}
+
+// appInstallationAccessToken returns an access token of the GitHub App installation on the given repository.
+// Tokens are valid for one hour and are kept in cache until they expire.
+func (c *githubClient) appInstallationAccessToken(ctx context.Context, repo string) (string, error) {
+	k := cache.Key("vcs", "github", "app", strconv.FormatInt(c.appID, 10), "installation", repo)
+	var cached appInstallationToken
+	if ok, err := c.Cache.Get(k, &cached); err != nil {
+		log.Error(ctx, "cannot get from cache %s: %v", k, err)
+	} else if ok && time.Until(cached.ExpiresAt) > time.Minute {
+		return cached.Token, nil
+	}
+
+	appToken, err := c.appJWT()
+	if err != nil {
+		return "", err
+	}
+
+	var installation struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.appRequest(http.MethodGet, fmt.Sprintf("/repos/%s/installation", repo), appToken, &installation); err != nil {
+		return "", sdk.WrapError(err, "unable to get GitHub App installation on %s", repo)
+	}
+
+	var token appInstallationToken
+	if err := c.appRequest(http.MethodPost, fmt.Sprintf("/app/installations/%d/access_tokens", installation.ID), appToken, &token); err != nil {
+		return "", sdk.WrapError(err, "unable to create access token for GitHub App installation %d", installation.ID)
+	}
+
+	if ttl := int(time.Until(token.ExpiresAt).Seconds()) - 60; ttl > 0 {
+		if err := c.Cache.SetWithTTL(k, token, ttl); err != nil {
+			log.Error(ctx, "cannot SetWithTTL: %s: %v", k, err)
+		}
+	}
+	return token.Token, nil
+}
+
+// appRequest calls the GitHub API authenticated as the GitHub App
+func (c *githubClient) appRequest(method, path, appToken string, result interface{}) error {
+	req, err := http.NewRequest(method, c.GitHubAPIURL+path, nil)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("User-Agent", "CDS-gh_client_id="+c.ClientID)
+	req.Header.Set("Accept", appAcceptHeader)
+	req.Header.Set("Authorization", "Bearer "+appToken)
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WrapError(err, "unable to read body")
+	}
+	if res.StatusCode >= 300 {
+		return sdk.WithStack(fmt.Errorf("GitHub API error %d: %s", res.StatusCode, body))
+	}
+	return sdk.WithStack(json.Unmarshal(body, result))
+}
@@ -0,0 +1,264 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// CheckRunRerunAction is the identifier of the action that runs a workflow node again
+	CheckRunRerunAction = "rerun"
+
+	checksAcceptHeader     = "application/vnd.github.antiope-preview+json"
+	maxCheckRunAnnotations = 50
+	checkRunCacheTTL       = 7 * 24 * 60 * 60
+)
+
+// CheckRunRerun is a request to run again a workflow node, sent from the "Re-run" button of a check run
+type CheckRunRerun struct {
+	ProjectKey   string
+	WorkflowName string
+	Number       int64
+	NodeID       int64
+	Username     string
+	UserID       string
+}
+
+// setCheckRun creates or updates the check run of a workflow node run:
+// https://developer.github.com/v3/checks/runs/
+func (g *githubClient) setCheckRun(ctx context.Context, event sdk.Event) error {
+	var eventNR sdk.EventRunWorkflowNode
+	if err := mapstructure.Decode(event.Payload, &eventNR); err != nil {
+		return sdk.WrapError(err, "Error during consumption")
+	}
+
+	checkRun, ok := newCheckRun(event, eventNR, g.uiURL, g.DisableStatusDetail)
+	if !ok {
+		log.Debug("github.setCheckRun> Do not process event for current status: %v", event)
+		return nil
+	}
+
+	k := cache.Key("vcs", "github", "checks", eventNR.RepositoryFullName, eventNR.Hash, checkRun.Name)
+	var checkRunID int64
+	if _, err := g.Cache.Get(k, &checkRunID); err != nil {
+		log.Error(ctx, "cannot get from cache %s: %v", k, err)
+	}
+
+	b, err := json.Marshal(checkRun)
+	if err != nil {
+		return sdk.WrapError(err, "Unable to marshal github check run")
+	}
+
+	// Check runs can only be created by a GitHub App
+	token, err := g.appInstallationAccessToken(ctx, eventNR.RepositoryFullName)
+	if err != nil {
+		return err
+	}
+	opts := &postOptions{accept: checksAcceptHeader, token: token}
+	var res *http.Response
+	if checkRunID > 0 {
+		path := fmt.Sprintf("/repos/%s/check-runs/%d", eventNR.RepositoryFullName, checkRunID)
+		res, err = g.patch(path, "application/json", bytes.NewReader(b), opts)
+		if err == nil && res.StatusCode == http.StatusNotFound {
+			res.Body.Close() // nolint
+			checkRunID = 0
+		}
+	}
+	if checkRunID == 0 {
+		path := fmt.Sprintf("/repos/%s/check-runs", eventNR.RepositoryFullName)
+		res, err = g.post(path, "application/json", bytes.NewReader(b), opts)
+	}
+	if err != nil {
+		return sdk.WrapError(err, "Unable to post check run")
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WrapError(err, "Unable to read body")
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return sdk.WithStack(fmt.Errorf("Unable to set check run on github. Status code : %d - Body: %s", res.StatusCode, body))
+	}
+
+	var created CheckRun
+	if err := json.Unmarshal(body, &created); err != nil {
+		return sdk.WrapError(err, "Unable to unmarshal body")
+	}
+	if err := g.Cache.SetWithTTL(k, created.ID, checkRunCacheTTL); err != nil {
+		log.Error(ctx, "cannot SetWithTTL: %s: %v", k, err)
+	}
+
+	log.Debug("github.setCheckRun> Check run %d %s set to %s", created.ID, created.Name, checkRun.Status)
+	return nil
+}
+
+// newCheckRun returns the check run for a workflow node run event, or false if there is nothing to report
+func newCheckRun(event sdk.Event, eventNR sdk.EventRunWorkflowNode, cdsUIURL string, disabledStatusDetail bool) (CheckRun, bool) {
+	checkRun := CheckRun{
+		Name:       sdk.VCSCommitStatusDescription(event.ProjectKey, event.WorkflowName, eventNR),
+		HeadSHA:    eventNR.Hash,
+		ExternalID: checkRunExternalID(event.ProjectKey, event.WorkflowName, eventNR.Number, eventNR.NodeID),
+	}
+
+	switch eventNR.Status {
+	case sdk.StatusWaiting:
+		checkRun.Status = "queued"
+	case sdk.StatusBuilding:
+		checkRun.Status = "in_progress"
+	case sdk.StatusSuccess:
+		checkRun.Status, checkRun.Conclusion = "completed", "success"
+	case sdk.StatusFail:
+		checkRun.Status, checkRun.Conclusion = "completed", "failure"
+	case sdk.StatusStopped:
+		checkRun.Status, checkRun.Conclusion = "completed", "cancelled"
+	case sdk.StatusSkipped, sdk.StatusDisabled, sdk.StatusNeverBuilt:
+		checkRun.Status, checkRun.Conclusion = "completed", "neutral"
+	default:
+		return checkRun, false
+	}
+
+	if eventNR.Start > 0 {
+		start := time.Unix(eventNR.Start, 0)
+		checkRun.StartedAt = &start
+	}
+	if checkRun.Status == "completed" {
+		done := time.Now()
+		if eventNR.Done > 0 {
+			done = time.Unix(eventNR.Done, 0)
+		}
+		checkRun.CompletedAt = &done
+		checkRun.Actions = []CheckRunAction{{
+			Label:       "Re-run",
+			Description: "Run this pipeline again on CDS",
+			Identifier:  CheckRunRerunAction,
+		}}
+	}
+
+	//CDS can avoid sending github target url in check runs, if it's disable
+	if !disabledStatusDetail {
+		checkRun.DetailsURL = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d",
+			cdsUIURL,
+			event.ProjectKey,
+			event.WorkflowName,
+			eventNR.Number,
+		)
+	}
+
+	checkRun.Output = &CheckRunOutput{
+		Title:   eventNR.NodeName + ": " + eventNR.Status,
+		Summary: checkRunSummary(eventNR),
+	}
+	for i, a := range eventNR.Annotations {
+		if i == maxCheckRunAnnotations {
+			checkRun.Output.Text = fmt.Sprintf("%d more reports are available on CDS.", len(eventNR.Annotations)-maxCheckRunAnnotations)
+			break
+		}
+		checkRun.Output.Annotations = append(checkRun.Output.Annotations, CheckRunAnnotation{
+			Path:            a.Path,
+			StartLine:       1,
+			EndLine:         1,
+			AnnotationLevel: a.Level,
+			Title:           a.Title,
+			Message:         a.Message,
+		})
+	}
+
+	return checkRun, true
+}
+
+// checkRunSummary returns a markdown table with the status of each job of the node run
+func checkRunSummary(eventNR sdk.EventRunWorkflowNode) string {
+	var buf strings.Builder
+	buf.WriteString("| Stage | Job | Status | Duration |\n")
+	buf.WriteString("| --- | --- | --- | --- |\n")
+	for _, s := range eventNR.StagesSummary {
+		for _, rj := range s.RunJobsSummary {
+			var duration string
+			if rj.Start > 0 && rj.Done >= rj.Start {
+				duration = (time.Duration(rj.Done-rj.Start) * time.Second).String()
+			}
+			fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n", s.Name, rj.Job.JobName, rj.Status, duration)
+		}
+	}
+	return buf.String()
+}
+
+func checkRunExternalID(projectKey, workflowName string, number, nodeID int64) string {
+	return fmt.Sprintf("%s/%s/%d/%d", projectKey, workflowName, number, nodeID)
+}
+
+// ParseCheckRunEvent reads the payload of a check_run webhook, it returns nil if the event is not a "Re-run" request
+func ParseCheckRunEvent(body []byte) (*CheckRunRerun, error) {
+	var e CheckRunEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid check_run payload: %v", err)
+	}
+	if e.Action != "requested_action" || e.RequestedAction == nil || e.RequestedAction.Identifier != CheckRunRerunAction {
+		return nil, nil
+	}
+
+	if e.Sender.ID == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing sender in check_run payload")
+	}
+
+	parts := strings.Split(e.CheckRun.ExternalID, "/")
+	if len(parts) != 4 {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid check run external id %q", e.CheckRun.ExternalID)
+	}
+	number, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid check run external id %q", e.CheckRun.ExternalID)
+	}
+	nodeID, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid check run external id %q", e.CheckRun.ExternalID)
+	}
+
+	return &CheckRunRerun{
+		ProjectKey:   parts[0],
+		WorkflowName: parts[1],
+		Number:       number,
+		NodeID:       nodeID,
+		Username:     e.Sender.Login,
+		UserID:       strconv.Itoa(e.Sender.ID),
+	}, nil
+}
+
+// CheckWebhookSignature checks the signature of a webhook sent by a GitHub App with given secret
+func CheckWebhookSignature(secret string, body []byte, header http.Header) bool {
+	var h func() hash.Hash
+	signature := header.Get("X-Hub-Signature-256")
+	if signature != "" {
+		h = sha256.New
+		signature = strings.TrimPrefix(signature, "sha256=")
+	} else {
+		h = sha1.New
+		signature = strings.TrimPrefix(header.Get("X-Hub-Signature"), "sha1=")
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body) // nolint
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNewCheckRun(t *testing.T) {
+	event := sdk.Event{ProjectKey: "PROJ", WorkflowName: "my-workflow"}
+	eventNR := sdk.EventRunWorkflowNode{
+		Number:   12,
+		NodeID:   42,
+		NodeName: "build",
+		Status:   sdk.StatusFail,
+		Hash:     "abcdef",
+		Start:    1000,
+		Done:     1100,
+		StagesSummary: []sdk.StageSummary{{
+			Name: "Compile",
+			RunJobsSummary: []sdk.WorkflowNodeJobRunSummary{{
+				Status: sdk.StatusFail,
+				Start:  1000,
+				Done:   1090,
+				Job:    sdk.ExecutedJobSummary{JobName: "go build"},
+			}},
+		}},
+		Annotations: []sdk.VCSCheckAnnotation{{
+			Path:    "TestSuite",
+			Level:   sdk.VCSCheckAnnotationFailure,
+			Title:   "Test TestFoo failed",
+			Message: "expected 1, got 2",
+		}},
+	}
+
+	checkRun, ok := newCheckRun(event, eventNR, "https://cds.local", false)
+	require.True(t, ok)
+	assert.Equal(t, "CDS/PROJ-my-workflow-build", checkRun.Name)
+	assert.Equal(t, "abcdef", checkRun.HeadSHA)
+	assert.Equal(t, "PROJ/my-workflow/12/42", checkRun.ExternalID)
+	assert.Equal(t, "completed", checkRun.Status)
+	assert.Equal(t, "failure", checkRun.Conclusion)
+	assert.Equal(t, "https://cds.local/project/PROJ/workflow/my-workflow/run/12", checkRun.DetailsURL)
+	require.Len(t, checkRun.Actions, 1)
+	assert.Equal(t, CheckRunRerunAction, checkRun.Actions[0].Identifier)
+	require.NotNil(t, checkRun.Output)
+	assert.Contains(t, checkRun.Output.Summary, "| Compile | go build | Fail | 1m30s |")
+	require.Len(t, checkRun.Output.Annotations, 1)
+	assert.Equal(t, "failure", checkRun.Output.Annotations[0].AnnotationLevel)
+
+	eventNR.Status = sdk.StatusBuilding
+	checkRun, ok = newCheckRun(event, eventNR, "https://cds.local", true)
+	require.True(t, ok)
+	assert.Equal(t, "in_progress", checkRun.Status)
+	assert.Empty(t, checkRun.Conclusion)
+	assert.Empty(t, checkRun.Actions)
+	assert.Empty(t, checkRun.DetailsURL)
+
+	eventNR.Status = sdk.StatusUnknown
+	_, ok = newCheckRun(event, eventNR, "https://cds.local", false)
+	assert.False(t, ok)
+}
+
+func TestParseCheckRunEvent(t *testing.T) {
+	rerun, err := ParseCheckRunEvent([]byte(`{"action":"requested_action","requested_action":{"identifier":"rerun"},"check_run":{"external_id":"PROJ/my-workflow/12/42"},"sender":{"login":"john","id":1234}}`))
+	require.NoError(t, err)
+	require.NotNil(t, rerun)
+	assert.Equal(t, CheckRunRerun{ProjectKey: "PROJ", WorkflowName: "my-workflow", Number: 12, NodeID: 42, Username: "john", UserID: "1234"}, *rerun)
+
+	rerun, err = ParseCheckRunEvent([]byte(`{"action":"created","check_run":{"external_id":"PROJ/my-workflow/12/42"}}`))
+	require.NoError(t, err)
+	assert.Nil(t, rerun)
+
+	_, err = ParseCheckRunEvent([]byte(`{"action":"requested_action","requested_action":{"identifier":"rerun"},"check_run":{"external_id":"invalid"}}`))
+	assert.Error(t, err)
+}
+
+func TestCheckWebhookSignature(t *testing.T) {
+	body := []byte(`{"action":"requested_action"}`)
+	mac := hmac.New(sha256.New, []byte("my-secret"))
+	mac.Write(body) // nolint
+
+	header := http.Header{}
+	header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	assert.True(t, CheckWebhookSignature("my-secret", body, header))
+	assert.False(t, CheckWebhookSignature("another-secret", body, header))
+	assert.False(t, CheckWebhookSignature("my-secret", body, http.Header{}))
+}
+
+func TestAppInstallationAccessToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		token, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &jwt.StandardClaims{}, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || token.Claims.(*jwt.StandardClaims).Issuer != "42" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/my-org/my-repo/installation":
+			fmt.Fprint(w, `{"id": 7}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/app/installations/7/access_tokens":
+			fmt.Fprintf(w, `{"token": "installation-token", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := &githubClient{
+		GitHubAPIURL:  server.URL,
+		Cache:         cache.NewLocalStore(context.TODO(), 60),
+		appID:         42,
+		appPrivateKey: string(privateKey),
+	}
+
+	token, err := c.appInstallationAccessToken(context.TODO(), "my-org/my-repo")
+	require.NoError(t, err)
+	assert.Equal(t, "installation-token", token)
+	assert.Equal(t, 2, calls)
+
+	// The token is kept in cache until it expires
+	token, err = c.appInstallationAccessToken(context.TODO(), "my-org/my-repo")
+	require.NoError(t, err)
+	assert.Equal(t, "installation-token", token)
+	assert.Equal(t, 2, calls)
+
+	_, err = c.appInstallationAccessToken(context.TODO(), "my-org/unknown")
+	assert.Error(t, err)
+
+	c.appPrivateKey = ""
+	_, err = c.appInstallationAccessToken(context.TODO(), "my-org/other")
+	assert.Error(t, err)
+}
@@ -322,3 +322,44 @@ func (g *githubClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return commits, nil
 }
+
+// ChangedFilesBetweenRefs returns the files changed between base and head, renamed files are listed with their previous name
+func (g *githubClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	var files []string
+	url := fmt.Sprintf("/repos/%s/compare/%s...%s", repo, base, head)
+	k := cache.Key("vcs", "github", "changedfiles", g.OAuthToken, url)
+	status, body, _, err := g.get(ctx, url)
+	if err != nil {
+		log.Warning(ctx, "githubClient.ChangedFilesBetweenRefs> Error %s", err)
+		return nil, err
+	}
+	if status >= 400 {
+		return nil, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+	}
+
+	//Github may return 304 status because we are using conditional request with ETag based headers
+	if status == http.StatusNotModified {
+		if _, err := g.Cache.Get(k, &files); err != nil {
+			log.Error(ctx, "cannot get from cache %s: %v", k, err)
+		}
+		return files, nil
+	}
+
+	var diff DiffCommits
+	if err := json.Unmarshal(body, &diff); err != nil {
+		log.Warning(ctx, "githubClient.ChangedFilesBetweenRefs> Unable to parse github diff: %s", err)
+		return nil, err
+	}
+	files = make([]string, 0, len(diff.Files))
+	for _, f := range diff.Files {
+		files = append(files, f.Filename)
+		if f.PreviousFilename != "" {
+			files = append(files, f.PreviousFilename)
+		}
+	}
+	//Put the body on cache for one hour and one minute
+	if err := g.Cache.SetWithTTL(k, &files, 61*60); err != nil {
+		log.Error(ctx, "cannot SetWithTTL: %s: %v", k, err)
+	}
+	return files, nil
+}
@@ -118,8 +118,13 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 	}
 
 	lastCommitPerBranch := map[string]sdk.VCSCommit{}
+	// The oldest push on each branch gives the commit before all the pushed commits
+	firstPushPerBranch := map[string]Event{}
 	for _, e := range events {
 		branch := strings.Replace(e.Payload.Ref, "refs/heads/", "", 1)
+		if f, has := firstPushPerBranch[branch]; !has || e.CreatedAt.Time.Before(f.CreatedAt.Time) {
+			firstPushPerBranch[branch] = e
+		}
 		for _, c := range e.Payload.Commits {
 			commit := sdk.VCSCommit{
 				Hash:      c.Sha,
@@ -155,6 +160,7 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 		res = append(res, sdk.VCSPushEvent{
 			Branch: *branch,
 			Commit: c,
+			Before: firstPushPerBranch[b].Payload.Before,
 			Repo:   fullname,
 		})
 	}
@@ -35,6 +35,7 @@ func (g *githubClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 		Config: WebHookConfig{
 			URL:         hook.URL,
 			ContentType: "json",
+			Secret:      hook.Secret,
 		},
 	}
 	b, err := json.Marshal(r)
@@ -84,6 +85,8 @@ func (g *githubClient) UpdateHook(ctx context.Context, repo string, hook *sdk.VC
 	}
 
 	githubWebHook.Events = hook.Events
+	// Github returns a masked secret, it must be set again
+	githubWebHook.Config.Secret = hook.Secret
 	b, err := json.Marshal(githubWebHook)
 	if err != nil {
 		return sdk.WrapError(err, "Cannot marshal body %+v", githubWebHook)
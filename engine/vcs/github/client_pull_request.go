@@ -170,6 +170,70 @@ func (g *githubClient) PullRequestComment(ctx context.Context, repo string, id i
 	return nil
 }
 
+// PullRequestComments returns all the comments posted on a pull request
+func (g *githubClient) PullRequestComments(ctx context.Context, repo string, id int) ([]sdk.VCSPullRequestComment, error) {
+	var comments []IssueComment
+	var nextPage = fmt.Sprintf("/repos/%s/issues/%d/comments", repo, id)
+	for nextPage != "" {
+		status, body, headers, err := g.get(ctx, nextPage, withoutETag)
+		if err != nil {
+			log.Warning(ctx, "githubClient.PullRequestComments> Error %s", err)
+			return nil, err
+		}
+		if status >= 400 {
+			return nil, sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+		}
+		var nextComments []IssueComment
+		if err := json.Unmarshal(body, &nextComments); err != nil {
+			return nil, sdk.WrapError(err, "unable to parse github comments")
+		}
+		comments = append(comments, nextComments...)
+		nextPage = getNextPage(headers)
+	}
+
+	author, err := g.commentAuthor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sdk.VCSPullRequestComment, 0, len(comments))
+	for _, c := range comments {
+		res = append(res, sdk.VCSPullRequestComment{
+			ID:   c.ID,
+			Body: c.Body,
+			User: sdk.VCSAuthor{
+				Name:        c.User.Login,
+				DisplayName: c.User.Login,
+				Avatar:      c.User.AvatarURL,
+			},
+			PostedByCDS: c.User.Login == author,
+		})
+	}
+	return res, nil
+}
+
+// PullRequestCommentEdit updates the body of an existing comment on a pull request
+func (g *githubClient) PullRequestCommentEdit(ctx context.Context, repo string, id int, comment sdk.VCSPullRequestComment) error {
+	if g.DisableStatus {
+		log.Warning(ctx, "github.PullRequestCommentEdit>  ⚠ Github statuses are disabled")
+		return nil
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/comments/%d", repo, comment.ID)
+	values, _ := json.Marshal(map[string]string{"body": comment.Body})
+	res, err := g.patch(path, "application/json", bytes.NewReader(values), &postOptions{asUser: true})
+	if err != nil {
+		return sdk.WrapError(err, "unable to edit comment %d", comment.ID)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return sdk.WithStack(fmt.Errorf("unable to edit comment %d on github. Status code : %d - Body: %s", comment.ID, res.StatusCode, body))
+	}
+	return nil
+}
+
 func (g *githubClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	path := fmt.Sprintf("/repos/%s/pulls", repo)
 	payload := map[string]string{
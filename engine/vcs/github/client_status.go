@@ -34,6 +34,10 @@ func (g *githubClient) SetStatus(ctx context.Context, event sdk.Event) error {
 		return nil
 	}
 
+	if g.EnableChecks {
+		return g.setCheckRun(ctx, event)
+	}
+
 	var data statusData
 	var err error
 	switch event.EventType {
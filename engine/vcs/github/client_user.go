@@ -44,3 +44,24 @@ func (g *githubClient) User(ctx context.Context, username string) (User, error)
 
 	return user, nil
 }
+
+// commentAuthor returns the login of the account used to comment pull requests: the configured user, or the OAuth user
+// https://developer.github.com/v3/users/#get-the-authenticated-user
+func (g *githubClient) commentAuthor(ctx context.Context) (string, error) {
+	if g.token != "" {
+		return g.username, nil
+	}
+	status, body, _, err := g.get(ctx, "/user", withoutETag)
+	if err != nil {
+		log.Warning(ctx, "githubClient.commentAuthor> Error %s", err)
+		return "", err
+	}
+	if status >= 400 {
+		return "", sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+	}
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return "", sdk.WrapError(err, "unable to parse github user")
+	}
+	return user.Login, nil
+}
@@ -15,12 +15,15 @@ type githubClient struct {
 	OAuthToken          string
 	DisableStatus       bool
 	DisableStatusDetail bool
+	EnableChecks        bool
 	Cache               cache.Store
 	apiURL              string
 	uiURL               string
 	proxyURL            string
 	username            string
 	token               string
+	appID               int64
+	appPrivateKey       string
 }
 
 //GithubConsumer implements vcs.Server and it's used to instantiate a githubClient
@@ -35,12 +38,15 @@ type githubConsumer struct {
 	proxyURL            string
 	disableStatus       bool
 	disableStatusDetail bool
+	enableChecks        bool
 	username            string
 	token               string
+	appID               int64
+	appPrivateKey       string
 }
 
-//New creates a new GithubConsumer
-func New(ClientID, ClientSecret, githubURL, githubAPIURL, apiURL, uiURL, proxyURL, username, token string, store cache.Store, disableStatus, disableStatusDetail bool) sdk.VCSServer {
+//New creates a new GithubConsumer, the GitHub App is used to post check runs when checks are enabled
+func New(ClientID, ClientSecret, githubURL, githubAPIURL, apiURL, uiURL, proxyURL, username, token string, store cache.Store, disableStatus, disableStatusDetail, enableChecks bool, appID int64, appPrivateKey string) sdk.VCSServer {
 	//Github const
 	const (
 		publicURL    = "https://github.com"
@@ -65,8 +71,11 @@ func New(ClientID, ClientSecret, githubURL, githubAPIURL, apiURL, uiURL, proxyUR
 		proxyURL:            proxyURL,
 		disableStatus:       disableStatus,
 		disableStatusDetail: disableStatusDetail,
+		enableChecks:        enableChecks,
 		username:            username,
 		token:               token,
+		appID:               appID,
+		appPrivateKey:       appPrivateKey,
 	}
 }
 
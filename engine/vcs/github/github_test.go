@@ -35,12 +35,12 @@ func getNewConsumer(t *testing.T) sdk.VCSServer {
 		t.SkipNow()
 	}
 
-	cache, err := cache.New(redisHost, redisPassword, 30)
+	cache, err := cache.New(context.TODO(), cache.ModeRedis, redisHost, redisPassword, 30)
 	if err != nil {
 		t.Fatalf("Unable to init cache (%s): %v", redisHost, err)
 	}
 
-	ghConsummer := New(clientID, clientSecret, "", "", "http://localhost", "", "", "", "", cache, true, true)
+	ghConsummer := New(clientID, clientSecret, "", "", "http://localhost", "", "", "", "", cache, true, true, false, 0, "")
 	return ghConsummer
 }
 
@@ -58,12 +58,12 @@ func getNewAuthorizedClient(t *testing.T) sdk.VCSAuthorizedClient {
 		t.SkipNow()
 	}
 
-	cache, err := cache.New(redisHost, redisPassword, 30)
+	cache, err := cache.New(context.TODO(), cache.ModeRedis, redisHost, redisPassword, 30)
 	if err != nil {
 		t.Fatalf("Unable to init cache (%s): %v", redisHost, err)
 	}
 
-	ghConsummer := New(clientID, clientSecret, "", "", "http://localhost", "", "", "", "", cache, true, true)
+	ghConsummer := New(clientID, clientSecret, "", "", "http://localhost", "", "", "", "", cache, true, true, false, 0, "")
 	cli, err := ghConsummer.GetAuthorizedClient(context.Background(), accessToken, "", 0)
 	if err != nil {
 		t.Fatalf("Unable to init authorized client (%s): %v", redisHost, err)
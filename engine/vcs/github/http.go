@@ -123,6 +123,8 @@ func withoutETag(ctx context.Context, c *githubClient, req *http.Request, path s
 type postOptions struct {
 	skipDefaultBaseURL bool
 	asUser             bool
+	accept             string
+	token              string // overrides the OAuth token, ie. with a GitHub App installation token
 }
 
 func (c *githubClient) post(path string, bodyType string, body io.Reader, opts *postOptions) (*http.Response, error) {
@@ -140,8 +142,14 @@ func (c *githubClient) post(path string, bodyType string, body io.Reader, opts *
 
 	req.Header.Set("Content-Type", bodyType)
 	req.Header.Set("User-Agent", "CDS-gh_client_id="+c.ClientID)
-	req.Header.Add("Accept", "application/json")
-	if opts.asUser && c.token != "" {
+	if opts.accept != "" {
+		req.Header.Add("Accept", opts.accept)
+	} else {
+		req.Header.Add("Accept", "application/json")
+	}
+	if opts.token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("token %s", opts.token))
+	} else if opts.asUser && c.token != "" {
 		req.SetBasicAuth(c.username, c.token)
 	} else {
 		req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
@@ -169,8 +177,14 @@ func (c *githubClient) patch(path string, bodyType string, body io.Reader, opts
 		req.Header.Set("Content-Type", bodyType)
 	}
 	req.Header.Set("User-Agent", "CDS-gh_client_id="+c.ClientID)
-	req.Header.Add("Accept", "application/json")
-	if opts.asUser && c.token != "" {
+	if opts.accept != "" {
+		req.Header.Add("Accept", opts.accept)
+	} else {
+		req.Header.Add("Accept", "application/json")
+	}
+	if opts.token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("token %s", opts.token))
+	} else if opts.asUser && c.token != "" {
 		req.SetBasicAuth(c.username, c.token)
 	} else {
 		req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
@@ -196,8 +210,14 @@ func (c *githubClient) put(path string, bodyType string, body io.Reader, opts *p
 
 	req.Header.Set("Content-Type", bodyType)
 	req.Header.Set("User-Agent", "CDS-gh_client_id="+c.ClientID)
-	req.Header.Add("Accept", "application/json")
-	if opts.asUser && c.token != "" {
+	if opts.accept != "" {
+		req.Header.Add("Accept", opts.accept)
+	} else {
+		req.Header.Add("Accept", "application/json")
+	}
+	if opts.token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("token %s", opts.token))
+	} else if opts.asUser && c.token != "" {
 		req.SetBasicAuth(c.username, c.token)
 	} else {
 		req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
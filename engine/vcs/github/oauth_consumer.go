@@ -93,10 +93,13 @@ func (g *githubConsumer) GetAuthorizedClient(ctx context.Context, accessToken, a
 			uiURL:               g.uiURL,
 			DisableStatus:       g.disableStatus,
 			DisableStatusDetail: g.disableStatusDetail,
+			EnableChecks:        g.enableChecks,
 			apiURL:              g.apiURL,
 			proxyURL:            g.proxyURL,
 			username:            g.username,
 			token:               g.token,
+			appID:               g.appID,
+			appPrivateKey:       g.appPrivateKey,
 		}
 		instancesAuthorizedClient[accessToken] = c
 	}
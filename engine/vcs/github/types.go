@@ -63,6 +63,7 @@ type Webhook struct {
 	Config  struct {
 		URL         string `json:"url"`
 		ContentType string `json:"content_type"`
+		Secret      string `json:"secret,omitempty"`
 	} `json:"config"`
 	UpdatedAt time.Time `json:"updated_at"`
 	CreatedAt time.Time `json:"created_at"`
@@ -72,6 +73,7 @@ type Webhook struct {
 type WebHookConfig struct {
 	URL         string `json:"url"`
 	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
 }
 
 // User represents a GitHub user.
@@ -712,16 +714,17 @@ type DiffCommits struct {
 	TotalCommits int      `json:"total_commits"`
 	Commits      []Commit `json:"commits"`
 	Files        []struct {
-		Sha         string `json:"sha"`
-		Filename    string `json:"filename"`
-		Status      string `json:"status"`
-		Additions   int    `json:"additions"`
-		Deletions   int    `json:"deletions"`
-		Changes     int    `json:"changes"`
-		BlobURL     string `json:"blob_url"`
-		RawURL      string `json:"raw_url"`
-		ContentsURL string `json:"contents_url"`
-		Patch       string `json:"patch"`
+		Sha              string `json:"sha"`
+		Filename         string `json:"filename"`
+		PreviousFilename string `json:"previous_filename"`
+		Status           string `json:"status"`
+		Additions        int    `json:"additions"`
+		Deletions        int    `json:"deletions"`
+		Changes          int    `json:"changes"`
+		BlobURL          string `json:"blob_url"`
+		RawURL           string `json:"raw_url"`
+		ContentsURL      string `json:"contents_url"`
+		Patch            string `json:"patch"`
 	} `json:"files"`
 }
 
@@ -735,3 +738,63 @@ type Ref struct {
 		URL  string `json:"url"`
 	} `json:"object"`
 }
+
+// CheckRun represents a GitHub check run, https://developer.github.com/v3/checks/runs/
+type CheckRun struct {
+	ID          int64            `json:"id,omitempty"`
+	Name        string           `json:"name,omitempty"`
+	HeadSHA     string           `json:"head_sha,omitempty"`
+	DetailsURL  string           `json:"details_url,omitempty"`
+	ExternalID  string           `json:"external_id,omitempty"`
+	Status      string           `json:"status,omitempty"`
+	Conclusion  string           `json:"conclusion,omitempty"`
+	StartedAt   *time.Time       `json:"started_at,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	Output      *CheckRunOutput  `json:"output,omitempty"`
+	Actions     []CheckRunAction `json:"actions,omitempty"`
+}
+
+// CheckRunOutput is the description of a check run
+type CheckRunOutput struct {
+	Title       string               `json:"title"`
+	Summary     string               `json:"summary"`
+	Text        string               `json:"text,omitempty"`
+	Annotations []CheckRunAnnotation `json:"annotations,omitempty"`
+}
+
+// CheckRunAnnotation is a line reported on a check run
+type CheckRunAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// CheckRunAction is a button displayed on a check run
+type CheckRunAction struct {
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	Identifier  string `json:"identifier"`
+}
+
+// CheckRunEvent is the payload of the check_run webhook
+type CheckRunEvent struct {
+	Action          string   `json:"action"`
+	CheckRun        CheckRun `json:"check_run"`
+	RequestedAction *struct {
+		Identifier string `json:"identifier"`
+	} `json:"requested_action,omitempty"`
+	Repository Repository `json:"repository"`
+	Sender     User       `json:"sender"`
+}
+
+// IssueComment is a comment posted on an issue or a pull request
+type IssueComment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      User      `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
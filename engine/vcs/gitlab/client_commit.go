@@ -105,3 +105,28 @@ func (c *gitlabClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return vcscommits, nil
 }
+
+// ChangedFilesBetweenRefs returns the files changed between base and head, renamed files are listed with their previous name
+func (c *gitlabClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	opt := &gitlab.CompareOptions{
+		From: &base,
+		To:   &head,
+	}
+
+	compare, _, err := c.client.Repositories.Compare(repo, opt)
+	if err != nil {
+		return nil, err
+	}
+	if compare == nil {
+		return nil, nil
+	}
+
+	files := make([]string, 0, len(compare.Diffs))
+	for _, d := range compare.Diffs {
+		files = append(files, d.NewPath)
+		if d.RenamedFile {
+			files = append(files, d.OldPath)
+		}
+	}
+	return files, nil
+}
@@ -95,6 +95,9 @@ func (c *gitlabClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 		JobEvents:             &jobEvent,
 		EnableSSLVerification: &f,
 	}
+	if hook.Secret != "" {
+		opt.Token = &hook.Secret
+	}
 
 	log.Debug("GitlabClient.CreateHook: %s %s\n", repo, *opt.URL)
 	ph, resp, err := c.client.Projects.AddProjectHook(repo, &opt)
@@ -154,6 +157,9 @@ func (c *gitlabClient) UpdateHook(ctx context.Context, repo string, hook *sdk.VC
 		EnableSSLVerification:    &gitlabHook.EnableSSLVerification,
 		ConfidentialIssuesEvents: &gitlabHook.ConfidentialIssuesEvents,
 	}
+	if hook.Secret != "" {
+		opt.Token = &hook.Secret
+	}
 
 	log.Debug("GitlabClient.UpdateHook: %s %s", repo, *opt.URL)
 	_, resp, err := c.client.Projects.EditProjectHook(repo, gitlabHook.ID, &opt)
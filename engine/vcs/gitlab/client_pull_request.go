@@ -4,20 +4,106 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/xanzy/go-gitlab"
+
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
+// PullRequest returns the merge request with the given project scoped id
 func (c *gitlabClient) PullRequest(ctx context.Context, repo string, id int) (sdk.VCSPullRequest, error) {
-	return sdk.VCSPullRequest{}, nil
+	mr, _, err := c.client.MergeRequests.GetMergeRequest(repo, id, nil)
+	if err != nil {
+		return sdk.VCSPullRequest{}, sdk.WrapError(err, "unable to get merge request %d", id)
+	}
+	return toVCSPullRequest(repo, mr), nil
 }
 
-// PullRequests fetch all the pull request for a repository
-func (c *gitlabClient) PullRequests(context.Context, string) ([]sdk.VCSPullRequest, error) {
-	return []sdk.VCSPullRequest{}, nil
+// PullRequests fetch all the opened merge requests for a repository
+func (c *gitlabClient) PullRequests(ctx context.Context, repo string) ([]sdk.VCSPullRequest, error) {
+	state := "opened"
+	opts := &gitlab.ListProjectMergeRequestsOptions{State: &state}
+	opts.PerPage = 100
+
+	var prs []sdk.VCSPullRequest
+	for {
+		mrs, resp, err := c.client.MergeRequests.ListProjectMergeRequests(repo, opts)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to list merge requests")
+		}
+		for _, mr := range mrs {
+			prs = append(prs, toVCSPullRequest(repo, mr))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return prs, nil
 }
 
 // PullRequestComment push a new comment on a pull request
-func (c *gitlabClient) PullRequestComment(context.Context, string, int, string) error {
+func (c *gitlabClient) PullRequestComment(ctx context.Context, repo string, id int, text string) error {
+	if c.disableStatus {
+		log.Warning(ctx, "gitlab.PullRequestComment>  ⚠ Gitlab statuses are disabled")
+		return nil
+	}
+	if _, _, err := c.client.Notes.CreateMergeRequestNote(repo, id, &gitlab.CreateMergeRequestNoteOptions{Body: &text}); err != nil {
+		return sdk.WrapError(err, "unable to comment merge request %d", id)
+	}
+	return nil
+}
+
+// PullRequestComments returns all the notes posted on a merge request
+func (c *gitlabClient) PullRequestComments(ctx context.Context, repo string, id int) ([]sdk.VCSPullRequestComment, error) {
+	// Notes are posted with the OAuth user
+	author, _, err := c.client.Users.CurrentUser()
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to get current user")
+	}
+
+	opts := &gitlab.ListMergeRequestNotesOptions{}
+	opts.PerPage = 100
+
+	var comments []sdk.VCSPullRequestComment
+	for {
+		notes, resp, err := c.client.Notes.ListMergeRequestNotes(repo, id, opts)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to list notes of merge request %d", id)
+		}
+		for _, n := range notes {
+			comments = append(comments, sdk.VCSPullRequestComment{
+				ID:   int64(n.ID),
+				Body: n.Body,
+				User: sdk.VCSAuthor{
+					Name:        n.Author.Username,
+					DisplayName: n.Author.Name,
+					Email:       n.Author.Email,
+					Avatar:      n.Author.AvatarURL,
+				},
+				PostedByCDS: n.Author.ID == author.ID,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return comments, nil
+}
+
+// PullRequestCommentEdit updates the body of an existing note on a merge request
+func (c *gitlabClient) PullRequestCommentEdit(ctx context.Context, repo string, id int, comment sdk.VCSPullRequestComment) error {
+	if c.disableStatus {
+		log.Warning(ctx, "gitlab.PullRequestCommentEdit>  ⚠ Gitlab statuses are disabled")
+		return nil
+	}
+	opts := &gitlab.UpdateMergeRequestNoteOptions{Body: &comment.Body}
+	if _, _, err := c.client.Notes.UpdateMergeRequestNote(repo, id, int(comment.ID), opts); err != nil {
+		return sdk.WrapError(err, "unable to edit note %d of merge request %d", comment.ID, id)
+	}
 	return nil
 }
 
@@ -25,3 +111,33 @@ func (c *gitlabClient) PullRequestComment(context.Context, string, int, string)
 func (c *gitlabClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	return sdk.VCSPullRequest{}, fmt.Errorf("not yet implemented")
 }
+
+func toVCSPullRequest(repo string, mr *gitlab.MergeRequest) sdk.VCSPullRequest {
+	return sdk.VCSPullRequest{
+		ID:    mr.IID,
+		URL:   mr.WebURL,
+		Title: mr.Title,
+		User: sdk.VCSAuthor{
+			Name:        mr.Author.Username,
+			DisplayName: mr.Author.Name,
+		},
+		Head: sdk.VCSPushEvent{
+			Repo: repo,
+			Branch: sdk.VCSBranch{
+				ID:           mr.SourceBranch,
+				DisplayID:    mr.SourceBranch,
+				LatestCommit: mr.SHA,
+			},
+			Commit: sdk.VCSCommit{Hash: mr.SHA},
+		},
+		Base: sdk.VCSPushEvent{
+			Repo: repo,
+			Branch: sdk.VCSBranch{
+				ID:        mr.TargetBranch,
+				DisplayID: mr.TargetBranch,
+			},
+		},
+		Merged: mr.State == "merged",
+		Closed: mr.State == "closed",
+	}
+}
@@ -33,7 +33,8 @@ type Configuration struct {
 	} `toml:"ui" json:"ui"`
 	API   service.APIServiceConfiguration `toml:"api" comment:"######################\n CDS API Settings \n######################" json:"api"`
 	Cache struct {
-		TTL   int `toml:"ttl" default:"60" json:"ttl"`
+		TTL   int    `toml:"ttl" default:"60" json:"ttl"`
+		Mode  string `toml:"mode" default:"redis" comment:"redis or local. With local, the cache is kept in memory and redis settings are ignored, use it only for a single instance installation" json:"mode"`
 		Redis struct {
 			Host     string `toml:"host" default:"localhost:6379" comment:"If your want to use a redis-sentinel based cluster, follow this syntax ! <clustername>@sentinel1:26379,sentinel2:26379sentinel3:26379" json:"host"`
 			Password string `toml:"password" json:"-"`
@@ -50,6 +51,7 @@ type ServerConfiguration struct {
 	Bitbucket      *BitbucketServerConfiguration `toml:"bitbucket" json:"bitbucket,omitempty"`
 	BitbucketCloud *BitbucketCloudConfiguration  `toml:"bitbucketcloud" json:"bitbucketcloud,omitempty"`
 	Gerrit         *GerritServerConfiguration    `toml:"gerrit" json:"gerrit,omitempty"`
+	Gitea          *GiteaServerConfiguration     `toml:"gitea" json:"gitea,omitempty"`
 }
 
 // GithubServerConfiguration represents the github configuration
@@ -61,6 +63,12 @@ type GithubServerConfiguration struct {
 		Disable    bool `toml:"disable" default:"false" commented:"true" comment:"Set to true if you don't want CDS to push statuses on the VCS server" json:"disable"`
 		ShowDetail bool `toml:"showDetail" default:"false" commented:"true" comment:"Set to true if you don't want CDS to push CDS URL in statuses on the VCS server" json:"show_detail"`
 	}
+	Checks struct {
+		Enable        bool   `toml:"enable" default:"false" commented:"true" comment:"Set to true to report workflow node runs as GitHub check runs instead of commit statuses. The GitHub App must be installed on the repositories" json:"enable"`
+		AppID         int64  `toml:"appId" default:"0" commented:"true" comment:"ID of the GitHub App, check runs can only be created by a GitHub App" json:"-"`
+		AppPrivateKey string `toml:"appPrivateKey" default:"" commented:"true" comment:"Private key of the GitHub App (PEM format), used to get the installation access tokens" json:"-"`
+		WebhookSecret string `toml:"webhookSecret" default:"" commented:"true" comment:"Secret of the GitHub App webhook, used to check 'Re-run' requests sent by GitHub on /vcs/<name>/checks/webhook" json:"-"`
+	}
 	DisableWebHooks bool   `toml:"disableWebHooks" comment:"Does webhooks are supported by VCS Server" json:"disable_web_hook"`
 	DisablePolling  bool   `toml:"disablePolling" comment:"Does polling is supported by VCS Server" json:"disable_polling"`
 	ProxyWebhook    string `toml:"proxyWebhook" default:"" commented:"true" comment:"If you want to have a reverse proxy url for your repository webhook, for example if you put https://myproxy.com it will generate a webhook URL like this https://myproxy.com/UUID_OF_YOUR_WEBHOOK" json:"proxy_webhook"`
@@ -78,6 +86,9 @@ func (s GithubServerConfiguration) check() error {
 	if s.ProxyWebhook != "" && !strings.Contains(s.ProxyWebhook, "://") {
 		return fmt.Errorf("Github proxy webhook must have the HTTP scheme")
 	}
+	if s.Checks.Enable && (s.Checks.AppID == 0 || s.Checks.AppPrivateKey == "") {
+		return fmt.Errorf("Github checks need the ID and the private key of a GitHub App")
+	}
 	return nil
 }
 
@@ -104,6 +115,31 @@ func (s GitlabServerConfiguration) check() error {
 	return nil
 }
 
+// GiteaServerConfiguration represents the gitea (or forgejo) configuration
+type GiteaServerConfiguration struct {
+	ClientID     string `toml:"clientId" json:"-" default:"xxxxx" comment:"#######\n CDS <-> Gitea. Documentation on https://ovh.github.io/cds/docs/integrations/gitea/ \n#######\n Gitea OAuth2 Application Client ID"`
+	ClientSecret string `toml:"clientSecret" json:"-" default:"xxxxx" comment:"Gitea OAuth2 Application Client Secret"`
+	CallbackURL  string `toml:"callbackUrl" json:"callbackUrl" default:"http://localhost:8081/repositories_manager/oauth2/callback" comment:"OAuth2 Application Redirect URI"`
+	Status       struct {
+		Disable    bool `toml:"disable" default:"false" commented:"true" comment:"Set to true if you don't want CDS to push statuses on the VCS server" json:"disable"`
+		ShowDetail bool `toml:"showDetail" default:"false" commented:"true" comment:"Set to true if you don't want CDS to push CDS URL in statuses on the VCS server" json:"show_detail"`
+	}
+	DisableWebHooks bool   `toml:"disableWebHooks" comment:"Does webhooks are supported by VCS Server" json:"disable_web_hook"`
+	ProxyWebhook    string `toml:"proxyWebhook" default:"" commented:"true" comment:"If you want to have a reverse proxy url for your repository webhook, for example if you put https://myproxy.com it will generate a webhook URL like this https://myproxy.com/UUID_OF_YOUR_WEBHOOK" json:"proxy_webhook"`
+	Username        string `toml:"username" comment:"optional. Gitea username, used to add comment on Pull Request on failed build." json:"username"`
+	Token           string `toml:"token" comment:"optional, Gitea Token associated to username, used to add comment on Pull Request" json:"-"`
+}
+
+func (s GiteaServerConfiguration) check() error {
+	if s.ClientID == "" || s.ClientSecret == "" {
+		return fmt.Errorf("Gitea configuration Error")
+	}
+	if s.ProxyWebhook != "" && !strings.Contains(s.ProxyWebhook, "://") {
+		return fmt.Errorf("Gitea proxy webhook must have the HTTP scheme")
+	}
+	return nil
+}
+
 // BitbucketServerConfiguration represents the bitbucket configuration
 type BitbucketServerConfiguration struct {
 	ConsumerKey string `toml:"consumerKey" json:"-" default:"xxxxx" comment:"#######\n CDS <-> Bitbucket. Documentation on https://ovh.github.io/cds/hosting/repositories-manager/bitbucket/ \n#######\n You can change the consumeKey if you want"`
@@ -195,6 +231,12 @@ func (s ServerConfiguration) check() error {
 		}
 	}
 
+	if s.Gitea != nil {
+		if err := s.Gitea.check(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
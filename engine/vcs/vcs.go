@@ -15,6 +15,7 @@ import (
 	"github.com/ovh/cds/engine/vcs/bitbucketserver"
 	"github.com/ovh/cds/engine/vcs/gerrit"
 	"github.com/ovh/cds/engine/vcs/github"
+	"github.com/ovh/cds/engine/vcs/gitea"
 	"github.com/ovh/cds/engine/vcs/gitlab"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -101,6 +102,9 @@ func (s *Service) getConsumer(name string) (sdk.VCSServer, error) {
 			s.Cache,
 			serverCfg.Github.Status.Disable,
 			!serverCfg.Github.Status.ShowDetail,
+			serverCfg.Github.Checks.Enable,
+			serverCfg.Github.Checks.AppID,
+			serverCfg.Github.Checks.AppPrivateKey,
 		), nil
 	}
 	if serverCfg.Bitbucket != nil {
@@ -139,6 +143,20 @@ func (s *Service) getConsumer(name string) (sdk.VCSServer, error) {
 			serverCfg.Gitlab.Status.ShowDetail,
 		), nil
 	}
+	if serverCfg.Gitea != nil {
+		return gitea.New(serverCfg.Gitea.ClientID,
+			serverCfg.Gitea.ClientSecret,
+			serverCfg.URL,
+			serverCfg.Gitea.CallbackURL,
+			s.Cfg.UI.HTTP.URL,
+			serverCfg.Gitea.ProxyWebhook,
+			serverCfg.Gitea.Username,
+			serverCfg.Gitea.Token,
+			s.Cache,
+			serverCfg.Gitea.Status.Disable,
+			!serverCfg.Gitea.Status.ShowDetail,
+		), nil
+	}
 	if serverCfg.Gerrit != nil {
 		return gerrit.New(
 			serverCfg.URL,
@@ -159,7 +177,7 @@ func (s *Service) Serve(c context.Context) error {
 
 	//Init the cache
 	var errCache error
-	s.Cache, errCache = cache.New(s.Cfg.Cache.Redis.Host, s.Cfg.Cache.Redis.Password, s.Cfg.Cache.TTL)
+	s.Cache, errCache = cache.New(c, s.Cfg.Cache.Mode, s.Cfg.Cache.Redis.Host, s.Cfg.Cache.Redis.Password, s.Cfg.Cache.TTL)
 	if errCache != nil {
 		return fmt.Errorf("Cannot connect to redis instance : %v", errCache)
 	}
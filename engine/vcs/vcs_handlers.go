@@ -3,6 +3,7 @@ package vcs
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -38,6 +39,8 @@ func (s *Service) getAllVCSServersHandler() service.Handler {
 				vcsType = "github"
 			} else if v.Gitlab != nil {
 				vcsType = "gitlab"
+			} else if v.Gitea != nil {
+				vcsType = "gitea"
 			}
 
 			servers[k] = sdk.VCSConfiguration{
@@ -46,6 +49,7 @@ func (s *Service) getAllVCSServersHandler() service.Handler {
 				Password: privateKey,
 				URL:      v.URL,
 				SSHPort:  sshPort,
+				Checks:   v.Github != nil && v.Github.Checks.Enable,
 			}
 		}
 		return service.WriteJSON(w, servers, http.StatusOK)
@@ -68,8 +72,11 @@ func (s *Service) getVCSServersHandler() service.Handler {
 			s.Type = "bitbucket"
 		} else if cfg.Github != nil {
 			s.Type = "github"
+			s.Checks = cfg.Github.Checks.Enable
 		} else if cfg.Gitlab != nil {
 			s.Type = "gitlab"
+		} else if cfg.Gitea != nil {
+			s.Type = "gitea"
 		}
 		return service.WriteJSON(w, s, http.StatusOK)
 	}
@@ -207,6 +214,22 @@ func (s *Service) getVCSServersHooksHandler() service.Handler {
 				"Pipeline Hook",
 				"Job Hook",
 			}
+		case cfg.Gitea != nil:
+			res.WebhooksSupported = true
+			res.WebhooksDisabled = cfg.Gitea.DisableWebHooks
+			res.WebhooksIcon = sdk.GiteaIcon
+			// https://docs.gitea.io/en-us/webhooks/
+			res.Events = []string{
+				"push",
+				"create",
+				"delete",
+				"fork",
+				"issues",
+				"issue_comment",
+				"pull_request",
+				"release",
+				"repository",
+			}
 		case cfg.Gerrit != nil:
 			res.WebhooksSupported = false
 			res.GerritHookDisabled = cfg.Gerrit.DisableGerritEvent
@@ -262,6 +285,8 @@ func (s *Service) getVCSServersPollingHandler() service.Handler {
 		case cfg.Gitlab != nil:
 			res.PollingSupported = false
 			res.PollingDisabled = cfg.Gitlab.DisablePolling
+		case cfg.Gitea != nil:
+			res.PollingSupported = false
 		}
 
 		return service.WriteJSON(w, res, http.StatusOK)
@@ -556,6 +581,41 @@ func (s *Service) getCommitsBetweenRefsHandler() service.Handler {
 	}
 }
 
+func (s *Service) getChangedFilesBetweenRefsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		base := r.URL.Query().Get("base")
+		head := r.URL.Query().Get("head")
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> getChangedFilesBetweenRefsHandler> Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		files, err := client.ChangedFilesBetweenRefs(ctx, fmt.Sprintf("%s/%s", owner, repo), base, head)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get changed files of %s/%s between %s and %s", owner, repo, base, head)
+		}
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
+
 func (s *Service) getCommitHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
@@ -777,6 +837,92 @@ func (s *Service) postPullRequestCommentHandler() service.Handler {
 	}
 }
 
+func (s *Service) getPullRequestCommentsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		sid := muxVar(r, "id")
+		id, err := strconv.Atoi(sid)
+		if err != nil {
+			return sdk.ErrWrongRequest
+		}
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		comments, err := client.PullRequestComments(ctx, fmt.Sprintf("%s/%s", owner, repo), id)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get PR comments %s %s/%s", name, owner, repo)
+		}
+
+		return service.WriteJSON(w, comments, http.StatusOK)
+	}
+}
+
+func (s *Service) putPullRequestCommentHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		id, err := strconv.Atoi(muxVar(r, "id"))
+		if err != nil {
+			return sdk.ErrWrongRequest
+		}
+		commentID, err := strconv.ParseInt(muxVar(r, "commentID"), 10, 64)
+		if err != nil {
+			return sdk.ErrWrongRequest
+		}
+
+		var comment sdk.VCSPullRequestComment
+		if err := service.UnmarshalBody(r, &comment); err != nil {
+			return sdk.WithStack(err)
+		}
+		comment.ID = commentID
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		if err := client.PullRequestCommentEdit(ctx, fmt.Sprintf("%s/%s", owner, repo), id, comment); err != nil {
+			return sdk.WrapError(err, "Unable to edit PR comment %s %s/%s", name, owner, repo)
+		}
+
+		return nil
+	}
+}
+
 func (s *Service) getEventsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
@@ -926,6 +1072,51 @@ func (s *Service) postStatusHandler() service.Handler {
 	}
 }
 
+func (s *Service) postChecksWebhookHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		cfg, ok := s.Cfg.Servers[name]
+		if !ok || cfg.Github == nil || !cfg.Github.Checks.Enable {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		if cfg.Github.Checks.WebhookSecret == "" {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "checks webhook secret is not set for %s", name)
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to read body")
+		}
+		if !github.CheckWebhookSignature(cfg.Github.Checks.WebhookSecret, body, r.Header) {
+			return sdk.WithStack(sdk.ErrUnauthorized)
+		}
+		if r.Header.Get("X-GitHub-Event") != "check_run" {
+			return nil
+		}
+
+		rerun, err := github.ParseCheckRunEvent(body)
+		if err != nil {
+			return err
+		}
+		if rerun == nil {
+			return nil
+		}
+
+		// The API runs the node again on behalf of the CDS user linked to the GitHub sender, only if this user can execute it
+		checkRerun := sdk.WorkflowCheckRunRerun{
+			FromNodeID:     rerun.NodeID,
+			ConsumerType:   sdk.ConsumerGithub,
+			ExternalUserID: rerun.UserID,
+		}
+		if _, err := s.Client.WorkflowRunFromCheckRun(rerun.ProjectKey, rerun.WorkflowName, rerun.Number, checkRerun); err != nil {
+			return sdk.WrapError(err, "Unable to run again workflow %s/%s #%d from node %d", rerun.ProjectKey, rerun.WorkflowName, rerun.Number, rerun.NodeID)
+		}
+		log.Info(ctx, "postChecksWebhookHandler> workflow %s/%s #%d run again from node %d by %s", rerun.ProjectKey, rerun.WorkflowName, rerun.Number, rerun.NodeID, rerun.Username)
+
+		return service.WriteJSON(w, nil, http.StatusAccepted)
+	}
+}
+
 func (s *Service) postReleaseHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
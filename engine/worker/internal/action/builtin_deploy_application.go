@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
@@ -84,32 +85,195 @@ func RunDeployApplication(ctx context.Context, wk workerruntime.Runtime, a sdk.A
 
 	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("# Plugin %s v%s is ready", manifest.Name, manifest.Version))
 
-	query := integrationplugin.DeployQuery{
-		Options: sdk.ParametersToMap(wk.Parameters()),
+	opts, err := newDeployOptions(a)
+	if err != nil {
+		integrationPluginClientStop(ctx, integrationPluginClient, done, stopLogs)
+		return sdk.Result{}, err
 	}
 
-	res, err := integrationPluginClient.Deploy(ctx, &query)
+	res, deployment, err := runDeployment(ctx, wk, integrationPluginClient, sdk.ParametersToMap(wk.Parameters()), opts)
+	integrationPluginClientStop(ctx, integrationPluginClient, done, stopLogs)
+
+	// Failed attempts are recorded too, unless the step failed before calling the plugin
+	if deployment.Status != "" {
+		if err := wk.Client().QueueSendDeployment(ctx, jobID, deployment); err != nil {
+			wk.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("# Unable to record the deployment in the application history: %v", err))
+		}
+	}
 	if err != nil {
-		integrationPluginClientStop(ctx, integrationPluginClient, done, stopLogs)
-		return sdk.Result{}, fmt.Errorf("Error deploying application: %v", err)
+		return sdk.Result{}, err
+	}
+
+	return res, nil
+}
+
+// deployStatusInterval is the delay between two checks of the status of a progressive deployment
+var deployStatusInterval = 10 * time.Second
+
+// deployOptions are the parameters of a DeployApplication step
+type deployOptions struct {
+	strategy string
+	steps    []int32
+	wait     time.Duration
+}
+
+func newDeployOptions(a sdk.Action) (deployOptions, error) {
+	opts := deployOptions{strategy: sdk.ParameterValue(a.Parameters, "strategy")}
+	if opts.strategy == "" {
+		opts.strategy = sdk.DeploymentStrategyDirect
+	}
+
+	var err error
+	opts.steps, err = sdk.DeploymentSteps(opts.strategy, sdk.ParameterValue(a.Parameters, "steps"))
+	if err != nil {
+		return opts, err
+	}
+
+	if wait := sdk.ParameterValue(a.Parameters, "wait"); wait != "" {
+		opts.wait, err = time.ParseDuration(wait)
+		if err != nil {
+			return opts, fmt.Errorf("invalid wait duration %q: %v", wait, err)
+		}
+	}
+	return opts, nil
+}
+
+// runDeployment deploys the application with the integration plugin, or restores a previous version when the
+// node run was relaunched by a rollback. With a canary or blue-green strategy, the release is promoted step by step
+// and aborted as soon as the plugin reports a failure.
+func runDeployment(ctx context.Context, wk workerruntime.Runtime, c integrationplugin.IntegrationPluginClient, options map[string]string, opts deployOptions) (sdk.Result, sdk.ApplicationDeployment, error) {
+	deployment := sdk.ApplicationDeployment{
+		Strategy: opts.strategy,
+		Version:  options["cds.version"],
+		Author:   options["cds.triggered_by.username"],
+	}
+
+	var res *integrationplugin.DeployResult
+	var err error
+	if version := options[sdk.DeployRollbackVersionParameter]; version != "" {
+		deployment.Strategy = sdk.DeploymentStrategyRollback
+		deployment.Version = version
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("# Rolling back to version %s", version))
+		res, err = c.Rollback(ctx, &integrationplugin.RollbackQuery{Options: options, Version: version})
+		if err != nil {
+			err = fmt.Errorf("Error rolling back application: %v", err)
+			deployment.Status = sdk.StatusFail
+			deployment.Details = err.Error()
+			return sdk.Result{}, deployment, err
+		}
+		opts.steps = nil
+	} else {
+		query := integrationplugin.DeployQuery{Options: options}
+		if len(opts.steps) > 0 {
+			query.Strategy = opts.strategy
+			query.Weight = opts.steps[0]
+			wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("# Deploying with %s strategy, step 1/%d: %d%% of traffic", opts.strategy, len(opts.steps)+1, query.Weight))
+		}
+		res, err = c.Deploy(ctx, &query)
+		if err != nil {
+			err = fmt.Errorf("Error deploying application: %v", err)
+			deployment.Status = sdk.StatusFail
+			deployment.Details = err.Error()
+			return sdk.Result{}, deployment, err
+		}
 	}
 
 	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("# Details: %s", res.Details))
 	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("# Status: %s", res.Status))
 
-	if strings.ToUpper(res.Status) == strings.ToUpper(sdk.StatusSuccess) {
-		integrationPluginClientStop(ctx, integrationPluginClient, done, stopLogs)
-		return sdk.Result{
-			Status: sdk.StatusSuccess,
-		}, nil
+	deployment.DeploymentID = res.ID
+	if res.Version != "" {
+		deployment.Version = res.Version
+	}
+	if !isDeploySuccess(res.Status) {
+		return deployFailed(deployment, res.Details)
 	}
 
-	integrationPluginClientStop(ctx, integrationPluginClient, done, stopLogs)
+	// The release receives all the traffic at the last step
+	var promotions []int32
+	if len(opts.steps) > 0 {
+		promotions = append(promotions, opts.steps[1:]...)
+		promotions = append(promotions, 100)
+	}
+	for i, weight := range promotions {
+		if details, ok := waitDeployment(ctx, wk, c, res.ID, opts.wait); !ok {
+			abortDeployment(ctx, wk, c, options, res.ID)
+			return deployFailed(deployment, details)
+		}
 
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("# Promoting step %d/%d: %d%% of traffic", i+2, len(opts.steps)+1, weight))
+		promoteRes, err := c.Promote(ctx, &integrationplugin.PromoteQuery{Options: options, ID: res.ID, Weight: weight})
+		if err != nil {
+			abortDeployment(ctx, wk, c, options, res.ID)
+			return deployFailed(deployment, fmt.Sprintf("unable to promote deployment: %v", err))
+		}
+		if !isDeploySuccess(promoteRes.Status) {
+			abortDeployment(ctx, wk, c, options, res.ID)
+			return deployFailed(deployment, promoteRes.Details)
+		}
+	}
+
+	deployment.Status = sdk.StatusSuccess
+	return sdk.Result{Status: sdk.StatusSuccess}, deployment, nil
+}
+
+// waitDeployment waits before the next step of a progressive deployment, the status reported by the plugin
+// must not fail during the wait and must be successful at the end of it.
+func waitDeployment(ctx context.Context, wk workerruntime.Runtime, c integrationplugin.IntegrationPluginClient, id string, wait time.Duration) (string, bool) {
+	if wait > 0 {
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("# Waiting %s before the next step", wait))
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		res, err := c.DeployStatus(ctx, &integrationplugin.DeployStatusQuery{ID: id})
+		if err != nil {
+			return fmt.Sprintf("unable to get deployment status: %v", err), false
+		}
+		if strings.EqualFold(res.Status, sdk.StatusFail) || strings.EqualFold(res.Status, sdk.StatusStopped) {
+			return fmt.Sprintf("deployment status is %s: %s", res.Status, res.Details), false
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if isDeploySuccess(res.Status) {
+				return "", true
+			}
+			return fmt.Sprintf("deployment status is %s after %s", res.Status, wait), false
+		}
+		if remaining > deployStatusInterval {
+			remaining = deployStatusInterval
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err().Error(), false
+		case <-time.After(remaining):
+		}
+	}
+}
+
+func abortDeployment(ctx context.Context, wk workerruntime.Runtime, c integrationplugin.IntegrationPluginClient, options map[string]string, id string) {
+	wk.SendLog(ctx, workerruntime.LevelWarn, "# Aborting deployment")
+	res, err := c.Abort(ctx, &integrationplugin.AbortQuery{Options: options, ID: id})
+	if err != nil {
+		wk.SendLog(ctx, workerruntime.LevelError, fmt.Sprintf("# Unable to abort deployment: %v", err))
+		return
+	}
+	if !isDeploySuccess(res.Status) {
+		wk.SendLog(ctx, workerruntime.LevelError, fmt.Sprintf("# Unable to abort deployment: %s", res.Details))
+	}
+}
+
+func deployFailed(deployment sdk.ApplicationDeployment, details string) (sdk.Result, sdk.ApplicationDeployment, error) {
+	deployment.Status = sdk.StatusFail
+	deployment.Details = details
 	return sdk.Result{
 		Status: sdk.StatusFail,
-		Reason: res.Details,
-	}, nil
+		Reason: details,
+	}, deployment, nil
+}
+
+func isDeploySuccess(status string) bool {
+	return strings.EqualFold(status, sdk.StatusSuccess)
 }
 
 func integrationPluginClientStop(ctx context.Context, integrationPluginClient integrationplugin.IntegrationPluginClient, done chan struct{}, stopLogs context.CancelFunc) {
@@ -0,0 +1,155 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin/integrationplugin"
+)
+
+// fakeIntegrationPlugin records the calls made by the DeployApplication action
+type fakeIntegrationPlugin struct {
+	calls    []string
+	statuses []string
+	err      error
+}
+
+func (f *fakeIntegrationPlugin) Manifest(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*integrationplugin.IntegrationPluginManifest, error) {
+	return &integrationplugin.IntegrationPluginManifest{}, nil
+}
+
+func (f *fakeIntegrationPlugin) Deploy(ctx context.Context, in *integrationplugin.DeployQuery, opts ...grpc.CallOption) (*integrationplugin.DeployResult, error) {
+	f.calls = append(f.calls, "deploy:"+in.Strategy)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &integrationplugin.DeployResult{Status: sdk.StatusSuccess, ID: "dep-1", Version: in.Options["cds.version"]}, nil
+}
+
+func (f *fakeIntegrationPlugin) DeployStatus(ctx context.Context, in *integrationplugin.DeployStatusQuery, opts ...grpc.CallOption) (*integrationplugin.DeployResult, error) {
+	f.calls = append(f.calls, "status:"+in.ID)
+	status := sdk.StatusSuccess
+	if len(f.statuses) > 0 {
+		status, f.statuses = f.statuses[0], f.statuses[1:]
+	}
+	return &integrationplugin.DeployResult{Status: status, Details: "health check"}, nil
+}
+
+func (f *fakeIntegrationPlugin) Rollback(ctx context.Context, in *integrationplugin.RollbackQuery, opts ...grpc.CallOption) (*integrationplugin.DeployResult, error) {
+	f.calls = append(f.calls, "rollback:"+in.Version)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &integrationplugin.DeployResult{Status: sdk.StatusSuccess, ID: "dep-2"}, nil
+}
+
+func (f *fakeIntegrationPlugin) Promote(ctx context.Context, in *integrationplugin.PromoteQuery, opts ...grpc.CallOption) (*integrationplugin.DeployResult, error) {
+	f.calls = append(f.calls, fmt.Sprintf("promote:%d", in.Weight))
+	return &integrationplugin.DeployResult{Status: sdk.StatusSuccess, ID: in.ID}, nil
+}
+
+func (f *fakeIntegrationPlugin) Abort(ctx context.Context, in *integrationplugin.AbortQuery, opts ...grpc.CallOption) (*integrationplugin.DeployResult, error) {
+	f.calls = append(f.calls, "abort:"+in.ID)
+	return &integrationplugin.DeployResult{Status: sdk.StatusSuccess, ID: in.ID}, nil
+}
+
+func (f *fakeIntegrationPlugin) Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func TestRunDeployment(t *testing.T) {
+	deployStatusInterval = 10 * time.Millisecond
+	defer func() { deployStatusInterval = 10 * time.Second }()
+
+	wk, ctx := setupTest(t)
+	options := map[string]string{"cds.version": "12", "cds.triggered_by.username": "alice"}
+
+	// Direct deployment
+	c := &fakeIntegrationPlugin{}
+	opts, err := newDeployOptions(sdk.Action{})
+	require.NoError(t, err)
+	res, d, err := runDeployment(ctx, wk, c, options, opts)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusSuccess, res.Status)
+	assert.Equal(t, []string{"deploy:"}, c.calls)
+	assert.Equal(t, sdk.ApplicationDeployment{
+		Strategy:     sdk.DeploymentStrategyDirect,
+		Version:      "12",
+		Author:       "alice",
+		DeploymentID: "dep-1",
+		Status:       sdk.StatusSuccess,
+	}, d)
+
+	// Canary deployment promoted step by step
+	c = &fakeIntegrationPlugin{statuses: []string{sdk.StatusBuilding, sdk.StatusSuccess}}
+	opts, err = newDeployOptions(sdk.Action{Parameters: []sdk.Parameter{
+		{Name: "strategy", Value: "canary"},
+		{Name: "steps", Value: "10;50"},
+		{Name: "wait", Value: "15ms"},
+	}})
+	require.NoError(t, err)
+	res, d, err = runDeployment(ctx, wk, c, options, opts)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusSuccess, res.Status)
+	assert.Equal(t, "deploy:canary", c.calls[0])
+	assert.Equal(t, []string{"promote:50", "promote:100"}, promotions(c.calls))
+	assert.Equal(t, sdk.DeploymentStrategyCanary, d.Strategy)
+
+	// Canary deployment aborted on failure, without wait the status is checked once before each promotion
+	c = &fakeIntegrationPlugin{statuses: []string{sdk.StatusSuccess, sdk.StatusFail}}
+	opts.wait = 0
+	res, d, err = runDeployment(ctx, wk, c, options, opts)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, res.Status)
+	assert.Equal(t, []string{"promote:50"}, promotions(c.calls))
+	assert.Equal(t, "abort:dep-1", c.calls[len(c.calls)-1])
+	assert.Equal(t, sdk.StatusFail, d.Status)
+	assert.Contains(t, d.Details, "health check")
+
+	// Rollback
+	c = &fakeIntegrationPlugin{}
+	options[sdk.DeployRollbackVersionParameter] = "11"
+	res, d, err = runDeployment(ctx, wk, c, options, opts)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusSuccess, res.Status)
+	assert.Equal(t, []string{"rollback:11"}, c.calls)
+	assert.Equal(t, sdk.DeploymentStrategyRollback, d.Strategy)
+	assert.Equal(t, "11", d.Version)
+
+	// Failed rollback attempt is kept in the history
+	c = &fakeIntegrationPlugin{err: fmt.Errorf("unreachable")}
+	_, d, err = runDeployment(ctx, wk, c, options, opts)
+	assert.Error(t, err)
+	assert.Equal(t, sdk.DeploymentStrategyRollback, d.Strategy)
+	assert.Equal(t, sdk.StatusFail, d.Status)
+	assert.Contains(t, d.Details, "unreachable")
+
+	// Failed deployment attempt too
+	delete(options, sdk.DeployRollbackVersionParameter)
+	_, d, err = runDeployment(ctx, wk, c, options, opts)
+	assert.Error(t, err)
+	assert.Equal(t, sdk.StatusFail, d.Status)
+	assert.Equal(t, "12", d.Version)
+
+	_, err = newDeployOptions(sdk.Action{Parameters: []sdk.Parameter{{Name: "strategy", Value: "rolling"}}})
+	assert.Error(t, err)
+}
+
+func promotions(calls []string) []string {
+	var res []string
+	for _, c := range calls {
+		if strings.HasPrefix(c, "promote:") {
+			res = append(res, c)
+		}
+	}
+	return res
+}
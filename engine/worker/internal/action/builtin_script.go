@@ -248,7 +248,7 @@ func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action
 		<-outchan
 		<-errchan
 		if err := cmd.Wait(); err != nil {
-			chanErr <- fmt.Errorf("command failure: %v", err)
+			chanErr <- fmt.Errorf("command failure: %w", err)
 		}
 
 		res.Status = sdk.StatusSuccess
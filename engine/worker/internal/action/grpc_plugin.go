@@ -14,6 +14,8 @@ import (
 
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
 	"github.com/spf13/afero"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/grpcplugin"
@@ -148,7 +150,7 @@ func RunGRPCPlugin(ctx context.Context, actionName string, params []sdk.Paramete
 		JobID:   jobID,
 	}
 
-	result, err := actionPluginClient.Run(ctx, &query)
+	result, err := runActionPlugin(ctx, w, actionPluginClient, &query)
 	pluginDetails := fmt.Sprintf("plugin %s v%s", manifest.Name, manifest.Version)
 	if err != nil {
 		t := fmt.Sprintf("failure %s err: %v", pluginDetails, err)
@@ -160,10 +162,113 @@ func RunGRPCPlugin(ctx context.Context, actionName string, params []sdk.Paramete
 
 	actionPluginClientStop(ctx, actionPluginClient, stopLogs)
 
-	chanRes <- sdk.Result{
+	chanRes <- result
+}
+
+// runActionPlugin runs the action with the RunStream RPC and handles the events sent by the plugin.
+// Plugins which don't implement RunStream answer Unimplemented on the first receive, Run is then called.
+func runActionPlugin(ctx context.Context, w workerruntime.Runtime, c actionplugin.ActionPluginClient, query *actionplugin.ActionQuery) (sdk.Result, error) {
+	stream, err := c.RunStream(ctx, query)
+	if err != nil {
+		return sdk.Result{}, err
+	}
+
+	var result *actionplugin.ActionResult
+	var failures []string
+	for first := true; ; first = false {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if status.Code(err) == codes.Unimplemented && first {
+			log.Debug("plugin does not implement RunStream, calling Run")
+			res, err := c.Run(ctx, query)
+			if err != nil {
+				return sdk.Result{}, err
+			}
+			return sdk.Result{
+				Status: res.GetStatus(),
+				Reason: res.GetDetails(),
+			}, nil
+		}
+		if err != nil {
+			return sdk.Result{}, err
+		}
+
+		switch e := event.GetEvent().(type) {
+		case *actionplugin.ActionEvent_Log:
+			w.SendLog(ctx, pluginLogLevel(e.Log.GetLevel()), e.Log.GetMessage())
+		case *actionplugin.ActionEvent_Progress:
+			w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("[%d%%] %s", e.Progress.GetPercent(), e.Progress.GetMessage()))
+		case *actionplugin.ActionEvent_Variable:
+			w.ExportVariable(sdk.Variable{
+				Name:  e.Variable.GetName(),
+				Type:  sdk.StringVariable,
+				Value: e.Variable.GetValue(),
+			})
+		case *actionplugin.ActionEvent_Artifact:
+			a := sdk.Action{
+				Parameters: []sdk.Parameter{
+					{Name: "path", Type: sdk.StringParameter, Value: e.Artifact.GetPath()},
+					{Name: "tag", Type: sdk.StringParameter, Value: e.Artifact.GetTag()},
+					{Name: "destination", Type: sdk.StringParameter, Value: e.Artifact.GetDestination()},
+				},
+			}
+			res, err := RunArtifactUpload(ctx, w, a, nil)
+			if err != nil || res.Status != sdk.StatusSuccess {
+				failures = append(failures, fmt.Sprintf("artifact upload of %s failed: %s", e.Artifact.GetPath(), pluginEventFailure(res, err)))
+			}
+		case *actionplugin.ActionEvent_Tests:
+			a := sdk.Action{
+				Parameters: []sdk.Parameter{
+					{Name: "path", Type: sdk.StringParameter, Value: e.Tests.GetPath()},
+				},
+			}
+			res, err := RunParseJunitTestResultAction(ctx, w, a, nil)
+			if err != nil || res.Status != sdk.StatusSuccess {
+				failures = append(failures, fmt.Sprintf("test report %s failed: %s", e.Tests.GetPath(), pluginEventFailure(res, err)))
+			}
+		case *actionplugin.ActionEvent_Result:
+			result = e.Result
+		}
+	}
+
+	if result == nil {
+		return sdk.Result{}, fmt.Errorf("plugin stream ended without result")
+	}
+
+	res := sdk.Result{
 		Status: result.GetStatus(),
 		Reason: result.GetDetails(),
 	}
+	if len(failures) > 0 && res.Status == sdk.StatusSuccess {
+		res.Status = sdk.StatusFail
+		res.Reason = strings.Join(failures, ", ")
+	}
+	for _, f := range failures {
+		w.SendLog(ctx, workerruntime.LevelError, f)
+	}
+	return res, nil
+}
+
+func pluginLogLevel(level string) workerruntime.Level {
+	switch level {
+	case actionplugin.LogLevelWarn:
+		return workerruntime.LevelWarn
+	case actionplugin.LogLevelError:
+		return workerruntime.LevelError
+	}
+	return workerruntime.LevelInfo
+}
+
+func pluginEventFailure(res sdk.Result, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if res.Reason == "" {
+		return "status " + res.Status
+	}
+	return res.Reason
 }
 
 func startGRPCPlugin(ctx context.Context, pluginName string, w workerruntime.Runtime, p *sdk.GRPCPluginBinary, opts startGRPCPluginOptions) (*pluginClientSocket, error) {
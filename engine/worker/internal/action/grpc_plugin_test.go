@@ -0,0 +1,117 @@
+package action
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
+)
+
+// fakeActionPlugin replays events on RunStream, without events RunStream is not implemented
+type fakeActionPlugin struct {
+	events []*actionplugin.ActionEvent
+	runs   int
+}
+
+func (f *fakeActionPlugin) Manifest(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*actionplugin.ActionPluginManifest, error) {
+	return &actionplugin.ActionPluginManifest{}, nil
+}
+
+func (f *fakeActionPlugin) Run(ctx context.Context, in *actionplugin.ActionQuery, opts ...grpc.CallOption) (*actionplugin.ActionResult, error) {
+	f.runs++
+	return &actionplugin.ActionResult{Status: sdk.StatusSuccess, Details: "run"}, nil
+}
+
+func (f *fakeActionPlugin) RunStream(ctx context.Context, in *actionplugin.ActionQuery, opts ...grpc.CallOption) (actionplugin.ActionPlugin_RunStreamClient, error) {
+	return &fakeActionStream{events: f.events}, nil
+}
+
+func (f *fakeActionPlugin) WorkerHTTPPort(ctx context.Context, in *actionplugin.WorkerHTTPPortQuery, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func (f *fakeActionPlugin) Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+type fakeActionStream struct {
+	grpc.ClientStream
+	events []*actionplugin.ActionEvent
+}
+
+func (s *fakeActionStream) Recv() (*actionplugin.ActionEvent, error) {
+	if s.events == nil {
+		return nil, status.Error(codes.Unimplemented, "method RunStream not implemented")
+	}
+	if len(s.events) == 0 {
+		return nil, io.EOF
+	}
+	e := s.events[0]
+	s.events = s.events[1:]
+	return e, nil
+}
+
+func TestRunActionPlugin(t *testing.T) {
+	wk, ctx := setupTest(t)
+	query := &actionplugin.ActionQuery{JobID: 666}
+
+	// Plugin without RunStream
+	c := &fakeActionPlugin{}
+	res, err := runActionPlugin(ctx, wk, c, query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.runs)
+	assert.Equal(t, sdk.Result{Status: sdk.StatusSuccess, Reason: "run"}, res)
+
+	// Streamed logs, variables and test report
+	report := `<testsuites><testsuite name="plugin"><testcase name="ok"></testcase></testsuite></testsuites>`
+	require.NoError(t, afero.WriteFile(wk.BaseDir(), "working_directory/results.xml", []byte(report), 0644))
+	reportPath, err := wk.BaseDir().(*afero.BasePathFs).RealPath("working_directory/results.xml")
+	require.NoError(t, err)
+
+	defer gock.Off()
+	gock.New("http://lolcat.host").Post("/queue/workflows/666/test").Reply(http.StatusOK)
+	gock.InterceptClient(wk.Client().(cdsclient.Raw).HTTPClient())
+
+	c = &fakeActionPlugin{events: []*actionplugin.ActionEvent{
+		{Event: &actionplugin.ActionEvent_Log{Log: &actionplugin.ActionLog{Level: actionplugin.LogLevelWarn, Message: "starting"}}},
+		{Event: &actionplugin.ActionEvent_Progress{Progress: &actionplugin.ActionProgress{Percent: 50, Message: "half"}}},
+		{Event: &actionplugin.ActionEvent_Variable{Variable: &actionplugin.ActionVariable{Name: "foo", Value: "bar"}}},
+		{Event: &actionplugin.ActionEvent_Tests{Tests: &actionplugin.ActionTestReport{Path: reportPath}}},
+		{Event: &actionplugin.ActionEvent_Result{Result: &actionplugin.ActionResult{Status: sdk.StatusSuccess}}},
+	}}
+	res, err = runActionPlugin(ctx, wk, c, query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.runs)
+	assert.Equal(t, sdk.StatusSuccess, res.Status)
+	assert.Empty(t, res.NewVariables)
+	assert.Equal(t, []sdk.Variable{{Name: "cds.build.foo", Type: sdk.StringVariable, Value: "bar"}}, *wk.exported)
+	assert.True(t, gock.IsDone())
+
+	// A failed artifact upload fails the step
+	c = &fakeActionPlugin{events: []*actionplugin.ActionEvent{
+		{Event: &actionplugin.ActionEvent_Artifact{Artifact: &actionplugin.ActionArtifact{Path: "*.tar.gz", Tag: "1"}}},
+		{Event: &actionplugin.ActionEvent_Result{Result: &actionplugin.ActionResult{Status: sdk.StatusSuccess}}},
+	}}
+	res, err = runActionPlugin(ctx, wk, c, query)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, res.Status)
+	assert.Contains(t, res.Reason, "artifact upload of *.tar.gz failed")
+
+	// A stream without result is an error
+	c = &fakeActionPlugin{events: []*actionplugin.ActionEvent{}}
+	_, err = runActionPlugin(ctx, wk, c, query)
+	assert.Error(t, err)
+}
@@ -26,6 +26,12 @@ type TestWorker struct {
 	keyDirectory     *afero.BasePathFile
 	client           cdsclient.WorkerInterface
 	Params           []sdk.Parameter
+	exported         *[]sdk.Variable
+}
+
+func (w TestWorker) ExportVariable(v sdk.Variable) {
+	v.Name = "cds.build." + v.Name
+	*w.exported = append(*w.exported, v)
 }
 
 func (w TestWorker) Blur(i interface{}) error {
@@ -104,6 +110,7 @@ func setupTest(t *testing.T) (TestWorker, context.Context) {
 	wk := TestWorker{
 		t:         t,
 		workspace: afero.NewBasePathFs(fs, basedir),
+		exported:  new([]sdk.Variable),
 	}
 
 	err := wk.BaseDir().Mkdir("working_directory", os.FileMode(0755))
@@ -2,7 +2,9 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os/exec"
 
 	"github.com/ovh/cds/engine/worker/internal/action"
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
@@ -44,6 +46,12 @@ func (w *CurrentWorker) runBuiltin(ctx context.Context, a sdk.Action, secrets []
 	if err != nil {
 		res.Status = sdk.StatusFail
 		res.Reason = err.Error()
+		// Keep the exit code of the command, used to match retry policies
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode := exitErr.ExitCode()
+			res.ExitCode = &exitCode
+		}
 		log.Error(ctx, "worker.runBuiltin> %v", err)
 		w.SendLog(ctx, workerruntime.LevelError, res.Reason)
 	}
@@ -26,9 +26,6 @@ func addBuildVarHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		v.Name = "cds.build." + v.Name
-
-		wk.currentJob.newVariables = append(wk.currentJob.newVariables, v)
-		log.Debug("Variable %s added to %+v", v.Name, wk.currentJob.newVariables)
+		wk.ExportVariable(v)
 	}
 }
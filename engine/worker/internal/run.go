@@ -109,6 +109,7 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 	}
 
 	var nDisabled, nCriticalFailed int
+	var criticalFailure *sdk.Result
 	for jobStepIndex, step := range a.Actions {
 		ctx = workerruntime.SetStepOrder(ctx, jobStepIndex)
 		if err := w.updateStepStatus(ctx, jobID, jobStepIndex, sdk.StatusBuilding); err != nil {
@@ -121,7 +122,7 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 			BuildID: jobID,
 		}
 		if nCriticalFailed == 0 || step.AlwaysExecuted {
-			stepResult = w.runAction(ctx, step, jobID, secrets, step.Name)
+			stepResult = w.runStep(ctx, step, jobID, secrets, step.Name)
 
 			for _, newVariable := range stepResult.NewVariables {
 				// append the new variable from a step to the following steps
@@ -136,6 +137,10 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 			case sdk.StatusFail:
 				if !step.Optional {
 					nCriticalFailed++
+					if criticalFailure == nil {
+						failure := stepResult
+						criticalFailure = &failure
+					}
 				}
 			}
 		}
@@ -156,10 +161,21 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 	}
 	if nCriticalFailed > 0 {
 		jobResult.Status = sdk.StatusFail
+		// Keep the exit code and timeout of the first failed step, used to match the retry policy of the job
+		jobResult.ExitCode = criticalFailure.ExitCode
+		jobResult.TimedOut = criticalFailure.TimedOut
+		jobResult.Reason = criticalFailure.Reason
 	}
 	return jobResult, nil
 }
 
+// runStep runs an action within its timeout, and runs it again while its retry policy matches the result
+func (w *CurrentWorker) runStep(ctx context.Context, a sdk.Action, jobID int64, secrets []sdk.Variable, actionName string) sdk.Result {
+	return workerruntime.RunStep(ctx, a, actionName, w.SendLog, func(ctx context.Context) sdk.Result {
+		return w.runAction(ctx, a, jobID, secrets, actionName)
+	})
+}
+
 func (w *CurrentWorker) runAction(ctx context.Context, a sdk.Action, jobID int64, secrets []sdk.Variable, actionName string) sdk.Result {
 	log.Info(ctx, "runAction> start action %s %s %d", a.StepName, actionName, jobID)
 	defer func() { log.Info(ctx, "runAction> end action %s %s run %d", a.StepName, actionName, jobID) }()
@@ -251,7 +267,7 @@ func (w *CurrentWorker) runSteps(ctx context.Context, steps []sdk.Action, a sdk.
 		}
 
 		if !criticalStepFailed || child.AlwaysExecuted {
-			r = w.runAction(ctx, child, jobID, secrets, childName)
+			r = w.runStep(ctx, child, jobID, secrets, childName)
 			if r.Status != sdk.StatusSuccess && !child.Optional {
 				criticalStepFailed = true
 			}
@@ -510,7 +526,28 @@ func (w *CurrentWorker) ProcessJob(jobInfo sdk.WorkflowNodeJobRunData) (sdk.Resu
 
 	w.currentJob.params = jobParameters
 
-	res, err := w.runJob(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, jobInfo.Secrets)
+	jobTimeout, err := sdk.ParseActionTimeout(jobInfo.NodeJobRun.Job.Action.Timeout)
+	if err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail,
+			Reason: err.Error(),
+		}, err
+	}
+	runCtx := ctx
+	if jobTimeout > 0 {
+		var cancelRun context.CancelFunc
+		runCtx, cancelRun = context.WithTimeout(ctx, jobTimeout)
+		defer cancelRun()
+	}
+
+	res, err := w.runJob(runCtx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, jobInfo.Secrets)
+	if jobTimeout > 0 && runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		res.Status = sdk.StatusFail
+		res.TimedOut = true
+		res.Reason = fmt.Sprintf("Job timed out after %s", jobTimeout)
+		w.SendLog(ctx, workerruntime.LevelError, res.Reason)
+		err = nil
+	}
 
 	if len(res.NewVariables) > 0 {
 		log.Debug("processJob> new variables: %v", res.NewVariables)
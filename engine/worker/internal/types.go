@@ -67,6 +67,13 @@ func (wk *CurrentWorker) Parameters() []sdk.Parameter {
 	return wk.currentJob.params
 }
 
+// ExportVariable adds a variable to the job result, it is available in the next steps and jobs as cds.build.<name>
+func (wk *CurrentWorker) ExportVariable(v sdk.Variable) {
+	v.Name = "cds.build." + v.Name
+	wk.currentJob.newVariables = append(wk.currentJob.newVariables, v)
+	log.Debug("Variable %s added to %+v", v.Name, wk.currentJob.newVariables)
+}
+
 func (wk *CurrentWorker) SendLog(ctx context.Context, level workerruntime.Level, s string) {
 	jobID, _ := workerruntime.JobID(ctx)
 	stepOrder, err := workerruntime.StepOrder(ctx)
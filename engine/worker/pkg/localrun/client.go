@@ -0,0 +1,103 @@
+package localrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ovh/venom"
+	"github.com/sguiheux/go-coverage"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+// client implements the calls of the builtin actions run locally, artifacts are stored in a local directory as <dir>/<tag>/<file>.
+// The other calls are not implemented.
+type client struct {
+	cdsclient.WorkerInterface
+	dir string
+	rt  *runtime
+}
+
+func (c *client) QueueArtifactUpload(ctx context.Context, projectKey, integrationName string, nodeJobRunID int64, tag, filePath string) (bool, time.Duration, error) {
+	t0 := time.Now()
+	src, err := os.Open(filePath)
+	if err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	defer src.Close() // nolint
+	fi, err := src.Stat()
+	if err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+
+	dir := filepath.Join(c.dir, tag)
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	dst, err := os.OpenFile(filepath.Join(dir, filepath.Base(filePath)), os.O_RDWR|os.O_CREATE|os.O_TRUNC, fi.Mode())
+	if err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	defer dst.Close() // nolint
+	if _, err := io.Copy(dst, src); err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	return false, time.Since(t0), nil
+}
+
+func (c *client) WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error) {
+	tags, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	var artifacts []sdk.WorkflowNodeRunArtifact
+	for _, tag := range tags {
+		if !tag.IsDir() {
+			continue
+		}
+		files, err := ioutil.ReadDir(filepath.Join(c.dir, tag.Name()))
+		if err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			artifacts = append(artifacts, sdk.WorkflowNodeRunArtifact{
+				Name:    f.Name(),
+				Tag:     tag.Name(),
+				Size:    f.Size(),
+				Perm:    uint32(f.Mode().Perm()),
+				Created: f.ModTime(),
+			})
+		}
+	}
+	return artifacts, nil
+}
+
+func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
+	f, err := os.Open(filepath.Join(c.dir, a.Tag, a.Name))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer f.Close() // nolint
+	_, err = io.Copy(w, f)
+	return sdk.WithStack(err)
+}
+
+func (c *client) QueueSendUnitTests(ctx context.Context, id int64, report venom.Tests) error {
+	c.rt.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Unit tests: %d total, %d ok, %d ko, %d skipped", report.Total, report.TotalOK, report.TotalKO, report.TotalSkipped))
+	return nil
+}
+
+func (c *client) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) error {
+	c.rt.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Coverage: %d/%d lines, %d/%d functions, %d/%d branches", report.CoveredLines, report.TotalLines, report.CoveredFunctions, report.TotalFunctions, report.CoveredBranches, report.TotalBranches))
+	return nil
+}
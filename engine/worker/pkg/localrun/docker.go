@@ -0,0 +1,116 @@
+package localrun
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+// container runs the script steps of a job in a docker container, the working directory is mounted at the same path.
+type container struct {
+	id      string
+	workdir string
+}
+
+func startContainer(ctx context.Context, image, workdir string) (*container, error) {
+	out, err := exec.CommandContext(ctx, "docker", "run", "-d", "--rm",
+		"-v", workdir+":"+workdir, "-w", workdir,
+		"--entrypoint", "tail", image, "-f", "/dev/null").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, sdk.WithStack(fmt.Errorf("unable to start container from image %s: %s", image, strings.TrimSpace(string(exitErr.Stderr))))
+		}
+		return nil, sdk.WithStack(fmt.Errorf("unable to start container from image %s: %v", image, err))
+	}
+	id := strings.TrimSpace(string(out))
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return &container{id: id, workdir: workdir}, nil
+}
+
+func (c *container) remove() {
+	_ = exec.Command("docker", "rm", "-f", c.id).Run()
+}
+
+// runScript runs the script of the step in the container, with the job parameters as env variables
+func (c *container) runScript(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, _ []sdk.Variable) (sdk.Result, error) {
+	content := sdk.ParameterValue(a.Parameters, "script")
+	if content == "" {
+		return sdk.Result{}, errors.New("script content not provided, aborting")
+	}
+	shell := []string{"/bin/sh", "-e"}
+	if strings.HasPrefix(content, "#!") {
+		t := strings.SplitN(content, "\n", 2)
+		shell = strings.Fields(strings.TrimPrefix(t[0], "#!"))
+		if len(shell) == 1 && strings.HasSuffix(shell[0], "sh") {
+			shell = append(shell, "-e")
+		}
+		content = ""
+		if len(t) > 1 {
+			content = t[1]
+		}
+	}
+
+	f, err := ioutil.TempFile(c.workdir, ".script-")
+	if err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+	defer os.Remove(f.Name()) // nolint
+	if _, err := f.WriteString(content); err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+	if err := f.Close(); err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+
+	args := []string{"exec", "-w", c.workdir}
+	if r, ok := wk.(*runtime); ok {
+		for _, e := range r.jobEnviron() {
+			args = append(args, "-e", e)
+		}
+	}
+	args = append(args, c.id)
+	args = append(args, shell...)
+	args = append(args, filepath.Base(f.Name()))
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			wk.SendLog(ctx, workerruntime.LevelInfo, scanner.Text())
+		}
+	}()
+	err = cmd.Run()
+	pw.Close() // nolint
+	<-done
+
+	res := sdk.Result{Status: sdk.StatusSuccess}
+	if err != nil {
+		res.Status = sdk.StatusFail
+		res.Reason = fmt.Sprintf("command failure: %v", err)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code := exitErr.ExitCode()
+			res.ExitCode = &code
+		}
+		wk.SendLog(ctx, workerruntime.LevelError, res.Reason)
+	}
+	return res, nil
+}
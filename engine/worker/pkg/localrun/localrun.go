@@ -0,0 +1,308 @@
+// Package localrun runs the jobs of a pipeline on the local machine, with the builtin actions of the worker.
+package localrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/internal/action"
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+type builtinAction func(context.Context, workerruntime.Runtime, sdk.Action, []sdk.Variable) (sdk.Result, error)
+
+// The builtin actions that don't need a CDS API
+var builtinActions = map[string]builtinAction{
+	sdk.ScriptAction:              action.RunScriptAction,
+	sdk.GitCloneAction:            action.RunGitClone,
+	sdk.CheckoutApplicationAction: action.RunCheckoutApplication,
+	sdk.JUnitAction:               action.RunParseJunitTestResultAction,
+	sdk.CoverageAction:            action.RunParseCoverageResultAction,
+	sdk.ArtifactUpload:            action.RunArtifactUpload,
+	sdk.ArtifactDownload:          action.RunArtifactDownload,
+}
+
+// Options of a local run
+type Options struct {
+	// Workspace is the directory where the working directory of each job is created
+	Workspace string
+	// ArtifactDir stores the artifacts uploaded by the jobs as <ArtifactDir>/<tag>/<file>
+	ArtifactDir string
+	// Parameters of the run, ie. git.branch or cds.version
+	Parameters []sdk.Parameter
+	// Jobs filters the jobs to run by name, all the jobs are run if empty
+	Jobs []string
+	// DockerImage returns the image used to run the script steps of a job, they are run on the local machine if it is empty
+	DockerImage func(sdk.Job) (string, error)
+	// Output receives the logs of the jobs
+	Output io.Writer
+}
+
+// JobResult is the result of a job run locally
+type JobResult struct {
+	Stage string
+	Job   string
+	sdk.Result
+}
+
+// Run runs the jobs of the pipeline stage by stage. The next stages are not run if a job failed.
+func Run(ctx context.Context, pip sdk.Pipeline, opts Options) ([]JobResult, error) {
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	for _, d := range []*string{&opts.Workspace, &opts.ArtifactDir} {
+		abs, err := filepath.Abs(*d)
+		if err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		if err := os.MkdirAll(abs, os.FileMode(0755)); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		*d = abs
+	}
+
+	var results []JobResult
+	var jobID int64
+	for _, s := range pip.Stages {
+		if !s.Enabled {
+			continue
+		}
+		var failed bool
+		for _, j := range s.Jobs {
+			if !j.Enabled || !matchJob(opts.Jobs, j.Action.Name) {
+				continue
+			}
+			// A matrix job is run once per variant
+			variants := []sdk.JobMatrixVariant{nil}
+			if j.Action.Matrix != nil {
+				variants = j.Action.Matrix.Variants()
+			}
+			for _, v := range variants {
+				jobID++
+				res, err := runJob(ctx, pip, s, j, v, jobID, opts)
+				if err != nil {
+					return results, err
+				}
+				name := j.Action.Name
+				if v != nil {
+					name = fmt.Sprintf("%s (%s)", name, v.String())
+				}
+				results = append(results, JobResult{Stage: s.Name, Job: name, Result: res})
+				if res.Status == sdk.StatusFail {
+					failed = true
+					if j.Action.Matrix != nil && j.Action.Matrix.FailFast {
+						break
+					}
+				}
+			}
+		}
+		if failed {
+			break
+		}
+	}
+	return results, nil
+}
+
+func matchJob(filter []string, name string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func runJob(ctx context.Context, pip sdk.Pipeline, stage sdk.Stage, job sdk.Job, variant sdk.JobMatrixVariant, jobID int64, opts Options) (sdk.Result, error) {
+	dir, err := ioutil.TempDir(opts.Workspace, "job-")
+	if err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+	baseDir := afero.NewBasePathFs(afero.NewOsFs(), dir)
+	for _, d := range []string{"run", "keys"} {
+		if err := baseDir.Mkdir(d, os.FileMode(0755)); err != nil {
+			return sdk.Result{}, sdk.WithStack(err)
+		}
+	}
+	workdir, err := baseDir.Open("run")
+	if err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+	keysdir, err := baseDir.Open("keys")
+	if err != nil {
+		return sdk.Result{}, sdk.WithStack(err)
+	}
+	workdirPath := filepath.Join(dir, "run")
+
+	params := append([]sdk.Parameter{}, opts.Parameters...)
+	for k, v := range map[string]string{
+		"cds.project":    "local",
+		"cds.workflow":   "local",
+		"cds.run.number": "0",
+		"cds.version":    "0",
+	} {
+		if sdk.ParameterFind(params, k) == nil {
+			sdk.AddParameter(&params, k, sdk.StringParameter, v)
+		}
+	}
+	sdk.AddParameter(&params, "cds.pipeline", sdk.StringParameter, pip.Name)
+	sdk.AddParameter(&params, "cds.stage", sdk.StringParameter, stage.Name)
+	sdk.AddParameter(&params, "cds.job", sdk.StringParameter, job.Action.Name)
+	sdk.AddParameter(&params, "cds.workspace", sdk.StringParameter, workdirPath)
+	params = append(params, variant.Parameters()...)
+
+	name := job.Action.Name
+	if variant != nil {
+		name = fmt.Sprintf("%s (%s)", name, variant.String())
+		job.Action.Requirements = variant.Requirements(job.Action.Requirements)
+	}
+	rt := &runtime{
+		name:    name,
+		baseDir: baseDir,
+		params:  params,
+		out:     opts.Output,
+	}
+	rt.client = &client{dir: opts.ArtifactDir, rt: rt}
+
+	if opts.DockerImage != nil {
+		image, err := opts.DockerImage(job)
+		if err != nil {
+			return sdk.Result{}, err
+		}
+		if image != "" {
+			c, err := startContainer(ctx, image, workdirPath)
+			if err != nil {
+				return sdk.Result{}, err
+			}
+			defer c.remove()
+			rt.container = c
+			rt.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Script steps run in container %s (%s)", c.id, image))
+		}
+	}
+
+	ctx = workerruntime.SetJobID(ctx, jobID)
+	ctx = workerruntime.SetWorkingDirectory(ctx, workdir)
+	ctx = workerruntime.SetKeysDirectory(ctx, keysdir)
+
+	timeout, err := sdk.ParseActionTimeout(job.Action.Timeout)
+	if err != nil {
+		return sdk.Result{}, err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	rt.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting job \"%s\" in %s", name, workdirPath))
+	t0 := time.Now()
+	res := sdk.Result{Status: sdk.StatusSuccess, BuildID: jobID}
+	var nCriticalFailed int
+	for i, step := range job.Action.Actions {
+		if nCriticalFailed > 0 && !step.AlwaysExecuted {
+			continue
+		}
+		stepCtx := workerruntime.SetStepOrder(ctx, i)
+		stepName := step.StepName
+		if stepName == "" {
+			stepName = step.Name
+		}
+		stepRes := rt.runStep(stepCtx, step, stepName)
+		if stepRes.Status == sdk.StatusFail && !step.Optional {
+			nCriticalFailed++
+			res.Status = sdk.StatusFail
+			res.Reason = stepRes.Reason
+		}
+	}
+	if timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+		res.Status = sdk.StatusFail
+		res.TimedOut = true
+		res.Reason = fmt.Sprintf("Job timed out after %s", timeout)
+	}
+	res.Duration = sdk.Round(time.Since(t0), time.Second).String()
+	rt.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("End of job \"%s\": %s (%s)", name, res.Status, res.Duration))
+	return res, nil
+}
+
+// runStep runs a step within its timeout, and runs it again while its retry policy matches the result
+func (r *runtime) runStep(ctx context.Context, a sdk.Action, stepName string) sdk.Result {
+	return workerruntime.RunStep(ctx, a, stepName, r.SendLog, func(ctx context.Context) sdk.Result {
+		return r.runAction(ctx, a, stepName)
+	})
+}
+
+func (r *runtime) runAction(ctx context.Context, a sdk.Action, stepName string) sdk.Result {
+	if !a.Enabled {
+		r.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Step \"%s\" is disabled", stepName))
+		return sdk.Result{Status: sdk.StatusDisabled}
+	}
+
+	r.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting step \"%s\"", stepName))
+	t0 := time.Now()
+	defer func() {
+		r.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("End of step \"%s\" (%s)", stepName, sdk.Round(time.Since(t0), time.Second).String()))
+	}()
+
+	// Replace the variables like the worker does, without expanding "CDS_*" env variables
+	vars := sdk.ParametersToMap(r.params)
+	a.Parameters = append([]sdk.Parameter{}, a.Parameters...)
+	for i := range a.Parameters {
+		v, err := interpolate.Do(a.Parameters[i].Value, vars)
+		if err != nil {
+			return r.fail(ctx, fmt.Sprintf("unable to interpolate parameter %s: %v", a.Parameters[i].Name, err))
+		}
+		if a.Name != sdk.ScriptAction {
+			v = os.Expand(v, func(s string) string {
+				if strings.HasPrefix(s, "CDS_") {
+					return s
+				}
+				return os.Getenv(s)
+			})
+		}
+		a.Parameters[i].Value = v
+	}
+
+	f, ok := builtinActions[a.Name]
+	if a.Type != sdk.BuiltinAction || !ok {
+		return r.fail(ctx, fmt.Sprintf("step \"%s\" can't be run locally, only the builtin actions %s are supported", stepName, strings.Join(SupportedActions(), ", ")))
+	}
+	if a.Name == sdk.ScriptAction && r.container != nil {
+		f = r.container.runScript
+	}
+
+	res, err := f(ctx, r, a, nil)
+	if err != nil {
+		res = r.fail(ctx, err.Error())
+	}
+	return res
+}
+
+func (r *runtime) fail(ctx context.Context, reason string) sdk.Result {
+	r.SendLog(ctx, workerruntime.LevelError, reason)
+	return sdk.Result{Status: sdk.StatusFail, Reason: reason}
+}
+
+// SupportedActions returns the names of the builtin actions that can be run locally
+func SupportedActions() []string {
+	return []string{
+		sdk.ScriptAction,
+		sdk.GitCloneAction,
+		sdk.CheckoutApplicationAction,
+		sdk.JUnitAction,
+		sdk.CoverageAction,
+		sdk.ArtifactUpload,
+		sdk.ArtifactDownload,
+	}
+}
@@ -0,0 +1,116 @@
+package localrun
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func TestRun(t *testing.T) {
+	p, err := exportentities.ParsePipeline("yaml", []byte(`version: v1.0
+name: build
+stages:
+- Build
+- Test
+jobs:
+- job: Compile
+  stage: Build
+  steps:
+  - script:
+    - echo "{{.cds.pipeline}}-{{.git.branch}}" > out.txt
+  - artifactUpload:
+      path: out.txt
+      tag: "{{.cds.version}}"
+- job: Check
+  stage: Test
+  steps:
+  - artifactDownload:
+      path: downloads
+  - script:
+    - test "$(cat downloads/out.txt)" = "build-master"
+  - name: optional failure
+    optional: true
+    script:
+    - exit 1
+- job: Matrix
+  stage: Test
+  matrix:
+    variables:
+      go: ["1.13", "1.14"]
+  steps:
+  - script:
+    - test "{{.cds.matrix.go}}" = "1.13"
+- job: Skipped
+  stage: Test
+  steps:
+  - script:
+    - exit 1
+`))
+	require.NoError(t, err)
+	pip, err := p.Pipeline()
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "localrun")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	out := new(bytes.Buffer)
+	results, err := Run(context.TODO(), *pip, Options{
+		Workspace:   filepath.Join(dir, "workspace"),
+		ArtifactDir: filepath.Join(dir, "artifacts"),
+		Parameters:  []sdk.Parameter{{Name: "git.branch", Type: sdk.StringParameter, Value: "master"}},
+		Jobs:        []string{"Compile", "Check", "Matrix"},
+		Output:      out,
+	})
+	require.NoError(t, err)
+	t.Log(out.String())
+
+	require.Len(t, results, 4)
+	assert.Equal(t, "Compile", results[0].Job)
+	assert.Equal(t, sdk.StatusSuccess, results[0].Status)
+	assert.Equal(t, "Check", results[1].Job)
+	assert.Equal(t, sdk.StatusSuccess, results[1].Status)
+	assert.Equal(t, "Matrix (go=1.13)", results[2].Job)
+	assert.Equal(t, sdk.StatusSuccess, results[2].Status)
+	assert.Equal(t, "Matrix (go=1.14)", results[3].Job)
+	assert.Equal(t, sdk.StatusFail, results[3].Status)
+
+	btes, err := ioutil.ReadFile(filepath.Join(dir, "artifacts", "0", "out.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "build-master\n", string(btes))
+}
+
+func TestRunStopsAfterFailedStage(t *testing.T) {
+	pip := sdk.Pipeline{
+		Name: "build",
+		Stages: []sdk.Stage{
+			{Name: "Build", Enabled: true, Jobs: []sdk.Job{{Enabled: true, Action: sdk.Action{
+				Name: "Compile",
+				Actions: []sdk.Action{
+					{Name: sdk.ScriptAction, Type: sdk.BuiltinAction, Enabled: true, Parameters: []sdk.Parameter{{Name: "script", Value: "exit 3"}}},
+					{Name: sdk.DeployApplicationAction, Type: sdk.BuiltinAction, Enabled: true},
+				},
+			}}}},
+			{Name: "Test", Enabled: true, Jobs: []sdk.Job{{Enabled: true, Action: sdk.Action{Name: "Check"}}}},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "localrun")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	results, err := Run(context.TODO(), pip, Options{Workspace: dir, ArtifactDir: dir, Output: ioutil.Discard})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, sdk.StatusFail, results[0].Status)
+	assert.Contains(t, results[0].Reason, "exit status 3")
+}
@@ -0,0 +1,103 @@
+package localrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+var _ workerruntime.Runtime = new(runtime)
+
+// runtime is the worker runtime of a job run locally, there is no CDS API behind it.
+type runtime struct {
+	name      string
+	baseDir   afero.Fs
+	params    []sdk.Parameter
+	client    cdsclient.WorkerInterface
+	container *container
+	out       io.Writer
+	mutex     sync.Mutex
+}
+
+func (r *runtime) Name() string { return r.name }
+
+func (r *runtime) Register(ctx context.Context) error {
+	return sdk.WithStack(fmt.Errorf("not implemented in local runs"))
+}
+
+func (r *runtime) Take(ctx context.Context, job sdk.WorkflowNodeJobRun) error {
+	return sdk.WithStack(fmt.Errorf("not implemented in local runs"))
+}
+
+func (r *runtime) ProcessJob(job sdk.WorkflowNodeJobRunData) (sdk.Result, error) {
+	return sdk.Result{}, sdk.WithStack(fmt.Errorf("not implemented in local runs"))
+}
+
+func (r *runtime) Unregister(ctx context.Context) error { return nil }
+
+// SendLog prints the log line prefixed with the job name
+func (r *runtime) SendLog(ctx context.Context, level workerruntime.Level, s string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		if level == workerruntime.LevelInfo {
+			fmt.Fprintf(r.out, "[%s] %s\n", r.name, line)
+		} else {
+			fmt.Fprintf(r.out, "[%s] %s: %s\n", r.name, level, line)
+		}
+	}
+}
+
+// InstallKey is not supported because the keys are stored in the CDS project
+func (r *runtime) InstallKey(key sdk.Variable, destinationPath string) (*workerruntime.KeyResponse, error) {
+	return nil, sdk.WithStack(fmt.Errorf("key %s can't be installed in local runs", key.Name))
+}
+
+func (r *runtime) Client() cdsclient.WorkerInterface { return r.client }
+
+func (r *runtime) BaseDir() afero.Fs { return r.baseDir }
+
+// Environ returns the env variables of the local machine and the env variables of the job parameters, like the worker does.
+func (r *runtime) Environ() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, "CDS_") {
+			continue
+		}
+		env = append(env, e)
+	}
+	return append(env, r.jobEnviron()...)
+}
+
+func (r *runtime) jobEnviron() []string {
+	env := []string{"CI=1"}
+	for _, p := range r.params {
+		env = append(env, sdk.EnvVartoENV(p)...)
+		envName := strings.Replace(p.Name, ".", "_", -1)
+		envName = strings.Replace(envName, "-", "_", -1)
+		envName = strings.ToUpper(envName)
+		env = append(env, fmt.Sprintf("%s=%s", envName, p.Value))
+	}
+	return env
+}
+
+// Blur does nothing, there is no secret in local runs
+func (r *runtime) Blur(interface{}) error { return nil }
+
+func (r *runtime) HTTPPort() int32 { return 0 }
+
+func (r *runtime) Parameters() []sdk.Parameter { return r.params }
+
+// ExportVariable adds the variable to the parameters of the job, there is no job result in local runs
+func (r *runtime) ExportVariable(v sdk.Variable) {
+	r.params = append(r.params, sdk.Parameter{Name: "cds.build." + v.Name, Type: v.Type, Value: v.Value})
+}
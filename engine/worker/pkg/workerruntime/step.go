@@ -0,0 +1,53 @@
+package workerruntime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// RunStep runs a step within its timeout, and runs it again while its retry policy matches the result.
+// The run func is called for each attempt with the context of the attempt, retries and timeouts are logged with sendLog.
+func RunStep(ctx context.Context, a sdk.Action, stepName string, sendLog func(context.Context, Level, string), run func(context.Context) sdk.Result) sdk.Result {
+	for attempt := 1; ; attempt++ {
+		res := runStepWithTimeout(ctx, a, stepName, sendLog, run)
+		if a.Retry == nil || attempt > a.Retry.Max || !a.Retry.Match(res) || ctx.Err() != nil {
+			return res
+		}
+
+		delay := a.Retry.Delay(attempt)
+		sendLog(ctx, LevelWarn, fmt.Sprintf("Step \"%s\" failed (%s), attempt %d/%d in %s", stepName, res.Reason, attempt+1, a.Retry.Max+1, delay))
+		select {
+		case <-ctx.Done():
+			return res
+		case <-time.After(delay):
+		}
+	}
+}
+
+func runStepWithTimeout(ctx context.Context, a sdk.Action, stepName string, sendLog func(context.Context, Level, string), run func(context.Context) sdk.Result) sdk.Result {
+	timeout, err := sdk.ParseActionTimeout(a.Timeout)
+	if err != nil {
+		res := sdk.Result{Status: sdk.StatusFail, Reason: err.Error()}
+		if id, err := JobID(ctx); err == nil {
+			res.BuildID = id
+		}
+		return res
+	}
+	if timeout == 0 {
+		return run(ctx)
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	res := run(stepCtx)
+	if stepCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		res.Status = sdk.StatusFail
+		res.TimedOut = true
+		res.Reason = fmt.Sprintf("Step \"%s\" timed out after %s", stepName, timeout)
+		sendLog(ctx, LevelError, res.Reason)
+	}
+	return res
+}
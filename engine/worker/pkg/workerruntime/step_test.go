@@ -0,0 +1,81 @@
+package workerruntime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestRunStepRetry(t *testing.T) {
+	var logs []string
+	sendLog := func(_ context.Context, _ Level, s string) { logs = append(logs, s) }
+
+	var attempts int
+	a := sdk.Action{Retry: &sdk.ActionRetry{Max: 2}}
+	res := RunStep(context.TODO(), a, "my-step", sendLog, func(context.Context) sdk.Result {
+		attempts++
+		if attempts < 2 {
+			return sdk.Result{Status: sdk.StatusFail, Reason: "failed"}
+		}
+		return sdk.Result{Status: sdk.StatusSuccess}
+	})
+	assert.Equal(t, sdk.StatusSuccess, res.Status)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{"Step \"my-step\" failed (failed), attempt 2/3 in 0s"}, logs)
+
+	// Stops after the maximum number of retries
+	attempts = 0
+	res = RunStep(context.TODO(), a, "my-step", sendLog, func(context.Context) sdk.Result {
+		attempts++
+		return sdk.Result{Status: sdk.StatusFail}
+	})
+	assert.Equal(t, sdk.StatusFail, res.Status)
+	assert.Equal(t, 3, attempts)
+
+	// Without retry policy the step is run once
+	attempts = 0
+	RunStep(context.TODO(), sdk.Action{}, "my-step", sendLog, func(context.Context) sdk.Result {
+		attempts++
+		return sdk.Result{Status: sdk.StatusFail}
+	})
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRunStepTimeout(t *testing.T) {
+	var logs []string
+	sendLog := func(_ context.Context, _ Level, s string) { logs = append(logs, s) }
+
+	var attempts int
+	a := sdk.Action{Timeout: "100ms", Retry: &sdk.ActionRetry{Max: 1, On: []string{sdk.ActionRetryOnTimeout}}}
+	ctx := SetJobID(context.TODO(), 42)
+	res := RunStep(ctx, a, "my-step", sendLog, func(ctx context.Context) sdk.Result {
+		attempts++
+		<-ctx.Done()
+		return sdk.Result{Status: sdk.StatusSuccess}
+	})
+	assert.Equal(t, sdk.StatusFail, res.Status)
+	assert.True(t, res.TimedOut)
+	assert.Equal(t, "Step \"my-step\" timed out after 100ms", res.Reason)
+	assert.Equal(t, 2, attempts)
+
+	// An invalid timeout fails the step of the job
+	res = RunStep(ctx, sdk.Action{Timeout: "foo"}, "my-step", sendLog, func(context.Context) sdk.Result {
+		t.Fatal("the step should not be run")
+		return sdk.Result{}
+	})
+	assert.Equal(t, sdk.StatusFail, res.Status)
+	assert.Equal(t, int64(42), res.BuildID)
+
+	// The timeout of the job is not reported as a timeout of the step
+	jobCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	res = RunStep(jobCtx, sdk.Action{Timeout: "1s"}, "my-step", sendLog, func(ctx context.Context) sdk.Result {
+		<-ctx.Done()
+		return sdk.Result{Status: sdk.StatusFail}
+	})
+	assert.False(t, res.TimedOut)
+}
@@ -66,6 +66,7 @@ type Runtime interface {
 	Blur(interface{}) error
 	HTTPPort() int32
 	Parameters() []sdk.Parameter
+	ExportVariable(v sdk.Variable)
 }
 
 func JobID(ctx context.Context) (int64, error) {
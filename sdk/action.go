@@ -50,6 +50,11 @@ type Action struct {
 	StepName       string `json:"step_name,omitempty" yaml:"step_name,omitempty" db:"-"`
 	Optional       bool   `json:"optional" yaml:"-" db:"-"`
 	AlwaysExecuted bool   `json:"always_executed" yaml:"-" db:"-"`
+	// execution policy, overridden by action_edge for steps
+	Timeout string       `json:"timeout,omitempty" yaml:"-" db:"timeout"`
+	Retry   *ActionRetry `json:"retry,omitempty" yaml:"-" db:"retry"`
+	// variants of a job
+	Matrix *JobMatrix `json:"matrix,omitempty" yaml:"-" db:"matrix"`
 	// aggregates
 	Requirements RequirementList `json:"requirements" db:"-"`
 	Parameters   []Parameter     `json:"parameters" db:"-"`
@@ -103,10 +108,23 @@ func (a Action) IsValid() error {
 		return err
 	}
 
+	if err := a.isRetryValid(); err != nil {
+		return err
+	}
+
+	if a.Matrix != nil {
+		if err := a.Matrix.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	for i := range a.Actions {
 		if a.Actions[i].ID == 0 {
 			return NewErrorFrom(ErrWrongRequest, "invalid action id for child")
 		}
+		if err := a.Actions[i].isRetryValid(); err != nil {
+			return err
+		}
 		for j := range a.Actions[i].Parameters {
 			if err := a.Actions[i].Parameters[j].IsValid(); err != nil {
 				return err
@@ -117,6 +135,16 @@ func (a Action) IsValid() error {
 	return nil
 }
 
+func (a Action) isRetryValid() error {
+	if _, err := ParseActionTimeout(a.Timeout); err != nil {
+		return err
+	}
+	if a.Retry != nil {
+		return a.Retry.IsValid()
+	}
+	return nil
+}
+
 // FlattenRequirements returns all requirements for an action and its children.
 func (a *Action) FlattenRequirements() RequirementList {
 	if !a.Enabled {
@@ -5,13 +5,41 @@ import (
 	"github.com/ovh/cds/sdk/exportentities"
 )
 
-var deployExample = exportentities.StepDeploy("{{.cds.application}}")
+var deployExample = exportentities.StepDeploy(map[string]string{
+	"strategy": sdk.DeploymentStrategyCanary,
+	"steps":    "10;50",
+	"wait":     "5m",
+})
 
 // DeployApplication action definition.
 var DeployApplication = Manifest{
 	Action: sdk.Action{
-		Name:        sdk.DeployApplicationAction,
-		Description: `Deploy an application, useful only if you have a Deployment Plaftorm associated to your current application.`,
+		Name: sdk.DeployApplicationAction,
+		Description: `Deploy an application, useful only if you have a Deployment Plaftorm associated to your current application.
+
+With a canary or blue-green strategy, the new release is promoted step by step by the integration plugin.
+Before each promotion, the deployment status reported by the plugin must be successful at the end of the wait,
+otherwise the deployment is aborted and the traffic is sent back to the previous release.`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "strategy",
+				Description: `Deployment strategy: direct (default), canary or blue-green.`,
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "steps",
+				Description: `Canary steps: percentages of traffic sent to the new release before it receives all the traffic, separated by ';' (default 10;50).`,
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "wait",
+				Description: `Time to wait before each promotion of a canary or blue-green deployment (ex: 5m).`,
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+		},
 	},
 	Example: exportentities.PipelineV1{
 		Version: exportentities.PipelineVersion1,
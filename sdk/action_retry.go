@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	json "encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retry limits
+const (
+	ActionRetryMaxAttempts = 10
+	ActionRetryMaxBackoff  = time.Hour
+
+	// ActionRetryOnTimeout is the value of retry.on matching a job or a step that timed out
+	ActionRetryOnTimeout = "timeout"
+)
+
+// ActionRetry describes how a job or a step is retried when it fails
+type ActionRetry struct {
+	Max     int      `json:"max" yaml:"max" jsonschema_description:"Maximum number of retries."`
+	Backoff string   `json:"backoff,omitempty" yaml:"backoff,omitempty" jsonschema_description:"Delay before the first retry (ex: 30s), doubled for each new attempt."`
+	On      []string `json:"on,omitempty" yaml:"on,omitempty" jsonschema_description:"Statuses (Fail, timeout) or exit codes triggering a retry, any failure by default."`
+}
+
+// Value returns driver.Value from action retry.
+func (r ActionRetry) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	return j, WrapError(err, "cannot marshal ActionRetry")
+}
+
+// Scan action retry.
+func (r *ActionRetry) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, r), "cannot unmarshal ActionRetry")
+}
+
+// IsValid returns an error if the retry configuration is not valid.
+func (r ActionRetry) IsValid() error {
+	if r.Max < 1 || r.Max > ActionRetryMaxAttempts {
+		return NewErrorFrom(ErrWrongRequest, "invalid retry max %d, it should be between 1 and %d", r.Max, ActionRetryMaxAttempts)
+	}
+	if r.Backoff != "" {
+		d, err := time.ParseDuration(r.Backoff)
+		if err != nil || d < 0 {
+			return NewErrorFrom(ErrWrongRequest, "invalid retry backoff %q", r.Backoff)
+		}
+	}
+	for _, on := range r.On {
+		if strings.EqualFold(on, StatusFail) || strings.EqualFold(on, ActionRetryOnTimeout) {
+			continue
+		}
+		if _, err := strconv.Atoi(on); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid retry condition %q, it should be %s, %s or an exit code", on, StatusFail, ActionRetryOnTimeout)
+		}
+	}
+	return nil
+}
+
+// Delay returns the time to wait before the given attempt (the first retry is the attempt 1).
+func (r ActionRetry) Delay(attempt int) time.Duration {
+	d, _ := time.ParseDuration(r.Backoff)
+	if d <= 0 || attempt < 1 {
+		return 0
+	}
+	for i := 1; i < attempt && d < ActionRetryMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > ActionRetryMaxBackoff {
+		d = ActionRetryMaxBackoff
+	}
+	return d
+}
+
+// Match returns true if the result should be retried.
+func (r ActionRetry) Match(res Result) bool {
+	if res.Status != StatusFail {
+		return false
+	}
+	if len(r.On) == 0 {
+		return true
+	}
+	for _, on := range r.On {
+		switch {
+		case strings.EqualFold(on, StatusFail):
+			return true
+		case strings.EqualFold(on, ActionRetryOnTimeout):
+			if res.TimedOut {
+				return true
+			}
+		default:
+			if code, err := strconv.Atoi(on); err == nil && res.ExitCode != nil && *res.ExitCode == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseActionTimeout returns the timeout of a job or a step, 0 if not set.
+func ParseActionTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		return 0, NewErrorFrom(ErrWrongRequest, "invalid timeout %q", timeout)
+	}
+	return d, nil
+}
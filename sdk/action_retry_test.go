@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRetry_IsValid(t *testing.T) {
+	assert.NoError(t, ActionRetry{Max: 1}.IsValid())
+	assert.NoError(t, ActionRetry{Max: 3, Backoff: "30s", On: []string{"Fail", "timeout", "137"}}.IsValid())
+	assert.Error(t, ActionRetry{}.IsValid())
+	assert.Error(t, ActionRetry{Max: ActionRetryMaxAttempts + 1}.IsValid())
+	assert.Error(t, ActionRetry{Max: 1, Backoff: "30"}.IsValid())
+	assert.Error(t, ActionRetry{Max: 1, On: []string{"Stopped"}}.IsValid())
+}
+
+func TestActionRetry_Delay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), ActionRetry{Max: 3}.Delay(1))
+	r := ActionRetry{Max: 10, Backoff: "10s"}
+	assert.Equal(t, 10*time.Second, r.Delay(1))
+	assert.Equal(t, 20*time.Second, r.Delay(2))
+	assert.Equal(t, 40*time.Second, r.Delay(3))
+	assert.Equal(t, ActionRetryMaxBackoff, r.Delay(10))
+}
+
+func TestActionRetry_Match(t *testing.T) {
+	exitCode := 7
+	failed := Result{Status: StatusFail, ExitCode: &exitCode}
+	timedOut := Result{Status: StatusFail, TimedOut: true}
+
+	assert.True(t, ActionRetry{Max: 1}.Match(failed))
+	assert.False(t, ActionRetry{Max: 1}.Match(Result{Status: StatusSuccess}))
+	assert.False(t, ActionRetry{Max: 1}.Match(Result{Status: StatusStopped}))
+
+	onTimeout := ActionRetry{Max: 1, On: []string{"timeout"}}
+	assert.True(t, onTimeout.Match(timedOut))
+	assert.False(t, onTimeout.Match(failed))
+
+	onExitCode := ActionRetry{Max: 1, On: []string{"7"}}
+	assert.True(t, onExitCode.Match(failed))
+	assert.False(t, onExitCode.Match(timedOut))
+}
+
+func TestParseActionTimeout(t *testing.T) {
+	d, err := ParseActionTimeout("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	d, err = ParseActionTimeout("1h30m")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	_, err = ParseActionTimeout("-1m")
+	assert.Error(t, err)
+	_, err = ParseActionTimeout("forever")
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deployment strategies of the DeployApplication action
+const (
+	DeploymentStrategyDirect    = "direct"
+	DeploymentStrategyCanary    = "canary"
+	DeploymentStrategyBlueGreen = "blue-green"
+	DeploymentStrategyRollback  = "rollback"
+)
+
+// DefaultCanarySteps are the traffic weights used by a canary deployment without steps
+const DefaultCanarySteps = "10;50"
+
+// DeployRollbackVersionParameter is set on the node run relaunched by a rollback, the DeployApplication
+// action then asks the integration plugin to restore this version instead of deploying a new one
+const DeployRollbackVersionParameter = "cds.deploy.rollback.version"
+
+// ApplicationDeployment is an entry of the deployment history of an application on an environment.
+// It is recorded at the end of each DeployApplication step.
+type ApplicationDeployment struct {
+	ID                int64     `json:"id" db:"id" cli:"id,key"`
+	ProjectID         int64     `json:"project_id" db:"project_id"`
+	ApplicationID     int64     `json:"application_id" db:"application_id"`
+	EnvironmentName   string    `json:"environment_name" db:"environment_name" cli:"environment"`
+	IntegrationName   string    `json:"integration_name" db:"integration_name"`
+	WorkflowName      string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowRunNumber int64     `json:"workflow_run_number" db:"num" cli:"run"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	DeploymentID      string    `json:"deployment_id,omitempty" db:"deployment_id"`
+	Version           string    `json:"version" db:"version" cli:"version"`
+	Strategy          string    `json:"strategy" db:"strategy" cli:"strategy"`
+	RollbackFrom      string    `json:"rollback_from,omitempty" db:"rollback_from"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Details           string    `json:"details,omitempty" db:"details"`
+	Author            string    `json:"author,omitempty" db:"author" cli:"author"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// ApplicationDeploymentRollback is the body of a rollback request, without version the previous release is restored
+type ApplicationDeploymentRollback struct {
+	Environment string `json:"environment"`
+	Version     string `json:"version,omitempty"`
+}
+
+// DeploymentSteps returns the traffic weights of the steps of a progressive deployment before the release
+// receives all the traffic. A direct deployment has no step and a blue-green deployment has a single step
+// where the new release is deployed without traffic.
+func DeploymentSteps(strategy, steps string) ([]int32, error) {
+	switch strategy {
+	case "", DeploymentStrategyDirect:
+		return nil, nil
+	case DeploymentStrategyBlueGreen:
+		return []int32{0}, nil
+	case DeploymentStrategyCanary:
+	default:
+		return nil, NewErrorFrom(ErrInvalidDeploymentStrategy, "unknown deployment strategy %q", strategy)
+	}
+
+	if strings.TrimSpace(steps) == "" {
+		steps = DefaultCanarySteps
+	}
+	var weights []int32
+	var last int64
+	for _, s := range strings.Split(steps, ";") {
+		s = strings.TrimSuffix(strings.TrimSpace(s), "%")
+		if s == "" {
+			continue
+		}
+		w, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || w <= last || w >= 100 {
+			return nil, NewErrorFrom(ErrInvalidDeploymentStrategy, "invalid canary steps %q, it should be increasing percentages between 1 and 99 separated by ';'", steps)
+		}
+		weights = append(weights, int32(w))
+		last = w
+	}
+	return weights, nil
+}
+
+// ApplicationDeploymentRollbackTarget returns the current deployment and the deployment to restore from a history
+// sorted from the newest to the oldest deployment. Without version, the last successful deployment of another
+// version that was not itself rolled back is selected.
+func ApplicationDeploymentRollbackTarget(history []ApplicationDeployment, version string) (*ApplicationDeployment, *ApplicationDeployment, error) {
+	var current *ApplicationDeployment
+	excluded := map[string]struct{}{}
+	for i := range history {
+		d := &history[i]
+		if d.Status != StatusSuccess {
+			continue
+		}
+		if current == nil {
+			current = d
+			if version == current.Version {
+				return nil, nil, NewErrorFrom(ErrWrongRequest, "version %s is already deployed", version)
+			}
+			excluded[d.Version] = struct{}{}
+			if d.RollbackFrom != "" {
+				excluded[d.RollbackFrom] = struct{}{}
+			}
+			continue
+		}
+
+		if version != "" {
+			if d.Version == version {
+				return current, d, nil
+			}
+			continue
+		}
+		if _, has := excluded[d.Version]; !has {
+			return current, d, nil
+		}
+		if d.RollbackFrom != "" {
+			excluded[d.RollbackFrom] = struct{}{}
+		}
+	}
+
+	if current == nil {
+		return nil, nil, NewErrorFrom(ErrApplicationDeploymentNotFound, "no successful deployment found")
+	}
+	if version != "" {
+		return nil, nil, NewErrorFrom(ErrApplicationDeploymentNotFound, "version %s was never deployed successfully", version)
+	}
+	return nil, nil, NewErrorFrom(ErrApplicationDeploymentNotFound, "no previous version to roll back to")
+}
+
+// RollbackJob returns the job to run when a node run is relaunched by a rollback: only its DeployApplication
+// steps are kept and the job is not expanded as a matrix. It returns false if the job has no DeployApplication step.
+func RollbackJob(job Job) (Job, bool) {
+	var steps []Action
+	for _, a := range job.Action.Actions {
+		if a.Type == BuiltinAction && a.Name == DeployApplicationAction {
+			steps = append(steps, a)
+		}
+	}
+	job.Action.Actions = steps
+	job.Action.Matrix = nil
+	return job, len(steps) > 0
+}
@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentSteps(t *testing.T) {
+	steps, err := DeploymentSteps("", "")
+	require.NoError(t, err)
+	assert.Empty(t, steps)
+
+	steps, err = DeploymentSteps(DeploymentStrategyBlueGreen, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0}, steps)
+
+	steps, err = DeploymentSteps(DeploymentStrategyCanary, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{10, 50}, steps)
+
+	steps, err = DeploymentSteps(DeploymentStrategyCanary, "5%; 25;75")
+	require.NoError(t, err)
+	assert.Equal(t, []int32{5, 25, 75}, steps)
+
+	_, err = DeploymentSteps(DeploymentStrategyCanary, "50;25")
+	assert.True(t, ErrorIs(err, ErrInvalidDeploymentStrategy))
+	_, err = DeploymentSteps(DeploymentStrategyCanary, "10;100")
+	assert.True(t, ErrorIs(err, ErrInvalidDeploymentStrategy))
+	_, err = DeploymentSteps("rolling", "")
+	assert.True(t, ErrorIs(err, ErrInvalidDeploymentStrategy))
+}
+
+func TestApplicationDeploymentRollbackTarget(t *testing.T) {
+	// newest first
+	history := []ApplicationDeployment{
+		{ID: 6, Version: "4", Status: StatusFail},
+		{ID: 5, Version: "3", Status: StatusSuccess},
+		{ID: 4, Version: "1", Status: StatusSuccess, Strategy: DeploymentStrategyRollback, RollbackFrom: "2"},
+		{ID: 3, Version: "2", Status: StatusSuccess},
+		{ID: 2, Version: "1", Status: StatusSuccess},
+		{ID: 1, Version: "0", Status: StatusSuccess},
+	}
+
+	current, target, err := ApplicationDeploymentRollbackTarget(history, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), current.ID)
+	assert.Equal(t, int64(4), target.ID)
+
+	// Rolling back the rollback skips the version that was rolled back
+	current, target, err = ApplicationDeploymentRollbackTarget(history[2:], "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), current.ID)
+	assert.Equal(t, "0", target.Version)
+
+	_, target, err = ApplicationDeploymentRollbackTarget(history, "2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), target.ID)
+
+	_, _, err = ApplicationDeploymentRollbackTarget(history, "3")
+	assert.Error(t, err)
+	_, _, err = ApplicationDeploymentRollbackTarget(history, "4")
+	assert.True(t, ErrorIs(err, ErrApplicationDeploymentNotFound))
+	_, _, err = ApplicationDeploymentRollbackTarget(history[5:], "")
+	assert.True(t, ErrorIs(err, ErrApplicationDeploymentNotFound))
+	_, _, err = ApplicationDeploymentRollbackTarget(nil, "")
+	assert.True(t, ErrorIs(err, ErrApplicationDeploymentNotFound))
+}
+
+func TestRollbackJob(t *testing.T) {
+	job := Job{Enabled: true, Action: Action{
+		Name:   "deploy",
+		Matrix: &JobMatrix{Variables: map[string][]string{"os": {"linux", "windows"}}},
+		Actions: []Action{
+			{Name: CheckoutApplicationAction, Type: BuiltinAction},
+			{Name: "build", Type: DefaultAction},
+			{Name: DeployApplicationAction, Type: BuiltinAction},
+		},
+	}}
+
+	rollback, ok := RollbackJob(job)
+	require.True(t, ok)
+	assert.Nil(t, rollback.Action.Matrix)
+	require.Len(t, rollback.Action.Actions, 1)
+	assert.Equal(t, DeployApplicationAction, rollback.Action.Actions[0].Name)
+	assert.Len(t, job.Action.Actions, 3, "the original job should not be modified")
+
+	_, ok = RollbackJob(Job{Action: Action{Actions: []Action{{Name: "build", Type: DefaultAction}}}})
+	assert.False(t, ok)
+}
@@ -46,3 +46,26 @@ func (c *client) ApplicationAttachToReposistoriesManager(projectKey, appName, re
 	_, _, _, err := c.Request(context.Background(), "POST", uri, nil)
 	return err
 }
+
+// ApplicationDeploymentHistory returns the last deployments of an application, from the newest to the oldest
+func (c *client) ApplicationDeploymentHistory(projectKey string, appName string, environment string) ([]sdk.ApplicationDeployment, error) {
+	uri := fmt.Sprintf("/project/%s/application/%s/deployment/history", projectKey, appName)
+	if environment != "" {
+		uri += "?environment=" + url.QueryEscape(environment)
+	}
+	history := []sdk.ApplicationDeployment{}
+	if _, err := c.GetJSON(context.Background(), uri, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// ApplicationDeploymentRollback relaunches the current deployment of an application to restore a previous version
+func (c *client) ApplicationDeploymentRollback(projectKey string, appName string, rollback sdk.ApplicationDeploymentRollback) (*sdk.WorkflowRun, error) {
+	uri := fmt.Sprintf("/project/%s/application/%s/deployment/rollback", projectKey, appName)
+	run := &sdk.WorkflowRun{}
+	if _, err := c.PostJSON(context.Background(), uri, rollback, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
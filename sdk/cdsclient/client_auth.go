@@ -20,6 +20,12 @@ func (c *client) AuthConsumerSignin(consumerType sdk.AuthConsumerType, request s
 	return res, err
 }
 
+func (c *client) AuthConsumerDeviceCode(consumerType sdk.AuthConsumerType) (sdk.AuthDriverDeviceCode, error) {
+	var res sdk.AuthDriverDeviceCode
+	_, _, _, err := c.RequestJSON(context.Background(), "POST", "/auth/consumer/"+string(consumerType)+"/deviceCode", nil, &res)
+	return res, err
+}
+
 func (c *client) AuthConsumerSignout() error {
 	_, _, _, err := c.RequestJSON(context.Background(), "POST", "/auth/consumer/signout", nil, nil)
 	return err
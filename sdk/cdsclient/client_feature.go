@@ -0,0 +1,34 @@
+package cdsclient
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) AdminFeatureList() ([]sdk.Feature, error) {
+	var fs []sdk.Feature
+	if _, err := c.GetJSON(context.Background(), "/admin/feature", &fs); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (c *client) AdminFeatureGet(name string) (*sdk.Feature, error) {
+	var f sdk.Feature
+	if _, err := c.GetJSON(context.Background(), "/admin/feature/"+url.PathEscape(name), &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (c *client) AdminFeatureSet(f sdk.Feature) error {
+	_, err := c.PutJSON(context.Background(), "/admin/feature/"+url.PathEscape(f.Name), f, nil)
+	return err
+}
+
+func (c *client) AdminFeatureDelete(name string) error {
+	_, err := c.DeleteJSON(context.Background(), "/admin/feature/"+url.PathEscape(name), nil)
+	return err
+}
@@ -1,7 +1,9 @@
 package cdsclient
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -25,3 +27,13 @@ func (c *client) PollVCSEvents(uuid string, workflowID int64, vcsServer string,
 
 	return events, interval, nil
 }
+
+// HookChangedFiles returns the files changed between two refs of the repository of the given hook
+func (c *client) HookChangedFiles(uuid, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/hook/%s/changes?base=%s&head=%s", uuid, url.QueryEscape(base), url.QueryEscape(head))
+	if _, err := c.GetJSON(context.Background(), path, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
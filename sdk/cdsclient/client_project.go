@@ -83,3 +83,11 @@ func (c *client) ProjectGroupsImport(projectKey string, content io.Reader, forma
 
 	return proj, nil
 }
+
+func (c *client) ProjectConcurrencyLocks(projectKey string) ([]sdk.WorkflowNodeRunLock, error) {
+	var locks []sdk.WorkflowNodeRunLock
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/concurrency/locks", &locks); err != nil {
+		return nil, err
+	}
+	return locks, nil
+}
@@ -89,6 +89,23 @@ func (c *client) ProjectIntegrationDelete(projectKey string, integrationName str
 	return nil
 }
 
+func (c *client) ProjectIntegrationEventsStatus(projectKey string, integrationName string) (sdk.EventIntegrationStatus, error) {
+	path := fmt.Sprintf("/project/%s/integrations/%s/events/status", projectKey, integrationName)
+	var status sdk.EventIntegrationStatus
+	if _, err := c.GetJSON(context.Background(), path, &status); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+func (c *client) ProjectIntegrationEventsReplay(projectKey string, integrationName string, req sdk.EventReplayRequest) error {
+	path := fmt.Sprintf("/project/%s/integrations/%s/events/replay", projectKey, integrationName)
+	if _, err := c.PostJSON(context.Background(), path, req, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *client) ProjectIntegrationImport(projectKey string, content io.Reader, format string, force bool) (sdk.ProjectIntegration, error) {
 	var pf sdk.ProjectIntegration
 
@@ -273,6 +273,12 @@ func (c *client) QueueSendVulnerability(ctx context.Context, id int64, report sd
 	return err
 }
 
+func (c *client) QueueSendDeployment(ctx context.Context, id int64, deployment sdk.ApplicationDeployment) error {
+	path := fmt.Sprintf("/queue/workflows/%d/deployment", id)
+	_, err := c.PostJSON(ctx, path, deployment, nil)
+	return err
+}
+
 func (c *client) QueueSendStepResult(ctx context.Context, id int64, res sdk.StepStatus) error {
 	path := fmt.Sprintf("/queue/workflows/%d/step", id)
 	_, err := c.PostJSON(ctx, path, res, nil)
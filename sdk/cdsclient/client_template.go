@@ -151,3 +151,36 @@ func (c *client) TemplateDeleteInstance(groupName, templateSlug string, id int64
 
 	return nil
 }
+
+func (c *client) TemplatePublish(groupName, templateSlug, tag string) (*sdk.WorkflowTemplateVersion, error) {
+	url := fmt.Sprintf("/template/%s/%s/publish", groupName, templateSlug)
+
+	var res sdk.WorkflowTemplateVersion
+	if _, err := c.PostJSON(context.Background(), url, sdk.WorkflowTemplatePublish{Tag: tag}, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *client) TemplateGetVersions(groupName, templateSlug string) ([]sdk.WorkflowTemplateVersion, error) {
+	url := fmt.Sprintf("/template/%s/%s/version", groupName, templateSlug)
+
+	var wtvs []sdk.WorkflowTemplateVersion
+	if _, err := c.GetJSON(context.Background(), url, &wtvs); err != nil {
+		return nil, err
+	}
+
+	return wtvs, nil
+}
+
+func (c *client) TemplateGetUpgrades(groupName, templateSlug string) ([]sdk.WorkflowTemplateInstanceUpgrade, error) {
+	url := fmt.Sprintf("/template/%s/%s/upgrade", groupName, templateSlug)
+
+	var upgrades []sdk.WorkflowTemplateInstanceUpgrade
+	if _, err := c.GetJSON(context.Background(), url, &upgrades); err != nil {
+		return nil, err
+	}
+
+	return upgrades, nil
+}
@@ -78,6 +78,22 @@ func (c *client) WorkflowRunsDeleteByBranch(projectKey string, workflowName stri
 	return nil
 }
 
+func (c *client) WorkflowPurge(projectKey string, workflowName string, dryRun bool) ([]sdk.WorkflowRunRetentionResult, error) {
+	var res []sdk.WorkflowRunRetentionResult
+	if dryRun {
+		url := fmt.Sprintf("/project/%s/workflows/%s/purge/dryrun", projectKey, workflowName)
+		if _, err := c.GetJSON(context.Background(), url, &res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+	url := fmt.Sprintf("/project/%s/workflows/%s/purge", projectKey, workflowName)
+	if _, err := c.PostJSON(context.Background(), url, nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (c *client) WorkflowRunResync(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/resync", projectKey, workflowName, number)
 	var run sdk.WorkflowRun
@@ -252,6 +268,15 @@ func (c *client) WorkflowRunFromManual(projectKey string, workflowName string, m
 	return run, nil
 }
 
+func (c *client) WorkflowRunFromCheckRun(projectKey string, workflowName string, number int64, rerun sdk.WorkflowCheckRunRerun) (*sdk.WorkflowRun, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/checks/rerun", projectKey, workflowName, number)
+	run := &sdk.WorkflowRun{}
+	if _, err := c.PostJSON(context.Background(), url, &rerun, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
 func (c *client) WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/stop", projectKey, workflowName, number)
 
@@ -15,3 +15,12 @@ func (c *client) WorkflowAllHooksList() ([]sdk.NodeHook, error) {
 	}
 	return w, nil
 }
+
+func (c *client) WorkflowHookSecretRotate(projectKey, workflowName, uuid string) (*sdk.NodeHook, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/hooks/%s/secret/rotate", projectKey, workflowName, uuid)
+	var h sdk.NodeHook
+	if _, err := c.PostJSON(context.Background(), url, nil, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
@@ -29,6 +29,9 @@ type TemplateClient interface {
 	TemplateDelete(groupName, templateSlug string) error
 	TemplateGetInstances(groupName, templateSlug string) ([]sdk.WorkflowTemplateInstance, error)
 	TemplateDeleteInstance(groupName, templateSlug string, id int64) error
+	TemplatePublish(groupName, templateSlug, tag string) (*sdk.WorkflowTemplateVersion, error)
+	TemplateGetVersions(groupName, templateSlug string) ([]sdk.WorkflowTemplateVersion, error)
+	TemplateGetUpgrades(groupName, templateSlug string) ([]sdk.WorkflowTemplateInstanceUpgrade, error)
 }
 
 // Admin expose all function to CDS administration
@@ -45,6 +48,10 @@ type Admin interface {
 	AdminCDSMigrationList() ([]sdk.Migration, error)
 	AdminCDSMigrationCancel(id int64) error
 	AdminCDSMigrationReset(id int64) error
+	AdminFeatureList() ([]sdk.Feature, error)
+	AdminFeatureGet(name string) (*sdk.Feature, error)
+	AdminFeatureSet(f sdk.Feature) error
+	AdminFeatureDelete(name string) error
 	Services() ([]sdk.Service, error)
 	ServicesByName(name string) (*sdk.Service, error)
 	ServiceDelete(name string) error
@@ -91,6 +98,8 @@ type ApplicationClient interface {
 	ApplicationDelete(projectKey string, appName string) error
 	ApplicationGet(projectKey string, appName string, opts ...RequestModifier) (*sdk.Application, error)
 	ApplicationList(projectKey string) ([]sdk.Application, error)
+	ApplicationDeploymentHistory(projectKey string, appName string, environment string) ([]sdk.ApplicationDeployment, error)
+	ApplicationDeploymentRollback(projectKey string, appName string, rollback sdk.ApplicationDeploymentRollback) (*sdk.WorkflowRun, error)
 	ApplicationVariableClient
 	ApplicationKeysClient
 }
@@ -211,8 +220,11 @@ type ProjectClient interface {
 	ProjectIntegrationGet(projectKey string, integrationName string, clearPassword bool) (sdk.ProjectIntegration, error)
 	ProjectIntegrationList(projectKey string) ([]sdk.ProjectIntegration, error)
 	ProjectIntegrationDelete(projectKey string, integrationName string) error
+	ProjectIntegrationEventsStatus(projectKey string, integrationName string) (sdk.EventIntegrationStatus, error)
+	ProjectIntegrationEventsReplay(projectKey string, integrationName string, req sdk.EventReplayRequest) error
 	ProjectRepositoryManagerList(projectKey string) ([]sdk.ProjectVCSServer, error)
 	ProjectRepositoryManagerDelete(projectKey string, repoManagerName string, force bool) error
+	ProjectConcurrencyLocks(projectKey string) ([]sdk.WorkflowNodeRunLock, error)
 }
 
 // ProjectKeysClient exposes project keys related functions
@@ -246,6 +258,7 @@ type QueueClient interface {
 	QueueSendUnitTests(ctx context.Context, id int64, report venom.Tests) error
 	QueueSendLogs(ctx context.Context, id int64, log sdk.Log) error
 	QueueSendVulnerability(ctx context.Context, id int64, report sdk.VulnerabilityWorkerReport) error
+	QueueSendDeployment(ctx context.Context, id int64, deployment sdk.ApplicationDeployment) error
 	QueueSendStepResult(ctx context.Context, id int64, res sdk.StepStatus) error
 	QueueSendResult(ctx context.Context, id int64, res sdk.Result) error
 	QueueArtifactUpload(ctx context.Context, projectKey, integrationName string, nodeJobRunID int64, tag, filePath string) (bool, time.Duration, error)
@@ -283,6 +296,7 @@ type WorkerClient interface {
 // HookClient exposes functions used for hooks services
 type HookClient interface {
 	PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, err error)
+	HookChangedFiles(uuid, base, head string) ([]string, error)
 	VCSConfiguration() (map[string]sdk.VCSConfiguration, error)
 }
 
@@ -296,12 +310,14 @@ type WorkflowClient interface {
 	WorkflowGroupDelete(projectKey, name, groupName string) error
 	WorkflowRunGet(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunsDeleteByBranch(projectKey string, workflowName string, branch string) error
+	WorkflowPurge(projectKey string, workflowName string, dryRun bool) ([]sdk.WorkflowRunRetentionResult, error)
 	WorkflowRunResync(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowRunList(projectKey string, workflowName string, offset, limit int64) ([]sdk.WorkflowRun, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
 	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
 	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
+	WorkflowRunFromCheckRun(projectKey string, workflowName string, number int64, rerun sdk.WorkflowCheckRunRerun) (*sdk.WorkflowRun, error)
 	WorkflowRunNumberGet(projectKey string, workflowName string) (*sdk.WorkflowRunNumber, error)
 	WorkflowRunNumberSet(projectKey string, workflowName string, number int64) error
 	WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
@@ -311,6 +327,7 @@ type WorkflowClient interface {
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
 	WorkflowAllHooksList() ([]sdk.NodeHook, error)
+	WorkflowHookSecretRotate(projectKey, workflowName, uuid string) (*sdk.NodeHook, error)
 	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
 	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
 	WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error)
@@ -460,6 +477,7 @@ func WithUsage() RequestModifier {
 type AuthClient interface {
 	AuthDriverList() (sdk.AuthDriverResponse, error)
 	AuthConsumerSignin(sdk.AuthConsumerType, sdk.AuthConsumerSigninRequest) (sdk.AuthConsumerSigninResponse, error)
+	AuthConsumerDeviceCode(sdk.AuthConsumerType) (sdk.AuthDriverDeviceCode, error)
 	AuthConsumerLocalAskResetPassword(sdk.AuthConsumerSigninRequest) error
 	AuthConsumerLocalResetPassword(token, newPassword string) (sdk.AuthConsumerSigninResponse, error)
 	AuthConsumerLocalSignup(sdk.AuthConsumerSigninRequest) error
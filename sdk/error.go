@@ -200,6 +200,16 @@ var (
 	ErrInvalidJobRequirementNetworkAccess            = Error{ID: 184, Status: http.StatusBadRequest}
 	ErrInvalidWorkerModelNamePattern                 = Error{ID: 185, Status: http.StatusBadRequest}
 	ErrWorkflowAsCodeResync                          = Error{ID: 186, Status: http.StatusForbidden}
+	ErrInvalidExternalSecret                         = Error{ID: 187, Status: http.StatusBadRequest}
+	ErrExternalSecretNotFound                        = Error{ID: 188, Status: http.StatusNotFound}
+	ErrWorkflowConcurrencyInvalid                    = Error{ID: 189, Status: http.StatusBadRequest}
+	ErrJobNotReady                                   = Error{ID: 190, Status: http.StatusConflict}
+	ErrSigninPending                                 = Error{ID: 191, Status: http.StatusBadRequest}
+	ErrInvalidWebHookSignature                       = Error{ID: 192, Status: http.StatusUnauthorized}
+	ErrApplicationDeploymentNotFound                 = Error{ID: 193, Status: http.StatusNotFound}
+	ErrInvalidDeploymentStrategy                     = Error{ID: 194, Status: http.StatusBadRequest}
+	ErrWorkflowTemplateVersionNotFound               = Error{ID: 195, Status: http.StatusNotFound}
+	ErrHookChangedFilesUnknown                       = Error{ID: 196, Status: http.StatusUnprocessableEntity}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -382,6 +392,16 @@ var errorsAmericanEnglish = map[int]string{
 	ErrBadBrokerConfiguration.ID:                        "Cannot connect to the broker of your event integration. Check your configuration",
 	ErrInvalidJobRequirementNetworkAccess.ID:            "Invalid job requirement: network requirement must contains ':'. Example: golang.org:http, golang.org:443",
 	ErrWorkflowAsCodeResync.ID:                          "You cannot resynchronize an as-code workflow",
+	ErrInvalidExternalSecret.ID:                         "Invalid external secret reference, it should be <provider>:<path>[#key]",
+	ErrExternalSecretNotFound.ID:                        "External secret not found",
+	ErrWorkflowConcurrencyInvalid.ID:                    "Invalid concurrency group",
+	ErrJobNotReady.ID:                                   "Job is waiting before being retried",
+	ErrSigninPending.ID:                                 "Signin is pending, the authorization is not completed yet",
+	ErrInvalidWebHookSignature.ID:                       "Invalid webhook signature",
+	ErrApplicationDeploymentNotFound.ID:                 "No deployment found for this application",
+	ErrInvalidDeploymentStrategy.ID:                     "Invalid deployment strategy",
+	ErrWorkflowTemplateVersionNotFound.ID:               "No version of the template matches the given range",
+	ErrHookChangedFilesUnknown.ID:                       "Changed files between the commits cannot be computed by the repository manager",
 }
 
 var errorsFrench = map[int]string{
@@ -563,6 +583,16 @@ var errorsFrench = map[int]string{
 	ErrBadBrokerConfiguration.ID:                        "Impossible de se connecter à votre intégration de type évènement. Veuillez vérifier votre configuration",
 	ErrInvalidJobRequirementNetworkAccess.ID:            "Pré-requis de job invalide: Le pré-requis network doit contenir un ':'. Exemple: golang.org:http, golang.org:443",
 	ErrWorkflowAsCodeResync.ID:                          "Impossible de resynchroniser un workflow en mode as-code",
+	ErrInvalidExternalSecret.ID:                         "Référence de secret externe invalide, elle doit être de la forme <provider>:<path>[#key]",
+	ErrExternalSecretNotFound.ID:                        "Secret externe introuvable",
+	ErrWorkflowConcurrencyInvalid.ID:                    "Groupe de concurrence invalide",
+	ErrJobNotReady.ID:                                   "Le job est en attente avant d'être relancé",
+	ErrSigninPending.ID:                                 "La connexion est en attente, l'autorisation n'est pas encore terminée",
+	ErrInvalidWebHookSignature.ID:                       "Signature du webhook invalide",
+	ErrApplicationDeploymentNotFound.ID:                 "Aucun déploiement trouvé pour cette application",
+	ErrInvalidDeploymentStrategy.ID:                     "Stratégie de déploiement invalide",
+	ErrWorkflowTemplateVersionNotFound.ID:               "Aucune version du modèle ne correspond à l'intervalle donné",
+	ErrHookChangedFilesUnknown.ID:                       "Les fichiers modifiés entre les commits ne peuvent pas être calculés par le gestionnaire de dépôt",
 }
 
 var errorsLanguages = []map[int]string{
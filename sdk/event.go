@@ -43,6 +43,24 @@ type EventFilter struct {
 	Filter      TimelineFilter `json:"filter"`
 }
 
+// EventReplayRequest is used to replay the persisted events of a project on an event integration
+//easyjson:json
+type EventReplayRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to,omitempty"`
+}
+
+// EventHistoryFilter represents filters when getting the persisted events of a project, sorted by date
+//easyjson:json
+type EventHistoryFilter struct {
+	ProjectKey string    `json:"project_key"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to,omitempty"`
+	// SearchAfter are the sort values of the last event of the previous page, empty for the first page
+	SearchAfter []interface{} `json:"search_after,omitempty"`
+	Size        int           `json:"size"`
+}
+
 // EventSubscription data send to api to subscribe to an event
 //easyjson:json
 type EventSubscription struct {
@@ -88,6 +106,7 @@ type EventRunWorkflowNode struct {
 	NodeType              string                    `json:"node_type,omitempty"`
 	GerritChange          *GerritChangeEvent        `json:"gerrit_change,omitempty"`
 	EventIntegrations     []int64                   `json:"event_integrations_id,omitempty"`
+	Annotations           []VCSCheckAnnotation      `json:"annotations,omitempty"`
 }
 
 // GerritChangeEvent Gerrit information that are needed on event
@@ -122,6 +141,16 @@ type EventRunWorkflowJob struct {
 	Done   int64  `json:"done,omitempty"`
 }
 
+// EventRunWorkflowSecretResolved contains event data when an external secret is resolved for a workflow run
+//easyjson:json
+type EventRunWorkflowSecretResolved struct {
+	NodeRunID int64  `json:"node_run_id,omitempty"`
+	Name      string `json:"name"`
+	Provider  string `json:"provider"`
+	Path      string `json:"path"`
+	Key       string `json:"key,omitempty"`
+}
+
 // EventRunWorkflow contains event data for a workflow run
 //easyjson:json
 type EventRunWorkflow struct {
@@ -43,14 +43,17 @@ type Stage struct {
 
 // Job represents exported sdk.Job
 type Job struct {
-	Name           string        `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
-	Stage          string        `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
-	Description    string        `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
-	Enabled        *bool         `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
-	Steps          []Step        `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
-	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
-	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
-	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Name           string           `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
+	Stage          string           `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
+	Description    string           `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
+	Enabled        *bool            `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
+	Steps          []Step           `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
+	Requirements   []Requirement    `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
+	Optional       *bool            `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
+	AlwaysExecuted *bool            `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Timeout        string           `json:"timeout,omitempty" yaml:"timeout,omitempty" jsonschema_description:"Maximum duration of the job (ex: 30m)."`
+	Retry          *sdk.ActionRetry `json:"retry,omitempty" yaml:"retry,omitempty" jsonschema_description:"Retry the job when it fails."`
+	Matrix         *sdk.JobMatrix   `json:"matrix,omitempty" yaml:"matrix,omitempty" jsonschema_description:"Run the job for each variant of the matrix."`
 }
 
 // Requirement represents an exported sdk.Requirement
@@ -169,6 +172,9 @@ func newJob(j sdk.Job) Job {
 	jo.Steps = newSteps(j.Action)
 	jo.Description = j.Action.Description
 	jo.Requirements = newRequirements(j.Action.Requirements)
+	jo.Timeout = j.Action.Timeout
+	jo.Retry = j.Action.Retry
+	jo.Matrix = j.Action.Matrix
 	return jo
 }
 
@@ -258,6 +264,14 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	}
 	job.Action.Enabled = job.Enabled
 	job.Action.Requirements = computeJobRequirements(j.Requirements)
+	job.Action.Timeout = j.Timeout
+	job.Action.Retry = j.Retry
+	job.Action.Matrix = j.Matrix
+	if j.Matrix != nil {
+		if err := j.Matrix.IsValid(); err != nil {
+			return nil, sdk.WrapError(err, "invalid matrix for job %s", name)
+		}
+	}
 
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
@@ -535,6 +535,52 @@ jobs:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Requirements, 2)
 }
 
+func Test_ImportPipelineWithMatrix(t *testing.T) {
+	in := `name: test
+jobs:
+- job: unit
+  timeout: 30m
+  matrix:
+    variables:
+      go: ["1.13", "1.14"]
+      os-architecture: [linux/amd64, darwin/amd64]
+    exclude:
+    - go: "1.13"
+      os-architecture: darwin/amd64
+    fail_fast: true
+    max_parallel: 2
+  steps:
+  - script: go test ./...
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	assert.Equal(t, "30m", job.Action.Timeout)
+	test.NotNil(t, job.Action.Matrix)
+	assert.True(t, job.Action.Matrix.FailFast)
+	assert.Equal(t, 2, job.Action.Matrix.MaxParallel)
+	assert.Len(t, job.Action.Matrix.Variants(), 3)
+
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Equal(t, job.Action.Matrix, exported.Jobs[0].Matrix)
+
+	// Invalid matrices are refused on import
+	for _, matrix := range []string{
+		`{variables: {"go version": ["1.13"]}}`,
+		`{variables: {a: [1, 2, 3, 4, 5, 6, 7, 8, 9], b: [1, 2, 3, 4, 5, 6, 7, 8, 9]}}`,
+	} {
+		payload := &exportentities.PipelineV1{}
+		test.NoError(t, yaml.Unmarshal([]byte("name: build\njobs:\n- job: unit\n  matrix: "+matrix+"\n  steps:\n  - script: go test ./...\n"), payload))
+		_, err := payload.Pipeline()
+		assert.Error(t, err, matrix)
+	}
+}
+
 func Test_ImportPipelineWithGitClone(t *testing.T) {
 	in := `name: build-all-images
 jobs:
@@ -596,6 +642,50 @@ jobs:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 1)
 }
 
+func Test_ImportPipelineWithDeploy(t *testing.T) {
+	in := `name: deploy
+jobs:
+- job: deploy
+  steps:
+  - deploy: '{{.cds.application}}'
+  - deploy:
+      strategy: canary
+      steps: 10;50
+      wait: 5m
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	actions := p.Stages[0].Jobs[0].Action.Actions
+	assert.Len(t, actions, 2)
+	assert.Equal(t, sdk.DeployApplicationAction, actions[0].Name)
+	assert.Len(t, actions[0].Parameters, 0)
+	assert.Equal(t, sdk.DeployApplicationAction, actions[1].Name)
+	assert.Equal(t, "canary", sdk.ParameterValue(actions[1].Parameters, "strategy"))
+	assert.Equal(t, "10;50", sdk.ParameterValue(actions[1].Parameters, "steps"))
+	assert.Equal(t, "5m", sdk.ParameterValue(actions[1].Parameters, "wait"))
+
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Equal(t, "{{.cds.application}}", *exported.Jobs[0].Steps[0].Deploy)
+	assert.Equal(t, map[string]string{"strategy": "canary", "steps": "10;50", "wait": "5m"}, *exported.Jobs[0].Steps[1].Deploy)
+
+	in = `name: deploy
+jobs:
+- job: deploy
+  steps:
+  - deploy:
+      strategy: rolling
+`
+	payload = &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
 func Test_ImportPipelineWithOneStageAndRunConditions(t *testing.T) {
 	in := `version: v1.0
 name: echo
@@ -26,6 +26,8 @@ func newStep(act sdk.Action) Step {
 	if act.AlwaysExecuted {
 		s.AlwaysExecuted = &sdk.True
 	}
+	s.Timeout = act.Timeout
+	s.Retry = act.Retry
 
 	switch act.Type {
 	case sdk.BuiltinAction:
@@ -216,6 +218,15 @@ func newStep(act sdk.Action) Step {
 			}
 		case sdk.DeployApplicationAction:
 			step := StepDeploy("{{.cds.application}}")
+			m := map[string]string{}
+			for _, name := range []string{"strategy", "steps", "wait"} {
+				if v := sdk.ParameterValue(act.Parameters, name); v != "" {
+					m[name] = v
+				}
+			}
+			if len(m) > 0 {
+				step = StepDeploy(m)
+			}
 			s.Deploy = &step
 		}
 	default:
@@ -316,16 +327,18 @@ type StepCheckout string
 // StepInstallKey represents exported installKey step.
 type StepInstallKey interface{}
 
-// StepDeploy represents exported deploy step.
-type StepDeploy string
+// StepDeploy represents exported deploy step, it can be a string or a map with the deployment strategy.
+type StepDeploy interface{}
 
 // Step represents exported step used in a job.
 type Step struct {
 	// common step data
-	Name           string `json:"name,omitempty" yaml:"name,omitempty" jsonschema_description:"The name for this step."`
-	Enabled        *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
-	Optional       *bool  `json:"optional,omitempty" yaml:"optional,omitempty"`
-	AlwaysExecuted *bool  `json:"always_executed,omitempty" yaml:"always_executed,omitempty"`
+	Name           string           `json:"name,omitempty" yaml:"name,omitempty" jsonschema_description:"The name for this step."`
+	Enabled        *bool            `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Optional       *bool            `json:"optional,omitempty" yaml:"optional,omitempty"`
+	AlwaysExecuted *bool            `json:"always_executed,omitempty" yaml:"always_executed,omitempty"`
+	Timeout        string           `json:"timeout,omitempty" yaml:"timeout,omitempty" jsonschema_description:"Maximum duration of the step (ex: 5m)."`
+	Retry          *sdk.ActionRetry `json:"retry,omitempty" yaml:"retry,omitempty" jsonschema_description:"Retry the step when it fails."`
 	// step specific data, only one option should be set
 	StepCustom       `json:"-" yaml:",inline"`
 	Script           interface{}           `json:"script,omitempty" yaml:"script,omitempty" jsonschema:"-" jsonschema_description:"Script.\nhttps://ovh.github.io/cds/docs/actions/builtin-script"`
@@ -490,7 +503,7 @@ func (s Step) toAction() (*sdk.Action, error) {
 	} else if s.isInstallKey() {
 		a = s.asInstallKey()
 	} else if s.isDeploy() {
-		a = s.asDeployApplication()
+		a, err = s.asDeployApplication()
 	} else if s.isCoverage() {
 		a, err = s.asCoverage()
 	} else if s.isScript() {
@@ -506,6 +519,8 @@ func (s Step) toAction() (*sdk.Action, error) {
 	a.Enabled = s.Enabled == nil || *s.Enabled == sdk.True // enabled is true by default
 	a.Optional = s.Optional != nil && *s.Optional == sdk.True
 	a.AlwaysExecuted = s.AlwaysExecuted != nil && *s.AlwaysExecuted == sdk.True
+	a.Timeout = s.Timeout
+	a.Retry = s.Retry
 
 	return &a, nil
 }
@@ -640,11 +655,42 @@ func (s Step) asCoverage() (sdk.Action, error) {
 
 func (s Step) isDeploy() bool { return s.Deploy != nil }
 
-func (s Step) asDeployApplication() sdk.Action {
-	return sdk.Action{
+func (s Step) asDeployApplication() (sdk.Action, error) {
+	a := sdk.Action{
 		Name: sdk.DeployApplicationAction,
 		Type: sdk.BuiltinAction,
 	}
+
+	var m map[string]string
+	switch v := (*s.Deploy).(type) {
+	case map[string]string:
+		m = v
+	case map[string]interface{}:
+		m = make(map[string]string, len(v))
+		for k, value := range v {
+			m[k] = fmt.Sprintf("%v", value)
+		}
+	case map[interface{}]interface{}:
+		m = make(map[string]string, len(v))
+		for k, value := range v {
+			m[fmt.Sprintf("%v", k)] = fmt.Sprintf("%v", value)
+		}
+	default:
+		return a, nil
+	}
+
+	for k := range m {
+		switch k {
+		case "strategy", "steps", "wait":
+		default:
+			return a, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown deploy parameter %q", k)
+		}
+	}
+	if _, err := sdk.DeploymentSteps(m["strategy"], m["steps"]); err != nil {
+		return a, err
+	}
+	a.Parameters = sdk.ParametersFromMap(m)
+	return a, nil
 }
 
 func (s Step) isServeStaticFiles() bool { return s.ServeStaticFiles != nil }
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 )
 
@@ -68,6 +69,20 @@ var tests = []struct {
 		Json: `{"script":["line1","line2"]}`,
 		Yaml: "script:\n- line1\n- line2\n",
 	},
+	{
+		Name: "Step with timeout and retry",
+		Step: exportentities.Step{
+			Script:  []interface{}{"curl -f http://my-service"},
+			Timeout: "5m",
+			Retry: &sdk.ActionRetry{
+				Max:     3,
+				Backoff: "10s",
+				On:      []string{"timeout", "7"},
+			},
+		},
+		Json: `{"timeout":"5m","retry":{"max":3,"backoff":"10s","on":["timeout","7"]},"script":["curl -f http://my-service"]}`,
+		Yaml: "timeout: 5m\nretry:\n  max: 3\n  backoff: 10s\n  \"on\":\n  - timeout\n  - \"7\"\nscript:\n- curl -f http://my-service\n",
+	},
 }
 
 func TestMarshal(t *testing.T) {
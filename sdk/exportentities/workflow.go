@@ -33,6 +33,7 @@ type Workflow struct {
 	// this will be filled for simple workflows
 	DependsOn              []string                    `json:"depends_on,omitempty" yaml:"depends_on,omitempty" jsonschema_description:"Names of the parent nodes, can be pipelines, forks or joins."`
 	OneAtATime             *bool                       `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty" jsonschema_description:"Set to true if you want to limit the execution of this node to one at a time."`
+	Concurrency            *sdk.NodeConcurrency        `json:"concurrency,omitempty" yaml:"concurrency,omitempty" jsonschema_description:"Named concurrency group shared with other nodes and workflows of the project.\nhttps://ovh.github.io/cds/docs/concepts/workflow/concurrency"`
 	Conditions             *sdk.WorkflowNodeConditions `json:"conditions,omitempty" yaml:"conditions,omitempty" jsonschema_description:"Conditions to run this node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-conditions."`
 	When                   []string                    `json:"when,omitempty" yaml:"when,omitempty" jsonschema_description:"Set manual and status condition (ex: 'success')."` //This is used only for manual and success condition
 	PipelineName           string                      `json:"pipeline,omitempty" yaml:"pipeline,omitempty" jsonschema_description:"The name of a pipeline used for pipeline node."`
@@ -48,6 +49,7 @@ type Workflow struct {
 	PurgeTags        []string                       `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
 	Notifications    []NotificationEntry            `json:"notify,omitempty" yaml:"notify,omitempty"` // This is used when the workflow have only one pipeline
 	HistoryLength    *int64                         `json:"history_length,omitempty" yaml:"history_length,omitempty"`
+	RetentionPolicy  *sdk.WorkflowRetentionPolicy   `json:"retention_policy,omitempty" yaml:"retention_policy,omitempty" jsonschema_description:"Rules to keep workflow runs, other runs are purged. If not set, history_length is used."`
 	MapNotifications map[string][]NotificationEntry `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have more than one pipeline
 }
 
@@ -76,6 +78,7 @@ type NodeEntry struct {
 	EnvironmentName        string                      `json:"environment,omitempty" yaml:"environment,omitempty" jsonschema_description:"The environment to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
 	ProjectIntegrationName string                      `json:"integration,omitempty" yaml:"integration,omitempty" jsonschema_description:"The integration to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
 	OneAtATime             *bool                       `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty" jsonschema_description:"Set to true if you want to limit the execution of this node to one at a time."`
+	Concurrency            *sdk.NodeConcurrency        `json:"concurrency,omitempty" yaml:"concurrency,omitempty" jsonschema_description:"Named concurrency group shared with other nodes and workflows of the project.\nhttps://ovh.github.io/cds/docs/concepts/workflow/concurrency"`
 	Payload                map[string]interface{}      `json:"payload,omitempty" yaml:"payload,omitempty"`
 	Parameters             map[string]string           `json:"parameters,omitempty" yaml:"parameters,omitempty" jsonschema_description:"List of parameters for the workflow."`
 	OutgoingHookModelName  string                      `json:"trigger,omitempty" yaml:"trigger,omitempty"`
@@ -176,6 +179,10 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 			entry.OneAtATime = &n.Context.Mutex
 		}
 
+		if n.Context.Concurrency != nil {
+			entry.Concurrency = n.Context.Concurrency
+		}
+
 		if n.Context.HasDefaultPayload() {
 			enc := dump.NewDefaultEncoder()
 			enc.ExtraFields.DetailedMap = false
@@ -306,6 +313,7 @@ func NewWorkflow(ctx context.Context, w sdk.Workflow, opts ...WorkflowOptions) (
 	}
 
 	exportedWorkflow.PurgeTags = w.PurgeTags
+	exportedWorkflow.RetentionPolicy = w.RetentionPolicy
 
 	nodes := w.WorkflowData.Array()
 
@@ -321,6 +329,7 @@ func NewWorkflow(ctx context.Context, w sdk.Workflow, opts ...WorkflowOptions) (
 		exportedWorkflow.ProjectIntegrationName = entry.ProjectIntegrationName
 		exportedWorkflow.DependsOn = entry.DependsOn
 		exportedWorkflow.OneAtATime = entry.OneAtATime
+		exportedWorkflow.Concurrency = entry.Concurrency
 		if entry.Conditions != nil && (len(entry.Conditions.PlainConditions) > 0 || entry.Conditions.LuaScript != "") {
 			exportedWorkflow.When = entry.When
 			exportedWorkflow.Conditions = entry.Conditions
@@ -423,6 +432,7 @@ func (w Workflow) Entries() map[string]NodeEntry {
 		Payload:                w.Payload,
 		Parameters:             w.Parameters,
 		OneAtATime:             w.OneAtATime,
+		Concurrency:            w.Concurrency,
 	}
 	return map[string]NodeEntry{
 		w.PipelineName: singleEntry,
@@ -454,6 +464,9 @@ func (w Workflow) CheckValidity() error {
 		if len(w.DependsOn) != 0 {
 			mError.Append(fmt.Errorf("Error: wrong usage: depends_on not allowed here"))
 		}
+		if w.Concurrency != nil {
+			mError.Append(fmt.Errorf("Error: wrong usage: concurrency not allowed here"))
+		}
 		if len(w.PipelineHooks) != 0 {
 			mError.Append(fmt.Errorf("Error: wrong usage: pipeline_hooks not allowed here"))
 		}
@@ -528,6 +541,7 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 		return nil, sdk.WrapError(err, "Unable to check dependencies")
 	}
 	wf.PurgeTags = w.PurgeTags
+	wf.RetentionPolicy = w.RetentionPolicy
 	if len(w.Metadata) > 0 {
 		wf.Metadata = make(map[string]string, len(w.Metadata))
 		for k, v := range w.Metadata {
@@ -671,6 +685,13 @@ func (e *NodeEntry) getNode(name string, w *sdk.Workflow) (*sdk.Node, error) {
 		node.Context.Mutex = *e.OneAtATime
 	}
 
+	if e.Concurrency != nil {
+		if err := e.Concurrency.IsValid(); err != nil {
+			return nil, err
+		}
+		node.Context.Concurrency = e.Concurrency
+	}
+
 	if e.OutgoingHookModelName != "" {
 		node.Type = sdk.NodeTypeOutGoingHook
 		config := sdk.WorkflowNodeHookConfig{}
@@ -68,7 +68,8 @@ func craftNotificationEntry(ctx context.Context, w sdk.Workflow, notif sdk.Workf
 			entry.Settings.Template.Body = ""
 		}
 		if entry.Settings.Template.Body == "" && entry.Settings.Template.Subject == "" {
-			if entry.Settings.Template.DisableComment == nil || !*entry.Settings.Template.DisableComment {
+			if (entry.Settings.Template.DisableComment == nil || !*entry.Settings.Template.DisableComment) &&
+				(entry.Settings.Template.SummaryComment == nil || !*entry.Settings.Template.SummaryComment) {
 				entry.Settings.Template = nil
 			}
 		}
@@ -185,6 +186,9 @@ func ProcessNotificationValues(notif NotificationEntry) (sdk.WorkflowNotificatio
 		if n.Settings.Template.DisableComment == nil || !*n.Settings.Template.DisableComment {
 			n.Settings.Template.DisableComment = nil
 		}
+		if n.Settings.Template.SummaryComment == nil || !*n.Settings.Template.SummaryComment {
+			n.Settings.Template.SummaryComment = nil
+		}
 	}
 	return n, nil
 }
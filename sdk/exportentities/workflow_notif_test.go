@@ -196,6 +196,17 @@ notify:
         Details : {{.cds.buildURL}}
         Triggered by : {{.cds.triggered_by.username}}
         Branch : {{.git.branch}}
+`,
+		}, {
+			name: "test one pipeline with vcs summary comment",
+			yaml: `name: test-notif-vcs
+version: v1.0
+pipeline: test
+notify:
+- type: vcs
+  settings:
+    template:
+      summary_comment: true
 `,
 		}, {
 			name: "two pipelines with one notif",
@@ -442,6 +442,18 @@ func TestWorkflow_GetWorkflow(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Complexe workflow with an invalid concurrency policy should raise an error",
+			fields: fields{
+				Workflow: map[string]exportentities.NodeEntry{
+					"root": {
+						PipelineName: "pipeline-root",
+						Concurrency:  &sdk.NodeConcurrency{Group: "deploy", Policy: "unknown"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		// root(pipeline-root) -> child(pipeline-child)
 		{
 			name: "Complexe workflow unordered without joins should not raise an error",
@@ -1149,6 +1161,37 @@ workflow:
     - aa_2
     when:
     - manual
+`,
+		},
+		{
+			name: "Workflow with typed conditions",
+			yaml: `name: typed-conditions
+version: v1.0
+workflow:
+  build:
+    pipeline: build
+  deploy:
+    depends_on:
+    - build
+    conditions:
+      check:
+      - variable: cds.run.number
+        operator: gt
+        value: "9"
+        type: number
+      - variable: git.branch
+        operator: in
+        value: master, develop
+      - operator: or
+        conditions:
+        - variable: git.tag
+          operator: ge
+          value: v1.10.0
+          type: semver
+        - variable: git.message
+          operator: contains
+          value: '[deploy]'
+    pipeline: deploy
 `,
 		},
 		{
@@ -1174,6 +1217,35 @@ workflow:
     - success
     pipeline: env
     one_at_a_time: true
+`,
+		},
+		{
+			name: "Workflow with concurrency group on root",
+			yaml: `name: myconcurrency
+version: v1.0
+concurrency:
+  group: deploy
+pipeline: deploy
+`,
+		},
+		{
+			name: "Workflow with concurrency group on pipeline child",
+			yaml: `name: myconcurrency
+version: v1.0
+workflow:
+  build:
+    pipeline: build
+  deploy:
+    depends_on:
+    - build
+    when:
+    - success
+    pipeline: deploy
+    environment: production
+    concurrency:
+      group: deploy
+      scope: environment
+      policy: cancel-pending
 `,
 		},
 	}
@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Feature represents a feature flag stored in CDS.
+// A disabled feature is off for everyone, an enabled feature without rules is on for everyone,
+// else it is on only when one of its rules matches.
+type Feature struct {
+	ID       int64        `json:"id" db:"id" cli:"-"`
+	Name     string       `json:"name" db:"name" cli:"name,key"`
+	Enabled  bool         `json:"enabled" db:"enabled" cli:"enabled"`
+	Rules    FeatureRules `json:"rules" db:"rules" cli:"-"`
+	Created  time.Time    `json:"created" db:"created" cli:"-"`
+	Modified time.Time    `json:"modified" db:"modified" cli:"modified"`
+	// aggregate for cli display
+	RulesSummary string `json:"-" db:"-" cli:"rules"`
+}
+
+// FeatureRules contains the conditions to enable a feature.
+type FeatureRules struct {
+	ProjectKeys []string `json:"project_keys,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+	Percentage  int      `json:"percentage,omitempty"`
+}
+
+// FeatureContext contains the values used to evaluate the rules of a feature.
+type FeatureContext struct {
+	ProjectKey string   `json:"project_key"`
+	Groups     []string `json:"groups,omitempty"`
+}
+
+// IsValid returns an error if the feature is invalid.
+func (f Feature) IsValid() error {
+	if f.Name == "" {
+		return NewErrorFrom(ErrWrongRequest, "invalid given feature name")
+	}
+	return f.Rules.IsValid()
+}
+
+// IsEnabled returns true if the feature is enabled for given context.
+func (f Feature) IsEnabled(c FeatureContext) bool {
+	if !f.Enabled {
+		return false
+	}
+	if f.Rules.IsEmpty() {
+		return true
+	}
+	if c.ProjectKey != "" && IsInArray(c.ProjectKey, f.Rules.ProjectKeys) {
+		return true
+	}
+	for _, g := range c.Groups {
+		if IsInArray(g, f.Rules.Groups) {
+			return true
+		}
+	}
+	return f.Rules.Percentage > 0 && FeatureRolloutBucket(f.Name, c.ProjectKey) < f.Rules.Percentage
+}
+
+// FeatureRolloutBucket returns a stable value between 0 and 99 for a feature and a project,
+// a project is in the rollout of a feature if its bucket is lower than the rollout percentage.
+func FeatureRolloutBucket(featureName, projectKey string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(featureName + ":" + projectKey))
+	return int(h.Sum32() % 100)
+}
+
+// IsEmpty returns true if there is no rule.
+func (r FeatureRules) IsEmpty() bool {
+	return len(r.ProjectKeys) == 0 && len(r.Groups) == 0 && r.Percentage == 0
+}
+
+// IsValid returns an error if rules are invalid.
+func (r FeatureRules) IsValid() error {
+	if r.Percentage < 0 || r.Percentage > 100 {
+		return NewErrorFrom(ErrWrongRequest, "invalid given rollout percentage %d, should be between 0 and 100", r.Percentage)
+	}
+	return nil
+}
+
+// String returns a human readable summary of rules.
+func (r FeatureRules) String() string {
+	if r.IsEmpty() {
+		return "all"
+	}
+	var s string
+	if len(r.ProjectKeys) > 0 {
+		s += fmt.Sprintf("projects:%v ", r.ProjectKeys)
+	}
+	if len(r.Groups) > 0 {
+		s += fmt.Sprintf("groups:%v ", r.Groups)
+	}
+	if r.Percentage > 0 {
+		s += fmt.Sprintf("rollout:%d%% ", r.Percentage)
+	}
+	return s[:len(s)-1]
+}
+
+// Scan feature rules.
+func (r *FeatureRules) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, r), "cannot unmarshal FeatureRules")
+}
+
+// Value returns driver.Value from feature rules.
+func (r FeatureRules) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	return j, WrapError(err, "cannot marshal FeatureRules")
+}
@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureIsEnabled(t *testing.T) {
+	assert.True(t, Feature{Name: "feat", Enabled: true}.IsEnabled(FeatureContext{ProjectKey: "PROJ"}))
+	assert.False(t, Feature{Name: "feat"}.IsEnabled(FeatureContext{ProjectKey: "PROJ"}))
+
+	f := Feature{
+		Name:    "feat",
+		Enabled: true,
+		Rules: FeatureRules{
+			ProjectKeys: []string{"PROJ1"},
+			Groups:      []string{"grp1"},
+		},
+	}
+	assert.True(t, f.IsEnabled(FeatureContext{ProjectKey: "PROJ1"}))
+	assert.True(t, f.IsEnabled(FeatureContext{ProjectKey: "PROJ2", Groups: []string{"grp2", "grp1"}}))
+	assert.False(t, f.IsEnabled(FeatureContext{ProjectKey: "PROJ2", Groups: []string{"grp2"}}))
+
+	f.Enabled = false
+	assert.False(t, f.IsEnabled(FeatureContext{ProjectKey: "PROJ1"}))
+}
+
+func TestFeatureIsEnabledPercentage(t *testing.T) {
+	f := Feature{Name: "feat", Enabled: true, Rules: FeatureRules{Percentage: 30}}
+
+	var enabled int
+	for i := 0; i < 1000; i++ {
+		c := FeatureContext{ProjectKey: RandomString(10)}
+		if f.IsEnabled(c) {
+			enabled++
+		}
+		// The result should be stable for a project
+		assert.Equal(t, f.IsEnabled(c), f.IsEnabled(c))
+	}
+	assert.InDelta(t, 300, enabled, 60)
+
+	f.Rules.Percentage = 100
+	assert.True(t, f.IsEnabled(FeatureContext{ProjectKey: "PROJ"}))
+	f.Rules.Percentage = 0
+	assert.True(t, f.IsEnabled(FeatureContext{ProjectKey: "PROJ"}), "no rule means enabled for everyone")
+}
+
+func TestFeatureIsValid(t *testing.T) {
+	assert.NoError(t, Feature{Name: "feat", Rules: FeatureRules{Percentage: 100}}.IsValid())
+	assert.Error(t, Feature{}.IsValid())
+	assert.Error(t, Feature{Name: "feat", Rules: FeatureRules{Percentage: 101}}.IsValid())
+}
@@ -11,6 +11,15 @@ import (
 	"github.com/ovh/cds/sdk/grpcplugin"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Levels of the log events sent on the RunStream stream
+const (
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
 )
 
 // Common is the common struct of actionplugin
@@ -67,3 +76,67 @@ func Fail(format string, args ...interface{}) (*ActionResult, error) {
 		Status:  "Fail",
 	}, nil
 }
+
+// RunStream is implemented by plugins which stream their logs and outputs, without it the worker calls Run
+func (c *Common) RunStream(q *ActionQuery, stream ActionPlugin_RunStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunStream not implemented")
+}
+
+// Stream helps to send events on the RunStream stream
+type Stream struct {
+	ActionPlugin_RunStreamServer
+}
+
+// NewStream returns a Stream sending events on the given stream
+func NewStream(s ActionPlugin_RunStreamServer) *Stream {
+	return &Stream{s}
+}
+
+func (s *Stream) log(level, format string, args ...interface{}) error {
+	return s.Send(&ActionEvent{Event: &ActionEvent_Log{Log: &ActionLog{Level: level, Message: fmt.Sprintf(format, args...)}}})
+}
+
+// Info sends an info log line
+func (s *Stream) Info(format string, args ...interface{}) error {
+	return s.log(LogLevelInfo, format, args...)
+}
+
+// Warn sends a warning log line
+func (s *Stream) Warn(format string, args ...interface{}) error {
+	return s.log(LogLevelWarn, format, args...)
+}
+
+// Error sends an error log line, it does not fail the step
+func (s *Stream) Error(format string, args ...interface{}) error {
+	return s.log(LogLevelError, format, args...)
+}
+
+// Progress sends the progress of the action in percent
+func (s *Stream) Progress(percent int32, format string, args ...interface{}) error {
+	return s.Send(&ActionEvent{Event: &ActionEvent_Progress{Progress: &ActionProgress{Percent: percent, Message: fmt.Sprintf(format, args...)}}})
+}
+
+// Export exports the variable cds.build.<name>, available in the next steps and jobs
+func (s *Stream) Export(name, value string) error {
+	return s.Send(&ActionEvent{Event: &ActionEvent_Variable{Variable: &ActionVariable{Name: name, Value: value}}})
+}
+
+// UploadArtifact asks the worker to upload the files matching path, destination is an optional storage integration
+func (s *Stream) UploadArtifact(path, tag, destination string) error {
+	return s.Send(&ActionEvent{Event: &ActionEvent_Artifact{Artifact: &ActionArtifact{Path: path, Tag: tag, Destination: destination}}})
+}
+
+// TestReport asks the worker to parse and send the junit reports matching path
+func (s *Stream) TestReport(path string) error {
+	return s.Send(&ActionEvent{Event: &ActionEvent_Tests{Tests: &ActionTestReport{Path: path}}})
+}
+
+// Result sends the result of the action, it must be the last event
+func (s *Stream) Result(status, details string) error {
+	return s.Send(&ActionEvent{Event: &ActionEvent_Result{Result: &ActionResult{Status: status, Details: details}}})
+}
+
+// Fail sends a failed result
+func (s *Stream) Fail(format string, args ...interface{}) error {
+	return s.Result("Fail", fmt.Sprintf(format, args...))
+}
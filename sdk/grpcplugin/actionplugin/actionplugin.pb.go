@@ -4,12 +4,15 @@
 package actionplugin
 
 import (
+	context "context"
 	fmt "fmt"
+	math "math"
+
 	proto "github.com/golang/protobuf/proto"
 	empty "github.com/golang/protobuf/ptypes/empty"
-	context "golang.org/x/net/context"
 	grpc "google.golang.org/grpc"
-	math "math"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -21,7 +24,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type ActionPluginManifest struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -219,45 +222,441 @@ func (m *WorkerHTTPPortQuery) GetPort() int32 {
 	return 0
 }
 
+type ActionEvent struct {
+	// Types that are valid to be assigned to Event:
+	//	*ActionEvent_Log
+	//	*ActionEvent_Progress
+	//	*ActionEvent_Variable
+	//	*ActionEvent_Artifact
+	//	*ActionEvent_Tests
+	//	*ActionEvent_Result
+	Event                isActionEvent_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ActionEvent) Reset()         { *m = ActionEvent{} }
+func (m *ActionEvent) String() string { return proto.CompactTextString(m) }
+func (*ActionEvent) ProtoMessage()    {}
+func (*ActionEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8761e3c72e0ffc53, []int{4}
+}
+
+func (m *ActionEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionEvent.Unmarshal(m, b)
+}
+func (m *ActionEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionEvent.Marshal(b, m, deterministic)
+}
+func (m *ActionEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionEvent.Merge(m, src)
+}
+func (m *ActionEvent) XXX_Size() int {
+	return xxx_messageInfo_ActionEvent.Size(m)
+}
+func (m *ActionEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionEvent proto.InternalMessageInfo
+
+type isActionEvent_Event interface {
+	isActionEvent_Event()
+}
+
+type ActionEvent_Log struct {
+	Log *ActionLog `protobuf:"bytes,1,opt,name=log,proto3,oneof"`
+}
+
+type ActionEvent_Progress struct {
+	Progress *ActionProgress `protobuf:"bytes,2,opt,name=progress,proto3,oneof"`
+}
+
+type ActionEvent_Variable struct {
+	Variable *ActionVariable `protobuf:"bytes,3,opt,name=variable,proto3,oneof"`
+}
+
+type ActionEvent_Artifact struct {
+	Artifact *ActionArtifact `protobuf:"bytes,4,opt,name=artifact,proto3,oneof"`
+}
+
+type ActionEvent_Tests struct {
+	Tests *ActionTestReport `protobuf:"bytes,5,opt,name=tests,proto3,oneof"`
+}
+
+type ActionEvent_Result struct {
+	Result *ActionResult `protobuf:"bytes,6,opt,name=result,proto3,oneof"`
+}
+
+func (*ActionEvent_Log) isActionEvent_Event() {}
+
+func (*ActionEvent_Progress) isActionEvent_Event() {}
+
+func (*ActionEvent_Variable) isActionEvent_Event() {}
+
+func (*ActionEvent_Artifact) isActionEvent_Event() {}
+
+func (*ActionEvent_Tests) isActionEvent_Event() {}
+
+func (*ActionEvent_Result) isActionEvent_Event() {}
+
+func (m *ActionEvent) GetEvent() isActionEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetLog() *ActionLog {
+	if x, ok := m.GetEvent().(*ActionEvent_Log); ok {
+		return x.Log
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetProgress() *ActionProgress {
+	if x, ok := m.GetEvent().(*ActionEvent_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetVariable() *ActionVariable {
+	if x, ok := m.GetEvent().(*ActionEvent_Variable); ok {
+		return x.Variable
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetArtifact() *ActionArtifact {
+	if x, ok := m.GetEvent().(*ActionEvent_Artifact); ok {
+		return x.Artifact
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetTests() *ActionTestReport {
+	if x, ok := m.GetEvent().(*ActionEvent_Tests); ok {
+		return x.Tests
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetResult() *ActionResult {
+	if x, ok := m.GetEvent().(*ActionEvent_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ActionEvent) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ActionEvent_Log)(nil),
+		(*ActionEvent_Progress)(nil),
+		(*ActionEvent_Variable)(nil),
+		(*ActionEvent_Artifact)(nil),
+		(*ActionEvent_Tests)(nil),
+		(*ActionEvent_Result)(nil),
+	}
+}
+
+type ActionLog struct {
+	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActionLog) Reset()         { *m = ActionLog{} }
+func (m *ActionLog) String() string { return proto.CompactTextString(m) }
+func (*ActionLog) ProtoMessage()    {}
+func (*ActionLog) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8761e3c72e0ffc53, []int{5}
+}
+
+func (m *ActionLog) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionLog.Unmarshal(m, b)
+}
+func (m *ActionLog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionLog.Marshal(b, m, deterministic)
+}
+func (m *ActionLog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionLog.Merge(m, src)
+}
+func (m *ActionLog) XXX_Size() int {
+	return xxx_messageInfo_ActionLog.Size(m)
+}
+func (m *ActionLog) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionLog.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionLog proto.InternalMessageInfo
+
+func (m *ActionLog) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *ActionLog) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type ActionProgress struct {
+	Percent              int32    `protobuf:"varint,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActionProgress) Reset()         { *m = ActionProgress{} }
+func (m *ActionProgress) String() string { return proto.CompactTextString(m) }
+func (*ActionProgress) ProtoMessage()    {}
+func (*ActionProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8761e3c72e0ffc53, []int{6}
+}
+
+func (m *ActionProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionProgress.Unmarshal(m, b)
+}
+func (m *ActionProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionProgress.Marshal(b, m, deterministic)
+}
+func (m *ActionProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionProgress.Merge(m, src)
+}
+func (m *ActionProgress) XXX_Size() int {
+	return xxx_messageInfo_ActionProgress.Size(m)
+}
+func (m *ActionProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionProgress proto.InternalMessageInfo
+
+func (m *ActionProgress) GetPercent() int32 {
+	if m != nil {
+		return m.Percent
+	}
+	return 0
+}
+
+func (m *ActionProgress) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type ActionVariable struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActionVariable) Reset()         { *m = ActionVariable{} }
+func (m *ActionVariable) String() string { return proto.CompactTextString(m) }
+func (*ActionVariable) ProtoMessage()    {}
+func (*ActionVariable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8761e3c72e0ffc53, []int{7}
+}
+
+func (m *ActionVariable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionVariable.Unmarshal(m, b)
+}
+func (m *ActionVariable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionVariable.Marshal(b, m, deterministic)
+}
+func (m *ActionVariable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionVariable.Merge(m, src)
+}
+func (m *ActionVariable) XXX_Size() int {
+	return xxx_messageInfo_ActionVariable.Size(m)
+}
+func (m *ActionVariable) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionVariable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionVariable proto.InternalMessageInfo
+
+func (m *ActionVariable) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ActionVariable) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type ActionArtifact struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Tag                  string   `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	Destination          string   `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActionArtifact) Reset()         { *m = ActionArtifact{} }
+func (m *ActionArtifact) String() string { return proto.CompactTextString(m) }
+func (*ActionArtifact) ProtoMessage()    {}
+func (*ActionArtifact) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8761e3c72e0ffc53, []int{8}
+}
+
+func (m *ActionArtifact) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionArtifact.Unmarshal(m, b)
+}
+func (m *ActionArtifact) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionArtifact.Marshal(b, m, deterministic)
+}
+func (m *ActionArtifact) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionArtifact.Merge(m, src)
+}
+func (m *ActionArtifact) XXX_Size() int {
+	return xxx_messageInfo_ActionArtifact.Size(m)
+}
+func (m *ActionArtifact) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionArtifact.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionArtifact proto.InternalMessageInfo
+
+func (m *ActionArtifact) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ActionArtifact) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *ActionArtifact) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+type ActionTestReport struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActionTestReport) Reset()         { *m = ActionTestReport{} }
+func (m *ActionTestReport) String() string { return proto.CompactTextString(m) }
+func (*ActionTestReport) ProtoMessage()    {}
+func (*ActionTestReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8761e3c72e0ffc53, []int{9}
+}
+
+func (m *ActionTestReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionTestReport.Unmarshal(m, b)
+}
+func (m *ActionTestReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionTestReport.Marshal(b, m, deterministic)
+}
+func (m *ActionTestReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionTestReport.Merge(m, src)
+}
+func (m *ActionTestReport) XXX_Size() int {
+	return xxx_messageInfo_ActionTestReport.Size(m)
+}
+func (m *ActionTestReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionTestReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionTestReport proto.InternalMessageInfo
+
+func (m *ActionTestReport) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ActionPluginManifest)(nil), "actionplugin.ActionPluginManifest")
 	proto.RegisterType((*ActionQuery)(nil), "actionplugin.ActionQuery")
 	proto.RegisterMapType((map[string]string)(nil), "actionplugin.ActionQuery.OptionsEntry")
 	proto.RegisterType((*ActionResult)(nil), "actionplugin.ActionResult")
 	proto.RegisterType((*WorkerHTTPPortQuery)(nil), "actionplugin.WorkerHTTPPortQuery")
+	proto.RegisterType((*ActionEvent)(nil), "actionplugin.ActionEvent")
+	proto.RegisterType((*ActionLog)(nil), "actionplugin.ActionLog")
+	proto.RegisterType((*ActionProgress)(nil), "actionplugin.ActionProgress")
+	proto.RegisterType((*ActionVariable)(nil), "actionplugin.ActionVariable")
+	proto.RegisterType((*ActionArtifact)(nil), "actionplugin.ActionArtifact")
+	proto.RegisterType((*ActionTestReport)(nil), "actionplugin.ActionTestReport")
 }
 
 func init() { proto.RegisterFile("actionplugin.proto", fileDescriptor_8761e3c72e0ffc53) }
 
 var fileDescriptor_8761e3c72e0ffc53 = []byte{
-	// 425 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x8d, 0x63, 0xb7, 0x85, 0x49, 0x84, 0x60, 0xa8, 0x2a, 0x63, 0x2e, 0x61, 0x0f, 0x50, 0x2e,
-	0x5b, 0xa9, 0x5c, 0xaa, 0x1e, 0x50, 0xa9, 0xa8, 0x54, 0x24, 0x2a, 0x8c, 0xa9, 0x84, 0xc4, 0xcd,
-	0xb1, 0x37, 0x8e, 0x89, 0xe3, 0xb5, 0xf6, 0x23, 0x92, 0x2f, 0xfc, 0x97, 0xfc, 0x53, 0xe4, 0x5d,
-	0xbb, 0xb2, 0xa5, 0xf8, 0x36, 0x6f, 0xe6, 0xcd, 0xec, 0xbc, 0x79, 0x5a, 0xc0, 0x38, 0x51, 0x39,
-	0x2f, 0xab, 0x42, 0x67, 0x79, 0x49, 0x2b, 0xc1, 0x15, 0xc7, 0x79, 0x3f, 0x17, 0xbc, 0xcd, 0x38,
-	0xcf, 0x0a, 0x76, 0x61, 0x6a, 0x4b, 0xbd, 0xba, 0x60, 0xdb, 0x4a, 0xd5, 0x96, 0x4a, 0xfe, 0xc1,
-	0xe9, 0x17, 0x43, 0x0e, 0x0d, 0xf9, 0x21, 0x2e, 0xf3, 0x15, 0x93, 0x0a, 0x11, 0xbc, 0x32, 0xde,
-	0x32, 0xdf, 0x59, 0x38, 0xe7, 0xcf, 0x23, 0x13, 0xa3, 0x0f, 0x27, 0x3b, 0x26, 0x64, 0xce, 0x4b,
-	0x7f, 0x6a, 0xd2, 0x1d, 0xc4, 0x05, 0xcc, 0x52, 0x26, 0x13, 0x91, 0x57, 0xcd, 0x28, 0xdf, 0x35,
-	0xd5, 0x7e, 0x0a, 0xcf, 0xe0, 0x38, 0xd6, 0x6a, 0xcd, 0x85, 0xef, 0x99, 0x62, 0x8b, 0xc8, 0xde,
-	0x81, 0x99, 0x5d, 0xe0, 0xa7, 0x66, 0xa2, 0xc6, 0x1b, 0x38, 0xe1, 0xa6, 0x43, 0xfa, 0xce, 0xc2,
-	0x3d, 0x9f, 0x5d, 0xbe, 0xa7, 0x03, 0x81, 0x3d, 0x2e, 0xfd, 0x61, 0x89, 0x77, 0xa5, 0x12, 0x75,
-	0xd4, 0xb5, 0xe1, 0x29, 0x1c, 0xfd, 0xe5, 0xcb, 0x6f, 0x5f, 0xcd, 0x8e, 0x6e, 0x64, 0x41, 0x70,
-	0x0d, 0xf3, 0x3e, 0x1d, 0x5f, 0x82, 0xbb, 0x61, 0x75, 0x2b, 0xaf, 0x09, 0x9b, 0xbe, 0x5d, 0x5c,
-	0x68, 0xd6, 0x6a, 0xb3, 0xe0, 0x7a, 0x7a, 0xe5, 0x90, 0x1b, 0x98, 0xdb, 0x67, 0x23, 0x26, 0x75,
-	0xa1, 0x1a, 0x2d, 0x52, 0xc5, 0x4a, 0xcb, 0xb6, 0xbd, 0x45, 0xcd, 0x7d, 0x52, 0xa6, 0xe2, 0xbc,
-	0x90, 0xdd, 0x7d, 0x5a, 0x48, 0x3e, 0xc2, 0xeb, 0xdf, 0x5c, 0x6c, 0x98, 0xb8, 0x7f, 0x7c, 0x0c,
-	0x43, 0x2e, 0x94, 0x15, 0x8b, 0xe0, 0x55, 0x5c, 0x28, 0x33, 0xe6, 0x28, 0x32, 0xf1, 0xe5, 0x7e,
-	0xda, 0xbd, 0x66, 0x1d, 0xc1, 0x7b, 0x78, 0xf6, 0xe4, 0xca, 0x19, 0xb5, 0x5e, 0xd2, 0xce, 0x4b,
-	0x7a, 0xd7, 0x78, 0x19, 0x90, 0x43, 0x47, 0x1a, 0x3a, 0x4a, 0x26, 0xf8, 0x19, 0xdc, 0x48, 0x97,
-	0xf8, 0x66, 0xf4, 0xa2, 0x41, 0x70, 0xa8, 0x64, 0x55, 0x93, 0x09, 0x3e, 0xc0, 0x8b, 0xa1, 0x0a,
-	0x7c, 0x37, 0xe4, 0x1f, 0xd0, 0x18, 0x8c, 0xac, 0x4c, 0x26, 0x78, 0x05, 0xde, 0x2f, 0xc5, 0xab,
-	0x51, 0x51, 0xa3, 0x9d, 0xb7, 0xdf, 0xe1, 0x43, 0xc2, 0xb7, 0x94, 0xef, 0xd6, 0x34, 0x49, 0x25,
-	0x95, 0xe9, 0x86, 0x66, 0xa2, 0x4a, 0xda, 0x2d, 0xfa, 0x2b, 0xdd, 0xbe, 0xea, 0xdf, 0x22, 0x6c,
-	0x06, 0x85, 0xce, 0x9f, 0xc1, 0xff, 0x58, 0x1e, 0x9b, 0xf9, 0x9f, 0xfe, 0x07, 0x00, 0x00, 0xff,
-	0xff, 0x00, 0x7f, 0x06, 0xee, 0x4a, 0x03, 0x00, 0x00,
+	// 667 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xdd, 0x6a, 0xdb, 0x30,
+	0x14, 0x76, 0xe2, 0x24, 0x6d, 0x4f, 0x42, 0xe8, 0xb4, 0xd2, 0x79, 0xd9, 0x18, 0x9d, 0x2e, 0xba,
+	0x8e, 0x81, 0x3b, 0xb2, 0x31, 0x4a, 0x06, 0xa3, 0x2d, 0x0d, 0x64, 0xd0, 0xb2, 0xcc, 0x2d, 0xdb,
+	0xd8, 0x9d, 0xe2, 0xa8, 0xae, 0x57, 0xc7, 0x32, 0x92, 0x1c, 0xc8, 0xcd, 0xde, 0x65, 0x0f, 0x37,
+	0xd8, 0x63, 0x0c, 0xc9, 0x52, 0xb1, 0x8b, 0xd3, 0x3b, 0x1d, 0xe9, 0xfb, 0x8e, 0xcf, 0xf9, 0xce,
+	0x8f, 0x01, 0x91, 0x50, 0xc6, 0x2c, 0xcd, 0x92, 0x3c, 0x8a, 0x53, 0x3f, 0xe3, 0x4c, 0x32, 0xd4,
+	0x2b, 0xdf, 0x0d, 0x9e, 0x45, 0x8c, 0x45, 0x09, 0x3d, 0xd4, 0x6f, 0xb3, 0xfc, 0xfa, 0x90, 0x2e,
+	0x32, 0xb9, 0x2a, 0xa0, 0xf8, 0x37, 0xec, 0x9c, 0x68, 0xf0, 0x54, 0x83, 0x2f, 0x48, 0x1a, 0x5f,
+	0x53, 0x21, 0x11, 0x82, 0x56, 0x4a, 0x16, 0xd4, 0x6b, 0xec, 0x35, 0x0e, 0xb6, 0x02, 0x7d, 0x46,
+	0x1e, 0x6c, 0x2c, 0x29, 0x17, 0x31, 0x4b, 0xbd, 0xa6, 0xbe, 0xb6, 0x26, 0xda, 0x83, 0xee, 0x9c,
+	0x8a, 0x90, 0xc7, 0x99, 0x72, 0xe5, 0xb9, 0xfa, 0xb5, 0x7c, 0x85, 0x76, 0xa1, 0x43, 0x72, 0x79,
+	0xc3, 0xb8, 0xd7, 0xd2, 0x8f, 0xc6, 0xc2, 0x7f, 0x1a, 0xd0, 0x2d, 0x02, 0xf8, 0x9a, 0x53, 0xbe,
+	0x42, 0xc7, 0xb0, 0xc1, 0x34, 0x43, 0x78, 0x8d, 0x3d, 0xf7, 0xa0, 0x3b, 0xdc, 0xf7, 0x2b, 0x09,
+	0x96, 0xb0, 0xfe, 0x97, 0x02, 0x38, 0x4e, 0x25, 0x5f, 0x05, 0x96, 0x86, 0x76, 0xa0, 0xfd, 0x8b,
+	0xcd, 0x3e, 0x9f, 0xe9, 0x18, 0xdd, 0xa0, 0x30, 0x06, 0x23, 0xe8, 0x95, 0xe1, 0x68, 0x1b, 0xdc,
+	0x5b, 0xba, 0x32, 0xe9, 0xa9, 0xa3, 0xe2, 0x2d, 0x49, 0x92, 0x53, 0x93, 0x5b, 0x61, 0x8c, 0x9a,
+	0x47, 0x0d, 0x7c, 0x0c, 0xbd, 0xe2, 0xb3, 0x01, 0x15, 0x79, 0x22, 0x55, 0x2e, 0x42, 0x12, 0x99,
+	0x0b, 0x43, 0x37, 0x96, 0xd2, 0x67, 0x4e, 0x25, 0x89, 0x13, 0x61, 0xf5, 0x31, 0x26, 0x7e, 0x0d,
+	0x8f, 0xbf, 0x33, 0x7e, 0x4b, 0xf9, 0xe4, 0xea, 0x6a, 0x3a, 0x65, 0x5c, 0x16, 0xc9, 0x22, 0x68,
+	0x65, 0x8c, 0x4b, 0xed, 0xa6, 0x1d, 0xe8, 0x33, 0xfe, 0xdb, 0xb4, 0x82, 0x8c, 0x97, 0x34, 0x95,
+	0xe8, 0x0d, 0xb8, 0x09, 0x8b, 0x34, 0xa4, 0x3b, 0x7c, 0x52, 0x27, 0xc6, 0x39, 0x8b, 0x26, 0x4e,
+	0xa0, 0x50, 0x68, 0x04, 0x9b, 0x19, 0x67, 0x11, 0xa7, 0xa2, 0x08, 0xa1, 0x3b, 0x7c, 0x5e, 0xc7,
+	0x98, 0x1a, 0xcc, 0xc4, 0x09, 0xee, 0xf0, 0x8a, 0xbb, 0x24, 0x3c, 0x26, 0xb3, 0x84, 0x7a, 0xee,
+	0x7a, 0xee, 0x37, 0x83, 0x51, 0x5c, 0x8b, 0x57, 0x5c, 0xc2, 0x65, 0x7c, 0x4d, 0x42, 0xe9, 0xb5,
+	0xd6, 0x73, 0x4f, 0x0c, 0x46, 0x71, 0x2d, 0x1e, 0x7d, 0x80, 0xb6, 0xa4, 0x42, 0x0a, 0xaf, 0xad,
+	0x89, 0x2f, 0xea, 0x88, 0x57, 0x54, 0xc8, 0x80, 0x2a, 0x7d, 0x26, 0x4e, 0x50, 0xc0, 0xd1, 0x7b,
+	0xe8, 0x70, 0x5d, 0x0f, 0xaf, 0xa3, 0x89, 0x83, 0x3a, 0x62, 0x51, 0xb1, 0x89, 0x13, 0x18, 0xec,
+	0xe9, 0x06, 0xb4, 0xa9, 0xd2, 0x15, 0x7f, 0x84, 0xad, 0x3b, 0xf9, 0x54, 0xed, 0x13, 0xba, 0xa4,
+	0x89, 0x29, 0x68, 0x61, 0xa8, 0x7a, 0x2e, 0xa8, 0x10, 0x24, 0xb2, 0x3d, 0x61, 0x4d, 0x7c, 0x06,
+	0xfd, 0xaa, 0x92, 0x0a, 0x9b, 0x51, 0x1e, 0xd2, 0xd4, 0x56, 0xd3, 0x9a, 0x0f, 0x78, 0x19, 0x41,
+	0xbf, 0xaa, 0x69, 0xed, 0xd4, 0xd5, 0xf6, 0x25, 0xfe, 0x01, 0xfd, 0xaa, 0xa6, 0xba, 0x99, 0x88,
+	0xbc, 0xb1, 0x5c, 0x75, 0x56, 0x5d, 0x2e, 0x49, 0x64, 0x98, 0xea, 0x68, 0x26, 0x55, 0xc6, 0x29,
+	0xb9, 0x37, 0xa9, 0xf6, 0x0a, 0xef, 0xc3, 0xf6, 0x7d, 0xd1, 0xeb, 0x7c, 0x0f, 0xff, 0x35, 0xa1,
+	0x57, 0x5e, 0x1d, 0x68, 0x02, 0x9b, 0x77, 0xeb, 0x63, 0xd7, 0x2f, 0x96, 0x8e, 0x6f, 0x97, 0x8e,
+	0x3f, 0x56, 0x4b, 0x67, 0x80, 0x6b, 0xdb, 0xb1, 0xb2, 0x7a, 0xb0, 0x83, 0x3e, 0x81, 0x1b, 0xe4,
+	0x29, 0x7a, 0xba, 0x76, 0xf4, 0x07, 0x0f, 0x14, 0x1b, 0x3b, 0x68, 0x0c, 0x5b, 0x41, 0x9e, 0x5e,
+	0x4a, 0x4e, 0xc9, 0xe2, 0x21, 0x2f, 0xb5, 0x4f, 0x7a, 0xec, 0xb0, 0xf3, 0xb6, 0x81, 0x2e, 0xa0,
+	0x5f, 0x9d, 0x5a, 0xf4, 0xb2, 0x4a, 0xa8, 0x99, 0xe9, 0xc1, 0x9a, 0xcc, 0xb1, 0x83, 0x8e, 0xa0,
+	0x75, 0x29, 0x59, 0xb6, 0x56, 0x9b, 0xb5, 0xcc, 0xd3, 0x73, 0x78, 0x15, 0xb2, 0x85, 0xcf, 0x96,
+	0x37, 0x7e, 0x38, 0x17, 0xbe, 0x98, 0xdf, 0xfa, 0x11, 0xcf, 0x42, 0x13, 0x45, 0x39, 0xa4, 0xd3,
+	0x47, 0x65, 0x49, 0xa7, 0xca, 0xd1, 0xb4, 0xf1, 0xb3, 0xf2, 0x3f, 0x98, 0x75, 0xb4, 0xff, 0x77,
+	0xff, 0x07, 0x00, 0xa9, 0xa4, 0x38, 0x69, 0x3a, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -274,6 +673,7 @@ const _ = grpc.SupportPackageIsVersion4
 type ActionPluginClient interface {
 	Manifest(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ActionPluginManifest, error)
 	Run(ctx context.Context, in *ActionQuery, opts ...grpc.CallOption) (*ActionResult, error)
+	RunStream(ctx context.Context, in *ActionQuery, opts ...grpc.CallOption) (ActionPlugin_RunStreamClient, error)
 	WorkerHTTPPort(ctx context.Context, in *WorkerHTTPPortQuery, opts ...grpc.CallOption) (*empty.Empty, error)
 	Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
 }
@@ -304,6 +704,38 @@ func (c *actionPluginClient) Run(ctx context.Context, in *ActionQuery, opts ...g
 	return out, nil
 }
 
+func (c *actionPluginClient) RunStream(ctx context.Context, in *ActionQuery, opts ...grpc.CallOption) (ActionPlugin_RunStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ActionPlugin_serviceDesc.Streams[0], "/actionplugin.ActionPlugin/RunStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actionPluginRunStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActionPlugin_RunStreamClient interface {
+	Recv() (*ActionEvent, error)
+	grpc.ClientStream
+}
+
+type actionPluginRunStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *actionPluginRunStreamClient) Recv() (*ActionEvent, error) {
+	m := new(ActionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *actionPluginClient) WorkerHTTPPort(ctx context.Context, in *WorkerHTTPPortQuery, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/actionplugin.ActionPlugin/WorkerHTTPPort", in, out, opts...)
@@ -326,10 +758,31 @@ func (c *actionPluginClient) Stop(ctx context.Context, in *empty.Empty, opts ...
 type ActionPluginServer interface {
 	Manifest(context.Context, *empty.Empty) (*ActionPluginManifest, error)
 	Run(context.Context, *ActionQuery) (*ActionResult, error)
+	RunStream(*ActionQuery, ActionPlugin_RunStreamServer) error
 	WorkerHTTPPort(context.Context, *WorkerHTTPPortQuery) (*empty.Empty, error)
 	Stop(context.Context, *empty.Empty) (*empty.Empty, error)
 }
 
+// UnimplementedActionPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedActionPluginServer struct {
+}
+
+func (*UnimplementedActionPluginServer) Manifest(ctx context.Context, req *empty.Empty) (*ActionPluginManifest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Manifest not implemented")
+}
+func (*UnimplementedActionPluginServer) Run(ctx context.Context, req *ActionQuery) (*ActionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Run not implemented")
+}
+func (*UnimplementedActionPluginServer) RunStream(req *ActionQuery, srv ActionPlugin_RunStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunStream not implemented")
+}
+func (*UnimplementedActionPluginServer) WorkerHTTPPort(ctx context.Context, req *WorkerHTTPPortQuery) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorkerHTTPPort not implemented")
+}
+func (*UnimplementedActionPluginServer) Stop(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+
 func RegisterActionPluginServer(s *grpc.Server, srv ActionPluginServer) {
 	s.RegisterService(&_ActionPlugin_serviceDesc, srv)
 }
@@ -370,6 +823,27 @@ func _ActionPlugin_Run_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ActionPlugin_RunStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ActionQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActionPluginServer).RunStream(m, &actionPluginRunStreamServer{stream})
+}
+
+type ActionPlugin_RunStreamServer interface {
+	Send(*ActionEvent) error
+	grpc.ServerStream
+}
+
+type actionPluginRunStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *actionPluginRunStreamServer) Send(m *ActionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _ActionPlugin_WorkerHTTPPort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(WorkerHTTPPortQuery)
 	if err := dec(in); err != nil {
@@ -427,6 +901,12 @@ var _ActionPlugin_serviceDesc = grpc.ServiceDesc{
 			Handler:    _ActionPlugin_Stop_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunStream",
+			Handler:       _ActionPlugin_RunStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "actionplugin.proto",
 }
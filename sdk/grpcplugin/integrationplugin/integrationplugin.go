@@ -8,12 +8,29 @@ import (
 
 	"github.com/ovh/cds/sdk/grpcplugin"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Common struct {
 	grpcplugin.Common
 }
 
+// Rollback is not supported by default, plugins that can restore a previous release have to implement it
+func (c *Common) Rollback(ctx context.Context, q *RollbackQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "rollback is not supported by this plugin")
+}
+
+// Promote is not supported by default, plugins handling canary or blue-green deployments have to implement it
+func (c *Common) Promote(ctx context.Context, q *PromoteQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "progressive deployments are not supported by this plugin")
+}
+
+// Abort is not supported by default, plugins handling canary or blue-green deployments have to implement it
+func (c *Common) Abort(ctx context.Context, q *AbortQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "progressive deployments are not supported by this plugin")
+}
+
 func Start(ctx context.Context, srv IntegrationPluginServer) error {
 	p, ok := srv.(grpcplugin.Plugin)
 	if !ok {
@@ -4,13 +4,15 @@
 package integrationplugin
 
 import (
+	context "context"
 	fmt "fmt"
 	math "math"
 
 	proto "github.com/golang/protobuf/proto"
 	empty "github.com/golang/protobuf/ptypes/empty"
-	context "golang.org/x/net/context"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -22,7 +24,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type IntegrationPluginManifest struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -89,6 +91,8 @@ func (m *IntegrationPluginManifest) GetAuthor() string {
 
 type DeployQuery struct {
 	Options              map[string]string `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Strategy             string            `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Weight               int32             `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -126,9 +130,25 @@ func (m *DeployQuery) GetOptions() map[string]string {
 	return nil
 }
 
+func (m *DeployQuery) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+func (m *DeployQuery) GetWeight() int32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
 type DeployResult struct {
 	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	Details              string   `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
+	ID                   string   `protobuf:"bytes,3,opt,name=ID,proto3" json:"ID,omitempty"`
+	Version              string   `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -173,6 +193,20 @@ func (m *DeployResult) GetDetails() string {
 	return ""
 }
 
+func (m *DeployResult) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *DeployResult) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 type DeployStatusQuery struct {
 	ID                   string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -212,44 +246,209 @@ func (m *DeployStatusQuery) GetID() string {
 	return ""
 }
 
+type RollbackQuery struct {
+	Options              map[string]string `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Version              string            `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RollbackQuery) Reset()         { *m = RollbackQuery{} }
+func (m *RollbackQuery) String() string { return proto.CompactTextString(m) }
+func (*RollbackQuery) ProtoMessage()    {}
+func (*RollbackQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad20155c873eed76, []int{4}
+}
+
+func (m *RollbackQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RollbackQuery.Unmarshal(m, b)
+}
+func (m *RollbackQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RollbackQuery.Marshal(b, m, deterministic)
+}
+func (m *RollbackQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollbackQuery.Merge(m, src)
+}
+func (m *RollbackQuery) XXX_Size() int {
+	return xxx_messageInfo_RollbackQuery.Size(m)
+}
+func (m *RollbackQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollbackQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollbackQuery proto.InternalMessageInfo
+
+func (m *RollbackQuery) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *RollbackQuery) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+type PromoteQuery struct {
+	Options              map[string]string `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ID                   string            `protobuf:"bytes,2,opt,name=ID,proto3" json:"ID,omitempty"`
+	Weight               int32             `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PromoteQuery) Reset()         { *m = PromoteQuery{} }
+func (m *PromoteQuery) String() string { return proto.CompactTextString(m) }
+func (*PromoteQuery) ProtoMessage()    {}
+func (*PromoteQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad20155c873eed76, []int{5}
+}
+
+func (m *PromoteQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PromoteQuery.Unmarshal(m, b)
+}
+func (m *PromoteQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PromoteQuery.Marshal(b, m, deterministic)
+}
+func (m *PromoteQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PromoteQuery.Merge(m, src)
+}
+func (m *PromoteQuery) XXX_Size() int {
+	return xxx_messageInfo_PromoteQuery.Size(m)
+}
+func (m *PromoteQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_PromoteQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PromoteQuery proto.InternalMessageInfo
+
+func (m *PromoteQuery) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *PromoteQuery) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *PromoteQuery) GetWeight() int32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+type AbortQuery struct {
+	Options              map[string]string `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ID                   string            `protobuf:"bytes,2,opt,name=ID,proto3" json:"ID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AbortQuery) Reset()         { *m = AbortQuery{} }
+func (m *AbortQuery) String() string { return proto.CompactTextString(m) }
+func (*AbortQuery) ProtoMessage()    {}
+func (*AbortQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad20155c873eed76, []int{6}
+}
+
+func (m *AbortQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AbortQuery.Unmarshal(m, b)
+}
+func (m *AbortQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AbortQuery.Marshal(b, m, deterministic)
+}
+func (m *AbortQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AbortQuery.Merge(m, src)
+}
+func (m *AbortQuery) XXX_Size() int {
+	return xxx_messageInfo_AbortQuery.Size(m)
+}
+func (m *AbortQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_AbortQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AbortQuery proto.InternalMessageInfo
+
+func (m *AbortQuery) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *AbortQuery) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*IntegrationPluginManifest)(nil), "integrationplugin.IntegrationPluginManifest")
 	proto.RegisterType((*DeployQuery)(nil), "integrationplugin.DeployQuery")
 	proto.RegisterMapType((map[string]string)(nil), "integrationplugin.DeployQuery.OptionsEntry")
 	proto.RegisterType((*DeployResult)(nil), "integrationplugin.DeployResult")
 	proto.RegisterType((*DeployStatusQuery)(nil), "integrationplugin.DeployStatusQuery")
+	proto.RegisterType((*RollbackQuery)(nil), "integrationplugin.RollbackQuery")
+	proto.RegisterMapType((map[string]string)(nil), "integrationplugin.RollbackQuery.OptionsEntry")
+	proto.RegisterType((*PromoteQuery)(nil), "integrationplugin.PromoteQuery")
+	proto.RegisterMapType((map[string]string)(nil), "integrationplugin.PromoteQuery.OptionsEntry")
+	proto.RegisterType((*AbortQuery)(nil), "integrationplugin.AbortQuery")
+	proto.RegisterMapType((map[string]string)(nil), "integrationplugin.AbortQuery.OptionsEntry")
 }
 
 func init() { proto.RegisterFile("integrationplugin.proto", fileDescriptor_ad20155c873eed76) }
 
 var fileDescriptor_ad20155c873eed76 = []byte{
-	// 415 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x4f, 0x8b, 0xda, 0x40,
-	0x1c, 0x35, 0xd1, 0x6a, 0xfb, 0x53, 0x4a, 0x1d, 0x8a, 0x4d, 0x53, 0x68, 0x25, 0xed, 0x41, 0xa8,
-	0x8c, 0x60, 0x2f, 0xe2, 0xa9, 0x88, 0x1e, 0xa4, 0x94, 0xda, 0x78, 0x28, 0xb4, 0xa7, 0x98, 0x8c,
-	0x31, 0x18, 0x33, 0x61, 0x66, 0x22, 0xe4, 0xdc, 0x2f, 0xb0, 0xdf, 0x6a, 0xbf, 0xd6, 0x92, 0x99,
-	0xc9, 0x6e, 0x20, 0x9b, 0x65, 0x6f, 0xf9, 0xfd, 0x79, 0xef, 0x37, 0xef, 0x3d, 0x02, 0xef, 0xa2,
-	0x44, 0x90, 0x90, 0x79, 0x22, 0xa2, 0x49, 0x1a, 0x67, 0x61, 0x94, 0xe0, 0x94, 0x51, 0x41, 0xd1,
-	0xb0, 0x36, 0xb0, 0x3f, 0x84, 0x94, 0x86, 0x31, 0x99, 0xc9, 0x85, 0x43, 0x76, 0x9c, 0x91, 0x4b,
-	0x2a, 0x72, 0xb5, 0xef, 0xfc, 0x37, 0xe0, 0xfd, 0xf6, 0x01, 0xb2, 0x93, 0x90, 0x9f, 0x5e, 0x12,
-	0x1d, 0x09, 0x17, 0x08, 0x41, 0x27, 0xf1, 0x2e, 0xc4, 0x32, 0xc6, 0xc6, 0xe4, 0x95, 0x2b, 0xbf,
-	0x91, 0x05, 0xbd, 0x2b, 0x61, 0x3c, 0xa2, 0x89, 0x65, 0xca, 0x76, 0x59, 0xa2, 0x31, 0xf4, 0x03,
-	0xc2, 0x7d, 0x16, 0xa5, 0x05, 0x95, 0xd5, 0x96, 0xd3, 0x6a, 0x0b, 0x8d, 0xa0, 0xeb, 0x65, 0xe2,
-	0x44, 0x99, 0xd5, 0x91, 0x43, 0x5d, 0x39, 0x37, 0x06, 0xf4, 0xd7, 0x24, 0x8d, 0x69, 0xfe, 0x3b,
-	0x23, 0x2c, 0x47, 0x1b, 0xe8, 0x51, 0x89, 0xe0, 0x96, 0x31, 0x6e, 0x4f, 0xfa, 0xf3, 0xaf, 0xb8,
-	0x2e, 0xb8, 0x02, 0xc0, 0xbf, 0xd4, 0xf6, 0x26, 0x11, 0x2c, 0x77, 0x4b, 0xac, 0xbd, 0x84, 0x41,
-	0x75, 0x80, 0xde, 0x40, 0xfb, 0x4c, 0x72, 0xad, 0xa6, 0xf8, 0x44, 0x6f, 0xe1, 0xc5, 0xd5, 0x8b,
-	0x33, 0xa2, 0xa5, 0xa8, 0x62, 0x69, 0x2e, 0x0c, 0xe7, 0x3b, 0x0c, 0xd4, 0x01, 0x97, 0xf0, 0x2c,
-	0x16, 0xc5, 0xd3, 0xb9, 0xf0, 0x44, 0xc6, 0x35, 0x5c, 0x57, 0x85, 0x1d, 0x01, 0x11, 0x5e, 0x14,
-	0xf3, 0xd2, 0x0e, 0x5d, 0x3a, 0x9f, 0x61, 0xa8, 0x18, 0xf6, 0x72, 0x53, 0x29, 0x7b, 0x0d, 0xe6,
-	0x76, 0xad, 0x29, 0xcc, 0xed, 0x7a, 0x7e, 0x6b, 0xc2, 0xb0, 0xe6, 0x3f, 0x72, 0xe1, 0xe5, 0x7d,
-	0x06, 0x23, 0xac, 0xf2, 0xc3, 0x65, 0x7e, 0x78, 0x53, 0xe4, 0x67, 0x4f, 0x1f, 0xb1, 0xa4, 0x31,
-	0x49, 0xa7, 0x85, 0x7e, 0x40, 0x57, 0x3d, 0x07, 0x7d, 0x7c, 0xda, 0x4c, 0xfb, 0x53, 0xe3, 0x5c,
-	0x79, 0xe1, 0xb4, 0xd0, 0x9f, 0xd2, 0x1d, 0xa5, 0x0d, 0x7d, 0x69, 0x84, 0x54, 0xc4, 0x3f, 0x87,
-	0x78, 0x01, 0x9d, 0xbd, 0xa0, 0x69, 0xa3, 0xea, 0x86, 0xbe, 0xd3, 0x5a, 0xfd, 0x83, 0xa9, 0x4f,
-	0x2f, 0x98, 0x5e, 0x4f, 0xd8, 0x0f, 0x38, 0xe6, 0xc1, 0x19, 0x87, 0x2c, 0xf5, 0xf5, 0x99, 0xda,
-	0xe1, 0xd5, 0xa8, 0x66, 0xd6, 0xae, 0xa0, 0xdc, 0x19, 0x7f, 0xeb, 0xff, 0xd0, 0xa1, 0x2b, 0xcf,
-	0x7d, 0xbb, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x04, 0xbd, 0x83, 0x33, 0x78, 0x03, 0x00, 0x00,
+	// 571 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xcd, 0xe6, 0x9b, 0x49, 0x40, 0x64, 0x85, 0x82, 0x09, 0x82, 0x46, 0x86, 0x43, 0x05, 0xc5,
+	0x95, 0xca, 0xa5, 0xea, 0x8d, 0x2a, 0x01, 0x45, 0xa8, 0x6a, 0x70, 0x0f, 0x48, 0x70, 0x72, 0x92,
+	0xad, 0x63, 0xe2, 0x78, 0xad, 0xdd, 0x75, 0x90, 0xcf, 0xfc, 0x0f, 0x7e, 0x02, 0x07, 0x8e, 0x9c,
+	0xf9, 0x61, 0xc8, 0xbb, 0xeb, 0xd4, 0x8d, 0xe3, 0x36, 0x52, 0xc5, 0xcd, 0xe3, 0x9d, 0xf7, 0xe6,
+	0xe5, 0xf9, 0xcd, 0x06, 0x1e, 0x7b, 0x81, 0x20, 0x2e, 0x73, 0x84, 0x47, 0x83, 0xd0, 0x8f, 0x5c,
+	0x2f, 0xb0, 0x42, 0x46, 0x05, 0xc5, 0x9d, 0xdc, 0x41, 0xef, 0xa9, 0x4b, 0xa9, 0xeb, 0x93, 0x43,
+	0xd9, 0x30, 0x89, 0x2e, 0x0f, 0xc9, 0x32, 0x14, 0xb1, 0xea, 0x37, 0x7f, 0x20, 0x78, 0x32, 0xba,
+	0x82, 0x8c, 0x25, 0xe4, 0xcc, 0x09, 0xbc, 0x4b, 0xc2, 0x05, 0xc6, 0x50, 0x0d, 0x9c, 0x25, 0x31,
+	0x50, 0x1f, 0xed, 0xdf, 0xb3, 0xe5, 0x33, 0x36, 0xa0, 0xb1, 0x22, 0x8c, 0x7b, 0x34, 0x30, 0xca,
+	0xf2, 0x75, 0x5a, 0xe2, 0x3e, 0xb4, 0x66, 0x84, 0x4f, 0x99, 0x17, 0x26, 0x54, 0x46, 0x45, 0x9e,
+	0x66, 0x5f, 0xe1, 0x2e, 0xd4, 0x9d, 0x48, 0xcc, 0x29, 0x33, 0xaa, 0xf2, 0x50, 0x57, 0xe6, 0x5f,
+	0x04, 0xad, 0x01, 0x09, 0x7d, 0x1a, 0x7f, 0x8a, 0x08, 0x8b, 0xf1, 0x10, 0x1a, 0x54, 0x22, 0xb8,
+	0x81, 0xfa, 0x95, 0xfd, 0xd6, 0xd1, 0x6b, 0x2b, 0xff, 0x83, 0x33, 0x00, 0xeb, 0x5c, 0x75, 0x0f,
+	0x03, 0xc1, 0x62, 0x3b, 0xc5, 0xe2, 0x1e, 0x34, 0xb9, 0x60, 0x8e, 0x20, 0x6e, 0xac, 0xb5, 0xae,
+	0xeb, 0x44, 0xca, 0x77, 0xe2, 0xb9, 0x73, 0x21, 0x75, 0xd6, 0x6c, 0x5d, 0xf5, 0x4e, 0xa0, 0x9d,
+	0x25, 0xc3, 0x0f, 0xa1, 0xb2, 0x20, 0xb1, 0x76, 0x20, 0x79, 0xc4, 0x8f, 0xa0, 0xb6, 0x72, 0xfc,
+	0x88, 0x68, 0x4a, 0x55, 0x9c, 0x94, 0x8f, 0x91, 0xf9, 0x0d, 0xda, 0x4a, 0x94, 0x4d, 0x78, 0xe4,
+	0x8b, 0x64, 0x06, 0x17, 0x8e, 0x88, 0xb8, 0x86, 0xeb, 0x2a, 0xb1, 0x70, 0x46, 0x84, 0xe3, 0xf9,
+	0x3c, 0xb5, 0x50, 0x97, 0xf8, 0x01, 0x94, 0x47, 0x03, 0xed, 0x5c, 0x79, 0x34, 0xc8, 0x9a, 0x5d,
+	0xbd, 0x66, 0xb6, 0xf9, 0x02, 0x3a, 0x6a, 0xd6, 0x85, 0xe4, 0x54, 0xbe, 0x29, 0x38, 0x4a, 0xe1,
+	0xe6, 0x2f, 0x04, 0xf7, 0x6d, 0xea, 0xfb, 0x13, 0x67, 0xba, 0x50, 0x1d, 0x1f, 0x36, 0x9d, 0x7d,
+	0xb3, 0xc5, 0xd9, 0x6b, 0x90, 0x02, 0x6f, 0x0b, 0x63, 0x70, 0x27, 0x07, 0xff, 0x20, 0x68, 0x8f,
+	0x19, 0x5d, 0x52, 0x41, 0x94, 0xde, 0xf7, 0x9b, 0x7a, 0x0f, 0xb6, 0xe8, 0xcd, 0x22, 0x0a, 0xe4,
+	0x2a, 0x67, 0xca, 0x6b, 0x63, 0xff, 0xc7, 0xe7, 0xff, 0x89, 0x00, 0xde, 0x4d, 0x28, 0x13, 0x4a,
+	0xfa, 0x60, 0x53, 0xfa, 0xab, 0x2d, 0xd2, 0xaf, 0xfa, 0x77, 0x13, 0x7e, 0x17, 0x81, 0x47, 0xbf,
+	0xab, 0xd0, 0xc9, 0x2d, 0x3b, 0xb6, 0xa1, 0xb9, 0x5e, 0xf8, 0xae, 0xa5, 0x2e, 0x0b, 0x2b, 0xbd,
+	0x2c, 0xac, 0x61, 0x72, 0x59, 0xf4, 0xb6, 0xb9, 0x5e, 0x78, 0x6d, 0x98, 0x25, 0xfc, 0x11, 0xea,
+	0x2a, 0x9d, 0xf8, 0xf9, 0xcd, 0x9b, 0xdb, 0xdb, 0x2b, 0x3c, 0x57, 0x4b, 0x64, 0x96, 0xf0, 0x67,
+	0x68, 0x67, 0xa3, 0x8e, 0x5f, 0x16, 0x42, 0x32, 0xbb, 0xb0, 0x0b, 0xf1, 0x39, 0x34, 0xd3, 0xa8,
+	0xe3, 0xfe, 0x6d, 0x7b, 0xb0, 0x0b, 0xe1, 0x19, 0x34, 0x74, 0x16, 0xf1, 0xde, 0x2d, 0x39, 0xdd,
+	0x85, 0x6e, 0x04, 0x35, 0x99, 0x0f, 0xfc, 0xec, 0xc6, 0xe4, 0xec, 0x42, 0x75, 0x0c, 0xd5, 0x0b,
+	0x41, 0xc3, 0xc2, 0x0f, 0x5c, 0xf0, 0xde, 0x2c, 0x9d, 0x7e, 0x85, 0x83, 0x29, 0x5d, 0x5a, 0x74,
+	0x35, 0xb7, 0xa6, 0x33, 0x6e, 0xf1, 0xd9, 0xc2, 0x72, 0x59, 0x38, 0xd5, 0x63, 0x72, 0x83, 0x4f,
+	0xbb, 0xb9, 0x5c, 0x8c, 0x13, 0xca, 0x31, 0xfa, 0x92, 0xff, 0x6f, 0x9a, 0xd4, 0xe5, 0xb8, 0xb7,
+	0xff, 0x06, 0x00, 0x54, 0x21, 0xe5, 0x6c, 0xd0, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -267,6 +466,9 @@ type IntegrationPluginClient interface {
 	Manifest(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*IntegrationPluginManifest, error)
 	Deploy(ctx context.Context, in *DeployQuery, opts ...grpc.CallOption) (*DeployResult, error)
 	DeployStatus(ctx context.Context, in *DeployStatusQuery, opts ...grpc.CallOption) (*DeployResult, error)
+	Rollback(ctx context.Context, in *RollbackQuery, opts ...grpc.CallOption) (*DeployResult, error)
+	Promote(ctx context.Context, in *PromoteQuery, opts ...grpc.CallOption) (*DeployResult, error)
+	Abort(ctx context.Context, in *AbortQuery, opts ...grpc.CallOption) (*DeployResult, error)
 	Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
@@ -305,6 +507,33 @@ func (c *integrationPluginClient) DeployStatus(ctx context.Context, in *DeploySt
 	return out, nil
 }
 
+func (c *integrationPluginClient) Rollback(ctx context.Context, in *RollbackQuery, opts ...grpc.CallOption) (*DeployResult, error) {
+	out := new(DeployResult)
+	err := c.cc.Invoke(ctx, "/integrationplugin.IntegrationPlugin/Rollback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *integrationPluginClient) Promote(ctx context.Context, in *PromoteQuery, opts ...grpc.CallOption) (*DeployResult, error) {
+	out := new(DeployResult)
+	err := c.cc.Invoke(ctx, "/integrationplugin.IntegrationPlugin/Promote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *integrationPluginClient) Abort(ctx context.Context, in *AbortQuery, opts ...grpc.CallOption) (*DeployResult, error) {
+	out := new(DeployResult)
+	err := c.cc.Invoke(ctx, "/integrationplugin.IntegrationPlugin/Abort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *integrationPluginClient) Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/integrationplugin.IntegrationPlugin/Stop", in, out, opts...)
@@ -319,9 +548,38 @@ type IntegrationPluginServer interface {
 	Manifest(context.Context, *empty.Empty) (*IntegrationPluginManifest, error)
 	Deploy(context.Context, *DeployQuery) (*DeployResult, error)
 	DeployStatus(context.Context, *DeployStatusQuery) (*DeployResult, error)
+	Rollback(context.Context, *RollbackQuery) (*DeployResult, error)
+	Promote(context.Context, *PromoteQuery) (*DeployResult, error)
+	Abort(context.Context, *AbortQuery) (*DeployResult, error)
 	Stop(context.Context, *empty.Empty) (*empty.Empty, error)
 }
 
+// UnimplementedIntegrationPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedIntegrationPluginServer struct {
+}
+
+func (*UnimplementedIntegrationPluginServer) Manifest(ctx context.Context, req *empty.Empty) (*IntegrationPluginManifest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Manifest not implemented")
+}
+func (*UnimplementedIntegrationPluginServer) Deploy(ctx context.Context, req *DeployQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deploy not implemented")
+}
+func (*UnimplementedIntegrationPluginServer) DeployStatus(ctx context.Context, req *DeployStatusQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeployStatus not implemented")
+}
+func (*UnimplementedIntegrationPluginServer) Rollback(ctx context.Context, req *RollbackQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (*UnimplementedIntegrationPluginServer) Promote(ctx context.Context, req *PromoteQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Promote not implemented")
+}
+func (*UnimplementedIntegrationPluginServer) Abort(ctx context.Context, req *AbortQuery) (*DeployResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Abort not implemented")
+}
+func (*UnimplementedIntegrationPluginServer) Stop(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+
 func RegisterIntegrationPluginServer(s *grpc.Server, srv IntegrationPluginServer) {
 	s.RegisterService(&_IntegrationPlugin_serviceDesc, srv)
 }
@@ -380,6 +638,60 @@ func _IntegrationPlugin_DeployStatus_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IntegrationPlugin_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntegrationPluginServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/integrationplugin.IntegrationPlugin/Rollback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntegrationPluginServer).Rollback(ctx, req.(*RollbackQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IntegrationPlugin_Promote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntegrationPluginServer).Promote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/integrationplugin.IntegrationPlugin/Promote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntegrationPluginServer).Promote(ctx, req.(*PromoteQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IntegrationPlugin_Abort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntegrationPluginServer).Abort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/integrationplugin.IntegrationPlugin/Abort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntegrationPluginServer).Abort(ctx, req.(*AbortQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IntegrationPlugin_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
@@ -414,6 +726,18 @@ var _IntegrationPlugin_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeployStatus",
 			Handler:    _IntegrationPlugin_DeployStatus_Handler,
 		},
+		{
+			MethodName: "Rollback",
+			Handler:    _IntegrationPlugin_Rollback_Handler,
+		},
+		{
+			MethodName: "Promote",
+			Handler:    _IntegrationPlugin_Promote_Handler,
+		},
+		{
+			MethodName: "Abort",
+			Handler:    _IntegrationPlugin_Abort_Handler,
+		},
 		{
 			MethodName: "Stop",
 			Handler:    _IntegrationPlugin_Stop_Handler,
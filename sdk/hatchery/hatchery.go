@@ -65,7 +65,7 @@ func Create(ctx context.Context, h Interface) error {
 		return fmt.Errorf("Create> Init error: %v", err)
 	}
 
-	var chanRegister, chanGetModels <-chan time.Time
+	var chanRegister, chanGetModels, chanAutoscaling <-chan time.Time
 	var modelType string
 
 	hWithModels, isWithModels := h.(InterfaceWithModels)
@@ -83,6 +83,13 @@ func Create(ctx context.Context, h Interface) error {
 		chanGetModels = time.Tick(10 * time.Second)                                                          // nolint
 
 		modelType = hWithModels.ModelType()
+
+		if autoscaling := h.Configuration().Provision.Autoscaling; len(autoscaling.Models) > 0 {
+			if err := checkAutoscalingConfiguration(autoscaling); err != nil {
+				return fmt.Errorf("Create> Invalid autoscaling configuration: %v", err)
+			}
+			chanAutoscaling = time.Tick(time.Duration(autoscaling.Frequency) * time.Second) // nolint
+		}
 	}
 
 	wjobs := make(chan sdk.WorkflowNodeJobRun, h.Configuration().Provision.MaxConcurrentProvisioning)
@@ -249,6 +256,13 @@ func Create(ctx context.Context, h Interface) error {
 			}
 
 			if chosenModel != nil {
+				// An idle warm worker of the model will take the job from the queue
+				if canRunJobWithWarmWorker(workerRequest) && takeWarmWorker(chosenModel.Group.Name+"/"+chosenModel.Name, j.ID) {
+					log.Debug("hatchery> job %d will be taken by a warm worker of model %s", j.ID, chosenModel.Name)
+					endTrace("warm worker available")
+					continue
+				}
+
 				//We got a model, let's start a worker
 				workerRequest.model = chosenModel
 			}
@@ -261,6 +275,11 @@ func Create(ctx context.Context, h Interface) error {
 			if err := workerRegister(ctx, hWithModels, workersStartChan); err != nil {
 				log.Warning(ctx, "Error on workerRegister: %s", err)
 			}
+
+		case <-chanAutoscaling:
+			if err := autoscale(ctx, hWithModels, workersStartChan); err != nil {
+				log.Warning(ctx, "Error on autoscale: %s", err)
+			}
 		}
 	}
 }
@@ -282,6 +301,32 @@ func canRunJob(ctx context.Context, h Interface, j workerStarterRequest) bool {
 	return h.CanSpawn(ctx, nil, j.id, j.requirements)
 }
 
+// canRunJobWithWarmWorker returns false if the job needs a worker started for it:
+// services and memory are set when the worker is spawned.
+func canRunJobWithWarmWorker(j workerStarterRequest) bool {
+	for _, r := range j.requirements {
+		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+			return false
+		}
+		// model options like --port are given when the worker is spawned
+		if r.Type == sdk.ModelRequirement && len(strings.Split(r.Value, " ")) > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// isModelRequirementOf returns true if the model requirement is satisfied by given model.
+// r.Value could be: theModelName --port=8888:9999, so we take strings.Split(r.Value, " ")[0] to compare
+// only modelName
+func isModelRequirementOf(r sdk.Requirement, model *sdk.Model) bool {
+	modelName := strings.Split(r.Value, " ")[0]
+	isGroupModel := modelName == fmt.Sprintf("%s/%s", model.Group.Name, model.Name)
+	isSharedInfraModel := model.Group.Name == sdk.SharedInfraGroupName && modelName == model.Name
+	isSameName := modelName == model.Name // for backward compatibility with runs, if only the name match we considered that the model can be used, keep this condition until the workflow runs were not migrated.
+	return isGroupModel || isSharedInfraModel || isSameName
+}
+
 // MemoryRegisterContainer is the RAM used for spawning
 // a docker container for register a worker model. 128 Mo
 const MemoryRegisterContainer int64 = 128
@@ -338,11 +383,7 @@ func canRunJobWithModel(ctx context.Context, h InterfaceWithModels, j workerStar
 		// r.Value could be: theModelName --port=8888:9999, so we take strings.Split(r.Value, " ")[0] to compare
 		// only modelName
 		if r.Type == sdk.ModelRequirement {
-			modelName := strings.Split(r.Value, " ")[0]
-			isGroupModel := modelName == fmt.Sprintf("%s/%s", model.Group.Name, model.Name)
-			isSharedInfraModel := model.Group.Name == sdk.SharedInfraGroupName && modelName == model.Name
-			isSameName := modelName == model.Name // for backward compatibility with runs, if only the name match we considered that the model can be used, keep this condition until the workflow runs were not migrated.
-			if !isGroupModel && !isSharedInfraModel && !isSameName {
+			if !isModelRequirementOf(r, model) {
 				log.Debug("canRunJob> %d - job %d - model requirement r.Value(%s) do not match model.Name(%s) and model.Group(%s)", j.timestamp, j.id, strings.Split(r.Value, " ")[0], model.Name, model.Group.Name)
 				return false
 			}
@@ -2,11 +2,20 @@ package hatchery
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opencensus.io/stats"
+
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/namesgenerator"
 )
 
 var (
@@ -57,3 +66,365 @@ func checkCapacities(ctx context.Context, h Interface) bool {
 
 	return true
 }
+
+const (
+	warmWorkerPrefix       = "warm-"
+	defaultWarmIdleTimeout = 600
+)
+
+// warmPools keeps the state of warm workers between two autoscaling checks.
+var warmPools = struct {
+	sync.Mutex
+	// idleSince is the first time a warm worker was seen idle
+	idleSince map[string]time.Time
+	// available is the number of idle or starting warm workers, by model path
+	available map[string]int
+	// assigned is the set of waiting jobs that will be taken by a warm worker, by model path
+	assigned map[string]map[int64]struct{}
+}{
+	idleSince: map[string]time.Time{},
+	available: map[string]int{},
+	assigned:  map[string]map[int64]struct{}{},
+}
+
+// checkAutoscalingConfiguration returns an error if an autoscaling policy is invalid.
+func checkAutoscalingConfiguration(cfg service.HatcheryAutoscalingConfiguration) error {
+	if cfg.Frequency <= 0 {
+		return fmt.Errorf("invalid autoscaling frequency %d", cfg.Frequency)
+	}
+	for _, m := range cfg.Models {
+		if len(strings.Split(m.Model, "/")) != 2 {
+			return fmt.Errorf("invalid autoscaling model %q, it should be group/name", m.Model)
+		}
+		if m.MinIdle < 0 || m.MaxIdle < 0 || m.IdleTimeout < 0 || m.JobsPerWorker < 0 {
+			return fmt.Errorf("invalid autoscaling policy for model %s, values should be positive", m.Model)
+		}
+		if m.MaxIdle > 0 && m.MaxIdle < m.MinIdle {
+			return fmt.Errorf("invalid autoscaling policy for model %s, maxIdle should be greater than minIdle", m.Model)
+		}
+		for _, s := range m.Schedules {
+			if _, err := time.Parse("15:04", s.Start); err != nil {
+				return fmt.Errorf("invalid autoscaling schedule start %q for model %s", s.Start, m.Model)
+			}
+			if _, err := time.Parse("15:04", s.End); err != nil {
+				return fmt.Errorf("invalid autoscaling schedule end %q for model %s", s.End, m.Model)
+			}
+			if s.MinIdle < 0 || (m.MaxIdle > 0 && s.MinIdle > m.MaxIdle) {
+				return fmt.Errorf("invalid autoscaling schedule minIdle %d for model %s", s.MinIdle, m.Model)
+			}
+			for _, d := range s.Days {
+				if _, ok := parseWeekday(d); !ok {
+					return fmt.Errorf("invalid autoscaling schedule day %q for model %s", d, m.Model)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) || strings.EqualFold(s, d.String()[:3]) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// inSchedule returns true if given time is in the schedule, a schedule ending before its start ends the next day.
+func inSchedule(s service.HatcheryAutoscalingScheduleConfiguration, now time.Time) bool {
+	start, errS := time.Parse("15:04", s.Start)
+	end, errE := time.Parse("15:04", s.End)
+	if errS != nil || errE != nil {
+		return false
+	}
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+	nowMinutes := now.Hour()*60 + now.Minute()
+
+	day := now.Weekday()
+	var inRange bool
+	if startMinutes <= endMinutes {
+		inRange = nowMinutes >= startMinutes && nowMinutes < endMinutes
+	} else {
+		inRange = nowMinutes >= startMinutes || nowMinutes < endMinutes
+		// after midnight, the schedule started the day before
+		if nowMinutes < endMinutes {
+			day = (day + 6) % 7
+		}
+	}
+	if !inRange {
+		return false
+	}
+
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, d := range s.Days {
+		if wd, ok := parseWeekday(d); ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// desiredIdle returns the number of idle workers wanted for a model at given time for given queue depth.
+func desiredIdle(cfg service.HatcheryAutoscalingModelConfiguration, now time.Time, queueDepth int) int {
+	n := cfg.MinIdle
+	for _, s := range cfg.Schedules {
+		if inSchedule(s, now) {
+			n = s.MinIdle
+			break
+		}
+	}
+	if cfg.JobsPerWorker > 0 {
+		if q := (queueDepth + cfg.JobsPerWorker - 1) / cfg.JobsPerWorker; q > n {
+			n = q
+		}
+	}
+	if cfg.MaxIdle > 0 && n > cfg.MaxIdle {
+		n = cfg.MaxIdle
+	}
+	return n
+}
+
+func warmWorkerNamePrefix(hatcheryName, modelPath string) string {
+	return warmWorkerPrefix + hatcheryName + "-" + strings.Replace(strings.ToLower(modelPath), "/", "-", -1) + "-"
+}
+
+// queueDepth returns the number of waiting jobs with a model requirement on given model.
+func queueDepth(jobs []sdk.WorkflowNodeJobRun, model *sdk.Model) int {
+	var n int
+	for _, j := range jobs {
+		if j.Status != sdk.StatusWaiting {
+			continue
+		}
+		for _, r := range j.Job.Action.Requirements {
+			if r.Type == sdk.ModelRequirement && isModelRequirementOf(r, model) {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// takeWarmWorker returns true if an idle or starting warm worker of given model can take the job.
+// A job seen again in the queue keeps its warm worker.
+func takeWarmWorker(modelPath string, jobID int64) bool {
+	warmPools.Lock()
+	defer warmPools.Unlock()
+	assigned, ok := warmPools.assigned[modelPath]
+	if !ok {
+		assigned = map[int64]struct{}{}
+		warmPools.assigned[modelPath] = assigned
+	}
+	if _, ok := assigned[jobID]; ok {
+		return true
+	}
+	if warmPools.available[modelPath]-len(assigned) <= 0 {
+		return false
+	}
+	assigned[jobID] = struct{}{}
+	return true
+}
+
+// setWarmWorkersAvailable sets the number of warm workers of a model, jobs that are not waiting anymore
+// have been taken by a worker and are not counted anymore.
+func setWarmWorkersAvailable(modelPath string, available int, jobs []sdk.WorkflowNodeJobRun) {
+	warmPools.Lock()
+	defer warmPools.Unlock()
+	warmPools.available[modelPath] = available
+	waiting := make(map[int64]struct{}, len(jobs))
+	for _, j := range jobs {
+		if j.Status == sdk.StatusWaiting {
+			waiting[j.ID] = struct{}{}
+		}
+	}
+	for id := range warmPools.assigned[modelPath] {
+		if _, ok := waiting[id]; !ok {
+			delete(warmPools.assigned[modelPath], id)
+		}
+	}
+}
+
+// autoscale starts warm workers for each model of the autoscaling configuration and stops
+// the workers idle for too long above the pool size.
+func autoscale(ctx context.Context, h InterfaceWithModels, workersStartChan chan<- workerStarterRequest) error {
+	cfg := h.Configuration().Provision.Autoscaling
+	if len(cfg.Models) == 0 || h.Service() == nil {
+		return nil
+	}
+
+	pool, err := WorkerPool(ctx, h)
+	if err != nil {
+		return err
+	}
+	jobs, err := h.CDSClient().QueueWorkflowNodeJobRun(sdk.StatusWaiting)
+	if err != nil {
+		return sdk.WrapError(err, "cannot get queue")
+	}
+
+	var nbActive int
+	for _, w := range pool {
+		if w.Status != sdk.StatusDisabled {
+			nbActive++
+		}
+	}
+	room := h.Configuration().Provision.MaxWorker - nbActive
+
+	now := time.Now()
+	idleNames := map[string]struct{}{}
+	for _, mcfg := range cfg.Models {
+		var model *sdk.Model
+		for i := range models {
+			if models[i].Group.Name+"/"+models[i].Name == mcfg.Model {
+				model = &models[i]
+				break
+			}
+		}
+		if model == nil || model.Type != h.ModelType() {
+			log.Warning(ctx, "hatchery> autoscale> worker model %s not found for hatchery %s", mcfg.Model, h.Name())
+			continue
+		}
+
+		prefix := warmWorkerNamePrefix(h.Service().Name, mcfg.Model)
+		var idle []sdk.Worker
+		var starting int
+		for _, w := range pool {
+			if !strings.HasPrefix(w.Name, prefix) {
+				continue
+			}
+			switch w.Status {
+			case sdk.StatusWaiting:
+				idle = append(idle, w)
+				idleNames[w.Name] = struct{}{}
+			case sdk.StatusWorkerPending, sdk.StatusWorkerRegistering:
+				starting++
+			}
+		}
+
+		depth := queueDepth(jobs, model)
+		desired := desiredIdle(mcfg, now, depth)
+
+		ctxModel := observability.ContextWithTag(ctx,
+			observability.TagServiceName, h.Name(),
+			observability.TagServiceType, h.Type(),
+			observability.TagWorkerModel, mcfg.Model,
+		)
+		stats.Record(ctxModel,
+			GetMetrics().WarmWorkers.M(int64(len(idle)+starting)),
+			GetMetrics().QueueDepth.M(int64(depth)),
+		)
+
+		// scale up
+		toStart := desired - len(idle) - starting
+		if toStart > room {
+			toStart = room
+		}
+		for i := 0; i < toStart; i++ {
+			log.Info(ctx, "hatchery> autoscale> starting warm worker for model %s (idle:%d starting:%d desired:%d queue:%d)", mcfg.Model, len(idle), starting, desired, depth)
+			workersStartChan <- workerStarterRequest{ctx: ctxModel, cancel: func(string) {}, warmWorkerModel: model}
+			room--
+		}
+		if toStart < 0 {
+			toStart = 0
+		}
+
+		// scale down
+		idleTimeout := mcfg.IdleTimeout
+		if idleTimeout == 0 {
+			idleTimeout = defaultWarmIdleTimeout
+		}
+		stopped := scaleDownWarmWorkers(ctxModel, h, idle, desired, time.Duration(idleTimeout)*time.Second, now)
+
+		setWarmWorkersAvailable(mcfg.Model, len(idle)-stopped+starting+toStart, jobs)
+	}
+
+	// forget workers that are not idle anymore
+	warmPools.Lock()
+	for name := range warmPools.idleSince {
+		if _, ok := idleNames[name]; !ok {
+			delete(warmPools.idleSince, name)
+		}
+	}
+	warmPools.Unlock()
+
+	return nil
+}
+
+// scaleDownWarmWorkers disables the workers idle for more than the timeout while there are more idle workers than desired,
+// disabled workers are then removed by the hatchery. It returns the number of disabled workers.
+func scaleDownWarmWorkers(ctx context.Context, h Interface, idle []sdk.Worker, desired int, timeout time.Duration, now time.Time) int {
+	warmPools.Lock()
+	for _, w := range idle {
+		if _, ok := warmPools.idleSince[w.Name]; !ok {
+			warmPools.idleSince[w.Name] = now
+		}
+	}
+	// oldest idle workers first
+	sorted := make([]sdk.Worker, len(idle))
+	copy(sorted, idle)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return warmPools.idleSince[sorted[i].Name].Before(warmPools.idleSince[sorted[j].Name])
+	})
+	var toStop []sdk.Worker
+	for _, w := range sorted {
+		if len(idle)-len(toStop) <= desired {
+			break
+		}
+		if now.Sub(warmPools.idleSince[w.Name]) < timeout {
+			break
+		}
+		toStop = append(toStop, w)
+	}
+	warmPools.Unlock()
+
+	var stopped int
+	for _, w := range toStop {
+		log.Info(ctx, "hatchery> autoscale> stopping idle warm worker %s", w.Name)
+		if err := h.CDSClient().WorkerDisable(ctx, w.ID); err != nil {
+			log.Error(ctx, "hatchery> autoscale> unable to disable worker %s: %v", w.Name, err)
+			continue
+		}
+		observability.Record(ctx, GetMetrics().WarmWorkersStopped, 1)
+		stopped++
+	}
+	return stopped
+}
+
+// spawnWarmWorker starts a worker without job for given model.
+func spawnWarmWorker(ctx context.Context, h Interface, m *sdk.Model) {
+	maxProv := h.Configuration().Provision.MaxConcurrentProvisioning
+	if maxProv < 1 {
+		maxProv = defaultMaxProvisioning
+	}
+	if atomic.LoadInt64(&nbWorkerToStart) >= int64(maxProv) {
+		log.Debug("hatchery> spawnWarmWorker> max concurrent provisioning reached")
+		return
+	}
+	atomic.AddInt64(&nbWorkerToStart, 1)
+	defer atomic.AddInt64(&nbWorkerToStart, -1)
+
+	modelPath := m.Group.Name + "/" + m.Name
+	arg := SpawnArguments{
+		WorkerName:   warmWorkerNamePrefix(h.Service().Name, modelPath) + strings.Replace(namesgenerator.GetRandomNameCDS(0), "_", "-", -1),
+		Model:        m,
+		Warm:         true,
+		HatcheryName: h.Service().Name,
+	}
+
+	// Get a JWT to authentified the worker
+	jwt, err := NewWorkerToken(h.Service().Name, h.GetPrivateKey(), time.Now().Add(1*time.Hour), arg)
+	if err != nil {
+		log.Error(ctx, "hatchery> spawnWarmWorker> cannot create worker token for model %s: %v", modelPath, err)
+		return
+	}
+	arg.WorkerToken = jwt
+
+	if err := h.SpawnWorker(ctx, arg); err != nil {
+		log.Warning(ctx, "hatchery> spawnWarmWorker> cannot spawn warm worker for model %s: %v", modelPath, err)
+		return
+	}
+	observability.Record(ctx, GetMetrics().WarmWorkersStarted, 1)
+}
@@ -0,0 +1,132 @@
+package hatchery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestInSchedule(t *testing.T) {
+	// 2020-03-02 is a monday
+	monday := func(hour, min int) time.Time { return time.Date(2020, 3, 2, hour, min, 0, 0, time.UTC) }
+
+	office := service.HatcheryAutoscalingScheduleConfiguration{Days: []string{"monday", "Tue"}, Start: "08:00", End: "19:00"}
+	assert.True(t, inSchedule(office, monday(8, 0)))
+	assert.True(t, inSchedule(office, monday(18, 59)))
+	assert.False(t, inSchedule(office, monday(19, 0)))
+	assert.False(t, inSchedule(office, monday(7, 59)))
+	assert.False(t, inSchedule(office, monday(10, 0).AddDate(0, 0, 2)), "wednesday is not in schedule")
+
+	// a nightly schedule started sunday ends monday morning
+	nightly := service.HatcheryAutoscalingScheduleConfiguration{Days: []string{"sunday"}, Start: "22:00", End: "02:00"}
+	assert.True(t, inSchedule(nightly, monday(1, 30)))
+	assert.False(t, inSchedule(nightly, monday(22, 30)))
+	assert.True(t, inSchedule(nightly, monday(22, 30).AddDate(0, 0, -1)))
+
+	everyDay := service.HatcheryAutoscalingScheduleConfiguration{Start: "00:00", End: "23:59"}
+	assert.True(t, inSchedule(everyDay, monday(12, 0).AddDate(0, 0, 5)))
+}
+
+func TestDesiredIdle(t *testing.T) {
+	now := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	cfg := service.HatcheryAutoscalingModelConfiguration{
+		Model:         "shared.infra/go",
+		MinIdle:       1,
+		MaxIdle:       5,
+		JobsPerWorker: 2,
+	}
+	assert.Equal(t, 1, desiredIdle(cfg, now, 0))
+	assert.Equal(t, 2, desiredIdle(cfg, now, 3))
+	assert.Equal(t, 5, desiredIdle(cfg, now, 42), "pool size is limited by maxIdle")
+
+	cfg.Schedules = []service.HatcheryAutoscalingScheduleConfiguration{{Start: "08:00", End: "19:00", MinIdle: 3}}
+	assert.Equal(t, 3, desiredIdle(cfg, now, 0))
+	assert.Equal(t, 1, desiredIdle(cfg, now.Add(12*time.Hour), 0))
+
+	cfg.JobsPerWorker = 0
+	assert.Equal(t, 3, desiredIdle(cfg, now, 42), "queue depth scaling is disabled")
+}
+
+func TestQueueDepth(t *testing.T) {
+	model := &sdk.Model{Name: "go", Group: &sdk.Group{Name: "shared.infra"}}
+	job := func(status string, value string) sdk.WorkflowNodeJobRun {
+		j := sdk.WorkflowNodeJobRun{Status: status}
+		j.Job.Action.Requirements = []sdk.Requirement{{Type: sdk.BinaryRequirement, Value: "git"}}
+		if value != "" {
+			j.Job.Action.Requirements = append(j.Job.Action.Requirements, sdk.Requirement{Type: sdk.ModelRequirement, Value: value})
+		}
+		return j
+	}
+	jobs := []sdk.WorkflowNodeJobRun{
+		job(sdk.StatusWaiting, "shared.infra/go"),
+		job(sdk.StatusWaiting, "go --privileged"),
+		job(sdk.StatusBuilding, "shared.infra/go"),
+		job(sdk.StatusWaiting, "shared.infra/node"),
+		job(sdk.StatusWaiting, ""),
+	}
+	assert.Equal(t, 2, queueDepth(jobs, model))
+}
+
+func TestCheckAutoscalingConfiguration(t *testing.T) {
+	valid := service.HatcheryAutoscalingConfiguration{
+		Frequency: 30,
+		Models: []service.HatcheryAutoscalingModelConfiguration{{
+			Model:   "shared.infra/go",
+			MinIdle: 1,
+			MaxIdle: 3,
+			Schedules: []service.HatcheryAutoscalingScheduleConfiguration{
+				{Days: []string{"mon", "friday"}, Start: "08:00", End: "19:00", MinIdle: 2},
+			},
+		}},
+	}
+	assert.NoError(t, checkAutoscalingConfiguration(valid))
+
+	for name, update := range map[string]func(c *service.HatcheryAutoscalingConfiguration){
+		"frequency":   func(c *service.HatcheryAutoscalingConfiguration) { c.Frequency = 0 },
+		"model":       func(c *service.HatcheryAutoscalingConfiguration) { c.Models[0].Model = "go" },
+		"maxIdle":     func(c *service.HatcheryAutoscalingConfiguration) { c.Models[0].MaxIdle = 0; c.Models[0].MinIdle = -1 },
+		"minIdle":     func(c *service.HatcheryAutoscalingConfiguration) { c.Models[0].MinIdle = 4 },
+		"start":       func(c *service.HatcheryAutoscalingConfiguration) { c.Models[0].Schedules[0].Start = "8h" },
+		"day":         func(c *service.HatcheryAutoscalingConfiguration) { c.Models[0].Schedules[0].Days = []string{"someday"} },
+		"scheduleMax": func(c *service.HatcheryAutoscalingConfiguration) { c.Models[0].Schedules[0].MinIdle = 4 },
+	} {
+		c := valid
+		c.Models = []service.HatcheryAutoscalingModelConfiguration{valid.Models[0]}
+		c.Models[0].Schedules = []service.HatcheryAutoscalingScheduleConfiguration{valid.Models[0].Schedules[0]}
+		update(&c)
+		assert.Error(t, checkAutoscalingConfiguration(c), name)
+	}
+}
+
+func TestCanRunJobWithWarmWorker(t *testing.T) {
+	assert.True(t, canRunJobWithWarmWorker(workerStarterRequest{requirements: []sdk.Requirement{{Type: sdk.ModelRequirement, Value: "shared.infra/go"}}}))
+	assert.False(t, canRunJobWithWarmWorker(workerStarterRequest{requirements: []sdk.Requirement{{Type: sdk.ModelRequirement, Value: "shared.infra/go --privileged"}}}))
+	assert.False(t, canRunJobWithWarmWorker(workerStarterRequest{requirements: []sdk.Requirement{{Type: sdk.ServiceRequirement, Name: "pg", Value: "postgres"}}}))
+	assert.False(t, canRunJobWithWarmWorker(workerStarterRequest{requirements: []sdk.Requirement{{Type: sdk.MemoryRequirement, Value: "4096"}}}))
+}
+
+func TestTakeWarmWorker(t *testing.T) {
+	model := "shared.infra/" + sdk.RandomString(10)
+	setWarmWorkersAvailable(model, 2, nil)
+
+	assert.True(t, takeWarmWorker(model, 1))
+	// The same job seen again in the queue keeps its warm worker
+	assert.True(t, takeWarmWorker(model, 1))
+	assert.True(t, takeWarmWorker(model, 1))
+	assert.True(t, takeWarmWorker(model, 2))
+	assert.False(t, takeWarmWorker(model, 3))
+	assert.True(t, takeWarmWorker(model, 2))
+
+	// Job 1 has been taken by a warm worker that is not idle anymore, job 2 is still waiting
+	setWarmWorkersAvailable(model, 1, []sdk.WorkflowNodeJobRun{{ID: 2, Status: sdk.StatusWaiting}, {ID: 3, Status: sdk.StatusWaiting}})
+	assert.False(t, takeWarmWorker(model, 3))
+	assert.True(t, takeWarmWorker(model, 2))
+
+	// A new warm worker is starting
+	setWarmWorkersAvailable(model, 2, []sdk.WorkflowNodeJobRun{{ID: 2, Status: sdk.StatusWaiting}, {ID: 3, Status: sdk.StatusWaiting}})
+	assert.True(t, takeWarmWorker(model, 3))
+}
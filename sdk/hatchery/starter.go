@@ -27,6 +27,7 @@ type workerStarterRequest struct {
 	timestamp           int64
 	workflowNodeRunID   int64
 	registerWorkerModel *sdk.Model
+	warmWorkerModel     *sdk.Model
 }
 
 func PanicDump(h Interface) func(s string) (io.WriteCloser, error) {
@@ -59,7 +60,9 @@ func startWorkerStarters(ctx context.Context, h Interface) chan<- workerStarterR
 func workerStarter(ctx context.Context, h Interface, workerNum string, jobs <-chan workerStarterRequest) {
 	for j := range jobs {
 		// Start a worker for a job
-		if m := j.registerWorkerModel; m == nil {
+		if m := j.warmWorkerModel; m != nil { // Start a warm worker without job
+			spawnWarmWorker(j.ctx, h, m)
+		} else if m := j.registerWorkerModel; m == nil {
 			_ = spawnWorkerForJob(ctx, h, j)
 			j.cancel("") // call to EndTrace for observability
 		} else { // Start a worker for registering
@@ -33,8 +33,13 @@ func initMetrics() error {
 		metrics.CheckingWorkers = stats.Int64("cds/checking_workers", "number of checking workers", stats.UnitDimensionless)
 		metrics.BuildingWorkers = stats.Int64("cds/building_workers", "number of building workers", stats.UnitDimensionless)
 		metrics.DisabledWorkers = stats.Int64("cds/disabled_workers", "number of disabled workers", stats.UnitDimensionless)
+		metrics.WarmWorkers = stats.Int64("cds/warm_workers", "number of idle or starting warm workers by model", stats.UnitDimensionless)
+		metrics.WarmWorkersStarted = stats.Int64("cds/warm_workers_started", "number of started warm workers", stats.UnitDimensionless)
+		metrics.WarmWorkersStopped = stats.Int64("cds/warm_workers_stopped", "number of warm workers stopped after idle timeout", stats.UnitDimensionless)
+		metrics.QueueDepth = stats.Int64("cds/queue_depth", "number of waiting jobs by model", stats.UnitDimensionless)
 
 		tags := []tag.Key{observability.MustNewKey(observability.TagServiceType), observability.MustNewKey(observability.TagServiceName)}
+		modelTags := append(tags, observability.MustNewKey(observability.TagWorkerModel))
 		err = observability.RegisterView(
 			observability.NewViewCount("cds/hatchery/jobs_count", metrics.Jobs, tags),
 			observability.NewViewCount("cds/hatchery/jobs_sse_count", metrics.JobsSSE, tags),
@@ -45,6 +50,10 @@ func initMetrics() error {
 			observability.NewViewLast("cds/hatchery/checking_workers", metrics.CheckingWorkers, tags),
 			observability.NewViewLast("cds/hatchery/building_workers", metrics.BuildingWorkers, tags),
 			observability.NewViewLast("cds/hatchery/disabled_workers", metrics.DisabledWorkers, tags),
+			observability.NewViewLast("cds/hatchery/warm_workers", metrics.WarmWorkers, modelTags),
+			observability.NewViewCount("cds/hatchery/warm_workers_started_count", metrics.WarmWorkersStarted, modelTags),
+			observability.NewViewCount("cds/hatchery/warm_workers_stopped_count", metrics.WarmWorkersStopped, modelTags),
+			observability.NewViewLast("cds/hatchery/queue_depth", metrics.QueueDepth, modelTags),
 		)
 	})
 	return err
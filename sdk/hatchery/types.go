@@ -69,6 +69,7 @@ type SpawnArguments struct {
 	JobID        int64             `json:"job_id"`
 	Requirements []sdk.Requirement `json:"requirements"`
 	RegisterOnly bool              `json:"register_only"`
+	Warm         bool              `json:"warm"`
 	HatcheryName string            `json:"hatchery_name"`
 }
 
@@ -123,4 +124,8 @@ type Metrics struct {
 	WaitingWorkers     *stats.Int64Measure
 	BuildingWorkers    *stats.Int64Measure
 	DisabledWorkers    *stats.Int64Measure
+	WarmWorkers        *stats.Int64Measure
+	WarmWorkersStarted *stats.Int64Measure
+	WarmWorkersStopped *stats.Int64Measure
+	QueueDepth         *stats.Int64Measure
 }
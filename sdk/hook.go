@@ -17,8 +17,11 @@ const (
 	HookConfigTargetHook          = "target_hook"
 	HookConfigWorkflowID          = "workflow_id"
 	HookConfigWebHookID           = "webHookID"
+	HookConfigWebHookSecret       = "webHookSecret"
 	HookConfigVCSServer           = "vcsServer"
 	HookConfigEventFilter         = "eventFilter"
+	HookConfigPathsInclude        = "pathsInclude"
+	HookConfigPathsExclude        = "pathsExclude"
 	HookConfigRepoFullName        = "repoFullName"
 	HookConfigModelType           = "model_type"
 	HookConfigModelName           = "model_name"
@@ -143,6 +146,16 @@ var (
 				Configurable: false,
 				Type:         HookConfigTypeString,
 			},
+			HookConfigPathsInclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPathsExclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -158,6 +171,16 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			HookConfigPathsInclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPathsExclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -198,6 +221,16 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeMultiChoice,
 			},
+			HookConfigPathsInclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPathsExclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
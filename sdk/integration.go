@@ -1,9 +1,15 @@
 package sdk
 
+import (
+	"strings"
+	"time"
+)
+
 // This is the buitin integration model
 const (
 	KafkaIntegrationModel         = "Kafka"
 	RabbitMQIntegrationModel      = "RabbitMQ"
+	WebhookIntegrationModel       = "Webhook"
 	OpenstackIntegrationModel     = "Openstack"
 	AWSIntegrationModel           = "AWS"
 	DefaultStorageIntegrationName = "shared.infra"
@@ -14,6 +20,7 @@ var (
 	BuiltinIntegrationModels = []*IntegrationModel{
 		&KafkaIntegration,
 		&RabbitMQIntegration,
+		&WebhookIntegration,
 		&OpenstackIntegration,
 		&AWSIntegration,
 	}
@@ -37,6 +44,18 @@ var (
 				Type:        IntegrationConfigTypeString,
 				Description: "This is mandatory only if you want to use Event Integration",
 			},
+			IntegrationConfigEventTypes: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventTypesDescription,
+			},
+			IntegrationConfigEventWorkflows: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventWorkflowsDescription,
+			},
+			IntegrationConfigEventStatuses: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventStatusesDescription,
+			},
 		},
 		Disabled: false,
 		Hook:     true,
@@ -58,9 +77,66 @@ var (
 			"password": IntegrationConfigValue{
 				Type: IntegrationConfigTypePassword,
 			},
+			"exchange": IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "Exchange used to publish events, default exchange if empty",
+			},
+			"routing key": IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "Routing key used to publish events, mandatory only if you want to use Event Integration with the default exchange",
+			},
+			IntegrationConfigEventTypes: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventTypesDescription,
+			},
+			IntegrationConfigEventWorkflows: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventWorkflowsDescription,
+			},
+			IntegrationConfigEventStatuses: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventStatusesDescription,
+			},
 		},
 		Disabled: false,
 		Hook:     true,
+		Event:    true,
+	}
+	// WebhookIntegration represents an outgoing HTTP webhook integration for events
+	WebhookIntegration = IntegrationModel{
+		Name:       WebhookIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/webhook",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			"url": IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			"secret": IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "If set, the body of each request is signed with HMAC-SHA256 in the X-Cds-Signature header",
+			},
+			"max retries": IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Value:       "3",
+				Description: "Number of retries before an event is moved to the dead letters",
+			},
+			IntegrationConfigEventTypes: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventTypesDescription,
+			},
+			IntegrationConfigEventWorkflows: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventWorkflowsDescription,
+			},
+			IntegrationConfigEventStatuses: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: IntegrationConfigEventStatusesDescription,
+			},
+		},
+		Disabled: false,
+		Hook:     false,
+		Event:    true,
 	}
 	// OpenstackIntegration represents an openstack integration
 	OpenstackIntegration = IntegrationModel{
@@ -136,6 +212,73 @@ var (
 	}
 )
 
+// Event integrations common configuration
+const (
+	IntegrationConfigEventTypes                = "event types"
+	IntegrationConfigEventTypesDescription     = "Comma separated list of event types to send (ie. EventRunWorkflow,EventRunWorkflowJob), a trailing * matches all the types with the given prefix. All events are sent if empty"
+	IntegrationConfigEventWorkflows            = "event workflows"
+	IntegrationConfigEventWorkflowsDescription = "Comma separated list of workflow names, only the events of these workflows are sent. All events are sent if empty"
+	IntegrationConfigEventStatuses             = "event statuses"
+	IntegrationConfigEventStatusesDescription  = "Comma separated list of statuses (ie. Success,Fail), only the events with one of these statuses are sent. All events are sent if empty"
+)
+
+// EventIntegrationFilter selects the events sent to an event integration
+type EventIntegrationFilter struct {
+	EventTypes    []string `json:"event_types,omitempty"`
+	WorkflowNames []string `json:"workflow_names,omitempty"`
+	Statuses      []string `json:"statuses,omitempty"`
+}
+
+// NewEventIntegrationFilter returns the filter of an event integration configuration
+func NewEventIntegrationFilter(config IntegrationConfig) EventIntegrationFilter {
+	var f EventIntegrationFilter
+	for _, t := range splitIntegrationConfigList(config[IntegrationConfigEventTypes].Value) {
+		f.EventTypes = append(f.EventTypes, strings.TrimPrefix(t, "sdk."))
+	}
+	f.WorkflowNames = splitIntegrationConfigList(config[IntegrationConfigEventWorkflows].Value)
+	f.Statuses = splitIntegrationConfigList(config[IntegrationConfigEventStatuses].Value)
+	return f
+}
+
+func splitIntegrationConfigList(s string) []string {
+	var res []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Match returns true if the event is selected by the filter, an empty filter selects all events.
+// A trailing * in event types matches all the types with the given prefix.
+func (f EventIntegrationFilter) Match(e Event) bool {
+	if len(f.EventTypes) > 0 {
+		eventType := strings.TrimPrefix(e.EventType, "sdk.")
+		var found bool
+		for _, t := range f.EventTypes {
+			if strings.HasSuffix(t, "*") {
+				found = strings.HasPrefix(eventType, strings.TrimSuffix(t, "*"))
+			} else {
+				found = t == eventType
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.WorkflowNames) > 0 && !IsInArray(e.WorkflowName, f.WorkflowNames) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !IsInArray(e.Status, f.Statuses) {
+		return false
+	}
+	return true
+}
+
 // IntegrationType represents all different type of integrations
 type IntegrationType string
 
@@ -291,3 +434,21 @@ func (config *IntegrationConfig) HideSecrets() {
 		}
 	}
 }
+
+// EventIntegrationStatus is the delivery status of events sent to an event integration
+type EventIntegrationStatus struct {
+	IntegrationID int64             `json:"integration_id" cli:"-"`
+	Sent          int64             `json:"sent" cli:"sent"`
+	Failed        int64             `json:"failed" cli:"failed"`
+	LastDelivery  *time.Time        `json:"last_delivery,omitempty" cli:"last_delivery"`
+	LastError     string            `json:"last_error,omitempty" cli:"last_error"`
+	LastErrorDate *time.Time        `json:"last_error_date,omitempty" cli:"last_error_date"`
+	DeadLetters   []EventDeadLetter `json:"dead_letters,omitempty" cli:"-"`
+}
+
+// EventDeadLetter is an event that could not be delivered to an event integration
+type EventDeadLetter struct {
+	Event Event     `json:"event"`
+	Error string    `json:"error"`
+	Date  time.Time `json:"date"`
+}
@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventIntegrationFilter(t *testing.T) {
+	f := NewEventIntegrationFilter(IntegrationConfig{
+		IntegrationConfigEventTypes:     IntegrationConfigValue{Value: "sdk.EventRunWorkflow, EventRunWorkflowNode*"},
+		IntegrationConfigEventWorkflows: IntegrationConfigValue{Value: "build,deploy"},
+		IntegrationConfigEventStatuses:  IntegrationConfigValue{Value: ""},
+	})
+	assert.Equal(t, []string{"EventRunWorkflow", "EventRunWorkflowNode*"}, f.EventTypes)
+	assert.Equal(t, []string{"build", "deploy"}, f.WorkflowNames)
+	assert.Nil(t, f.Statuses)
+
+	assert.True(t, f.Match(Event{EventType: "sdk.EventRunWorkflow", WorkflowName: "build"}))
+	assert.True(t, f.Match(Event{EventType: "sdk.EventRunWorkflowNodeJob", WorkflowName: "deploy"}))
+	assert.False(t, f.Match(Event{EventType: "sdk.EventRunWorkflowJob", WorkflowName: "build"}))
+	assert.False(t, f.Match(Event{EventType: "sdk.EventRunWorkflow", WorkflowName: "test"}))
+
+	f.Statuses = []string{StatusFail}
+	assert.True(t, f.Match(Event{EventType: "sdk.EventRunWorkflow", WorkflowName: "build", Status: StatusFail}))
+	assert.False(t, f.Match(Event{EventType: "sdk.EventRunWorkflow", WorkflowName: "build", Status: StatusSuccess}))
+
+	assert.True(t, EventIntegrationFilter{}.Match(Event{EventType: "sdk.EventProjectAdd"}))
+}
@@ -0,0 +1,287 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	json "encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// JobMatrixMaxVariants limits the number of job runs created for a matrix
+const JobMatrixMaxVariants = 64
+
+var jobMatrixKeyPattern = regexp.MustCompile("^[a-zA-Z0-9._-]+$")
+
+// JobMatrix describes the variants of a job, the job is run once per variant.
+// The variables os-architecture and model set the corresponding requirement of each variant.
+type JobMatrix struct {
+	Variables   map[string][]string `json:"variables,omitempty" yaml:"variables,omitempty" jsonschema_description:"Values of each variable, the job is run for each combination."`
+	Include     []JobMatrixVariant  `json:"include,omitempty" yaml:"include,omitempty" jsonschema_description:"Additional variants."`
+	Exclude     []JobMatrixVariant  `json:"exclude,omitempty" yaml:"exclude,omitempty" jsonschema_description:"Combinations to remove, a variant is excluded if it matches all given variables."`
+	FailFast    bool                `json:"fail_fast,omitempty" yaml:"fail_fast,omitempty" jsonschema_description:"Stop the other variants when one fails."`
+	MaxParallel int                 `json:"max_parallel,omitempty" yaml:"max_parallel,omitempty" jsonschema_description:"Maximum number of variants run at the same time, no limit by default."`
+}
+
+// Value returns driver.Value from job matrix.
+func (m JobMatrix) Value() (driver.Value, error) {
+	j, err := json.Marshal(m)
+	return j, WrapError(err, "cannot marshal JobMatrix")
+}
+
+// Scan job matrix.
+func (m *JobMatrix) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, m), "cannot unmarshal JobMatrix")
+}
+
+// IsValid returns an error if the matrix is not valid.
+func (m JobMatrix) IsValid() error {
+	for k, values := range m.Variables {
+		if !jobMatrixKeyPattern.MatchString(k) {
+			return NewErrorFrom(ErrWrongRequest, "invalid matrix variable name %q", k)
+		}
+		if len(values) == 0 {
+			return NewErrorFrom(ErrWrongRequest, "matrix variable %s has no value", k)
+		}
+	}
+	for _, v := range append(append([]JobMatrixVariant{}, m.Include...), m.Exclude...) {
+		for k := range v {
+			if !jobMatrixKeyPattern.MatchString(k) {
+				return NewErrorFrom(ErrWrongRequest, "invalid matrix variable name %q", k)
+			}
+		}
+	}
+	if m.MaxParallel < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid matrix max_parallel %d", m.MaxParallel)
+	}
+	// Check the size of the matrix before computing its variants
+	if m.combinations() > JobMatrixMaxVariants {
+		return NewErrorFrom(ErrWrongRequest, "matrix has more than %d combinations, maximum is %d variants", JobMatrixMaxVariants, JobMatrixMaxVariants)
+	}
+	variants := m.Variants()
+	if len(variants) == 0 {
+		return NewErrorFrom(ErrWrongRequest, "matrix has no variant")
+	}
+	if len(variants) > JobMatrixMaxVariants {
+		return NewErrorFrom(ErrWrongRequest, "matrix has %d variants, maximum is %d", len(variants), JobMatrixMaxVariants)
+	}
+	return nil
+}
+
+// combinations returns the number of combinations of the matrix variables, computed from the number of values of each variable.
+// It stops counting once JobMatrixMaxVariants is exceeded.
+func (m JobMatrix) combinations() int {
+	if len(m.Variables) == 0 {
+		return 0
+	}
+	n := 1
+	for _, values := range m.Variables {
+		n *= len(values)
+		if n > JobMatrixMaxVariants {
+			return JobMatrixMaxVariants + 1
+		}
+	}
+	return n
+}
+
+// Variants returns all the combinations of the matrix variables without excluded ones, then included variants.
+// A matrix with more than JobMatrixMaxVariants combinations is not expanded and has no variant.
+func (m JobMatrix) Variants() []JobMatrixVariant {
+	if m.combinations() > JobMatrixMaxVariants {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m.Variables))
+	for k := range m.Variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var variants []JobMatrixVariant
+	if len(keys) > 0 {
+		variants = []JobMatrixVariant{{}}
+		for _, k := range keys {
+			next := make([]JobMatrixVariant, 0, len(variants)*len(m.Variables[k]))
+			for _, v := range variants {
+				for _, value := range m.Variables[k] {
+					nv := JobMatrixVariant{k: value}
+					for vk, vv := range v {
+						nv[vk] = vv
+					}
+					next = append(next, nv)
+				}
+			}
+			variants = next
+		}
+	}
+
+	res := make([]JobMatrixVariant, 0, len(variants)+len(m.Include))
+variants:
+	for _, v := range variants {
+		for _, e := range m.Exclude {
+			if v.Match(e) {
+				continue variants
+			}
+		}
+		res = append(res, v)
+	}
+	for _, i := range m.Include {
+		var exists bool
+		for _, v := range res {
+			if v.String() == i.String() {
+				exists = true
+				break
+			}
+		}
+		if !exists && len(i) > 0 {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// JobMatrixVariant contains the values of the matrix variables for one run of the job
+type JobMatrixVariant map[string]string
+
+// Match returns true if the variant contains all the given values.
+func (v JobMatrixVariant) Match(values JobMatrixVariant) bool {
+	for k, value := range values {
+		if v[k] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the variant as "key=value" pairs sorted by key.
+func (v JobMatrixVariant) String() string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + v[k]
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// Parameters returns the cds.matrix.* parameters of the variant.
+func (v JobMatrixVariant) Parameters() []Parameter {
+	var params []Parameter
+	for k, value := range v {
+		AddParameter(&params, "cds.matrix."+k, StringParameter, value)
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+// Requirements returns the job requirements where os-architecture and model are replaced by the values of the variant.
+func (v JobMatrixVariant) Requirements(reqs RequirementList) RequirementList {
+	res := make(RequirementList, 0, len(reqs)+2)
+	for _, r := range reqs {
+		if _, has := v[r.Type]; has && (r.Type == OSArchRequirement || r.Type == ModelRequirement) {
+			continue
+		}
+		res = append(res, r)
+	}
+	for _, t := range []string{OSArchRequirement, ModelRequirement} {
+		if value, has := v[t]; has {
+			res = append(res, Requirement{Name: value, Type: t, Value: value})
+		}
+	}
+	return res
+}
+
+// WorkflowNodeJobRunMatrix identifies the variant run by a job run
+type WorkflowNodeJobRunMatrix struct {
+	Index   int              `json:"index"`
+	Total   int              `json:"total"`
+	Variant JobMatrixVariant `json:"variant"`
+}
+
+// Value returns driver.Value from node job run matrix.
+func (m WorkflowNodeJobRunMatrix) Value() (driver.Value, error) {
+	j, err := json.Marshal(m)
+	return j, WrapError(err, "cannot marshal WorkflowNodeJobRunMatrix")
+}
+
+// Scan node job run matrix.
+func (m *WorkflowNodeJobRunMatrix) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, m), "cannot unmarshal WorkflowNodeJobRunMatrix")
+}
+
+// WorkflowNodeJobRunGroup contains the job runs of a job, one per variant for a matrix job
+type WorkflowNodeJobRunGroup struct {
+	Name    string               `json:"name"`
+	Status  string               `json:"status"`
+	Total   int                  `json:"total"`
+	RunJobs []WorkflowNodeJobRun `json:"run_jobs"`
+}
+
+// GroupRunJobsByJob groups the job runs of a stage by job, in the order of the stage.
+// The status of a group is the status of its worst job run, or Building while variants are not all created.
+func GroupRunJobsByJob(runJobs []WorkflowNodeJobRun) []WorkflowNodeJobRunGroup {
+	var groups []WorkflowNodeJobRunGroup
+	index := map[int64]int{}
+	for _, rj := range runJobs {
+		id := rj.Job.PipelineActionID
+		i, has := index[id]
+		if !has || rj.Matrix == nil {
+			groups = append(groups, WorkflowNodeJobRunGroup{Name: rj.Job.Action.Name, Total: 1})
+			i = len(groups) - 1
+			if rj.Matrix != nil {
+				index[id] = i
+				groups[i].Total = rj.Matrix.Total
+			}
+		}
+		groups[i].RunJobs = append(groups[i].RunJobs, rj)
+	}
+
+	for i := range groups {
+		g := &groups[i]
+		var counter = map[string]int{}
+		for _, rj := range g.RunJobs {
+			counter[rj.Status]++
+		}
+		switch {
+		case counter[StatusFail] > 0:
+			g.Status = StatusFail
+		case counter[StatusBuilding] > 0 || counter[StatusWaiting] > 0:
+			g.Status = StatusBuilding
+		case counter[StatusStopped] > 0:
+			g.Status = StatusStopped
+		case len(g.RunJobs) < g.Total:
+			g.Status = StatusBuilding
+		case counter[StatusSuccess] > 0:
+			g.Status = StatusSuccess
+		default:
+			g.Status = g.RunJobs[0].Status
+		}
+	}
+	return groups
+}
+
+// Name returns the name of the job run, with its matrix variant.
+func (njr WorkflowNodeJobRun) Name() string {
+	if njr.Matrix == nil {
+		return njr.Job.Action.Name
+	}
+	return fmt.Sprintf("%s (%s)", njr.Job.Action.Name, njr.Matrix.Variant.String())
+}
@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobMatrix_Variants(t *testing.T) {
+	m := JobMatrix{
+		Variables: map[string][]string{
+			"go": {"1.13", "1.14"},
+			"db": {"pg", "mysql"},
+		},
+		Exclude: []JobMatrixVariant{{"go": "1.13", "db": "mysql"}},
+		Include: []JobMatrixVariant{{"go": "1.15", "db": "pg"}, {"go": "1.14", "db": "pg"}},
+	}
+
+	variants := m.Variants()
+	res := make([]string, len(variants))
+	for i := range variants {
+		res[i] = variants[i].String()
+	}
+	assert.Equal(t, []string{
+		"db=pg, go=1.13",
+		"db=pg, go=1.14",
+		"db=mysql, go=1.14",
+		"db=pg, go=1.15",
+	}, res)
+}
+
+func TestJobMatrix_IsValid(t *testing.T) {
+	assert.NoError(t, JobMatrix{Variables: map[string][]string{"go": {"1.13"}}}.IsValid())
+	assert.NoError(t, JobMatrix{Include: []JobMatrixVariant{{"os-architecture": "linux/arm64"}}}.IsValid())
+	assert.Error(t, JobMatrix{}.IsValid())
+	assert.Error(t, JobMatrix{Variables: map[string][]string{"go": {}}}.IsValid())
+	assert.Error(t, JobMatrix{Variables: map[string][]string{"go version": {"1.13"}}}.IsValid())
+	assert.Error(t, JobMatrix{Variables: map[string][]string{"go": {"1.13"}}, MaxParallel: -1}.IsValid())
+
+	big := JobMatrix{Variables: map[string][]string{
+		"a": {"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		"b": {"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+	}}
+	assert.Error(t, big.IsValid())
+	assert.Empty(t, big.Variants())
+
+	// The number of combinations is checked before computing them
+	huge := JobMatrix{Variables: map[string][]string{}}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		huge.Variables[k] = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	}
+	assert.Error(t, huge.IsValid())
+	assert.Empty(t, huge.Variants())
+}
+
+func TestJobMatrixVariant_Requirements(t *testing.T) {
+	v := JobMatrixVariant{"go": "1.14", OSArchRequirement: "linux/arm64"}
+	reqs := v.Requirements(RequirementList{
+		{Name: "git", Type: BinaryRequirement, Value: "git"},
+		{Name: "linux/amd64", Type: OSArchRequirement, Value: "linux/amd64"},
+	})
+	assert.Equal(t, RequirementList{
+		{Name: "git", Type: BinaryRequirement, Value: "git"},
+		{Name: "linux/arm64", Type: OSArchRequirement, Value: "linux/arm64"},
+	}, reqs)
+
+	params := v.Parameters()
+	require.Len(t, params, 2)
+	assert.Equal(t, "cds.matrix.go", params[0].Name)
+	assert.Equal(t, "1.14", params[0].Value)
+	assert.Equal(t, "cds.matrix.os-architecture", params[1].Name)
+}
+
+func TestGroupRunJobsByJob(t *testing.T) {
+	unit := ExecutedJob{Job: Job{PipelineActionID: 1, Action: Action{Name: "unit"}}}
+	lint := ExecutedJob{Job: Job{PipelineActionID: 2, Action: Action{Name: "lint"}}}
+	groups := GroupRunJobsByJob([]WorkflowNodeJobRun{
+		{Job: unit, Status: StatusSuccess, Matrix: &WorkflowNodeJobRunMatrix{Index: 0, Total: 3, Variant: JobMatrixVariant{"go": "1.13"}}},
+		{Job: lint, Status: StatusSuccess},
+		{Job: unit, Status: StatusSuccess, Matrix: &WorkflowNodeJobRunMatrix{Index: 1, Total: 3, Variant: JobMatrixVariant{"go": "1.14"}}},
+	})
+	require.Len(t, groups, 2)
+	assert.Equal(t, "unit", groups[0].Name)
+	assert.Len(t, groups[0].RunJobs, 2)
+	assert.Equal(t, StatusBuilding, groups[0].Status)
+	assert.Equal(t, "unit (go=1.14)", groups[0].RunJobs[1].Name())
+	assert.Equal(t, "lint", groups[1].Name)
+	assert.Equal(t, StatusSuccess, groups[1].Status)
+}
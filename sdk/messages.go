@@ -73,12 +73,18 @@ var (
 	MsgSpawnInfoWorkerForJob               = &Message{"MsgSpawnInfoWorkerForJob", trad{FR: "Ce worker %s a été créé pour lancer ce job", EN: "This worker %s was created to take this action"}, nil}
 	MsgSpawnInfoWorkerForJobError          = &Message{"MsgSpawnInfoWorkerForJobError", trad{FR: "⚠ Ce worker %s a été créé pour lancer ce job, mais ne possède pas tous les pré-requis. Vérifiez que les prérequis suivants:%s", EN: "⚠ This worker %s was created to take this action, but does not have all prerequisites. Please verify the following prerequisites:%s"}, nil}
 	MsgSpawnInfoJobError                   = &Message{"MsgSpawnInfoJobError", trad{FR: "⚠ Impossible de lancer ce job : %s", EN: "⚠ Unable to run this job: %s"}, nil}
+	MsgSpawnInfoJobRetry                   = &Message{"MsgSpawnInfoJobRetry", trad{FR: "⟳ Le job a échoué (%s), tentative %d/%d dans %s", EN: "⟳ Job failed (%s), attempt %d/%d in %s"}, nil}
+	MsgSpawnInfoJobMatrixFailFast          = &Message{"MsgSpawnInfoJobMatrixFailFast", trad{FR: "⚠ Le job a été arrêté car la variante %s de la matrice a échoué", EN: "⚠ Job stopped because the matrix variant %s failed"}, nil}
+	MsgSpawnInfoJobTimeout                 = &Message{"MsgSpawnInfoJobTimeout", trad{FR: "⚠ Le job a dépassé son timeout de %s sans résultat du worker", EN: "⚠ Job exceeded its timeout of %s without result from the worker"}, nil}
 	MsgWorkflowStarting                    = &Message{"MsgWorkflowStarting", trad{FR: "Le workflow %s#%s a été démarré", EN: "Workflow %s#%s has been started"}, nil}
 	MsgWorkflowError                       = &Message{"MsgWorkflowError", trad{FR: "⚠ Une erreur est survenue: %v", EN: "⚠ An error has occurred: %v"}, nil}
 	MsgWorkflowConditionError              = &Message{"MsgWorkflowConditionError", trad{FR: "Les conditions de lancement ne sont pas respectées.", EN: "Run conditions aren't ok."}, nil}
 	MsgWorkflowNodeStop                    = &Message{"MsgWorkflowNodeStop", trad{FR: "Le pipeline a été arrété par %s", EN: "The pipeline has been stopped by %s"}, nil}
 	MsgWorkflowNodeMutex                   = &Message{"MsgWorkflowNodeMutex", trad{FR: "Le pipeline %s est mis en attente tant qu'il est en cours sur un autre run", EN: "The pipeline %s is waiting while it's running on another run"}, nil}
 	MsgWorkflowNodeMutexRelease            = &Message{"MsgWorkflowNodeMutexRelease", trad{FR: "Lancement du pipeline %s", EN: "Triggering pipeline %s"}, nil}
+	MsgWorkflowNodeConcurrencyLocked       = &Message{"MsgWorkflowNodeConcurrencyLocked", trad{FR: "Le pipeline %s attend le groupe de concurrence %s détenu par %s#%d (pipeline %s)", EN: "The pipeline %s is waiting for the concurrency group %s held by %s#%d (pipeline %s)"}, nil}
+	MsgWorkflowNodeConcurrencyRelease      = &Message{"MsgWorkflowNodeConcurrencyRelease", trad{FR: "Le groupe de concurrence %s a été libéré, lancement du pipeline %s", EN: "The concurrency group %s has been released, triggering pipeline %s"}, nil}
+	MsgWorkflowNodeConcurrencyCancelled    = &Message{"MsgWorkflowNodeConcurrencyCancelled", trad{FR: "Le pipeline %s a été annulé par un run plus récent en attente du groupe de concurrence %s", EN: "The pipeline %s has been cancelled by a newer run waiting for the concurrency group %s"}, nil}
 	MsgWorkflowImportedUpdated             = &Message{"MsgWorkflowImportedUpdated", trad{FR: "Le workflow %s a été mis à jour", EN: "Workflow %s has been updated"}, nil}
 	MsgWorkflowImportedInserted            = &Message{"MsgWorkflowImportedInserted", trad{FR: "Le workflow %s a été créé", EN: "Workflow %s has been created"}, nil}
 	MsgSpawnInfoHatcheryCannotStartJob     = &Message{"MsgSpawnInfoHatcheryCannotStart", trad{FR: "Aucune hatchery n'a pu démarrer de worker respectant vos pré-requis de job, merci de les vérifier.", EN: "No hatchery can spawn a worker corresponding your job's requirements. Please check your job's requirements."}, nil}
@@ -149,12 +155,18 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
 	MsgSpawnInfoWorkerForJobError.ID:          MsgSpawnInfoWorkerForJobError,
 	MsgSpawnInfoJobError.ID:                   MsgSpawnInfoJobError,
+	MsgSpawnInfoJobRetry.ID:                   MsgSpawnInfoJobRetry,
+	MsgSpawnInfoJobMatrixFailFast.ID:          MsgSpawnInfoJobMatrixFailFast,
+	MsgSpawnInfoJobTimeout.ID:                 MsgSpawnInfoJobTimeout,
 	MsgWorkflowStarting.ID:                    MsgWorkflowStarting,
 	MsgWorkflowError.ID:                       MsgWorkflowError,
 	MsgWorkflowConditionError.ID:              MsgWorkflowConditionError,
 	MsgWorkflowNodeStop.ID:                    MsgWorkflowNodeStop,
 	MsgWorkflowNodeMutex.ID:                   MsgWorkflowNodeMutex,
 	MsgWorkflowNodeMutexRelease.ID:            MsgWorkflowNodeMutexRelease,
+	MsgWorkflowNodeConcurrencyLocked.ID:       MsgWorkflowNodeConcurrencyLocked,
+	MsgWorkflowNodeConcurrencyRelease.ID:      MsgWorkflowNodeConcurrencyRelease,
+	MsgWorkflowNodeConcurrencyCancelled.ID:    MsgWorkflowNodeConcurrencyCancelled,
 	MsgWorkflowImportedUpdated.ID:             MsgWorkflowImportedUpdated,
 	MsgWorkflowImportedInserted.ID:            MsgWorkflowImportedInserted,
 	MsgSpawnInfoHatcheryCannotStartJob.ID:     MsgSpawnInfoHatcheryCannotStartJob,
@@ -50,6 +50,8 @@ type UserNotificationTemplate struct {
 	Body    string `json:"body,omitempty" yaml:"body,omitempty"`
 	// For VCS
 	DisableComment *bool `json:"disable_comment,omitempty" yaml:"disable_comment,omitempty"`
+	// For VCS, post and update a single summary comment for the whole workflow run instead of one comment per node run
+	SummaryComment *bool `json:"summary_comment,omitempty" yaml:"summary_comment,omitempty"`
 }
 
 //userNotificationInput is a way to parse notification
@@ -95,11 +95,22 @@ type VCSPullRequest struct {
 	Closed bool         `json:"closed"`
 }
 
+//VCSPullRequestComment represents a comment on a pull request
+type VCSPullRequestComment struct {
+	ID      int64     `json:"id"`
+	User    VCSAuthor `json:"user"`
+	Body    string    `json:"body"`
+	Version int64     `json:"version,omitempty"` // Needed by Bitbucket to update a comment
+	// PostedByCDS is true when the comment was posted with the account used by CDS to comment pull requests
+	PostedByCDS bool `json:"posted_by_cds"`
+}
+
 //VCSPushEvent represents a push events for polling
 type VCSPushEvent struct {
 	Repo     string    `json:"repo"`
 	Branch   VCSBranch `json:"branch"`
 	Commit   VCSCommit `json:"commit"`
+	Before   string    `json:"before,omitempty"`
 	CloneURL string    `json:"clone_url"`
 }
 
@@ -134,6 +145,7 @@ type VCSHook struct {
 	Disable     bool     `json:"disable"`
 	InsecureSSL bool     `json:"insecure_ssl"`
 	Workflow    bool     `json:"workflow"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 // VCSCommitStatus represents a status on a VCS repository
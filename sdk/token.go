@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql/driver"
 	json "encoding/json"
+	"net/http"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -28,6 +29,30 @@ type AuthDriverWithSigninStateToken interface {
 	CheckSigninStateToken(AuthConsumerSigninRequest) error
 }
 
+// AuthDriverWithGroupsSync is implemented by drivers that give the CDS groups of the users.
+// The membership of the managed groups is synchronized at each signin with the groups of the user info.
+type AuthDriverWithGroupsSync interface {
+	AuthDriver
+	GetManagedGroups() []string
+}
+
+// AuthDriverWithDeviceCode is implemented by drivers that support the device authorization flow,
+// the device code is then given in the signin request until the user completes the authorization.
+type AuthDriverWithDeviceCode interface {
+	AuthDriver
+	GetDeviceCode(context.Context) (AuthDriverDeviceCode, error)
+}
+
+// AuthDriverDeviceCode is returned to a client that starts a device authorization flow.
+type AuthDriverDeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
 type AuthDriverSigningRedirect struct {
 	Method      string            `json:"method"`
 	URL         string            `json:"url"`
@@ -64,6 +89,7 @@ func (a AuthDriverManifests) ExistsConsumerType(consumerType AuthConsumerType) b
 type AuthDriverManifest struct {
 	Type           AuthConsumerType `json:"type"`
 	SignupDisabled bool             `json:"signup_disabled"`
+	DeviceCode     bool             `json:"device_code,omitempty"`
 }
 
 // AuthConsumerScope alias type for string.
@@ -161,6 +187,7 @@ type AuthDriverUserInfo struct {
 	Fullname   string
 	Email      string
 	MFA        bool
+	Groups     []string
 }
 
 // AuthCurrentConsumerResponse describe the current consumer and the current session
@@ -180,6 +207,7 @@ const (
 	ConsumerCorporateSSO AuthConsumerType = "corporate-sso"
 	ConsumerGithub       AuthConsumerType = "github"
 	ConsumerGitlab       AuthConsumerType = "gitlab"
+	ConsumerOIDC         AuthConsumerType = "oidc"
 	ConsumerTest         AuthConsumerType = "futurama"
 	ConsumerTest2        AuthConsumerType = "planet-express"
 )
@@ -196,7 +224,7 @@ func (t AuthConsumerType) IsValid() bool {
 // IsValidExternal returns validity of given auth consumer type.
 func (t AuthConsumerType) IsValidExternal() bool {
 	switch t {
-	case ConsumerLDAP, ConsumerCorporateSSO, ConsumerGithub, ConsumerGitlab, ConsumerTest, ConsumerTest2:
+	case ConsumerLDAP, ConsumerCorporateSSO, ConsumerGithub, ConsumerGitlab, ConsumerOIDC, ConsumerTest, ConsumerTest2:
 		return true
 	}
 	return false
@@ -228,6 +256,7 @@ const (
 	WarningGroupInvalid     AuthConsumerWarningType = "group-invalid"
 	WarningGroupRemoved     AuthConsumerWarningType = "group-removed"
 	WarningLastGroupRemoved AuthConsumerWarningType = "last-group-removed"
+	WarningExpireSoon       AuthConsumerWarningType = "expire-soon"
 )
 
 // AuthConsumerWarnings contains specific information from the auth driver.
@@ -256,11 +285,20 @@ func NewConsumerWarningLastGroupRemoved() AuthConsumerWarning {
 	return AuthConsumerWarning{Type: WarningLastGroupRemoved}
 }
 
+// NewConsumerWarningExpireSoon returns a new warning for given expiration date.
+func NewConsumerWarningExpireSoon(expireAt time.Time) AuthConsumerWarning {
+	return AuthConsumerWarning{
+		Type:     WarningExpireSoon,
+		ExpireAt: &expireAt,
+	}
+}
+
 // AuthConsumerWarning contains info about a warning.
 type AuthConsumerWarning struct {
 	Type      AuthConsumerWarningType `json:"type"`
 	GroupID   int64                   `json:"group_id,omitempty"`
 	GroupName string                  `json:"group_name,omitempty"`
+	ExpireAt  *time.Time              `json:"expire_at,omitempty"`
 }
 
 // Scan consumer data.
@@ -283,20 +321,23 @@ type AuthConsumers []AuthConsumer
 
 // AuthConsumer issues session linked to an authentified user.
 type AuthConsumer struct {
-	ID                 string                 `json:"id" cli:"id,key" db:"id"`
-	Name               string                 `json:"name" cli:"name" db:"name"`
-	Description        string                 `json:"description" cli:"description" db:"description"`
-	ParentID           *string                `json:"parent_id,omitempty" db:"parent_id"`
-	AuthentifiedUserID string                 `json:"user_id,omitempty" db:"user_id"`
-	Type               AuthConsumerType       `json:"type" cli:"type" db:"type"`
-	Data               AuthConsumerData       `json:"-" db:"data"` // NEVER returns auth consumer data in json, TODO this fields should be visible only in auth package
-	Created            time.Time              `json:"created" cli:"created" db:"created"`
-	GroupIDs           Int64Slice             `json:"group_ids,omitempty" cli:"group_ids" db:"group_ids"`
-	InvalidGroupIDs    Int64Slice             `json:"invalid_group_ids,omitempty" db:"invalid_group_ids"`
-	Scopes             AuthConsumerScopeSlice `json:"scopes,omitempty" cli:"scopes" db:"scopes"`
-	IssuedAt           time.Time              `json:"issued_at" cli:"issued_at" db:"issued_at"`
-	Disabled           bool                   `json:"disabled" cli:"disabled" db:"disabled"`
-	Warnings           AuthConsumerWarnings   `json:"warnings,omitempty" db:"warnings"`
+	ID                 string                   `json:"id" cli:"id,key" db:"id"`
+	Name               string                   `json:"name" cli:"name" db:"name"`
+	Description        string                   `json:"description" cli:"description" db:"description"`
+	ParentID           *string                  `json:"parent_id,omitempty" db:"parent_id"`
+	AuthentifiedUserID string                   `json:"user_id,omitempty" db:"user_id"`
+	Type               AuthConsumerType         `json:"type" cli:"type" db:"type"`
+	Data               AuthConsumerData         `json:"-" db:"data"` // NEVER returns auth consumer data in json, TODO this fields should be visible only in auth package
+	Created            time.Time                `json:"created" cli:"created" db:"created"`
+	GroupIDs           Int64Slice               `json:"group_ids,omitempty" cli:"group_ids" db:"group_ids"`
+	InvalidGroupIDs    Int64Slice               `json:"invalid_group_ids,omitempty" db:"invalid_group_ids"`
+	Scopes             AuthConsumerScopeSlice   `json:"scopes,omitempty" cli:"scopes" db:"scopes"`
+	IssuedAt           time.Time                `json:"issued_at" cli:"issued_at" db:"issued_at"`
+	Disabled           bool                     `json:"disabled" cli:"disabled" db:"disabled"`
+	Warnings           AuthConsumerWarnings     `json:"warnings,omitempty" db:"warnings"`
+	Restrictions       AuthConsumerRestrictions `json:"restrictions" db:"restrictions"`
+	ExpireAt           *time.Time               `json:"expire_at,omitempty" cli:"expire_at" db:"expire_at"`
+	LastUsed           *time.Time               `json:"last_used,omitempty" cli:"last_used" db:"last_used"`
 	// aggregates
 	AuthentifiedUser *AuthentifiedUser `json:"user,omitempty" db:"-"`
 	Groups           Groups            `json:"groups,omitempty" db:"-"`
@@ -309,9 +350,155 @@ func (c AuthConsumer) IsValid() error {
 			return NewErrorFrom(ErrWrongRequest, "invalid given scope value %s", s)
 		}
 	}
+	if c.ExpireAt != nil && c.ExpireAt.Before(time.Now()) {
+		return NewErrorFrom(ErrWrongRequest, "invalid given expiration date %s", c.ExpireAt.Format(time.RFC3339))
+	}
+	return c.Restrictions.IsValid()
+}
+
+// IsExpired returns true if the consumer has an expiration date in the past.
+func (c AuthConsumer) IsExpired() bool {
+	return c.ExpireAt != nil && c.ExpireAt.Before(time.Now())
+}
+
+// AuthConsumerRestrictions limits the access of a consumer to some projects, workflows and http methods.
+// An empty list means no restriction.
+type AuthConsumerRestrictions struct {
+	ProjectKeys []string                          `json:"project_keys,omitempty"`
+	Workflows   []AuthConsumerRestrictionWorkflow `json:"workflows,omitempty"`
+	Methods     []string                          `json:"methods,omitempty"`
+}
+
+// AuthConsumerRestrictionWorkflow is a workflow allowed for a restricted consumer.
+type AuthConsumerRestrictionWorkflow struct {
+	ProjectKey   string `json:"project_key"`
+	WorkflowName string `json:"workflow_name"`
+}
+
+// AuthConsumerReadOnlyMethods are the http methods allowed for a read only consumer.
+var AuthConsumerReadOnlyMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+
+// IsValid returns validity for auth consumer restrictions.
+func (r AuthConsumerRestrictions) IsValid() error {
+	for _, k := range r.ProjectKeys {
+		if k == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid given empty project key restriction")
+		}
+	}
+	for _, w := range r.Workflows {
+		if w.ProjectKey == "" || w.WorkflowName == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid given workflow restriction, project key and workflow name are mandatory")
+		}
+	}
+	for _, m := range r.Methods {
+		switch m {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return NewErrorFrom(ErrWrongRequest, "invalid given http method restriction %s", m)
+		}
+	}
 	return nil
 }
 
+// HasProjectRestriction returns true if the consumer can only access some projects or workflows.
+func (r AuthConsumerRestrictions) HasProjectRestriction() bool {
+	return len(r.ProjectKeys) > 0 || len(r.Workflows) > 0
+}
+
+// IsReadOnly returns true if only read http methods are allowed.
+func (r AuthConsumerRestrictions) IsReadOnly() bool {
+	if len(r.Methods) == 0 {
+		return false
+	}
+	for _, m := range r.Methods {
+		if !IsInArray(m, AuthConsumerReadOnlyMethods) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowMethod returns true if given http method is allowed.
+func (r AuthConsumerRestrictions) AllowMethod(method string) bool {
+	return len(r.Methods) == 0 || IsInArray(method, r.Methods)
+}
+
+// AllowProject returns true if all the project is allowed.
+func (r AuthConsumerRestrictions) AllowProject(projectKey string) bool {
+	return !r.HasProjectRestriction() || IsInArray(projectKey, r.ProjectKeys)
+}
+
+// AllowWorkflow returns true if given workflow is allowed, directly or with its project.
+func (r AuthConsumerRestrictions) AllowWorkflow(projectKey, workflowName string) bool {
+	if r.AllowProject(projectKey) {
+		return true
+	}
+	for _, w := range r.Workflows {
+		if w.ProjectKey == projectKey && w.WorkflowName == workflowName {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWorkflowInProject returns true if at least one workflow of given project is allowed.
+func (r AuthConsumerRestrictions) HasWorkflowInProject(projectKey string) bool {
+	for _, w := range r.Workflows {
+		if w.ProjectKey == projectKey {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSubsetOf returns true if the restrictions don't give more access than the parent ones.
+func (r AuthConsumerRestrictions) IsSubsetOf(parent AuthConsumerRestrictions) bool {
+	if parent.HasProjectRestriction() {
+		if !r.HasProjectRestriction() {
+			return false
+		}
+		for _, k := range r.ProjectKeys {
+			if !parent.AllowProject(k) {
+				return false
+			}
+		}
+		for _, w := range r.Workflows {
+			if !parent.AllowWorkflow(w.ProjectKey, w.WorkflowName) {
+				return false
+			}
+		}
+	}
+	if len(parent.Methods) > 0 {
+		if len(r.Methods) == 0 {
+			return false
+		}
+		for _, m := range r.Methods {
+			if !parent.AllowMethod(m) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Scan consumer restrictions.
+func (r *AuthConsumerRestrictions) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, r), "cannot unmarshal AuthConsumerRestrictions")
+}
+
+// Value returns driver.Value from consumer restrictions.
+func (r AuthConsumerRestrictions) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	return j, WrapError(err, "cannot marshal AuthConsumerRestrictions")
+}
+
 // GetGroupIDs returns group ids for auth consumer, if empty
 // in consumer returns group ids from authentified user.
 func (c AuthConsumer) GetGroupIDs() []int64 {
@@ -0,0 +1,40 @@
+package sdk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthConsumerRestrictionsIsSubsetOf(t *testing.T) {
+	parent := AuthConsumerRestrictions{
+		ProjectKeys: []string{"PROJ1"},
+		Workflows:   []AuthConsumerRestrictionWorkflow{{ProjectKey: "PROJ2", WorkflowName: "build"}},
+	}
+
+	assert.True(t, AuthConsumerRestrictions{ProjectKeys: []string{"PROJ1"}}.IsSubsetOf(parent))
+	assert.True(t, AuthConsumerRestrictions{Workflows: []AuthConsumerRestrictionWorkflow{
+		{ProjectKey: "PROJ1", WorkflowName: "deploy"},
+		{ProjectKey: "PROJ2", WorkflowName: "build"},
+	}}.IsSubsetOf(parent))
+	assert.False(t, AuthConsumerRestrictions{}.IsSubsetOf(parent), "no restriction gives more access than parent")
+	assert.False(t, AuthConsumerRestrictions{ProjectKeys: []string{"PROJ2"}}.IsSubsetOf(parent))
+	assert.False(t, AuthConsumerRestrictions{Workflows: []AuthConsumerRestrictionWorkflow{
+		{ProjectKey: "PROJ2", WorkflowName: "deploy"},
+	}}.IsSubsetOf(parent))
+
+	readOnly := AuthConsumerRestrictions{Methods: AuthConsumerReadOnlyMethods}
+	assert.True(t, readOnly.IsReadOnly())
+	assert.True(t, AuthConsumerRestrictions{Methods: []string{http.MethodGet}}.IsSubsetOf(readOnly))
+	assert.False(t, AuthConsumerRestrictions{}.IsSubsetOf(readOnly))
+	assert.False(t, AuthConsumerRestrictions{Methods: []string{http.MethodPost}}.IsSubsetOf(readOnly))
+	assert.True(t, readOnly.IsSubsetOf(AuthConsumerRestrictions{}))
+}
+
+func TestAuthConsumerRestrictionsIsValid(t *testing.T) {
+	assert.NoError(t, AuthConsumerRestrictions{}.IsValid())
+	assert.NoError(t, AuthConsumerRestrictions{Methods: []string{http.MethodGet, http.MethodPost}}.IsValid())
+	assert.Error(t, AuthConsumerRestrictions{Methods: []string{"get"}}.IsValid())
+	assert.Error(t, AuthConsumerRestrictions{Workflows: []AuthConsumerRestrictionWorkflow{{ProjectKey: "PROJ"}}}.IsValid())
+}
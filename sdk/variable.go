@@ -47,6 +47,9 @@ const (
 	BooleanVariable    = "boolean"
 	NumberVariable     = "number"
 	RepositoryVariable = "repository"
+	// ExternalSecretVariable value is a reference to a secret stored in an external provider
+	// (ie. vault:secret/data/my-app#password), resolved by the API when a job is taken
+	ExternalSecretVariable = "external_secret"
 )
 
 var (
@@ -57,6 +60,7 @@ var (
 		StringVariable,
 		BooleanVariable,
 		NumberVariable,
+		ExternalSecretVariable,
 	}
 
 	BasicVariableNames = []string{
@@ -104,6 +108,40 @@ func NeedPlaceholder(t string) bool {
 	}
 }
 
+// ExternalSecretReference is the parsed value of an external secret variable.
+type ExternalSecretReference struct {
+	Provider string `json:"provider"`
+	Path     string `json:"path"`
+	Key      string `json:"key,omitempty"`
+}
+
+// String returns the reference as <provider>:<path>[#key].
+func (r ExternalSecretReference) String() string {
+	if r.Key == "" {
+		return r.Provider + ":" + r.Path
+	}
+	return r.Provider + ":" + r.Path + "#" + r.Key
+}
+
+// ParseExternalSecretReference parses an external secret variable value: <provider>:<path>[#key].
+func ParseExternalSecretReference(value string) (ExternalSecretReference, error) {
+	var r ExternalSecretReference
+	i := strings.Index(value, ":")
+	if i <= 0 || i == len(value)-1 {
+		return r, NewErrorFrom(ErrInvalidExternalSecret, "invalid external secret reference %q", value)
+	}
+	r.Provider = value[:i]
+	r.Path = value[i+1:]
+	if j := strings.LastIndex(r.Path, "#"); j >= 0 {
+		r.Key = r.Path[j+1:]
+		r.Path = r.Path[:j]
+	}
+	if r.Path == "" {
+		return r, NewErrorFrom(ErrInvalidExternalSecret, "invalid external secret reference %q", value)
+	}
+	return r, nil
+}
+
 // VariableFind return a variable given its name if it exists in array
 func VariableFind(vars []Variable, s string) *Variable {
 	for _, v := range vars {
@@ -58,3 +58,37 @@ func Test_EnvVartoENV(t *testing.T) {
 		})
 	}
 }
+
+func TestParseExternalSecretReference(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    sdk.ExternalSecretReference
+		wantErr bool
+	}{
+		{value: "vault:secret/data/my-app#password", want: sdk.ExternalSecretReference{Provider: "vault", Path: "secret/data/my-app", Key: "password"}},
+		{value: "file:my-app/token", want: sdk.ExternalSecretReference{Provider: "file", Path: "my-app/token"}},
+		{value: "my-app/token", wantErr: true},
+		{value: "vault:", wantErr: true},
+		{value: "vault:#password", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := sdk.ParseExternalSecretReference(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("sdk.ParseExternalSecretReference() should return an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdk.ParseExternalSecretReference() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sdk.ParseExternalSecretReference() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.value {
+				t.Errorf("String() = %s, want %s", got.String(), tt.value)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ type VCSConfiguration struct {
 	Password string `json:"password"`
 	URL      string `json:"url"`
 	SSHPort  int    `json:"sshport"`
+	Checks   bool   `json:"checks,omitempty"`
 }
 
 // VCSServer is an interce for a OAuth VCS Server. The goal of this interface is to return a VCSAuthorizedClient
@@ -57,11 +58,14 @@ type VCSAuthorizedClient interface {
 	Commits(ctx context.Context, repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(ctx context.Context, repo, hash string) (VCSCommit, error)
 	CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]VCSCommit, error)
+	ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error)
 
 	// PullRequests
 	PullRequest(context.Context, string, int) (VCSPullRequest, error)
 	PullRequests(context.Context, string) ([]VCSPullRequest, error)
 	PullRequestComment(context.Context, string, int, string) error
+	PullRequestComments(ctx context.Context, repo string, id int) ([]VCSPullRequestComment, error)
+	PullRequestCommentEdit(ctx context.Context, repo string, id int, comment VCSPullRequestComment) error
 	PullRequestCreate(context.Context, string, VCSPullRequest) (VCSPullRequest, error)
 
 	//Hooks
@@ -114,3 +118,18 @@ func VCSCommitStatusDescription(projKey, workflowName string, evt EventRunWorkfl
 	)
 	return fmt.Sprintf("CDS/%s", key)
 }
+
+// Levels of a VCS check annotation
+const (
+	VCSCheckAnnotationNotice  = "notice"
+	VCSCheckAnnotationWarning = "warning"
+	VCSCheckAnnotationFailure = "failure"
+)
+
+// VCSCheckAnnotation is a line reported on a commit check, for a failed test or a vulnerability
+type VCSCheckAnnotation struct {
+	Path    string `json:"path"`
+	Level   string `json:"level"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
@@ -34,6 +34,7 @@ type Workflow struct {
 	Usage                   *Usage                       `json:"usage,omitempty" db:"-" cli:"-"`
 	HistoryLength           int64                        `json:"history_length" db:"history_length" cli:"-"`
 	PurgeTags               []string                     `json:"purge_tags,omitempty" db:"-" cli:"-"`
+	RetentionPolicy         *WorkflowRetentionPolicy     `json:"retention_policy,omitempty" db:"-" cli:"-"`
 	Notifications           []WorkflowNotification       `json:"notifications,omitempty" db:"-" cli:"-"`
 	FromRepository          string                       `json:"from_repository,omitempty" db:"from_repository" cli:"from"`
 	DerivedFromWorkflowID   int64                        `json:"derived_from_workflow_id,omitempty" db:"derived_from_workflow_id" cli:"-"`
@@ -235,11 +236,15 @@ func (w *WorkflowNodeConditions) Scan(src interface{}) error {
 	return WrapError(json.Unmarshal(source, w), "cannot unmarshal WorkflowNodeConditions")
 }
 
-//WorkflowNodeCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex
+//WorkflowNodeCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex, in, contains...
+//Type forces the comparison type (number, semver, date, duration or string), it is guessed from values if empty.
+//Operators and/or combine nested conditions.
 type WorkflowNodeCondition struct {
-	Variable string `json:"variable" yaml:"variable"`
-	Operator string `json:"operator" yaml:"operator"`
-	Value    string `json:"value" yaml:"value"`
+	Variable   string                  `json:"variable" yaml:"variable,omitempty"`
+	Operator   string                  `json:"operator" yaml:"operator"`
+	Value      string                  `json:"value" yaml:"value,omitempty"`
+	Type       string                  `json:"type,omitempty" yaml:"type,omitempty"`
+	Conditions []WorkflowNodeCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 }
 
 //WorkflowNodeContextDefaultPayloadVCS represents a default payload when a workflow is attached to a repository Webhook
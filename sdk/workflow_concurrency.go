@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Concurrency scopes and policies
+const (
+	ConcurrencyScopeProject     = "project"
+	ConcurrencyScopeEnvironment = "environment"
+
+	ConcurrencyPolicyQueue         = "queue"
+	ConcurrencyPolicyCancelPending = "cancel-pending"
+
+	ConcurrencyLockHolding = "holding"
+	ConcurrencyLockPending = "pending"
+)
+
+var concurrencyGroupRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,}$`)
+
+// NodeConcurrency declares a named concurrency group on a node: only one node run of all the workflows
+// of the project (or of the environment) sharing the group can be building at a time
+type NodeConcurrency struct {
+	Group  string `json:"group" yaml:"group" jsonschema_description:"Name of the concurrency group."`
+	Scope  string `json:"scope,omitempty" yaml:"scope,omitempty" jsonschema_description:"Scope of the group: project (default) or environment."`
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty" jsonschema_description:"What to do with runs waiting for the group: queue (default) or cancel-pending to keep only the newest one."`
+}
+
+// IsValid checks the group name, the scope and the policy
+func (c NodeConcurrency) IsValid() error {
+	if !concurrencyGroupRegexp.MatchString(c.Group) {
+		return NewErrorFrom(ErrWorkflowConcurrencyInvalid, "invalid concurrency group name %q, it should match %s", c.Group, concurrencyGroupRegexp.String())
+	}
+	switch c.Scope {
+	case "", ConcurrencyScopeProject, ConcurrencyScopeEnvironment:
+	default:
+		return NewErrorFrom(ErrWorkflowConcurrencyInvalid, "unknown concurrency scope %q", c.Scope)
+	}
+	switch c.Policy {
+	case "", ConcurrencyPolicyQueue, ConcurrencyPolicyCancelPending:
+	default:
+		return NewErrorFrom(ErrWorkflowConcurrencyInvalid, "unknown concurrency policy %q", c.Policy)
+	}
+	return nil
+}
+
+// Key returns the name of the lock shared by all the nodes of the group
+func (c NodeConcurrency) Key(projectKey, environmentName string) string {
+	if c.Scope == ConcurrencyScopeEnvironment {
+		return fmt.Sprintf("%s/env/%s/%s", projectKey, environmentName, c.Group)
+	}
+	return fmt.Sprintf("%s/%s", projectKey, c.Group)
+}
+
+// WorkflowNodeRunLock is a workflow node run holding or waiting for a concurrency group
+type WorkflowNodeRunLock struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	ProjectID         int64     `json:"project_id" db:"project_id" cli:"-"`
+	Key               string    `json:"key" db:"lock_key" cli:"key,key"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowName      string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id" cli:"-"`
+	Number            int64     `json:"num" db:"num" cli:"num"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	WorkflowNodeName  string    `json:"workflow_node_name" db:"workflow_node_name" cli:"pipeline"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+	// Holder is the node run holding the group awaited by a pending node run, it is only loaded with the locks of a workflow run
+	Holder *WorkflowNodeRunLock `json:"holder,omitempty" db:"-" cli:"-"`
+}
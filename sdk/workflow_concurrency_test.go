@@ -0,0 +1,21 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeConcurrency_IsValid(t *testing.T) {
+	assert.NoError(t, NodeConcurrency{Group: "deploy.prod_1"}.IsValid())
+	assert.NoError(t, NodeConcurrency{Group: "deploy", Scope: ConcurrencyScopeEnvironment, Policy: ConcurrencyPolicyCancelPending}.IsValid())
+	assert.Error(t, NodeConcurrency{}.IsValid())
+	assert.Error(t, NodeConcurrency{Group: "deploy prod"}.IsValid())
+	assert.Error(t, NodeConcurrency{Group: "deploy", Scope: "workflow"}.IsValid())
+	assert.Error(t, NodeConcurrency{Group: "deploy", Policy: "cancel-all"}.IsValid())
+}
+
+func TestNodeConcurrency_Key(t *testing.T) {
+	assert.Equal(t, "PROJ/deploy", NodeConcurrency{Group: "deploy"}.Key("PROJ", "production"))
+	assert.Equal(t, "PROJ/env/production/deploy", NodeConcurrency{Group: "deploy", Scope: ConcurrencyScopeEnvironment}.Key("PROJ", "production"))
+}
@@ -3,7 +3,11 @@ package sdk
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/blang/semver"
 
 	"github.com/ovh/cds/sdk/interpolate"
 )
@@ -17,6 +21,21 @@ const (
 	WorkflowConditionsOperatorGreaterThan        = "gt"
 	WorkflowConditionsOperatorGreaterOrEqualThan = "ge"
 	WorkflowConditionsOperatorRegex              = "regex"
+	WorkflowConditionsOperatorIn                 = "in"
+	WorkflowConditionsOperatorNotIn              = "notin"
+	WorkflowConditionsOperatorContains           = "contains"
+	WorkflowConditionsOperatorAnd                = "and"
+	WorkflowConditionsOperatorOr                 = "or"
+)
+
+// WorkflowData conditions value types used by comparison operators
+const (
+	WorkflowConditionsTypeAuto     = ""
+	WorkflowConditionsTypeString   = "string"
+	WorkflowConditionsTypeNumber   = "number"
+	WorkflowConditionsTypeSemver   = "semver"
+	WorkflowConditionsTypeDate     = "date"
+	WorkflowConditionsTypeDuration = "duration"
 )
 
 // WorkflowData conditions operator
@@ -29,9 +48,52 @@ var (
 		WorkflowConditionsOperatorGreaterThan:        ">",
 		WorkflowConditionsOperatorGreaterOrEqualThan: ">=",
 		WorkflowConditionsOperatorRegex:              "match",
+		WorkflowConditionsOperatorIn:                 "in",
+		WorkflowConditionsOperatorNotIn:              "not in",
+		WorkflowConditionsOperatorContains:           "contains",
+		WorkflowConditionsOperatorAnd:                "and",
+		WorkflowConditionsOperatorOr:                 "or",
 	}
+
+	WorkflowConditionsTypes = []string{
+		WorkflowConditionsTypeString,
+		WorkflowConditionsTypeNumber,
+		WorkflowConditionsTypeSemver,
+		WorkflowConditionsTypeDate,
+		WorkflowConditionsTypeDuration,
+	}
+
+	workflowConditionsDateLayouts = []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
 )
 
+// IsWorkflowConditionsGroupOperator returns true if given operator combines nested conditions.
+func IsWorkflowConditionsGroupOperator(operator string) bool {
+	return operator == WorkflowConditionsOperatorAnd || operator == WorkflowConditionsOperatorOr
+}
+
+// IsValid checks operators and types of the condition and its nested conditions.
+func (c WorkflowNodeCondition) IsValid() error {
+	if _, ok := WorkflowConditionsOperators[c.Operator]; !ok {
+		return NewErrorFrom(ErrWorkflowConditionBadOperator, "unknown operator %q", c.Operator)
+	}
+	if c.Type != WorkflowConditionsTypeAuto && !IsInArray(c.Type, WorkflowConditionsTypes) {
+		return NewErrorFrom(ErrWorkflowConditionBadOperator, "unknown type %q for variable %s", c.Type, c.Variable)
+	}
+	if IsWorkflowConditionsGroupOperator(c.Operator) {
+		if len(c.Conditions) == 0 {
+			return NewErrorFrom(ErrWorkflowConditionBadOperator, "operator %s needs at least one nested condition", c.Operator)
+		}
+		for _, sub := range c.Conditions {
+			if err := sub.IsValid(); err != nil {
+				return err
+			}
+		}
+	} else if len(c.Conditions) > 0 {
+		return NewErrorFrom(ErrWorkflowConditionBadOperator, "nested conditions are only allowed with operators %s and %s", WorkflowConditionsOperatorAnd, WorkflowConditionsOperatorOr)
+	}
+	return nil
+}
+
 //WorkflowCheckConditions checks conditions given a list of parameters
 func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parameter) (bool, error) {
 	if len(conditions) == 0 {
@@ -48,39 +110,199 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 
 	var conditionsOK = true
 	for _, cond := range conditions {
-		var err error
-		cond.Value, err = interpolate.Do(cond.Value, mapParams)
+		ok, err := checkCondition(cond, mapParams)
 		if err != nil {
-			return false, fmt.Errorf("Unable to interpolate %s (%v)", cond.Value, err)
+			return false, err
 		}
+		conditionsOK = conditionsOK && ok
+	}
 
-		switch cond.Operator {
-		case WorkflowConditionsOperatorEquals:
-			conditionsOK = conditionsOK && cond.Value == mapParams[cond.Variable]
+	return conditionsOK, nil
+}
 
-		case WorkflowConditionsOperatorNotEquals:
-			conditionsOK = conditionsOK && cond.Value != mapParams[cond.Variable]
+func checkCondition(cond WorkflowNodeCondition, mapParams map[string]string) (bool, error) {
+	if IsWorkflowConditionsGroupOperator(cond.Operator) {
+		for _, sub := range cond.Conditions {
+			ok, err := checkCondition(sub, mapParams)
+			if err != nil {
+				return false, err
+			}
+			if cond.Operator == WorkflowConditionsOperatorOr && ok {
+				return true, nil
+			}
+			if cond.Operator == WorkflowConditionsOperatorAnd && !ok {
+				return false, nil
+			}
+		}
+		return cond.Operator == WorkflowConditionsOperatorAnd, nil
+	}
 
-		case WorkflowConditionsOperatorLessThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) < 0
+	value, err := interpolate.Do(cond.Value, mapParams)
+	if err != nil {
+		return false, fmt.Errorf("Unable to interpolate %s (%v)", cond.Value, err)
+	}
+	variable := mapParams[cond.Variable]
 
-		case WorkflowConditionsOperatorLessOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) <= 0
+	switch cond.Operator {
+	case WorkflowConditionsOperatorEquals:
+		return value == variable, nil
 
-		case WorkflowConditionsOperatorGreaterThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) > 0
+	case WorkflowConditionsOperatorNotEquals:
+		return value != variable, nil
 
-		case WorkflowConditionsOperatorGreaterOrEqualThan:
-			conditionsOK = conditionsOK && strings.Compare(mapParams[cond.Variable], cond.Value) >= 0
+	case WorkflowConditionsOperatorLessThan:
+		res, err := compareConditionValues(cond.Type, variable, value)
+		return err == nil && res < 0, err
 
-		case WorkflowConditionsOperatorRegex:
-			match, err := regexp.MatchString(cond.Value, mapParams[cond.Variable])
-			if err != nil {
-				return false, fmt.Errorf("Unable to match string with regex %s (%v)", cond.Value, err)
+	case WorkflowConditionsOperatorLessOrEqualThan:
+		res, err := compareConditionValues(cond.Type, variable, value)
+		return err == nil && res <= 0, err
+
+	case WorkflowConditionsOperatorGreaterThan:
+		res, err := compareConditionValues(cond.Type, variable, value)
+		return err == nil && res > 0, err
+
+	case WorkflowConditionsOperatorGreaterOrEqualThan:
+		res, err := compareConditionValues(cond.Type, variable, value)
+		return err == nil && res >= 0, err
+
+	case WorkflowConditionsOperatorRegex:
+		match, err := regexp.MatchString(value, variable)
+		if err != nil {
+			return false, fmt.Errorf("Unable to match string with regex %s (%v)", value, err)
+		}
+		return match, nil
+
+	case WorkflowConditionsOperatorIn:
+		return isInConditionList(cond.Type, variable, value)
+
+	case WorkflowConditionsOperatorNotIn:
+		found, err := isInConditionList(cond.Type, variable, value)
+		return err == nil && !found, err
+
+	case WorkflowConditionsOperatorContains:
+		return strings.Contains(variable, value), nil
+	}
+
+	return false, fmt.Errorf("Unsupported operator %s", cond.Operator)
+}
+
+// isInConditionList checks if given variable matches one of the comma separated values.
+func isInConditionList(typ, variable, list string) (bool, error) {
+	for _, v := range strings.Split(list, ",") {
+		res, err := compareConditionValues(typ, variable, strings.TrimSpace(v))
+		if err != nil {
+			return false, err
+		}
+		if res == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// compareConditionValues returns an integer comparing a and b: 0 if a==b, -1 if a < b, and +1 if a > b.
+// If no type is given, the most specific type that can parse both values is used
+// (number, semver, date then duration), with a fallback on string comparison.
+func compareConditionValues(typ, a, b string) (int, error) {
+	switch typ {
+	case WorkflowConditionsTypeString:
+		return strings.Compare(a, b), nil
+	case WorkflowConditionsTypeNumber:
+		return compareConditionNumbers(a, b)
+	case WorkflowConditionsTypeSemver:
+		return compareConditionSemvers(a, b)
+	case WorkflowConditionsTypeDate:
+		return compareConditionDates(a, b)
+	case WorkflowConditionsTypeDuration:
+		return compareConditionDurations(a, b)
+	case WorkflowConditionsTypeAuto:
+		for _, f := range []func(a, b string) (int, error){
+			compareConditionNumbers,
+			compareConditionSemvers,
+			compareConditionDates,
+			compareConditionDurations,
+		} {
+			if res, err := f(a, b); err == nil {
+				return res, nil
 			}
-			conditionsOK = conditionsOK && match
 		}
+		return strings.Compare(a, b), nil
 	}
+	return 0, fmt.Errorf("Unsupported condition type %s", typ)
+}
 
-	return conditionsOK, nil
+func compareConditionNumbers(a, b string) (int, error) {
+	fa, err := strconv.ParseFloat(a, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse %q as number", a)
+	}
+	fb, err := strconv.ParseFloat(b, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse %q as number", b)
+	}
+	switch {
+	case fa < fb:
+		return -1, nil
+	case fa > fb:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func compareConditionSemvers(a, b string) (int, error) {
+	va, err := semver.ParseTolerant(a)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse %q as semver (%v)", a, err)
+	}
+	vb, err := semver.ParseTolerant(b)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse %q as semver (%v)", b, err)
+	}
+	return va.Compare(vb), nil
+}
+
+func parseConditionDate(s string) (time.Time, error) {
+	for _, layout := range workflowConditionsDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Unable to parse %q as date", s)
+}
+
+func compareConditionDates(a, b string) (int, error) {
+	ta, err := parseConditionDate(a)
+	if err != nil {
+		return 0, err
+	}
+	tb, err := parseConditionDate(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case ta.Before(tb):
+		return -1, nil
+	case ta.After(tb):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func compareConditionDurations(a, b string) (int, error) {
+	da, err := time.ParseDuration(a)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse %q as duration", a)
+	}
+	db, err := time.ParseDuration(b)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse %q as duration", b)
+	}
+	switch {
+	case da < db:
+		return -1, nil
+	case da > db:
+		return 1, nil
+	}
+	return 0, nil
 }
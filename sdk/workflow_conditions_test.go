@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowCheckConditions(t *testing.T) {
+	params := []Parameter{
+		{Name: "cds.run.number", Type: StringParameter, Value: "10"},
+		{Name: "git.tag", Type: StringParameter, Value: "v1.10.0"},
+		{Name: "git.branch", Type: StringParameter, Value: "develop"},
+		{Name: "git.message", Type: StringParameter, Value: "fix: something [deploy]"},
+		{Name: "cds.date", Type: StringParameter, Value: "2019-10-01T10:00:00Z"},
+		{Name: "cds.timeout", Type: StringParameter, Value: "90s"},
+		{Name: "cds.threshold", Type: StringParameter, Value: "9"},
+	}
+
+	tests := []struct {
+		name       string
+		conditions []WorkflowNodeCondition
+		want       bool
+		wantErr    bool
+	}{
+		{name: "number gt", conditions: []WorkflowNodeCondition{{Variable: "cds.run.number", Operator: "gt", Value: "9"}}, want: true},
+		{name: "number le", conditions: []WorkflowNodeCondition{{Variable: "cds.run.number", Operator: "le", Value: "9"}}, want: false},
+		{name: "number with interpolated value", conditions: []WorkflowNodeCondition{{Variable: "cds.run.number", Operator: "gt", Value: "{{.cds.threshold}}"}}, want: true},
+		{name: "forced string type", conditions: []WorkflowNodeCondition{{Variable: "cds.run.number", Operator: "gt", Value: "9", Type: "string"}}, want: false},
+		{name: "semver ge", conditions: []WorkflowNodeCondition{{Variable: "git.tag", Operator: "ge", Value: "v1.9.2"}}, want: true},
+		{name: "semver lt", conditions: []WorkflowNodeCondition{{Variable: "git.tag", Operator: "lt", Value: "v1.10.1", Type: "semver"}}, want: true},
+		{name: "invalid semver", conditions: []WorkflowNodeCondition{{Variable: "git.branch", Operator: "lt", Value: "v1.10.1", Type: "semver"}}, wantErr: true},
+		{name: "date gt", conditions: []WorkflowNodeCondition{{Variable: "cds.date", Operator: "gt", Value: "2019-09-30"}}, want: true},
+		{name: "duration lt", conditions: []WorkflowNodeCondition{{Variable: "cds.timeout", Operator: "lt", Value: "2m"}}, want: true},
+		{name: "in", conditions: []WorkflowNodeCondition{{Variable: "git.branch", Operator: "in", Value: "master, develop"}}, want: true},
+		{name: "notin", conditions: []WorkflowNodeCondition{{Variable: "git.branch", Operator: "notin", Value: "master,release"}}, want: true},
+		{name: "contains", conditions: []WorkflowNodeCondition{{Variable: "git.message", Operator: "contains", Value: "[deploy]"}}, want: true},
+		{
+			name: "or group",
+			conditions: []WorkflowNodeCondition{{Operator: "or", Conditions: []WorkflowNodeCondition{
+				{Variable: "git.branch", Operator: "eq", Value: "master"},
+				{Variable: "git.tag", Operator: "ge", Value: "v1.10.0"},
+			}}},
+			want: true,
+		},
+		{
+			name: "and group",
+			conditions: []WorkflowNodeCondition{{Operator: "and", Conditions: []WorkflowNodeCondition{
+				{Variable: "git.branch", Operator: "eq", Value: "develop"},
+				{Variable: "cds.run.number", Operator: "lt", Value: "2"},
+			}}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WorkflowCheckConditions(tt.conditions, params)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorkflowNodeConditionIsValid(t *testing.T) {
+	assert.NoError(t, WorkflowNodeCondition{Operator: "or", Conditions: []WorkflowNodeCondition{{Variable: "a", Operator: "in", Value: "b"}}}.IsValid())
+	assert.Error(t, WorkflowNodeCondition{Operator: "or"}.IsValid())
+	assert.Error(t, WorkflowNodeCondition{Variable: "a", Operator: "unknown"}.IsValid())
+	assert.Error(t, WorkflowNodeCondition{Variable: "a", Operator: "gt", Type: "float"}.IsValid())
+	assert.Error(t, WorkflowNodeCondition{Variable: "a", Operator: "eq", Conditions: []WorkflowNodeCondition{{Variable: "a", Operator: "eq"}}}.IsValid())
+}
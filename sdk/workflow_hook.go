@@ -12,6 +12,7 @@ const (
 	GitHubIcon    = "Github"
 	BitbucketIcon = "Bitbucket"
 	GerritIcon    = "git"
+	GiteaIcon     = "git"
 )
 
 //NodeHook represents a hook which cann trigger the workflow from a given node
@@ -46,6 +46,7 @@ type NodeContext struct {
 	DefaultPipelineParameters []Parameter            `json:"default_pipeline_parameters" db:"-"`
 	Conditions                WorkflowNodeConditions `json:"conditions" db:"-"`
 	Mutex                     bool                   `json:"mutex" db:"mutex"`
+	Concurrency               *NodeConcurrency       `json:"concurrency,omitempty" db:"-"`
 }
 
 // FilterHooksConfig filter all hooks configuration and remove somme configuration key
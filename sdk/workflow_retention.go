@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"time"
+)
+
+// Reasons for a workflow run to be kept by a retention policy.
+const (
+	RetentionReasonNotTerminated = "not_terminated"
+	RetentionReasonBranch        = "last_runs_of_branch"
+	RetentionReasonAge           = "younger_than_keep_days"
+	RetentionReasonTagged        = "tagged_or_released"
+	RetentionReasonDeployed      = "deployed"
+	RetentionReasonLastSuccess   = "last_success_of_branch"
+)
+
+// WorkflowRetentionPolicy describes which workflow runs should be kept, a run is kept if at least one rule matches,
+// other runs are deleted by the purge. If a workflow has no retention policy, its history length is used.
+type WorkflowRetentionPolicy struct {
+	// KeepRunsPerBranch is the number of latest runs kept for each git branch.
+	KeepRunsPerBranch int64 `json:"keep_runs_per_branch,omitempty" yaml:"keep_runs_per_branch,omitempty"`
+	// KeepDays is the number of days a run is kept after its last modification.
+	KeepDays int64 `json:"keep_days,omitempty" yaml:"keep_days,omitempty"`
+	// KeepTagged keeps runs triggered on a git tag or released.
+	KeepTagged bool `json:"keep_tagged,omitempty" yaml:"keep_tagged,omitempty"`
+	// KeepDeployed keeps successful runs that deployed to an environment.
+	KeepDeployed bool `json:"keep_deployed,omitempty" yaml:"keep_deployed,omitempty"`
+	// KeepLastSuccess keeps the last successful run of each git branch.
+	KeepLastSuccess bool `json:"keep_last_success,omitempty" yaml:"keep_last_success,omitempty"`
+}
+
+// IsValid returns an error if the retention policy is invalid.
+func (p WorkflowRetentionPolicy) IsValid() error {
+	if p.KeepRunsPerBranch < 0 || p.KeepDays < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid retention policy, values should be positive")
+	}
+	if p.KeepRunsPerBranch == 0 && p.KeepDays == 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid retention policy, keep_runs_per_branch or keep_days should be set")
+	}
+	return nil
+}
+
+// Evaluate returns for each given run if it should be kept, runs should be sorted by number desc.
+func (p WorkflowRetentionPolicy) Evaluate(runs []WorkflowRunRetention, now time.Time) []WorkflowRunRetentionResult {
+	res := make([]WorkflowRunRetentionResult, len(runs))
+	countByBranch := make(map[string]int64)
+	successFoundByBranch := make(map[string]bool)
+	for i, r := range runs {
+		res[i] = WorkflowRunRetentionResult{WorkflowRunRetention: r}
+
+		// runs in progress are always kept and are not counted in the runs kept for a branch
+		if r.Status == StatusBuilding || r.Status == StatusChecking || r.Status == StatusWaiting || r.Status == StatusPending {
+			res[i].Reason = RetentionReasonNotTerminated
+			res[i].Keep = true
+			continue
+		}
+
+		countByBranch[r.Branch]++
+		isLastSuccess := r.Status == StatusSuccess && !successFoundByBranch[r.Branch]
+		if r.Status == StatusSuccess {
+			successFoundByBranch[r.Branch] = true
+		}
+
+		switch {
+		case p.KeepRunsPerBranch > 0 && countByBranch[r.Branch] <= p.KeepRunsPerBranch:
+			res[i].Reason = RetentionReasonBranch
+		case p.KeepDays > 0 && r.LastModified.After(now.Add(-time.Duration(p.KeepDays)*24*time.Hour)):
+			res[i].Reason = RetentionReasonAge
+		case p.KeepTagged && (r.Tagged || r.Released):
+			res[i].Reason = RetentionReasonTagged
+		case p.KeepDeployed && r.Deployed && r.Status == StatusSuccess:
+			res[i].Reason = RetentionReasonDeployed
+		case p.KeepLastSuccess && isLastSuccess:
+			res[i].Reason = RetentionReasonLastSuccess
+		}
+		res[i].Keep = res[i].Reason != ""
+	}
+	return res
+}
+
+// WorkflowRunRetention contains the data of a workflow run used to evaluate a retention policy.
+type WorkflowRunRetention struct {
+	ID             int64     `json:"id" db:"id" cli:"-"`
+	Number         int64     `json:"num" db:"num" cli:"num,key"`
+	Status         string    `json:"status" db:"status" cli:"status"`
+	LastModified   time.Time `json:"last_modified" db:"last_modified" cli:"last_modified"`
+	Branch         string    `json:"branch" db:"branch" cli:"branch"`
+	Tagged         bool      `json:"tagged" db:"tagged" cli:"-"`
+	Released       bool      `json:"released" db:"released" cli:"-"`
+	Deployed       bool      `json:"deployed" db:"deployed" cli:"-"`
+	ArtifactsCount int64     `json:"artifacts_count" db:"artifacts_count" cli:"artifacts"`
+	ArtifactsSize  int64     `json:"artifacts_size" db:"artifacts_size" cli:"-"`
+}
+
+// WorkflowRunRetentionResult is the result of a retention policy for a workflow run.
+type WorkflowRunRetentionResult struct {
+	WorkflowRunRetention
+	Keep   bool   `json:"keep" cli:"keep"`
+	Reason string `json:"reason,omitempty" cli:"reason"`
+}
@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRetentionPolicyEvaluate(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-60 * 24 * time.Hour)
+	runs := []WorkflowRunRetention{
+		{ID: 10, Number: 10, Status: StatusBuilding, Branch: "master", LastModified: old},
+		{ID: 9, Number: 9, Status: StatusFail, Branch: "master", LastModified: old},
+		{ID: 8, Number: 8, Status: StatusFail, Branch: "feat", LastModified: now},
+		{ID: 7, Number: 7, Status: StatusSuccess, Branch: "master", LastModified: old},
+		{ID: 6, Number: 6, Status: StatusSuccess, Branch: "master", LastModified: old, Deployed: true},
+		{ID: 5, Number: 5, Status: StatusFail, Branch: "master", LastModified: old, Deployed: true},
+		{ID: 4, Number: 4, Status: StatusSuccess, Branch: "master", LastModified: old, Tagged: true},
+		{ID: 3, Number: 3, Status: StatusSuccess, Branch: "feat", LastModified: old, Released: true},
+		{ID: 2, Number: 2, Status: StatusSuccess, Branch: "feat", LastModified: old},
+		{ID: 1, Number: 1, Status: StatusFail, Branch: "feat", LastModified: old},
+	}
+
+	p := WorkflowRetentionPolicy{KeepRunsPerBranch: 1, KeepDays: 30}
+	assert.NoError(t, p.IsValid())
+	res := p.Evaluate(runs, now)
+	assert.Equal(t, []string{
+		RetentionReasonNotTerminated,
+		RetentionReasonBranch,
+		RetentionReasonBranch,
+		"", "", "", "", "", "", "",
+	}, reasons(res))
+
+	p = WorkflowRetentionPolicy{KeepDays: 30, KeepTagged: true, KeepDeployed: true, KeepLastSuccess: true}
+	res = p.Evaluate(runs, now)
+	assert.Equal(t, []string{
+		RetentionReasonNotTerminated,
+		"",
+		RetentionReasonAge,
+		RetentionReasonLastSuccess,
+		RetentionReasonDeployed,
+		"",
+		RetentionReasonTagged,
+		RetentionReasonTagged,
+		"",
+		"",
+	}, reasons(res))
+	for _, r := range res {
+		assert.Equal(t, r.Reason != "", r.Keep)
+	}
+}
+
+func TestWorkflowRetentionPolicyIsValid(t *testing.T) {
+	assert.Error(t, WorkflowRetentionPolicy{}.IsValid())
+	assert.Error(t, WorkflowRetentionPolicy{KeepRunsPerBranch: -1, KeepDays: 10}.IsValid())
+	assert.Error(t, WorkflowRetentionPolicy{KeepTagged: true}.IsValid())
+	assert.NoError(t, WorkflowRetentionPolicy{KeepDays: 10}.IsValid())
+}
+
+func reasons(res []WorkflowRunRetentionResult) []string {
+	rs := make([]string, len(res))
+	for i := range res {
+		rs[i] = res[i].Reason
+	}
+	return rs
+}
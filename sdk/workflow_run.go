@@ -52,6 +52,7 @@ type WorkflowRun struct {
 	ToDelete         bool                             `json:"to_delete" db:"to_delete" cli:"-"`
 	JoinTriggersRun  map[int64]WorkflowNodeTriggerRun `json:"join_triggers_run,omitempty" db:"-"`
 	Header           WorkflowRunHeaders               `json:"header,omitempty" db:"-"`
+	ConcurrencyLocks []WorkflowNodeRunLock            `json:"concurrency_locks,omitempty" db:"-"`
 }
 
 // WorkflowNodeRunRelease represents the request struct use by release builtin action for workflow
@@ -347,27 +348,30 @@ func (w WorkflowNodeRunArtifact) Equal(c WorkflowNodeRunArtifact) bool {
 
 //WorkflowNodeJobRun represents an job to be run
 // /!\ DONT FORGET TO REGENERATE EASYJSON FILES /!\
+//
 //easyjson:json
 type WorkflowNodeJobRun struct {
-	ProjectID                 int64              `json:"project_id"`
-	ID                        int64              `json:"id"`
-	WorkflowNodeRunID         int64              `json:"workflow_node_run_id,omitempty"`
-	Job                       ExecutedJob        `json:"job"`
-	Parameters                []Parameter        `json:"parameters,omitempty"`
-	Status                    string             `json:"status"`
-	Retry                     int                `json:"retry"`
-	Queued                    time.Time          `json:"queued,omitempty" cli:"queued"`
-	QueuedSeconds             int64              `json:"queued_seconds,omitempty"`
-	Start                     time.Time          `json:"start,omitempty"`
-	Done                      time.Time          `json:"done,omitempty"`
-	Model                     string             `json:"model,omitempty"`
-	ModelType                 string             `json:"model_type,omitempty"`
-	BookedBy                  Service            `json:"bookedby,omitempty"`
-	SpawnInfos                []SpawnInfo        `json:"spawninfos"`
-	ExecGroups                Groups             `json:"exec_groups"`
-	IntegrationPluginBinaries []GRPCPluginBinary `json:"integration_plugin_binaries,omitempty"`
-	Header                    WorkflowRunHeaders `json:"header,omitempty"`
-	ContainsService           bool               `json:"contains_service,omitempty"`
+	ProjectID                 int64                     `json:"project_id"`
+	ID                        int64                     `json:"id"`
+	WorkflowNodeRunID         int64                     `json:"workflow_node_run_id,omitempty"`
+	Job                       ExecutedJob               `json:"job"`
+	Parameters                []Parameter               `json:"parameters,omitempty"`
+	Status                    string                    `json:"status"`
+	Retry                     int                       `json:"retry"`
+	Attempt                   int                       `json:"attempt,omitempty"`
+	Queued                    time.Time                 `json:"queued,omitempty" cli:"queued"`
+	QueuedSeconds             int64                     `json:"queued_seconds,omitempty"`
+	Start                     time.Time                 `json:"start,omitempty"`
+	Done                      time.Time                 `json:"done,omitempty"`
+	Model                     string                    `json:"model,omitempty"`
+	ModelType                 string                    `json:"model_type,omitempty"`
+	BookedBy                  Service                   `json:"bookedby,omitempty"`
+	SpawnInfos                []SpawnInfo               `json:"spawninfos"`
+	ExecGroups                Groups                    `json:"exec_groups"`
+	IntegrationPluginBinaries []GRPCPluginBinary        `json:"integration_plugin_binaries,omitempty"`
+	Header                    WorkflowRunHeaders        `json:"header,omitempty"`
+	ContainsService           bool                      `json:"contains_service,omitempty"`
+	Matrix                    *WorkflowNodeJobRunMatrix `json:"matrix,omitempty"`
 }
 
 // /!\ DONT FORGET TO REGENERATE EASYJSON FILES /!\
@@ -435,6 +439,15 @@ type WorkflowNodeRunManual struct {
 	Username           string      `json:"username" db:"-"`
 	Fullname           string      `json:"fullname" db:"-"`
 	Email              string      `json:"email" db:"-"`
+	RollbackVersion    string      `json:"rollback_version,omitempty" db:"-"`
+}
+
+// WorkflowCheckRunRerun is sent by a VCS service to run again a workflow node on behalf of a VCS user.
+// The user must be linked to a CDS user by an auth consumer of given type.
+type WorkflowCheckRunRerun struct {
+	FromNodeID     int64            `json:"from_node_id"`
+	ConsumerType   AuthConsumerType `json:"consumer_type"`
+	ExternalUserID string           `json:"external_user_id"`
 }
 
 //GetName returns the name the artifact
@@ -15,6 +15,7 @@ type WorkflowTemplateRequest struct {
 	WorkflowName string            `json:"workflow_name"`
 	Parameters   map[string]string `json:"parameters"`
 	Detached     bool              `json:"detached,omitempty"`
+	Version      string            `json:"version,omitempty"`
 }
 
 // Value returns driver.Value from workflow template request.
@@ -35,6 +36,33 @@ func (w *WorkflowTemplateRequest) Scan(src interface{}) error {
 	return WrapError(json.Unmarshal(source, w), "cannot unmarshal WorkflowTemplateRequest")
 }
 
+// WorkflowTemplateInstancePending is a template version applied on an as code workflow, the generated files
+// are proposed in a pull request and the instance is upgraded only once it is merged.
+type WorkflowTemplateInstancePending struct {
+	WorkflowTemplateVersion int64                   `json:"workflow_template_version"`
+	WorkflowTemplateSemVer  string                  `json:"workflow_template_semver,omitempty"`
+	Request                 WorkflowTemplateRequest `json:"request"`
+	PullRequestURL          string                  `json:"pull_request_url,omitempty"`
+}
+
+// Value returns driver.Value from workflow template instance pending.
+func (w WorkflowTemplateInstancePending) Value() (driver.Value, error) {
+	j, err := json.Marshal(w)
+	return j, WrapError(err, "cannot marshal WorkflowTemplateInstancePending")
+}
+
+// Scan workflow template instance pending.
+func (w *WorkflowTemplateInstancePending) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, w), "cannot unmarshal WorkflowTemplateInstancePending")
+}
+
 // WorkflowTemplateResult struct.
 type WorkflowTemplateResult struct {
 	Workflow     string
@@ -57,6 +85,8 @@ type WorkflowTemplate struct {
 	Environments EnvironmentTemplates       `json:"environments" db:"environments"`
 	Version      int64                      `json:"version" db:"version"`
 	ImportURL    string                     `json:"import_url" db:"import_url"`
+	Repository   WorkflowTemplateRepository `json:"repository" db:"repository"`
+	SemVer       string                     `json:"semver,omitempty" db:"semver"`
 	// aggregates
 	Group         *Group                 `json:"group,omitempty" db:"-"`
 	FirstAudit    *AuditWorkflowTemplate `json:"first_audit,omitempty" db:"-"`
@@ -102,6 +132,10 @@ func (w *WorkflowTemplate) IsValid() error {
 		return NewErrorFrom(ErrWrongRequest, "invalid given name")
 	}
 
+	if err := w.Repository.IsValid(); err != nil {
+		return err
+	}
+
 	for _, p := range w.Parameters {
 		if err := p.IsValid(); err != nil {
 			return err
@@ -187,6 +221,11 @@ func (w *WorkflowTemplate) Update(data WorkflowTemplate) {
 	w.ImportURL = data.ImportURL
 }
 
+// IsFromRepository returns true if the template is maintained in a git repository.
+func (w WorkflowTemplate) IsFromRepository() bool {
+	return w.Repository.ApplicationName != ""
+}
+
 // WorkflowTemplatesToIDs returns ids of given workflow templates.
 func WorkflowTemplatesToIDs(wts []*WorkflowTemplate) []int64 {
 	ids := make([]int64, len(wts))
@@ -205,6 +244,54 @@ func WorkflowTemplatesToGroupIDs(wts []*WorkflowTemplate) []int64 {
 	return ids
 }
 
+// WorkflowTemplateRepository is the git repository where a template is maintained, the template files
+// are loaded from the repository of an application in the given directory.
+type WorkflowTemplateRepository struct {
+	ProjectKey      string `json:"project_key,omitempty"`
+	ApplicationName string `json:"application_name,omitempty"`
+	Path            string `json:"path,omitempty"`
+}
+
+// DefaultWorkflowTemplateRepositoryPath is the directory of the template files in a repository.
+const DefaultWorkflowTemplateRepositoryPath = "template"
+
+// Value returns driver.Value from workflow template repository.
+func (w WorkflowTemplateRepository) Value() (driver.Value, error) {
+	j, err := json.Marshal(w)
+	return j, WrapError(err, "cannot marshal WorkflowTemplateRepository")
+}
+
+// Scan workflow template repository.
+func (w *WorkflowTemplateRepository) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, w), "cannot unmarshal WorkflowTemplateRepository")
+}
+
+// IsValid returns workflow template repository validity.
+func (w *WorkflowTemplateRepository) IsValid() error {
+	if w.ProjectKey == "" && w.ApplicationName == "" {
+		w.Path = ""
+		return nil
+	}
+	if w.ProjectKey == "" || w.ApplicationName == "" {
+		return NewErrorFrom(ErrWrongRequest, "project key and application name are required for template repository")
+	}
+	w.Path = strings.Trim(w.Path, "/")
+	if w.Path == "" {
+		w.Path = DefaultWorkflowTemplateRepositoryPath
+	}
+	if strings.Contains(w.Path, "..") || strings.ContainsAny(w.Path, "*?[") {
+		return NewErrorFrom(ErrWrongRequest, "invalid given template repository path")
+	}
+	return nil
+}
+
 // PipelineTemplate struct.
 type PipelineTemplate struct {
 	Value string `json:"value"`
@@ -370,8 +457,11 @@ type WorkflowTemplateInstance struct {
 	ProjectID               int64                   `json:"project_id" db:"project_id"`
 	WorkflowID              *int64                  `json:"workflow_id" db:"workflow_id"`
 	WorkflowTemplateVersion int64                   `json:"workflow_template_version" db:"workflow_template_version"`
+	WorkflowTemplateSemVer  string                  `json:"workflow_template_semver,omitempty" db:"workflow_template_semver"`
 	Request                 WorkflowTemplateRequest `json:"request" db:"request"`
 	WorkflowName            string                  `json:"workflow_name" db:"workflow_name"`
+	// Pending is the version applied on an as code workflow, waiting for its pull request to be merged
+	Pending *WorkflowTemplateInstancePending `json:"pending,omitempty" db:"pending"`
 	// aggregates
 	FirstAudit *AuditWorkflowTemplateInstance `json:"first_audit,omitempty" db:"-"`
 	LastAudit  *AuditWorkflowTemplateInstance `json:"last_audit,omitempty" db:"-"`
@@ -380,6 +470,17 @@ type WorkflowTemplateInstance struct {
 	Workflow   *Workflow                      `json:"workflow,omitempty" db:"-"`
 }
 
+// ApplyPending replaces the version and the request of the instance by its pending ones.
+func (w *WorkflowTemplateInstance) ApplyPending() {
+	if w.Pending == nil {
+		return
+	}
+	w.WorkflowTemplateVersion = w.Pending.WorkflowTemplateVersion
+	w.WorkflowTemplateSemVer = w.Pending.WorkflowTemplateSemVer
+	w.Request = w.Pending.Request
+	w.Pending = nil
+}
+
 // Key returns unique key for instance.
 func (w WorkflowTemplateInstance) Key() string {
 	workflowName := w.WorkflowName
@@ -438,9 +539,10 @@ func (w WorkflowTemplateBulk) IsDone() bool {
 
 // WorkflowTemplateBulkOperation contains one operation of a template bulk task.
 type WorkflowTemplateBulkOperation struct {
-	Status  OperationStatus         `json:"status"`
-	Error   string                  `json:"error,omitempty"`
-	Request WorkflowTemplateRequest `json:"request"`
+	Status         OperationStatus         `json:"status"`
+	Error          string                  `json:"error,omitempty"`
+	Request        WorkflowTemplateRequest `json:"request"`
+	PullRequestURL string                  `json:"pull_request_url,omitempty"`
 }
 
 // WorkflowTemplateBulkOperations struct.
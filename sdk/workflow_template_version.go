@@ -0,0 +1,195 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+)
+
+// WorkflowTemplateVersion is a version of a template published from a tag of its repository.
+type WorkflowTemplateVersion struct {
+	ID                 int64            `json:"id" db:"id"`
+	WorkflowTemplateID int64            `json:"workflow_template_id" db:"workflow_template_id"`
+	Version            string           `json:"version" db:"version" cli:"version,key"`
+	Tag                string           `json:"tag" db:"tag" cli:"tag"`
+	Template           WorkflowTemplate `json:"template" db:"template"`
+	Author             string           `json:"author" db:"author" cli:"author"`
+	Created            time.Time        `json:"created" db:"created" cli:"created"`
+}
+
+// WorkflowTemplatePublish is the body of a request to publish a template version from a tag.
+type WorkflowTemplatePublish struct {
+	Tag string `json:"tag"`
+}
+
+// WorkflowTemplateVersionFromTag returns the semantic version of a git tag like v1.2.3.
+func WorkflowTemplateVersionFromTag(tag string) (semver.Version, error) {
+	v, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return v, NewErrorFrom(ErrWrongRequest, "tag %s is not a semantic version", tag)
+	}
+	return v, nil
+}
+
+// ParseWorkflowTemplateVersionRange returns the range of versions allowed by a constraint. An empty constraint
+// allows all versions, ~1.2 allows patches of 1.2, ^1.2 allows minors and patches of 1 and a partial version
+// like 1.2 allows patches of 1.2. Other constraints use the range syntax of github.com/blang/semver.
+func ParseWorkflowTemplateVersionRange(constraint string) (semver.Range, error) {
+	c := strings.TrimSpace(constraint)
+	switch c {
+	case "", "*", "x", "latest":
+		return func(semver.Version) bool { return true }, nil
+	}
+
+	invalid := NewErrorFrom(ErrWrongRequest, "invalid template version range %s", constraint)
+
+	var operator byte
+	if c[0] == '~' || c[0] == '^' {
+		operator = c[0]
+		c = c[1:]
+	}
+	parts := strings.Split(strings.TrimPrefix(c, "v"), ".")
+	if (operator == 0 && len(parts) == 3) || strings.ContainsAny(c, " <>=!|x*") {
+		if operator != 0 {
+			return nil, invalid
+		}
+		r, err := semver.ParseRange(c)
+		if err != nil {
+			return nil, invalid
+		}
+		return r, nil
+	}
+	if len(parts) > 3 {
+		return nil, invalid
+	}
+
+	// major and minor are needed to compute the upper bound, the patch is only part of the lower bound
+	numbers := make([]uint64, 2)
+	for i := 0; i < len(parts) && i < 2; i++ {
+		n, err := strconv.ParseUint(parts[i], 10, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		numbers[i] = n
+	}
+	min, err := semver.ParseTolerant(c)
+	if err != nil {
+		return nil, invalid
+	}
+
+	// upper bound is the next version of the last fixed component
+	max := semver.Version{Major: numbers[0] + 1}
+	switch {
+	case operator == '^' && numbers[0] == 0 && len(parts) > 1:
+		max = semver.Version{Minor: numbers[1] + 1}
+	case operator == '^' || len(parts) == 1:
+	default:
+		max = semver.Version{Major: numbers[0], Minor: numbers[1] + 1}
+	}
+
+	return func(v semver.Version) bool { return v.GTE(min) && v.LT(max) }, nil
+}
+
+// LatestWorkflowTemplateVersion returns the greatest version that matches the given constraint.
+func LatestWorkflowTemplateVersion(versions []WorkflowTemplateVersion, constraint string) (*WorkflowTemplateVersion, error) {
+	r, err := ParseWorkflowTemplateVersionRange(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *WorkflowTemplateVersion
+	var latestVersion semver.Version
+	for i := range versions {
+		v, err := semver.Parse(versions[i].Version)
+		if err != nil || !r(v) {
+			continue
+		}
+		if latest == nil || v.GT(latestVersion) {
+			latest = &versions[i]
+			latestVersion = v
+		}
+	}
+	if latest == nil {
+		return nil, NewErrorFrom(ErrWorkflowTemplateVersionNotFound, "no version of the template matches %s", constraint)
+	}
+	return latest, nil
+}
+
+// Upgrade status of a template instance.
+const (
+	WorkflowTemplateInstanceUpToDate    = "up-to-date"
+	WorkflowTemplateInstanceUpgradable  = "upgradable"
+	WorkflowTemplateInstanceOutOfRange  = "out-of-range"
+	WorkflowTemplateInstanceNotImported = "not-imported"
+)
+
+// WorkflowTemplateInstanceUpgrade is a line of the upgrade report of a template, it compares the version applied
+// on an instance with the latest version allowed by the range of the instance and the latest published version.
+type WorkflowTemplateInstanceUpgrade struct {
+	InstanceID   int64  `json:"instance_id" cli:"id,key"`
+	ProjectKey   string `json:"project_key" cli:"project"`
+	WorkflowName string `json:"workflow_name" cli:"workflow"`
+	AsCode       bool   `json:"as_code" cli:"as_code"`
+	Range        string `json:"range,omitempty" cli:"range"`
+	Current      string `json:"current" cli:"current"`
+	Target       string `json:"target,omitempty" cli:"target"`
+	Latest       string `json:"latest" cli:"latest"`
+	Status       string `json:"status" cli:"status"`
+	// Pending is the version proposed in a pull request not merged yet on an as code workflow
+	Pending     string `json:"pending,omitempty" cli:"pending"`
+	PullRequest string `json:"pull_request,omitempty" cli:"pull_request"`
+}
+
+// NewWorkflowTemplateInstanceUpgrade returns the upgrade status of an instance with its project and workflow
+// aggregated. Versions of a template without repository are the integer versions of the template.
+func NewWorkflowTemplateInstanceUpgrade(wt WorkflowTemplate, versions []WorkflowTemplateVersion, wti WorkflowTemplateInstance) WorkflowTemplateInstanceUpgrade {
+	u := WorkflowTemplateInstanceUpgrade{
+		InstanceID:   wti.ID,
+		WorkflowName: wti.WorkflowName,
+		Range:        wti.Request.Version,
+	}
+	if wti.Project != nil {
+		u.ProjectKey = wti.Project.Key
+	}
+	if wti.Workflow != nil {
+		u.WorkflowName = wti.Workflow.Name
+		u.AsCode = wti.Workflow.FromRepository != ""
+	}
+
+	if len(versions) == 0 {
+		u.Current = fmt.Sprintf("%d", wti.WorkflowTemplateVersion)
+		u.Latest = fmt.Sprintf("%d", wt.Version)
+		u.Target = u.Latest
+	} else {
+		u.Current = wti.WorkflowTemplateSemVer
+		if latest, err := LatestWorkflowTemplateVersion(versions, ""); err == nil {
+			u.Latest = latest.Version
+		}
+		if target, err := LatestWorkflowTemplateVersion(versions, wti.Request.Version); err == nil {
+			u.Target = target.Version
+		}
+	}
+
+	if wti.Pending != nil {
+		u.Pending = wti.Pending.WorkflowTemplateSemVer
+		if len(versions) == 0 {
+			u.Pending = fmt.Sprintf("%d", wti.Pending.WorkflowTemplateVersion)
+		}
+		u.PullRequest = wti.Pending.PullRequestURL
+	}
+
+	switch {
+	case wti.Workflow == nil:
+		u.Status = WorkflowTemplateInstanceNotImported
+	case u.Target != "" && u.Target != u.Current:
+		u.Status = WorkflowTemplateInstanceUpgradable
+	case u.Latest != u.Current:
+		u.Status = WorkflowTemplateInstanceOutOfRange
+	default:
+		u.Status = WorkflowTemplateInstanceUpToDate
+	}
+	return u
+}
@@ -0,0 +1,124 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkflowTemplateVersionRange(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		excludes   []string
+	}{
+		{constraint: "", matches: []string{"0.0.1", "3.2.1"}},
+		{constraint: "latest", matches: []string{"0.0.1", "3.2.1"}},
+		{constraint: "1.2.3", matches: []string{"1.2.3"}, excludes: []string{"1.2.4"}},
+		{constraint: "1.2", matches: []string{"1.2.0", "1.2.9"}, excludes: []string{"1.1.9", "1.3.0"}},
+		{constraint: "1", matches: []string{"1.0.0", "1.9.9"}, excludes: []string{"2.0.0"}},
+		{constraint: "~1.2.3", matches: []string{"1.2.3", "1.2.9"}, excludes: []string{"1.2.2", "1.3.0"}},
+		{constraint: "^1.2.3", matches: []string{"1.2.3", "1.9.0"}, excludes: []string{"1.2.2", "2.0.0"}},
+		{constraint: "^0.2", matches: []string{"0.2.0", "0.2.5"}, excludes: []string{"0.3.0"}},
+		{constraint: "v1.2", matches: []string{"1.2.1"}, excludes: []string{"1.3.0"}},
+		{constraint: ">=1.0.0 <2.0.0", matches: []string{"1.5.0"}, excludes: []string{"2.0.0"}},
+		{constraint: "1.2.x", matches: []string{"1.2.7"}, excludes: []string{"1.3.0"}},
+	}
+	for _, tt := range tests {
+		r, err := ParseWorkflowTemplateVersionRange(tt.constraint)
+		require.NoError(t, err, tt.constraint)
+		for _, v := range tt.matches {
+			assert.True(t, r(semver.MustParse(v)), "%s should match %s", tt.constraint, v)
+		}
+		for _, v := range tt.excludes {
+			assert.False(t, r(semver.MustParse(v)), "%s should not match %s", tt.constraint, v)
+		}
+	}
+
+	for _, c := range []string{"~", "^a.b", "~1.2 <3.0.0", "1.2.3.4", "foo"} {
+		_, err := ParseWorkflowTemplateVersionRange(c)
+		assert.True(t, ErrorIs(err, ErrWrongRequest), c)
+	}
+}
+
+func TestLatestWorkflowTemplateVersion(t *testing.T) {
+	versions := []WorkflowTemplateVersion{{Version: "1.0.0"}, {Version: "1.10.0"}, {Version: "1.2.0"}, {Version: "2.0.0"}}
+
+	v, err := LatestWorkflowTemplateVersion(versions, "")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", v.Version)
+
+	v, err = LatestWorkflowTemplateVersion(versions, "^1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.10.0", v.Version)
+
+	_, err = LatestWorkflowTemplateVersion(versions, "3")
+	assert.True(t, ErrorIs(err, ErrWorkflowTemplateVersionNotFound))
+}
+
+func TestNewWorkflowTemplateInstanceUpgrade(t *testing.T) {
+	wt := WorkflowTemplate{Version: 3}
+	versions := []WorkflowTemplateVersion{{Version: "1.0.0"}, {Version: "1.1.0"}, {Version: "2.0.0"}}
+	wf := &Workflow{Name: "my-workflow", FromRepository: "ssh://git@host/repo.git"}
+
+	u := NewWorkflowTemplateInstanceUpgrade(wt, versions, WorkflowTemplateInstance{
+		Workflow:               wf,
+		WorkflowTemplateSemVer: "1.0.0",
+		Request:                WorkflowTemplateRequest{Version: "1"},
+	})
+	assert.Equal(t, WorkflowTemplateInstanceUpgrade{
+		WorkflowName: "my-workflow",
+		AsCode:       true,
+		Range:        "1",
+		Current:      "1.0.0",
+		Target:       "1.1.0",
+		Latest:       "2.0.0",
+		Status:       WorkflowTemplateInstanceUpgradable,
+	}, u)
+
+	u = NewWorkflowTemplateInstanceUpgrade(wt, versions, WorkflowTemplateInstance{
+		Workflow:               wf,
+		WorkflowTemplateSemVer: "1.1.0",
+		Request:                WorkflowTemplateRequest{Version: "1"},
+	})
+	assert.Equal(t, WorkflowTemplateInstanceOutOfRange, u.Status)
+
+	// the version proposed in a pull request is pending until it is merged
+	wti := WorkflowTemplateInstance{
+		Workflow:               wf,
+		WorkflowTemplateSemVer: "1.0.0",
+		Request:                WorkflowTemplateRequest{Version: "1"},
+		Pending: &WorkflowTemplateInstancePending{
+			WorkflowTemplateSemVer: "1.1.0",
+			Request:                WorkflowTemplateRequest{Version: "~1.1"},
+			PullRequestURL:         "https://host/repo/pull/1",
+		},
+	}
+	u = NewWorkflowTemplateInstanceUpgrade(wt, versions, wti)
+	assert.Equal(t, "1.0.0", u.Current)
+	assert.Equal(t, "1.1.0", u.Pending)
+	assert.Equal(t, "https://host/repo/pull/1", u.PullRequest)
+	assert.Equal(t, WorkflowTemplateInstanceUpgradable, u.Status)
+
+	wti.ApplyPending()
+	assert.Nil(t, wti.Pending)
+	assert.Equal(t, "~1.1", wti.Request.Version)
+	u = NewWorkflowTemplateInstanceUpgrade(wt, versions, wti)
+	assert.Equal(t, "1.1.0", u.Current)
+	assert.Equal(t, "", u.Pending)
+	assert.Equal(t, WorkflowTemplateInstanceOutOfRange, u.Status)
+
+	u = NewWorkflowTemplateInstanceUpgrade(wt, versions, WorkflowTemplateInstance{
+		Workflow:               wf,
+		WorkflowTemplateSemVer: "2.0.0",
+	})
+	assert.Equal(t, WorkflowTemplateInstanceUpToDate, u.Status)
+
+	// template without repository
+	u = NewWorkflowTemplateInstanceUpgrade(wt, nil, WorkflowTemplateInstance{WorkflowTemplateVersion: 2, WorkflowName: "new"})
+	assert.Equal(t, "2", u.Current)
+	assert.Equal(t, "3", u.Target)
+	assert.Equal(t, WorkflowTemplateInstanceNotImported, u.Status)
+}
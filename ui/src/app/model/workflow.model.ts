@@ -741,4 +741,5 @@ export class UserNotificationTemplate {
     subject: string;
     body: string;
     disable_comment: boolean;
+    summary_comment: boolean;
 }
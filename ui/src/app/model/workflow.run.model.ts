@@ -41,6 +41,7 @@ export class WorkflowRun {
     commits: Array<Commit>;
     infos: Array<SpawnInfo>;
     version: number;
+    concurrency_locks: Array<WorkflowNodeRunLock>;
 
     // Useful for UI
     duration: string;
@@ -62,6 +63,18 @@ export class WorkflowRun {
     }
 }
 
+export class WorkflowNodeRunLock {
+    id: number;
+    key: string;
+    status: string;
+    workflow_name: string;
+    num: number;
+    workflow_node_run_id: number;
+    workflow_node_name: string;
+    created: string;
+    holder: WorkflowNodeRunLock;
+}
+
 export class WorkflowRunTags {
     tag: string;
     value: string;